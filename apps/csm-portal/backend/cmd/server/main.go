@@ -31,14 +31,24 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/alerts"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/assignment"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/cannedresponses"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/casenotify"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/chatalerts"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/entity"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/escalation"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/handler"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/mentions"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/reports"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/scheduling"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/scim"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/updates"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/workflow"
 )
 
 func main() {
@@ -53,6 +63,11 @@ func main() {
 	// configuration alone, so nothing about them needs an upstream call on the
 	// request path.
 	dir := loadDirectory()
+	oncall := loadOnCall(dir)
+
+	// The case lifecycle's role and team-family guards resolve against the
+	// directory, so it is loaded after it.
+	workflow.SetActive(loadCaseWorkflow(dir))
 
 	// All upstream service clients (entity, updates, SCIM, and future notification
 	// channels) authenticate as the same OAuth2 client-credentials app; only the
@@ -71,8 +86,10 @@ func main() {
 	}
 
 	customerEntityClient := entity.NewCustomerEntityClient(customerEntityCfg)
-	caseHandler := handler.NewCaseHandler(customerEntityClient)
-	dashboardHandler := handler.NewDashboardHandler()
+	scheduler := scheduling.NewScheduler(customerEntityClient)
+	caseHandler := handler.NewCaseHandler(customerEntityClient).WithScheduler(scheduler)
+	onCallHandler := handler.NewOnCallHandler(dir, oncall, customerEntityClient)
+	dashboardHandler := handler.NewDashboardHandler().WithOnCall(onCallHandler).WithTrends(reports.NewTrendResolver(customerEntityClient))
 	accountHandler := handler.NewAccountHandler(customerEntityClient)
 	projectHandler := handler.NewProjectHandler(customerEntityClient)
 	productHandler := handler.NewProductHandler(customerEntityClient)
@@ -81,6 +98,8 @@ func main() {
 	itServiceHandler := handler.NewITServiceHandler(customerEntityClient)
 	serviceOfferingHandler := handler.NewServiceOfferingHandler(customerEntityClient)
 	groupHandler := handler.NewGroupHandler(customerEntityClient)
+	calendarHandler := handler.NewCalendarHandler(customerEntityClient)
+	callSchedulingHandler := handler.NewCallSchedulingHandler(scheduler)
 	referenceHandler := handler.NewReferenceHandler(dir)
 	configurationItemHandler := handler.NewConfigurationItemHandler(customerEntityClient)
 	catalogHandler := handler.NewCatalogHandler(customerEntityClient)
@@ -102,6 +121,53 @@ func main() {
 	})
 	notificationHandler := handler.NewNotificationHandler(googleChatClient, os.Getenv("CSM_PORTAL_WEB_BASE_URL"))
 
+	// Email is optional too. Its callers -- the dashboard alert evaluator and
+	// report scheduler -- skip email targets, and report schedules, when it
+	// is not configured.
+	var emailNotifier alerts.EmailNotifier
+	if emailBaseURL := strings.TrimSpace(os.Getenv("NOTIFICATIONS_EMAIL_BASE_URL")); emailBaseURL != "" {
+		emailNotifier = notifications.NewEmailClient(notifications.EmailConfig{
+			BaseURL:      emailBaseURL,
+			TokenURL:     oauth2TokenURL,
+			ClientID:     oauth2ClientID,
+			ClientSecret: oauth2ClientSecret,
+			Scopes:       splitComma(os.Getenv("NOTIFICATIONS_EMAIL_SCOPES")),
+			FromAddress:  os.Getenv("NOTIFICATIONS_EMAIL_FROM_ADDRESS"),
+		})
+	}
+	alertsInterval := parseDashboardAlertsInterval()
+	chatAlertWatcher := loadChatAlertWatcher(customerEntityClient, googleChatClient)
+	reportScheduler := reports.NewScheduler(customerEntityClient, emailNotifier, dashboard.All, reports.Config{
+		PortalBaseURL: os.Getenv("CSM_PORTAL_WEB_BASE_URL"),
+		OnCall:        oncall.EmailAt,
+	})
+	reportHandler := handler.NewReportHandler(reportScheduler)
+	notificationPrefs := loadNotificationPreferences()
+	notificationPrefsHandler := handler.NewNotificationPreferencesHandler(notificationPrefs)
+	cannedResponseHandler := handler.NewCannedResponseHandler(loadCannedResponses(), customerEntityClient, dir, caseHandler)
+	assignmentEngine, assignmentLog := loadAssignmentEngine(customerEntityClient, dir)
+	assignmentHandler := handler.NewAssignmentHandler(assignmentEngine, assignmentLog)
+	assignmentPoller := loadAssignmentPoller(assignmentEngine)
+	// The dispatcher sends both case activity and @mention emails, each per
+	// its recipient's preferences.
+	var caseNotifyDispatcher *casenotify.Dispatcher
+	var mentionNotifier mentions.Notifier
+	if emailNotifier != nil {
+		caseNotifyDispatcher = casenotify.NewDispatcher(notificationPrefs, emailNotifier, os.Getenv("CSM_PORTAL_WEB_BASE_URL"))
+		mentionNotifier = caseNotifyDispatcher
+	} else {
+		slog.Info("email channel is not configured; @mentions in comments are resolved but nobody is emailed")
+	}
+	caseNotifyWatcher := loadCaseNotifyWatcher(customerEntityClient, caseNotifyDispatcher)
+	escalationEngine := loadEscalationEngine(customerEntityClient, emailNotifier, dir, oncall)
+	escalationHandler := handler.NewEscalationHandler(escalationEngine)
+	// Mentions on a case are also posted into its product's Google Chat
+	// space, where one is configured.
+	mentionService := mentions.NewService(customerEntityClient, mentionNotifier, os.Getenv("CSM_PORTAL_WEB_BASE_URL")).WithChat(googleChatClient)
+	caseHandler.WithMentions(mentionService)
+	incidentHandler.WithMentions(mentionService)
+	changeRequestHandler.WithMentions(mentionService)
+
 	updatesCfg := updates.Config{
 		BaseURL:      mustEnv("UPDATES_BASE_URL"),
 		TokenURL:     oauth2TokenURL,
@@ -137,8 +203,12 @@ func main() {
 	mux.HandleFunc("POST /cases", caseHandler.CreateCase)
 	mux.HandleFunc("GET /cases/{id}", caseHandler.GetCase)
 	mux.HandleFunc("PATCH /cases/{id}", caseHandler.PatchCase)
+	mux.HandleFunc("POST /cases/{id}/auto-assign", assignmentHandler.AutoAssignCase)
+	mux.HandleFunc("GET /cases/{id}/escalation", escalationHandler.GetCaseEscalation)
+	mux.HandleFunc("POST /cases/{id}/escalation/snooze", escalationHandler.SnoozeCaseEscalation)
 	mux.HandleFunc("POST /cases/{id}/comments", caseHandler.CreateCaseComment)
 	mux.HandleFunc("POST /cases/{id}/comments/search", caseHandler.SearchCaseComments)
+	mux.HandleFunc("POST /cases/{id}/comments/from-template", cannedResponseHandler.CreateCaseCommentFromTemplate)
 	mux.HandleFunc("POST /cases/{id}/activities/search", caseHandler.SearchCaseActivities)
 	mux.HandleFunc("POST /attachments", caseHandler.CreateCaseAttachment)
 	mux.HandleFunc("POST /attachments/search", caseHandler.SearchCaseAttachments)
@@ -148,6 +218,9 @@ func main() {
 	mux.HandleFunc("POST /cases/{id}/call-requests/search", caseHandler.SearchCallRequests)
 	mux.HandleFunc("POST /call-requests/search", caseHandler.SearchAllCallRequests)
 	mux.HandleFunc("PATCH /cases/{caseId}/call-requests/{callRequestId}", caseHandler.PatchCallRequest)
+	mux.HandleFunc("POST /call-requests/{id}/suggest-slots", callSchedulingHandler.SuggestSlots)
+	mux.HandleFunc("POST /call-requests/{id}/schedule", callSchedulingHandler.ScheduleCallRequest)
+	mux.HandleFunc("GET /call-requests/{id}/invite.ics", callSchedulingHandler.GetCallRequestInvite)
 	mux.HandleFunc("POST /cases/{id}/github-issues", caseHandler.CreateCaseGithubIssue)
 	mux.HandleFunc("POST /cases/{id}/tags", caseHandler.AddCaseTag)
 	mux.HandleFunc("DELETE /cases/{id}/tags/{tagId}", caseHandler.RemoveCaseTag)
@@ -166,19 +239,32 @@ func main() {
 	mux.HandleFunc("GET /dashboards/filter-presets", dashboardHandler.GetFilterPresets)
 	mux.HandleFunc("GET /dashboards/sections", dashboardHandler.GetSharedSections)
 	mux.HandleFunc("GET /dashboards/{dashboardId}", dashboardHandler.GetDashboardDetail)
+	mux.HandleFunc("GET /dashboards/{dashboardId}/widgets/{widgetId}/trend", dashboardHandler.GetWidgetTrend)
+	mux.HandleFunc("POST /dashboards/{dashboardId}/report", reportHandler.SendDashboardReport)
 	mux.HandleFunc("GET /updates/product-update-levels", updatesHandler.GetProductUpdateLevels)
 	mux.HandleFunc("POST /updates/levels/search", updatesHandler.SearchUpdatesBetweenUpdateLevels)
 	mux.HandleFunc("GET /users/me", usersHandler.GetMe)
 	mux.HandleFunc("PATCH /users/me", usersHandler.PatchMe)
 	mux.HandleFunc("POST /users/search", usersHandler.SearchUsers)
+	mux.HandleFunc("GET /users/me/notification-preferences", notificationPrefsHandler.GetMyPreferences)
+	mux.HandleFunc("PUT /users/me/notification-preferences", notificationPrefsHandler.PutMyPreferences)
+	mux.HandleFunc("GET /canned-responses", cannedResponseHandler.ListCannedResponses)
+	mux.HandleFunc("POST /canned-responses", cannedResponseHandler.CreateCannedResponse)
+	mux.HandleFunc("GET /canned-responses/{templateId}", cannedResponseHandler.GetCannedResponse)
+	mux.HandleFunc("PUT /canned-responses/{templateId}", cannedResponseHandler.UpdateCannedResponse)
+	mux.HandleFunc("DELETE /canned-responses/{templateId}", cannedResponseHandler.DeleteCannedResponse)
+	mux.HandleFunc("GET /assignment/decisions", assignmentHandler.ListAssignmentDecisions)
 	mux.HandleFunc("GET /users/{id}", usersHandler.GetUser)
 	mux.HandleFunc("POST /roles/search", referenceHandler.SearchRoles)
 	mux.HandleFunc("POST /teams/search", referenceHandler.SearchTeams)
+	mux.HandleFunc("GET /teams/{key}/on-call", onCallHandler.GetTeamOnCall)
+	mux.HandleFunc("GET /on-call/calendar", onCallHandler.GetOnCallCalendar)
 	mux.HandleFunc("GET /accounts/{id}", accountHandler.GetAccount)
 	mux.HandleFunc("POST /accounts/search", accountHandler.SearchAccounts)
 	mux.HandleFunc("POST /accounts/{id}/contacts/search", accountHandler.SearchAccountContacts)
 	mux.HandleFunc("GET /projects/{id}", projectHandler.GetProject)
 	mux.HandleFunc("POST /projects/search", projectHandler.SearchProjects)
+	mux.HandleFunc("POST /projects/renewals/search", projectHandler.SearchRenewals)
 	mux.HandleFunc("POST /projects/{id}/contacts/search", projectHandler.SearchProjectContacts)
 	mux.HandleFunc("GET /projects/{id}/contacts/{contactId}", projectHandler.GetProjectContact)
 	mux.HandleFunc("PATCH /projects/{id}", projectHandler.UpdateProject)
@@ -200,6 +286,8 @@ func main() {
 	mux.HandleFunc("POST /services/search", itServiceHandler.SearchITServices)
 	mux.HandleFunc("POST /service-offerings/search", serviceOfferingHandler.SearchServiceOfferings)
 	mux.HandleFunc("POST /groups/search", groupHandler.SearchGroups)
+	mux.HandleFunc("GET /calendars", calendarHandler.ListCalendars)
+	mux.HandleFunc("POST /calendars/{id}/add-business-time", calendarHandler.AddBusinessTime)
 	mux.HandleFunc("POST /configuration-items/search", configurationItemHandler.SearchConfigurationItems)
 	mux.HandleFunc("POST /time-cards/search", timeCardHandler.SearchTimeCards)
 	mux.HandleFunc("POST /time-cards", timeCardHandler.CreateTimeCard)
@@ -220,6 +308,7 @@ func main() {
 	mux.HandleFunc("PATCH /tasks/{id}", taskHandler.UpdateTask)
 	mux.HandleFunc("POST /incidents/search", incidentHandler.SearchIncidents)
 	mux.HandleFunc("POST /incidents/aggregate", incidentHandler.AggregateIncidents)
+	mux.HandleFunc("POST /incidents/metrics", incidentHandler.IncidentMetrics)
 	mux.HandleFunc("POST /incidents", incidentHandler.CreateIncident)
 	mux.HandleFunc("GET /incidents/{id}", incidentHandler.GetIncident)
 	mux.HandleFunc("PATCH /incidents/{id}", incidentHandler.PatchIncident)
@@ -235,7 +324,8 @@ func main() {
 	mux.HandleFunc("GET /incident-tasks/{id}", incidentTaskHandler.GetIncidentTask)
 	mux.HandleFunc("POST /incident-tasks/search", incidentTaskHandler.SearchIncidentTasks)
 	mux.HandleFunc("POST /incident-tasks/aggregate", incidentTaskHandler.AggregateIncidentTasks)
-	// Called manually today; not yet wired into real incident/case creation.
+	// Ad-hoc alerts; automatic ones for new and escalated records come from
+	// the chatalerts watcher.
 	mux.HandleFunc("POST /notifications/google-chat/alerts", notificationHandler.PostGoogleChatAlert)
 
 	addr := ":" + mustPort("PORT", "8080")
@@ -264,6 +354,34 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	if alertsInterval > 0 {
+		evaluator := alerts.NewEvaluator(customerEntityClient, googleChatClient, emailNotifier, dashboard.All, alerts.Config{
+			Interval:      alertsInterval,
+			PortalBaseURL: os.Getenv("CSM_PORTAL_WEB_BASE_URL"),
+			OnCall:        oncall.EmailAt,
+		})
+		go evaluator.Run(ctx)
+		slog.Info("dashboard threshold alerts enabled", "interval", alertsInterval.String())
+	}
+	if chatAlertWatcher != nil {
+		go chatAlertWatcher.Run(ctx)
+	}
+	if caseNotifyDispatcher != nil {
+		go caseNotifyDispatcher.Run(ctx)
+	}
+	if caseNotifyWatcher != nil {
+		go caseNotifyWatcher.Run(ctx)
+	}
+	if emailNotifier != nil {
+		go reportScheduler.Run(ctx)
+	}
+	if assignmentPoller != nil {
+		go assignmentPoller.Run(ctx)
+	}
+	if escalationEngine != nil {
+		go escalationEngine.Run(ctx)
+	}
+
 	go func() {
 		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			slog.Error("server exited", "err", err)
@@ -283,6 +401,239 @@ func main() {
 	slog.Info("CSM Portal Backend stopped")
 }
 
+// minDashboardAlertsInterval keeps a typo like "1s" from turning the alert
+// evaluator into a load test against the entity service.
+const minDashboardAlertsInterval = time.Minute
+
+// parseDashboardAlertsInterval reads DASHBOARD_ALERTS_INTERVAL, a
+// time.ParseDuration value. Unset means threshold alerts are off (zero); a
+// value that does not parse, or is under minDashboardAlertsInterval, is fatal
+// for the same reason loadDashboards is.
+func parseDashboardAlertsInterval() time.Duration {
+	raw := strings.TrimSpace(os.Getenv("DASHBOARD_ALERTS_INTERVAL"))
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < minDashboardAlertsInterval {
+		slog.Error("invalid DASHBOARD_ALERTS_INTERVAL; expected a duration of at least "+minDashboardAlertsInterval.String(),
+			"value", raw, "err", err)
+		os.Exit(1)
+	}
+	return d
+}
+
+// loadChatAlertWatcher builds the automatic case and incident Google Chat
+// alert watcher, or returns nil when it is off. It is configured by:
+//
+//	CHAT_ALERTS_INTERVAL      poll interval (time.ParseDuration, at least
+//	                          minDashboardAlertsInterval). Unset means off.
+//	CHAT_ALERTS_DEDUP_WINDOW  how long an alert suppresses a repeat for the
+//	                          same record (default 30m).
+//	CHAT_ALERT_RULES          JSON array of chatalerts.Rule.
+//
+// Like parseDashboardAlertsInterval, anything set but invalid is fatal, as is
+// enabling it with no rules: a watcher that can route nothing is a typo.
+func loadChatAlertWatcher(entityClient *entity.CustomerEntityClient, chat *notifications.GoogleChatClient) *chatalerts.Watcher {
+	raw := strings.TrimSpace(os.Getenv("CHAT_ALERTS_INTERVAL"))
+	if raw == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < minDashboardAlertsInterval {
+		slog.Error("invalid CHAT_ALERTS_INTERVAL; expected a duration of at least "+minDashboardAlertsInterval.String(),
+			"value", raw, "err", err)
+		os.Exit(1)
+	}
+	dedupWindow := 30 * time.Minute
+	if raw := strings.TrimSpace(os.Getenv("CHAT_ALERTS_DEDUP_WINDOW")); raw != "" {
+		if dedupWindow, err = time.ParseDuration(raw); err != nil || dedupWindow <= 0 {
+			slog.Error("invalid CHAT_ALERTS_DEDUP_WINDOW; expected a positive duration", "value", raw, "err", err)
+			os.Exit(1)
+		}
+	}
+	rules, err := chatalerts.ParseRules(os.Getenv("CHAT_ALERT_RULES"), chat.HasSpace)
+	if err != nil {
+		slog.Error("invalid CHAT_ALERT_RULES", "err", err)
+		os.Exit(1)
+	}
+	if len(rules) == 0 {
+		slog.Error("CHAT_ALERTS_INTERVAL is set but CHAT_ALERT_RULES has no rules")
+		os.Exit(1)
+	}
+	slog.Info("chat alerts enabled", "interval", interval.String(), "dedupWindow", dedupWindow.String(), "rules", len(rules))
+	return chatalerts.NewWatcher(entityClient, chat, rules, chatalerts.Config{
+		Interval:      interval,
+		DedupWindow:   dedupWindow,
+		PortalBaseURL: os.Getenv("CSM_PORTAL_WEB_BASE_URL"),
+	})
+}
+
+// loadNotificationPreferences opens the per-user case notification
+// preference store at NOTIFICATION_PREFERENCES_FILE, exiting on a file that
+// cannot be read or holds invalid preferences. Unset keeps preferences in
+// memory, so every user is back on the defaults after a restart -- fine for
+// local development, and warned about for anything else.
+func loadNotificationPreferences() *casenotify.Store {
+	path := strings.TrimSpace(os.Getenv("NOTIFICATION_PREFERENCES_FILE"))
+	if path == "" {
+		slog.Warn("NOTIFICATION_PREFERENCES_FILE is unset; notification preferences are kept in memory and lost on restart")
+	}
+	store, err := casenotify.OpenStore(path)
+	if err != nil {
+		slog.Error("failed to load notification preferences", "err", err)
+		os.Exit(1)
+	}
+	return store
+}
+
+// loadCannedResponses opens the canned response library at
+// CANNED_RESPONSES_FILE, exiting on a file that cannot be read. Unset keeps
+// the library in memory, lost on restart, and is warned about.
+func loadCannedResponses() *cannedresponses.Store {
+	path := strings.TrimSpace(os.Getenv("CANNED_RESPONSES_FILE"))
+	if path == "" {
+		slog.Warn("CANNED_RESPONSES_FILE is unset; canned responses are kept in memory and lost on restart")
+	}
+	store, err := cannedresponses.OpenStore(path)
+	if err != nil {
+		slog.Error("failed to load canned responses", "err", err)
+		os.Exit(1)
+	}
+	return store
+}
+
+// loadAssignmentEngine builds the case assignment engine from the team
+// registry's strategies and:
+//
+//	CSM_ENGINEER_SKILLS     engineer skills for skills-based teams, as
+//	                        "email|Product|Product" rows separated by commas
+//	                        (see assignment.ParseSkills). Optional.
+//	ASSIGNMENT_LOG_FILE     JSON file the decision audit trail is saved to.
+//	                        Optional; unset keeps it in memory, lost on
+//	                        restart, and is warned about.
+//
+// Malformed skills, or a log file that cannot be read, are fatal.
+func loadAssignmentEngine(entityClient *entity.CustomerEntityClient, dir *directory.Directory) (*assignment.Engine, *assignment.Log) {
+	skills, err := assignment.ParseSkills(os.Getenv("CSM_ENGINEER_SKILLS"))
+	if err != nil {
+		slog.Error("invalid CSM_ENGINEER_SKILLS", "err", err)
+		os.Exit(1)
+	}
+	path := strings.TrimSpace(os.Getenv("ASSIGNMENT_LOG_FILE"))
+	if path == "" {
+		slog.Warn("ASSIGNMENT_LOG_FILE is unset; case assignment decisions are kept in memory and lost on restart")
+	}
+	log, err := assignment.OpenLog(path)
+	if err != nil {
+		slog.Error("failed to load the case assignment log", "err", err)
+		os.Exit(1)
+	}
+	return assignment.NewEngine(entityClient, dir, skills, log), log
+}
+
+// loadAssignmentPoller builds the poller that assigns new cases in the queue
+// of every team with an assignment strategy, or returns nil when
+// CASE_AUTO_ASSIGN_INTERVAL is unset. The interval is parsed like
+// DASHBOARD_ALERTS_INTERVAL.
+func loadAssignmentPoller(engine *assignment.Engine) *assignment.Poller {
+	raw := strings.TrimSpace(os.Getenv("CASE_AUTO_ASSIGN_INTERVAL"))
+	if raw == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < minDashboardAlertsInterval {
+		slog.Error("invalid CASE_AUTO_ASSIGN_INTERVAL; expected a duration of at least "+minDashboardAlertsInterval.String(),
+			"value", raw, "err", err)
+		os.Exit(1)
+	}
+	slog.Info("automatic case assignment enabled", "interval", interval.String())
+	return assignment.NewPoller(engine, interval)
+}
+
+// loadCaseNotifyWatcher builds the case activity email watcher, or returns
+// nil when CASE_NOTIFICATIONS_INTERVAL is unset. The interval is parsed like
+// DASHBOARD_ALERTS_INTERVAL. Email is its only channel, so enabling it without
+// the email channel configured -- and so without a dispatcher -- is fatal
+// rather than a watcher that polls and sends nothing.
+func loadCaseNotifyWatcher(entityClient *entity.CustomerEntityClient, dispatcher *casenotify.Dispatcher) *casenotify.Watcher {
+	raw := strings.TrimSpace(os.Getenv("CASE_NOTIFICATIONS_INTERVAL"))
+	if raw == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < minDashboardAlertsInterval {
+		slog.Error("invalid CASE_NOTIFICATIONS_INTERVAL; expected a duration of at least "+minDashboardAlertsInterval.String(),
+			"value", raw, "err", err)
+		os.Exit(1)
+	}
+	if dispatcher == nil {
+		slog.Error("CASE_NOTIFICATIONS_INTERVAL is set but the email channel (NOTIFICATIONS_EMAIL_BASE_URL) is not configured")
+		os.Exit(1)
+	}
+	slog.Info("case activity notifications enabled", "interval", interval.String())
+	return casenotify.NewWatcher(entityClient, dispatcher, interval)
+}
+
+// loadEscalationEngine builds the engine that escalates unacknowledged cases,
+// or returns nil when CASE_ESCALATION_FILE is unset. It is configured by:
+//
+//	CASE_ESCALATION_FILE        JSON escalation policies (see
+//	                            escalation.example.json). Unset means off.
+//	CASE_ESCALATION_INTERVAL    poll interval, parsed like
+//	                            DASHBOARD_ALERTS_INTERVAL (default 1m).
+//	CASE_ESCALATION_STATE_FILE  JSON file each case's escalation and the audit
+//	                            trail are saved to. Optional; unset keeps them
+//	                            in memory, so a restart re-pages, and is warned
+//	                            about.
+//
+// An invalid definition is fatal, for the same reason a bad on-call rotation
+// is: a policy that silently fails to load pages nobody. So is enabling it
+// without the email channel, its only way to page anyone.
+func loadEscalationEngine(entityClient *entity.CustomerEntityClient, email alerts.EmailNotifier, dir *directory.Directory, oncall *directory.OnCall) *escalation.Engine {
+	path := strings.TrimSpace(os.Getenv("CASE_ESCALATION_FILE"))
+	if path == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		slog.Error("failed to read CASE_ESCALATION_FILE", "path", path, "err", err)
+		os.Exit(1)
+	}
+	def, err := escalation.Parse(raw, dir, oncall)
+	if err != nil {
+		slog.Error("invalid CASE_ESCALATION_FILE", "path", path, "err", err)
+		os.Exit(1)
+	}
+	interval := time.Minute
+	if v := strings.TrimSpace(os.Getenv("CASE_ESCALATION_INTERVAL")); v != "" {
+		interval, err = time.ParseDuration(v)
+		if err != nil || interval < minDashboardAlertsInterval {
+			slog.Error("invalid CASE_ESCALATION_INTERVAL; expected a duration of at least "+minDashboardAlertsInterval.String(),
+				"value", v, "err", err)
+			os.Exit(1)
+		}
+	}
+	if email == nil {
+		slog.Error("CASE_ESCALATION_FILE is set but the email channel (NOTIFICATIONS_EMAIL_BASE_URL) is not configured")
+		os.Exit(1)
+	}
+	statePath := strings.TrimSpace(os.Getenv("CASE_ESCALATION_STATE_FILE"))
+	if statePath == "" {
+		slog.Warn("CASE_ESCALATION_STATE_FILE is unset; case escalations are kept in memory, and a restart re-pages")
+	}
+	store, err := escalation.OpenStore(statePath)
+	if err != nil {
+		slog.Error("failed to load the case escalation state", "err", err)
+		os.Exit(1)
+	}
+	slog.Info("case escalation enabled", "path", path, "policies", len(def.Policies), "interval", interval.String())
+	return escalation.NewEngine(entityClient, email, def, dir, oncall, store, escalation.Config{
+		Interval:      interval,
+		PortalBaseURL: os.Getenv("CSM_PORTAL_WEB_BASE_URL"),
+	})
+}
+
 // loadDashboards builds the dashboard registry from configuration, and exits
 // the process on any failure. Every failure mode here is a misconfigured
 // deploy, and the alternative — starting up with dashboards silently missing
@@ -366,14 +717,49 @@ func loadDashboards() *dashboard.Registry {
 	return registry
 }
 
+// loadCaseWorkflow resolves the case lifecycle PATCH /cases/{id} enforces,
+// from the JSON definition at CASE_WORKFLOW_FILE (see
+// case-workflow.example.json). Unset means workflow.Default: the lifecycle the
+// portal has always enforced, with no guards.
+//
+// An unreadable or invalid definition is fatal, for the same reason a bad team
+// registry is: a transition silently dropped or left unguarded does not error
+// anywhere, it just lets the wrong people close cases.
+func loadCaseWorkflow(dir *directory.Directory) *workflow.Workflow {
+	path := strings.TrimSpace(os.Getenv("CASE_WORKFLOW_FILE"))
+	if path == "" {
+		slog.Info("CASE_WORKFLOW_FILE is not set: using the default case workflow")
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		slog.Error("failed to read CASE_WORKFLOW_FILE", "path", path, "err", err)
+		os.Exit(1)
+	}
+	def, err := workflow.Parse(raw)
+	if err != nil {
+		slog.Error("invalid CASE_WORKFLOW_FILE", "path", path, "err", err)
+		os.Exit(1)
+	}
+	wf, err := workflow.Compile(def, dir)
+	if err != nil {
+		slog.Error("invalid CASE_WORKFLOW_FILE", "path", path, "err", err)
+		os.Exit(1)
+	}
+	slog.Info("loaded case workflow", "path", path, "caseTypes", len(def.CaseTypes))
+	return wf
+}
+
 // loadDirectory resolves the reference catalogues from environment
 // configuration, once, at startup:
 //
 //	CSM_TEAM_REGISTRY  the team registry as
-//	                   "teamKey|Display Name|FAMILY|creGroupId|sreGroupId" rows
-//	                   separated by commas, where FAMILY is one of cre-abt,
-//	                   cre, sre-abt or sre (case insensitive) and FAMILY,
-//	                   creGroupId, and sreGroupId are all optional. Unset means
+//	                   "teamKey|Display Name|FAMILY|creGroupId|sreGroupId|assignment"
+//	                   rows separated by commas, where FAMILY is one of
+//	                   cre-abt, cre, sre-abt or sre (case insensitive),
+//	                   assignment is one of round_robin, least_loaded or
+//	                   skills, and every field after the display name is
+//	                   optional. Unset means
 //	                   no teams are configured; there is deliberately no
 //	                   default, because team names are organisation vocabulary
 //	                   that must not be committed here.
@@ -411,6 +797,31 @@ func loadDirectory() *directory.Directory {
 	return dir
 }
 
+// loadOnCall resolves the team on-call rotations from the JSON definition at
+// CSM_ONCALL_FILE (see oncall.example.json), against the team registry they
+// are keyed by. Unset means no rotations: nobody is on call for any team.
+//
+// An unreadable or invalid definition is fatal, for the same reason a bad team
+// registry is: a rotation that silently fails to load pages nobody.
+func loadOnCall(dir *directory.Directory) *directory.OnCall {
+	path := strings.TrimSpace(os.Getenv("CSM_ONCALL_FILE"))
+	if path == "" {
+		slog.Info("CSM_ONCALL_FILE is not set: no team has an on-call rotation")
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		slog.Error("failed to read CSM_ONCALL_FILE", "path", path, "err", err)
+		os.Exit(1)
+	}
+	oncall, err := directory.ParseOnCall(raw, dir)
+	if err != nil {
+		slog.Error("invalid CSM_ONCALL_FILE", "path", path, "err", err)
+		os.Exit(1)
+	}
+	return oncall
+}
+
 func mustEnv(key string) string {
 	v := os.Getenv(key)
 	if v == "" {
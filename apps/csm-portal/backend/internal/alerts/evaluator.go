@@ -0,0 +1,325 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package alerts evaluates dashboard count widgets that declare thresholds
+// (see dashboard.ThresholdConfig) in the background and notifies Google Chat
+// and email when one is crossed or recovers. Widgets are resolved through
+// widgetquery, with the portal backend's own credentials.
+//
+// State is in memory only. A restart forgets which levels were already
+// announced, so a level that is still raised is announced once more after
+// the first evaluation following startup.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/widgetquery"
+)
+
+// searchTimeout bounds one widget's count search, so one slow resource cannot
+// hold up every other widget in the same pass.
+const searchTimeout = 30 * time.Second
+
+// Level is a widget's alert level.
+type Level int
+
+const (
+	LevelOK Level = iota
+	LevelWarn
+	LevelCritical
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "warn"
+	case LevelCritical:
+		return "critical"
+	}
+	return "ok"
+}
+
+// ChatNotifier posts a dashboard alert card to a product's Google Chat space.
+type ChatNotifier interface {
+	SendDashboardAlert(ctx context.Context, product, title, details, portalURL string) error
+}
+
+// EmailNotifier sends an HTML email.
+type EmailNotifier interface {
+	SendEmail(ctx context.Context, to, cc, bcc, replyTo []string, subject, htmlBody string, attachments []notifications.EmailAttachment) error
+}
+
+// Config holds the evaluator's settings.
+type Config struct {
+	// Interval is how often every threshold widget is re-evaluated.
+	Interval time.Duration
+	// PortalBaseURL is the CSM portal webapp base URL alert links point into.
+	PortalBaseURL string
+	// OnCall returns the email of whoever is on call for a team at a time,
+	// for "__current_oncall__:<teamKey>" recipients. Nil resolves none.
+	OnCall func(teamKey string, at time.Time) (string, bool)
+}
+
+// channel names one delivery target, for per-channel delivery bookkeeping.
+type channel string
+
+const (
+	channelChat  channel = "chat"
+	channelEmail channel = "email"
+)
+
+// widgetState is what the evaluator remembers about one widget between
+// passes.
+type widgetState struct {
+	level Level
+	value float64 // the count level was last computed from
+	// delivered is the last level each channel was successfully told about.
+	// A failed delivery leaves it unchanged, so the next pass retries that
+	// channel alone without repeating the message on the ones that worked.
+	delivered map[channel]Level
+}
+
+// Evaluator periodically resolves every threshold-bearing count widget and
+// announces level changes.
+type Evaluator struct {
+	entity     widgetquery.Searcher
+	chat       ChatNotifier
+	email      EmailNotifier
+	dashboards func() []dashboard.Dashboard
+	cfg        Config
+	now        func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*widgetState
+}
+
+// NewEvaluator creates an Evaluator. dashboards is re-read on every pass so a
+// hot-reloaded definition is picked up. chat and email may each be nil when
+// that channel is not configured; a widget targeting only a missing channel
+// is then evaluated and logged but never delivered.
+func NewEvaluator(entity widgetquery.Searcher, chat ChatNotifier, email EmailNotifier, dashboards func() []dashboard.Dashboard, cfg Config) *Evaluator {
+	return &Evaluator{
+		entity:     entity,
+		chat:       chat,
+		email:      email,
+		dashboards: dashboards,
+		cfg:        cfg,
+		now:        time.Now,
+		states:     make(map[string]*widgetState),
+	}
+}
+
+// Run evaluates once immediately and then every cfg.Interval until ctx is
+// cancelled.
+func (e *Evaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		e.EvaluateOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// EvaluateOnce runs a single pass over every threshold widget.
+func (e *Evaluator) EvaluateOnce(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, d := range e.dashboards() {
+		for _, w := range d.Widgets {
+			if w.Thresholds == nil || w.Shape != dashboard.ShapeCount {
+				continue
+			}
+			key := d.ID + "/" + w.ID
+			seen[key] = true
+			e.evaluateWidget(ctx, key, d, w)
+		}
+	}
+	// A widget removed from (or whose thresholds were removed from) the
+	// definitions should not keep its state around forever, nor announce a
+	// stale level if it comes back.
+	for key := range e.states {
+		if !seen[key] {
+			delete(e.states, key)
+		}
+	}
+}
+
+func (e *Evaluator) evaluateWidget(ctx context.Context, key string, d dashboard.Dashboard, w dashboard.WidgetTemplate) {
+	st, ok := e.states[key]
+	if !ok {
+		st = &widgetState{delivered: make(map[channel]Level)}
+		e.states[key] = st
+	}
+
+	searchCtx, cancel := context.WithTimeout(ctx, searchTimeout)
+	value, err := e.count(searchCtx, w)
+	cancel()
+	if err != nil {
+		// Deliberately no level change on a failed search: an upstream outage
+		// should neither raise nor clear an alert. A level change whose
+		// delivery failed on an earlier pass is still retried below.
+		slog.ErrorContext(ctx, "dashboard alert: count search failed",
+			"dashboardId", d.ID, "widgetId", w.ID, "resourceType", w.ResourceType, "err", err)
+	} else {
+		st.level = levelFor(w.Thresholds, value, st.level)
+		st.value = value
+	}
+
+	now := e.now()
+	for _, m := range w.Thresholds.Mute {
+		if m.Contains(now) {
+			return
+		}
+	}
+
+	for _, ch := range e.channelsFor(w.Thresholds.Notify) {
+		if st.delivered[ch] == st.level {
+			continue
+		}
+		if err := e.deliver(ctx, ch, d, w, st.delivered[ch], st.level, st.value); err != nil {
+			slog.ErrorContext(ctx, "dashboard alert: delivery failed",
+				"dashboardId", d.ID, "widgetId", w.ID, "channel", string(ch), "level", st.level.String(), "err", err)
+			continue
+		}
+		slog.InfoContext(ctx, "dashboard alert delivered",
+			"dashboardId", d.ID, "widgetId", w.ID, "channel", string(ch),
+			"from", st.delivered[ch].String(), "to", st.level.String(), "value", st.value)
+		st.delivered[ch] = st.level
+	}
+}
+
+// levelFor computes a widget's new level from its value. A level that is
+// already raised keeps its threshold relaxed by Hysteresis, so a value
+// hovering right at the threshold does not flap between levels every pass.
+func levelFor(t *dashboard.ThresholdConfig, value float64, prev Level) Level {
+	margin := func(l Level) float64 {
+		if prev >= l {
+			return t.Hysteresis
+		}
+		return 0
+	}
+	if t.Critical != nil && t.Op.Breached(value, *t.Critical, margin(LevelCritical)) {
+		return LevelCritical
+	}
+	if t.Warn != nil && t.Op.Breached(value, *t.Warn, margin(LevelWarn)) {
+		return LevelWarn
+	}
+	return LevelOK
+}
+
+// channelsFor lists the configured channels a widget targets.
+func (e *Evaluator) channelsFor(n dashboard.AlertTargets) []channel {
+	var out []channel
+	if n.GoogleChatProduct != "" && e.chat != nil {
+		out = append(out, channelChat)
+	}
+	if len(n.Emails) > 0 && e.email != nil {
+		out = append(out, channelEmail)
+	}
+	return out
+}
+
+// count resolves a count widget the same way the frontend does.
+func (e *Evaluator) count(ctx context.Context, w dashboard.WidgetTemplate) (float64, error) {
+	r, err := widgetquery.For(e.entity, w.ResourceType)
+	if err != nil {
+		return 0, err
+	}
+	return widgetquery.Count(ctx, r, w.Query)
+}
+
+// deliver announces one level change on one channel.
+func (e *Evaluator) deliver(ctx context.Context, ch channel, d dashboard.Dashboard, w dashboard.WidgetTemplate, from, to Level, value float64) error {
+	title, details := alertText(w, from, to, value)
+	link := e.cfg.PortalBaseURL + "/dashboard/" + url.PathEscape(d.ID)
+	switch ch {
+	case channelChat:
+		return e.chat.SendDashboardAlert(ctx, w.Thresholds.Notify.GoogleChatProduct, title, details, link)
+	case channelEmail:
+		html := "<p>" + html.EscapeString(details) + "</p><p><a href=\"" + html.EscapeString(link) + "\">Open " +
+			html.EscapeString(d.DisplayName) + " in the CSM Portal</a></p>"
+		recipients, err := resolveRecipients(ctx, w.Thresholds.Notify.Emails, e.cfg.OnCall, e.now())
+		if err != nil {
+			return err
+		}
+		return e.email.SendEmail(ctx, recipients, nil, nil, nil, title, html, nil)
+	}
+	return nil
+}
+
+// resolveRecipients expands on-call recipients as of now. A team nobody is on
+// call for is logged and skipped; an error only when that leaves nobody, so
+// the next pass retries the channel.
+func resolveRecipients(ctx context.Context, addrs []string, onCall func(string, time.Time) (string, bool), now time.Time) ([]string, error) {
+	var lookup func(string) (string, bool)
+	if onCall != nil {
+		lookup = func(teamKey string) (string, bool) { return onCall(teamKey, now) }
+	}
+	recipients, unresolved := dashboard.ExpandRecipients(addrs, lookup)
+	if len(unresolved) > 0 {
+		slog.WarnContext(ctx, "dashboard alert: nobody is on call for a recipient team", "teams", unresolved)
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients: nobody is on call for %v", unresolved)
+	}
+	return recipients, nil
+}
+
+// alertText builds the title and one-line details for a level change.
+func alertText(w dashboard.WidgetTemplate, from, to Level, value float64) (title, details string) {
+	t := w.Thresholds
+	v := strconv.FormatFloat(value, 'f', -1, 64)
+	describe := func(l Level) string {
+		th := t.Warn
+		if l == LevelCritical {
+			th = t.Critical
+		}
+		if th == nil {
+			// A reload removed the level while it was raised.
+			return fmt.Sprintf("%s threshold", l)
+		}
+		return fmt.Sprintf("%s threshold %s %s", l, t.Op, strconv.FormatFloat(*th, 'f', -1, 64))
+	}
+
+	switch {
+	case to == LevelOK:
+		return fmt.Sprintf("[RECOVERED] %s", w.DisplayName),
+			fmt.Sprintf("%s is back to %s, clear of its %s.", w.DisplayName, v, describe(from))
+	case to > from:
+		return fmt.Sprintf("[%s] %s", strings.ToUpper(to.String()), w.DisplayName),
+			fmt.Sprintf("%s is %s, crossing its %s.", w.DisplayName, v, describe(to))
+	default:
+		return fmt.Sprintf("[%s] %s", strings.ToUpper(to.String()), w.DisplayName),
+			fmt.Sprintf("%s is down to %s, clear of critical but still past its %s.", w.DisplayName, v, describe(to))
+	}
+}
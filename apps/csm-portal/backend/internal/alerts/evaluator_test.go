@@ -0,0 +1,244 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// fakeEntity answers every search with the same total, counting calls.
+type fakeEntity struct {
+	total    int
+	err      error
+	lastBody []byte
+}
+
+func (f *fakeEntity) search(_ context.Context, body []byte) ([]byte, error) {
+	f.lastBody = body
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []byte(`{"total":` + strconv.Itoa(f.total) + `}`), nil
+}
+
+func (f *fakeEntity) SearchCases(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchIncidents(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchChangeRequests(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchProblems(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchIncidentTasks(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchAccounts(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchProjects(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchUsers(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchTimeCards(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchProductVulnerabilities(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+func (f *fakeEntity) SearchAllCallRequests(ctx context.Context, b []byte) ([]byte, error) {
+	return f.search(ctx, b)
+}
+
+type sentChat struct{ product, title string }
+
+type fakeChat struct {
+	sent []sentChat
+	err  error
+}
+
+func (f *fakeChat) SendDashboardAlert(_ context.Context, product, title, _, _ string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, sentChat{product, title})
+	return nil
+}
+
+type fakeEmail struct{ subjects []string }
+
+func (f *fakeEmail) SendEmail(_ context.Context, _, _, _, _ []string, subject, _ string, _ []notifications.EmailAttachment) error {
+	f.subjects = append(f.subjects, subject)
+	return nil
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func thresholdDashboard(t *dashboard.ThresholdConfig) []dashboard.Dashboard {
+	return []dashboard.Dashboard{{
+		ID: "cs-overview", DisplayName: "CS Overview",
+		Widgets: []dashboard.WidgetTemplate{{
+			ID: "s0-unacked", DisplayName: "Unacknowledged S0 cases", ResourceType: dashboard.ResourceCase,
+			Shape: dashboard.ShapeCount, GridWidth: 3,
+			Query:      map[string]any{"filters": []any{map[string]any{"field": "severity", "op": "in", "values": []any{"catastrophic"}}}},
+			Thresholds: t,
+		}},
+	}}
+}
+
+func TestEvaluator_RaisesEscalatesAndRecoversWithHysteresis(t *testing.T) {
+	entity := &fakeEntity{}
+	chat := &fakeChat{}
+	email := &fakeEmail{}
+	cfg := &dashboard.ThresholdConfig{
+		Op: dashboard.OpGreaterThan, Warn: ptr(0), Critical: ptr(5), Hysteresis: 1,
+		Notify: dashboard.AlertTargets{GoogleChatProduct: "support-ops", Emails: []string{"leads@example.com"}},
+	}
+	e := NewEvaluator(entity, chat, email, func() []dashboard.Dashboard { return thresholdDashboard(cfg) },
+		Config{PortalBaseURL: "https://portal.example.com"})
+
+	steps := []struct {
+		total int
+		want  string // chat title delivered this pass, "" for none
+	}{
+		{0, ""},
+		{1, "[WARN] Unacknowledged S0 cases"},
+		{1, ""}, // unchanged level is not re-announced
+		{6, "[CRITICAL] Unacknowledged S0 cases"},
+		{5, ""}, // within hysteresis of critical (> 5-1)
+		{4, "[WARN] Unacknowledged S0 cases"},
+		{1, ""}, // within hysteresis of warn (> 0-1)
+		{0, ""}, // still within hysteresis of warn
+		{-1, "[RECOVERED] Unacknowledged S0 cases"},
+	}
+	for i, step := range steps {
+		entity.total = step.total
+		before := len(chat.sent)
+		e.EvaluateOnce(context.Background())
+		var got string
+		if len(chat.sent) > before {
+			got = chat.sent[len(chat.sent)-1].title
+		}
+		if got != step.want {
+			t.Errorf("step %d (total %d): delivered %q, want %q", i, step.total, got, step.want)
+		}
+	}
+	if len(email.subjects) != len(chat.sent) {
+		t.Errorf("email got %d messages, chat %d; want the same level changes on both", len(email.subjects), len(chat.sent))
+	}
+
+	var sent struct {
+		Filters    map[string]any `json:"filters"`
+		Pagination struct{ Limit int }
+	}
+	if err := json.Unmarshal(entity.lastBody, &sent); err != nil || sent.Pagination.Limit != 1 || sent.Filters["filters"] == nil {
+		t.Errorf("search body = %s, want the widget query as filters with limit 1", entity.lastBody)
+	}
+}
+
+// TestEvaluator_RecoversFromALevelRemovedWhileRaised verifies a reload that
+// drops a raised level's threshold recovers the widget instead of quoting a
+// threshold that is no longer there.
+func TestEvaluator_RecoversFromALevelRemovedWhileRaised(t *testing.T) {
+	entity := &fakeEntity{total: 6}
+	chat := &fakeChat{}
+	cfg := &dashboard.ThresholdConfig{
+		Op: dashboard.OpGreaterThan, Warn: ptr(2), Critical: ptr(5),
+		Notify: dashboard.AlertTargets{GoogleChatProduct: "support-ops"},
+	}
+	e := NewEvaluator(entity, chat, nil, func() []dashboard.Dashboard { return thresholdDashboard(cfg) }, Config{})
+	e.EvaluateOnce(context.Background())
+
+	cfg = &dashboard.ThresholdConfig{
+		Op: dashboard.OpGreaterThan, Warn: ptr(2),
+		Notify: dashboard.AlertTargets{GoogleChatProduct: "support-ops"},
+	}
+	entity.total = 1
+	e.EvaluateOnce(context.Background())
+
+	want := []sentChat{
+		{"support-ops", "[CRITICAL] Unacknowledged S0 cases"},
+		{"support-ops", "[RECOVERED] Unacknowledged S0 cases"},
+	}
+	if !slices.Equal(chat.sent, want) {
+		t.Errorf("delivered %v, want %v", chat.sent, want)
+	}
+	_, details := alertText(thresholdDashboard(cfg)[0].Widgets[0], LevelCritical, LevelOK, 1)
+	if want := "Unacknowledged S0 cases is back to 1, clear of its critical threshold."; details != want {
+		t.Errorf("details = %q, want %q", details, want)
+	}
+}
+
+func TestEvaluator_MuteWindowDefersButDoesNotDrop(t *testing.T) {
+	entity := &fakeEntity{total: 3}
+	chat := &fakeChat{}
+	cfg := &dashboard.ThresholdConfig{
+		Op: dashboard.OpGreaterThan, Critical: ptr(0),
+		Notify: dashboard.AlertTargets{GoogleChatProduct: "support-ops"},
+		Mute:   []dashboard.MuteWindow{{From: "22:00", To: "06:00"}},
+	}
+	e := NewEvaluator(entity, chat, nil, func() []dashboard.Dashboard { return thresholdDashboard(cfg) }, Config{})
+
+	e.now = func() time.Time { return time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC) }
+	e.EvaluateOnce(context.Background())
+	if len(chat.sent) != 0 {
+		t.Fatalf("delivered %v inside the mute window", chat.sent)
+	}
+
+	e.now = func() time.Time { return time.Date(2026, 3, 5, 7, 0, 0, 0, time.UTC) }
+	e.EvaluateOnce(context.Background())
+	if len(chat.sent) != 1 || !strings.HasPrefix(chat.sent[0].title, "[CRITICAL]") {
+		t.Errorf("after the window closed delivered %v, want the still-raised critical", chat.sent)
+	}
+}
+
+func TestEvaluator_FailuresNeitherChangeLevelNorLoseDelivery(t *testing.T) {
+	entity := &fakeEntity{total: 2}
+	chat := &fakeChat{err: errors.New("webhook down")}
+	cfg := &dashboard.ThresholdConfig{
+		Op: dashboard.OpGreaterThanOrEqual, Warn: ptr(1),
+		Notify: dashboard.AlertTargets{GoogleChatProduct: "support-ops"},
+	}
+	e := NewEvaluator(entity, chat, nil, func() []dashboard.Dashboard { return thresholdDashboard(cfg) }, Config{})
+
+	e.EvaluateOnce(context.Background())
+	if len(chat.sent) != 0 {
+		t.Fatal("a failed delivery was recorded as sent")
+	}
+
+	chat.err = nil
+	entity.err = errors.New("entity unavailable")
+	e.EvaluateOnce(context.Background())
+	if len(chat.sent) != 1 || chat.sent[0].title != "[WARN] Unacknowledged S0 cases" {
+		t.Errorf("delivered %v, want the warn retried despite the failed search", chat.sent)
+	}
+}
@@ -0,0 +1,474 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package assignment picks the engineer a case is assigned to, by the
+// strategy its team configures in the registry (directory.Team.Assignment):
+// round robin, least loaded, or skills based. Candidates are the members of
+// the team's group and their load is their ongoing cases, both read live from
+// the entity service; skills are deployment configuration (see ParseSkills).
+//
+// Every decision -- whether it assigned someone, found nobody or failed -- is
+// kept in a Log with the candidates considered and the reason for the pick,
+// so a lead can see why a case went where it did. The Poller assigns new
+// cases in configured teams' queues as they arrive.
+package assignment
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/filestore"
+)
+
+// callTimeout bounds each entity call an assignment makes.
+const callTimeout = 30 * time.Second
+
+const (
+	// userPageLimit is the user search's own maximum page size.
+	userPageLimit = 50
+	// casePageLimit is the case search's own maximum page size.
+	casePageLimit = 100
+	// maxPages caps how many pages of members or of their cases are read, so
+	// a misconfigured team mapped to a huge group cannot stall an assignment.
+	maxPages = 10
+)
+
+// severityWeights is how much one ongoing case of each severity adds to an
+// engineer's load. An unknown severity counts as low.
+var severityWeights = map[string]int{
+	"catastrophic": 5,
+	"critical":     4,
+	"high":         3,
+	"medium":       2,
+	"low":          1,
+}
+
+func severityWeight(severity string) int {
+	if w, ok := severityWeights[strings.ToLower(severity)]; ok {
+		return w
+	}
+	return 1
+}
+
+var (
+	// ErrNoTeam is returned for a case in no configured team's queue when no
+	// team was given.
+	ErrNoTeam = errors.New("assignment: the case is not in a registry team's queue")
+	// ErrUnknownTeam is returned for a team key not in the registry.
+	ErrUnknownTeam = errors.New("assignment: unknown teamKey")
+	// ErrNotConfigured is returned for a team with no assignment strategy.
+	ErrNotConfigured = errors.New("assignment: the team has no assignment strategy")
+	// ErrAlreadyAssigned is returned for a case that already has an engineer.
+	ErrAlreadyAssigned = errors.New("assignment: the case is already assigned")
+	// ErrNoCandidates is returned when the team has nobody to assign to.
+	ErrNoCandidates = errors.New("assignment: the team has no engineers to assign to")
+)
+
+// entityClient abstracts the entity service calls an assignment makes.
+type entityClient interface {
+	GetCase(ctx context.Context, id string) ([]byte, error)
+	PatchCase(ctx context.Context, id string, body []byte) ([]byte, error)
+	SearchCases(ctx context.Context, body []byte) ([]byte, error)
+	SearchUsers(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// Trigger is what asked for an assignment.
+type Trigger string
+
+const (
+	// TriggerManual is POST /cases/{id}/auto-assign.
+	TriggerManual Trigger = "manual"
+	// TriggerPoller is the Poller, for a new case in a team's queue.
+	TriggerPoller Trigger = "poller"
+)
+
+// Request is one assignment to make.
+type Request struct {
+	// TeamKey overrides the team whose engineers are considered; empty uses
+	// the team the case is assigned to.
+	TeamKey string
+	// DryRun decides without assigning or recording anything.
+	DryRun      bool
+	Trigger     Trigger
+	RequestedBy string
+}
+
+// Candidate is an engineer considered for a case.
+type Candidate struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	// Load is the candidate's ongoing cases, each weighted by its severity.
+	Load         int `json:"load"`
+	OngoingCases int `json:"ongoingCases"`
+	// Skilled reports the candidate lists the case's product among their
+	// skills. Only set by the skills strategy.
+	Skilled bool `json:"skilled,omitempty"`
+}
+
+// Decision is the outcome of one assignment, kept for audit.
+type Decision struct {
+	ID         string                       `json:"id"`
+	CaseID     string                       `json:"caseId"`
+	CaseNumber string                       `json:"caseNumber"`
+	TeamKey    string                       `json:"teamKey"`
+	Strategy   directory.AssignmentStrategy `json:"strategy"`
+	Trigger    Trigger                      `json:"trigger"`
+	// RequestedBy is the email of whoever asked, for a manual assignment.
+	RequestedBy string `json:"requestedBy,omitempty"`
+	// Assignee is who the case went to; nil when nobody was picked or the
+	// assignment failed.
+	Assignee   *Candidate  `json:"assignee"`
+	Reason     string      `json:"reason"`
+	Candidates []Candidate `json:"candidates"`
+	// Error is why a picked assignee could not be assigned.
+	Error     string    `json:"error,omitempty"`
+	DryRun    bool      `json:"dryRun,omitempty"`
+	DecidedOn time.Time `json:"decidedOn"`
+}
+
+// Engine makes assignments.
+type Engine struct {
+	entity entityClient
+	dir    *directory.Directory
+	skills Skills
+	log    *Log
+	now    func() time.Time
+}
+
+// NewEngine creates an Engine recording its decisions to log.
+func NewEngine(entity entityClient, dir *directory.Directory, skills Skills, log *Log) *Engine {
+	return &Engine{entity: entity, dir: dir, skills: skills, log: log, now: time.Now}
+}
+
+// caseInfo is the part of a case an assignment reads.
+type caseInfo struct {
+	ID           string  `json:"id"`
+	Number       string  `json:"number"`
+	Severity     string  `json:"severity"`
+	CreatedOn    string  `json:"createdOn"`
+	AssignedTeam *ref    `json:"assignedTeam"`
+	Assigned     *person `json:"assignedEngineer"`
+	// Product is set on a case search result.
+	Product *ref `json:"product"`
+	// DeployedProduct carries the product on GET /cases/{id}.
+	DeployedProduct *struct {
+		Product *ref `json:"product"`
+	} `json:"deployedProduct"`
+}
+
+type ref struct {
+	Name string `json:"name"`
+}
+
+type person struct {
+	ID    *string `json:"id"`
+	Email *string `json:"email"`
+	Name  string  `json:"name"`
+}
+
+func (c caseInfo) product() string {
+	if c.DeployedProduct != nil && c.DeployedProduct.Product != nil {
+		return c.DeployedProduct.Product.Name
+	}
+	if c.Product != nil {
+		return c.Product.Name
+	}
+	return ""
+}
+
+// Assign picks an engineer for case caseID and, unless req.DryRun, assigns
+// the case to them and records the decision. A decision that picked nobody,
+// or whose assignment failed, is recorded too and returned alongside the
+// error. Errors before a team's candidates are read -- the case, its team, or
+// the team's members could not be read -- return no decision and record none.
+func (e *Engine) Assign(ctx context.Context, caseID string, req Request) (Decision, error) {
+	raw, err := e.call(ctx, func(ctx context.Context) ([]byte, error) { return e.entity.GetCase(ctx, caseID) })
+	if err != nil {
+		return Decision{}, err
+	}
+	var c caseInfo
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Decision{}, fmt.Errorf("decode case: %w", err)
+	}
+	if c.ID == "" {
+		c.ID = caseID
+	}
+	if c.Assigned != nil && (c.Assigned.ID != nil || c.Assigned.Email != nil) {
+		return Decision{}, ErrAlreadyAssigned
+	}
+
+	team, err := e.team(c, req.TeamKey)
+	if err != nil {
+		return Decision{}, err
+	}
+	candidates, err := e.candidates(ctx, team)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	d := Decision{
+		ID:          filestore.NewID(),
+		CaseID:      c.ID,
+		CaseNumber:  c.Number,
+		TeamKey:     team.Key,
+		Strategy:    team.Assignment,
+		Trigger:     req.Trigger,
+		RequestedBy: req.RequestedBy,
+		DryRun:      req.DryRun,
+		DecidedOn:   e.now().UTC(),
+	}
+	pick, reason := e.choose(team, c, candidates)
+	d.Candidates, d.Reason = candidates, reason
+	if pick < 0 {
+		e.record(d)
+		return d, ErrNoCandidates
+	}
+	d.Assignee = &d.Candidates[pick]
+	if req.DryRun {
+		return d, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"assigneeEmail": d.Assignee.Email})
+	if err != nil {
+		return Decision{}, err
+	}
+	if _, err := e.call(ctx, func(ctx context.Context) ([]byte, error) { return e.entity.PatchCase(ctx, c.ID, body) }); err != nil {
+		d.Assignee, d.Error = nil, err.Error()
+		e.record(d)
+		return d, err
+	}
+	e.record(d)
+	return d, nil
+}
+
+// record keeps d, unless it is a dry run. A decision that cannot be persisted
+// has still been acted on, so it is kept in memory and the failure is only
+// reported by the Log.
+func (e *Engine) record(d Decision) {
+	if d.DryRun {
+		return
+	}
+	e.log.Record(d)
+}
+
+func (e *Engine) team(c caseInfo, key string) (directory.Team, error) {
+	var team directory.Team
+	var ok bool
+	if key != "" {
+		if team, ok = e.dir.TeamByKey(key); !ok {
+			return directory.Team{}, fmt.Errorf("%w %q", ErrUnknownTeam, key)
+		}
+	} else {
+		if c.AssignedTeam == nil {
+			return directory.Team{}, ErrNoTeam
+		}
+		if team, ok = e.dir.TeamByGroupName(c.AssignedTeam.Name); !ok {
+			return directory.Team{}, ErrNoTeam
+		}
+	}
+	if team.Assignment == "" {
+		return directory.Team{}, ErrNotConfigured
+	}
+	return team, nil
+}
+
+// candidates returns team's members with their ongoing load, by email.
+func (e *Engine) candidates(ctx context.Context, team directory.Team) ([]Candidate, error) {
+	members := map[string]*Candidate{}
+	var ids []string
+	for page := 0; page < maxPages; page++ {
+		body, err := json.Marshal(map[string]any{
+			"filters":    map[string]any{"groupNames": []string{team.Name}},
+			"pagination": map[string]int{"offset": page * userPageLimit, "limit": userPageLimit},
+		})
+		if err != nil {
+			return nil, err
+		}
+		raw, err := e.call(ctx, func(ctx context.Context) ([]byte, error) { return e.entity.SearchUsers(ctx, body) })
+		if err != nil {
+			return nil, fmt.Errorf("search team members: %w", err)
+		}
+		var resp struct {
+			Users []struct {
+				ID        string `json:"id"`
+				Email     string `json:"email"`
+				Name      string `json:"name"`
+				FirstName string `json:"firstName"`
+				LastName  string `json:"lastName"`
+			} `json:"users"`
+			Total int `json:"total"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, fmt.Errorf("decode team members: %w", err)
+		}
+		for _, u := range resp.Users {
+			key := strings.ToLower(u.Email)
+			if key == "" || members[key] != nil {
+				continue
+			}
+			name := u.Name
+			if name == "" {
+				name = strings.TrimSpace(u.FirstName + " " + u.LastName)
+			}
+			members[key] = &Candidate{Email: u.Email, Name: name}
+			if u.ID != "" {
+				ids = append(ids, u.ID)
+			}
+		}
+		if len(resp.Users) < userPageLimit || (page+1)*userPageLimit >= resp.Total {
+			break
+		}
+	}
+
+	if len(ids) > 0 {
+		if err := e.addLoads(ctx, ids, members); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]Candidate, 0, len(members))
+	for _, m := range members {
+		out = append(out, *m)
+	}
+	slices.SortFunc(out, func(a, b Candidate) int { return cmp.Compare(strings.ToLower(a.Email), strings.ToLower(b.Email)) })
+	return out, nil
+}
+
+// addLoads adds each member's ongoing cases to their load.
+func (e *Engine) addLoads(ctx context.Context, userIDs []string, members map[string]*Candidate) error {
+	for page := 0; page < maxPages; page++ {
+		body, err := json.Marshal(map[string]any{
+			"filters": map[string]any{"filters": []map[string]any{
+				{"field": "assignedUserId", "op": "in", "values": userIDs},
+				{"field": "workState", "op": "in", "values": []string{"ongoing"}},
+			}},
+			"pagination": map[string]int{"offset": page * casePageLimit, "limit": casePageLimit},
+		})
+		if err != nil {
+			return err
+		}
+		raw, err := e.call(ctx, func(ctx context.Context) ([]byte, error) { return e.entity.SearchCases(ctx, body) })
+		if err != nil {
+			return fmt.Errorf("search ongoing cases: %w", err)
+		}
+		var resp struct {
+			Cases []caseInfo `json:"cases"`
+			Total int        `json:"total"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return fmt.Errorf("decode ongoing cases: %w", err)
+		}
+		for _, c := range resp.Cases {
+			if c.Assigned == nil || c.Assigned.Email == nil {
+				continue
+			}
+			if m := members[strings.ToLower(*c.Assigned.Email)]; m != nil {
+				m.Load += severityWeight(c.Severity)
+				m.OngoingCases++
+			}
+		}
+		if len(resp.Cases) < casePageLimit || (page+1)*casePageLimit >= resp.Total {
+			return nil
+		}
+	}
+	return nil
+}
+
+// choose returns the index in candidates of the engineer team's strategy
+// picks for c, or -1 for nobody, and why.
+func (e *Engine) choose(team directory.Team, c caseInfo, candidates []Candidate) (int, string) {
+	if len(candidates) == 0 {
+		return -1, fmt.Sprintf("Team %s has no members.", team.Key)
+	}
+	last := e.log.LastAssigned(team.Key)
+
+	switch team.Assignment {
+	case directory.AssignRoundRobin:
+		var prev string
+		var prevAt time.Time
+		for email, at := range last {
+			if at.After(prevAt) {
+				prev, prevAt = email, at
+			}
+		}
+		next := 0
+		if i := slices.IndexFunc(candidates, func(c Candidate) bool { return strings.EqualFold(c.Email, prev) }); i >= 0 {
+			next = (i + 1) % len(candidates)
+		}
+		if prev == "" {
+			return next, fmt.Sprintf("Round robin: first assignment for team %s.", team.Key)
+		}
+		return next, fmt.Sprintf("Round robin: next after %s.", prev)
+
+	case directory.AssignSkills:
+		product := c.product()
+		pool := make([]int, 0, len(candidates))
+		for i := range candidates {
+			if product != "" && e.skills.Has(candidates[i].Email, product) {
+				candidates[i].Skilled = true
+				pool = append(pool, i)
+			}
+		}
+		if len(pool) == 0 {
+			i := leastLoaded(candidates, allIndexes(len(candidates)), last)
+			if product == "" {
+				return i, fmt.Sprintf("Skills: the case has no product; least loaded in the team (load %d).", candidates[i].Load)
+			}
+			return i, fmt.Sprintf("Skills: nobody in the team lists %s; least loaded in the team (load %d).", product, candidates[i].Load)
+		}
+		i := leastLoaded(candidates, pool, last)
+		return i, fmt.Sprintf("Skills: least loaded of %d engineers skilled in %s (load %d).", len(pool), product, candidates[i].Load)
+
+	default:
+		i := leastLoaded(candidates, allIndexes(len(candidates)), last)
+		return i, fmt.Sprintf("Least loaded: load %d from %d ongoing cases.", candidates[i].Load, candidates[i].OngoingCases)
+	}
+}
+
+// leastLoaded returns the index, of those in pool, with the lowest load. A
+// tie goes to whoever was auto-assigned least recently -- a case assigned a
+// moment ago is not ongoing yet, so load alone would keep picking the same
+// engineer -- and then by email.
+func leastLoaded(candidates []Candidate, pool []int, last map[string]time.Time) int {
+	return slices.MinFunc(pool, func(a, b int) int {
+		ca, cb := candidates[a], candidates[b]
+		return cmp.Or(
+			cmp.Compare(ca.Load, cb.Load),
+			last[strings.ToLower(ca.Email)].Compare(last[strings.ToLower(cb.Email)]),
+			cmp.Compare(a, b),
+		)
+	})
+}
+
+func allIndexes(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+func (e *Engine) call(ctx context.Context, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	return fn(callCtx)
+}
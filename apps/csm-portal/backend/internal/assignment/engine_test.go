@@ -0,0 +1,283 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package assignment
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+)
+
+// fakeEntity serves cases by id, one team's members, their ongoing cases and
+// a page of new cases, and records every assignment.
+type fakeEntity struct {
+	cases    map[string]string
+	users    string
+	ongoing  string
+	newCases string
+	patchErr error
+	assigned map[string]string // case id -> PATCH body
+}
+
+func (f *fakeEntity) GetCase(_ context.Context, id string) ([]byte, error) {
+	return []byte(f.cases[id]), nil
+}
+
+func (f *fakeEntity) PatchCase(_ context.Context, id string, body []byte) ([]byte, error) {
+	if f.patchErr != nil {
+		return nil, f.patchErr
+	}
+	f.assigned[id] = string(body)
+	return []byte(`{}`), nil
+}
+
+func (f *fakeEntity) SearchCases(_ context.Context, body []byte) ([]byte, error) {
+	if strings.Contains(string(body), `"op":"isEmpty"`) {
+		return []byte(f.newCases), nil
+	}
+	return []byte(f.ongoing), nil
+}
+
+func (f *fakeEntity) SearchUsers(_ context.Context, _ []byte) ([]byte, error) {
+	return []byte(f.users), nil
+}
+
+const teamUsers = `{"users":[
+	{"id":"u-c","email":"carol@example.com","name":"Carol"},
+	{"id":"u-a","email":"alice@example.com","firstName":"Alice","lastName":"A"},
+	{"id":"u-b","email":"bob@example.com","name":"Bob"}],"total":3}`
+
+// Alice carries one critical case (4), Bob two low ones (2), Carol none.
+const teamOngoing = `{"cases":[
+	{"id":"o1","severity":"critical","assignedEngineer":{"id":"u-a","email":"alice@example.com"}},
+	{"id":"o2","severity":"low","assignedEngineer":{"id":"u-b","email":"bob@example.com"}},
+	{"id":"o3","severity":"low","assignedEngineer":{"id":"u-b","email":"bob@example.com"}}],"total":3}`
+
+func newCase(id, team, product string) string {
+	return `{"id":"` + id + `","number":"CS-` + id + `","severity":"high","assignedTeam":{"name":"` + team + `"},
+		"assignedEngineer":null,"deployedProduct":{"product":{"name":"` + product + `"}}}`
+}
+
+func testEngine(t *testing.T, registry string, skills Skills) (*Engine, *fakeEntity) {
+	t.Helper()
+	teams, err := directory.ParseTeamRegistry(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := directory.New(teams, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := OpenLog("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeEntity{cases: map[string]string{}, users: teamUsers, ongoing: teamOngoing, assigned: map[string]string{}}
+	return NewEngine(f, dir, skills, log), f
+}
+
+func TestAssign_LeastLoaded(t *testing.T) {
+	e, f := testEngine(t, "apim|APIM Team||||least_loaded", nil)
+	f.cases["c1"] = newCase("c1", "APIM Team", "API Manager")
+
+	d, err := e.Assign(context.Background(), "c1", Request{Trigger: TriggerManual, RequestedBy: "lead@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Assignee == nil || d.Assignee.Email != "carol@example.com" {
+		t.Fatalf("assignee = %+v, want carol with no load", d.Assignee)
+	}
+	if f.assigned["c1"] != `{"assigneeEmail":"carol@example.com"}` {
+		t.Errorf("PATCH = %q", f.assigned["c1"])
+	}
+	want := []Candidate{
+		{Email: "alice@example.com", Name: "Alice A", Load: 4, OngoingCases: 1},
+		{Email: "bob@example.com", Name: "Bob", Load: 2, OngoingCases: 2},
+		{Email: "carol@example.com", Name: "Carol"},
+	}
+	for i := range want {
+		if d.Candidates[i] != want[i] {
+			t.Errorf("candidate %d = %+v, want %+v", i, d.Candidates[i], want[i])
+		}
+	}
+	if got := e.log.List(Filter{}); len(got) != 1 || got[0].CaseNumber != "CS-c1" || got[0].RequestedBy != "lead@example.com" || got[0].Reason == "" {
+		t.Errorf("log = %+v", got)
+	}
+
+	// With loads level, the tie goes to whoever was assigned least recently.
+	f.ongoing = `{"cases":[],"total":0}`
+	f.cases["c2"] = newCase("c2", "APIM Team", "API Manager")
+	d, err = e.Assign(context.Background(), "c2", Request{Trigger: TriggerPoller})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Assignee.Email != "alice@example.com" {
+		t.Errorf("tie assignee = %s, want alice (never assigned) over carol (just assigned)", d.Assignee.Email)
+	}
+}
+
+func TestAssign_RoundRobin(t *testing.T) {
+	e, f := testEngine(t, "apim|APIM Team||||round_robin", nil)
+	var got []string
+	for _, id := range []string{"c1", "c2", "c3", "c4"} {
+		f.cases[id] = newCase(id, "APIM Team", "")
+		d, err := e.Assign(context.Background(), id, Request{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, d.Assignee.Name)
+	}
+	if strings.Join(got, ",") != "Alice A,Bob,Carol,Alice A" {
+		t.Errorf("round robin order = %v", got)
+	}
+}
+
+func TestAssign_Skills(t *testing.T) {
+	skills, err := ParseSkills("alice@example.com|API Manager,bob@example.com|api manager|Identity Server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, f := testEngine(t, "apim|APIM Team||||skills", skills)
+
+	f.cases["c1"] = newCase("c1", "APIM Team", "API Manager")
+	d, err := e.Assign(context.Background(), "c1", Request{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Assignee.Email != "bob@example.com" || !d.Assignee.Skilled {
+		t.Errorf("assignee = %+v, want bob, the less loaded of the skilled", d.Assignee)
+	}
+	if len(f.assigned) != 0 || len(e.log.List(Filter{})) != 0 {
+		t.Error("a dry run assigned or recorded")
+	}
+
+	f.cases["c2"] = newCase("c2", "APIM Team", "Micro Integrator")
+	d, err = e.Assign(context.Background(), "c2", Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Assignee.Email != "carol@example.com" || !strings.Contains(d.Reason, "nobody in the team lists Micro Integrator") {
+		t.Errorf("fallback = %+v, %q", d.Assignee, d.Reason)
+	}
+}
+
+func TestAssign_Errors(t *testing.T) {
+	e, f := testEngine(t, "apim|APIM Team||||least_loaded,iam|IAM Team", nil)
+	f.cases["assigned"] = `{"id":"assigned","assignedEngineer":{"id":"u-a","email":"alice@example.com"},"assignedTeam":{"name":"APIM Team"}}`
+	f.cases["noteam"] = `{"id":"noteam","assignedTeam":null}`
+	f.cases["iam"] = newCase("iam", "IAM Team", "")
+	f.cases["c1"] = newCase("c1", "APIM Team", "")
+
+	cases := []struct {
+		id, teamKey string
+		want        error
+	}{
+		{"assigned", "", ErrAlreadyAssigned},
+		{"noteam", "", ErrNoTeam},
+		{"noteam", "nope", ErrUnknownTeam},
+		{"iam", "", ErrNotConfigured},
+	}
+	for _, tc := range cases {
+		if _, err := e.Assign(context.Background(), tc.id, Request{TeamKey: tc.teamKey}); !errors.Is(err, tc.want) {
+			t.Errorf("Assign(%s, %q) error = %v, want %v", tc.id, tc.teamKey, err, tc.want)
+		}
+	}
+	if _, err := e.Assign(context.Background(), "noteam", Request{TeamKey: "apim"}); err != nil {
+		t.Errorf("Assign with teamKey = %v", err)
+	}
+
+	f.users = `{"users":[],"total":0}`
+	d, err := e.Assign(context.Background(), "c1", Request{})
+	if !errors.Is(err, ErrNoCandidates) || d.Assignee != nil {
+		t.Errorf("empty team = %+v, %v; want ErrNoCandidates", d, err)
+	}
+
+	f.users = teamUsers
+	f.patchErr = errors.New("upstream down")
+	d, err = e.Assign(context.Background(), "c1", Request{})
+	if err == nil || d.Assignee != nil || d.Error != "upstream down" {
+		t.Errorf("failed PATCH = %+v, %v", d, err)
+	}
+	if got := e.log.List(Filter{CaseID: "c1"}); len(got) != 2 {
+		t.Errorf("recorded %d decisions for c1, want the empty-team and the failed one", len(got))
+	}
+}
+
+func TestLog_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assignment-log.json")
+	log, err := OpenLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	log.Record(Decision{ID: "d1", CaseID: "c1", TeamKey: "apim", Assignee: &Candidate{Email: "Alice@example.com"}, DecidedOn: at})
+	log.Record(Decision{ID: "d2", CaseID: "c2", TeamKey: "iam", DecidedOn: at.Add(time.Hour)})
+
+	reopened, err := OpenLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.List(Filter{}); len(got) != 2 || got[0].ID != "d2" {
+		t.Errorf("List = %+v, want both, newest first", got)
+	}
+	if got := reopened.List(Filter{TeamKey: "apim", Limit: 5}); len(got) != 1 || got[0].ID != "d1" {
+		t.Errorf("List(apim) = %+v", got)
+	}
+	if got := reopened.LastAssigned("apim"); !got["alice@example.com"].Equal(at) {
+		t.Errorf("LastAssigned = %v", got)
+	}
+}
+
+func TestParseSkills_Rejects(t *testing.T) {
+	for _, raw := range []string{"alice", "alice@example.com", "alice@example.com|", "a@example.com|X,A@example.com|Y"} {
+		if _, err := ParseSkills(raw); err == nil || !strings.Contains(err.Error(), "row ") {
+			t.Errorf("ParseSkills(%q) error = %v, want a rejection naming the row", raw, err)
+		}
+	}
+	if s, err := ParseSkills(" , "); err != nil || len(s) != 0 {
+		t.Errorf("ParseSkills(blank) = %v, %v", s, err)
+	}
+}
+
+func TestPoller_AssignsNewCasesInConfiguredQueues(t *testing.T) {
+	e, f := testEngine(t, "apim|APIM Team||||least_loaded,iam|IAM Team", nil)
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	p := NewPoller(e, time.Minute)
+	p.now = func() time.Time { return now }
+
+	f.cases["c1"] = newCase("c1", "APIM Team", "")
+	f.newCases = `{"cases":[` + newCase("c1", "APIM Team", "") + `,` + newCase("c2", "IAM Team", "") + `,{"id":"c3","assignedTeam":null}],"total":3}`
+	p.PollOnce(context.Background()) // seeds
+	if len(f.assigned) != 0 {
+		t.Fatalf("the seeding poll assigned %v", f.assigned)
+	}
+
+	now = now.Add(time.Minute)
+	p.PollOnce(context.Background())
+	p.PollOnce(context.Background())
+	if len(f.assigned) != 1 || f.assigned["c1"] == "" {
+		t.Errorf("assigned = %v, want only c1, in the configured team's queue", f.assigned)
+	}
+	if got := e.log.List(Filter{}); len(got) != 1 || got[0].Trigger != TriggerPoller {
+		t.Errorf("log = %+v, want one poller decision", got)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package assignment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/filestore"
+)
+
+// maxDecisions is how many decisions the Log keeps; the oldest are dropped.
+const maxDecisions = 5000
+
+// Filter narrows Log.List. Empty fields match anything.
+type Filter struct {
+	CaseID  string
+	TeamKey string
+	// Limit caps the result; zero or less is every match.
+	Limit int
+}
+
+// Log is the audit trail of assignment decisions, oldest first. With a path
+// it is persisted to that JSON file on every decision; without one it is in
+// memory only and lost on restart.
+type Log struct {
+	path string
+
+	mu        sync.RWMutex
+	decisions []Decision
+}
+
+// OpenLog loads the log from path. A missing file is an empty log; a
+// malformed one is an error. An empty path is an in-memory log.
+func OpenLog(path string) (*Log, error) {
+	l := &Log{path: path}
+	if path == "" {
+		return l, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read assignment log: %w", err)
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return l, nil
+	}
+	if err := json.Unmarshal(raw, &l.decisions); err != nil {
+		return nil, fmt.Errorf("parse assignment log %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Record appends d. The case has already been assigned (or not) by the time
+// it is recorded, so a failure to persist is logged rather than returned: d
+// is still kept in memory.
+func (l *Log) Record(d Decision) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.decisions = append(l.decisions, d)
+	if over := len(l.decisions) - maxDecisions; over > 0 {
+		l.decisions = append([]Decision(nil), l.decisions[over:]...)
+	}
+	if err := l.persist(); err != nil {
+		slog.Error("assignment: failed to persist decision log", "caseID", d.CaseID, "err", err)
+	}
+}
+
+// List returns the decisions matching f, newest first.
+func (l *Log) List(f Filter) []Decision {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := []Decision{}
+	for i := len(l.decisions) - 1; i >= 0; i-- {
+		d := l.decisions[i]
+		if (f.CaseID != "" && d.CaseID != f.CaseID) || (f.TeamKey != "" && d.TeamKey != f.TeamKey) {
+			continue
+		}
+		out = append(out, d)
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// LastAssigned returns when each engineer, by lowercased email, was last
+// assigned a case of team teamKey.
+func (l *Log) LastAssigned(teamKey string) map[string]time.Time {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := map[string]time.Time{}
+	for _, d := range l.decisions {
+		if d.TeamKey == teamKey && d.Assignee != nil {
+			out[strings.ToLower(d.Assignee.Email)] = d.DecidedOn
+		}
+	}
+	return out
+}
+
+// persist saves the whole decision log to path. Callers hold mu.
+func (l *Log) persist() error {
+	if l.path == "" {
+		return nil
+	}
+	raw, err := json.Marshal(l.decisions)
+	if err != nil {
+		return err
+	}
+	return filestore.WriteFile(l.path, raw)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package assignment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// createdLookback widens each created-since search past the previous poll,
+// so a case whose createdOn lags its visibility in search is still seen. A
+// case already assigned drops out of the search by itself.
+const createdLookback = 2 * time.Minute
+
+// Poller assigns new, unassigned cases in the queue of every team with an
+// assignment strategy. Its first poll only records the time: cases already
+// waiting when it starts are left to a lead. Every replica running it
+// assigns; run it on one.
+type Poller struct {
+	engine   *Engine
+	interval time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	since  time.Time
+	seeded bool
+	// attempted is when each case was last tried, so a case that could not
+	// be assigned is not retried -- and re-recorded -- on every poll while it
+	// is inside the lookback.
+	attempted map[string]time.Time
+}
+
+// NewPoller creates a Poller assigning through engine every interval.
+func NewPoller(engine *Engine, interval time.Duration) *Poller {
+	return &Poller{engine: engine, interval: interval, now: time.Now, attempted: make(map[string]time.Time)}
+}
+
+// Run polls once immediately, to seed its state, and then every interval
+// until ctx is cancelled.
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.PollOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollOnce assigns the cases created since the previous poll that are still
+// unassigned and in a configured team's queue.
+func (p *Poller) PollOnce(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if !p.seeded {
+		p.since, p.seeded = now, true
+		return
+	}
+	cases, more, err := p.newUnassigned(ctx, p.since.Add(-createdLookback))
+	if err != nil {
+		slog.ErrorContext(ctx, "assignment: new-case search failed", "err", err)
+		return
+	}
+	p.since = now
+	if more {
+		// Cases come oldest first, so the next poll carries on from the last
+		// one read rather than skipping the rest.
+		if last, err := time.Parse(time.RFC3339, cases[len(cases)-1].CreatedOn); err == nil {
+			p.since = last.Add(createdLookback)
+		}
+	}
+
+	for id, at := range p.attempted {
+		if now.Sub(at) > createdLookback+p.interval {
+			delete(p.attempted, id)
+		}
+	}
+	for _, c := range cases {
+		if _, done := p.attempted[c.ID]; done || c.AssignedTeam == nil {
+			continue
+		}
+		team, ok := p.engine.dir.TeamByGroupName(c.AssignedTeam.Name)
+		if !ok || team.Assignment == "" {
+			continue
+		}
+		p.attempted[c.ID] = now
+		d, err := p.engine.Assign(ctx, c.ID, Request{Trigger: TriggerPoller})
+		switch {
+		case err == nil:
+			slog.InfoContext(ctx, "assignment: assigned new case", "caseID", c.ID, "team", team.Key, "assignee", d.Assignee.Email)
+		case errors.Is(err, ErrAlreadyAssigned):
+		default:
+			slog.WarnContext(ctx, "assignment: could not assign new case", "caseID", c.ID, "team", team.Key, "err", err)
+		}
+	}
+}
+
+// newUnassigned returns the unassigned open cases created at or after since,
+// oldest first, and whether there are more than one page of them.
+func (p *Poller) newUnassigned(ctx context.Context, since time.Time) ([]caseInfo, bool, error) {
+	body, err := json.Marshal(map[string]any{
+		"filters": map[string]any{"filters": []map[string]any{
+			{"field": "createdOn", "op": "gte", "values": []string{since.UTC().Format(time.RFC3339)}},
+			{"field": "assignedUserId", "op": "isEmpty"},
+			{"field": "state", "op": "in", "values": []string{"open"}},
+		}},
+		"sortBy":     map[string]string{"field": "createdOn", "order": "asc"},
+		"pagination": map[string]int{"offset": 0, "limit": casePageLimit},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	raw, err := p.engine.call(ctx, func(ctx context.Context) ([]byte, error) { return p.engine.entity.SearchCases(ctx, body) })
+	if err != nil {
+		return nil, false, err
+	}
+	var resp struct {
+		Cases []caseInfo `json:"cases"`
+		Total int        `json:"total"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, false, fmt.Errorf("decode case search: %w", err)
+	}
+	more := resp.Total > len(resp.Cases) && len(resp.Cases) > 0
+	if more {
+		slog.WarnContext(ctx, "assignment: new-case search matched more than one page; the rest wait for the next poll",
+			"total", resp.Total, "limit", casePageLimit)
+	}
+	return resp.Cases, more, nil
+}
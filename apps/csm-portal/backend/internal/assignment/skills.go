@@ -0,0 +1,71 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package assignment
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Skills maps an engineer's lowercased email to the lowercased names of the
+// products they are skilled in.
+type Skills map[string][]string
+
+// ParseSkills parses engineer skills from the same flat, single-line form as
+// the team registry, for the same reason (see directory.ParseTeamRegistry):
+//
+//	engineer@example.com|Product One|Product Two,other@example.com|Product One
+//
+// Rows are separated by ",", fields by "|": an email, then one or more product
+// names as cases carry them. Whitespace is trimmed, a blank row is skipped,
+// and emails and products compare case-insensitively. An empty string yields
+// no skills, which leaves a skills-based team assigning by load alone.
+func ParseSkills(raw string) (Skills, error) {
+	skills := Skills{}
+	for i, row := range strings.Split(raw, ",") {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		fields := strings.Split(row, "|")
+		email := strings.ToLower(strings.TrimSpace(fields[0]))
+		if !strings.Contains(email, "@") {
+			return nil, fmt.Errorf("engineer skills row %d (%q): %q is not an email", i+1, strings.TrimSpace(row), email)
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("engineer skills row %d (%q): no products", i+1, strings.TrimSpace(row))
+		}
+		if _, dup := skills[email]; dup {
+			return nil, fmt.Errorf("engineer skills row %d (%q): %s is configured more than once", i+1, strings.TrimSpace(row), email)
+		}
+		products := make([]string, 0, len(fields)-1)
+		for _, p := range fields[1:] {
+			p = strings.ToLower(strings.TrimSpace(p))
+			if p == "" {
+				return nil, fmt.Errorf("engineer skills row %d (%q): empty product name", i+1, strings.TrimSpace(row))
+			}
+			products = append(products, p)
+		}
+		skills[email] = products
+	}
+	return skills, nil
+}
+
+// Has reports whether the engineer with email is skilled in product.
+func (s Skills) Has(email, product string) bool {
+	return slices.Contains(s[strings.ToLower(email)], strings.ToLower(product))
+}
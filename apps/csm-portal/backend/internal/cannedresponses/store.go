@@ -0,0 +1,183 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cannedresponses
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/filestore"
+)
+
+// ErrNotFound is returned for an id no template has.
+var ErrNotFound = errors.New("cannedresponses: template not found")
+
+// Store holds every template, keyed by id. With a path it is persisted to
+// that JSON file on every change; without one it is in memory only and lost
+// on restart.
+type Store struct {
+	path string
+	now  func() time.Time
+
+	mu        sync.RWMutex
+	templates map[string]Template
+}
+
+// OpenStore loads the store from path. A missing file is an empty store; a
+// malformed one is an error. An empty path is an in-memory store.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, now: time.Now, templates: make(map[string]Template)}
+	if path == "" {
+		return s, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read canned responses: %w", err)
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return s, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s.templates); err != nil {
+		return nil, fmt.Errorf("parse canned responses %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// List returns every template, by name.
+func (s *Store) List() []Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.SortedFunc(maps.Values(s.templates), func(a, b Template) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+// Get returns the template with id.
+func (s *Store) Get(id string) (Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[id]
+	if !ok {
+		return Template{}, ErrNotFound
+	}
+	return t, nil
+}
+
+// Create saves a new template written by author and returns it. The caller
+// has validated in.
+func (s *Store) Create(in Input, author string) (Template, error) {
+	now := s.now().UTC()
+	t := Template{ID: filestore.NewID(), Input: normalize(in), CreatedBy: author, CreatedOn: now, UpdatedOn: now}
+	if in.Scope == ScopePersonal {
+		t.Owner = author
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[t.ID] = t
+	if err := s.persist(); err != nil {
+		delete(s.templates, t.ID)
+		return Template{}, err
+	}
+	return t, nil
+}
+
+// Update replaces the author-written part of template id and returns it.
+// editor becomes the owner if it is now personal. The caller has validated in.
+func (s *Store) Update(id string, in Input, editor string) (Template, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, ok := s.templates[id]
+	if !ok {
+		return Template{}, ErrNotFound
+	}
+	t := prev
+	t.Input = normalize(in)
+	t.Owner = ""
+	if in.Scope == ScopePersonal {
+		t.Owner = prev.Owner
+		if t.Owner == "" {
+			t.Owner = editor
+		}
+	}
+	t.UpdatedOn = s.now().UTC()
+	s.templates[id] = t
+	if err := s.persist(); err != nil {
+		s.templates[id] = prev
+		return Template{}, err
+	}
+	return t, nil
+}
+
+// Delete removes template id.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, ok := s.templates[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.templates, id)
+	if err := s.persist(); err != nil {
+		s.templates[id] = prev
+		return err
+	}
+	return nil
+}
+
+// normalize trims in and makes its lists empty rather than null.
+func normalize(in Input) Input {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Products == nil {
+		in.Products = []string{}
+	}
+	for i, p := range in.Products {
+		in.Products[i] = strings.TrimSpace(p)
+	}
+	if in.IssueTypes == nil {
+		in.IssueTypes = []string{}
+	}
+	return in
+}
+
+// persist saves every template to path. Callers hold mu.
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+	raw, err := json.MarshalIndent(s.templates, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := filestore.WriteFile(s.path, raw); err != nil {
+		return fmt.Errorf("persist canned responses: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cannedresponses
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "canned-responses.json")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	team, err := s.Create(Input{Name: " Logs ", Body: "x", Scope: ScopeTeam, TeamKey: "abt-1"}, "lead@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team.Name != "Logs" || team.Owner != "" || team.Products == nil || team.IssueTypes == nil {
+		t.Errorf("created = %+v, want a trimmed name, no owner and empty lists", team)
+	}
+	mine, err := s.Create(Input{Name: "Mine", Body: "y", Scope: ScopePersonal}, "agent@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mine.Owner != "agent@example.com" {
+		t.Errorf("personal owner = %q", mine.Owner)
+	}
+
+	// Sharing a personal template keeps no owner; making it personal again
+	// gives it to whoever did so.
+	shared, err := s.Update(mine.ID, Input{Name: "Mine", Body: "y", Scope: ScopeGlobal}, "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared.Owner != "" || shared.CreatedBy != "agent@example.com" {
+		t.Errorf("shared = %+v", shared)
+	}
+	if _, err := s.Update(team.ID, Input{Name: "Logs", Body: "z", Scope: ScopePersonal}, "lead@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(mine.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reopened.List()
+	if len(got) != 1 || got[0].ID != team.ID || got[0].Body != "z" || got[0].Owner != "lead@example.com" {
+		t.Errorf("reopened = %+v", got)
+	}
+
+	if _, err := reopened.Get(mine.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(deleted) error = %v, want ErrNotFound", err)
+	}
+	if _, err := reopened.Update("missing", Input{}, ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := reopened.Delete("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOpenStore_RejectsMalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "canned-responses.json")
+	if err := os.WriteFile(path, []byte(`{"a":{"id":"a","nme":"typo"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenStore(path); err == nil {
+		t.Error("OpenStore succeeded on an unknown field, want an error")
+	}
+	if s, err := OpenStore(filepath.Join(t.TempDir(), "missing.json")); err != nil || len(s.List()) != 0 {
+		t.Errorf("OpenStore(missing) = %v, %v; want an empty store", s, err)
+	}
+}
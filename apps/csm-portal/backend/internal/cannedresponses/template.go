@@ -0,0 +1,344 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package cannedresponses is the library of reusable comment replies --
+// "please send us the carbon logs" -- that support engineers post on cases.
+// A template's body is a Go text/template rendered against the case it is
+// posted on (see CaseView), and each template is shared with everyone, with
+// one team, or kept to its author (see Scope).
+package cannedresponses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+	tparse "text/template/parse"
+	"time"
+	"unicode/utf8"
+)
+
+// Scope is who a template is shared with.
+type Scope string
+
+const (
+	// ScopeGlobal templates are visible to everyone; only admins change them.
+	ScopeGlobal Scope = "global"
+	// ScopeTeam templates are visible to, and changed by, one team's members.
+	ScopeTeam Scope = "team"
+	// ScopePersonal templates are visible to, and changed by, their owner.
+	ScopePersonal Scope = "personal"
+)
+
+var validScopes = map[Scope]bool{ScopeGlobal: true, ScopeTeam: true, ScopePersonal: true}
+
+// IssueTypes are the case issue types a template can be categorised under,
+// as the entity service names them.
+var IssueTypes = []string{
+	"error",
+	"partial_outage",
+	"performance_degradation",
+	"question",
+	"security_or_compliance",
+	"total_outage",
+}
+
+const (
+	maxNameRunes = 200
+	// maxBodyRunes keeps a template well inside a comment's own size limit
+	// once rendered.
+	maxBodyRunes  = 20000
+	maxCategories = 50
+	// maxRenderedBytes is the most a body may render to: a reply has to fit
+	// in one comment, and a body should not be able to spin its output out
+	// much past what was written.
+	maxRenderedBytes = 256 << 10
+)
+
+// Input is the part of a template its author writes.
+type Input struct {
+	Name string `json:"name"`
+	// Body is a text/template rendered against a CaseView.
+	Body  string `json:"body"`
+	Scope Scope  `json:"scope"`
+	// TeamKey is the registry key of the team a ScopeTeam template is shared
+	// with; it must be empty for any other scope.
+	TeamKey string `json:"teamKey,omitempty"`
+	// Products are the product names the template applies to; empty is any.
+	Products []string `json:"products"`
+	// IssueTypes are the issue types the template applies to; empty is any.
+	IssueTypes []string `json:"issueTypes"`
+}
+
+// Template is a saved canned response.
+type Template struct {
+	ID string `json:"id"`
+	Input
+	// Owner is the email of the author of a ScopePersonal template.
+	Owner     string    `json:"owner,omitempty"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedOn time.Time `json:"createdOn"`
+	UpdatedOn time.Time `json:"updatedOn"`
+}
+
+// Validate reports the first problem with in, phrased for the user who sent
+// it. knownTeam reports whether a team key is in the registry. A body is
+// checked by parsing it and rendering it against an empty case, so a field
+// CaseView does not have is caught here rather than when it is first used.
+func (in Input) Validate(knownTeam func(string) bool) error {
+	if strings.TrimSpace(in.Name) == "" {
+		return errors.New("name is required")
+	}
+	if utf8.RuneCountInString(in.Name) > maxNameRunes {
+		return fmt.Errorf("name is longer than %d characters", maxNameRunes)
+	}
+	if strings.TrimSpace(in.Body) == "" {
+		return errors.New("body is required")
+	}
+	if utf8.RuneCountInString(in.Body) > maxBodyRunes {
+		return fmt.Errorf("body is longer than %d characters", maxBodyRunes)
+	}
+	tmpl, err := parse(in.Body)
+	if err != nil {
+		return fmt.Errorf("body is not a valid template: %w", err)
+	}
+	if err := tmpl.Execute(&limitWriter{w: io.Discard, n: maxRenderedBytes}, CaseView{}); err != nil {
+		return fmt.Errorf("body is not a valid template: %w", err)
+	}
+	if !validScopes[in.Scope] {
+		return fmt.Errorf("scope must be one of %q, %q or %q", ScopeGlobal, ScopeTeam, ScopePersonal)
+	}
+	if in.Scope == ScopeTeam {
+		if in.TeamKey == "" {
+			return errors.New("teamKey is required for a team template")
+		}
+		if !knownTeam(in.TeamKey) {
+			return fmt.Errorf("unknown teamKey %q", in.TeamKey)
+		}
+	} else if in.TeamKey != "" {
+		return errors.New("teamKey only applies to a team template")
+	}
+	if len(in.Products) > maxCategories || len(in.IssueTypes) > maxCategories {
+		return fmt.Errorf("products and issueTypes are limited to %d values each", maxCategories)
+	}
+	for _, p := range in.Products {
+		if strings.TrimSpace(p) == "" {
+			return errors.New("products cannot contain an empty name")
+		}
+	}
+	for _, t := range in.IssueTypes {
+		if !slices.Contains(IssueTypes, t) {
+			return fmt.Errorf("unknown issue type %q", t)
+		}
+	}
+	return nil
+}
+
+// parse parses body as a template. A CaseView has nothing to range over, so
+// {{range}} is rejected: all it could do is loop over an integer. So are
+// {{define}}, {{block}} and {{template}}: a body has no use for named
+// templates, and ones calling each other twice over execute exponentially
+// many times without writing a byte.
+func parse(body string) (*template.Template, error) {
+	tmpl, err := template.New("canned-response").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && contains(t.Tree.Root, tparse.NodeRange) {
+			return nil, errors.New("{{range}} is not supported")
+		}
+	}
+	for _, t := range tmpl.Templates() {
+		if t != tmpl || (t.Tree != nil && contains(t.Tree.Root, tparse.NodeTemplate)) {
+			return nil, errors.New("{{define}}, {{block}} and {{template}} are not supported")
+		}
+	}
+	return tmpl, nil
+}
+
+// contains reports whether n contains a node of type typ.
+func contains(n tparse.Node, typ tparse.NodeType) bool {
+	switch n := n.(type) {
+	case *tparse.ListNode:
+		if n == nil {
+			return false
+		}
+		return slices.ContainsFunc(n.Nodes, func(n tparse.Node) bool { return contains(n, typ) })
+	case *tparse.RangeNode:
+		return typ == tparse.NodeRange || contains(n.List, typ) || contains(n.ElseList, typ)
+	case *tparse.TemplateNode:
+		return typ == tparse.NodeTemplate
+	case *tparse.IfNode:
+		return contains(n.List, typ) || contains(n.ElseList, typ)
+	case *tparse.WithNode:
+		return contains(n.List, typ) || contains(n.ElseList, typ)
+	}
+	return false
+}
+
+// errRenderTooLarge aborts an execution whose output passes maxRenderedBytes.
+var errRenderTooLarge = fmt.Errorf("body renders to more than %d bytes", maxRenderedBytes)
+
+// limitWriter writes to w until n bytes have been written, then fails,
+// which stops the template executing into it.
+type limitWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		return 0, errRenderTooLarge
+	}
+	l.n -= len(p)
+	return l.w.Write(p)
+}
+
+// Person is someone on a case, by name and email.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// CaseView is what a template body is rendered against: {{.Customer.Name}},
+// {{.Project}}, {{.Product}}, {{.Number}}, {{.AssignedEngineer.Name}} and so
+// on. A field the case does not have renders empty.
+type CaseView struct {
+	ID        string
+	Number    string
+	Subject   string
+	Severity  string
+	IssueType string
+	State     string
+	// Customer is the contact who raised the case.
+	Customer         Person
+	AssignedEngineer Person
+	Account          string
+	Project          string
+	Product          string
+	Deployment       string
+}
+
+// ParseCaseView reads a CaseView out of a GET /cases/{id} response.
+func ParseCaseView(raw []byte) (CaseView, error) {
+	type ref struct {
+		Name string `json:"name"`
+	}
+	type person struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	var c struct {
+		ID               string  `json:"id"`
+		Number           string  `json:"number"`
+		Subject          string  `json:"subject"`
+		Severity         string  `json:"severity"`
+		IssueType        string  `json:"issueType"`
+		State            string  `json:"state"`
+		CreatedBy        *person `json:"createdBy"`
+		AssignedEngineer *person `json:"assignedEngineer"`
+		Account          *ref    `json:"account"`
+		Project          *ref    `json:"project"`
+		Deployment       *ref    `json:"deployment"`
+		DeployedProduct  *struct {
+			Product *ref `json:"product"`
+		} `json:"deployedProduct"`
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return CaseView{}, fmt.Errorf("decode case: %w", err)
+	}
+	v := CaseView{ID: c.ID, Number: c.Number, Subject: c.Subject, Severity: c.Severity, IssueType: c.IssueType, State: c.State}
+	if c.CreatedBy != nil {
+		v.Customer = Person(*c.CreatedBy)
+	}
+	if c.AssignedEngineer != nil {
+		v.AssignedEngineer = Person(*c.AssignedEngineer)
+	}
+	if c.Account != nil {
+		v.Account = c.Account.Name
+	}
+	if c.Project != nil {
+		v.Project = c.Project.Name
+	}
+	if c.Deployment != nil {
+		v.Deployment = c.Deployment.Name
+	}
+	if c.DeployedProduct != nil && c.DeployedProduct.Product != nil {
+		v.Product = c.DeployedProduct.Product.Name
+	}
+	return v, nil
+}
+
+// Render renders t's body for the case v.
+func (t Template) Render(v CaseView) (string, error) {
+	tmpl, err := parse(t.Body)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&limitWriter{w: &b, n: maxRenderedBytes}, v); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Caller is who is reading or changing templates.
+type Caller struct {
+	Email string
+	// TeamKeys are the registry teams the caller is a member of.
+	TeamKeys []string
+	// Admin callers change global templates.
+	Admin bool
+}
+
+// VisibleTo reports whether c can see and use t.
+func (t Template) VisibleTo(c Caller) bool {
+	switch t.Scope {
+	case ScopeGlobal:
+		return true
+	case ScopeTeam:
+		return slices.Contains(c.TeamKeys, t.TeamKey)
+	case ScopePersonal:
+		return strings.EqualFold(t.Owner, c.Email)
+	}
+	return false
+}
+
+// EditableBy reports whether c can create, change or delete a template
+// scoped like t.
+func (t Template) EditableBy(c Caller) bool {
+	if t.Scope == ScopeGlobal {
+		return c.Admin
+	}
+	return t.VisibleTo(c)
+}
+
+// Matches reports whether t applies to a case of product and issueType; an
+// empty argument matches any. Products compare case-insensitively.
+func (t Template) Matches(product, issueType string) bool {
+	if product != "" && len(t.Products) > 0 &&
+		!slices.ContainsFunc(t.Products, func(p string) bool { return strings.EqualFold(p, product) }) {
+		return false
+	}
+	if issueType != "" && len(t.IssueTypes) > 0 && !slices.Contains(t.IssueTypes, issueType) {
+		return false
+	}
+	return true
+}
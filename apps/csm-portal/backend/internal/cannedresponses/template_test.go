@@ -0,0 +1,170 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cannedresponses
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func knownTeams(keys ...string) func(string) bool {
+	return func(k string) bool { return slices.Contains(keys, k) }
+}
+
+func TestInputValidate_Rejects(t *testing.T) {
+	valid := Input{Name: "Logs", Body: "Hi {{.Customer.Name}}", Scope: ScopePersonal}
+	cases := []struct {
+		name string
+		edit func(*Input)
+		want string
+	}{
+		{"no name", func(in *Input) { in.Name = " " }, "name is required"},
+		{"long name", func(in *Input) { in.Name = strings.Repeat("n", maxNameRunes+1) }, "name is longer"},
+		{"no body", func(in *Input) { in.Body = "" }, "body is required"},
+		{"unparsable body", func(in *Input) { in.Body = "{{.Number" }, "not a valid template"},
+		{"unknown field", func(in *Input) { in.Body = "{{.Customer.Phone}}" }, "not a valid template"},
+		{"range", func(in *Input) { in.Body = "{{range 1000000000}}x{{end}}" }, "{{range}} is not supported"},
+		{"nested range", func(in *Input) { in.Body = `{{define "x"}}{{if .}}{{range 9}}{{end}}{{end}}{{end}}hi` }, "{{range}} is not supported"},
+		{"define", func(in *Input) { in.Body = `{{define "a"}}a{{end}}hi` }, "{{define}}, {{block}} and {{template}} are not supported"},
+		{"block", func(in *Input) { in.Body = `{{block "a" .}}a{{end}}` }, "{{define}}, {{block}} and {{template}} are not supported"},
+		{"template", func(in *Input) { in.Body = `{{if .Number}}{{template "a"}}{{end}}` }, "{{define}}, {{block}} and {{template}} are not supported"},
+		{"exponential calls", func(in *Input) {
+			var b strings.Builder
+			for i := range 40 {
+				fmt.Fprintf(&b, `{{define "t%d"}}{{template "t%d"}}{{template "t%d"}}{{end}}`, i, i+1, i+1)
+			}
+			b.WriteString(`{{define "t40"}}{{end}}{{template "t0"}}`)
+			in.Body = b.String()
+		}, "{{define}}, {{block}} and {{template}} are not supported"},
+		{"unknown scope", func(in *Input) { in.Scope = "org" }, "scope must be one of"},
+		{"team without key", func(in *Input) { in.Scope = ScopeTeam }, "teamKey is required"},
+		{"unknown team", func(in *Input) { in.Scope, in.TeamKey = ScopeTeam, "gamma" }, `unknown teamKey "gamma"`},
+		{"team key off team", func(in *Input) { in.TeamKey = "abt-1" }, "only applies to a team"},
+		{"empty product", func(in *Input) { in.Products = []string{" "} }, "empty name"},
+		{"unknown issue type", func(in *Input) { in.IssueTypes = []string{"outage"} }, `unknown issue type "outage"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := valid
+			tc.edit(&in)
+			err := in.Validate(knownTeams("abt-1"))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("Validate error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+
+	team := Input{Name: "Logs", Body: "{{.Project}}", Scope: ScopeTeam, TeamKey: "abt-1", IssueTypes: []string{"error"}}
+	if err := team.Validate(knownTeams("abt-1")); err != nil {
+		t.Errorf("Validate(team) = %v", err)
+	}
+}
+
+func TestParseCaseViewAndRender(t *testing.T) {
+	v, err := ParseCaseView([]byte(`{"id":"c1","number":"CS001","subject":"Login fails","state":"open",
+		"createdBy":{"id":"u1","name":"Chris","email":"chris@acme.example"},
+		"assignedEngineer":null,
+		"account":{"name":"Acme"},"project":{"name":"Acme Prod"},"deployment":{"name":"Prod DC"},
+		"deployedProduct":{"product":{"name":"WSO2 API Manager"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CaseView{ID: "c1", Number: "CS001", Subject: "Login fails", State: "open",
+		Customer: Person{Name: "Chris", Email: "chris@acme.example"},
+		Account:  "Acme", Project: "Acme Prod", Product: "WSO2 API Manager", Deployment: "Prod DC"}
+	if v != want {
+		t.Fatalf("ParseCaseView = %+v, want %+v", v, want)
+	}
+
+	tmpl := Template{Input: Input{Body: "Hi {{.Customer.Name}}, {{.Number}} on {{.Product}} is with {{or .AssignedEngineer.Name \"the team\"}}."}}
+	got, err := tmpl.Render(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Hi Chris, CS001 on WSO2 API Manager is with the team." {
+		t.Errorf("Render = %q", got)
+	}
+
+	if _, err := ParseCaseView([]byte(`[]`)); err == nil {
+		t.Error("ParseCaseView([]) succeeded, want an error")
+	}
+}
+
+func TestTemplateAccess(t *testing.T) {
+	member := Caller{Email: "Agent@Example.com", TeamKeys: []string{"abt-1"}}
+	admin := Caller{Email: "admin@example.com", Admin: true}
+	cases := []struct {
+		name              string
+		t                 Template
+		visible, editable bool
+		adminEdits        bool
+	}{
+		{"global", Template{Input: Input{Scope: ScopeGlobal}}, true, false, true},
+		{"own team", Template{Input: Input{Scope: ScopeTeam, TeamKey: "abt-1"}}, true, true, false},
+		{"other team", Template{Input: Input{Scope: ScopeTeam, TeamKey: "beta"}}, false, false, false},
+		{"own", Template{Input: Input{Scope: ScopePersonal}, Owner: "agent@example.com"}, true, true, false},
+		{"someone else's", Template{Input: Input{Scope: ScopePersonal}, Owner: "admin@example.com"}, false, false, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.t.VisibleTo(member); got != tc.visible {
+				t.Errorf("VisibleTo = %v, want %v", got, tc.visible)
+			}
+			if got := tc.t.EditableBy(member); got != tc.editable {
+				t.Errorf("EditableBy = %v, want %v", got, tc.editable)
+			}
+			if got := tc.t.EditableBy(admin); got != tc.adminEdits {
+				t.Errorf("EditableBy(admin) = %v, want %v", got, tc.adminEdits)
+			}
+		})
+	}
+}
+
+func TestTemplateMatches(t *testing.T) {
+	tmpl := Template{Input: Input{Products: []string{"WSO2 API Manager"}, IssueTypes: []string{"error", "question"}}}
+	cases := []struct {
+		product, issueType string
+		want               bool
+	}{
+		{"", "", true},
+		{"wso2 api manager", "", true},
+		{"WSO2 Identity Server", "", false},
+		{"", "question", true},
+		{"WSO2 API Manager", "total_outage", false},
+	}
+	for _, tc := range cases {
+		if got := tmpl.Matches(tc.product, tc.issueType); got != tc.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tc.product, tc.issueType, got, tc.want)
+		}
+	}
+	if !(Template{}).Matches("anything", "error") {
+		t.Error("an uncategorised template should match any case")
+	}
+}
+
+func TestRender_StopsAtSizeLimit(t *testing.T) {
+	tmpl := Template{Input: Input{Body: "{{.Subject}}{{.Subject}}"}}
+	if _, err := tmpl.Render(CaseView{Subject: strings.Repeat("s", maxRenderedBytes/2)}); err != nil {
+		t.Fatalf("Render at the limit = %v", err)
+	}
+	if _, err := tmpl.Render(CaseView{Subject: strings.Repeat("s", maxRenderedBytes/2+1)}); !errors.Is(err, errRenderTooLarge) {
+		t.Fatalf("Render past the limit = %v, want errRenderTooLarge", err)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package casenotify
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// maxHeldPerUser caps how many notifications are held for one user between
+// digests. Past it the oldest are dropped: a digest of hundreds of lines is
+// not read anyway, and an unbounded queue is a leak for a user whose mailbox
+// keeps rejecting mail.
+const maxHeldPerUser = 200
+
+// EmailNotifier sends an HTML email (see notifications.EmailClient).
+type EmailNotifier interface {
+	SendEmail(ctx context.Context, to, cc, bcc, replyTo []string, subject, htmlBody string, attachments []notifications.EmailAttachment) error
+}
+
+// Event is one thing that happened on a case, to be told to its recipients.
+// A mention can be on an incident or change request instead; CaseID and
+// CaseNumber then name that record and Link points at it.
+type Event struct {
+	Type       EventType
+	CaseID     string
+	CaseNumber string
+	Subject    string
+	// Link is the record's portal URL; empty means the case page.
+	Link string
+	// Actor is who did it, by display name; ActorEmail, when known, is never
+	// notified of their own action.
+	Actor      string
+	ActorEmail string
+	// Summary is the comment text, or the change ("Work In Progress",
+	// "Jane Doe").
+	Summary string
+	At      time.Time
+	// Recipients are the email addresses of the case's assignee and watchers.
+	Recipients []string
+}
+
+// held is the notifications waiting for one user's next digest.
+type held struct {
+	since  time.Time
+	events []Event
+}
+
+// Dispatcher turns events into emails, each recipient's way: sent at once,
+// or held for a digest, per their Preferences.
+type Dispatcher struct {
+	prefs         *Store
+	email         EmailNotifier
+	portalBaseURL string
+	now           func() time.Time
+
+	mu   sync.Mutex
+	held map[string]*held
+}
+
+// NewDispatcher creates a Dispatcher reading preferences from prefs.
+func NewDispatcher(prefs *Store, email EmailNotifier, portalBaseURL string) *Dispatcher {
+	return &Dispatcher{
+		prefs:         prefs,
+		email:         email,
+		portalBaseURL: strings.TrimRight(portalBaseURL, "/"),
+		now:           time.Now,
+		held:          make(map[string]*held),
+	}
+}
+
+// Dispatch notifies every recipient of e who wants it: by email now if they
+// take notifications as they happen and are outside quiet hours, otherwise by
+// holding it for their next digest. A failed immediate email is held too, so
+// it goes out with the next flush.
+func (d *Dispatcher) Dispatch(ctx context.Context, e Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	seen := make(map[string]bool, len(e.Recipients))
+	for _, r := range e.Recipients {
+		key := storeKey(r)
+		if key == "" || seen[key] || key == storeKey(e.ActorEmail) {
+			continue
+		}
+		seen[key] = true
+		p, _ := d.prefs.Get(key)
+		if !p.wants(e.Type) {
+			continue
+		}
+		if p.DigestFrequency == DigestNone && !p.quiet(now) {
+			subject, body, err := renderEvent(e, d.link(e))
+			if err == nil {
+				err = d.email.SendEmail(ctx, []string{key}, nil, nil, nil, subject, body, nil)
+			}
+			if err == nil {
+				continue
+			}
+			slog.WarnContext(ctx, "case notifications: email failed; holding it for the next flush",
+				"recipient", key, "caseId", e.CaseID, "event", string(e.Type), "err", err)
+		}
+		d.hold(ctx, key, e, now)
+	}
+}
+
+func (d *Dispatcher) hold(ctx context.Context, recipient string, e Event, now time.Time) {
+	h := d.held[recipient]
+	if h == nil {
+		h = &held{since: now}
+		d.held[recipient] = h
+	}
+	h.events = append(h.events, e)
+	if over := len(h.events) - maxHeldPerUser; over > 0 {
+		slog.WarnContext(ctx, "case notifications: too many held for one user; dropping the oldest",
+			"recipient", recipient, "dropped", over)
+		h.events = h.events[over:]
+	}
+}
+
+// FlushDue sends every held digest that is due under its recipient's current
+// preferences. A failed send stays held for the next call.
+func (d *Dispatcher) FlushDue(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for recipient, h := range d.held {
+		p, _ := d.prefs.Get(recipient)
+		if !p.digestDue(h.since, now) {
+			continue
+		}
+		subject, body, err := renderDigest(h.events, d.link)
+		if err == nil {
+			err = d.email.SendEmail(ctx, []string{recipient}, nil, nil, nil, subject, body, nil)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "case notifications: digest failed; will retry",
+				"recipient", recipient, "events", len(h.events), "err", err)
+			continue
+		}
+		delete(d.held, recipient)
+	}
+}
+
+// link is e's portal URL: its own Link, or else the case page.
+func (d *Dispatcher) link(e Event) string {
+	if e.Link != "" || d.portalBaseURL == "" {
+		return e.Link
+	}
+	return d.portalBaseURL + "/cases/" + url.PathEscape(e.CaseID)
+}
+
+// flushInterval is how often Run checks for due digests. Hourly and daily
+// digests go out within this long of their boundary.
+const flushInterval = time.Minute
+
+// Run sends due digests every flushInterval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.FlushDue(ctx)
+		}
+	}
+}
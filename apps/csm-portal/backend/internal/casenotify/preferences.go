@@ -0,0 +1,315 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package casenotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/filestore"
+)
+
+// Channel is a way a user can be notified.
+type Channel string
+
+// ChannelEmail is the only channel today. Google Chat spaces are per product,
+// not per user, so they are not a personal channel.
+const ChannelEmail Channel = "email"
+
+var validChannels = map[Channel]bool{ChannelEmail: true}
+
+// EventType is a kind of case activity a user can be notified about.
+type EventType string
+
+const (
+	// EventComment is a new customer-visible comment on the case.
+	EventComment EventType = "comment"
+	// EventStateChange is the case moving to another state.
+	EventStateChange EventType = "state_change"
+	// EventAssignment is the case being assigned to another engineer.
+	EventAssignment EventType = "assignment"
+	// EventMention is the user being @mentioned in a comment on a case,
+	// incident or change request.
+	EventMention EventType = "mention"
+)
+
+var validEventTypes = map[EventType]bool{EventComment: true, EventStateChange: true, EventAssignment: true, EventMention: true}
+
+// DigestFrequency is how often a user's notifications are sent.
+type DigestFrequency string
+
+const (
+	// DigestNone sends each notification as it happens.
+	DigestNone DigestFrequency = "none"
+	// DigestHourly batches notifications into one email at the top of each
+	// hour.
+	DigestHourly DigestFrequency = "hourly"
+	// DigestDaily batches notifications into one email a day, at DigestTime.
+	DigestDaily DigestFrequency = "daily"
+)
+
+var validDigestFrequencies = map[DigestFrequency]bool{DigestNone: true, DigestHourly: true, DigestDaily: true}
+
+// defaultDigestTime is when a daily digest is sent if DigestTime is unset.
+const defaultDigestTime = "08:00"
+
+// QuietHours is a daily window, in the user's time zone, in which nothing is
+// sent. Notifications due inside it are held and sent as one digest when it
+// ends. End before Start wraps past midnight.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Preferences is one user's notification settings.
+type Preferences struct {
+	// Channels are where notifications go; empty turns them all off.
+	Channels []Channel `json:"channels"`
+	// Events are the event types the user is notified about.
+	Events []EventType `json:"events"`
+	// TimeZone is an IANA zone name that QuietHours and DigestTime are read
+	// in. Empty is UTC.
+	TimeZone        string          `json:"timeZone,omitempty"`
+	QuietHours      *QuietHours     `json:"quietHours,omitempty"`
+	DigestFrequency DigestFrequency `json:"digestFrequency"`
+	// DigestTime is the "HH:MM" a daily digest is sent at; only meaningful
+	// with DigestDaily.
+	DigestTime string `json:"digestTime,omitempty"`
+}
+
+// Default is the preferences of a user who has never saved any: every event,
+// by email, as it happens.
+func Default() Preferences {
+	return Preferences{
+		Channels:        []Channel{ChannelEmail},
+		Events:          []EventType{EventComment, EventStateChange, EventAssignment, EventMention},
+		DigestFrequency: DigestNone,
+	}
+}
+
+// Validate reports the first problem with p, phrased for the user who sent it.
+func (p Preferences) Validate() error {
+	for _, c := range p.Channels {
+		if !validChannels[c] {
+			return fmt.Errorf("unknown channel %q", c)
+		}
+	}
+	for _, e := range p.Events {
+		if !validEventTypes[e] {
+			return fmt.Errorf("unknown event type %q", e)
+		}
+	}
+	if !validDigestFrequencies[p.DigestFrequency] {
+		return fmt.Errorf("digestFrequency must be one of %q, %q or %q", DigestNone, DigestHourly, DigestDaily)
+	}
+	if _, err := time.LoadLocation(p.TimeZone); err != nil {
+		return fmt.Errorf("unknown timeZone %q", p.TimeZone)
+	}
+	if p.DigestTime != "" {
+		if p.DigestFrequency != DigestDaily {
+			return errors.New("digestTime only applies to a daily digest")
+		}
+		if _, ok := parseClock(p.DigestTime); !ok {
+			return fmt.Errorf("digestTime %q is not a 24-hour HH:MM time", p.DigestTime)
+		}
+	}
+	if q := p.QuietHours; q != nil {
+		start, okStart := parseClock(q.Start)
+		end, okEnd := parseClock(q.End)
+		if !okStart || !okEnd {
+			return errors.New("quietHours start and end must be 24-hour HH:MM times")
+		}
+		if start == end {
+			return errors.New("quietHours start and end must differ")
+		}
+	}
+	return nil
+}
+
+// parseClock parses a 24-hour "HH:MM" into minutes past midnight.
+func parseClock(s string) (int, bool) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func (p Preferences) location() *time.Location {
+	loc, err := time.LoadLocation(p.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// wants reports whether p asks for e by email.
+func (p Preferences) wants(e EventType) bool {
+	return slices.Contains(p.Channels, ChannelEmail) && slices.Contains(p.Events, e)
+}
+
+// quiet reports whether now is inside p's quiet hours.
+func (p Preferences) quiet(now time.Time) bool {
+	if p.QuietHours == nil {
+		return false
+	}
+	start, _ := parseClock(p.QuietHours.Start)
+	end, _ := parseClock(p.QuietHours.End)
+	local := now.In(p.location())
+	m := local.Hour()*60 + local.Minute()
+	if start < end {
+		return m >= start && m < end
+	}
+	return m >= start || m < end
+}
+
+// digestDue reports whether notifications held since `since` are due at now:
+// immediately for DigestNone (they were held only by quiet hours or a failed
+// send), or once a digest boundary has passed since the first was held.
+// Quiet hours hold everything.
+func (p Preferences) digestDue(since, now time.Time) bool {
+	if p.quiet(now) {
+		return false
+	}
+	switch p.DigestFrequency {
+	case DigestHourly:
+		return now.Truncate(time.Hour).After(since)
+	case DigestDaily:
+		clock := p.DigestTime
+		if clock == "" {
+			clock = defaultDigestTime
+		}
+		m, _ := parseClock(clock)
+		local := now.In(p.location())
+		boundary := time.Date(local.Year(), local.Month(), local.Day(), m/60, m%60, 0, 0, local.Location())
+		if boundary.After(now) {
+			boundary = boundary.AddDate(0, 0, -1)
+		}
+		return boundary.After(since)
+	default:
+		return true
+	}
+}
+
+// Store holds every user's saved preferences, keyed by lowercased email. With
+// a path it is persisted to that JSON file on every change; without one it is
+// in memory only and lost on restart.
+type Store struct {
+	path string
+
+	mu    sync.RWMutex
+	prefs map[string]Preferences
+}
+
+// OpenStore loads the store from path. A missing file is an empty store; a
+// malformed one, or one holding invalid preferences, is an error. An empty
+// path is an in-memory store.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, prefs: make(map[string]Preferences)}
+	if path == "" {
+		return s, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read notification preferences: %w", err)
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return s, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s.prefs); err != nil {
+		return nil, fmt.Errorf("parse notification preferences %s: %w", path, err)
+	}
+	for email, p := range s.prefs {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("notification preferences %s: %s: %w", path, email, err)
+		}
+	}
+	return s, nil
+}
+
+func storeKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Get returns email's saved preferences, or Default and false if there are
+// none.
+func (s *Store) Get(email string) (Preferences, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.prefs[storeKey(email)]
+	if !ok {
+		return Default(), false
+	}
+	return p, true
+}
+
+// Put validates and saves email's preferences, returning them as saved. If
+// persisting fails the previous preferences are kept and the error returned.
+func (s *Store) Put(email string, p Preferences) (Preferences, error) {
+	if err := p.Validate(); err != nil {
+		return Preferences{}, err
+	}
+	// Empty, not null, in the JSON: "no channels" is a setting, not an
+	// omission.
+	if p.Channels == nil {
+		p.Channels = []Channel{}
+	}
+	if p.Events == nil {
+		p.Events = []EventType{}
+	}
+	key := storeKey(email)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, had := s.prefs[key]
+	s.prefs[key] = p
+	if err := s.persist(); err != nil {
+		if had {
+			s.prefs[key] = prev
+		} else {
+			delete(s.prefs, key)
+		}
+		return Preferences{}, err
+	}
+	return p, nil
+}
+
+// persist saves every user's preferences to path. Callers hold mu.
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+	raw, err := json.MarshalIndent(s.prefs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := filestore.WriteFile(s.path, raw); err != nil {
+		return fmt.Errorf("persist notification preferences: %w", err)
+	}
+	return nil
+}
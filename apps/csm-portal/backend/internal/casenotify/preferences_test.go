@@ -0,0 +1,114 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package casenotify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuietHours(t *testing.T) {
+	// 22:00-07:00 in Colombo (UTC+05:30) wraps midnight.
+	p := Preferences{TimeZone: "Asia/Colombo", QuietHours: &QuietHours{Start: "22:00", End: "07:00"}, DigestFrequency: DigestNone}
+	cases := []struct {
+		utc  string
+		want bool
+	}{
+		{"2026-03-02T16:29:00Z", false}, // 21:59 local
+		{"2026-03-02T16:30:00Z", true},  // 22:00 local
+		{"2026-03-02T20:00:00Z", true},  // 01:30 local
+		{"2026-03-03T01:29:00Z", true},  // 06:59 local
+		{"2026-03-03T01:30:00Z", false}, // 07:00 local
+	}
+	for _, tc := range cases {
+		now, _ := time.Parse(time.RFC3339, tc.utc)
+		if got := p.quiet(now); got != tc.want {
+			t.Errorf("quiet(%s) = %v, want %v", tc.utc, got, tc.want)
+		}
+	}
+}
+
+func TestDigestDue(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	hourly := Preferences{DigestFrequency: DigestHourly}
+	daily := Preferences{DigestFrequency: DigestDaily, DigestTime: "09:00", TimeZone: "Asia/Colombo"} // 03:30 UTC
+	quietDaily := Preferences{DigestFrequency: DigestDaily, QuietHours: &QuietHours{Start: "07:00", End: "10:00"}}
+
+	cases := []struct {
+		name       string
+		p          Preferences
+		since, now string
+		want       bool
+	}{
+		{"hourly, same hour", hourly, "2026-03-02T10:05:00Z", "2026-03-02T10:59:00Z", false},
+		{"hourly, next hour", hourly, "2026-03-02T10:05:00Z", "2026-03-02T11:00:00Z", true},
+		{"daily, before the time", daily, "2026-03-02T04:00:00Z", "2026-03-03T03:29:00Z", false},
+		{"daily, at the time", daily, "2026-03-02T04:00:00Z", "2026-03-03T03:30:00Z", true},
+		{"daily at 08:00 falls in quiet hours", quietDaily, "2026-03-01T12:00:00Z", "2026-03-02T09:59:00Z", false},
+		{"and is sent when they end", quietDaily, "2026-03-01T12:00:00Z", "2026-03-02T10:00:00Z", true},
+	}
+	for _, tc := range cases {
+		if got := tc.p.digestDue(at(tc.since), at(tc.now)); got != tc.want {
+			t.Errorf("%s: digestDue = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestStore_PersistsAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore(missing file): %v", err)
+	}
+	if _, ok := s.Get("jane@example.com"); ok {
+		t.Fatal("empty store reported saved preferences")
+	}
+
+	want := Preferences{Channels: []Channel{ChannelEmail}, Events: []EventType{EventComment}, DigestFrequency: DigestHourly}
+	if _, err := s.Put("Jane@Example.com", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := s.Put("jane@example.com", Preferences{DigestFrequency: "weekly"}); err == nil {
+		t.Fatal("Put accepted an invalid frequency")
+	}
+
+	reopened, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, ok := reopened.Get(" jane@example.com ")
+	if !ok || got.DigestFrequency != DigestHourly || len(got.Events) != 1 {
+		t.Fatalf("reloaded = %+v, %v", got, ok)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"jane@example.com":{"digestFrequency":"weekly"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenStore(path); err == nil || !strings.Contains(err.Error(), "jane@example.com") {
+		t.Fatalf("OpenStore(invalid entry) error = %v, want one naming the user", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package casenotify
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// maxSummaryRunes truncates a comment in an email: the email says there is
+// something to read, the portal is where it is read.
+const maxSummaryRunes = 500
+
+// Templates use inline styles only: most mail clients drop <style> blocks.
+var (
+	eventTemplate = template.Must(template.New("event").Parse(`<!DOCTYPE html>
+<html><body style="font-family:Arial,Helvetica,sans-serif;color:#222;">
+<h2 style="margin-bottom:4px;">{{.Case}}</h2>
+{{template "item" .Item}}
+{{if .CaseURL}}<p><a href="{{.CaseURL}}">Open in CSM Portal</a></p>{{end}}
+<p style="color:#999;font-size:11px;">{{if .Mention}}You are receiving this because you were mentioned.{{else}}You are receiving this because you are assigned to or watching this case.{{end}} Change what you receive in your CSM Portal notification preferences.</p>
+</body></html>`))
+
+	digestTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html><body style="font-family:Arial,Helvetica,sans-serif;color:#222;">
+<h2>{{.Title}}</h2>
+{{range .Cases}}
+<h3 style="border-bottom:1px solid #ddd;padding-bottom:4px;">{{if .CaseURL}}<a href="{{.CaseURL}}">{{.Case}}</a>{{else}}{{.Case}}{{end}}</h3>
+{{range .Items}}{{template "item" .}}{{end}}
+{{end}}
+<p style="color:#999;font-size:11px;">Change what you receive, and how often, in your CSM Portal notification preferences.</p>
+</body></html>`))
+
+	itemTemplate = `{{define "item"}}<p style="margin-bottom:2px;"><strong>{{.Headline}}</strong> <span style="color:#666;font-size:12px;">{{.At}}</span></p>
+{{if .Quote}}<blockquote style="margin:4px 0 12px 0;padding-left:8px;border-left:3px solid #ddd;color:#444;white-space:pre-wrap;">{{.Quote}}</blockquote>{{end}}{{end}}`
+)
+
+func init() {
+	template.Must(eventTemplate.Parse(itemTemplate))
+	template.Must(digestTemplate.Parse(itemTemplate))
+}
+
+type itemView struct {
+	Headline string
+	At       string
+	Quote    string
+}
+
+type caseView struct {
+	Case    string
+	CaseURL string
+	Items   []itemView
+}
+
+// caseLabel is how a case is named in an email: "CS0012345 — Subject".
+func caseLabel(e Event) string {
+	label := e.CaseNumber
+	if label == "" {
+		label = e.CaseID
+	}
+	if e.Subject != "" {
+		label += " — " + e.Subject
+	}
+	return label
+}
+
+// headline says what happened. A change picked up by polling has no known
+// actor, so it is told in the passive.
+func headline(e Event) string {
+	switch e.Type {
+	case EventComment:
+		if e.Actor == "" {
+			return "New comment"
+		}
+		return e.Actor + " commented"
+	case EventStateChange:
+		return "Moved to " + e.Summary
+	case EventAssignment:
+		if e.Summary == "" {
+			return "Unassigned"
+		}
+		return "Assigned to " + e.Summary
+	case EventMention:
+		if e.Actor == "" {
+			return "You were mentioned"
+		}
+		return e.Actor + " mentioned you"
+	}
+	return "Updated"
+}
+
+func item(e Event) itemView {
+	v := itemView{Headline: headline(e), At: e.At.UTC().Format("2006-01-02 15:04 MST")}
+	if e.Type == EventComment || e.Type == EventMention {
+		v.Quote = truncate(e.Summary, maxSummaryRunes)
+	}
+	return v
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
+
+// renderEvent renders the email for a single event.
+func renderEvent(e Event, caseURL string) (subject, body string, err error) {
+	var buf bytes.Buffer
+	err = eventTemplate.Execute(&buf, struct {
+		Case    string
+		CaseURL string
+		Item    itemView
+		Mention bool
+	}{caseLabel(e), caseURL, item(e), e.Type == EventMention})
+	if err != nil {
+		return "", "", err
+	}
+	number := e.CaseNumber
+	if number == "" {
+		number = e.CaseID
+	}
+	return fmt.Sprintf("[%s] %s", number, headline(e)), buf.String(), nil
+}
+
+// renderDigest renders one email for events, grouped by case in the order
+// each case first appears.
+func renderDigest(events []Event, link func(Event) string) (subject, body string, err error) {
+	var cases []*caseView
+	byID := make(map[string]*caseView)
+	for _, e := range events {
+		c := byID[e.CaseID]
+		if c == nil {
+			c = &caseView{Case: caseLabel(e), CaseURL: link(e)}
+			byID[e.CaseID] = c
+			cases = append(cases, c)
+		}
+		c.Items = append(c.Items, item(e))
+	}
+
+	title := fmt.Sprintf("%d update%s on %d case%s", len(events), plural(len(events)), len(cases), plural(len(cases)))
+	var buf bytes.Buffer
+	err = digestTemplate.Execute(&buf, struct {
+		Title string
+		Cases []*caseView
+	}{title, cases})
+	if err != nil {
+		return "", "", err
+	}
+	return "CSM Portal: " + title, buf.String(), nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,373 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package casenotify emails engineers about activity on the cases they are
+// assigned to or watching -- new comments, state changes and reassignment --
+// each as it happens or batched into a digest, per their Preferences.
+//
+// The Watcher polls the entity service for recently updated cases rather
+// than hooking the portal's own handlers: the comments engineers most need to
+// hear about are written by customers in the customer portal or by email,
+// which this service never sees.
+//
+// A state change or reassignment is detected by comparing a case with how it
+// looked the last time it was polled, so the first update to a case after a
+// restart only records how it looks. Held digests are in memory too, and a
+// restart loses them. Every replica running the watcher sends its own copy;
+// run it on one.
+package casenotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchTimeout bounds each entity call a poll makes.
+const searchTimeout = 30 * time.Second
+
+// caseSearchLimit caps how many updated cases one poll reads. More than that
+// is logged; at a sensible interval it does not happen.
+const caseSearchLimit = 100
+
+// commentSearchLimit is how many of a case's comments are read per poll.
+const commentSearchLimit = 50
+
+// updatedLookback widens each updated-since search past the previous poll, so
+// an update whose updatedOn lags its visibility in search is still seen. The
+// repeats it causes are dropped: comments by id, changes by the snapshot.
+const updatedLookback = 2 * time.Minute
+
+// maxCaseAttempts is how many polls in a row a case that cannot be read is
+// tried on before the activity it missed is given up on.
+const maxCaseAttempts = 5
+
+// snapshotRetention is how long a case's snapshot is kept after it was last
+// seen updated. A case quiet for longer is treated as first seen again.
+const snapshotRetention = 30 * 24 * time.Hour
+
+// entityClient abstracts the entity service calls the Watcher makes.
+type entityClient interface {
+	SearchCases(ctx context.Context, body []byte) ([]byte, error)
+	GetCase(ctx context.Context, id string) ([]byte, error)
+	SearchCaseComments(ctx context.Context, caseID string, body []byte) ([]byte, error)
+}
+
+// snapshot is what a case looked like when last polled.
+type snapshot struct {
+	state    string
+	assignee string
+	seen     time.Time
+}
+
+// retryCase is a case whose poll failed. It is read again on later polls,
+// whether or not it is updated again, from the cutoff it first failed at.
+type retryCase struct {
+	cutoff   time.Time
+	attempts int
+}
+
+// Watcher polls for case activity and hands it to a Dispatcher.
+type Watcher struct {
+	entity   entityClient
+	dispatch *Dispatcher
+	interval time.Duration
+	now      func() time.Time
+
+	mu sync.Mutex
+	// since is the updated-case watermark. It advances whenever the
+	// updated-case search succeeds; a case that then fails to read goes into
+	// retry rather than holding everyone else's activity back.
+	since        time.Time
+	retry        map[string]retryCase
+	cases        map[string]snapshot
+	seenComments map[string]time.Time
+}
+
+// NewWatcher creates a Watcher polling every interval.
+func NewWatcher(entity entityClient, dispatch *Dispatcher, interval time.Duration) *Watcher {
+	return &Watcher{
+		entity:       entity,
+		dispatch:     dispatch,
+		interval:     interval,
+		now:          time.Now,
+		retry:        make(map[string]retryCase),
+		cases:        make(map[string]snapshot),
+		seenComments: make(map[string]time.Time),
+	}
+}
+
+// Run polls once immediately, to set the watermark, and then every interval
+// until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.PollOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollOnce dispatches the activity on every case updated since the previous
+// poll. The first poll only sets the watermark.
+func (w *Watcher) PollOnce(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	if w.since.IsZero() {
+		w.since = now
+		return
+	}
+
+	cutoff := w.since.Add(-updatedLookback)
+	if err := w.poll(ctx, cutoff, now); err != nil {
+		slog.ErrorContext(ctx, "case notifications: updated-case search failed", "err", err)
+	} else {
+		w.since = now
+	}
+
+	// A comment older than every cutoff the next poll reads from can no
+	// longer be re-read.
+	horizon := w.since.Add(-updatedLookback)
+	for _, r := range w.retry {
+		if r.cutoff.Before(horizon) {
+			horizon = r.cutoff
+		}
+	}
+	for id, at := range w.seenComments {
+		if at.Before(horizon) {
+			delete(w.seenComments, id)
+		}
+	}
+	for id, s := range w.cases {
+		if now.Sub(s.seen) > snapshotRetention {
+			delete(w.cases, id)
+		}
+	}
+}
+
+// poll handles every case updated at or after cutoff, and every case still
+// to be retried. A case that fails is recorded in w.retry and read again on
+// the following polls, so one unreadable case neither holds up everyone
+// else's notifications nor has theirs sent twice. The only error returned is
+// the updated-case search's.
+func (w *Watcher) poll(ctx context.Context, cutoff, now time.Time) error {
+	ids, err := w.updatedCases(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	for id := range w.retry {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		r, retrying := w.retry[id]
+		if !retrying || cutoff.Before(r.cutoff) {
+			r.cutoff = cutoff
+		}
+		err := w.pollCase(ctx, id, r.cutoff, now)
+		if err == nil {
+			delete(w.retry, id)
+			continue
+		}
+		r.attempts++
+		attrs := []any{"caseId", id, "attempts", r.attempts, "err", err}
+		if r.attempts >= maxCaseAttempts {
+			slog.ErrorContext(ctx, "case notifications: giving up on case", attrs...)
+			delete(w.retry, id)
+			continue
+		}
+		slog.WarnContext(ctx, "case notifications: case poll failed; will retry", attrs...)
+		w.retry[id] = r
+	}
+	return nil
+}
+
+func (w *Watcher) updatedCases(ctx context.Context, cutoff time.Time) ([]string, error) {
+	body, err := json.Marshal(map[string]any{
+		"filters": map[string]any{"filters": []map[string]any{
+			{"field": "updatedOn", "op": "gte", "values": []string{cutoff.UTC().Format(time.RFC3339)}},
+		}},
+		"sortBy":     map[string]string{"field": "updatedOn", "order": "desc"},
+		"pagination": map[string]int{"offset": 0, "limit": caseSearchLimit},
+	})
+	if err != nil {
+		return nil, err
+	}
+	searchCtx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+	raw, err := w.entity.SearchCases(searchCtx, body)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Cases []struct {
+			ID string `json:"id"`
+		} `json:"cases"`
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("decode case search: %w", err)
+	}
+	if resp.Total > len(resp.Cases) {
+		slog.WarnContext(ctx, "case notifications: more cases updated than one poll reads; the rest are skipped",
+			"total", resp.Total, "limit", caseSearchLimit)
+	}
+	ids := make([]string, 0, len(resp.Cases))
+	for _, c := range resp.Cases {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+// userRef is a user reference on a case or comment.
+type userRef struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// caseDetail is the subset of GET /cases/{id} the watcher reads.
+type caseDetail struct {
+	Number           string    `json:"number"`
+	Subject          string    `json:"subject"`
+	State            string    `json:"state"`
+	AssignedEngineer *userRef  `json:"assignedEngineer"`
+	WatchList        []userRef `json:"watchList"`
+}
+
+func (c caseDetail) assignee() userRef {
+	if c.AssignedEngineer == nil {
+		return userRef{}
+	}
+	return *c.AssignedEngineer
+}
+
+func (c caseDetail) recipients() []string {
+	out := []string{c.assignee().Email}
+	for _, u := range c.WatchList {
+		out = append(out, u.Email)
+	}
+	return out
+}
+
+func (w *Watcher) pollCase(ctx context.Context, id string, cutoff, now time.Time) error {
+	getCtx, cancel := context.WithTimeout(ctx, searchTimeout)
+	raw, err := w.entity.GetCase(getCtx, id)
+	cancel()
+	if err != nil {
+		return err
+	}
+	var c caseDetail
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return fmt.Errorf("decode case: %w", err)
+	}
+
+	base := Event{CaseID: id, CaseNumber: c.Number, Subject: c.Subject, At: now, Recipients: c.recipients()}
+	assignee := c.assignee()
+	if prev, ok := w.cases[id]; ok {
+		if c.State != prev.state {
+			e := base
+			e.Type, e.Summary = EventStateChange, humanize(c.State)
+			w.dispatch.Dispatch(ctx, e)
+		}
+		if !strings.EqualFold(assignee.Email, prev.assignee) {
+			e := base
+			e.Type, e.Summary = EventAssignment, assignee.Name
+			if e.Summary == "" {
+				e.Summary = assignee.Email
+			}
+			// The engineer the case was taken from hears about it too.
+			e.Recipients = append(e.Recipients, prev.assignee)
+			w.dispatch.Dispatch(ctx, e)
+		}
+	}
+	w.cases[id] = snapshot{state: c.State, assignee: assignee.Email, seen: now}
+
+	return w.pollComments(ctx, base, cutoff)
+}
+
+// pollComments dispatches every customer-visible comment on the case created
+// at or after cutoff and not already dispatched. Work notes are internal and
+// are not notified.
+func (w *Watcher) pollComments(ctx context.Context, base Event, cutoff time.Time) error {
+	body, err := json.Marshal(map[string]any{
+		"filters":    map[string]string{"type": "comment"},
+		"sortBy":     map[string]string{"field": "createdOn", "order": "desc"},
+		"pagination": map[string]int{"offset": 0, "limit": commentSearchLimit},
+	})
+	if err != nil {
+		return err
+	}
+	searchCtx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+	raw, err := w.entity.SearchCaseComments(searchCtx, base.CaseID, body)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Comments []struct {
+			ID        string    `json:"id"`
+			Content   string    `json:"content"`
+			CreatedOn time.Time `json:"createdOn"`
+			CreatedBy *userRef  `json:"createdBy"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("decode comments: %w", err)
+	}
+	// The page is the newest comments, but every one on it is checked rather
+	// than stopping at the first old one: ties and a data source that ignores
+	// the sort cost nothing that way.
+	for _, cm := range resp.Comments {
+		if cm.CreatedOn.Before(cutoff) {
+			continue
+		}
+		if _, seen := w.seenComments[cm.ID]; seen {
+			continue
+		}
+		w.seenComments[cm.ID] = cm.CreatedOn
+		e := base
+		e.Type, e.Summary, e.At = EventComment, cm.Content, cm.CreatedOn
+		if cm.CreatedBy != nil {
+			e.Actor, e.ActorEmail = cm.CreatedBy.Name, cm.CreatedBy.Email
+		}
+		w.dispatch.Dispatch(ctx, e)
+	}
+	return nil
+}
+
+// humanize turns a state value into its label: "work_in_progress" becomes
+// "Work In Progress".
+func humanize(state string) string {
+	words := strings.Split(state, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package casenotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// fakeEntity serves one case, updated whenever updated is set.
+type fakeEntity struct {
+	updated  bool
+	caseJSON string
+	comments []map[string]any
+	getErr   error
+}
+
+func (f *fakeEntity) SearchCases(_ context.Context, body []byte) ([]byte, error) {
+	if !strings.Contains(string(body), `"updatedOn"`) {
+		return nil, errors.New("expected an updatedOn search")
+	}
+	if !f.updated {
+		return []byte(`{"cases":[],"total":0}`), nil
+	}
+	return []byte(`{"cases":[{"id":"c1"}],"total":1}`), nil
+}
+
+func (f *fakeEntity) GetCase(_ context.Context, id string) ([]byte, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	return []byte(f.caseJSON), nil
+}
+
+func (f *fakeEntity) SearchCaseComments(_ context.Context, _ string, _ []byte) ([]byte, error) {
+	return json.Marshal(map[string]any{"comments": f.comments, "total": len(f.comments)})
+}
+
+type sentEmail struct {
+	to      string
+	subject string
+	body    string
+}
+
+type fakeEmail struct {
+	sent []sentEmail
+	err  error
+}
+
+func (f *fakeEmail) SendEmail(_ context.Context, to, _, _, _ []string, subject, body string, _ []notifications.EmailAttachment) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, sentEmail{to[0], subject, body})
+	return nil
+}
+
+func (f *fakeEmail) subjects(to string) []string {
+	var out []string
+	for _, e := range f.sent {
+		if e.to == to {
+			out = append(out, e.subject)
+		}
+	}
+	return out
+}
+
+func caseJSON(state, assigneeEmail, assigneeName string) string {
+	return `{"number":"CS0012345","subject":"Gateway down","state":"` + state + `",
+		"assignedEngineer":{"email":"` + assigneeEmail + `","name":"` + assigneeName + `"},
+		"watchList":[{"email":"lead@example.com","name":"Lead"},{"userName":"cs-group"}]}`
+}
+
+func newTestWatcher(t *testing.T, entity *fakeEntity, email *fakeEmail) (*Watcher, *Store, *time.Time) {
+	t.Helper()
+	store, err := OpenStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	d := NewDispatcher(store, email, "https://portal.example/")
+	d.now = clock
+	w := NewWatcher(entity, d, time.Minute)
+	w.now = clock
+	return w, store, &now
+}
+
+func comment(id, content, authorEmail string, at time.Time) map[string]any {
+	return map[string]any{"id": id, "content": content, "createdOn": at.Format(time.RFC3339),
+		"createdBy": map[string]any{"email": authorEmail, "name": "Author"}}
+}
+
+func TestWatcher_CommentsStateAndAssignment(t *testing.T) {
+	entity := &fakeEntity{caseJSON: caseJSON("work_in_progress", "jane@example.com", "Jane Doe")}
+	email := &fakeEmail{}
+	w, _, now := newTestWatcher(t, entity, email)
+	w.PollOnce(context.Background()) // sets the watermark
+
+	// First sighting of the case: a customer comment is sent to the assignee
+	// and watcher, but the state and assignee are only recorded.
+	*now = now.Add(time.Minute)
+	entity.updated = true
+	entity.comments = []map[string]any{
+		comment("m1", "Still failing <after> the patch", "customer@acme.example", now.Add(-30*time.Second)),
+		comment("m0", "Old comment", "customer@acme.example", now.Add(-time.Hour)),
+	}
+	w.PollOnce(context.Background())
+	for _, to := range []string{"jane@example.com", "lead@example.com"} {
+		if got := email.subjects(to); !slices.Equal(got, []string{"[CS0012345] Author commented"}) {
+			t.Errorf("%s got %v", to, got)
+		}
+	}
+	if !strings.Contains(email.sent[0].body, "Still failing &lt;after&gt; the patch") ||
+		!strings.Contains(email.sent[0].body, `href="https://portal.example/cases/c1"`) {
+		t.Errorf("email body does not escape the comment or link the case:\n%s", email.sent[0].body)
+	}
+
+	// The lookback re-reads m1; it is not sent twice. A reassignment and a
+	// state change are.
+	email.sent = nil
+	*now = now.Add(time.Minute)
+	entity.caseJSON = caseJSON("solution_proposed", "john@example.com", "John Roe")
+	w.PollOnce(context.Background())
+	if got := email.subjects("jane@example.com"); !slices.Equal(got, []string{"[CS0012345] Assigned to John Roe"}) {
+		t.Errorf("previous assignee got %v", got)
+	}
+	if got := email.subjects("john@example.com"); !slices.Equal(got, []string{"[CS0012345] Moved to Solution Proposed", "[CS0012345] Assigned to John Roe"}) {
+		t.Errorf("new assignee got %v", got)
+	}
+
+	// Nobody is told about their own comment.
+	email.sent = nil
+	*now = now.Add(time.Minute)
+	entity.comments = []map[string]any{comment("m2", "Proposed a fix", "john@example.com", now.Add(-10*time.Second))}
+	w.PollOnce(context.Background())
+	if got := email.subjects("john@example.com"); len(got) != 0 {
+		t.Errorf("author was sent %v", got)
+	}
+	if got := email.subjects("lead@example.com"); len(got) != 1 {
+		t.Errorf("watcher got %v, want the comment", got)
+	}
+}
+
+func TestWatcher_DigestAndPreferences(t *testing.T) {
+	entity := &fakeEntity{caseJSON: caseJSON("work_in_progress", "jane@example.com", "Jane Doe"), updated: true}
+	email := &fakeEmail{}
+	w, store, now := newTestWatcher(t, entity, email)
+	mustPut := func(addr string, p Preferences) {
+		t.Helper()
+		if _, err := store.Put(addr, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustPut("jane@example.com", Preferences{Channels: []Channel{ChannelEmail}, Events: []EventType{EventComment}, DigestFrequency: DigestHourly})
+	mustPut("lead@example.com", Preferences{Channels: []Channel{}, Events: []EventType{EventComment}, DigestFrequency: DigestNone})
+	w.PollOnce(context.Background())
+
+	for i, id := range []string{"m1", "m2"} {
+		*now = now.Add(time.Minute)
+		entity.comments = append(entity.comments, comment(id, "update "+id, "customer@acme.example", now.Add(-time.Duration(i+1)*time.Second)))
+		w.PollOnce(context.Background())
+	}
+	if len(email.sent) != 0 {
+		t.Fatalf("sent before the digest was due: %v", email.sent)
+	}
+
+	*now = time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC)
+	w.dispatch.FlushDue(context.Background())
+	if len(email.sent) != 1 || email.sent[0].to != "jane@example.com" || email.sent[0].subject != "CSM Portal: 2 updates on 1 case" {
+		t.Fatalf("sent %v, want one digest to jane", email.sent)
+	}
+	if !strings.Contains(email.sent[0].body, "update m1") || !strings.Contains(email.sent[0].body, "update m2") {
+		t.Errorf("digest is missing a comment:\n%s", email.sent[0].body)
+	}
+}
+
+func TestWatcher_FailuresAreRetried(t *testing.T) {
+	entity := &fakeEntity{caseJSON: caseJSON("work_in_progress", "jane@example.com", "Jane Doe"), updated: true}
+	email := &fakeEmail{}
+	w, _, now := newTestWatcher(t, entity, email)
+	w.PollOnce(context.Background())
+	seededAt := *now
+
+	// An unreadable case does not hold the watermark; it is retried on its
+	// own, from where it first failed, even though it is not updated again.
+	*now = now.Add(time.Minute)
+	entity.getErr = errors.New("entity service down")
+	w.PollOnce(context.Background())
+	if !w.since.Equal(*now) {
+		t.Fatalf("watermark held at %v by a failed case", w.since)
+	}
+	if r, ok := w.retry["c1"]; !ok || !r.cutoff.Equal(seededAt.Add(-updatedLookback)) {
+		t.Fatalf("retry = %+v, want c1 from the seeded cutoff", w.retry)
+	}
+
+	// A failed email is held and goes out, as a digest, on the next flush. The
+	// comment predates the regular cutoff, so only the retry finds it.
+	entity.getErr, entity.updated = nil, false
+	email.err = errors.New("mail service down")
+	entity.comments = []map[string]any{comment("m1", "hello", "customer@acme.example", seededAt.Add(-90*time.Second))}
+	*now = now.Add(time.Minute)
+	w.PollOnce(context.Background())
+	if len(w.retry) != 0 {
+		t.Fatalf("retry = %+v after the case was read", w.retry)
+	}
+	email.err = nil
+	*now = now.Add(time.Minute)
+	w.dispatch.FlushDue(context.Background())
+	if got := email.subjects("jane@example.com"); !slices.Equal(got, []string{"CSM Portal: 1 update on 1 case"}) {
+		t.Fatalf("jane got %v, want the held comment", got)
+	}
+
+	// A case that keeps failing is given up on after maxCaseAttempts.
+	entity.getErr, entity.updated = errors.New("malformed case"), true
+	*now = now.Add(time.Minute)
+	w.PollOnce(context.Background())
+	entity.updated = false
+	for range maxCaseAttempts - 1 {
+		if _, ok := w.retry["c1"]; !ok {
+			t.Fatal("case given up on too early")
+		}
+		*now = now.Add(time.Minute)
+		w.PollOnce(context.Background())
+	}
+	if len(w.retry) != 0 {
+		t.Fatalf("retry = %+v after %d attempts", w.retry, maxCaseAttempts)
+	}
+}
+
+func TestWatcher_CommentSearchIsNewestFirst(t *testing.T) {
+	var body []byte
+	entity := &commentCapture{fakeEntity: &fakeEntity{caseJSON: caseJSON("open", "jane@example.com", "Jane Doe"), updated: true}, body: &body}
+	w, _, now := newTestWatcher(t, entity.fakeEntity, &fakeEmail{})
+	w.entity = entity
+	w.PollOnce(context.Background())
+	*now = now.Add(time.Minute)
+	w.PollOnce(context.Background())
+	if !strings.Contains(string(body), `"sortBy":{"field":"createdOn","order":"desc"}`) {
+		t.Fatalf("comment search %s is not sorted newest first", body)
+	}
+}
+
+// commentCapture records the last comment search body.
+type commentCapture struct {
+	*fakeEntity
+	body *[]byte
+}
+
+func (c *commentCapture) SearchCaseComments(ctx context.Context, id string, body []byte) ([]byte, error) {
+	*c.body = body
+	return c.fakeEntity.SearchCaseComments(ctx, id, body)
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package chatalerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kind is what happened to a record.
+type Kind string
+
+const (
+	// KindCreated is a case or incident that did not exist at the previous
+	// poll.
+	KindCreated Kind = "created"
+	// KindEscalated is a case whose escalation level, or an active incident
+	// whose priority, rose since the previous poll.
+	KindEscalated Kind = "escalated"
+)
+
+var validKinds = map[Kind]bool{KindCreated: true, KindEscalated: true}
+
+// RecordIncident is the record type of an incident. Every other record type
+// is a case type ("case", "service_request", ...).
+const RecordIncident = "incident"
+
+var validRecordTypes = map[string]bool{
+	"case": true, "service_request": true, "security_report_analysis": true,
+	"engagement": true, "announcement": true, RecordIncident: true,
+}
+
+// validSeverities is every case severity and incident priority, lowercased:
+// a rule's severities match either, depending on the record.
+var validSeverities = map[string]bool{
+	"catastrophic": true, "critical": true, "high": true, "medium": true, "low": true,
+	"moderate": true, "planning": true,
+}
+
+// maxEscalationLevel is EL5, the highest escalation level upstream has.
+const maxEscalationLevel = 5
+
+// Rule routes matching events to Google Chat spaces. Every criterion is
+// optional and an omitted one matches anything; within one criterion any
+// listed value matches. Values are compared case-insensitively.
+type Rule struct {
+	// Name identifies the rule in logs and errors.
+	Name string `json:"name"`
+	// Events are the Kinds the rule fires on; omitted means both.
+	Events []Kind `json:"events,omitempty"`
+	// Types are record types: case types ("case", "service_request",
+	// "security_report_analysis", "engagement", "announcement") or
+	// "incident".
+	Types []string `json:"types,omitempty"`
+	// Products are product names, matched against the case's product.
+	// Incidents carry no product, so a rule with Products never matches one.
+	Products []string `json:"products,omitempty"`
+	// Severities are case severities (catastrophic ... low) or incident
+	// priorities (critical, high, moderate, low, planning).
+	Severities []string `json:"severities,omitempty"`
+	// AccountTiers are support-tier labels (e.g. "Platinum"), matched against
+	// the case's account. Like Products, never matches an incident.
+	AccountTiers []string `json:"accountTiers,omitempty"`
+	// MinEscalationLevel only matches events at that escalation level (0-5)
+	// or above. A created event is at level 0.
+	MinEscalationLevel int `json:"minEscalationLevel,omitempty"`
+	// Spaces are the Google Chat spaces to post to, by their configured name
+	// (the "product" key of NOTIFICATIONS_GOOGLE_CHAT_SPACES). Required.
+	Spaces []string `json:"spaces"`
+}
+
+// ParseRules decodes and validates the routing rules from their JSON
+// configuration form, a JSON array of Rule objects. hasSpace reports whether
+// a space name is configured; a rule naming an unknown one is an error, since
+// every alert it matched would fail to send.
+//
+// An empty string yields no rules and no error. Any other problem is an error
+// naming the rule at fault, so a typo stops the deploy instead of silently
+// dropping alerts.
+func ParseRules(raw string, hasSpace func(string) bool) ([]Rule, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var rules []Rule
+	dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("chat alert rules: %w", err)
+	}
+
+	names := make(map[string]bool, len(rules))
+	for i := range rules {
+		r := &rules[i]
+		r.Name = strings.TrimSpace(r.Name)
+		if r.Name == "" {
+			return nil, fmt.Errorf("chat alert rules: rule %d has no name", i)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("chat alert rules: rule name %q is used more than once", r.Name)
+		}
+		names[r.Name] = true
+		if err := r.normalize(hasSpace); err != nil {
+			return nil, fmt.Errorf("chat alert rules: rule %q: %w", r.Name, err)
+		}
+	}
+	return rules, nil
+}
+
+// normalize lowercases every matched value and validates the rule.
+func (r *Rule) normalize(hasSpace func(string) bool) error {
+	for _, k := range r.Events {
+		if !validKinds[k] {
+			return fmt.Errorf("unknown event %q: expected %q or %q", k, KindCreated, KindEscalated)
+		}
+	}
+	r.Types = lowerAll(r.Types)
+	for _, t := range r.Types {
+		if !validRecordTypes[t] {
+			return fmt.Errorf("unknown type %q", t)
+		}
+	}
+	r.Severities = lowerAll(r.Severities)
+	for _, s := range r.Severities {
+		if !validSeverities[s] {
+			return fmt.Errorf("unknown severity %q", s)
+		}
+	}
+	r.Products = lowerAll(r.Products)
+	r.AccountTiers = lowerAll(r.AccountTiers)
+	if r.MinEscalationLevel < 0 || r.MinEscalationLevel > maxEscalationLevel {
+		return fmt.Errorf("minEscalationLevel %d is outside 0-%d", r.MinEscalationLevel, maxEscalationLevel)
+	}
+	if r.MinEscalationLevel > 0 && len(r.Events) > 0 && !slices.Contains(r.Events, KindEscalated) {
+		// A created event is always at level 0, so this rule could never fire.
+		return fmt.Errorf("minEscalationLevel %d can only match %q events", r.MinEscalationLevel, KindEscalated)
+	}
+	if len(r.Spaces) == 0 {
+		return fmt.Errorf("no spaces")
+	}
+	for _, s := range r.Spaces {
+		if !hasSpace(s) {
+			return fmt.Errorf("space %q is not configured in NOTIFICATIONS_GOOGLE_CHAT_SPACES", s)
+		}
+	}
+	return nil
+}
+
+func lowerAll(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// matchesAny reports whether value is in allowed, or allowed is empty.
+func matchesAny(allowed []string, value string) bool {
+	return len(allowed) == 0 || slices.Contains(allowed, strings.ToLower(strings.TrimSpace(value)))
+}
+
+// Matches reports whether e satisfies every criterion of the rule.
+func (r Rule) Matches(e Event) bool {
+	if len(r.Events) > 0 && !slices.Contains(r.Events, e.Kind) {
+		return false
+	}
+	return matchesAny(r.Types, e.RecordType) &&
+		matchesAny(r.Products, e.Product) &&
+		matchesAny(r.Severities, e.Severity) &&
+		matchesAny(r.AccountTiers, e.AccountTier) &&
+		e.EscalationLevel >= r.MinEscalationLevel
+}
+
+// route returns the spaces every rule matching e posts to, each once, in the
+// order the rules first name them.
+func route(rules []Rule, e Event) []string {
+	var spaces []string
+	for _, r := range rules {
+		if !r.Matches(e) {
+			continue
+		}
+		for _, s := range r.Spaces {
+			if !slices.ContainsFunc(spaces, func(o string) bool { return strings.EqualFold(o, s) }) {
+				spaces = append(spaces, s)
+			}
+		}
+	}
+	return spaces
+}
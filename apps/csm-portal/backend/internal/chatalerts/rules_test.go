@@ -0,0 +1,86 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package chatalerts
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func knownSpaces(names ...string) func(string) bool {
+	return func(s string) bool { return slices.Contains(names, s) }
+}
+
+func TestParseRules_Rejects(t *testing.T) {
+	cases := []struct {
+		name, raw, want string
+	}{
+		{"unknown key", `[{"name":"a","spaces":["apim"],"product":["APIM"]}]`, `unknown field "product"`},
+		{"no name", `[{"spaces":["apim"]}]`, "has no name"},
+		{"duplicate name", `[{"name":"a","spaces":["apim"]},{"name":"a","spaces":["iam"]}]`, "more than once"},
+		{"unknown event", `[{"name":"a","events":["updated"],"spaces":["apim"]}]`, `unknown event "updated"`},
+		{"unknown type", `[{"name":"a","types":["problem"],"spaces":["apim"]}]`, `unknown type "problem"`},
+		{"unknown severity", `[{"name":"a","severities":["S1"],"spaces":["apim"]}]`, `unknown severity "s1"`},
+		{"level out of range", `[{"name":"a","minEscalationLevel":6,"spaces":["apim"]}]`, "outside 0-5"},
+		{"level on created only", `[{"name":"a","events":["created"],"minEscalationLevel":2,"spaces":["apim"]}]`, "can only match"},
+		{"no spaces", `[{"name":"a"}]`, "no spaces"},
+		{"unknown space", `[{"name":"a","spaces":["mi"]}]`, `space "mi" is not configured`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseRules(tc.raw, knownSpaces("apim", "iam"))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("ParseRules error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+
+	if rules, err := ParseRules("  ", knownSpaces()); err != nil || rules != nil {
+		t.Errorf("ParseRules(blank) = %v, %v; want no rules and no error", rules, err)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	rules, err := ParseRules(`[
+		{"name":"apim-critical","types":["case"],"products":["API Manager"],"severities":["Catastrophic","critical"],"spaces":["apim"]},
+		{"name":"platinum","accountTiers":["Platinum"],"spaces":["apim","leads"]},
+		{"name":"el3","events":["escalated"],"minEscalationLevel":3,"spaces":["leads"]},
+		{"name":"incidents","types":["incident"],"severities":["critical"],"spaces":["sre"]}
+	]`, knownSpaces("apim", "leads", "sre"))
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		event Event
+		want  []string
+	}{
+		{"product and severity", Event{Kind: KindCreated, RecordType: "case", Product: "API Manager", Severity: "critical"}, []string{"apim"}},
+		{"wrong product", Event{Kind: KindCreated, RecordType: "case", Product: "Identity Server", Severity: "critical"}, nil},
+		{"spaces are de-duplicated across rules", Event{Kind: KindCreated, RecordType: "case", Product: "API Manager", Severity: "catastrophic", AccountTier: "platinum"}, []string{"apim", "leads"}},
+		{"below the minimum level", Event{Kind: KindEscalated, RecordType: "service_request", EscalationLevel: 2}, nil},
+		{"at the minimum level", Event{Kind: KindEscalated, RecordType: "service_request", EscalationLevel: 3}, []string{"leads"}},
+		{"incident priority", Event{Kind: KindCreated, RecordType: RecordIncident, Severity: "CRITICAL"}, []string{"sre"}},
+	}
+	for _, tc := range cases {
+		if got := route(rules, tc.event); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: route = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
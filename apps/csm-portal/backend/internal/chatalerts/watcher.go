@@ -0,0 +1,676 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package chatalerts posts Google Chat alerts automatically when a case or
+// incident is created or escalated -- a case when its escalation level
+// rises, an incident when its priority does -- routed to spaces by
+// configurable rules (see Rule).
+//
+// The Watcher polls the entity service rather than hooking the portal's own
+// create and update handlers: most cases are raised by email or directly in
+// the backing data source, and escalation is set there too, so a hook would
+// see only a fraction of what it should announce.
+//
+// Every alert about one record is posted into one Chat thread per space,
+// keyed by the record, so an escalation lands under the alert for the
+// record's creation. A repeat of the same alert within Config.DedupWindow is dropped.
+//
+// State is in memory only, and a restart re-seeds it without alerting: a record
+// escalated while the process was down is not announced, and nothing already
+// announced is announced again. Every replica running the watcher posts its
+// own copy; run it on one.
+package chatalerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchTimeout bounds each entity search a poll makes.
+const searchTimeout = 30 * time.Second
+
+// searchLimit caps each search's page. A poll that would need more than one
+// page is logged; at a sensible interval it does not happen.
+const searchLimit = 100
+
+// searchLookback widens each created- or updated-since search past the
+// previous poll, so a record whose createdOn or updatedOn lags its
+// visibility in search is still seen. The repeat it causes is dropped by
+// de-duplication.
+const searchLookback = 2 * time.Minute
+
+// maxDeliveryAttempts is how many polls a failed post is retried on before it
+// is dropped.
+const maxDeliveryAttempts = 3
+
+// entityClient abstracts the entity service searches the Watcher polls.
+type entityClient interface {
+	SearchCases(ctx context.Context, body []byte) ([]byte, error)
+	SearchIncidents(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// ChatNotifier posts an alert card into a thread of a Google Chat space (see
+// notifications.GoogleChatClient).
+type ChatNotifier interface {
+	SendThreadedAlert(ctx context.Context, space, threadKey, title, details, portalURL string) error
+}
+
+// Config holds the Watcher's settings.
+type Config struct {
+	// Interval is how often the entity service is polled.
+	Interval time.Duration
+	// DedupWindow is how long a sent alert suppresses an identical one for
+	// the same record. It is never shorter than Interval plus the created
+	// lookback, which the created-event search relies on.
+	DedupWindow time.Duration
+	// PortalBaseURL is the CSM portal webapp base URL alert links point into.
+	PortalBaseURL string
+}
+
+// Event is one thing that happened to a case or incident.
+type Event struct {
+	Kind Kind
+	// RecordType is the case type, or RecordIncident.
+	RecordType string
+	ID         string
+	Number     string
+	Subject    string
+	// Product and AccountTier are empty for incidents.
+	Product     string
+	Severity    string
+	AccountTier string
+	// EscalationLevel is 0 for a created event and for an incident, whose
+	// escalation is a rise in Severity, its priority.
+	EscalationLevel int
+}
+
+// dedupKey identifies an alert for de-duplication: the same record, the same
+// kind and, for an escalation, the same level (a case's) or priority (an
+// incident's).
+func (e Event) dedupKey() string {
+	key := e.RecordType + "|" + e.ID + "|" + string(e.Kind) + "|" + strconv.Itoa(e.EscalationLevel)
+	if e.Kind == KindEscalated && e.RecordType == RecordIncident {
+		key += "|" + e.Severity
+	}
+	return key
+}
+
+// threadKey is the Chat thread every alert about e's record goes into.
+func (e Event) threadKey() string {
+	return e.RecordType + "-" + e.ID
+}
+
+// delivery is one event still to be posted to one space.
+type delivery struct {
+	event    Event
+	space    string
+	attempts int
+}
+
+// Watcher polls for created and escalated records and posts alerts for them.
+type Watcher struct {
+	entity entityClient
+	chat   ChatNotifier
+	rules  []Rule
+	cfg    Config
+	now    func() time.Time
+
+	mu sync.Mutex
+	// casesSince and incidentsSince are the created-event watermarks; each
+	// advances only when its search succeeds, so a failed poll is made up by
+	// the next one.
+	casesSince, incidentsSince time.Time
+	// levelsSince and prioritiesSince are the escalation watermarks: each
+	// poll reads only what was updated since, and they advance the same way.
+	levelsSince, prioritiesSince time.Time
+	seeded                       bool
+	// levels is every open escalated case's level, and priorities every
+	// active incident's priority rank, as last seen. Each is nil until a
+	// poll has seeded it.
+	levels     map[string]int
+	priorities map[string]int
+	sent       map[string]time.Time
+	pending    []delivery
+}
+
+// NewWatcher creates a Watcher posting through chat by rules.
+func NewWatcher(entity entityClient, chat ChatNotifier, rules []Rule, cfg Config) *Watcher {
+	if floor := cfg.Interval + searchLookback; cfg.DedupWindow < floor {
+		cfg.DedupWindow = floor
+	}
+	cfg.PortalBaseURL = strings.TrimRight(cfg.PortalBaseURL, "/")
+	return &Watcher{
+		entity: entity,
+		chat:   chat,
+		rules:  rules,
+		cfg:    cfg,
+		now:    time.Now,
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// Run polls once immediately, to seed its state, and then every
+// cfg.Interval until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		w.PollOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollOnce runs a single poll: detect events, queue a delivery per routed
+// space, and attempt every queued delivery. The first poll only seeds state.
+func (w *Watcher) PollOnce(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	if !w.seeded {
+		w.casesSince, w.incidentsSince = now, now
+		w.seeded = true
+		w.caseEscalations(ctx, now)
+		w.incidentEscalations(ctx, now)
+		return
+	}
+
+	var events []Event
+	if created, err := w.createdCases(ctx, w.casesSince.Add(-searchLookback)); err != nil {
+		slog.ErrorContext(ctx, "chat alerts: created-case search failed", "err", err)
+	} else {
+		events = append(events, created...)
+		w.casesSince = now
+	}
+	if created, err := w.createdIncidents(ctx, w.incidentsSince.Add(-searchLookback)); err != nil {
+		slog.ErrorContext(ctx, "chat alerts: created-incident search failed", "err", err)
+	} else {
+		events = append(events, created...)
+		w.incidentsSince = now
+	}
+	events = append(events, w.caseEscalations(ctx, now)...)
+	events = append(events, w.incidentEscalations(ctx, now)...)
+
+	for key, at := range w.sent {
+		if now.Sub(at) >= w.cfg.DedupWindow {
+			delete(w.sent, key)
+		}
+	}
+	for _, e := range events {
+		w.queue(ctx, e, now)
+	}
+	w.deliver(ctx)
+}
+
+// queue routes e and queues a delivery per space, unless an identical alert
+// was sent within the dedup window.
+func (w *Watcher) queue(ctx context.Context, e Event, now time.Time) {
+	key := e.dedupKey()
+	if _, dup := w.sent[key]; dup {
+		return
+	}
+	spaces := route(w.rules, e)
+	if len(spaces) == 0 {
+		return
+	}
+	w.sent[key] = now
+	slog.InfoContext(ctx, "chat alerts: alert routed", "event", string(e.Kind), "recordType", e.RecordType,
+		"recordId", e.ID, "number", e.Number, "escalationLevel", e.EscalationLevel, "spaces", spaces)
+	for _, s := range spaces {
+		w.pending = append(w.pending, delivery{event: e, space: s})
+	}
+}
+
+// deliver attempts every queued delivery, keeping failures for the next poll
+// until they run out of attempts.
+func (w *Watcher) deliver(ctx context.Context) {
+	var retry []delivery
+	for _, d := range w.pending {
+		title, details := render(d.event)
+		err := w.chat.SendThreadedAlert(ctx, d.space, d.event.threadKey(), title, details, w.portalURL(d.event))
+		if err == nil {
+			continue
+		}
+		d.attempts++
+		attrs := []any{"space", d.space, "recordId", d.event.ID, "event", string(d.event.Kind), "attempts", d.attempts, "err", err}
+		if d.attempts >= maxDeliveryAttempts {
+			slog.ErrorContext(ctx, "chat alerts: giving up on alert", attrs...)
+			continue
+		}
+		slog.WarnContext(ctx, "chat alerts: alert delivery failed; will retry", attrs...)
+		retry = append(retry, d)
+	}
+	w.pending = retry
+}
+
+// caseEscalations returns an escalated event for every case whose level rose
+// since the previous poll -- including one that was not escalated at all.
+// Until the level snapshot has been seeded there is nothing to compare
+// against, so the first successful poll only seeds it, from every open
+// escalated case; later polls read only the cases updated since. A failed
+// search keeps the snapshot and its watermark.
+func (w *Watcher) caseEscalations(ctx context.Context, now time.Time) []Event {
+	var since time.Time
+	if w.levels != nil {
+		since = w.levelsSince.Add(-searchLookback)
+	}
+	changed, err := w.escalationLevels(ctx, since)
+	if err != nil {
+		slog.ErrorContext(ctx, "chat alerts: escalation search failed", "err", err)
+		return nil
+	}
+	w.levelsSince = now
+	if w.levels == nil {
+		w.levels = make(map[string]int, len(changed))
+		for id, c := range changed {
+			w.levels[id] = c.EscalationLevel
+		}
+		return nil
+	}
+
+	var events []Event
+	for id, c := range changed {
+		if c.EscalationLevel > w.levels[id] {
+			events = append(events, c)
+		}
+		if c.EscalationLevel == 0 {
+			delete(w.levels, id)
+		} else {
+			w.levels[id] = c.EscalationLevel
+		}
+	}
+	return events
+}
+
+// incidentEscalations returns an escalated event for every active incident
+// whose priority rose since the previous poll. It seeds and keeps its
+// snapshot the way caseEscalations does. An incident first seen after the
+// seed is recorded without an alert: its created alert already carries its
+// priority.
+func (w *Watcher) incidentEscalations(ctx context.Context, now time.Time) []Event {
+	if w.priorities == nil {
+		items, err := w.activeIncidents(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "chat alerts: incident priority search failed", "err", err)
+			return nil
+		}
+		w.prioritiesSince = now
+		w.priorities = make(map[string]int, len(items))
+		for _, in := range items {
+			if rank, ok := in.rank(); ok && in.ID != nil {
+				w.priorities[*in.ID] = rank
+			}
+		}
+		return nil
+	}
+
+	items, err := w.updatedIncidents(ctx, w.prioritiesSince.Add(-searchLookback))
+	if err != nil {
+		slog.ErrorContext(ctx, "chat alerts: incident priority search failed", "err", err)
+		return nil
+	}
+	w.prioritiesSince = now
+	var events []Event
+	for _, in := range items {
+		if in.ID == nil {
+			continue
+		}
+		rank, ok := in.rank()
+		if !ok || !in.active() {
+			delete(w.priorities, *in.ID)
+			continue
+		}
+		if prev, known := w.priorities[*in.ID]; known && rank < prev {
+			events = append(events, in.event(KindEscalated))
+		}
+		w.priorities[*in.ID] = rank
+	}
+	return events
+}
+
+// render builds the card title and details for e.
+func render(e Event) (title, details string) {
+	label := e.Number
+	if label == "" {
+		label = e.ID
+	}
+	switch e.Kind {
+	case KindEscalated:
+		if e.RecordType == RecordIncident {
+			title = fmt.Sprintf("[Escalated to %s] %s", e.Severity, label)
+		} else {
+			title = fmt.Sprintf("[Escalated to EL%d] %s", e.EscalationLevel, label)
+		}
+	default:
+		title = fmt.Sprintf("[New %s] %s", strings.ReplaceAll(e.RecordType, "_", " "), label)
+	}
+	if e.Subject != "" {
+		title += " — " + e.Subject
+	}
+
+	var parts []string
+	for _, f := range []struct{ name, value string }{
+		{"Severity", e.Severity},
+		{"Product", e.Product},
+		{"Account tier", e.AccountTier},
+	} {
+		if f.value != "" {
+			parts = append(parts, f.name+": "+f.value)
+		}
+	}
+	if len(parts) == 0 {
+		return title, "No further details."
+	}
+	return title, strings.Join(parts, " · ")
+}
+
+// portalPaths is where the portal webapp shows each record type.
+var portalPaths = map[string]string{
+	"case":                     "/cases/",
+	"service_request":          "/operations/service-requests/",
+	"engagement":               "/engagements/",
+	"security_report_analysis": "/security-center/security-reports/",
+	"announcement":             "/announcements/",
+	RecordIncident:             "/operations/incidents/",
+}
+
+func (w *Watcher) portalURL(e Event) string {
+	path, ok := portalPaths[e.RecordType]
+	if !ok {
+		path = portalPaths["case"]
+	}
+	return w.cfg.PortalBaseURL + path + url.PathEscape(e.ID)
+}
+
+// caseItem is the subset of a case search result the watcher reads.
+type caseItem struct {
+	ID       string  `json:"id"`
+	Number   string  `json:"number"`
+	Subject  *string `json:"subject"`
+	Severity *string `json:"severity"`
+	Type     string  `json:"type"`
+	Product  *struct {
+		Name string `json:"name"`
+	} `json:"product"`
+	Account *struct {
+		Type string `json:"type"`
+	} `json:"account"`
+}
+
+func (c caseItem) event(kind Kind, level int) Event {
+	e := Event{Kind: kind, RecordType: c.Type, ID: c.ID, Number: c.Number, EscalationLevel: level}
+	if e.RecordType == "" {
+		e.RecordType = "case"
+	}
+	if c.Subject != nil {
+		e.Subject = *c.Subject
+	}
+	if c.Severity != nil {
+		e.Severity = *c.Severity
+	}
+	if c.Product != nil {
+		e.Product = c.Product.Name
+	}
+	if c.Account != nil {
+		e.AccountTier = c.Account.Type
+	}
+	return e
+}
+
+type caseFilter struct {
+	Field  string   `json:"field"`
+	Op     string   `json:"op"`
+	Values []string `json:"values,omitempty"`
+}
+
+func (w *Watcher) searchCases(ctx context.Context, filters []caseFilter) ([]caseItem, error) {
+	body, err := json.Marshal(map[string]any{
+		"filters":    map[string]any{"filters": filters},
+		"sortBy":     map[string]string{"field": "createdOn", "order": "desc"},
+		"pagination": map[string]int{"offset": 0, "limit": searchLimit},
+	})
+	if err != nil {
+		return nil, err
+	}
+	searchCtx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+	raw, err := w.entity.SearchCases(searchCtx, body)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Cases []caseItem `json:"cases"`
+		Total int        `json:"total"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("decode case search: %w", err)
+	}
+	if resp.Total > len(resp.Cases) {
+		slog.WarnContext(ctx, "chat alerts: case search matched more than one page; the rest are not alerted on",
+			"filters", filters, "total", resp.Total, "limit", searchLimit)
+	}
+	return resp.Cases, nil
+}
+
+func (w *Watcher) createdCases(ctx context.Context, since time.Time) ([]Event, error) {
+	items, err := w.searchCases(ctx, []caseFilter{{Field: "createdOn", Op: "gte", Values: []string{since.UTC().Format(time.RFC3339)}}})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(items))
+	for _, c := range items {
+		events = append(events, c.event(KindCreated, 0))
+	}
+	return events, nil
+}
+
+// escalatedCases maps case id -> its escalated event, at its current level.
+type escalatedCases map[string]Event
+
+// escalationLevels finds the open cases at escalation level 1 or above. With
+// a non-zero since it finds only those updated at or after it, plus the cases
+// updated since that are at level 0 or closed, which come back at level 0 so
+// the caller can forget them. A case search result does not carry the level,
+// so each level is its own search.
+func (w *Watcher) escalationLevels(ctx context.Context, since time.Time) (escalatedCases, error) {
+	var updated []caseFilter
+	if !since.IsZero() {
+		updated = []caseFilter{{Field: "updatedOn", Op: "gte", Values: []string{since.UTC().Format(time.RFC3339)}}}
+	}
+	out := escalatedCases{}
+	if !since.IsZero() {
+		for _, f := range []caseFilter{
+			{Field: "escalationLevel", Op: "in", Values: []string{"0"}},
+			{Field: "state", Op: "in", Values: []string{caseStateClosed}},
+		} {
+			items, err := w.searchCases(ctx, append([]caseFilter{f}, updated...))
+			if err != nil {
+				return nil, fmt.Errorf("de-escalated cases: %w", err)
+			}
+			for _, c := range items {
+				out[c.ID] = c.event(KindEscalated, 0)
+			}
+		}
+	}
+	for level := 1; level <= maxEscalationLevel; level++ {
+		items, err := w.searchCases(ctx, append([]caseFilter{
+			{Field: "escalationLevel", Op: "in", Values: []string{strconv.Itoa(level)}},
+			{Field: "state", Op: "notIn", Values: []string{caseStateClosed}},
+		}, updated...))
+		if err != nil {
+			return nil, fmt.Errorf("escalation level %d: %w", level, err)
+		}
+		for _, c := range items {
+			out[c.ID] = c.event(KindEscalated, level)
+		}
+	}
+	return out, nil
+}
+
+// caseStateClosed is the one case state escalation no longer applies to.
+const caseStateClosed = "closed"
+
+// incidentItem is the subset of an incident search result the watcher reads.
+type incidentItem struct {
+	ID        *string `json:"id"`
+	Number    *string `json:"number"`
+	Subject   *string `json:"subject"`
+	Priority  *string `json:"priority"`
+	State     *string `json:"state"`
+	CreatedOn string  `json:"createdOn"`
+	UpdatedOn string  `json:"updatedOn"`
+}
+
+// incidentPriorityRanks orders the incident priorities, most urgent first.
+var incidentPriorityRanks = map[string]int{"CRITICAL": 1, "HIGH": 2, "MODERATE": 3, "LOW": 4, "PLANNING": 5}
+
+// activeIncidentStates are the states in which an incident's priority is
+// tracked.
+var activeIncidentStates = []string{"NEW", "IN_PROGRESS", "ON_HOLD"}
+
+func (in incidentItem) rank() (int, bool) {
+	if in.Priority == nil {
+		return 0, false
+	}
+	rank, ok := incidentPriorityRanks[*in.Priority]
+	return rank, ok
+}
+
+func (in incidentItem) active() bool {
+	return in.State != nil && slices.Contains(activeIncidentStates, *in.State)
+}
+
+func (in incidentItem) event(kind Kind) Event {
+	e := Event{Kind: kind, RecordType: RecordIncident, ID: *in.ID}
+	if in.Number != nil {
+		e.Number = *in.Number
+	}
+	if in.Subject != nil {
+		e.Subject = *in.Subject
+	}
+	if in.Priority != nil {
+		e.Severity = *in.Priority
+	}
+	return e
+}
+
+// incidentDateLayouts are the createdOn and updatedOn shapes incident search
+// returns; a zoneless one is UTC.
+var incidentDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05"}
+
+// searchIncidents returns the first page of incidents matching filters,
+// newest sortField first, and how many matched in all.
+func (w *Watcher) searchIncidents(ctx context.Context, filters []caseFilter, sortField string) ([]incidentItem, int, error) {
+	body, err := json.Marshal(map[string]any{
+		"filters":    map[string]any{"filters": filters},
+		"sortBy":     map[string]string{"field": sortField, "order": "desc"},
+		"pagination": map[string]int{"offset": 0, "limit": searchLimit},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	searchCtx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+	raw, err := w.entity.SearchIncidents(searchCtx, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	var resp struct {
+		Incidents []incidentItem `json:"incidents"`
+		Total     int            `json:"total"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, 0, fmt.Errorf("decode incident search: %w", err)
+	}
+	return resp.Incidents, resp.Total, nil
+}
+
+// createdIncidents returns incidents created at or after since.
+func (w *Watcher) createdIncidents(ctx context.Context, since time.Time) ([]Event, error) {
+	filters := []caseFilter{{Field: "createdOn", Op: "gte", Values: []string{since.UTC().Format(time.RFC3339)}}}
+	items, total, err := w.searchIncidents(ctx, filters, "createdOn")
+	if err != nil {
+		return nil, err
+	}
+	if total > len(items) {
+		slog.WarnContext(ctx, "chat alerts: incident search matched more than one page; the rest are not alerted on",
+			"filters", filters, "total", total, "limit", searchLimit)
+	}
+	events := make([]Event, 0, len(items))
+	for _, in := range items {
+		if in.ID != nil {
+			events = append(events, in.event(KindCreated))
+		}
+	}
+	return events, nil
+}
+
+// activeIncidents returns the incidents in an active state, to seed the
+// priority snapshot.
+func (w *Watcher) activeIncidents(ctx context.Context) ([]incidentItem, error) {
+	filters := []caseFilter{{Field: "state", Op: "in", Values: activeIncidentStates}}
+	items, total, err := w.searchIncidents(ctx, filters, "updatedOn")
+	if err != nil {
+		return nil, err
+	}
+	if total > len(items) {
+		slog.WarnContext(ctx, "chat alerts: active incidents span more than one page; escalations of the rest are not alerted on",
+			"total", total, "limit", searchLimit)
+	}
+	return items, nil
+}
+
+// updatedIncidents returns incidents updated at or after since, in any state.
+// The incident search has no updated-date filter, so it reads the most
+// recently updated page and stops at the first older record.
+func (w *Watcher) updatedIncidents(ctx context.Context, since time.Time) ([]incidentItem, error) {
+	items, total, err := w.searchIncidents(ctx, nil, "updatedOn")
+	if err != nil {
+		return nil, err
+	}
+	for i, in := range items {
+		updated, ok := parseIncidentDate(in.UpdatedOn)
+		if !ok || updated.Before(since) {
+			return items[:i], nil
+		}
+	}
+	if total > len(items) {
+		slog.WarnContext(ctx, "chat alerts: incidents updated since the previous poll span more than one page; the rest are not compared",
+			"since", since, "limit", searchLimit)
+	}
+	return items, nil
+}
+
+func parseIncidentDate(s string) (time.Time, bool) {
+	for _, layout := range incidentDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
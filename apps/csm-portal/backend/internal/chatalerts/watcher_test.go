@@ -0,0 +1,389 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package chatalerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeCase struct {
+	id, number, product, severity string
+	level                         int
+	closed                        bool
+}
+
+type fakeIncident struct {
+	id, number, priority, state, createdOn, updatedOn string
+}
+
+// fakeEntity serves created-case searches from created, escalation searches
+// from each case's level and state in escalated, and incident searches from
+// incidents. The updatedOn case filter is ignored: every escalated case
+// counts as updated.
+type fakeEntity struct {
+	created   []fakeCase
+	escalated []fakeCase
+	incidents []fakeIncident
+	err       error
+}
+
+func (f *fakeEntity) SearchCases(_ context.Context, body []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var req struct {
+		Filters struct {
+			Filters []caseFilter `json:"filters"`
+		} `json:"filters"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	matched := f.escalated
+	for _, filter := range req.Filters.Filters {
+		if filter.Field == "createdOn" {
+			matched = f.created
+		}
+	}
+	matched = slices.DeleteFunc(slices.Clone(matched), func(c fakeCase) bool {
+		for _, filter := range req.Filters.Filters {
+			switch {
+			case filter.Field == "escalationLevel" && strconv.Itoa(c.level) != filter.Values[0],
+				filter.Field == "state" && filter.Op == "in" && !c.closed,
+				filter.Field == "state" && filter.Op == "notIn" && c.closed:
+				return true
+			}
+		}
+		return false
+	})
+	items := make([]map[string]any, 0, len(matched))
+	for _, c := range matched {
+		items = append(items, map[string]any{
+			"id": c.id, "number": c.number, "subject": "Gateway down", "severity": c.severity, "type": "case",
+			"product": map[string]string{"name": c.product}, "account": map[string]string{"type": "Platinum"},
+		})
+	}
+	return json.Marshal(map[string]any{"cases": items, "total": len(items)})
+}
+
+func (f *fakeEntity) SearchIncidents(_ context.Context, body []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var req struct {
+		Filters struct {
+			Filters []caseFilter `json:"filters"`
+		} `json:"filters"`
+		SortBy struct {
+			Field string `json:"field"`
+		} `json:"sortBy"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	matched := slices.DeleteFunc(slices.Clone(f.incidents), func(in fakeIncident) bool {
+		for _, filter := range req.Filters.Filters {
+			switch filter.Field {
+			case "createdOn":
+				created, _ := parseIncidentDate(in.createdOn)
+				since, _ := time.Parse(time.RFC3339, filter.Values[0])
+				if created.Before(since) {
+					return true
+				}
+			case "state":
+				if !slices.Contains(filter.Values, in.state) {
+					return true
+				}
+			}
+		}
+		return false
+	})
+	slices.SortFunc(matched, func(a, b fakeIncident) int {
+		if req.SortBy.Field == "updatedOn" {
+			return strings.Compare(b.updatedOn, a.updatedOn)
+		}
+		return strings.Compare(b.createdOn, a.createdOn)
+	})
+	items := make([]map[string]any, 0, len(matched))
+	for _, in := range matched {
+		items = append(items, map[string]any{
+			"id": in.id, "number": in.number, "subject": "Outage", "priority": in.priority, "state": in.state,
+			"createdOn": in.createdOn, "updatedOn": in.updatedOn,
+		})
+	}
+	return json.Marshal(map[string]any{"incidents": items, "total": len(items)})
+}
+
+type sentAlert struct{ space, threadKey, title, portalURL string }
+
+type fakeChat struct {
+	sent  []sentAlert
+	fails int
+}
+
+func (f *fakeChat) SendThreadedAlert(_ context.Context, space, threadKey, title, _, portalURL string) error {
+	if f.fails > 0 {
+		f.fails--
+		return errors.New("webhook unavailable")
+	}
+	f.sent = append(f.sent, sentAlert{space, threadKey, title, portalURL})
+	return nil
+}
+
+func newTestWatcher(t *testing.T, entity *fakeEntity, chat *fakeChat) (*Watcher, *time.Time) {
+	t.Helper()
+	rules, err := ParseRules(`[
+		{"name":"apim","products":["API Manager"],"spaces":["apim"]},
+		{"name":"escalations","events":["escalated"],"minEscalationLevel":2,"spaces":["leads"]},
+		{"name":"incidents","types":["incident"],"spaces":["sre"]}
+	]`, func(string) bool { return true })
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+	w := NewWatcher(entity, chat, rules, Config{Interval: time.Minute, DedupWindow: 30 * time.Minute, PortalBaseURL: "https://portal.example/"})
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	return w, &now
+}
+
+func TestWatcher_SeedsThenAlertsCreatedAndEscalated(t *testing.T) {
+	entity := &fakeEntity{escalated: []fakeCase{{id: "c0", number: "CS0000001", product: "API Manager", level: 2}}}
+	chat := &fakeChat{}
+	w, now := newTestWatcher(t, entity, chat)
+
+	// The first poll seeds: c0 is already escalated and is not announced.
+	w.PollOnce(context.Background())
+	if len(chat.sent) != 0 {
+		t.Fatalf("seeding poll sent %v", chat.sent)
+	}
+
+	*now = now.Add(time.Minute)
+	entity.created = []fakeCase{{id: "c1", number: "CS0000002", product: "API Manager", severity: "critical"}}
+	entity.incidents = []fakeIncident{
+		{id: "i1", number: "INC0001", priority: "CRITICAL", state: "NEW", createdOn: "2026-03-02 10:00:30", updatedOn: "2026-03-02 10:00:30"},
+		{id: "i0", number: "INC0000", priority: "LOW", state: "NEW", createdOn: "2026-03-02 09:00:00", updatedOn: "2026-03-02 09:00:00"},
+	}
+	w.PollOnce(context.Background())
+	want := []sentAlert{
+		{"apim", "case-c1", "[New case] CS0000002 — Gateway down", "https://portal.example/cases/c1"},
+		{"sre", "incident-i1", "[New incident] INC0001 — Outage", "https://portal.example/operations/incidents/i1"},
+	}
+	if len(chat.sent) != len(want) {
+		t.Fatalf("sent %v, want %v", chat.sent, want)
+	}
+	for i := range want {
+		if chat.sent[i] != want[i] {
+			t.Errorf("sent[%d] = %+v, want %+v", i, chat.sent[i], want[i])
+		}
+	}
+
+	// c1 is escalated to EL1 (routed only to apim) and then EL3 (both); c0
+	// is unchanged. The created lookback re-reads c1 and i1, which de-dup
+	// drops.
+	chat.sent = nil
+	*now = now.Add(time.Minute)
+	entity.escalated = append(entity.escalated, fakeCase{id: "c1", number: "CS0000002", product: "API Manager", level: 1})
+	w.PollOnce(context.Background())
+	*now = now.Add(time.Minute)
+	entity.escalated[1].level = 3
+	w.PollOnce(context.Background())
+	want = []sentAlert{
+		{"apim", "case-c1", "[Escalated to EL1] CS0000002 — Gateway down", "https://portal.example/cases/c1"},
+		{"apim", "case-c1", "[Escalated to EL3] CS0000002 — Gateway down", "https://portal.example/cases/c1"},
+		{"leads", "case-c1", "[Escalated to EL3] CS0000002 — Gateway down", "https://portal.example/cases/c1"},
+	}
+	if len(chat.sent) != len(want) {
+		t.Fatalf("sent %v, want %v", chat.sent, want)
+	}
+	for i := range want {
+		if chat.sent[i] != want[i] {
+			t.Errorf("sent[%d] = %+v, want %+v", i, chat.sent[i], want[i])
+		}
+	}
+}
+
+func TestWatcher_DedupWindow(t *testing.T) {
+	entity := &fakeEntity{}
+	chat := &fakeChat{}
+	w, now := newTestWatcher(t, entity, chat)
+	w.PollOnce(context.Background())
+
+	// Dropping to EL0 and back to EL2 inside the window is the same alert.
+	escalated := fakeCase{id: "c1", number: "CS0000002", product: "API Manager", level: 2}
+	dropped := escalated
+	dropped.level = 0
+	for _, levels := range [][]fakeCase{{escalated}, {dropped}, {escalated}} {
+		*now = now.Add(time.Minute)
+		entity.escalated = levels
+		w.PollOnce(context.Background())
+	}
+	if len(chat.sent) != 2 { // apim and leads, once each
+		t.Fatalf("sent %d alerts inside the window, want 2: %v", len(chat.sent), chat.sent)
+	}
+
+	*now = now.Add(31 * time.Minute)
+	entity.escalated = []fakeCase{dropped}
+	w.PollOnce(context.Background())
+	entity.escalated = []fakeCase{escalated}
+	w.PollOnce(context.Background())
+	if len(chat.sent) != 4 {
+		t.Fatalf("sent %d alerts after the window, want 4: %v", len(chat.sent), chat.sent)
+	}
+}
+
+func TestWatcher_ForgetsClosedCases(t *testing.T) {
+	escalated := fakeCase{id: "c1", number: "CS0000002", product: "API Manager", level: 2}
+	entity := &fakeEntity{escalated: []fakeCase{escalated}}
+	chat := &fakeChat{}
+	w, now := newTestWatcher(t, entity, chat)
+	w.PollOnce(context.Background())
+	if w.levels["c1"] != 2 {
+		t.Fatalf("seeded levels = %v, want c1 at 2", w.levels)
+	}
+
+	// Closed at EL2, it is no longer tracked; reopened at EL2 it is alerted
+	// again once the dedup window is past.
+	*now = now.Add(time.Minute)
+	entity.escalated[0].closed = true
+	w.PollOnce(context.Background())
+	if _, ok := w.levels["c1"]; ok || len(chat.sent) != 0 {
+		t.Fatalf("after closing: levels %v, sent %v", w.levels, chat.sent)
+	}
+	*now = now.Add(time.Minute)
+	entity.escalated[0].closed = false
+	w.PollOnce(context.Background())
+	if len(chat.sent) != 2 {
+		t.Fatalf("after reopening sent %v, want apim and leads", chat.sent)
+	}
+}
+
+func TestWatcher_IncidentPriorityEscalation(t *testing.T) {
+	entity := &fakeEntity{incidents: []fakeIncident{
+		{id: "i1", number: "INC0001", priority: "MODERATE", state: "IN_PROGRESS", createdOn: "2026-03-01 09:00:00", updatedOn: "2026-03-02 09:00:00"},
+		{id: "i2", number: "INC0002", priority: "LOW", state: "RESOLVED", createdOn: "2026-03-01 09:00:00", updatedOn: "2026-03-02 09:00:00"},
+	}}
+	chat := &fakeChat{}
+	w, now := newTestWatcher(t, entity, chat)
+	w.PollOnce(context.Background())
+	if len(w.priorities) != 1 || w.priorities["i1"] != incidentPriorityRanks["MODERATE"] {
+		t.Fatalf("seeded priorities = %v, want only i1 at MODERATE", w.priorities)
+	}
+
+	poll := func(priority, state string) {
+		*now = now.Add(time.Minute)
+		entity.incidents[0].priority, entity.incidents[0].state = priority, state
+		entity.incidents[0].updatedOn = now.Add(-30 * time.Second).Format("2006-01-02 15:04:05")
+		w.PollOnce(context.Background())
+	}
+	// Raised to HIGH: alerted once, although the lookback reads it twice.
+	// Lowered to LOW: nothing. Raised again after resolving: no longer
+	// tracked, so nothing.
+	poll("HIGH", "IN_PROGRESS")
+	poll("HIGH", "IN_PROGRESS")
+	poll("LOW", "ON_HOLD")
+	poll("LOW", "RESOLVED")
+	poll("CRITICAL", "RESOLVED")
+	want := []sentAlert{{"sre", "incident-i1", "[Escalated to HIGH] INC0001 — Outage", "https://portal.example/operations/incidents/i1"}}
+	if len(chat.sent) != len(want) || chat.sent[0] != want[0] {
+		t.Fatalf("sent %v, want %v", chat.sent, want)
+	}
+
+	// Active again, it is tracked from LOW, so a rise to CRITICAL is alerted.
+	poll("LOW", "IN_PROGRESS")
+	poll("CRITICAL", "IN_PROGRESS")
+	if len(chat.sent) != 2 || chat.sent[1].title != "[Escalated to CRITICAL] INC0001 — Outage" {
+		t.Fatalf("sent %v, want a CRITICAL escalation", chat.sent)
+	}
+}
+
+func TestWatcher_RetriesFailedDeliveries(t *testing.T) {
+	entity := &fakeEntity{}
+	chat := &fakeChat{}
+	w, now := newTestWatcher(t, entity, chat)
+	w.PollOnce(context.Background())
+
+	*now = now.Add(time.Minute)
+	entity.created = []fakeCase{{id: "c1", number: "CS0000002", product: "API Manager"}}
+	chat.fails = 1
+	w.PollOnce(context.Background())
+	if len(chat.sent) != 0 || len(w.pending) != 1 {
+		t.Fatalf("after a failed post: sent %v, pending %d", chat.sent, len(w.pending))
+	}
+
+	*now = now.Add(time.Minute)
+	w.PollOnce(context.Background())
+	if len(chat.sent) != 1 || len(w.pending) != 0 {
+		t.Fatalf("after the retry: sent %v, pending %d", chat.sent, len(w.pending))
+	}
+
+	// A post that keeps failing is given up on after maxDeliveryAttempts.
+	*now = now.Add(time.Minute)
+	entity.created = []fakeCase{{id: "c2", number: "CS0000003", product: "API Manager"}}
+	chat.fails = maxDeliveryAttempts
+	for range maxDeliveryAttempts {
+		w.PollOnce(context.Background())
+		*now = now.Add(time.Minute)
+	}
+	if len(w.pending) != 0 || len(chat.sent) != 1 {
+		t.Fatalf("after exhausting attempts: sent %v, pending %d", chat.sent, len(w.pending))
+	}
+}
+
+func TestWatcher_FailedSearchHoldsWatermark(t *testing.T) {
+	entity := &fakeEntity{}
+	chat := &fakeChat{}
+	w, now := newTestWatcher(t, entity, chat)
+	w.PollOnce(context.Background())
+	seededAt := w.casesSince
+
+	*now = now.Add(time.Minute)
+	entity.err = errors.New("entity service down")
+	w.PollOnce(context.Background())
+	if !w.casesSince.Equal(seededAt) {
+		t.Fatalf("casesSince advanced to %v on a failed search", w.casesSince)
+	}
+
+	var captured []byte
+	probe := &captureEntity{fakeEntity: &fakeEntity{}, body: &captured}
+	w.entity = probe
+	*now = now.Add(time.Minute)
+	w.PollOnce(context.Background())
+	wantSince := seededAt.Add(-searchLookback).Format(time.RFC3339)
+	if !strings.Contains(string(captured), wantSince) {
+		t.Fatalf("created search %s does not start at %s", captured, wantSince)
+	}
+}
+
+// captureEntity records the first case search body of each poll.
+type captureEntity struct {
+	*fakeEntity
+	body *[]byte
+}
+
+func (c *captureEntity) SearchCases(ctx context.Context, body []byte) ([]byte, error) {
+	if strings.Contains(string(body), `"createdOn","op"`) {
+		*c.body = body
+	}
+	return c.fakeEntity.SearchCases(ctx, body)
+}
@@ -25,6 +25,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Type classifies a dashboard by the audience it is built for. It is the
@@ -563,6 +564,9 @@ func validate(loaded []sourced, requireType bool) error {
 		if err := validateWidgets(d, l.source); err != nil {
 			return err
 		}
+		if err := validateReportSchedule(d, time.Now()); err != nil {
+			return fmt.Errorf("dashboard definitions: %s (id %q): %w", l.source, d.ID, err)
+		}
 
 		// CsmDashboardPage selects a caller's landing dashboard by matching
 		// its team key against DefaultForTeamKeys, taking the first list
@@ -650,7 +654,8 @@ var validWidgetResourceTypes = map[ResourceType]bool{
 }
 
 var validWidgetShapes = map[Shape]bool{
-	ShapeCount: true, ShapeList: true, ShapePie: true, ShapeBar: true,
+	ShapeCount: true, ShapeList: true, ShapePie: true, ShapeBar: true, ShapeMetrics: true,
+	ShapeLine: true, ShapeArea: true,
 }
 
 // validateWidgets applies the loader's own fail-loud rationale one level down.
@@ -687,8 +692,8 @@ func validateWidgets(d Dashboard, source string) error {
 			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: unknown \"resourceType\" %q", source, d.ID, w.ID, w.ResourceType)
 		}
 		if !validWidgetShapes[w.Shape] {
-			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: unknown \"shape\" %q; expected one of %q, %q, %q, %q",
-				source, d.ID, w.ID, w.Shape, ShapeCount, ShapeList, ShapePie, ShapeBar)
+			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: unknown \"shape\" %q; expected one of %q, %q, %q, %q, %q, %q, %q",
+				source, d.ID, w.ID, w.Shape, ShapeCount, ShapeList, ShapePie, ShapeBar, ShapeMetrics, ShapeLine, ShapeArea)
 		}
 		if w.GridWidth < 1 || w.GridWidth > 12 {
 			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: \"gridWidth\" is %d; it is a column count out of 12 and must be between 1 and 12",
@@ -706,7 +711,49 @@ func validateWidgets(d Dashboard, source string) error {
 			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: \"groupBy.field\" is empty",
 				source, d.ID, w.ID)
 		}
+		if err := validateMetricsWidget(w); err != nil {
+			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: %w", source, d.ID, w.ID, err)
+		}
+		if err := validateTrendWidget(w); err != nil {
+			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: %w", source, d.ID, w.ID, err)
+		}
+		if err := validateThresholdWidget(w); err != nil {
+			return fmt.Errorf("dashboard definitions: %s (id %q): widget %q: %w", source, d.ID, w.ID, err)
+		}
 	}
 
 	return nil
 }
+
+// validateMetricsWidget checks a Shape "metrics" widget's resourceType and
+// MetricsConfig, and that no other shape carries a "metrics" block it would
+// silently ignore.
+func validateMetricsWidget(w WidgetTemplate) error {
+	if w.Shape != ShapeMetrics {
+		if w.Metrics != nil {
+			return fmt.Errorf("\"metrics\" is only meaningful for shape %q, not %q", ShapeMetrics, w.Shape)
+		}
+		return nil
+	}
+	if w.ResourceType != ResourceIncident {
+		return fmt.Errorf("shape %q is only supported for resourceType %q, not %q", ShapeMetrics, ResourceIncident, w.ResourceType)
+	}
+	m := w.Metrics
+	if m == nil {
+		return fmt.Errorf("shape %q needs a \"metrics\" block", ShapeMetrics)
+	}
+	if !validMetricsMeasures[m.Measure] {
+		return fmt.Errorf("unknown \"metrics.measure\" %q; expected one of %q, %q, %q, %q",
+			m.Measure, MeasureCount, MeasureMTTA, MeasureMTTR, MeasureReopenRate)
+	}
+	if m.RangeDays < 1 || m.RangeDays > maxMetricsRangeDays {
+		return fmt.Errorf("\"metrics.rangeDays\" is %d; it must be between 1 and %d", m.RangeDays, maxMetricsRangeDays)
+	}
+	if !validMetricsIntervals[m.Interval] {
+		return fmt.Errorf("unknown \"metrics.interval\" %q; expected \"week\" or \"month\"", m.Interval)
+	}
+	if !validMetricsGroupBy[m.GroupBy] {
+		return fmt.Errorf("unknown \"metrics.groupBy\" %q; expected one of \"priority\", \"category\", \"assignmentGroup\", \"businessService\"", m.GroupBy)
+	}
+	return nil
+}
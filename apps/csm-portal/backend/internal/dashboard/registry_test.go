@@ -833,6 +833,47 @@ func TestLoadDir_RejectsInvalidWidgets(t *testing.T) {
 			 "query": {}, "groupBy": {"field": ""}}`,
 			want: `widget "w": "groupBy.field" is empty`,
 		},
+		{
+			name: "metrics widget on a resource without a metrics endpoint",
+			widgets: `{"id": "w", "displayName": "W", "resourceType": "case", "shape": "metrics", "gridWidth": 6,
+			 "metrics": {"measure": "mttr", "rangeDays": 90}}`,
+			want: `widget "w": shape "metrics" is only supported for resourceType "incident"`,
+		},
+		{
+			name:    "metrics widget without a metrics block",
+			widgets: `{"id": "w", "displayName": "W", "resourceType": "incident", "shape": "metrics", "gridWidth": 6}`,
+			want:    `widget "w": shape "metrics" needs a "metrics" block`,
+		},
+		{
+			name: "metrics widget with an unknown measure",
+			widgets: `{"id": "w", "displayName": "W", "resourceType": "incident", "shape": "metrics", "gridWidth": 6,
+			 "metrics": {"measure": "mtbf", "rangeDays": 90}}`,
+			want: `widget "w": unknown "metrics.measure" "mtbf"`,
+		},
+		{
+			name: "metrics widget without a range",
+			widgets: `{"id": "w", "displayName": "W", "resourceType": "incident", "shape": "metrics", "gridWidth": 6,
+			 "metrics": {"measure": "mtta"}}`,
+			want: `widget "w": "metrics.rangeDays" is 0`,
+		},
+		{
+			name: "metrics widget with a range wider than the endpoint allows",
+			widgets: `{"id": "w", "displayName": "W", "resourceType": "incident", "shape": "metrics", "gridWidth": 6,
+			 "metrics": {"measure": "mtta", "rangeDays": 400}}`,
+			want: `widget "w": "metrics.rangeDays" is 400; it must be between 1 and 366`,
+		},
+		{
+			name: "metrics widget grouped by a field the endpoint does not support",
+			widgets: `{"id": "w", "displayName": "W", "resourceType": "incident", "shape": "metrics", "gridWidth": 6,
+			 "metrics": {"measure": "count", "rangeDays": 30, "groupBy": "state"}}`,
+			want: `widget "w": unknown "metrics.groupBy" "state"`,
+		},
+		{
+			name: "metrics block on a non-metrics shape",
+			widgets: `{"id": "w", "displayName": "W", "resourceType": "incident", "shape": "count", "gridWidth": 3,
+			 "metrics": {"measure": "count", "rangeDays": 30}}`,
+			want: `widget "w": "metrics" is only meaningful for shape "metrics", not "count"`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,286 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReportSchedule makes a dashboard an emailed snapshot report: the portal's
+// report scheduler (see internal/reports) resolves every widget at each Cron
+// fire time and mails the rendered result to Recipients.
+//
+// Like ThresholdConfig, the report is resolved with the portal's own service
+// credentials, so a widget whose query carries "__current_user__"/
+// "__current_team__"/"__current_oncall__" cannot be resolved for it. Unlike thresholds, that is
+// not a load error -- most team dashboards have a "Mine" widget or two, and
+// losing the whole report over them would be worse than the report saying
+// which widgets it left out.
+type ReportSchedule struct {
+	// Cron is a standard five-field expression (minute hour day-of-month
+	// month day-of-week), e.g. "0 8 * * mon" for Monday 08:00. See
+	// ParseCron for the accepted syntax.
+	Cron string `json:"cron"`
+	// Timezone is the IANA zone Cron is evaluated in. Omitted means UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Recipients are plain email addresses, or "__current_oncall__:<teamKey>"
+	// for whoever is on call for that team at send time; at least one is
+	// required.
+	Recipients []string `json:"recipients"`
+	// Subject overrides the default "<displayName> report" subject line.
+	Subject string `json:"subject,omitempty"`
+}
+
+// Location returns the zone Cron is evaluated in.
+func (s ReportSchedule) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// Next returns the first fire time strictly after after, or the zero time if
+// the schedule is invalid or never fires again.
+func (s ReportSchedule) Next(after time.Time) time.Time {
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}
+	}
+	return c.Next(after, loc)
+}
+
+// CronSchedule is a parsed five-field cron expression. Each field is a bit
+// set of the values it matches.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record an unrestricted ("*") day field: when both
+	// day fields are restricted, a day matching EITHER fires, as in every
+	// other cron.
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day-of-month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as a second Sunday, as most crons do; parseCronField
+	// folds it onto 0.
+	cronDow = cronField{name: "day-of-week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCron parses a five-field cron expression. Each field is "*" or a
+// comma-separated list of values, "a-b" ranges and "/n" steps over either
+// ("*/15", "9-17/2"); month and day-of-week also take three-letter English
+// names ("jan", "mon"). The @-shorthands and seconds/year fields of some
+// crons are not supported.
+func ParseCron(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q has %d fields; expected 5 (minute hour day-of-month month day-of-week)", expr, len(fields))
+	}
+	var c CronSchedule
+	var err error
+	if c.minute, err = parseCronField(fields[0], cronMinute); err != nil {
+		return nil, err
+	}
+	if c.hour, err = parseCronField(fields[1], cronHour); err != nil {
+		return nil, err
+	}
+	if c.dom, err = parseCronField(fields[2], cronDom); err != nil {
+		return nil, err
+	}
+	if c.month, err = parseCronField(fields[3], cronMonth); err != nil {
+		return nil, err
+	}
+	if c.dow, err = parseCronField(fields[4], cronDow); err != nil {
+		return nil, err
+	}
+	c.domStar = fields[2] == "*"
+	c.dowStar = fields[4] == "*"
+	return &c, nil
+}
+
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("cron %s field %q: step %q is not a positive integer", f.name, s, part[i+1:])
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			i := strings.Index(rangePart, "-")
+			var err error
+			if lo, err = cronValue(rangePart[:i], f); err != nil {
+				return 0, fmt.Errorf("cron %s field %q: %w", f.name, s, err)
+			}
+			if hi, err = cronValue(rangePart[i+1:], f); err != nil {
+				return 0, fmt.Errorf("cron %s field %q: %w", f.name, s, err)
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("cron %s field %q: range %q runs backwards", f.name, s, rangePart)
+			}
+		default:
+			v, err := cronValue(rangePart, f)
+			if err != nil {
+				return 0, fmt.Errorf("cron %s field %q: %w", f.name, s, err)
+			}
+			lo = v
+			// "5/10" means from 5 to the end in steps of 10; a bare "5" is
+			// just 5.
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	if f.name == cronDow.name && bits&(1<<7) != 0 {
+		bits = bits&^(1<<7) | 1
+	}
+	return bits, nil
+}
+
+func cronValue(s string, f cronField) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d is outside %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domHit := c.dom&(1<<uint(t.Day())) != 0
+	dowHit := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dowHit
+	case c.dowStar:
+		return domHit
+	}
+	return domHit || dowHit
+}
+
+// cronSearchYears bounds Next's search, so an expression that can never fire
+// (e.g. "0 0 30 2 *", February 30th) ends instead of spinning.
+const cronSearchYears = 5
+
+// Next returns the first minute strictly after after, in loc's wall-clock
+// time, that the schedule fires on -- or the zero time if it never does
+// within cronSearchYears. A wall-clock minute a DST change skips never fires;
+// one it repeats fires on its first occurrence only.
+func (c *CronSchedule) Next(after time.Time, loc *time.Location) time.Time {
+	t := after.In(loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(time.Hour)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 || repeatedMinute(t) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// repeatedMinute reports whether t is the second time round for its
+// wall-clock minute, in the hour a DST change turns the clock back over.
+func repeatedMinute(t time.Time) bool {
+	_, offset := t.Zone()
+	_, dayBefore := t.AddDate(0, 0, -1).Zone()
+	if dayBefore <= offset {
+		return false
+	}
+	first := t.Add(-time.Duration(dayBefore-offset) * time.Second)
+	return first.Hour() == t.Hour() && first.Minute() == t.Minute() && first.Day() == t.Day()
+}
+
+// validateReportSchedule checks a dashboard's ReportSchedule, if it carries
+// one. now is only used to prove the expression fires at all.
+func validateReportSchedule(d Dashboard, now time.Time) error {
+	s := d.Schedule
+	if s == nil {
+		return nil
+	}
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return fmt.Errorf("\"schedule.cron\": %w", err)
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return fmt.Errorf("\"schedule.timezone\" %q is not a known IANA zone", s.Timezone)
+	}
+	if c.Next(now, loc).IsZero() {
+		return fmt.Errorf("\"schedule.cron\" %q never fires", s.Cron)
+	}
+	if len(s.Recipients) == 0 {
+		return fmt.Errorf("\"schedule.recipients\" is empty; a report needs at least one recipient")
+	}
+	for _, addr := range s.Recipients {
+		if !looksLikeRecipient(addr) {
+			return fmt.Errorf("\"schedule.recipients\" entry %q is not an email address or on-call recipient", addr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Wednesday 2026-03-04 10:17 UTC.
+	after := time.Date(2026, 3, 4, 10, 17, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 8 * * mon", time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC)},
+		{"30 9 1 * *", time.Date(2026, 4, 1, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)}, // 7 is Sunday too
+		// Both day fields restricted: either one matching fires.
+		{"0 6 15 * fri", time.Date(2026, 3, 6, 6, 0, 0, 0, time.UTC)},
+	} {
+		c, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tc.expr, err)
+		}
+		if got := c.Next(after, time.UTC); !got.Equal(tc.want) {
+			t.Errorf("%q: Next = %s, want %s", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestCronScheduleNext_Timezone(t *testing.T) {
+	colombo, err := time.LoadLocation("Asia/Colombo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	c, _ := ParseCron("0 8 * * mon")
+	got := c.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), colombo)
+	if want := time.Date(2026, 3, 9, 2, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next = %s, want %s (Monday 08:00 in Colombo)", got.UTC(), want)
+	}
+}
+
+// TestCronScheduleNext_DST verifies a minute the spring-forward change skips
+// never fires, and one the fall-back change repeats fires only the first time
+// round, however far into the repeat after is.
+func TestCronScheduleNext_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	c, _ := ParseCron("30 1,2 * * *")
+	// 2026-11-01 01:00-01:59 happens twice: EDT (05:00Z) then EST (06:00Z).
+	for _, tc := range []struct {
+		name  string
+		after time.Time
+		want  time.Time
+	}{
+		{"before the repeat", time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC)},
+		{"after the first 01:30", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), time.Date(2026, 11, 1, 7, 30, 0, 0, time.UTC)},
+		{"inside the repeat", time.Date(2026, 11, 1, 6, 29, 0, 0, time.UTC), time.Date(2026, 11, 1, 7, 30, 0, 0, time.UTC)},
+		// 2026-03-08 02:00-02:59 doesn't happen.
+		{"across the skip", time.Date(2026, 3, 8, 6, 30, 0, 0, time.UTC), time.Date(2026, 3, 9, 5, 30, 0, 0, time.UTC)},
+	} {
+		if got := c.Next(tc.after, ny); !got.Equal(tc.want) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tc.name, tc.after.In(ny), got.In(ny), tc.want.In(ny))
+		}
+	}
+}
+
+func TestParseCron_Rejects(t *testing.T) {
+	for expr, want := range map[string]string{
+		"0 8 * *":        "has 4 fields",
+		"60 * * * *":     "outside 0-59",
+		"0 8 * * funday": `"funday" is not a number`,
+		"0 17-9 * * *":   "runs backwards",
+		"*/0 * * * *":    "not a positive integer",
+	} {
+		if _, err := ParseCron(expr); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseCron(%q) err = %v, want it to contain %q", expr, err, want)
+		}
+	}
+}
+
+func TestValidateReportSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	recipients := []string{"leads@example.com"}
+	for _, tc := range []struct {
+		name string
+		s    ReportSchedule
+		want string
+	}{
+		{"bad cron", ReportSchedule{Cron: "every monday", Recipients: recipients}, `"schedule.cron"`},
+		{"never fires", ReportSchedule{Cron: "0 0 30 feb *", Recipients: recipients}, "never fires"},
+		{"bad timezone", ReportSchedule{Cron: "0 8 * * mon", Timezone: "Mars/Olympus", Recipients: recipients}, "not a known IANA zone"},
+		{"no recipients", ReportSchedule{Cron: "0 8 * * mon"}, `"schedule.recipients" is empty`},
+		{"bad recipient", ReportSchedule{Cron: "0 8 * * mon", Recipients: []string{"a@x.com, b@x.com"}}, "is not an email address"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.s
+			err := validateReportSchedule(Dashboard{ID: "d", Schedule: &s}, now)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+
+	ok := ReportSchedule{Cron: "0 8 * * mon", Timezone: "UTC", Recipients: recipients}
+	if err := validateReportSchedule(Dashboard{ID: "d", Schedule: &ok}, now); err != nil {
+		t.Errorf("valid schedule rejected: %v", err)
+	}
+}
@@ -300,6 +300,11 @@ func applyExtraFilters(w *WidgetTemplate, extra []map[string]any) {
 	for si := range w.Slices {
 		w.Slices[si].Query = appendFilters(w.Slices[si].Query, extra)
 	}
+	if w.Trend != nil {
+		for si := range w.Trend.Series {
+			w.Trend.Series[si].Query = appendFilters(w.Trend.Series[si].Query, extra)
+		}
+	}
 }
 
 // appendFilters returns query (creating it if nil) with a fresh copy of each
@@ -321,7 +326,7 @@ func appendFilters(query map[string]any, extra []map[string]any) map[string]any
 
 // copyWidget returns a deep copy of w: the WidgetTemplate itself is copied
 // by value, and every decoded-JSON structure hanging off it (Query, SortBy,
-// each slice's Query) is copied deeply, since those are the maps the rest of
+// each slice's and trend series' Query) is copied deeply, since those are the maps the rest of
 // the load pipeline mutates in place. Columns and Slices are copied as fresh
 // slices so an append to one dashboard's copy cannot reach another's.
 func copyWidget(w WidgetTemplate) WidgetTemplate {
@@ -335,6 +340,26 @@ func copyWidget(w WidgetTemplate) WidgetTemplate {
 		g := *w.GroupBy
 		out.GroupBy = &g
 	}
+	if w.Metrics != nil {
+		m := *w.Metrics
+		out.Metrics = &m
+	}
+	if w.Thresholds != nil {
+		t := *w.Thresholds
+		t.Notify.Emails = append([]string(nil), w.Thresholds.Notify.Emails...)
+		t.Mute = append([]MuteWindow(nil), w.Thresholds.Mute...)
+		out.Thresholds = &t
+	}
+	if w.Trend != nil {
+		t := *w.Trend
+		t.Series = make([]TrendSeries, len(w.Trend.Series))
+		for i, ts := range w.Trend.Series {
+			cs := ts
+			cs.Query = deepCopyMap(ts.Query)
+			t.Series[i] = cs
+		}
+		out.Trend = &t
+	}
 	if w.Slices != nil {
 		out.Slices = make([]PieSlice, len(w.Slices))
 		for i, s := range w.Slices {
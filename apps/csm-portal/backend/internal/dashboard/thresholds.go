@@ -0,0 +1,325 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ThresholdOp is how a Shape "count" widget's resolved total is compared
+// against its ThresholdConfig levels.
+type ThresholdOp string
+
+const (
+	OpGreaterThan        ThresholdOp = "gt"
+	OpGreaterThanOrEqual ThresholdOp = "gte"
+	OpLessThan           ThresholdOp = "lt"
+	OpLessThanOrEqual    ThresholdOp = "lte"
+)
+
+var validThresholdOps = map[ThresholdOp]bool{
+	OpGreaterThan: true, OpGreaterThanOrEqual: true, OpLessThan: true, OpLessThanOrEqual: true,
+}
+
+// Breached reports whether value is on the alerting side of threshold. margin
+// moves the threshold toward the healthy side, which is how hysteresis keeps
+// an already-raised level from flapping: a "gt 10" level raised at 11 with a
+// margin of 2 only clears once the value drops to 8.
+func (op ThresholdOp) Breached(value, threshold, margin float64) bool {
+	switch op {
+	case OpGreaterThan:
+		return value > threshold-margin
+	case OpGreaterThanOrEqual:
+		return value >= threshold-margin
+	case OpLessThan:
+		return value < threshold+margin
+	case OpLessThanOrEqual:
+		return value <= threshold+margin
+	}
+	return false
+}
+
+// ThresholdConfig makes a Shape "count" widget alert-bearing: the portal's
+// background evaluator (see internal/alerts) periodically resolves the
+// widget's Query itself, compares the total against Warn and Critical, and
+// notifies Notify when the level rises or recovers.
+//
+// The evaluator resolves Query with the portal's own service credentials, not
+// any signed-in user's, so a widget carrying thresholds cannot use the
+// "__current_user__"/"__current_team__"/"__current_oncall__" placeholders --
+// there is nobody for them to mean. Notify.Emails may still name a team's
+// on-call engineer (see OnCallRecipientTeam). Relative-date placeholders are fine: the entity service
+// resolves those itself.
+type ThresholdConfig struct {
+	Op ThresholdOp `json:"op"`
+	// Warn and Critical are the two levels; at least one must be set. When
+	// both are, Critical must be at least as far onto the alerting side as
+	// Warn (e.g. op "gt", warn 5, critical 10).
+	Warn     *float64 `json:"warn,omitempty"`
+	Critical *float64 `json:"critical,omitempty"`
+	// Hysteresis is how far back past a raised level's threshold the value
+	// must move before that level clears (see ThresholdOp.Breached). Omitted
+	// or zero clears as soon as the value is back on the healthy side.
+	Hysteresis float64 `json:"hysteresis,omitempty"`
+	// Notify is where level changes are posted. At least one target is
+	// required -- an alert nobody receives is just a slower dashboard.
+	Notify AlertTargets `json:"notify"`
+	// Mute suppresses notifications while any window is open. Level changes
+	// are still tracked; a level that is still raised when the window closes
+	// is announced then, one that came and went inside it never is.
+	Mute []MuteWindow `json:"mute,omitempty"`
+}
+
+// AlertTargets is where a ThresholdConfig's level changes are delivered.
+type AlertTargets struct {
+	// GoogleChatProduct selects the Google Chat space, by the same product
+	// key NOTIFICATIONS_GOOGLE_CHAT_SPACES routes on.
+	GoogleChatProduct string `json:"googleChatProduct,omitempty"`
+	// Emails are plain recipient addresses, or "__current_oncall__:<teamKey>"
+	// for whoever is on call for that team when the alert is sent.
+	Emails []string `json:"emails,omitempty"`
+}
+
+// MuteWindow is a recurring daily window, e.g. {"days": ["sat", "sun"],
+// "from": "00:00", "to": "23:59"} for weekends, or {"from": "22:00", "to":
+// "06:00"} overnight every day. From is inclusive and To exclusive; a window
+// whose To is earlier than its From runs past midnight, and Days then names
+// the day it starts on.
+type MuteWindow struct {
+	// Days are three-letter lowercase weekday names ("mon" ... "sun").
+	// Omitted or empty means every day.
+	Days []string `json:"days,omitempty"`
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	// Timezone is an IANA zone name the window's days and times are in.
+	// Omitted means UTC.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+var muteWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an HH:MM time", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t falls inside the window. A window that failed
+// validation never contains anything; validateThresholdWidget keeps those
+// from loading in the first place.
+func (m MuteWindow) Contains(t time.Time) bool {
+	loc := time.UTC
+	if m.Timezone != "" {
+		l, err := time.LoadLocation(m.Timezone)
+		if err != nil {
+			return false
+		}
+		loc = l
+	}
+	from, err := parseClock(m.From)
+	if err != nil {
+		return false
+	}
+	to, err := parseClock(m.To)
+	if err != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	onDay := func(d time.Weekday) bool {
+		if len(m.Days) == 0 {
+			return true
+		}
+		for _, name := range m.Days {
+			if muteWeekdays[name] == d {
+				return true
+			}
+		}
+		return false
+	}
+
+	if from <= to {
+		return onDay(local.Weekday()) && minute >= from && minute < to
+	}
+	// Overnight: the evening part belongs to today, the morning part to the
+	// window that started yesterday.
+	if minute >= from {
+		return onDay(local.Weekday())
+	}
+	return minute < to && onDay(local.AddDate(0, 0, -1).Weekday())
+}
+
+// looksLikeEmail is a sanity check for hand-typed recipient addresses, not
+// RFC 5322 validation: it catches a name pasted where an address belongs, or
+// several addresses pasted into one entry.
+func looksLikeEmail(addr string) bool {
+	at := strings.Index(addr, "@")
+	return at > 0 && at < len(addr)-1 && !strings.ContainsAny(addr, " ,;")
+}
+
+// OnCallPlaceholder stands for the platform user id of the engineer on call
+// for a team right now. In a widget query it means the caller's own team, or
+// the one they name, so it is user-scoped like "__current_team__"; in a recipient list
+// the team is named explicitly (see OnCallRecipientTeam).
+const OnCallPlaceholder = "__current_oncall__"
+
+// userScopedPlaceholders are the query placeholders only a signed-in caller
+// can resolve (see WidgetTemplate's doc comment).
+var userScopedPlaceholders = []string{"__current_user__", "__current_team__", OnCallPlaceholder}
+
+// OnCallRecipientTeam reports whether addr is an on-call recipient,
+// "__current_oncall__:<teamKey>", and for which team.
+func OnCallRecipientTeam(addr string) (string, bool) {
+	key, ok := strings.CutPrefix(addr, OnCallPlaceholder+":")
+	key = strings.TrimSpace(key)
+	return key, ok && key != ""
+}
+
+// looksLikeRecipient accepts an email address or an on-call recipient.
+func looksLikeRecipient(addr string) bool {
+	if _, ok := OnCallRecipientTeam(addr); ok {
+		return true
+	}
+	return looksLikeEmail(addr)
+}
+
+// ExpandRecipients replaces every on-call recipient in addrs with the email
+// onCall returns for its team, dropping duplicates and the teams nobody is on
+// call for. The second result lists the team keys that were dropped, for the
+// caller to log.
+func ExpandRecipients(addrs []string, onCall func(teamKey string) (string, bool)) ([]string, []string) {
+	out := make([]string, 0, len(addrs))
+	seen := make(map[string]bool, len(addrs))
+	var unresolved []string
+	for _, addr := range addrs {
+		if key, ok := OnCallRecipientTeam(addr); ok {
+			email, found := "", false
+			if onCall != nil {
+				email, found = onCall(key)
+			}
+			if !found {
+				unresolved = append(unresolved, key)
+				continue
+			}
+			addr = email
+		}
+		if k := strings.ToLower(addr); !seen[k] {
+			seen[k] = true
+			out = append(out, addr)
+		}
+	}
+	return out, unresolved
+}
+
+// FindUserScopedPlaceholder returns the first user-scoped placeholder found
+// anywhere inside v, a decoded-JSON value, or "" if there is none.
+func FindUserScopedPlaceholder(v any) string {
+	switch x := v.(type) {
+	case string:
+		for _, p := range userScopedPlaceholders {
+			if strings.Contains(x, p) {
+				return p
+			}
+		}
+	case []any:
+		for _, e := range x {
+			if p := FindUserScopedPlaceholder(e); p != "" {
+				return p
+			}
+		}
+	case map[string]any:
+		for _, e := range x {
+			if p := FindUserScopedPlaceholder(e); p != "" {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// validateThresholdWidget checks a widget's ThresholdConfig, if it carries
+// one.
+func validateThresholdWidget(w WidgetTemplate) error {
+	t := w.Thresholds
+	if t == nil {
+		return nil
+	}
+	if w.Shape != ShapeCount {
+		return fmt.Errorf("\"thresholds\" is only meaningful for shape %q, not %q", ShapeCount, w.Shape)
+	}
+	if !validThresholdOps[t.Op] {
+		return fmt.Errorf("unknown \"thresholds.op\" %q; expected one of %q, %q, %q, %q",
+			t.Op, OpGreaterThan, OpGreaterThanOrEqual, OpLessThan, OpLessThanOrEqual)
+	}
+	if t.Warn == nil && t.Critical == nil {
+		return fmt.Errorf("\"thresholds\" needs \"warn\", \"critical\" or both")
+	}
+	if t.Warn != nil && t.Critical != nil {
+		moreSevere := *t.Critical >= *t.Warn
+		if t.Op == OpLessThan || t.Op == OpLessThanOrEqual {
+			moreSevere = *t.Critical <= *t.Warn
+		}
+		if !moreSevere {
+			return fmt.Errorf("\"thresholds.critical\" %v is less severe than \"thresholds.warn\" %v for op %q", *t.Critical, *t.Warn, t.Op)
+		}
+	}
+	if t.Hysteresis < 0 {
+		return fmt.Errorf("\"thresholds.hysteresis\" is %v; it must not be negative", t.Hysteresis)
+	}
+	if strings.TrimSpace(t.Notify.GoogleChatProduct) == "" && len(t.Notify.Emails) == 0 {
+		return fmt.Errorf("\"thresholds.notify\" needs a \"googleChatProduct\", \"emails\" or both")
+	}
+	for _, addr := range t.Notify.Emails {
+		if !looksLikeRecipient(addr) {
+			return fmt.Errorf("\"thresholds.notify.emails\" entry %q is not an email address or on-call recipient", addr)
+		}
+	}
+	for i, m := range t.Mute {
+		if _, err := parseClock(m.From); err != nil {
+			return fmt.Errorf("\"thresholds.mute[%d].from\": %w", i, err)
+		}
+		if _, err := parseClock(m.To); err != nil {
+			return fmt.Errorf("\"thresholds.mute[%d].to\": %w", i, err)
+		}
+		if m.From == m.To {
+			return fmt.Errorf("\"thresholds.mute[%d]\" is empty; \"from\" and \"to\" are both %q", i, m.From)
+		}
+		for _, d := range m.Days {
+			if _, ok := muteWeekdays[d]; !ok {
+				return fmt.Errorf("\"thresholds.mute[%d].days\" entry %q is not one of \"mon\" ... \"sun\"", i, d)
+			}
+		}
+		if m.Timezone != "" {
+			if _, err := time.LoadLocation(m.Timezone); err != nil {
+				return fmt.Errorf("\"thresholds.mute[%d].timezone\" %q is not a known IANA zone", i, m.Timezone)
+			}
+		}
+	}
+	if p := FindUserScopedPlaceholder(w.Query); p != "" {
+		return fmt.Errorf("\"thresholds\" cannot be used on a query carrying %q; alerts are evaluated without a signed-in user", p)
+	}
+	return nil
+}
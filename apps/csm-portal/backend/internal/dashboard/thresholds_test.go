@@ -0,0 +1,130 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateThresholdWidget(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	chat := AlertTargets{GoogleChatProduct: "support-ops"}
+
+	cases := []struct {
+		name string
+		w    WidgetTemplate
+		want string
+	}{
+		{"on a pie widget", WidgetTemplate{Shape: ShapePie, Thresholds: &ThresholdConfig{}}, `only meaningful for shape "count"`},
+		{"unknown op", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: "eq", Warn: f(1), Notify: chat}},
+			`unknown "thresholds.op" "eq"`},
+		{"no levels", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Notify: chat}},
+			`needs "warn", "critical" or both`},
+		{"critical below warn for gt", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(10), Critical: f(5), Notify: chat}},
+			`less severe`},
+		{"critical above warn for lt", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpLessThan, Warn: f(5), Critical: f(10), Notify: chat}},
+			`less severe`},
+		{"negative hysteresis", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1), Hysteresis: -1, Notify: chat}},
+			`must not be negative`},
+		{"no targets", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1)}},
+			`"thresholds.notify" needs`},
+		{"bad email", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1),
+			Notify: AlertTargets{Emails: []string{"leads"}}}}, `"leads" is not an email address`},
+		{"bad mute time", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1), Notify: chat,
+			Mute: []MuteWindow{{From: "25:00", To: "06:00"}}}}, `"thresholds.mute[0].from"`},
+		{"empty mute window", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1), Notify: chat,
+			Mute: []MuteWindow{{From: "06:00", To: "06:00"}}}}, `is empty`},
+		{"bad mute day", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1), Notify: chat,
+			Mute: []MuteWindow{{Days: []string{"saturday"}, From: "00:00", To: "23:59"}}}}, `"saturday" is not one of`},
+		{"bad mute timezone", WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1), Notify: chat,
+			Mute: []MuteWindow{{From: "22:00", To: "06:00", Timezone: "Mars/Olympus"}}}}, `not a known IANA zone`},
+		{"user-scoped query", WidgetTemplate{Shape: ShapeCount,
+			Query:      map[string]any{"filters": []any{map[string]any{"field": "assignedTo", "op": "eq", "values": []any{"__current_user__"}}}},
+			Thresholds: &ThresholdConfig{Op: OpGreaterThan, Warn: f(1), Notify: chat}}, `"__current_user__"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateThresholdWidget(tc.w)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+
+	ok := WidgetTemplate{Shape: ShapeCount, Thresholds: &ThresholdConfig{Op: OpLessThanOrEqual, Warn: f(5), Critical: f(0),
+		Notify: AlertTargets{Emails: []string{"leads@example.com"}}, Mute: []MuteWindow{{Days: []string{"sat", "sun"}, From: "00:00", To: "23:59", Timezone: "Asia/Colombo"}}}}
+	if err := validateThresholdWidget(ok); err != nil {
+		t.Errorf("valid thresholds rejected: %v", err)
+	}
+}
+
+func TestMuteWindowContains(t *testing.T) {
+	overnight := MuteWindow{Days: []string{"fri"}, From: "22:00", To: "06:00"}
+	for _, tc := range []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 3, 6, 21, 59, 0, 0, time.UTC), false}, // Friday, before the window
+		{time.Date(2026, 3, 6, 22, 0, 0, 0, time.UTC), true},   // Friday, from is inclusive
+		{time.Date(2026, 3, 7, 5, 59, 0, 0, time.UTC), true},   // Saturday morning belongs to Friday's window
+		{time.Date(2026, 3, 7, 6, 0, 0, 0, time.UTC), false},   // to is exclusive
+		{time.Date(2026, 3, 7, 23, 0, 0, 0, time.UTC), false},  // Saturday's own evening is not muted
+	} {
+		if got := overnight.Contains(tc.at); got != tc.want {
+			t.Errorf("Contains(%s) = %v, want %v", tc.at.Format(time.RFC1123), got, tc.want)
+		}
+	}
+
+	colombo := MuteWindow{From: "09:00", To: "17:00", Timezone: "Asia/Colombo"} // UTC+05:30
+	if !colombo.Contains(time.Date(2026, 3, 6, 4, 0, 0, 0, time.UTC)) {
+		t.Error("09:30 in Colombo should be inside a 09:00-17:00 Colombo window")
+	}
+	if colombo.Contains(time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)) {
+		t.Error("17:30 in Colombo should be outside a 09:00-17:00 Colombo window")
+	}
+}
+
+func TestThresholdOpBreached(t *testing.T) {
+	if !OpGreaterThan.Breached(9, 10, 2) || OpGreaterThan.Breached(8, 10, 2) {
+		t.Error("gt 10 with margin 2 should hold at 9 and clear at 8")
+	}
+	if !OpLessThanOrEqual.Breached(6, 5, 1) || OpLessThanOrEqual.Breached(7, 5, 1) {
+		t.Error("lte 5 with margin 1 should hold at 6 and clear at 7")
+	}
+}
+
+func TestExpandRecipients(t *testing.T) {
+	onCall := func(teamKey string) (string, bool) {
+		if teamKey == "abt-1" {
+			return "Jane@example.com", true
+		}
+		return "", false
+	}
+	got, unresolved := ExpandRecipients([]string{"jane@example.com", "__current_oncall__:abt-1", "__current_oncall__:abt-2", "leads@example.com"}, onCall)
+	if want := []string{"jane@example.com", "leads@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recipients = %v, want %v", got, want)
+	}
+	if want := []string{"abt-2"}; !reflect.DeepEqual(unresolved, want) {
+		t.Errorf("unresolved = %v, want %v", unresolved, want)
+	}
+	if _, ok := OnCallRecipientTeam("__current_oncall__:"); ok {
+		t.Error("an on-call recipient without a team key was accepted")
+	}
+}
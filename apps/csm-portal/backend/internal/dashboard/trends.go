@@ -0,0 +1,307 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dashboard
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrendBucket is the period a Shape "line"/"area" widget counts per point.
+type TrendBucket string
+
+const (
+	BucketDay   TrendBucket = "day"
+	BucketWeek  TrendBucket = "week" // Monday-start, matching /incidents/metrics
+	BucketMonth TrendBucket = "month"
+)
+
+var validTrendBuckets = map[TrendBucket]bool{BucketDay: true, BucketWeek: true, BucketMonth: true}
+
+// validTrendDateFields are the date fields a TrendSeries may bucket on. Every
+// case-table resourceType's /search accepts gte/lte on all three; a resource
+// that lacks one (incident search only filters on createdOn) rejects it with a 400 from
+// its own /search, not caught here.
+var validTrendDateFields = map[string]bool{"createdOn": true, "closedOn": true, "resolvedOn": true}
+
+// maxTrendSearches caps buckets x series for one widget. Every point is its
+// own search (see ResolveTrendRange), so a "day" bucket over a year (365
+// calls, per series, per dashboard load) is a definition mistake, not a chart.
+const maxTrendSearches = 120
+
+// TrendConfig configures a Shape "line"/"area" widget. The backend resolves
+// it (GET /dashboards/{dashboardId}/widgets/{widgetId}/trend, see
+// reports.TrendResolver): From and To are resolved to a range, the range is
+// cut into Bucket-sized periods, and each point is one /search per Series
+// entry with the widget's Query merged under the series' own Query and a
+// {DateField gte periodStart, DateField lte periodEnd} pair appended, reading
+// total off the response with pagination.limit=1. Being resolved with the
+// service's own credentials, a trend's criteria cannot carry a user-scoped
+// placeholder.
+type TrendConfig struct {
+	Bucket TrendBucket `json:"bucket"`
+	// From and To bound the range, inclusive. Both are literal "YYYY-MM-DD"
+	// dates, or both are calendar-day relative-date placeholders the entity
+	// service's date filters accept (__today__, __daysAgo:N__,
+	// __startOfMonth:N__, __endOfMonth:N__, __startOfQuarter:N__,
+	// __endOfQuarter:N__); a fixed end against a moving one would outgrow
+	// maxTrendSearches one day. Its business-day and @zone placeholders are
+	// not accepted here: the range is cut into periods without the entity
+	// service's calendars. To defaults to __today__ when omitted.
+	From string `json:"from"`
+	To   string `json:"to,omitempty"`
+	// Series is one line (or one stacked band, for Shape "area") per entry.
+	Series []TrendSeries `json:"series"`
+}
+
+// TrendSeries is one line of a TrendConfig — e.g. "Created" bucketed on
+// createdOn alongside "Closed" bucketed on closedOn, over the same widget.
+type TrendSeries struct {
+	Label string `json:"label"`
+	// Color is a palette key, exactly as PieSlice.Color.
+	Color string `json:"color,omitempty"`
+	// DateField is which date field this series counts per bucket.
+	DateField string `json:"dateField"`
+	// Query is this series' own extra criteria, merged over the widget's own
+	// Query the same way PieSlice.Query is (see injectImpliedTypeFilters for
+	// why every series' own "filters" gets the implied type filter too).
+	Query map[string]any `json:"query,omitempty"`
+}
+
+// trendDatePlaceholderPattern matches the same placeholder shape the frontend's
+// resolveRelativeDateFilters.ts resolves: __name__ or __name:N__ with an
+// integer N. Anything else must be a literal date.
+var trendDatePlaceholderPattern = regexp.MustCompile(`^__([a-zA-Z]+)(?::(-?\d+))?__$`)
+
+// resolveTrendDate resolves one TrendConfig.From/To value against now (UTC),
+// mirroring the entity service's resolveRelativeDate.
+func resolveTrendDate(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	m := trendDatePlaceholderPattern.FindStringSubmatch(value)
+	if m == nil {
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is neither a YYYY-MM-DD date nor a relative-date placeholder", value)
+		}
+		return t, nil
+	}
+
+	name, argStr := m[1], m[2]
+	if name == "today" {
+		if argStr != "" {
+			return time.Time{}, fmt.Errorf("%q does not take an argument", value)
+		}
+		return today, nil
+	}
+	if argStr == "" {
+		return time.Time{}, fmt.Errorf("%q requires a :N integer offset", value)
+	}
+	n, err := strconv.Atoi(argStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q has a non-integer offset", value)
+	}
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	quarterStart := time.Date(today.Year(), time.Month((int(today.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	switch name {
+	case "daysAgo":
+		if n < 0 {
+			return time.Time{}, fmt.Errorf("%q must have a non-negative offset", value)
+		}
+		return today.AddDate(0, 0, -n), nil
+	case "startOfMonth":
+		return monthStart.AddDate(0, n, 0), nil
+	case "endOfMonth":
+		return monthStart.AddDate(0, n+1, -1), nil
+	case "startOfQuarter":
+		return quarterStart.AddDate(0, 3*n, 0), nil
+	case "endOfQuarter":
+		return quarterStart.AddDate(0, 3*(n+1), -1), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a known relative-date placeholder", value)
+}
+
+// TrendPeriod is one Bucket-sized period of a resolved trend range, both
+// ends inclusive.
+type TrendPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ResolveTrendRange resolves t's From/To against now and cuts the range into
+// t.Bucket-sized periods, the first and last clipped to the range.
+func ResolveTrendRange(t TrendConfig, now time.Time) ([]TrendPeriod, error) {
+	from, to, err := trendRange(t, now)
+	if err != nil {
+		return nil, err
+	}
+	var periods []TrendPeriod
+	for start := from; !start.After(to); {
+		next := trendBucketStart(start, t.Bucket)
+		switch t.Bucket {
+		case BucketWeek:
+			next = next.AddDate(0, 0, 7)
+		case BucketMonth:
+			next = next.AddDate(0, 1, 0)
+		default:
+			next = next.AddDate(0, 0, 1)
+		}
+		end := next.AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+		periods = append(periods, TrendPeriod{Start: start, End: end})
+		start = next
+	}
+	return periods, nil
+}
+
+// trendRange resolves t's From/To against now.
+func trendRange(t TrendConfig, now time.Time) (from, to time.Time, err error) {
+	if from, err = resolveTrendDate(t.From, now); err != nil {
+		return from, to, fmt.Errorf("\"trend.from\": %w", err)
+	}
+	if to, err = resolveTrendDate(trendTo(t), now); err != nil {
+		return from, to, fmt.Errorf("\"trend.to\": %w", err)
+	}
+	return from, to, nil
+}
+
+func trendTo(t TrendConfig) string {
+	if t.To == "" {
+		return "__today__"
+	}
+	return t.To
+}
+
+// trendBucketStart is the first day of the Bucket-sized period t falls in.
+func trendBucketStart(t time.Time, bucket TrendBucket) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t
+}
+
+// trendBucketCount is how many Bucket-sized periods [from, to] touches.
+func trendBucketCount(from, to time.Time, bucket TrendBucket) int {
+	switch bucket {
+	case BucketWeek:
+		return int(trendBucketStart(to, bucket).Sub(trendBucketStart(from, bucket)).Hours()/24)/7 + 1
+	case BucketMonth:
+		return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month()) + 1
+	default:
+		return int(to.Sub(from).Hours()/24) + 1
+	}
+}
+
+// trendCycleStart and trendCycleYears span every combination of month
+// length, leap year and weekday a calendar day can fall on, so a relative
+// range checked against each day in it is checked against its worst case.
+var trendCycleStart = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const trendCycleYears = 28
+
+// worstTrendRange checks a relative range against every day of the cycle
+// and returns the most buckets it ever touches. A range that is reversed on
+// any day is an error naming that day.
+func worstTrendRange(t TrendConfig) (int, error) {
+	worst := 0
+	end := trendCycleStart.AddDate(trendCycleYears, 0, 0)
+	for now := trendCycleStart; now.Before(end); now = now.AddDate(0, 0, 1) {
+		from, to, err := trendRange(t, now)
+		if err != nil {
+			return 0, err
+		}
+		if to.Before(from) {
+			return 0, fmt.Errorf("\"trend.from\" %q resolves after \"trend.to\" %q (on %s, for one)",
+				t.From, trendTo(t), now.Format("2006-01-02"))
+		}
+		worst = max(worst, trendBucketCount(from, to, t.Bucket))
+	}
+	return worst, nil
+}
+
+// validateTrendWidget checks a Shape "line"/"area" widget's TrendConfig, and
+// that no other shape carries a "trend" block it would silently ignore. The
+// range checks hold on every day, not just the day the definition loads: a
+// relative range is checked against its worst case.
+func validateTrendWidget(w WidgetTemplate) error {
+	if w.Shape != ShapeLine && w.Shape != ShapeArea {
+		if w.Trend != nil {
+			return fmt.Errorf("\"trend\" is only meaningful for shapes %q/%q, not %q", ShapeLine, ShapeArea, w.Shape)
+		}
+		return nil
+	}
+	t := w.Trend
+	if t == nil {
+		return fmt.Errorf("shape %q needs a \"trend\" block", w.Shape)
+	}
+	if !validTrendBuckets[t.Bucket] {
+		return fmt.Errorf("unknown \"trend.bucket\" %q; expected one of %q, %q, %q", t.Bucket, BucketDay, BucketWeek, BucketMonth)
+	}
+	if strings.TrimSpace(t.From) == "" {
+		return fmt.Errorf("\"trend.from\" is empty")
+	}
+	// Resolved once up front so a malformed value is reported as such, not
+	// from inside the cycle walk.
+	from, to, err := trendRange(*t, trendCycleStart)
+	if err != nil {
+		return err
+	}
+	fromFixed := !trendDatePlaceholderPattern.MatchString(t.From)
+	toFixed := !trendDatePlaceholderPattern.MatchString(trendTo(*t))
+	if fromFixed != toFixed {
+		return fmt.Errorf("\"trend.from\" %q and \"trend.to\" %q must both be literal dates or both be relative-date placeholders; a fixed end against a moving one grows (or reverses) every day",
+			t.From, trendTo(*t))
+	}
+	buckets := 0
+	if fromFixed {
+		if to.Before(from) {
+			return fmt.Errorf("\"trend.from\" %q resolves after \"trend.to\" %q", t.From, trendTo(*t))
+		}
+		buckets = trendBucketCount(from, to, t.Bucket)
+	} else if buckets, err = worstTrendRange(*t); err != nil {
+		return err
+	}
+	if len(t.Series) == 0 {
+		return fmt.Errorf("\"trend.series\" is empty; a trend needs at least one series")
+	}
+	for i, s := range t.Series {
+		if strings.TrimSpace(s.Label) == "" {
+			return fmt.Errorf("\"trend.series[%d].label\" is empty", i)
+		}
+		if !validTrendDateFields[s.DateField] {
+			return fmt.Errorf("trend series %q: unknown \"dateField\" %q; expected one of \"createdOn\", \"closedOn\", \"resolvedOn\"", s.Label, s.DateField)
+		}
+		if p := FindUserScopedPlaceholder(s.Query); p != "" {
+			return fmt.Errorf("trend series %q: cannot carry %q; a trend is resolved without a signed-in user", s.Label, p)
+		}
+	}
+	if p := FindUserScopedPlaceholder(w.Query); p != "" {
+		return fmt.Errorf("\"trend\" cannot be used on a query carrying %q; a trend is resolved without a signed-in user", p)
+	}
+	if n := buckets * len(t.Series); n > maxTrendSearches {
+		return fmt.Errorf("\"trend\" needs up to %d searches per load (%d series x %d %s buckets), over the limit of %d; use a larger bucket or a shorter range",
+			n, len(t.Series), buckets, t.Bucket, maxTrendSearches)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// The CRE ask this shape exists for: cases created vs closed per week over
+// the last quarter. Both series get the implied case "type" filter, same as
+// a pie slice would.
+func TestLoadDir_TrendWidget(t *testing.T) {
+	dir := t.TempDir()
+	writeDefinition(t, dir, "d.json", `{
+	  "id": "d", "displayName": "D", "type": "cs",
+	  "widgets": [{"id": "created-vs-closed", "displayName": "Created vs Closed", "resourceType": "case",
+	   "shape": "line", "gridWidth": 12, "query": {},
+	   "trend": {"bucket": "week", "from": "__startOfQuarter:-1__", "to": "__endOfQuarter:-1__", "series": [
+	     {"label": "Created", "dateField": "createdOn"},
+	     {"label": "Closed", "dateField": "closedOn", "color": "success"}
+	   ]}}]
+	}`)
+
+	dashboards, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	trend := dashboards[0].Widgets[0].Trend
+	if trend == nil || len(trend.Series) != 2 {
+		t.Fatalf("trend = %+v, want two series", trend)
+	}
+	for _, s := range trend.Series {
+		filters, _ := s.Query["filters"].([]any)
+		if len(filters) != 1 || filters[0].(map[string]any)["field"] != "type" {
+			t.Errorf("series %q query = %v, want the implied type filter", s.Label, s.Query)
+		}
+	}
+}
+
+func TestValidateTrendWidget(t *testing.T) {
+	series := []TrendSeries{{Label: "Created", DateField: "createdOn"}}
+
+	cases := []struct {
+		name string
+		w    WidgetTemplate
+		want string
+	}{
+		{"line without trend", WidgetTemplate{Shape: ShapeLine}, `shape "line" needs a "trend" block`},
+		{"trend on a count widget", WidgetTemplate{Shape: ShapeCount, Trend: &TrendConfig{}}, `"trend" is only meaningful`},
+		{"unknown bucket", WidgetTemplate{Shape: ShapeArea, Trend: &TrendConfig{Bucket: "hour", From: "__daysAgo:7__", Series: series}},
+			`unknown "trend.bucket" "hour"`},
+		{"missing from", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketWeek, Series: series}},
+			`"trend.from" is empty`},
+		{"unknown placeholder", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketWeek, From: "__weeksAgo:4__", Series: series}},
+			`"__weeksAgo:4__" is not a known relative-date placeholder`},
+		{"reversed range", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketDay, From: "__today__", To: "__daysAgo:3__", Series: series}},
+			`resolves after`},
+		{"no series", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketWeek, From: "__daysAgo:90__"}},
+			`"trend.series" is empty`},
+		{"unknown date field", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketWeek, From: "__daysAgo:90__",
+			Series: []TrendSeries{{Label: "Updated", DateField: "updatedOn"}}}},
+			`unknown "dateField" "updatedOn"`},
+		{"too many searches", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketDay, From: "__daysAgo:365__", Series: series}},
+			`over the limit of 120`},
+		// Three whole months plus the current one is 92-123 days depending on
+		// the day; the worst case decides, not the day it loads.
+		{"too many searches in the worst case", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketDay, From: "__startOfMonth:-3__", Series: series}},
+			`over the limit of 120`},
+		{"reversed on some days", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketDay, From: "__daysAgo:20__", To: "__startOfMonth:0__", Series: series}},
+			`resolves after`},
+		{"fixed start, moving end", WidgetTemplate{Shape: ShapeLine, Trend: &TrendConfig{Bucket: BucketMonth, From: "2026-01-01", Series: series}},
+			`must both be literal dates or both be relative-date placeholders`},
+		{"user-scoped query", WidgetTemplate{Shape: ShapeLine, Query: map[string]any{"filters": []any{map[string]any{"field": "assignedUserId", "values": []any{"__current_user__"}}}},
+			Trend: &TrendConfig{Bucket: BucketWeek, From: "__daysAgo:90__", Series: series}},
+			`cannot be used on a query carrying "__current_user__"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTrendWidget(tc.w)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+
+	for _, tr := range []TrendConfig{
+		{Bucket: BucketMonth, From: "__startOfMonth:-11__", Series: series},
+		{Bucket: BucketWeek, From: "2026-01-01", To: "2026-03-31", Series: series},
+		{Bucket: BucketWeek, From: "__startOfQuarter:-1__", To: "__endOfQuarter:-1__", Series: series},
+	} {
+		if err := validateTrendWidget(WidgetTemplate{Shape: ShapeLine, Trend: &tr}); err != nil {
+			t.Errorf("valid trend %+v rejected: %v", tr, err)
+		}
+	}
+}
+
+func TestResolveTrendDate(t *testing.T) {
+	now := time.Date(2026, 5, 14, 23, 30, 0, 0, time.UTC)
+	for value, want := range map[string]string{
+		"__today__":             "2026-05-14",
+		"__daysAgo:14__":        "2026-04-30",
+		"__startOfMonth:-1__":   "2026-04-01",
+		"__endOfMonth:0__":      "2026-05-31",
+		"__startOfQuarter:-1__": "2026-01-01",
+		"__endOfQuarter:-1__":   "2026-03-31",
+		"2025-12-25":            "2025-12-25",
+	} {
+		got, err := resolveTrendDate(value, now)
+		if err != nil || got.Format("2006-01-02") != want {
+			t.Errorf("resolveTrendDate(%q) = %v, %v; want %s", value, got, err, want)
+		}
+	}
+}
+
+func TestTrendBucketCount(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) // Thursday
+	to := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)  // Tuesday
+	for bucket, want := range map[TrendBucket]int{BucketDay: 90, BucketWeek: 14, BucketMonth: 3} {
+		if got := trendBucketCount(from, to, bucket); got != want {
+			t.Errorf("trendBucketCount(%s) = %d, want %d", bucket, got, want)
+		}
+	}
+}
+
+func TestResolveTrendRange(t *testing.T) {
+	now := time.Date(2026, 5, 14, 10, 0, 0, 0, time.UTC) // Thursday
+	periods, err := ResolveTrendRange(TrendConfig{Bucket: BucketWeek, From: "__daysAgo:10__"}, now)
+	if err != nil {
+		t.Fatalf("ResolveTrendRange: %v", err)
+	}
+	var got []string
+	for _, p := range periods {
+		got = append(got, p.Start.Format("2006-01-02")+".."+p.End.Format("2006-01-02"))
+	}
+	want := []string{"2026-05-04..2026-05-10", "2026-05-11..2026-05-14"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("periods = %v, want %v", got, want)
+	}
+
+	periods, err = ResolveTrendRange(TrendConfig{Bucket: BucketMonth, From: "2026-01-15", To: "2026-03-02"}, now)
+	if err != nil || len(periods) != 3 || periods[0].End.Format("2006-01-02") != "2026-01-31" || periods[2].Start.Format("2006-01-02") != "2026-03-01" {
+		t.Errorf("monthly periods = %v, %v", periods, err)
+	}
+}
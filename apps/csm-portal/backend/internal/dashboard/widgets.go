@@ -72,8 +72,67 @@ const (
 	ShapeList  Shape = "list"  // top-N matching records
 	ShapePie   Shape = "pie"   // one search per Slices entry (or one server-side aggregation via GroupBy), each resolved via its own total — see PieSlice and GroupByConfig
 	ShapeBar   Shape = "bar"   // same resolution as ShapePie; differs only in how the frontend renders the resolved data
+	// ShapeMetrics is one POST /incidents/metrics call rendered as a time
+	// series of a single measure — see MetricsConfig. Only ResourceIncident
+	// has a metrics endpoint, so it is the only resourceType this shape is
+	// accepted for.
+	ShapeMetrics Shape = "metrics"
+	// ShapeLine and ShapeArea are bucketed counts over a date range, one
+	// series per TrendSeries — see TrendConfig. ShapeArea stacks the series;
+	// the two differ only in how the frontend renders the resolved data.
+	ShapeLine Shape = "line"
+	ShapeArea Shape = "area"
 )
 
+// MetricsMeasure is which value of an /incidents/metrics response a Shape
+// "metrics" widget plots.
+type MetricsMeasure string
+
+const (
+	MeasureCount      MetricsMeasure = "count"      // incidents opened per period
+	MeasureMTTA       MetricsMeasure = "mtta"       // meanTimeToAcknowledgeSeconds
+	MeasureMTTR       MetricsMeasure = "mttr"       // meanTimeToResolveSeconds
+	MeasureReopenRate MetricsMeasure = "reopenRate" // reopened / resolved
+)
+
+var validMetricsMeasures = map[MetricsMeasure]bool{
+	MeasureCount: true, MeasureMTTA: true, MeasureMTTR: true, MeasureReopenRate: true,
+}
+
+// maxMetricsRangeDays mirrors the entity service's cap on the createdOn range
+// of one /incidents/metrics call; a wider RangeDays would 400 on every load.
+const maxMetricsRangeDays = 366
+
+// validMetricsIntervals and validMetricsGroupBy mirror the entity service's own
+// /incidents/metrics allowlists. Unlike GroupByConfig.Field, which is left to
+// each aggregate endpoint to reject, these are checked here: the set is one
+// endpoint's, small and fixed, and a dashboard widget that 400s on every load
+// is exactly the silent breakage validateWidgets exists to catch.
+var (
+	validMetricsIntervals = map[string]bool{"": true, "week": true, "month": true}
+	validMetricsGroupBy   = map[string]bool{
+		"": true, "priority": true, "category": true, "assignmentGroup": true, "businessService": true,
+	}
+)
+
+// MetricsConfig configures a Shape "metrics" widget. The frontend resolves it
+// with a single POST /incidents/metrics call whose filters are the widget's
+// own Query plus a createdOn range of the last RangeDays days ending today,
+// computed client-side at render time -- the endpoint requires a closed range,
+// and a dashboard definition has no fixed dates to give it.
+type MetricsConfig struct {
+	// Measure is which value to plot per period.
+	Measure MetricsMeasure `json:"measure"`
+	// RangeDays is how many days back from today the createdOn range reaches.
+	RangeDays int `json:"rangeDays"`
+	// Interval is "week" or "month". Omitted defers to the endpoint's own
+	// default (week).
+	Interval string `json:"interval,omitempty"`
+	// GroupBy, when set, plots one line per group (the response's per-group
+	// series) instead of the single overall line.
+	GroupBy string `json:"groupBy,omitempty"`
+}
+
 // GroupByConfig configures a Shape "pie"/"bar" widget to resolve via a single
 // server-side aggregation call instead of one search per literal Slices
 // entry -- the alternative for a resourceType/field with too many distinct
@@ -168,7 +227,11 @@ type Column struct {
 // filter value carries is left exactly as authored -- the BE does not
 // resolve it, the frontend does, client-side (see
 // apps/csm-portal/webapp/src/features/csm-dashboard/utils, e.g.
-// teamFilterPlaceholder.ts for the pattern). The two things the BE does
+// teamFilterPlaceholder.ts for the pattern). The exception is
+// OnCallPlaceholder, which only the BE can resolve: GET
+// /dashboards/{dashboardId} substitutes it per request, on a copy, for the
+// caller's own team or ?team=.
+// The two things the BE does
 // interpret, both once at directory-load time rather than per-request, are:
 // migrating deprecated key names (see migrateLegacyWidgetKeys), and
 // expanding {"preset": "key"} filter references and auto-injecting the
@@ -194,6 +257,15 @@ type WidgetTemplate struct {
 	// are meaningless for count/list.
 	Slices  []PieSlice     `json:"slices,omitempty"`
 	GroupBy *GroupByConfig `json:"groupBy,omitempty"`
+	// Metrics is required for, and only meaningful for, Shape metrics (see
+	// MetricsConfig).
+	Metrics *MetricsConfig `json:"metrics,omitempty"`
+	// Trend is required for, and only meaningful for, Shape line/area (see
+	// TrendConfig).
+	Trend *TrendConfig `json:"trend,omitempty"`
+	// Thresholds is only meaningful for Shape count: it makes the widget an
+	// alert the portal evaluates in the background (see ThresholdConfig).
+	Thresholds *ThresholdConfig `json:"thresholds,omitempty"`
 	// Section groups widgets sharing the same (non-empty) value under a
 	// titled sub-section within the dashboard, in the order that value
 	// first appears among the dashboard's widgets — e.g. a handful of
@@ -312,6 +384,10 @@ type Dashboard struct {
 	// ever sees "filterPresets" or a {"preset": ...} reference: this field
 	// is cleared to nil once resolution has run (see finalize).
 	FilterPresets map[string]map[string]any `json:"filterPresets,omitempty"`
+
+	// Schedule, when set, also mails this dashboard out as a rendered
+	// snapshot report on a cron schedule (see ReportSchedule).
+	Schedule *ReportSchedule `json:"schedule,omitempty"`
 }
 
 // ParseDashboardsConfig decodes DASHBOARDS_CONFIG, a JSON array of Dashboard
@@ -493,6 +569,12 @@ func injectImpliedTypeFilters(d *Dashboard, source string) {
 			s := &w.Slices[si]
 			s.Query = injectTypeFilter(s.Query, w.ResourceType, source, d.ID, w.ID, s.Label)
 		}
+		if w.Trend != nil {
+			for si := range w.Trend.Series {
+				s := &w.Trend.Series[si]
+				s.Query = injectTypeFilter(s.Query, w.ResourceType, source, d.ID, w.ID, s.Label)
+			}
+		}
 	}
 }
 
@@ -688,6 +770,15 @@ func resolveDashboardFilterPresets(d *Dashboard, sharedPresets map[string]map[st
 				return err
 			}
 		}
+		if w.Trend != nil {
+			for si := range w.Trend.Series {
+				s := &w.Trend.Series[si]
+				sctx := fmt.Sprintf("%s: trend series %q", ctx, s.Label)
+				if err := resolveQueryPresets(s.Query, presets, sctx); err != nil {
+					return err
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -86,7 +86,7 @@ func New(teams []Team, roles []string) (*Directory, error) {
 		d.byGroupName[t.Name] = t
 		d.groupNames = append(d.groupNames, t.Name)
 
-		result := TeamResult{ID: t.Key, Name: t.Name, Family: string(t.Family)}
+		result := TeamResult{ID: t.Key, Name: t.Name, Family: string(t.Family), Assignment: string(t.Assignment)}
 		if t.CreGroupID != "" {
 			result.CreGroupID = sourceIDToUUID(t.CreGroupID)
 			if _, dup := d.byCreGroupID[result.CreGroupID]; dup {
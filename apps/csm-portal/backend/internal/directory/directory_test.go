@@ -292,7 +292,7 @@ func TestSearchTeams_FiltersOnFamily(t *testing.T) {
 		t.Fatalf("family=cre-abt matched %+v, want just alpha", got.Teams)
 	}
 	// Case-insensitive: the registry row spelled it "CRE-ABT" (see
-	// registryFixture), and parseFamily normalizes storage to lowercase, but a
+	// registryFixture), and ParseFamily normalizes storage to lowercase, but a
 	// caller filtering with either case must match.
 	if got := dir.SearchTeams(SearchRequest{Filters: SearchFilters{Family: "CRE-ABT"}}); got.Total != 1 {
 		t.Errorf("family=CRE-ABT (uppercase) matched %d teams, want 1", got.Total)
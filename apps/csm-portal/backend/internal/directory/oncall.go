@@ -0,0 +1,568 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package directory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LayerType is how a rotation layer hands off between its members.
+type LayerType string
+
+const (
+	// LayerDaily hands off to the next member every day at the handoff time.
+	LayerDaily LayerType = "daily"
+	// LayerWeekly hands off to the next member every week, on the weekday of
+	// the layer's start date, at the handoff time.
+	LayerWeekly LayerType = "weekly"
+	// LayerFollowTheSun covers each day with a sequence of regional windows,
+	// each in its own timezone with its own members; a window's members take
+	// a week each.
+	LayerFollowTheSun LayerType = "follow_the_sun"
+)
+
+// OnCallConfig is the on-call configuration file (see ParseOnCall).
+//
+// Unlike the team registry, rotations are a JSON file and not a single-line
+// variable: layers, windows and overrides nest, and an override changes far
+// more often than a team does. The file is still deployment configuration,
+// read once at startup.
+type OnCallConfig struct {
+	Rotations []RotationConfig `json:"rotations"`
+}
+
+// RotationConfig is one team's on-call rotation.
+type RotationConfig struct {
+	// TeamKey is the registry key of the team the rotation covers.
+	TeamKey string `json:"teamKey"`
+	// Timezone is the IANA zone handoff times and start dates are in, unless
+	// a layer or window names its own. Omitted means UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Layers are ordered lowest precedence first: where a later layer has
+	// somebody on call, it wins over every layer before it. A typical
+	// rotation is one weekly layer with a follow-the-sun or daily layer over
+	// it for the hours it covers.
+	Layers []LayerConfig `json:"layers"`
+	// Overrides put somebody on call for a fixed period, over every layer.
+	Overrides []OverrideConfig `json:"overrides,omitempty"`
+}
+
+// LayerConfig is one layer of a rotation.
+type LayerConfig struct {
+	Name string    `json:"name"`
+	Type LayerType `json:"type"`
+	// Start is the date ("2006-01-02") of the layer's first handoff. Nobody
+	// is on call on the layer before it. For a weekly layer it also fixes the
+	// handoff weekday.
+	Start string `json:"start"`
+	// Handoff is the "HH:MM" local time of every daily or weekly handoff.
+	// Omitted means midnight. Not used by follow-the-sun layers, whose
+	// windows carry their own times.
+	Handoff string `json:"handoff,omitempty"`
+	// Timezone overrides the rotation's.
+	Timezone string `json:"timezone,omitempty"`
+	// Members are engineer email addresses, on call in turn. Required for
+	// daily and weekly layers.
+	Members []string `json:"members,omitempty"`
+	// Windows are the follow-the-sun regions. Required for, and only for,
+	// follow-the-sun layers.
+	Windows []WindowConfig `json:"windows,omitempty"`
+}
+
+// WindowConfig is one region of a follow-the-sun layer: every day from From
+// to To, local time. A window whose To is earlier than its From runs past
+// midnight and belongs to the day it starts on.
+type WindowConfig struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Timezone string   `json:"timezone,omitempty"`
+	Members  []string `json:"members"`
+}
+
+// OverrideConfig puts Email on call from Start to End (RFC 3339).
+type OverrideConfig struct {
+	Email  string `json:"email"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Shift is a period one engineer is on call for a team.
+type Shift struct {
+	TeamKey string    `json:"teamKey"`
+	Email   string    `json:"email"`
+	Layer   string    `json:"layer,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	// Override reports the shift comes from an override rather than a layer.
+	Override bool   `json:"override,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// OnCall is the resolved, immutable set of rotations. A nil *OnCall is valid
+// and has nobody on call for any team, which is what a deployment without an
+// on-call file gets.
+type OnCall struct {
+	rotations map[string]*rotation
+}
+
+type rotation struct {
+	teamKey   string
+	layers    []*layer
+	overrides []override
+}
+
+type layer struct {
+	name     string
+	typ      LayerType
+	loc      *time.Location
+	startDay int64 // civil day number of the start date
+	handoff  int   // minutes after midnight
+	members  []string
+	windows  []window
+}
+
+type window struct {
+	loc      *time.Location
+	from, to int
+	members  []string
+}
+
+type override struct {
+	email      string
+	start, end time.Time
+	reason     string
+}
+
+// ParseOnCall decodes and validates the on-call configuration against the
+// resolved team registry. Every team key must be a registry team, every
+// timezone a known IANA zone and every member an email address.
+//
+// Errors name the rotation and layer at fault, and callers are expected to
+// treat them as fatal at startup, like a bad registry: a rotation that fails
+// to load silently pages nobody.
+func ParseOnCall(raw []byte, dir *Directory) (*OnCall, error) {
+	var cfg OnCallConfig
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("on-call config: %w", err)
+	}
+
+	o := &OnCall{rotations: make(map[string]*rotation, len(cfg.Rotations))}
+	for i, rc := range cfg.Rotations {
+		key := strings.TrimSpace(rc.TeamKey)
+		if _, ok := dir.TeamByKey(key); !ok {
+			return nil, fmt.Errorf("on-call config: rotation %d: teamKey %q is not in the team registry", i, rc.TeamKey)
+		}
+		if _, dup := o.rotations[key]; dup {
+			return nil, fmt.Errorf("on-call config: team %q has more than one rotation", key)
+		}
+		rot, err := compileRotation(key, rc)
+		if err != nil {
+			return nil, fmt.Errorf("on-call config: team %q: %w", key, err)
+		}
+		o.rotations[key] = rot
+	}
+	return o, nil
+}
+
+func compileRotation(key string, rc RotationConfig) (*rotation, error) {
+	loc, err := loadZone(rc.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	if len(rc.Layers) == 0 {
+		return nil, fmt.Errorf("no layers")
+	}
+	rot := &rotation{teamKey: key}
+	names := make(map[string]bool, len(rc.Layers))
+	for i, lc := range rc.Layers {
+		l, err := compileLayer(lc, loc)
+		if err != nil {
+			return nil, fmt.Errorf("layer %d: %w", i, err)
+		}
+		if names[l.name] {
+			return nil, fmt.Errorf("layer name %q is used more than once", l.name)
+		}
+		names[l.name] = true
+		rot.layers = append(rot.layers, l)
+	}
+	for i, oc := range rc.Overrides {
+		ov := override{email: strings.TrimSpace(oc.Email), reason: oc.Reason}
+		if !isEmail(ov.email) {
+			return nil, fmt.Errorf("override %d: %q is not an email address", i, oc.Email)
+		}
+		if ov.start, err = time.Parse(time.RFC3339, oc.Start); err != nil {
+			return nil, fmt.Errorf("override %d: start %q is not an RFC 3339 time", i, oc.Start)
+		}
+		if ov.end, err = time.Parse(time.RFC3339, oc.End); err != nil {
+			return nil, fmt.Errorf("override %d: end %q is not an RFC 3339 time", i, oc.End)
+		}
+		if !ov.end.After(ov.start) {
+			return nil, fmt.Errorf("override %d: end is not after start", i)
+		}
+		rot.overrides = append(rot.overrides, ov)
+	}
+	return rot, nil
+}
+
+func compileLayer(lc LayerConfig, rotationLoc *time.Location) (*layer, error) {
+	l := &layer{name: strings.TrimSpace(lc.Name), typ: lc.Type, loc: rotationLoc}
+	if l.name == "" {
+		return nil, fmt.Errorf("no name")
+	}
+	if lc.Timezone != "" {
+		loc, err := loadZone(lc.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		l.loc = loc
+	}
+	start, err := time.Parse(time.DateOnly, lc.Start)
+	if err != nil {
+		return nil, fmt.Errorf("start %q is not a YYYY-MM-DD date", lc.Start)
+	}
+	l.startDay = civilDay(start)
+
+	switch lc.Type {
+	case LayerDaily, LayerWeekly:
+		if len(lc.Windows) > 0 {
+			return nil, fmt.Errorf("windows are only for %q layers", LayerFollowTheSun)
+		}
+		if lc.Handoff != "" {
+			if l.handoff, err = parseHHMM(lc.Handoff); err != nil {
+				return nil, fmt.Errorf("handoff: %w", err)
+			}
+		}
+		if l.members, err = validMembers(lc.Members); err != nil {
+			return nil, err
+		}
+	case LayerFollowTheSun:
+		if len(lc.Members) > 0 || lc.Handoff != "" {
+			return nil, fmt.Errorf("a %q layer takes its members and times from its windows", LayerFollowTheSun)
+		}
+		if len(lc.Windows) == 0 {
+			return nil, fmt.Errorf("no windows")
+		}
+		for i, wc := range lc.Windows {
+			w := window{loc: l.loc}
+			if wc.Timezone != "" {
+				if w.loc, err = loadZone(wc.Timezone); err != nil {
+					return nil, fmt.Errorf("window %d: %w", i, err)
+				}
+			}
+			if w.from, err = parseHHMM(wc.From); err != nil {
+				return nil, fmt.Errorf("window %d: from: %w", i, err)
+			}
+			if w.to, err = parseHHMM(wc.To); err != nil {
+				return nil, fmt.Errorf("window %d: to: %w", i, err)
+			}
+			if w.from == w.to {
+				return nil, fmt.Errorf("window %d: from and to are the same time", i)
+			}
+			if w.members, err = validMembers(wc.Members); err != nil {
+				return nil, fmt.Errorf("window %d: %w", i, err)
+			}
+			l.windows = append(l.windows, w)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q: expected %q, %q or %q", lc.Type, LayerDaily, LayerWeekly, LayerFollowTheSun)
+	}
+	return l, nil
+}
+
+func loadZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("timezone %q is not a known IANA zone", name)
+	}
+	return loc, nil
+}
+
+func parseHHMM(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an HH:MM time", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func validMembers(members []string) ([]string, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no members")
+	}
+	out := make([]string, 0, len(members))
+	for _, m := range members {
+		m = strings.TrimSpace(m)
+		if !isEmail(m) {
+			return nil, fmt.Errorf("member %q is not an email address", m)
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// isEmail is a sanity check for hand-typed addresses, not RFC 5322
+// validation.
+func isEmail(s string) bool {
+	at := strings.Index(s, "@")
+	return at > 0 && at < len(s)-1 && !strings.ContainsAny(s, " ,;")
+}
+
+// civilDay is the number of days from the Unix epoch to t's calendar date in
+// t's own location, so dates in any zone can be counted between.
+func civilDay(t time.Time) int64 {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// at is minute minutes after midnight on civil day in loc. A time a DST
+// change skips is normalised forward, as time.Date does.
+func at(day int64, minute int, loc *time.Location) time.Time {
+	d := time.Unix(day*86400, 0).UTC()
+	return time.Date(d.Year(), d.Month(), d.Day(), minute/60, minute%60, 0, 0, loc)
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// HasRotation reports whether teamKey has an on-call rotation configured.
+func (o *OnCall) HasRotation(teamKey string) bool {
+	if o == nil {
+		return false
+	}
+	_, ok := o.rotations[teamKey]
+	return ok
+}
+
+// At returns who is on call for teamKey at t. ok is false when the team has
+// no rotation or nobody is on call at t. The shift's Start and End are the
+// winning layer's or override's own, which a later override may interrupt;
+// Timeline gives the effective periods.
+func (o *OnCall) At(teamKey string, t time.Time) (Shift, bool) {
+	if o == nil {
+		return Shift{}, false
+	}
+	rot, ok := o.rotations[teamKey]
+	if !ok {
+		return Shift{}, false
+	}
+	return rot.at(t)
+}
+
+// EmailAt is At reduced to the on-call engineer's email, the shape the
+// alert and report recipient lists resolve "__current_oncall__:<teamKey>"
+// with.
+func (o *OnCall) EmailAt(teamKey string, t time.Time) (string, bool) {
+	s, ok := o.At(teamKey, t)
+	return s.Email, ok
+}
+
+// Timeline returns the effective on-call periods for teamKey between from and
+// to, in order, each clipped to the range and to whatever overrides it.
+func (o *OnCall) Timeline(teamKey string, from, to time.Time) []Shift {
+	if o == nil {
+		return nil
+	}
+	rot, ok := o.rotations[teamKey]
+	if !ok {
+		return nil
+	}
+	return rot.timeline(from, to)
+}
+
+// ShiftsFor returns email's effective shifts on every team between from and
+// to, ordered by start. email is compared case-insensitively.
+func (o *OnCall) ShiftsFor(email string, from, to time.Time) []Shift {
+	if o == nil {
+		return nil
+	}
+	var out []Shift
+	for _, rot := range o.rotations {
+		for _, s := range rot.timeline(from, to) {
+			if strings.EqualFold(s.Email, email) {
+				out = append(out, s)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Start.Equal(out[j].Start) {
+			return out[i].Start.Before(out[j].Start)
+		}
+		return out[i].TeamKey < out[j].TeamKey
+	})
+	return out
+}
+
+func (r *rotation) at(t time.Time) (Shift, bool) {
+	// The last matching override wins, so a later entry can correct an
+	// earlier one without editing it.
+	for i := len(r.overrides) - 1; i >= 0; i-- {
+		ov := r.overrides[i]
+		if !t.Before(ov.start) && t.Before(ov.end) {
+			return Shift{TeamKey: r.teamKey, Email: ov.email, Start: ov.start, End: ov.end, Override: true, Reason: ov.reason}, true
+		}
+	}
+	for i := len(r.layers) - 1; i >= 0; i-- {
+		if s, ok := r.layers[i].at(t); ok {
+			s.TeamKey = r.teamKey
+			return s, true
+		}
+	}
+	return Shift{}, false
+}
+
+// timeline cuts [from, to) at every point who is on call can change, resolves
+// each piece and merges neighbours with the same engineer and source.
+func (r *rotation) timeline(from, to time.Time) []Shift {
+	if !to.After(from) {
+		return nil
+	}
+	cuts := []time.Time{from, to}
+	for _, ov := range r.overrides {
+		cuts = append(cuts, ov.start, ov.end)
+	}
+	for _, l := range r.layers {
+		cuts = append(cuts, l.boundaries(from, to)...)
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].Before(cuts[j]) })
+
+	var out []Shift
+	for i := 0; i+1 < len(cuts); i++ {
+		a, b := cuts[i], cuts[i+1]
+		if a.Before(from) || !b.After(a) || b.After(to) {
+			continue
+		}
+		s, ok := r.at(a)
+		if !ok {
+			continue
+		}
+		if n := len(out); n > 0 {
+			last := &out[n-1]
+			if last.End.Equal(a) && last.Email == s.Email && last.Layer == s.Layer && last.Override == s.Override {
+				last.End = b
+				continue
+			}
+		}
+		s.Start, s.End = a, b
+		out = append(out, s)
+	}
+	return out
+}
+
+func (l *layer) periodDays() int64 {
+	if l.typ == LayerWeekly {
+		return 7
+	}
+	return 1
+}
+
+func (l *layer) at(t time.Time) (Shift, bool) {
+	if l.typ == LayerFollowTheSun {
+		return l.windowAt(t)
+	}
+	day := civilDay(t.In(l.loc))
+	if t.Before(at(day, l.handoff, l.loc)) {
+		day--
+	}
+	if day < l.startDay {
+		return Shift{}, false
+	}
+	period := l.periodDays()
+	n := (day - l.startDay) / period
+	first := l.startDay + n*period
+	return Shift{
+		Email: l.members[n%int64(len(l.members))],
+		Layer: l.name,
+		Start: at(first, l.handoff, l.loc),
+		End:   at(first+period, l.handoff, l.loc),
+	}, true
+}
+
+// windowAt finds the window covering t. Windows are checked last first, so
+// where two overlap the later one wins, as layers do.
+func (l *layer) windowAt(t time.Time) (Shift, bool) {
+	for i := len(l.windows) - 1; i >= 0; i-- {
+		w := l.windows[i]
+		today := civilDay(t.In(w.loc))
+		for _, day := range []int64{today, today - 1} {
+			start, end := w.span(day)
+			if day < l.startDay || t.Before(start) || !t.Before(end) {
+				continue
+			}
+			week := floorDiv(day-l.startDay, 7)
+			return Shift{
+				Email: w.members[week%int64(len(w.members))],
+				Layer: l.name,
+				Start: start,
+				End:   end,
+			}, true
+		}
+	}
+	return Shift{}, false
+}
+
+// span is the window's occurrence starting on civil day.
+func (w window) span(day int64) (time.Time, time.Time) {
+	endDay := day
+	if w.to < w.from {
+		endDay++
+	}
+	return at(day, w.from, w.loc), at(endDay, w.to, w.loc)
+}
+
+// boundaries lists the layer's handoffs and window edges inside [from, to).
+func (l *layer) boundaries(from, to time.Time) []time.Time {
+	var out []time.Time
+	if l.typ == LayerFollowTheSun {
+		for _, w := range l.windows {
+			for day := civilDay(from.In(w.loc)) - 1; day <= civilDay(to.In(w.loc)); day++ {
+				start, end := w.span(day)
+				out = append(out, start, end)
+			}
+		}
+		return out
+	}
+	period := l.periodDays()
+	day := civilDay(from.In(l.loc)) - period
+	if day < l.startDay {
+		day = l.startDay
+	} else {
+		day = l.startDay + (day-l.startDay)/period*period
+	}
+	for ; ; day += period {
+		t := at(day, l.handoff, l.loc)
+		if !t.Before(to) {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
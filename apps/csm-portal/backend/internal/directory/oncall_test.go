@@ -0,0 +1,190 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package directory
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const onCallFixture = `{"rotations": [
+  {
+    "teamKey": "alpha",
+    "timezone": "Europe/London",
+    "layers": [
+      {"name": "primary", "type": "weekly", "start": "2026-01-05", "handoff": "09:00",
+       "members": ["a@example.com", "b@example.com"]}
+    ],
+    "overrides": [
+      {"email": "c@example.com", "start": "2026-01-07T00:00:00Z", "end": "2026-01-08T00:00:00Z", "reason": "swap"}
+    ]
+  },
+  {
+    "teamKey": "beta",
+    "layers": [
+      {"name": "sun", "type": "follow_the_sun", "start": "2026-01-05", "windows": [
+        {"timezone": "Asia/Colombo", "from": "08:00", "to": "16:00", "members": ["x1@example.com", "x2@example.com"]},
+        {"from": "10:30", "to": "02:30", "members": ["y@example.com"]}
+      ]}
+    ]
+  }
+]}`
+
+func mustOnCall(t *testing.T) *OnCall {
+	t.Helper()
+	o, err := ParseOnCall([]byte(onCallFixture), mustDirectory(t, registryFixture, ""))
+	if err != nil {
+		t.Fatalf("ParseOnCall: %v", err)
+	}
+	return o
+}
+
+func ts(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestOnCallAt_WeeklyLayer(t *testing.T) {
+	o := mustOnCall(t)
+	cases := []struct {
+		at, want string
+	}{
+		{"2026-01-05T08:59:00Z", ""}, // before the first handoff
+		{"2026-01-05T09:00:00Z", "a@example.com"},
+		{"2026-01-12T09:00:00Z", "b@example.com"},
+		{"2026-01-19T10:00:00Z", "a@example.com"},
+		// 09:00 BST is 08:00 UTC: the handoff follows local time across DST.
+		{"2026-07-06T08:30:00Z", "a@example.com"},
+		{"2026-07-06T07:30:00Z", "b@example.com"},
+		{"2026-01-07T12:00:00Z", "c@example.com"}, // override
+	}
+	for _, tc := range cases {
+		s, ok := o.At("alpha", ts(tc.at))
+		if tc.want == "" {
+			if ok {
+				t.Errorf("At(%s) = %+v, want nobody", tc.at, s)
+			}
+			continue
+		}
+		if !ok || s.Email != tc.want {
+			t.Errorf("At(%s) = %+v/%v, want %s", tc.at, s, ok, tc.want)
+		}
+	}
+
+	s, _ := o.At("alpha", ts("2026-01-06T00:00:00Z"))
+	if !s.Start.Equal(ts("2026-01-05T09:00:00Z")) || !s.End.Equal(ts("2026-01-12T09:00:00Z")) || s.Layer != "primary" {
+		t.Errorf("shift = %+v, want the primary layer's first week", s)
+	}
+	if s, _ := o.At("alpha", ts("2026-01-07T12:00:00Z")); !s.Override || s.Reason != "swap" {
+		t.Errorf("override shift = %+v", s)
+	}
+}
+
+func TestOnCallAt_FollowTheSun(t *testing.T) {
+	o := mustOnCall(t)
+	cases := []struct {
+		at, want string
+	}{
+		{"2026-01-05T03:00:00Z", "x1@example.com"}, // 08:30 in Colombo
+		{"2026-01-12T03:00:00Z", "x2@example.com"}, // next week's member
+		{"2026-01-06T01:00:00Z", "y@example.com"},  // the overnight window started yesterday
+		{"2026-01-05T01:00:00Z", ""},               // that window started before the layer did
+	}
+	for _, tc := range cases {
+		s, ok := o.At("beta", ts(tc.at))
+		if ok != (tc.want != "") || s.Email != tc.want {
+			t.Errorf("At(%s) = %+v/%v, want %q", tc.at, s, ok, tc.want)
+		}
+	}
+	if _, ok := o.At("gamma", ts("2026-01-06T01:00:00Z")); ok {
+		t.Error("a team without a rotation has somebody on call")
+	}
+	var none *OnCall
+	if _, ok := none.At("alpha", time.Now()); ok || none.HasRotation("alpha") {
+		t.Error("a nil OnCall has somebody on call")
+	}
+}
+
+func TestOnCallTimeline_ClipsAndMerges(t *testing.T) {
+	got := mustOnCall(t).Timeline("alpha", ts("2026-01-06T00:00:00Z"), ts("2026-01-13T00:00:00Z"))
+	want := []struct{ email, start, end string }{
+		{"a@example.com", "2026-01-06T00:00:00Z", "2026-01-07T00:00:00Z"},
+		{"c@example.com", "2026-01-07T00:00:00Z", "2026-01-08T00:00:00Z"},
+		{"a@example.com", "2026-01-08T00:00:00Z", "2026-01-12T09:00:00Z"},
+		{"b@example.com", "2026-01-12T09:00:00Z", "2026-01-13T00:00:00Z"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Timeline = %+v, want %d shifts", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Email != w.email || !got[i].Start.Equal(ts(w.start)) || !got[i].End.Equal(ts(w.end)) {
+			t.Errorf("shift %d = %s %s-%s, want %s %s-%s", i, got[i].Email, got[i].Start, got[i].End, w.email, w.start, w.end)
+		}
+	}
+}
+
+func TestOnCallShiftsFor(t *testing.T) {
+	got := mustOnCall(t).ShiftsFor("Y@example.com", ts("2026-01-05T00:00:00Z"), ts("2026-01-07T00:00:00Z"))
+	if len(got) != 2 {
+		t.Fatalf("ShiftsFor = %+v, want the two overnight windows", got)
+	}
+	if got[0].TeamKey != "beta" || !got[0].Start.Equal(ts("2026-01-05T10:30:00Z")) || !got[0].End.Equal(ts("2026-01-06T02:30:00Z")) {
+		t.Errorf("first shift = %+v", got[0])
+	}
+}
+
+func TestParseOnCall_Rejects(t *testing.T) {
+	dir := mustDirectory(t, registryFixture, "")
+	cases := map[string]string{
+		"unknown team":   `{"rotations":[{"teamKey":"nope","layers":[{"name":"l","type":"daily","start":"2026-01-05","members":["a@example.com"]}]}]}`,
+		"duplicate team": `{"rotations":[{"teamKey":"alpha","layers":[{"name":"l","type":"daily","start":"2026-01-05","members":["a@example.com"]}]},{"teamKey":"alpha","layers":[{"name":"l","type":"daily","start":"2026-01-05","members":["a@example.com"]}]}]}`,
+		"bad timezone":   `{"rotations":[{"teamKey":"alpha","timezone":"Mars/Base","layers":[{"name":"l","type":"daily","start":"2026-01-05","members":["a@example.com"]}]}]}`,
+		"bad type":       `{"rotations":[{"teamKey":"alpha","layers":[{"name":"l","type":"monthly","start":"2026-01-05","members":["a@example.com"]}]}]}`,
+		"no members":     `{"rotations":[{"teamKey":"alpha","layers":[{"name":"l","type":"weekly","start":"2026-01-05"}]}]}`,
+		"bad member":     `{"rotations":[{"teamKey":"alpha","layers":[{"name":"l","type":"weekly","start":"2026-01-05","members":["Jane Doe"]}]}]}`,
+		"bad handoff":    `{"rotations":[{"teamKey":"alpha","layers":[{"name":"l","type":"weekly","start":"2026-01-05","handoff":"9am","members":["a@example.com"]}]}]}`,
+		"no layers":      `{"rotations":[{"teamKey":"alpha","layers":[]}]}`,
+		"empty window":   `{"rotations":[{"teamKey":"alpha","layers":[{"name":"l","type":"follow_the_sun","start":"2026-01-05","windows":[{"from":"09:00","to":"09:00","members":["a@example.com"]}]}]}]}`,
+		"backwards":      `{"rotations":[{"teamKey":"alpha","layers":[{"name":"l","type":"daily","start":"2026-01-05","members":["a@example.com"]}],"overrides":[{"email":"a@example.com","start":"2026-01-06T00:00:00Z","end":"2026-01-05T00:00:00Z"}]}]}`,
+		"unknown field":  `{"rotations":[{"teamKey":"alpha","team":"x","layers":[]}]}`,
+	}
+	for name, raw := range cases {
+		if _, err := ParseOnCall([]byte(raw), dir); err == nil {
+			t.Errorf("%s: ParseOnCall accepted %s", name, raw)
+		} else if !strings.HasPrefix(err.Error(), "on-call config") {
+			t.Errorf("%s: error %q does not say where it came from", name, err)
+		}
+	}
+}
+
+// The committed oncall.example.json is what .env.example points
+// CSM_ONCALL_FILE at, so it must load against the .env.example registry's
+// team keys.
+func TestParseOnCall_Example(t *testing.T) {
+	raw, err := os.ReadFile("../../oncall.example.json")
+	if err != nil {
+		t.Fatalf("read example: %v", err)
+	}
+	if _, err := ParseOnCall(raw, mustDirectory(t, registryFixture, "")); err != nil {
+		t.Fatalf("ParseOnCall(example): %v", err)
+	}
+}
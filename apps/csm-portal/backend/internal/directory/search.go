@@ -65,6 +65,9 @@ type TeamResult struct {
 	// filter. Omitted when the registry configured no backing SRE group id
 	// for this team -- the team is still listed, just not filter-scopable.
 	SreGroupID string `json:"sreGroupId,omitempty"`
+	// Assignment is the team's automatic case assignment strategy. Omitted
+	// when its cases are assigned by hand.
+	Assignment string `json:"assignment,omitempty"`
 }
 
 // SearchTeamsResponse is the paginated result of a team search.
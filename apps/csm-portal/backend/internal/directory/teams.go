@@ -109,22 +109,61 @@ type Team struct {
 	// is optional and independent -- a team like an SRE ABT may configure only
 	// this one, with no CreGroupID at all.
 	SreGroupID string
+	// Assignment is how new cases in this team's queue are assigned to its
+	// engineers (see internal/assignment). Empty means they are not: the team
+	// is left to hand-pick, and POST /cases/{id}/auto-assign refuses.
+	Assignment AssignmentStrategy
+}
+
+// AssignmentStrategy is how a team's cases are automatically assigned.
+type AssignmentStrategy string
+
+const (
+	// AssignRoundRobin takes the team's engineers in turn.
+	AssignRoundRobin AssignmentStrategy = "round_robin"
+	// AssignLeastLoaded picks the engineer with the lightest ongoing
+	// workload, each case weighted by its severity.
+	AssignLeastLoaded AssignmentStrategy = "least_loaded"
+	// AssignSkills picks the least-loaded engineer skilled in the case's
+	// product, falling back to the whole team when nobody is.
+	AssignSkills AssignmentStrategy = "skills"
+)
+
+// ParseAssignmentStrategy normalizes a configured strategy (in any case). An
+// empty value is legal and yields no automatic assignment; any other unknown
+// value is an error, for the same reason an unknown family is: a typo would
+// otherwise quietly leave the team's queue unassigned.
+func ParseAssignmentStrategy(raw string) (AssignmentStrategy, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", nil
+	}
+	switch s := AssignmentStrategy(strings.ToLower(trimmed)); s {
+	case AssignRoundRobin, AssignLeastLoaded, AssignSkills:
+		return s, nil
+	}
+	return "", fmt.Errorf(
+		"unknown assignment strategy %q: expected one of %q, %q, %q, or empty",
+		trimmed, AssignRoundRobin, AssignLeastLoaded, AssignSkills)
 }
 
 // ParseTeamRegistry parses the team registry from its flat, single-line
 // configuration form:
 //
-//	teamKey|Display Name|FAMILY|creGroupId|sreGroupId,teamKey|Display Name,...
+//	teamKey|Display Name|FAMILY|creGroupId|sreGroupId|assignment,teamKey|Display Name,...
 //
 // Rows are separated by ",", fields within a row by "|". A row carries two
 // fields (key and display name), three (plus the family), four (plus the
-// backing CRE group's id), or five (plus the backing SRE group's id). family
+// backing CRE group's id), five (plus the backing SRE group's id), or six
+// (plus the case assignment strategy, see ParseAssignmentStrategy). family
 // is a real optional middle field, not a slot that can be skipped: a group id
 // cannot be supplied without a family alongside it, so a 2-field-plus-id shape
 // is not accepted -- pad the family field (even empty, e.g. "key|Name||id")
 // if a team needs an id but no family. Likewise, an sreGroupId cannot be
 // supplied without a creGroupId slot alongside it (even empty, e.g.
-// "key|Name|FAMILY||sreId") since fields are positional. Whitespace around
+// "key|Name|FAMILY||sreId") since fields are positional, and an assignment
+// strategy needs every slot before it ("key|Name|||least_loaded" is not a row;
+// "key|Name||||least_loaded" is). Whitespace around
 // every field is trimmed, so a value pasted into a web form survives. A
 // wholly blank row is skipped, which tolerates a trailing comma.
 //
@@ -145,9 +184,9 @@ func ParseTeamRegistry(raw string) ([]Team, error) {
 		}
 
 		fields := strings.Split(row, "|")
-		if len(fields) < 2 || len(fields) > 5 {
+		if len(fields) < 2 || len(fields) > 6 {
 			return nil, fmt.Errorf(
-				"team registry row %d (%q): expected 2, 3, 4, or 5 %q-separated fields (teamKey|displayName[|family[|creGroupId[|sreGroupId]]]), got %d",
+				"team registry row %d (%q): expected 2 to 6 %q-separated fields (teamKey|displayName[|family[|creGroupId[|sreGroupId[|assignment]]]]), got %d",
 				i+1, strings.TrimSpace(row), "|", len(fields))
 		}
 		for j := range fields {
@@ -166,7 +205,7 @@ func ParseTeamRegistry(raw string) ([]Team, error) {
 
 		team := Team{Key: fields[0], Name: fields[1]}
 		if len(fields) >= 3 {
-			family, err := parseFamily(fields[2])
+			family, err := ParseFamily(fields[2])
 			if err != nil {
 				return nil, fmt.Errorf("team registry row %d (%q): %w", i+1, strings.TrimSpace(row), err)
 			}
@@ -184,13 +223,20 @@ func ParseTeamRegistry(raw string) ([]Team, error) {
 			}
 			team.CreGroupID = fields[3]
 		}
-		if len(fields) == 5 {
+		if len(fields) >= 5 {
 			// Same validation, same reasoning, for the parallel sreTeam filter.
 			if err := validateGroupID(fields[4]); err != nil {
 				return nil, fmt.Errorf("team registry row %d (%q): %w", i+1, strings.TrimSpace(row), err)
 			}
 			team.SreGroupID = fields[4]
 		}
+		if len(fields) == 6 {
+			strategy, err := ParseAssignmentStrategy(fields[5])
+			if err != nil {
+				return nil, fmt.Errorf("team registry row %d (%q): %w", i+1, strings.TrimSpace(row), err)
+			}
+			team.Assignment = strategy
+		}
 		teams = append(teams, team)
 	}
 
@@ -212,7 +258,7 @@ func validateGroupID(id string) error {
 	return nil
 }
 
-// parseFamily normalizes a configured family value (in any case, e.g.
+// ParseFamily normalizes a configured family value (in any case, e.g.
 // "SRE-ABT") into this package's lowercase Family constants. An empty value is
 // legal and yields the empty family: not every team is classified.
 //
@@ -220,7 +266,7 @@ func validateGroupID(id string) error {
 // team picker and default-dashboard selection both branch on the family, so an
 // unrecognised value silently removes a team from every picker instead of
 // erroring anywhere. A rejected deploy is the cheaper failure.
-func parseFamily(family string) (Family, error) {
+func ParseFamily(family string) (Family, error) {
 	trimmed := strings.TrimSpace(family)
 	if trimmed == "" {
 		return "", nil
@@ -16,7 +16,10 @@
 
 package directory
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // Regression: a supplied creGroupId was stored unvalidated. sourceIDToUUID
 // passes anything that is not exactly 32 hex characters through unchanged, so
@@ -92,3 +95,24 @@ func TestParseTeamRegistry_RejectsMalformedSreGroupID(t *testing.T) {
 		t.Fatal("ParseTeamRegistry returned no error, want a rejection of the malformed sreGroupId")
 	}
 }
+
+// A 6th field sets the team's assignment strategy, in any case; an unknown
+// one is rejected naming the row, and a 7th field is still too many.
+func TestParseTeamRegistry_AssignmentStrategy(t *testing.T) {
+	teams, err := ParseTeamRegistry("castor|Castor||||Least_Loaded,vega|Vega|sre-abt|||")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := teams[0].Assignment; got != AssignLeastLoaded {
+		t.Errorf("castor Assignment = %q, want %q", got, AssignLeastLoaded)
+	}
+	if got := teams[1].Assignment; got != "" {
+		t.Errorf("vega Assignment = %q, want empty", got)
+	}
+
+	for _, raw := range []string{"castor|Castor||||fastest", "castor|Castor||||skills|extra"} {
+		if _, err := ParseTeamRegistry(raw); err == nil || !strings.Contains(err.Error(), "row 1") {
+			t.Errorf("ParseTeamRegistry(%q) error = %v, want a rejection naming row 1", raw, err)
+		}
+	}
+}
@@ -182,6 +182,14 @@ func (c *CustomerEntityClient) AggregateIncidents(ctx context.Context, body []by
 	return c.do(ctx, http.MethodPost, "/incidents/aggregate", body)
 }
 
+// IncidentMetrics calls POST /incidents/metrics on the entity service: mean
+// time to acknowledge, mean time to resolve and reopen rate over a createdOn
+// range, as a weekly or monthly series and optionally broken down by one
+// field. Response is returned as raw JSON; typed response structs are deferred.
+func (c *CustomerEntityClient) IncidentMetrics(ctx context.Context, body []byte) ([]byte, error) {
+	return c.do(ctx, http.MethodPost, "/incidents/metrics", body)
+}
+
 // CreateIncident calls POST /incidents on the entity service to create a new incident.
 // Response is returned as raw JSON; typed response structs are deferred.
 func (c *CustomerEntityClient) CreateIncident(ctx context.Context, body []byte) ([]byte, error) {
@@ -445,6 +453,19 @@ func (c *CustomerEntityClient) SearchServiceOfferings(ctx context.Context, body
 	return c.do(ctx, http.MethodPost, "/service-offerings/search", body)
 }
 
+// ListCalendars calls GET /calendars on the entity service.
+// Response is returned as raw JSON.
+func (c *CustomerEntityClient) ListCalendars(ctx context.Context) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, "/calendars", nil)
+}
+
+// AddBusinessTime calls POST /calendars/{id}/add-business-time on the entity
+// service: when the given business time after a start runs out on that
+// calendar. Response is returned as raw JSON.
+func (c *CustomerEntityClient) AddBusinessTime(ctx context.Context, calendarID string, body []byte) ([]byte, error) {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/calendars/%s/add-business-time", url.PathEscape(calendarID)), body)
+}
+
 // SearchGroups calls POST /groups/search on the entity service.
 // Response is returned as raw JSON.
 func (c *CustomerEntityClient) SearchGroups(ctx context.Context, body []byte) ([]byte, error) {
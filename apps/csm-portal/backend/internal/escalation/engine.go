@@ -0,0 +1,510 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package escalation pages people about high-severity cases nobody has
+// acknowledged. Each Policy covers cases of some severities and account tiers
+// and lists steps -- notify the assigned team, then its lead, then the on-call
+// manager -- each due a number of minutes after the case started waiting.
+//
+// The Engine polls for open cases under a policy and emails each step's
+// recipients as it falls due, until the case is acknowledged
+// (CaseView.acknowledgedBy). A severity change clears the acknowledgement
+// upstream, so it restarts the escalation too. Where every case is, and an
+// audit trail of what was sent to whom, is kept in a Store persisted across
+// restarts, so a restart neither re-pages nor forgets a snooze.
+package escalation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// callTimeout bounds each entity or email call the Engine makes.
+const callTimeout = 30 * time.Second
+
+// createdLookback is how far before the previous poll a case may have been
+// created and still be timed from its creation when first seen; a case older
+// than that was already waiting before the engine could see it, and is timed
+// from when it was.
+const createdLookback = 2 * time.Minute
+
+const (
+	// casePageLimit is the case search's own maximum page size.
+	casePageLimit = 100
+	// userPageLimit is the user search's own maximum page size.
+	userPageLimit = 50
+	// maxPages caps how many pages of cases or team members are read.
+	maxPages = 10
+	// MaxSnooze is the longest a single snooze may last.
+	MaxSnooze = 24 * time.Hour
+)
+
+// activeStates are the case states in which WSO2 owes the customer a response,
+// and so in which an unacknowledged case escalates.
+var activeStates = []string{"open", "reopened", "work_in_progress", "waiting_on_wso2"}
+
+var (
+	// ErrNotConfigured is returned when no escalation policies are configured.
+	ErrNotConfigured = errors.New("escalation: no escalation policies are configured")
+	// ErrNotEscalating is returned for a case under no policy.
+	ErrNotEscalating = errors.New("escalation: the case is not being escalated")
+	// ErrAcknowledged is returned for a case already acknowledged.
+	ErrAcknowledged = errors.New("escalation: the case is already acknowledged")
+)
+
+// entityClient abstracts the entity service calls the Engine makes.
+type entityClient interface {
+	SearchCases(ctx context.Context, body []byte) ([]byte, error)
+	GetCase(ctx context.Context, id string) ([]byte, error)
+	SearchUsers(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// EmailNotifier sends escalation emails.
+type EmailNotifier interface {
+	SendEmail(ctx context.Context, to, cc, bcc, replyTo []string, subject, htmlBody string, attachments []notifications.EmailAttachment) error
+}
+
+// Config holds the Engine's settings.
+type Config struct {
+	// Interval is how often open cases are checked.
+	Interval time.Duration
+	// PortalBaseURL is the CSM portal webapp base URL emails link into.
+	PortalBaseURL string
+}
+
+// Engine escalates unacknowledged cases by their policies. Every replica
+// running it pages; run it on one. A nil Engine escalates nothing and
+// answers every call with ErrNotConfigured or nothing.
+type Engine struct {
+	entity entityClient
+	email  EmailNotifier
+	def    *Definition
+	dir    *directory.Directory
+	oncall *directory.OnCall
+	store  *Store
+	cfg    Config
+	now    func() time.Time
+
+	// mu serialises polls.
+	mu sync.Mutex
+}
+
+// NewEngine creates an Engine escalating by def and keeping its state in
+// store.
+func NewEngine(entity entityClient, email EmailNotifier, def *Definition, dir *directory.Directory, oncall *directory.OnCall, store *Store, cfg Config) *Engine {
+	return &Engine{entity: entity, email: email, def: def, dir: dir, oncall: oncall, store: store, cfg: cfg, now: time.Now}
+}
+
+// caseInfo is the part of a case the Engine reads.
+type caseInfo struct {
+	ID           string `json:"id"`
+	Number       string `json:"number"`
+	Subject      string `json:"subject"`
+	Severity     string `json:"severity"`
+	CreatedOn    string `json:"createdOn"`
+	AssignedTeam *struct {
+		Name string `json:"name"`
+	} `json:"assignedTeam"`
+	Account *struct {
+		Type string `json:"type"`
+	} `json:"account"`
+	// AcknowledgedBy is only set on GET /cases/{id}.
+	AcknowledgedBy *struct {
+		Email string `json:"email"`
+		Name  string `json:"name"`
+	} `json:"acknowledgedBy"`
+}
+
+func (c caseInfo) tier() string {
+	if c.Account == nil {
+		return ""
+	}
+	return c.Account.Type
+}
+
+func (c caseInfo) teamName() string {
+	if c.AssignedTeam == nil {
+		return ""
+	}
+	return c.AssignedTeam.Name
+}
+
+// Run polls every interval until ctx is cancelled.
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		e.PollOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollOnce reads the open cases under some policy and fires each one's next
+// step if it is due and the case is still unacknowledged. A case that has
+// left every policy since the previous poll has its escalation ended.
+func (e *Engine) PollOnce(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	last := e.store.lastPoll()
+	cases, complete, err := e.openCases(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "escalation: case search failed", "err", err)
+		return
+	}
+	seen := make(map[string]bool, len(cases))
+	for _, c := range cases {
+		seen[c.ID] = true
+		e.evaluate(ctx, c, now, last)
+	}
+
+	_ = e.store.update(func(d *storeFile) ([]Entry, error) {
+		d.LastPoll = now
+		if !complete {
+			// Cases beyond the pages read are not gone, only unread.
+			return nil, nil
+		}
+		var entries []Entry
+		for id, st := range d.Cases {
+			if seen[id] {
+				continue
+			}
+			delete(d.Cases, id)
+			entries = append(entries, Entry{
+				CaseID: id, CaseNumber: st.CaseNumber, Policy: st.Policy, Action: ActionEnded,
+				Reason: "The case is no longer open at a severity under a policy.", At: now.UTC(),
+			})
+		}
+		return entries, nil
+	})
+}
+
+// evaluate moves one case on through its escalation.
+func (e *Engine) evaluate(ctx context.Context, c caseInfo, now, lastPoll time.Time) {
+	p := e.def.Match(c.Severity, c.tier())
+	snap, known := e.store.Case(c.ID)
+	if p == nil {
+		if known {
+			e.end(c, snap, now, fmt.Sprintf("Severity %s and account tier %q are under no policy.", c.Severity, c.tier()))
+		}
+		return
+	}
+
+	st := snap
+	var entries []Entry
+	severity := strings.ToLower(c.Severity)
+	switch {
+	case !known:
+		st = CaseState{CaseID: c.ID, CaseNumber: c.Number, Policy: p.Name, Severity: severity, Since: now.UTC()}
+		// A case created since the previous poll (or while the engine was
+		// down) has been waiting since it was created; an older one only
+		// since it came under a policy, which is now as far as anyone knows.
+		if created, err := time.Parse(time.RFC3339, c.CreatedOn); err == nil && !lastPoll.IsZero() &&
+			!created.Before(lastPoll.Add(-createdLookback)) && created.Before(now) {
+			st.Since = created.UTC()
+		}
+		entries = append(entries, Entry{Action: ActionStarted, Reason: fmt.Sprintf("Severity %s.", c.Severity)})
+	case snap.Severity != severity || snap.Policy != p.Name:
+		st = CaseState{CaseID: c.ID, CaseNumber: c.Number, Policy: p.Name, Severity: severity, Since: now.UTC(),
+			SnoozedAt: snap.SnoozedAt, SnoozedUntil: snap.SnoozedUntil, SnoozedBy: snap.SnoozedBy}
+		entries = append(entries, Entry{Action: ActionRestarted, Reason: fmt.Sprintf("Severity changed from %s to %s.", snap.Severity, c.Severity)})
+		if snap.Severity == severity {
+			entries[len(entries)-1].Reason = "The case's account tier moved it to this policy."
+		}
+	}
+
+	if !st.Acknowledged {
+		entries = append(entries, e.advance(ctx, c, p, &st, now)...)
+	}
+	if len(entries) == 0 && st == snap {
+		return
+	}
+	for i := range entries {
+		entries[i].CaseID, entries[i].CaseNumber, entries[i].Policy, entries[i].At = c.ID, c.Number, p.Name, now.UTC()
+	}
+	_ = e.store.update(func(d *storeFile) ([]Entry, error) {
+		// A snooze recorded while the case was being evaluated wins.
+		if cur := d.Cases[c.ID]; cur != nil && !cur.SnoozedUntil.Equal(snap.SnoozedUntil) {
+			st.SnoozedAt, st.SnoozedUntil, st.SnoozedBy = cur.SnoozedAt, cur.SnoozedUntil, cur.SnoozedBy
+		}
+		d.Cases[c.ID] = &st
+		return entries, nil
+	})
+}
+
+// advance resumes st from a snooze that has run out and fires its next step
+// if it is due, returning what it did.
+func (e *Engine) advance(ctx context.Context, c caseInfo, p *Policy, st *CaseState, now time.Time) []Entry {
+	var entries []Entry
+	if !st.SnoozedUntil.IsZero() {
+		if now.Before(st.SnoozedUntil) {
+			return nil
+		}
+		// A snooze pauses the clock rather than skipping the steps it covered.
+		st.Since = st.Since.Add(st.SnoozedUntil.Sub(st.SnoozedAt))
+		st.SnoozedAt, st.SnoozedUntil, st.SnoozedBy = time.Time{}, time.Time{}, ""
+		entries = append(entries, Entry{Action: ActionResumed})
+	}
+	if st.Fired >= len(p.Steps) {
+		return entries
+	}
+	step := p.Steps[st.Fired]
+	if now.Before(st.Since.Add(time.Duration(step.AfterMinutes) * time.Minute)) {
+		return entries
+	}
+
+	// Search results carry no acknowledgement, so the case is read again
+	// before anyone is paged.
+	raw, err := e.call(ctx, func(ctx context.Context) ([]byte, error) { return e.entity.GetCase(ctx, c.ID) })
+	if err != nil {
+		slog.WarnContext(ctx, "escalation: could not read case; retrying next poll", "caseID", c.ID, "err", err)
+		return entries
+	}
+	var detail caseInfo
+	if err := json.Unmarshal(raw, &detail); err != nil {
+		slog.WarnContext(ctx, "escalation: could not decode case; retrying next poll", "caseID", c.ID, "err", err)
+		return entries
+	}
+	if detail.AcknowledgedBy != nil {
+		st.Acknowledged = true
+		return append(entries, Entry{Action: ActionAcknowledged, By: detail.AcknowledgedBy.Email})
+	}
+
+	entry := Entry{Step: st.Fired + 1, Target: step.Notify}
+	recipients, err := e.recipients(ctx, step.Notify, c, now)
+	if err != nil {
+		// Nobody to page is not going to fix itself by the next poll, so the
+		// escalation moves on to its next step rather than stalling here.
+		entry.Action, entry.Error = ActionFailed, err.Error()
+		st.Fired++
+		slog.WarnContext(ctx, "escalation: step has nobody to notify", "caseID", c.ID, "policy", p.Name, "step", entry.Step, "err", err)
+		return append(entries, entry)
+	}
+	entry.Recipients = recipients
+	waited := now.Sub(st.Since)
+	subject, body, err := render(c, p, st.Fired, waited, e.caseURL(c.ID))
+	if err == nil {
+		err = e.sendEmail(ctx, recipients, subject, body)
+	}
+	if err != nil {
+		// The step stays due, so it is retried on the next poll.
+		entry.Action, entry.Error = ActionFailed, err.Error()
+		slog.ErrorContext(ctx, "escalation: failed to send escalation email", "caseID", c.ID, "policy", p.Name, "step", entry.Step, "err", err)
+		return append(entries, entry)
+	}
+	entry.Action = ActionNotified
+	st.Fired++
+	slog.InfoContext(ctx, "escalation: notified", "caseID", c.ID, "policy", p.Name, "step", entry.Step, "target", step.Notify)
+	return append(entries, entry)
+}
+
+// end drops c's escalation state.
+func (e *Engine) end(c caseInfo, st CaseState, now time.Time, reason string) {
+	_ = e.store.update(func(d *storeFile) ([]Entry, error) {
+		delete(d.Cases, c.ID)
+		return []Entry{{CaseID: c.ID, CaseNumber: c.Number, Policy: st.Policy, Action: ActionEnded, Reason: reason, At: now.UTC()}}, nil
+	})
+}
+
+// recipients returns the email addresses target resolves to for c.
+func (e *Engine) recipients(ctx context.Context, target Target, c caseInfo, now time.Time) ([]string, error) {
+	if target == TargetOnCallManager {
+		email, ok := e.oncall.EmailAt(e.def.OnCallManagerTeam, now)
+		if !ok {
+			return nil, fmt.Errorf("nobody is on call for manager team %s", e.def.OnCallManagerTeam)
+		}
+		return []string{email}, nil
+	}
+
+	team, ok := e.dir.TeamByGroupName(c.teamName())
+	if !ok {
+		return nil, errors.New("the case is not in a registry team's queue")
+	}
+	if target == TargetTeamLead {
+		lead, ok := e.def.TeamLeads[team.Key]
+		if !ok {
+			return nil, fmt.Errorf("team %s has no lead configured", team.Key)
+		}
+		return []string{lead}, nil
+	}
+	if email, ok := e.oncall.EmailAt(team.Key, now); ok {
+		return []string{email}, nil
+	}
+	members, err := e.members(ctx, team.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("team %s has nobody on call and no members", team.Key)
+	}
+	return members, nil
+}
+
+// members returns the email addresses of group's members.
+func (e *Engine) members(ctx context.Context, group string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for page := 0; page < maxPages; page++ {
+		body, err := json.Marshal(map[string]any{
+			"filters":    map[string]any{"groupNames": []string{group}},
+			"pagination": map[string]int{"offset": page * userPageLimit, "limit": userPageLimit},
+		})
+		if err != nil {
+			return nil, err
+		}
+		raw, err := e.call(ctx, func(ctx context.Context) ([]byte, error) { return e.entity.SearchUsers(ctx, body) })
+		if err != nil {
+			return nil, fmt.Errorf("search team members: %w", err)
+		}
+		var resp struct {
+			Users []struct {
+				Email string `json:"email"`
+			} `json:"users"`
+			Total int `json:"total"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, fmt.Errorf("decode team members: %w", err)
+		}
+		for _, u := range resp.Users {
+			if key := strings.ToLower(u.Email); key != "" && !seen[key] {
+				seen[key] = true
+				out = append(out, u.Email)
+			}
+		}
+		if len(resp.Users) < userPageLimit || (page+1)*userPageLimit >= resp.Total {
+			break
+		}
+	}
+	return out, nil
+}
+
+// openCases returns the cases in an active state at a severity some policy
+// covers, and whether that is all of them.
+func (e *Engine) openCases(ctx context.Context) ([]caseInfo, bool, error) {
+	var out []caseInfo
+	for page := 0; page < maxPages; page++ {
+		body, err := json.Marshal(map[string]any{
+			"filters": map[string]any{"filters": []map[string]any{
+				{"field": "severity", "op": "in", "values": e.def.severities()},
+				{"field": "state", "op": "in", "values": activeStates},
+			}},
+			"sortBy":     map[string]string{"field": "createdOn", "order": "asc"},
+			"pagination": map[string]int{"offset": page * casePageLimit, "limit": casePageLimit},
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		raw, err := e.call(ctx, func(ctx context.Context) ([]byte, error) { return e.entity.SearchCases(ctx, body) })
+		if err != nil {
+			return nil, false, err
+		}
+		var resp struct {
+			Cases []caseInfo `json:"cases"`
+			Total int        `json:"total"`
+		}
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return nil, false, fmt.Errorf("decode case search: %w", err)
+		}
+		out = append(out, resp.Cases...)
+		if len(resp.Cases) < casePageLimit || (page+1)*casePageLimit >= resp.Total {
+			return out, true, nil
+		}
+	}
+	slog.WarnContext(ctx, "escalation: open-case search matched more than the pages read; the rest are not escalated this poll",
+		"read", len(out))
+	return out, false, nil
+}
+
+// Snooze pauses caseID's escalation for d: no step fires until it runs out,
+// and the steps then fall due d later than they would have. Snoozing a case
+// already snoozed replaces when the snooze ends.
+func (e *Engine) Snooze(caseID string, d time.Duration, by, reason string) (CaseState, error) {
+	if e == nil {
+		return CaseState{}, ErrNotConfigured
+	}
+	now := e.now().UTC()
+	var out CaseState
+	err := e.store.update(func(data *storeFile) ([]Entry, error) {
+		st := data.Cases[caseID]
+		if st == nil {
+			return nil, ErrNotEscalating
+		}
+		if st.Acknowledged {
+			return nil, ErrAcknowledged
+		}
+		if st.SnoozedUntil.IsZero() {
+			st.SnoozedAt = now
+		}
+		st.SnoozedUntil, st.SnoozedBy = now.Add(d), by
+		out = *st
+		return []Entry{{
+			CaseID: caseID, CaseNumber: st.CaseNumber, Policy: st.Policy, Action: ActionSnoozed,
+			By: by, Reason: reason, At: now,
+		}}, nil
+	})
+	return out, err
+}
+
+// Case returns caseID's escalation state; ok is false for a case under no
+// policy.
+func (e *Engine) Case(caseID string) (CaseState, bool) {
+	if e == nil {
+		return CaseState{}, false
+	}
+	return e.store.Case(caseID)
+}
+
+// Entries returns the audit entries matching f, newest first.
+func (e *Engine) Entries(f Filter) []Entry {
+	if e == nil {
+		return []Entry{}
+	}
+	return e.store.Entries(f)
+}
+
+func (e *Engine) caseURL(caseID string) string {
+	if e.cfg.PortalBaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(e.cfg.PortalBaseURL, "/") + "/cases/" + url.PathEscape(caseID)
+}
+
+func (e *Engine) sendEmail(ctx context.Context, to []string, subject, body string) error {
+	callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	return e.email.SendEmail(callCtx, to, nil, nil, nil, subject, body, nil)
+}
+
+func (e *Engine) call(ctx context.Context, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	return fn(callCtx)
+}
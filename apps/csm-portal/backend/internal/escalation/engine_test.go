@@ -0,0 +1,302 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package escalation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// fakeEntity serves a page of open cases, each case's acknowledgement and
+// one group's members.
+type fakeEntity struct {
+	open    []map[string]any
+	ackedBy map[string]string // case id -> acknowledging engineer's email
+	members string
+}
+
+func (f *fakeEntity) SearchCases(_ context.Context, body []byte) ([]byte, error) {
+	if !strings.Contains(string(body), `"field":"severity"`) {
+		return nil, errors.New("expected a severity search")
+	}
+	return json.Marshal(map[string]any{"cases": f.open, "total": len(f.open)})
+}
+
+func (f *fakeEntity) GetCase(_ context.Context, id string) ([]byte, error) {
+	c := map[string]any{"id": id, "acknowledgedBy": nil}
+	if email, ok := f.ackedBy[id]; ok {
+		c["acknowledgedBy"] = map[string]string{"email": email, "name": "Acker"}
+	}
+	return json.Marshal(c)
+}
+
+func (f *fakeEntity) SearchUsers(_ context.Context, _ []byte) ([]byte, error) {
+	return []byte(f.members), nil
+}
+
+type fakeEmail struct {
+	sent []struct {
+		to      []string
+		subject string
+	}
+	err error
+}
+
+func (f *fakeEmail) SendEmail(_ context.Context, to, _, _, _ []string, subject, _ string, _ []notifications.EmailAttachment) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, struct {
+		to      []string
+		subject string
+	}{to, subject})
+	return nil
+}
+
+const testDefinition = `{
+  "policies": [
+    {"name": "critical", "severities": ["critical"], "steps": [
+      {"afterMinutes": 10, "notify": "team"},
+      {"afterMinutes": 20, "notify": "team_lead"},
+      {"afterMinutes": 40, "notify": "oncall_manager"}]}
+  ],
+  "teamLeads": {"alpha": "alpha-lead@example.com"},
+  "onCallManagerTeam": "beta"
+}`
+
+var t0 = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func openCase(id, team, severity string, created time.Time) map[string]any {
+	return map[string]any{"id": id, "number": "CS-" + id, "subject": "Gateway down", "severity": severity,
+		"createdOn": created.Format(time.RFC3339), "assignedTeam": map[string]string{"name": team},
+		"account": map[string]string{"type": "Gold"}}
+}
+
+func newTestEngine(t *testing.T, path string, entity *fakeEntity, email *fakeEmail, now *time.Time) *Engine {
+	t.Helper()
+	dir, oncall := testDirectory(t)
+	def, err := Parse([]byte(testDefinition), dir, oncall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(entity, email, def, dir, oncall, store, Config{Interval: time.Minute, PortalBaseURL: "https://portal.example"})
+	e.now = func() time.Time { return *now }
+	return e
+}
+
+func actions(entries []Entry) []Action {
+	out := make([]Action, len(entries))
+	for i, e := range entries {
+		// Entries come newest first; read them oldest first.
+		out[len(entries)-1-i] = e.Action
+	}
+	return out
+}
+
+func TestEngine_EscalatesUntilAcknowledged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "escalation.json")
+	entity := &fakeEntity{ackedBy: map[string]string{}, open: []map[string]any{openCase("c1", "Alpha Team", "Critical", t0)}}
+	email := &fakeEmail{}
+	now := t0
+	e := newTestEngine(t, path, entity, email, &now)
+	ctx := context.Background()
+	poll := func(at time.Duration) {
+		now = t0.Add(at)
+		e.PollOnce(ctx)
+	}
+
+	poll(0) // the case is first seen: its escalation starts now
+	poll(9 * time.Minute)
+	if len(email.sent) != 0 {
+		t.Fatalf("sent %v before the first step was due", email.sent)
+	}
+	poll(10 * time.Minute)
+	onCall, _ := e.oncall.EmailAt("alpha", now)
+	if len(email.sent) != 1 || !slices.Equal(email.sent[0].to, []string{onCall}) {
+		t.Fatalf("step 1 sent %+v, want the alpha on-call engineer %s", email.sent, onCall)
+	}
+	if want := "[CS-c1] Unacknowledged Critical case: escalation 1 of 3"; email.sent[0].subject != want {
+		t.Errorf("subject = %q, want %q", email.sent[0].subject, want)
+	}
+
+	// A 30 minute snooze pauses the clock: step 2 falls due at 50 minutes
+	// instead of 20.
+	now = t0.Add(15 * time.Minute)
+	st, err := e.Snooze("c1", 30*time.Minute, "jane@example.com", "Working with the customer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.SnoozedUntil.Equal(t0.Add(45 * time.Minute)) {
+		t.Errorf("snoozed until %s", st.SnoozedUntil)
+	}
+	poll(25 * time.Minute)
+	poll(49 * time.Minute)
+	if len(email.sent) != 1 {
+		t.Fatalf("sent %+v while snoozed", email.sent)
+	}
+	poll(50 * time.Minute)
+	if len(email.sent) != 2 || !slices.Equal(email.sent[1].to, []string{"alpha-lead@example.com"}) {
+		t.Fatalf("step 2 sent %+v, want the alpha lead", email.sent)
+	}
+
+	// A restart re-reads the state: nothing is sent again.
+	e = newTestEngine(t, path, entity, email, &now)
+	poll(51 * time.Minute)
+	if len(email.sent) != 2 {
+		t.Fatalf("a restart re-paged: %+v", email.sent)
+	}
+
+	// Acknowledged before the manager is due: nobody else is paged.
+	entity.ackedBy["c1"] = "jane@example.com"
+	poll(70 * time.Minute)
+	poll(90 * time.Minute)
+	if len(email.sent) != 2 {
+		t.Fatalf("paged after acknowledgement: %+v", email.sent)
+	}
+	if _, err := e.Snooze("c1", time.Minute, "jane@example.com", ""); !errors.Is(err, ErrAcknowledged) {
+		t.Errorf("Snooze(acknowledged) = %v", err)
+	}
+
+	// Moving to a severity under no policy ends the escalation.
+	entity.open[0]["severity"] = "high"
+	poll(91 * time.Minute)
+	if _, ok := e.Case("c1"); ok {
+		t.Error("a case under no policy is still escalating")
+	}
+
+	want := []Action{ActionStarted, ActionNotified, ActionSnoozed, ActionResumed, ActionNotified, ActionAcknowledged, ActionEnded}
+	if got := actions(e.Entries(Filter{CaseID: "c1"})); !slices.Equal(got, want) {
+		t.Errorf("audit trail = %v, want %v", got, want)
+	}
+	snoozed := e.Entries(Filter{CaseID: "c1"})[4]
+	if snoozed.By != "jane@example.com" || snoozed.Reason != "Working with the customer" {
+		t.Errorf("snooze entry = %+v", snoozed)
+	}
+}
+
+func TestEngine_TeamMembersAndMissingLead(t *testing.T) {
+	entity := &fakeEntity{members: `{"users":[{"email":"g1@example.com"},{"email":"G1@example.com"},{"email":"g2@example.com"}],"total":3}`}
+	email := &fakeEmail{}
+	now := t0
+	e := newTestEngine(t, "", entity, email, &now)
+	ctx := context.Background()
+
+	e.PollOnce(ctx) // nothing open yet
+	// Created a minute after the previous poll, the case is timed from its
+	// creation rather than from when it was first seen.
+	entity.open = []map[string]any{openCase("c2", "Gamma Team", "critical", t0.Add(time.Minute))}
+	now = t0.Add(12 * time.Minute)
+	e.PollOnce(ctx)
+	if len(email.sent) != 1 || !slices.Equal(email.sent[0].to, []string{"g1@example.com", "g2@example.com"}) {
+		t.Fatalf("step 1 sent %+v, want gamma's members, the team having no rotation", email.sent)
+	}
+
+	// Gamma has no lead: the step fails and the escalation moves on.
+	now = t0.Add(21 * time.Minute)
+	e.PollOnce(ctx)
+	entries := e.Entries(Filter{CaseID: "c2", Limit: 1})
+	if entries[0].Action != ActionFailed || entries[0].Step != 2 || !strings.Contains(entries[0].Error, "no lead") {
+		t.Errorf("step 2 entry = %+v", entries[0])
+	}
+	if st, _ := e.Case("c2"); st.Fired != 2 {
+		t.Errorf("fired = %d, want 2", st.Fired)
+	}
+}
+
+func TestEngine_SeverityChangeRestarts(t *testing.T) {
+	entity := &fakeEntity{ackedBy: map[string]string{"c4": "jane@example.com"},
+		open: []map[string]any{openCase("c4", "Alpha Team", "critical", t0)}}
+	email := &fakeEmail{}
+	now := t0
+	e := newTestEngine(t, "", entity, email, &now)
+	ctx := context.Background()
+
+	e.PollOnce(ctx)
+	now = t0.Add(10 * time.Minute)
+	e.PollOnce(ctx) // seen acknowledged
+	if len(email.sent) != 0 {
+		t.Fatalf("paged an acknowledged case: %+v", email.sent)
+	}
+
+	// A severity change clears the acknowledgement upstream. The case is
+	// still under the same policy, but it escalates again from the change.
+	e.def.Policies[0].Severities = append(e.def.Policies[0].Severities, "catastrophic")
+	delete(entity.ackedBy, "c4")
+	entity.open[0]["severity"] = "catastrophic"
+	now = t0.Add(30 * time.Minute)
+	e.PollOnce(ctx)
+	if st, _ := e.Case("c4"); st.Acknowledged || st.Fired != 0 || !st.Since.Equal(now) {
+		t.Errorf("state after the severity change = %+v", st)
+	}
+	now = t0.Add(40 * time.Minute)
+	e.PollOnce(ctx)
+	if len(email.sent) != 1 {
+		t.Errorf("sent %+v, want step 1 ten minutes after the change", email.sent)
+	}
+	want := []Action{ActionStarted, ActionAcknowledged, ActionRestarted, ActionNotified}
+	if got := actions(e.Entries(Filter{CaseID: "c4"})); !slices.Equal(got, want) {
+		t.Errorf("audit trail = %v, want %v", got, want)
+	}
+}
+
+func TestEngine_SendFailureRetries(t *testing.T) {
+	entity := &fakeEntity{open: []map[string]any{openCase("c3", "Alpha Team", "critical", t0)}}
+	email := &fakeEmail{err: errors.New("smtp down")}
+	now := t0
+	e := newTestEngine(t, "", entity, email, &now)
+	ctx := context.Background()
+
+	e.PollOnce(ctx)
+	now = t0.Add(10 * time.Minute)
+	e.PollOnce(ctx)
+	if st, _ := e.Case("c3"); st.Fired != 0 {
+		t.Fatalf("fired = %d after a failed send, want the step retried", st.Fired)
+	}
+	email.err = nil
+	now = t0.Add(11 * time.Minute)
+	e.PollOnce(ctx)
+	if st, _ := e.Case("c3"); st.Fired != 1 || len(email.sent) != 1 {
+		t.Errorf("fired = %d, sent %d after the retry", st.Fired, len(email.sent))
+	}
+}
+
+func TestEngine_SnoozeErrors(t *testing.T) {
+	now := t0
+	e := newTestEngine(t, "", &fakeEntity{}, &fakeEmail{}, &now)
+	if _, err := e.Snooze("nope", time.Minute, "jane@example.com", ""); !errors.Is(err, ErrNotEscalating) {
+		t.Errorf("Snooze(unknown) = %v", err)
+	}
+	var none *Engine
+	if _, err := none.Snooze("c1", time.Minute, "jane@example.com", ""); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("nil Snooze = %v", err)
+	}
+	if _, ok := none.Case("c1"); ok || len(none.Entries(Filter{})) != 0 {
+		t.Error("a nil Engine has state")
+	}
+}
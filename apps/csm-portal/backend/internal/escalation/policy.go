@@ -0,0 +1,188 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package escalation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+)
+
+// Target is who an escalation step notifies.
+type Target string
+
+const (
+	// TargetTeam is the case's assigned team: whoever is on call for it, or
+	// every member of its group when it has no rotation or nobody is on call.
+	TargetTeam Target = "team"
+	// TargetTeamLead is the lead configured for the case's team.
+	TargetTeamLead Target = "team_lead"
+	// TargetOnCallManager is whoever is on call for the configured manager
+	// team's rotation.
+	TargetOnCallManager Target = "oncall_manager"
+)
+
+var validTargets = map[Target]bool{TargetTeam: true, TargetTeamLead: true, TargetOnCallManager: true}
+
+// Step is one escalation: when the case has gone AfterMinutes unacknowledged,
+// Notify is told.
+type Step struct {
+	AfterMinutes int    `json:"afterMinutes"`
+	Notify       Target `json:"notify"`
+}
+
+// Policy escalates unacknowledged cases of its severities and account tiers.
+type Policy struct {
+	// Name identifies the policy in the audit trail, logs and errors.
+	Name string `json:"name"`
+	// Severities are case severities (catastrophic ... low). Required.
+	Severities []string `json:"severities"`
+	// AccountTiers are support-tier labels (e.g. "Platinum"), matched against
+	// the case's account; omitted matches any tier.
+	AccountTiers []string `json:"accountTiers,omitempty"`
+	// Steps fire in order, each strictly later than the one before.
+	Steps []Step `json:"steps"`
+}
+
+// Definition is the CASE_ESCALATION_FILE document: the policies and who
+// their steps notify.
+type Definition struct {
+	// Policies are matched in order; a case follows the first that matches.
+	Policies []Policy `json:"policies"`
+	// TeamLeads is each registry team key's lead, for TargetTeamLead.
+	TeamLeads map[string]string `json:"teamLeads,omitempty"`
+	// OnCallManagerTeam is the registry team key whose on-call rotation is
+	// the on-call manager, for TargetOnCallManager.
+	OnCallManagerTeam string `json:"onCallManagerTeam,omitempty"`
+}
+
+// Parse decodes and validates the escalation definition against the team
+// registry and on-call rotations its team keys refer to. Severities and tiers
+// are lowercased for matching.
+func Parse(raw []byte, dir *directory.Directory, oncall *directory.OnCall) (*Definition, error) {
+	var cfg Definition
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("escalation config: %w", err)
+	}
+	if len(cfg.Policies) == 0 {
+		return nil, fmt.Errorf("escalation config: no policies")
+	}
+
+	for key, lead := range cfg.TeamLeads {
+		if _, ok := dir.TeamByKey(key); !ok {
+			return nil, fmt.Errorf("escalation config: teamLeads: unknown team %q", key)
+		}
+		if !isEmail(lead) {
+			return nil, fmt.Errorf("escalation config: teamLeads: team %q: %q is not an email address", key, lead)
+		}
+	}
+	if cfg.OnCallManagerTeam != "" && !oncall.HasRotation(cfg.OnCallManagerTeam) {
+		return nil, fmt.Errorf("escalation config: onCallManagerTeam %q has no on-call rotation", cfg.OnCallManagerTeam)
+	}
+
+	names := map[string]bool{}
+	for i := range cfg.Policies {
+		p := &cfg.Policies[i]
+		if strings.TrimSpace(p.Name) == "" {
+			return nil, fmt.Errorf("escalation config: policy %d: name is required", i)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("escalation config: policy %q: duplicate name", p.Name)
+		}
+		names[p.Name] = true
+		if len(p.Severities) == 0 {
+			return nil, fmt.Errorf("escalation config: policy %q: severities are required", p.Name)
+		}
+		p.Severities = lowerAll(p.Severities)
+		p.AccountTiers = lowerAll(p.AccountTiers)
+		if len(p.Steps) == 0 {
+			return nil, fmt.Errorf("escalation config: policy %q: no steps", p.Name)
+		}
+		prev := 0
+		for j, s := range p.Steps {
+			if !validTargets[s.Notify] {
+				return nil, fmt.Errorf("escalation config: policy %q: step %d: unknown notify %q", p.Name, j+1, s.Notify)
+			}
+			if s.AfterMinutes <= prev {
+				return nil, fmt.Errorf("escalation config: policy %q: step %d: afterMinutes must be positive and later than the step before", p.Name, j+1)
+			}
+			prev = s.AfterMinutes
+			if s.Notify == TargetOnCallManager && cfg.OnCallManagerTeam == "" {
+				return nil, fmt.Errorf("escalation config: policy %q: step %d notifies the on-call manager but onCallManagerTeam is not set", p.Name, j+1)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+// Match returns the first policy covering a case of severity and accountTier,
+// or nil.
+func (c *Definition) Match(severity, accountTier string) *Policy {
+	severity, accountTier = strings.ToLower(severity), strings.ToLower(accountTier)
+	for i := range c.Policies {
+		p := &c.Policies[i]
+		if slices.Contains(p.Severities, severity) && (len(p.AccountTiers) == 0 || slices.Contains(p.AccountTiers, accountTier)) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Policy returns the policy called name, or nil.
+func (c *Definition) Policy(name string) *Policy {
+	for i := range c.Policies {
+		if c.Policies[i].Name == name {
+			return &c.Policies[i]
+		}
+	}
+	return nil
+}
+
+// severities returns every severity some policy covers, sorted.
+func (c *Definition) severities() []string {
+	var out []string
+	for _, p := range c.Policies {
+		for _, s := range p.Severities {
+			if !slices.Contains(out, s) {
+				out = append(out, s)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func isEmail(s string) bool {
+	at := strings.Index(s, "@")
+	return at > 0 && at < len(s)-1 && !strings.ContainsAny(s, " ,;")
+}
+
+func lowerAll(ss []string) []string {
+	out := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package escalation
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+)
+
+// exampleRegistry is CSM_TEAM_REGISTRY in .env.example.
+const exampleRegistry = "alpha|Alpha Team|CRE-ABT,beta|Beta Team|SRE-ABT,delta|Delta Team|CRE,gamma|Gamma Team"
+
+func testDirectory(t *testing.T) (*directory.Directory, *directory.OnCall) {
+	t.Helper()
+	teams, err := directory.ParseTeamRegistry(exampleRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := directory.New(teams, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile("../../oncall.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oncall, err := directory.ParseOnCall(raw, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, oncall
+}
+
+// The committed escalation.example.json must load against the .env.example
+// registry and oncall.example.json rotations it is documented alongside.
+func TestParse_Example(t *testing.T) {
+	dir, oncall := testDirectory(t)
+	raw, err := os.ReadFile("../../escalation.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := Parse(raw, dir, oncall)
+	if err != nil {
+		t.Fatalf("Parse(example): %v", err)
+	}
+
+	cases := []struct{ severity, tier, want string }{
+		{"Catastrophic", "", "catastrophic"},
+		{"critical", "platinum", "critical-platinum"},
+		{"critical", "Gold", "critical"},
+		{"high", "Platinum", ""},
+	}
+	for _, tc := range cases {
+		got := ""
+		if p := def.Match(tc.severity, tc.tier); p != nil {
+			got = p.Name
+		}
+		if got != tc.want {
+			t.Errorf("Match(%s, %s) = %q, want %q", tc.severity, tc.tier, got, tc.want)
+		}
+	}
+	if got := strings.Join(def.severities(), ","); got != "catastrophic,critical" {
+		t.Errorf("severities = %s", got)
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	dir, oncall := testDirectory(t)
+	step := `"steps":[{"afterMinutes":10,"notify":"team"}]`
+	cases := map[string]string{
+		"no policies":       `{"policies":[]}`,
+		"no name":           `{"policies":[{"severities":["critical"],` + step + `}]}`,
+		"duplicate name":    `{"policies":[{"name":"p","severities":["critical"],` + step + `},{"name":"p","severities":["high"],` + step + `}]}`,
+		"no severities":     `{"policies":[{"name":"p",` + step + `}]}`,
+		"no steps":          `{"policies":[{"name":"p","severities":["critical"],"steps":[]}]}`,
+		"unknown target":    `{"policies":[{"name":"p","severities":["critical"],"steps":[{"afterMinutes":10,"notify":"ceo"}]}]}`,
+		"zero minutes":      `{"policies":[{"name":"p","severities":["critical"],"steps":[{"afterMinutes":0,"notify":"team"}]}]}`,
+		"not increasing":    `{"policies":[{"name":"p","severities":["critical"],"steps":[{"afterMinutes":10,"notify":"team"},{"afterMinutes":10,"notify":"team_lead"}]}]}`,
+		"no manager team":   `{"policies":[{"name":"p","severities":["critical"],"steps":[{"afterMinutes":10,"notify":"oncall_manager"}]}]}`,
+		"manager no rota":   `{"onCallManagerTeam":"gamma","policies":[{"name":"p","severities":["critical"],` + step + `}]}`,
+		"lead unknown team": `{"teamLeads":{"nope":"a@example.com"},"policies":[{"name":"p","severities":["critical"],` + step + `}]}`,
+		"lead not an email": `{"teamLeads":{"alpha":"Alpha Lead"},"policies":[{"name":"p","severities":["critical"],` + step + `}]}`,
+		"unknown field":     `{"policies":[{"name":"p","severity":["critical"],` + step + `}]}`,
+		"not a JSON object": `[]`,
+	}
+	for name, raw := range cases {
+		if _, err := Parse([]byte(raw), dir, oncall); err == nil {
+			t.Errorf("%s: Parse accepted %s", name, raw)
+		} else if !strings.HasPrefix(err.Error(), "escalation config") {
+			t.Errorf("%s: error %q does not say where it came from", name, err)
+		}
+	}
+}
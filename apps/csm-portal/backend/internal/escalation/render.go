@@ -0,0 +1,91 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package escalation
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// The template uses inline styles only: most mail clients drop <style> blocks.
+var escalationTemplate = template.Must(template.New("escalation").Parse(`<!DOCTYPE html>
+<html><body style="font-family:Arial,Helvetica,sans-serif;color:#222;">
+<h2 style="margin-bottom:4px;color:#b00020;">{{.Case}}</h2>
+<p>This {{.Severity}} case has not been acknowledged for {{.Waited}}.</p>
+<table style="border-collapse:collapse;font-size:14px;">
+<tr><td style="padding:2px 12px 2px 0;color:#666;">Severity</td><td>{{.Severity}}</td></tr>
+{{if .Tier}}<tr><td style="padding:2px 12px 2px 0;color:#666;">Account tier</td><td>{{.Tier}}</td></tr>{{end}}
+{{if .Team}}<tr><td style="padding:2px 12px 2px 0;color:#666;">Team</td><td>{{.Team}}</td></tr>{{end}}
+<tr><td style="padding:2px 12px 2px 0;color:#666;">Escalation</td><td>step {{.Step}} of {{.Steps}} ({{.Policy}})</td></tr>
+</table>
+{{if .CaseURL}}<p><a href="{{.CaseURL}}">Open in CSM Portal</a> and acknowledge the case to stop the escalation.</p>{{else}}<p>Acknowledge the case in CSM Portal to stop the escalation.</p>{{end}}
+<p style="color:#999;font-size:11px;">You are receiving this as {{.Audience}} under the {{.Policy}} escalation policy.</p>
+</body></html>`))
+
+var audiences = map[Target]string{
+	TargetTeam:          "a member of the assigned team",
+	TargetTeamLead:      "the assigned team's lead",
+	TargetOnCallManager: "the on-call manager",
+}
+
+// render renders the email for step index i of p on c, which has waited
+// that long unacknowledged.
+func render(c caseInfo, p *Policy, i int, waited time.Duration, caseURL string) (subject, body string, err error) {
+	number := c.Number
+	if number == "" {
+		number = c.ID
+	}
+	label := number
+	if c.Subject != "" {
+		label += " — " + c.Subject
+	}
+	var buf bytes.Buffer
+	err = escalationTemplate.Execute(&buf, struct {
+		Case, CaseURL, Severity, Tier, Team, Policy, Audience, Waited string
+		Step, Steps                                                   int
+	}{
+		Case: label, CaseURL: caseURL, Severity: c.Severity, Tier: c.tier(), Team: c.teamName(),
+		Policy: p.Name, Audience: audiences[p.Steps[i].Notify], Waited: waitedText(waited),
+		Step: i + 1, Steps: len(p.Steps),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("[%s] Unacknowledged %s case: escalation %d of %d", number, c.Severity, i+1, len(p.Steps)), buf.String(), nil
+}
+
+// waitedText is d in whole minutes, or hours and minutes from an hour up.
+func waitedText(d time.Duration) string {
+	m := int(d / time.Minute)
+	if m < 60 {
+		return fmt.Sprintf("%d minute%s", m, plural(m))
+	}
+	h, m := m/60, m%60
+	if m == 0 {
+		return fmt.Sprintf("%d hour%s", h, plural(h))
+	}
+	return fmt.Sprintf("%d hour%s %d minute%s", h, plural(h), m, plural(m))
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
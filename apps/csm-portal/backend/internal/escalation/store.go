@@ -0,0 +1,227 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package escalation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/filestore"
+)
+
+// maxEntries is how many audit entries the Store keeps; the oldest are
+// dropped.
+const maxEntries = 5000
+
+// CaseState is where one case is in its escalation.
+type CaseState struct {
+	CaseID     string `json:"caseId"`
+	CaseNumber string `json:"caseNumber"`
+	Policy     string `json:"policy"`
+	// Severity is the severity the policy was matched at. A change restarts
+	// the escalation, as it clears the acknowledgement.
+	Severity string `json:"severity"`
+	// Since is when the case started waiting for acknowledgement, moved on by
+	// however long it was snoozed.
+	Since time.Time `json:"since"`
+	// Fired is how many of the policy's steps have been notified.
+	Fired int `json:"fired"`
+	// Acknowledged is set once the case is seen acknowledged; nothing more
+	// fires for it.
+	Acknowledged bool `json:"acknowledged,omitempty"`
+	// SnoozedAt and SnoozedUntil bound a snooze in effect; both are zero
+	// when there is none.
+	SnoozedAt    time.Time `json:"snoozedAt,omitzero"`
+	SnoozedUntil time.Time `json:"snoozedUntil,omitzero"`
+	SnoozedBy    string    `json:"snoozedBy,omitempty"`
+}
+
+// Action is what an audit entry records.
+type Action string
+
+const (
+	// ActionStarted is a case first matching a policy.
+	ActionStarted Action = "started"
+	// ActionRestarted is an escalation starting over after a severity change
+	// cleared the case's acknowledgement.
+	ActionRestarted Action = "restarted"
+	// ActionNotified is a step's recipients being emailed.
+	ActionNotified Action = "notified"
+	// ActionFailed is a step that could not be notified.
+	ActionFailed Action = "failed"
+	// ActionAcknowledged is the case being seen acknowledged.
+	ActionAcknowledged Action = "acknowledged"
+	// ActionSnoozed is POST /cases/{id}/escalation/snooze.
+	ActionSnoozed Action = "snoozed"
+	// ActionResumed is a snooze running out.
+	ActionResumed Action = "resumed"
+	// ActionEnded is the case leaving every policy: resolved, closed or
+	// downgraded.
+	ActionEnded Action = "ended"
+)
+
+// Entry is one audit trail record.
+type Entry struct {
+	ID         string `json:"id"`
+	CaseID     string `json:"caseId"`
+	CaseNumber string `json:"caseNumber"`
+	Policy     string `json:"policy"`
+	Action     Action `json:"action"`
+	// Step is the 1-based step notified or failed.
+	Step       int      `json:"step,omitempty"`
+	Target     Target   `json:"target,omitempty"`
+	Recipients []string `json:"recipients,omitempty"`
+	// By is the email of whoever snoozed or acknowledged the case.
+	By     string    `json:"by,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	Error  string    `json:"error,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Filter narrows Store.Entries. Empty fields match anything.
+type Filter struct {
+	CaseID string
+	// Limit caps the result; zero or less is every match.
+	Limit int
+}
+
+// storeFile is the persisted form of a Store.
+type storeFile struct {
+	// LastPoll is when the engine last polled, so after a restart a case
+	// created while it was down is timed from its creation.
+	LastPoll time.Time             `json:"lastPoll,omitzero"`
+	Cases    map[string]*CaseState `json:"cases"`
+	Entries  []Entry               `json:"entries"`
+}
+
+// Store keeps every case's escalation state and the audit trail. With a path
+// it is persisted to that JSON file on every change, so a restart neither
+// re-pages nor forgets a snooze; without one it is in memory only.
+type Store struct {
+	path string
+
+	mu   sync.RWMutex
+	data storeFile
+}
+
+// OpenStore loads the store from path. A missing file is an empty store; a
+// malformed one is an error. An empty path is an in-memory store.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, data: storeFile{Cases: map[string]*CaseState{}}}
+	if path == "" {
+		return s, nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read escalation state: %w", err)
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("parse escalation state %s: %w", path, err)
+	}
+	if s.data.Cases == nil {
+		s.data.Cases = map[string]*CaseState{}
+	}
+	return s, nil
+}
+
+// Case returns caseID's escalation state.
+func (s *Store) Case(caseID string) (CaseState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	st, ok := s.data.Cases[caseID]
+	if !ok {
+		return CaseState{}, false
+	}
+	return *st, true
+}
+
+// Entries returns the audit entries matching f, newest first.
+func (s *Store) Entries(f Filter) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []Entry{}
+	for i := len(s.data.Entries) - 1; i >= 0; i-- {
+		e := s.data.Entries[i]
+		if f.CaseID != "" && e.CaseID != f.CaseID {
+			continue
+		}
+		out = append(out, e)
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// update runs fn on the state under the lock, appends the entries it returns
+// and persists. An error from fn is returned with nothing recorded. The change
+// has otherwise already happened by the time it is recorded -- an email has
+// gone, say -- so a failure to persist is logged rather than returned: the
+// state is still kept in memory.
+func (s *Store) update(fn func(d *storeFile) ([]Entry, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := fn(&s.data)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ID == "" {
+			e.ID = filestore.NewID()
+		}
+		s.data.Entries = append(s.data.Entries, e)
+	}
+	if over := len(s.data.Entries) - maxEntries; over > 0 {
+		s.data.Entries = append([]Entry(nil), s.data.Entries[over:]...)
+	}
+	if err := s.persist(); err != nil {
+		slog.Error("escalation: failed to persist state", "err", err)
+	}
+	return nil
+}
+
+// lastPoll returns when the engine last polled, or zero.
+func (s *Store) lastPoll() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data.LastPoll
+}
+
+// persist saves the escalation state and audit trail to path. Callers hold
+// mu.
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+	raw, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	return filestore.WriteFile(s.path, raw)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/assignment"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+const (
+	defaultDecisionLimit = 50
+	maxDecisionLimit     = 500
+)
+
+// caseAssigner abstracts the assignment engine used by AssignmentHandler.
+type caseAssigner interface {
+	Assign(ctx context.Context, caseID string, req assignment.Request) (assignment.Decision, error)
+}
+
+// assignmentLog abstracts the decision log used by AssignmentHandler.
+type assignmentLog interface {
+	List(f assignment.Filter) []assignment.Decision
+}
+
+// AssignmentHandler handles automatic case assignment and its audit trail.
+type AssignmentHandler struct {
+	engine caseAssigner
+	log    assignmentLog
+}
+
+// NewAssignmentHandler creates an AssignmentHandler.
+func NewAssignmentHandler(engine caseAssigner, log assignmentLog) *AssignmentHandler {
+	return &AssignmentHandler{engine: engine, log: log}
+}
+
+// autoAssignRequest is the optional POST /cases/{id}/auto-assign body.
+type autoAssignRequest struct {
+	// TeamKey picks from another team's engineers than the case's own.
+	TeamKey string `json:"teamKey"`
+	// DryRun returns the decision without assigning or recording it.
+	DryRun bool `json:"dryRun"`
+}
+
+// AutoAssignCase handles POST /cases/{id}/auto-assign.
+// Assigns an unassigned case to an engineer of its team by the team's
+// configured strategy and returns the decision, with the candidates
+// considered and the reason for the pick. The assignment itself is a PATCH of
+// the case made as the caller.
+func (h *AssignmentHandler) AutoAssignCase(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	caseID := r.PathValue("id")
+	if caseID == "" {
+		writeError(w, http.StatusBadRequest, "Case ID cannot be empty!")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+	var req autoAssignRequest
+	if len(bytes.TrimSpace(body)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+			return
+		}
+	}
+
+	d, err := h.engine.Assign(r.Context(), caseID, assignment.Request{
+		TeamKey:     req.TeamKey,
+		DryRun:      req.DryRun,
+		Trigger:     assignment.TriggerManual,
+		RequestedBy: user.Email,
+	})
+	switch {
+	case err == nil:
+		writeJSONValue(w, http.StatusOK, d)
+	case errors.Is(err, assignment.ErrUnknownTeam):
+		writeError(w, http.StatusBadRequest, "Unknown teamKey.")
+	case errors.Is(err, assignment.ErrNoTeam):
+		writeError(w, http.StatusConflict, "The case is not in a registry team's queue; give a teamKey.")
+	case errors.Is(err, assignment.ErrNotConfigured):
+		writeError(w, http.StatusConflict, "The team does not assign cases automatically.")
+	case errors.Is(err, assignment.ErrAlreadyAssigned):
+		writeError(w, http.StatusConflict, "The case is already assigned.")
+	case errors.Is(err, assignment.ErrNoCandidates):
+		writeError(w, http.StatusConflict, "The team has no engineers to assign to.")
+	default:
+		slog.ErrorContext(r.Context(), "case auto-assignment failed", "userID", user.UserID, "caseID", caseID, "err", err)
+		mapUpstreamErrorGeneric(w, err, "Failed to auto-assign case.")
+	}
+}
+
+// ListAssignmentDecisions handles GET /assignment/decisions.
+// Returns recorded assignment decisions, newest first, optionally narrowed by
+// ?caseId= and ?teamKey=; ?limit= is 1-500, default 50.
+func (h *AssignmentHandler) ListAssignmentDecisions(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	f := assignment.Filter{CaseID: q.Get("caseId"), TeamKey: q.Get("teamKey"), Limit: defaultDecisionLimit}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxDecisionLimit {
+			writeError(w, http.StatusBadRequest, "limit must be an integer between 1 and 500")
+			return
+		}
+		f.Limit = n
+	}
+	writeJSONValue(w, http.StatusOK, h.log.List(f))
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/apierror"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/assignment"
+)
+
+// mockCaseAssigner records the request it was given and returns decision
+// and err.
+type mockCaseAssigner struct {
+	caseID   string
+	req      assignment.Request
+	decision assignment.Decision
+	err      error
+}
+
+func (m *mockCaseAssigner) Assign(_ context.Context, caseID string, req assignment.Request) (assignment.Decision, error) {
+	m.caseID, m.req = caseID, req
+	return m.decision, m.err
+}
+
+type mockAssignmentLog struct {
+	filter assignment.Filter
+}
+
+func (m *mockAssignmentLog) List(f assignment.Filter) []assignment.Decision {
+	m.filter = f
+	return []assignment.Decision{{ID: "d1"}}
+}
+
+func autoAssign(h *AssignmentHandler, body string) *httptest.ResponseRecorder {
+	r := withUser(httptest.NewRequest(http.MethodPost, "/cases/case-1/auto-assign", strings.NewReader(body)))
+	r.SetPathValue("id", "case-1")
+	w := httptest.NewRecorder()
+	h.AutoAssignCase(w, r)
+	return w
+}
+
+func TestAutoAssignCase(t *testing.T) {
+	t.Run("assigns as the caller", func(t *testing.T) {
+		m := &mockCaseAssigner{decision: assignment.Decision{ID: "d1", Assignee: &assignment.Candidate{Email: "jane@example.com"}}}
+		w := autoAssign(NewAssignmentHandler(m, &mockAssignmentLog{}), `{"teamKey":"abt-1","dryRun":true}`)
+		assertStatus(t, w, http.StatusOK)
+		want := assignment.Request{TeamKey: "abt-1", DryRun: true, Trigger: assignment.TriggerManual, RequestedBy: testUser.Email}
+		if m.caseID != "case-1" || m.req != want {
+			t.Errorf("Assign(%q, %+v), want %+v", m.caseID, m.req, want)
+		}
+		if got := decodeJSON[assignment.Decision](t, w); got.Assignee == nil || got.Assignee.Email != "jane@example.com" {
+			t.Errorf("decision = %+v", got)
+		}
+	})
+
+	t.Run("an empty body uses the case's team", func(t *testing.T) {
+		m := &mockCaseAssigner{}
+		assertStatus(t, autoAssign(NewAssignmentHandler(m, &mockAssignmentLog{}), ""), http.StatusOK)
+		if m.req.TeamKey != "" || m.req.DryRun {
+			t.Errorf("request = %+v", m.req)
+		}
+	})
+
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"unknown key", `{"team":"x"}`, nil, http.StatusBadRequest},
+		{"unknown team", "", fmt.Errorf("%w %q", assignment.ErrUnknownTeam, "x"), http.StatusBadRequest},
+		{"no team", "", assignment.ErrNoTeam, http.StatusConflict},
+		{"not configured", "", assignment.ErrNotConfigured, http.StatusConflict},
+		{"already assigned", "", assignment.ErrAlreadyAssigned, http.StatusConflict},
+		{"no candidates", "", assignment.ErrNoCandidates, http.StatusConflict},
+		{"case not found", "", &apierror.Error{StatusCode: http.StatusNotFound}, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewAssignmentHandler(&mockCaseAssigner{err: tc.err}, &mockAssignmentLog{})
+			assertStatus(t, autoAssign(h, tc.body), tc.status)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	NewAssignmentHandler(&mockCaseAssigner{}, &mockAssignmentLog{}).AutoAssignCase(w, httptest.NewRequest(http.MethodPost, "/cases/case-1/auto-assign", nil))
+	assertStatus(t, w, http.StatusUnauthorized)
+}
+
+func TestListAssignmentDecisions(t *testing.T) {
+	log := &mockAssignmentLog{}
+	h := NewAssignmentHandler(&mockCaseAssigner{}, log)
+
+	w := httptest.NewRecorder()
+	h.ListAssignmentDecisions(w, withUser(httptest.NewRequest(http.MethodGet, "/assignment/decisions?caseId=c1&teamKey=abt-1&limit=5", nil)))
+	assertStatus(t, w, http.StatusOK)
+	if want := (assignment.Filter{CaseID: "c1", TeamKey: "abt-1", Limit: 5}); log.filter != want {
+		t.Errorf("filter = %+v, want %+v", log.filter, want)
+	}
+
+	w = httptest.NewRecorder()
+	h.ListAssignmentDecisions(w, withUser(httptest.NewRequest(http.MethodGet, "/assignment/decisions", nil)))
+	assertStatus(t, w, http.StatusOK)
+	if log.filter.Limit != defaultDecisionLimit {
+		t.Errorf("default limit = %d", log.filter.Limit)
+	}
+
+	w = httptest.NewRecorder()
+	h.ListAssignmentDecisions(w, withUser(httptest.NewRequest(http.MethodGet, "/assignment/decisions?limit=501", nil)))
+	assertStatus(t, w, http.StatusBadRequest)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+// calendarIDRe is the shape of an entity-service business calendar ID.
+var calendarIDRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// entityCalendarClient abstracts the entity service business calendar operations.
+type entityCalendarClient interface {
+	ListCalendars(ctx context.Context) ([]byte, error)
+	AddBusinessTime(ctx context.Context, calendarID string, body []byte) ([]byte, error)
+}
+
+// CalendarHandler handles HTTP requests for business calendar operations.
+type CalendarHandler struct {
+	entity entityCalendarClient
+}
+
+// NewCalendarHandler creates a CalendarHandler backed by the given entity client.
+func NewCalendarHandler(entity entityCalendarClient) *CalendarHandler {
+	return &CalendarHandler{entity: entity}
+}
+
+// ListCalendars handles GET /calendars.
+func (h *CalendarHandler) ListCalendars(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	result, err := h.entity.ListCalendars(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity ListCalendars failed", "userID", user.UserID, "err", err)
+		mapUpstreamErrorGeneric(w, err, "Failed to list calendars.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// AddBusinessTime handles POST /calendars/{id}/add-business-time.
+func (h *CalendarHandler) AddBusinessTime(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	if !calendarIDRe.MatchString(id) {
+		writeError(w, http.StatusBadRequest, "Invalid calendar ID.")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+
+	if !json.Valid(body) {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+
+	result, err := h.entity.AddBusinessTime(r.Context(), id, body)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity AddBusinessTime failed", "userID", user.UserID, "calendarID", id, "err", err)
+		mapUpstreamErrorGeneric(w, err, "Failed to compute the business time.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
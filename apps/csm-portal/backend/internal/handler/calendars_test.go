@@ -0,0 +1,82 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func addBusinessTime(h *CalendarHandler, id, body string) *httptest.ResponseRecorder {
+	r := withUser(httptest.NewRequest(http.MethodPost, "/calendars/"+id+"/add-business-time", strings.NewReader(body)))
+	r.SetPathValue("id", id)
+	w := httptest.NewRecorder()
+	h.AddBusinessTime(w, r)
+	return w
+}
+
+func TestAddBusinessTime(t *testing.T) {
+	t.Run("forwards the calendar and body", func(t *testing.T) {
+		const body = `{"start":"2026-12-24T16:00:00Z","hours":4}`
+		m := &mockEntityCalendarClient{}
+		w := addBusinessTime(NewCalendarHandler(m), "uk", body)
+		assertStatus(t, w, http.StatusOK)
+		assertContentType(t, w, "application/json")
+		if m.calendarID != "uk" || string(m.body) != body {
+			t.Errorf("upstream got %q %s", m.calendarID, m.body)
+		}
+	})
+
+	t.Run("rejects a malformed calendar ID", func(t *testing.T) {
+		m := &mockEntityCalendarClient{}
+		w := addBusinessTime(NewCalendarHandler(m), "..%2Fcases", `{}`)
+		assertStatus(t, w, http.StatusBadRequest)
+		if m.calendarID != "" {
+			t.Error("a malformed ID reached the entity service")
+		}
+	})
+
+	t.Run("rejects invalid JSON body", func(t *testing.T) {
+		w := addBusinessTime(NewCalendarHandler(&mockEntityCalendarClient{}), "uk", `not-json`)
+		assertStatus(t, w, http.StatusBadRequest)
+		assertErrorMessage(t, w, ErrMsgBadRequest)
+	})
+
+	t.Run("requires authenticated user", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/calendars/uk/add-business-time", strings.NewReader(`{}`))
+		r.SetPathValue("id", "uk")
+		w := httptest.NewRecorder()
+		NewCalendarHandler(&mockEntityCalendarClient{}).AddBusinessTime(w, r)
+		assertStatus(t, w, http.StatusUnauthorized)
+	})
+
+	t.Run("upstream errors are mapped correctly", func(t *testing.T) {
+		for _, tc := range upstreamErrorsGeneric("Failed to compute the business time.") {
+			t.Run(tc.name, func(t *testing.T) {
+				m := &mockEntityCalendarClient{addBusinessTimeFn: func(context.Context, string, []byte) ([]byte, error) {
+					return nil, tc.err
+				}}
+				w := addBusinessTime(NewCalendarHandler(m), "uk", `{"days":1}`)
+				assertStatus(t, w, tc.wantCode)
+				assertErrorMessage(t, w, tc.wantMsg)
+			})
+		}
+	})
+}
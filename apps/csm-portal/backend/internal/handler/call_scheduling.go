@@ -0,0 +1,271 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/scheduling"
+)
+
+// callScheduler abstracts the scheduler used by CallSchedulingHandler.
+type callScheduler interface {
+	Suggest(ctx context.Context, req scheduling.SuggestRequest) (scheduling.Suggestion, error)
+	Schedule(ctx context.Context, req scheduling.ScheduleRequest) (scheduling.ScheduleResult, error)
+	Invite(ctx context.Context, caseID, callRequestID string) (string, error)
+}
+
+// CallSchedulingHandler suggests, books and sends invites for call request
+// slots.
+type CallSchedulingHandler struct {
+	scheduler callScheduler
+}
+
+// NewCallSchedulingHandler creates a CallSchedulingHandler.
+func NewCallSchedulingHandler(scheduler callScheduler) *CallSchedulingHandler {
+	return &CallSchedulingHandler{scheduler: scheduler}
+}
+
+// suggestSlotsRequest is the POST /call-requests/{id}/suggest-slots body.
+type suggestSlotsRequest struct {
+	CaseID             string `json:"caseId"`
+	Assignee           string `json:"assignee"`
+	CalendarID         string `json:"calendarId"`
+	CustomerCalendarID string `json:"customerCalendarId"`
+	CustomerTimezone   string `json:"customerTimezone"`
+	DurationMin        int    `json:"durationMin"`
+	Limit              int    `json:"limit"`
+}
+
+// scheduleCallRequest is the POST /call-requests/{id}/schedule body.
+type scheduleCallRequest struct {
+	CaseID            string `json:"caseId"`
+	MeetingDate       string `json:"meetingDate"`
+	DurationInMinutes int    `json:"durationInMinutes"`
+	Assignee          string `json:"assignee"`
+	Override          bool   `json:"override"`
+}
+
+// conflictBody is the 409 payload for a refused double booking.
+type conflictBody struct {
+	Message   string               `json:"message"`
+	Conflicts []scheduling.Booking `json:"conflicts"`
+}
+
+// SuggestSlots handles POST /call-requests/{id}/suggest-slots.
+// Returns conflict-free slots for the call request -- the customer's
+// preferred times that fit the assignee's working hours and scheduled calls,
+// then alternatives -- on both parties' clocks, with the reason each
+// preferred time that does not fit was left out.
+func (h *CallSchedulingHandler) SuggestSlots(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	callRequestID := r.PathValue("id")
+	if !uuidRe.MatchString(callRequestID) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+
+	var req suggestSlotsRequest
+	if !decodeSchedulingBody(w, r, &req) {
+		return
+	}
+	if !uuidRe.MatchString(req.CaseID) {
+		writeError(w, http.StatusBadRequest, "caseId must be a UUID.")
+		return
+	}
+	if (req.CalendarID != "" && !calendarIDRe.MatchString(req.CalendarID)) ||
+		(req.CustomerCalendarID != "" && !calendarIDRe.MatchString(req.CustomerCalendarID)) {
+		writeError(w, http.StatusBadRequest, "Invalid calendar ID.")
+		return
+	}
+	if req.Limit < 0 || req.Limit > scheduling.MaxLimit {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and 20.")
+		return
+	}
+
+	got, err := h.scheduler.Suggest(r.Context(), scheduling.SuggestRequest{
+		CaseID:             req.CaseID,
+		CallRequestID:      callRequestID,
+		Assignee:           req.Assignee,
+		CalendarID:         req.CalendarID,
+		CustomerCalendarID: req.CustomerCalendarID,
+		CustomerTimezone:   req.CustomerTimezone,
+		DurationMin:        req.DurationMin,
+		Limit:              req.Limit,
+	})
+	if err != nil {
+		if !writeSchedulingError(w, err) {
+			slog.ErrorContext(r.Context(), "call request slot suggestion failed", "userID", user.UserID, "callRequestID", callRequestID, "err", err)
+			mapUpstreamErrorGeneric(w, err, "Failed to suggest call request slots.")
+		}
+		return
+	}
+	writeJSONValue(w, http.StatusOK, got)
+}
+
+// ScheduleCallRequest handles POST /call-requests/{id}/schedule.
+// Books the call request for the assignee and returns the updated call
+// request with an iCalendar invite. A slot that overlaps another of the
+// assignee's scheduled calls is refused with 409 and the conflicting calls
+// unless "override" is set.
+func (h *CallSchedulingHandler) ScheduleCallRequest(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	callRequestID := r.PathValue("id")
+	if !uuidRe.MatchString(callRequestID) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+
+	var req scheduleCallRequest
+	if !decodeSchedulingBody(w, r, &req) {
+		return
+	}
+	if !uuidRe.MatchString(req.CaseID) {
+		writeError(w, http.StatusBadRequest, "caseId must be a UUID.")
+		return
+	}
+	start, err := time.Parse(time.RFC3339, req.MeetingDate)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "meetingDate must be an RFC3339 timestamp.")
+		return
+	}
+
+	got, err := h.scheduler.Schedule(r.Context(), scheduling.ScheduleRequest{
+		CaseID:        req.CaseID,
+		CallRequestID: callRequestID,
+		Start:         start,
+		DurationMin:   req.DurationInMinutes,
+		Assignee:      req.Assignee,
+		Override:      req.Override,
+	})
+	if err != nil {
+		var conflict *scheduling.ConflictError
+		switch {
+		case errors.As(err, &conflict):
+			writeJSONValue(w, http.StatusConflict, conflictBody{
+				Message:   "The slot overlaps another call scheduled for the assignee.",
+				Conflicts: conflict.Conflicts,
+			})
+		case writeSchedulingError(w, err):
+		default:
+			slog.ErrorContext(r.Context(), "call request scheduling failed", "userID", user.UserID, "callRequestID", callRequestID, "err", err)
+			mapUpstreamError(w, err, "Failed to schedule call request.")
+		}
+		return
+	}
+	if len(got.Conflicts) > 0 {
+		slog.InfoContext(r.Context(), "call request double-booked by override", "userID", user.UserID, "callRequestID", callRequestID, "conflicts", len(got.Conflicts))
+	}
+	writeJSONValue(w, http.StatusOK, got)
+}
+
+// GetCallRequestInvite handles GET /call-requests/{id}/invite.ics?caseId=.
+// Returns the iCalendar invite for a scheduled call request.
+func (h *CallSchedulingHandler) GetCallRequestInvite(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	callRequestID := r.PathValue("id")
+	caseID := r.URL.Query().Get("caseId")
+	if !uuidRe.MatchString(callRequestID) || !uuidRe.MatchString(caseID) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+
+	invite, err := h.scheduler.Invite(r.Context(), caseID, callRequestID)
+	if err != nil {
+		if !writeSchedulingError(w, err) {
+			slog.ErrorContext(r.Context(), "call request invite failed", "userID", user.UserID, "callRequestID", callRequestID, "err", err)
+			mapUpstreamErrorGeneric(w, err, "Failed to build call request invite.")
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8; method=REQUEST")
+	w.Header().Set("Content-Disposition", `attachment; filename="call-request.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, invite)
+}
+
+// decodeSchedulingBody reads a scheduling request body into v, rejecting
+// unknown fields, and writes the error response when it cannot.
+func decodeSchedulingBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return false
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return false
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeSchedulingError writes the response for the scheduler's own errors and
+// reports whether err was one of them.
+func writeSchedulingError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, scheduling.ErrCallRequestNotFound):
+		writeError(w, http.StatusNotFound, "Call request not found on the case.")
+	case errors.Is(err, scheduling.ErrUnknownCalendar):
+		writeError(w, http.StatusBadRequest, "Unknown calendar ID.")
+	case errors.Is(err, scheduling.ErrInvalidTimezone):
+		writeError(w, http.StatusBadRequest, "customerTimezone must be an IANA timezone name.")
+	case errors.Is(err, scheduling.ErrNoAssignee):
+		writeError(w, http.StatusBadRequest, "An assignee is required.")
+	case errors.Is(err, scheduling.ErrUnknownAssignee):
+		writeError(w, http.StatusBadRequest, "The assignee must be a platform user id or a known user's email.")
+	case errors.Is(err, scheduling.ErrInvalidDuration):
+		writeError(w, http.StatusBadRequest, "Duration must be between 1 and 480 minutes.")
+	case errors.Is(err, scheduling.ErrInPast):
+		writeError(w, http.StatusBadRequest, "Meeting time must be in the future.")
+	case errors.Is(err, scheduling.ErrNotScheduled):
+		writeError(w, http.StatusConflict, "The call request is not scheduled.")
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/apierror"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/scheduling"
+)
+
+const (
+	testCallRequestID = "aaaaaaaa-bbbb-4ccc-8ddd-eeeeeeeeeeee"
+	testCallCaseID    = "11111111-2222-4333-8444-555555555555"
+)
+
+// mockCallScheduler records the requests it was given and returns err, or a
+// fixed result.
+type mockCallScheduler struct {
+	suggest  scheduling.SuggestRequest
+	schedule scheduling.ScheduleRequest
+	err      error
+}
+
+func (m *mockCallScheduler) Suggest(_ context.Context, req scheduling.SuggestRequest) (scheduling.Suggestion, error) {
+	m.suggest = req
+	return scheduling.Suggestion{CallRequestID: req.CallRequestID, Slots: []scheduling.Slot{{Preferred: true}}}, m.err
+}
+
+func (m *mockCallScheduler) Schedule(_ context.Context, req scheduling.ScheduleRequest) (scheduling.ScheduleResult, error) {
+	m.schedule = req
+	return scheduling.ScheduleResult{CallRequest: []byte(`{}`), Invite: "BEGIN:VCALENDAR\r\n"}, m.err
+}
+
+func (m *mockCallScheduler) Invite(_ context.Context, _, _ string) (string, error) {
+	return "BEGIN:VCALENDAR\r\n", m.err
+}
+
+func callSchedulingRequest(method, path, body string) *http.Request {
+	r := withUser(httptest.NewRequest(method, path, strings.NewReader(body)))
+	r.SetPathValue("id", testCallRequestID)
+	return r
+}
+
+func TestSuggestSlots(t *testing.T) {
+	m := &mockCallScheduler{}
+	h := NewCallSchedulingHandler(m)
+	w := httptest.NewRecorder()
+	h.SuggestSlots(w, callSchedulingRequest(http.MethodPost, "/call-requests/x/suggest-slots",
+		`{"caseId":"`+testCallCaseID+`","calendarId":"lk","customerTimezone":"Europe/London","limit":3}`))
+	assertStatus(t, w, http.StatusOK)
+	want := scheduling.SuggestRequest{CaseID: testCallCaseID, CallRequestID: testCallRequestID, CalendarID: "lk", CustomerTimezone: "Europe/London", Limit: 3}
+	if m.suggest != want {
+		t.Errorf("Suggest(%+v), want %+v", m.suggest, want)
+	}
+	if got := decodeJSON[scheduling.Suggestion](t, w); got.CallRequestID != testCallRequestID || len(got.Slots) != 1 {
+		t.Errorf("suggestion = %+v", got)
+	}
+
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"unknown field", `{"caseId":"` + testCallCaseID + `","when":"now"}`, nil, http.StatusBadRequest},
+		{"no case", `{}`, nil, http.StatusBadRequest},
+		{"bad calendar", `{"caseId":"` + testCallCaseID + `","calendarId":"../x"}`, nil, http.StatusBadRequest},
+		{"limit too high", `{"caseId":"` + testCallCaseID + `","limit":21}`, nil, http.StatusBadRequest},
+		{"not found", `{"caseId":"` + testCallCaseID + `"}`, scheduling.ErrCallRequestNotFound, http.StatusNotFound},
+		{"unknown calendar", `{"caseId":"` + testCallCaseID + `"}`, scheduling.ErrUnknownCalendar, http.StatusBadRequest},
+		{"no assignee", `{"caseId":"` + testCallCaseID + `"}`, scheduling.ErrNoAssignee, http.StatusBadRequest},
+		{"upstream", `{"caseId":"` + testCallCaseID + `"}`, &apierror.Error{StatusCode: http.StatusBadGateway}, http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewCallSchedulingHandler(&mockCallScheduler{err: tc.err}).SuggestSlots(w, callSchedulingRequest(http.MethodPost, "/call-requests/x/suggest-slots", tc.body))
+			assertStatus(t, w, tc.status)
+		})
+	}
+
+	w = httptest.NewRecorder()
+	h.SuggestSlots(w, httptest.NewRequest(http.MethodPost, "/call-requests/x/suggest-slots", nil))
+	assertStatus(t, w, http.StatusUnauthorized)
+}
+
+func TestScheduleCallRequest(t *testing.T) {
+	body := `{"caseId":"` + testCallCaseID + `","meetingDate":"2026-10-20T13:30:00+05:30","durationInMinutes":60,"assignee":"alice@example.com"}`
+
+	m := &mockCallScheduler{}
+	w := httptest.NewRecorder()
+	NewCallSchedulingHandler(m).ScheduleCallRequest(w, callSchedulingRequest(http.MethodPost, "/call-requests/x/schedule", body))
+	assertStatus(t, w, http.StatusOK)
+	if !m.schedule.Start.Equal(time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)) || m.schedule.DurationMin != 60 ||
+		m.schedule.Assignee != "alice@example.com" || m.schedule.Override || m.schedule.CaseID != testCallCaseID {
+		t.Errorf("Schedule(%+v)", m.schedule)
+	}
+	if got := decodeJSON[scheduling.ScheduleResult](t, w); got.Invite != "BEGIN:VCALENDAR\r\n" {
+		t.Errorf("result = %+v", got)
+	}
+
+	t.Run("a double booking is refused with the conflicts", func(t *testing.T) {
+		m := &mockCallScheduler{err: &scheduling.ConflictError{Conflicts: []scheduling.Booking{{CallRequestID: "cr2", Number: "CR2"}}}}
+		w := httptest.NewRecorder()
+		NewCallSchedulingHandler(m).ScheduleCallRequest(w, callSchedulingRequest(http.MethodPost, "/call-requests/x/schedule", body))
+		assertStatus(t, w, http.StatusConflict)
+		if got := decodeJSON[conflictBody](t, w); len(got.Conflicts) != 1 || got.Conflicts[0].Number != "CR2" {
+			t.Errorf("body = %+v", got)
+		}
+	})
+
+	t.Run("override is passed on", func(t *testing.T) {
+		m := &mockCallScheduler{}
+		w := httptest.NewRecorder()
+		NewCallSchedulingHandler(m).ScheduleCallRequest(w, callSchedulingRequest(http.MethodPost, "/call-requests/x/schedule",
+			strings.Replace(body, "}", `,"override":true}`, 1)))
+		assertStatus(t, w, http.StatusOK)
+		if !m.schedule.Override {
+			t.Error("override was not passed on")
+		}
+	})
+
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"bad meeting date", strings.Replace(body, "2026-10-20T13:30:00+05:30", "2026-10-20 08:00", 1), nil, http.StatusBadRequest},
+		{"past", body, scheduling.ErrInPast, http.StatusBadRequest},
+		{"bad duration", body, scheduling.ErrInvalidDuration, http.StatusBadRequest},
+		{"rejected upstream", body, &apierror.Error{StatusCode: http.StatusBadRequest}, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewCallSchedulingHandler(&mockCallScheduler{err: tc.err}).ScheduleCallRequest(w, callSchedulingRequest(http.MethodPost, "/call-requests/x/schedule", tc.body))
+			assertStatus(t, w, tc.status)
+		})
+	}
+}
+
+func TestGetCallRequestInvite(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewCallSchedulingHandler(&mockCallScheduler{}).GetCallRequestInvite(w,
+		callSchedulingRequest(http.MethodGet, "/call-requests/x/invite.ics?caseId="+testCallCaseID, ""))
+	assertStatus(t, w, http.StatusOK)
+	assertContentType(t, w, "text/calendar; charset=utf-8; method=REQUEST")
+	if w.Body.String() != "BEGIN:VCALENDAR\r\n" {
+		t.Errorf("body = %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	NewCallSchedulingHandler(&mockCallScheduler{}).GetCallRequestInvite(w, callSchedulingRequest(http.MethodGet, "/call-requests/x/invite.ics", ""))
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w = httptest.NewRecorder()
+	NewCallSchedulingHandler(&mockCallScheduler{err: scheduling.ErrNotScheduled}).GetCallRequestInvite(w,
+		callSchedulingRequest(http.MethodGet, "/call-requests/x/invite.ics?caseId="+testCallCaseID, ""))
+	assertStatus(t, w, http.StatusConflict)
+}
+
+// mockConflictChecker reports conflicts for every booking, recording the
+// last one it checked.
+type mockConflictChecker struct {
+	start     time.Time
+	conflicts []scheduling.Booking
+}
+
+func (m *mockConflictChecker) Conflicts(_ context.Context, _, _ string, start time.Time, _ int, _ string) ([]scheduling.Booking, error) {
+	m.start = start
+	return m.conflicts, nil
+}
+
+func TestPatchCallRequest_ChecksConflicts(t *testing.T) {
+	var forwarded string
+	entity := &mockEntityCaseClient{patchCallRequestFn: func(_ context.Context, _ string, body []byte) ([]byte, error) {
+		forwarded = string(body)
+		return []byte(`{}`), nil
+	}}
+	checker := &mockConflictChecker{conflicts: []scheduling.Booking{{CallRequestID: "cr2", Number: "CR2"}}}
+	h := NewCaseHandler(entity).WithScheduler(checker)
+	patch := func(body string) *httptest.ResponseRecorder {
+		r := withUser(httptest.NewRequest(http.MethodPatch, "/cases/x/call-requests/y", strings.NewReader(body)))
+		r.SetPathValue("caseId", testCallCaseID)
+		r.SetPathValue("callRequestId", testCallRequestID)
+		w := httptest.NewRecorder()
+		h.PatchCallRequest(w, r)
+		return w
+	}
+
+	w := patch(`{"state":"scheduled","meetingDate":"2026-10-20T05:00:00Z","durationInMinutes":60,"assignee":"alice@example.com"}`)
+	assertStatus(t, w, http.StatusConflict)
+	if got := decodeJSON[conflictBody](t, w); len(got.Conflicts) != 1 || got.Conflicts[0].Number != "CR2" {
+		t.Errorf("conflict body = %+v", got)
+	}
+	if forwarded != "" {
+		t.Errorf("a double booking was forwarded: %s", forwarded)
+	}
+	if !checker.start.Equal(time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC)) {
+		t.Errorf("checked start = %s", checker.start)
+	}
+
+	// Overriding books it anyway, without passing override on.
+	w = patch(`{"state":"scheduled","meetingDate":"2026-10-20T05:00:00Z","override":true}`)
+	assertStatus(t, w, http.StatusOK)
+	if strings.Contains(forwarded, "override") || !strings.Contains(forwarded, `"caseId":"`+testCallCaseID+`"`) {
+		t.Errorf("forwarded body = %s", forwarded)
+	}
+
+	// Other transitions are not checked.
+	checker.start, forwarded = time.Time{}, ""
+	w = patch(`{"state":"rejected"}`)
+	assertStatus(t, w, http.StatusOK)
+	if !checker.start.IsZero() || forwarded == "" {
+		t.Errorf("a rejection was checked for conflicts, or not forwarded: %s", forwarded)
+	}
+
+	assertStatus(t, patch(`{"state":"scheduled","meetingDate":"soon"}`), http.StatusBadRequest)
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/cannedresponses"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+const (
+	errMsgInvalidCannedResponse = "Invalid canned response: "
+	errMsgCannedResponseEdit    = "You are not permitted to change this canned response."
+	errMsgCannedResponseRender  = "The canned response could not be rendered for this case: "
+)
+
+// adminRole is the platform role that may change global canned responses.
+const adminRole = "admin"
+
+// cannedResponseStore abstracts the template store used by
+// CannedResponseHandler, allowing the handler to be tested independently of
+// the file-backed store.
+type cannedResponseStore interface {
+	List() []cannedresponses.Template
+	Get(id string) (cannedresponses.Template, error)
+	Create(in cannedresponses.Input, author string) (cannedresponses.Template, error)
+	Update(id string, in cannedresponses.Input, editor string) (cannedresponses.Template, error)
+	Delete(id string) error
+}
+
+// entityCannedResponseClient abstracts the entity service calls
+// CannedResponseHandler makes.
+type entityCannedResponseClient interface {
+	GetUserMe(ctx context.Context) ([]byte, error)
+	GetCase(ctx context.Context, id string) ([]byte, error)
+}
+
+// caseCommentCreator creates a case comment with every guard a directly
+// posted one goes through (see CaseHandler.CreateCaseComment).
+type caseCommentCreator interface {
+	CreateCaseComment(w http.ResponseWriter, r *http.Request)
+}
+
+// CannedResponseHandler handles HTTP requests for the canned response
+// library and for posting a rendered one as a case comment.
+type CannedResponseHandler struct {
+	store    cannedResponseStore
+	entity   entityCannedResponseClient
+	dir      *directory.Directory
+	comments caseCommentCreator
+}
+
+// NewCannedResponseHandler creates a CannedResponseHandler. comments creates
+// the case comment a template is rendered into.
+func NewCannedResponseHandler(store cannedResponseStore, entity entityCannedResponseClient, dir *directory.Directory, comments caseCommentCreator) *CannedResponseHandler {
+	return &CannedResponseHandler{store: store, entity: entity, dir: dir, comments: comments}
+}
+
+// resolveCaller resolves the caller's teams and admin role from GET /users/me.
+// The email is the token's, which personal templates are keyed by. A failed
+// lookup is returned so the caller fails closed.
+func (h *CannedResponseHandler) resolveCaller(r *http.Request, user *middleware.UserInfo) (cannedresponses.Caller, error) {
+	raw, err := h.entity.GetUserMe(r.Context())
+	if err != nil {
+		return cannedresponses.Caller{}, err
+	}
+	var me entityUserMeResponse
+	if err := json.Unmarshal(raw, &me); err != nil {
+		return cannedresponses.Caller{}, err
+	}
+	c := cannedresponses.Caller{Email: user.Email, Admin: slices.Contains(me.Roles, adminRole)}
+	for _, g := range me.Groups {
+		if team, ok := h.dir.TeamByGroupName(g.Name); ok {
+			c.TeamKeys = append(c.TeamKeys, team.Key)
+		}
+	}
+	return c, nil
+}
+
+// caller authenticates the request and resolves its caller, writing the error
+// response and returning false when either fails.
+func (h *CannedResponseHandler) caller(w http.ResponseWriter, r *http.Request) (*middleware.UserInfo, cannedresponses.Caller, bool) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return nil, cannedresponses.Caller{}, false
+	}
+	c, err := h.resolveCaller(r, user)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity GetUserMe failed while resolving the caller for canned responses", "userID", user.UserID, "err", err)
+		mapUpstreamErrorGeneric(w, err, ErrMsgInternal)
+		return nil, cannedresponses.Caller{}, false
+	}
+	return user, c, true
+}
+
+// visible returns template id if the caller can see it. A template the caller
+// cannot see is reported as not found, so its existence is not disclosed.
+func (h *CannedResponseHandler) visible(w http.ResponseWriter, id string, c cannedresponses.Caller) (cannedresponses.Template, bool) {
+	t, err := h.store.Get(id)
+	if err != nil || !t.VisibleTo(c) {
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		return cannedresponses.Template{}, false
+	}
+	return t, true
+}
+
+// readInput reads and validates a template from the request body, writing
+// the error response and returning false when it is not acceptable.
+func (h *CannedResponseHandler) readInput(w http.ResponseWriter, r *http.Request) (cannedresponses.Input, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return cannedresponses.Input{}, false
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return cannedresponses.Input{}, false
+	}
+	var in cannedresponses.Input
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return cannedresponses.Input{}, false
+	}
+	knownTeam := func(key string) bool { _, ok := h.dir.TeamByKey(key); return ok }
+	if err := in.Validate(knownTeam); err != nil {
+		writeError(w, http.StatusBadRequest, errMsgInvalidCannedResponse+err.Error()+".")
+		return cannedresponses.Input{}, false
+	}
+	return in, true
+}
+
+// ListCannedResponses handles GET /canned-responses.
+// Returns the templates the caller can see, by name. The optional scope,
+// product and issueType query parameters narrow the list; a template with no
+// products or issue types matches any.
+func (h *CannedResponseHandler) ListCannedResponses(w http.ResponseWriter, r *http.Request) {
+	_, c, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	scope := cannedresponses.Scope(q.Get("scope"))
+	out := []cannedresponses.Template{}
+	for _, t := range h.store.List() {
+		if !t.VisibleTo(c) || (scope != "" && t.Scope != scope) || !t.Matches(q.Get("product"), q.Get("issueType")) {
+			continue
+		}
+		out = append(out, t)
+	}
+	writeJSONValue(w, http.StatusOK, out)
+}
+
+// GetCannedResponse handles GET /canned-responses/{templateId}.
+func (h *CannedResponseHandler) GetCannedResponse(w http.ResponseWriter, r *http.Request) {
+	_, c, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	t, ok := h.visible(w, r.PathValue("templateId"), c)
+	if !ok {
+		return
+	}
+	writeJSONValue(w, http.StatusOK, t)
+}
+
+// CreateCannedResponse handles POST /canned-responses.
+// A team template can only be shared with the caller's own team, and a global
+// one only created by an admin.
+func (h *CannedResponseHandler) CreateCannedResponse(w http.ResponseWriter, r *http.Request) {
+	user, c, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	in, ok := h.readInput(w, r)
+	if !ok {
+		return
+	}
+	if !(cannedresponses.Template{Input: in, Owner: c.Email}).EditableBy(c) {
+		writeError(w, http.StatusForbidden, errMsgCannedResponseEdit)
+		return
+	}
+	t, err := h.store.Create(in, c.Email)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "saving canned response failed", "userID", user.UserID, "err", err)
+		writeError(w, http.StatusInternalServerError, "Failed to save canned response.")
+		return
+	}
+	writeJSONValue(w, http.StatusCreated, t)
+}
+
+// UpdateCannedResponse handles PUT /canned-responses/{templateId}.
+// The body replaces the template's name, body, scope and categories. The
+// caller must be able to change the template both as it was and as it will
+// be, so a team template cannot be handed to another team.
+func (h *CannedResponseHandler) UpdateCannedResponse(w http.ResponseWriter, r *http.Request) {
+	user, c, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	prev, ok := h.visible(w, r.PathValue("templateId"), c)
+	if !ok {
+		return
+	}
+	in, ok := h.readInput(w, r)
+	if !ok {
+		return
+	}
+	owner := prev.Owner
+	if owner == "" {
+		owner = c.Email
+	}
+	if !prev.EditableBy(c) || !(cannedresponses.Template{Input: in, Owner: owner}).EditableBy(c) {
+		writeError(w, http.StatusForbidden, errMsgCannedResponseEdit)
+		return
+	}
+	t, err := h.store.Update(prev.ID, in, c.Email)
+	if errors.Is(err, cannedresponses.ErrNotFound) {
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "saving canned response failed", "userID", user.UserID, "templateID", prev.ID, "err", err)
+		writeError(w, http.StatusInternalServerError, "Failed to save canned response.")
+		return
+	}
+	writeJSONValue(w, http.StatusOK, t)
+}
+
+// DeleteCannedResponse handles DELETE /canned-responses/{templateId}.
+func (h *CannedResponseHandler) DeleteCannedResponse(w http.ResponseWriter, r *http.Request) {
+	user, c, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+	t, ok := h.visible(w, r.PathValue("templateId"), c)
+	if !ok {
+		return
+	}
+	if !t.EditableBy(c) {
+		writeError(w, http.StatusForbidden, errMsgCannedResponseEdit)
+		return
+	}
+	err := h.store.Delete(t.ID)
+	if errors.Is(err, cannedresponses.ErrNotFound) {
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "deleting canned response failed", "userID", user.UserID, "templateID", t.ID, "err", err)
+		writeError(w, http.StatusInternalServerError, "Failed to delete canned response.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cannedCommentRequest is the POST /cases/{id}/comments/from-template body.
+type cannedCommentRequest struct {
+	TemplateID string `json:"templateId"`
+	// Type is the comment type, "comment" or "work_note"; empty is "comment".
+	Type string `json:"type"`
+	// Preview renders the template without creating the comment.
+	Preview bool `json:"preview"`
+}
+
+// CreateCaseCommentFromTemplate handles POST /cases/{id}/comments/from-template.
+// Renders the template against the case and creates the result as a comment,
+// through exactly the guards and @mention handling of POST /cases/{id}/comments.
+// With "preview": true it returns {"content": ...} instead, and creates
+// nothing.
+func (h *CannedResponseHandler) CreateCaseCommentFromTemplate(w http.ResponseWriter, r *http.Request) {
+	user, c, ok := h.caller(w, r)
+	if !ok {
+		return
+	}
+
+	caseID := r.PathValue("id")
+	if caseID == "" {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+	var req cannedCommentRequest
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil || req.TemplateID == "" {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+	if req.Type == "" {
+		req.Type = "comment"
+	}
+	if req.Type != "comment" && req.Type != "work_note" {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+
+	t, ok := h.visible(w, req.TemplateID, c)
+	if !ok {
+		return
+	}
+	raw, err := h.entity.GetCase(r.Context(), caseID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity GetCase failed while rendering a canned response", "userID", user.UserID, "caseID", caseID, "err", err)
+		mapUpstreamErrorGeneric(w, err, "Failed to create case comment.")
+		return
+	}
+	view, err := cannedresponses.ParseCaseView(raw)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to parse case for a canned response", "userID", user.UserID, "caseID", caseID, "err", err)
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+	content, err := t.Render(view)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, errMsgCannedResponseRender+err.Error()+".")
+		return
+	}
+
+	if req.Preview {
+		writeJSONValue(w, http.StatusOK, map[string]string{"content": content})
+		return
+	}
+
+	comment, err := json.Marshal(map[string]string{"content": content, "type": req.Type})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(comment))
+	r.ContentLength = int64(len(comment))
+	h.comments.CreateCaseComment(w, r)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/cannedresponses"
+)
+
+const cannedCaseJSON = `{"id":"case-1","number":"CS0012345","state":"work_in_progress",
+	"createdBy":{"name":"Chris Customer","email":"chris@acme.example"},
+	"assignedEngineer":{"id":"e1","name":"Agent Smith","email":"agent@example.com"},
+	"project":{"id":"p1","name":"Acme Prod"},
+	"deployedProduct":{"id":"d1","product":{"id":"pr1","name":"WSO2 API Manager"}}}`
+
+// cannedFixture is a CannedResponseHandler over an in-memory store, for a
+// caller in team abt-1 whose roles are given.
+type cannedFixture struct {
+	h        *CannedResponseHandler
+	store    *cannedresponses.Store
+	comments []string // bodies of created case comments
+}
+
+func newCannedFixture(t *testing.T, roles ...string) *cannedFixture {
+	t.Helper()
+	store, err := cannedresponses.OpenStore("")
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	f := &cannedFixture{store: store}
+	me, _ := json.Marshal(map[string]any{"id": "me-1", "email": testUser.Email, "roles": roles, "groups": []map[string]string{{"name": "ABT One"}}})
+	client := &mockEntityCaseClient{
+		getUserMeFn: func(_ context.Context) ([]byte, error) { return me, nil },
+		getCaseFn: func(_ context.Context, _ string) ([]byte, error) {
+			return []byte(cannedCaseJSON), nil
+		},
+		createCaseCommentFn: func(_ context.Context, _ string, body []byte) ([]byte, error) {
+			f.comments = append(f.comments, string(body))
+			return []byte(`{"message":"Comment created.","comment":{"id":"c1"}}`), nil
+		},
+	}
+	f.h = NewCannedResponseHandler(store, client, testDirectory(t), NewCaseHandler(client))
+	return f
+}
+
+func (f *cannedFixture) do(method, target, body string, pathValues ...string) *httptest.ResponseRecorder {
+	r := withUser(httptest.NewRequest(method, target, strings.NewReader(body)))
+	for i := 0; i+1 < len(pathValues); i += 2 {
+		r.SetPathValue(pathValues[i], pathValues[i+1])
+	}
+	w := httptest.NewRecorder()
+	switch {
+	case strings.HasSuffix(target, "/from-template"):
+		f.h.CreateCaseCommentFromTemplate(w, r)
+	case method == http.MethodPost:
+		f.h.CreateCannedResponse(w, r)
+	case method == http.MethodPut:
+		f.h.UpdateCannedResponse(w, r)
+	case method == http.MethodDelete:
+		f.h.DeleteCannedResponse(w, r)
+	case r.PathValue("templateId") != "":
+		f.h.GetCannedResponse(w, r)
+	default:
+		f.h.ListCannedResponses(w, r)
+	}
+	return w
+}
+
+func (f *cannedFixture) mustCreate(t *testing.T, in cannedresponses.Input, author string) cannedresponses.Template {
+	t.Helper()
+	tmpl, err := f.store.Create(in, author)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
+
+func TestCannedResponses_RequiresAuth(t *testing.T) {
+	f := newCannedFixture(t)
+	w := httptest.NewRecorder()
+	f.h.ListCannedResponses(w, httptest.NewRequest(http.MethodGet, "/canned-responses", nil))
+	assertStatus(t, w, http.StatusUnauthorized)
+}
+
+func TestCannedResponses_CreateAndScopes(t *testing.T) {
+	f := newCannedFixture(t, "agent")
+
+	w := f.do(http.MethodPost, "/canned-responses", `{"name":"Logs","body":"Hi {{.Customer.Name}}","scope":"team","teamKey":"abt-1","products":["WSO2 API Manager"],"issueTypes":["error"]}`)
+	assertStatus(t, w, http.StatusCreated)
+	created := decodeJSON[cannedresponses.Template](t, w)
+	if created.ID == "" || created.CreatedBy != testUser.Email || created.Owner != "" {
+		t.Errorf("created = %+v", created)
+	}
+
+	cases := []struct {
+		name, body string
+		status     int
+		msg        string
+	}{
+		{"another team", `{"name":"x","body":"x","scope":"team","teamKey":"beta"}`, http.StatusForbidden, errMsgCannedResponseEdit},
+		{"global without admin", `{"name":"x","body":"x","scope":"global"}`, http.StatusForbidden, errMsgCannedResponseEdit},
+		{"unknown team", `{"name":"x","body":"x","scope":"team","teamKey":"nope"}`, http.StatusBadRequest, errMsgInvalidCannedResponse + `unknown teamKey "nope".`},
+		{"unknown field", `{"name":"x","body":"{{.Customer.Phone}}","scope":"personal"}`, http.StatusBadRequest, ""},
+		{"unknown issue type", `{"name":"x","body":"x","scope":"personal","issueTypes":["outage"]}`, http.StatusBadRequest, errMsgInvalidCannedResponse + `unknown issue type "outage".`},
+		{"unknown key", `{"name":"x","body":"x","scope":"personal","owner":"someone@example.com"}`, http.StatusBadRequest, ErrMsgBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := f.do(http.MethodPost, "/canned-responses", tc.body)
+			assertStatus(t, w, tc.status)
+			if tc.msg != "" {
+				assertErrorMessage(t, w, tc.msg)
+			}
+		})
+	}
+
+	admin := newCannedFixture(t, "agent", adminRole)
+	assertStatus(t, admin.do(http.MethodPost, "/canned-responses", `{"name":"x","body":"x","scope":"global"}`), http.StatusCreated)
+}
+
+func TestCannedResponses_ListAndVisibility(t *testing.T) {
+	f := newCannedFixture(t)
+	global := f.mustCreate(t, cannedresponses.Input{Name: "B global", Body: "x", Scope: cannedresponses.ScopeGlobal, IssueTypes: []string{"question"}}, "admin@example.com")
+	team := f.mustCreate(t, cannedresponses.Input{Name: "A team", Body: "x", Scope: cannedresponses.ScopeTeam, TeamKey: "abt-1", Products: []string{"WSO2 API Manager"}}, "lead@example.com")
+	mine := f.mustCreate(t, cannedresponses.Input{Name: "C mine", Body: "x", Scope: cannedresponses.ScopePersonal}, testUser.Email)
+	otherTeam := f.mustCreate(t, cannedresponses.Input{Name: "D beta", Body: "x", Scope: cannedresponses.ScopeTeam, TeamKey: "beta"}, "beta@example.com")
+	theirs := f.mustCreate(t, cannedresponses.Input{Name: "E theirs", Body: "x", Scope: cannedresponses.ScopePersonal}, "someone@example.com")
+
+	ids := func(w *httptest.ResponseRecorder) []string {
+		t.Helper()
+		assertStatus(t, w, http.StatusOK)
+		var out []string
+		for _, tmpl := range decodeJSON[[]cannedresponses.Template](t, w) {
+			out = append(out, tmpl.ID)
+		}
+		return out
+	}
+	if got, want := ids(f.do(http.MethodGet, "/canned-responses", "")), []string{team.ID, global.ID, mine.ID}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("list = %v, want %v", got, want)
+	}
+	if got := ids(f.do(http.MethodGet, "/canned-responses?product=wso2+api+manager&issueType=error", "")); strings.Join(got, ",") != team.ID+","+mine.ID {
+		t.Errorf("filtered list = %v, want the team template and the uncategorised one", got)
+	}
+	if got := ids(f.do(http.MethodGet, "/canned-responses?scope=personal", "")); strings.Join(got, ",") != mine.ID {
+		t.Errorf("personal list = %v", got)
+	}
+
+	for _, id := range []string{otherTeam.ID, theirs.ID, "missing"} {
+		assertStatus(t, f.do(http.MethodGet, "/canned-responses/"+id, "", "templateId", id), http.StatusNotFound)
+		assertStatus(t, f.do(http.MethodDelete, "/canned-responses/"+id, "", "templateId", id), http.StatusNotFound)
+	}
+	w := f.do(http.MethodPut, "/canned-responses/"+global.ID, `{"name":"x","body":"x","scope":"global"}`, "templateId", global.ID)
+	assertStatus(t, w, http.StatusForbidden)
+	w = f.do(http.MethodPut, "/canned-responses/"+team.ID, `{"name":"Moved","body":"x","scope":"team","teamKey":"beta"}`, "templateId", team.ID)
+	assertStatus(t, w, http.StatusForbidden)
+
+	w = f.do(http.MethodPut, "/canned-responses/"+team.ID, `{"name":"Renamed","body":"y","scope":"personal"}`, "templateId", team.ID)
+	assertStatus(t, w, http.StatusOK)
+	if got := decodeJSON[cannedresponses.Template](t, w); got.Name != "Renamed" || got.Owner != testUser.Email || got.CreatedBy != "lead@example.com" {
+		t.Errorf("updated = %+v", got)
+	}
+	assertStatus(t, f.do(http.MethodDelete, "/canned-responses/"+mine.ID, "", "templateId", mine.ID), http.StatusNoContent)
+	assertStatus(t, f.do(http.MethodGet, "/canned-responses/"+mine.ID, "", "templateId", mine.ID), http.StatusNotFound)
+}
+
+func TestCreateCaseCommentFromTemplate(t *testing.T) {
+	f := newCannedFixture(t)
+	tmpl := f.mustCreate(t, cannedresponses.Input{
+		Name:  "Carbon logs",
+		Body:  "Hi {{.Customer.Name}}, please send the carbon logs from {{.Project}} ({{.Product}}) for {{.Number}}. -- {{.AssignedEngineer.Name}}",
+		Scope: cannedresponses.ScopeGlobal,
+	}, "admin@example.com")
+	const want = "Hi Chris Customer, please send the carbon logs from Acme Prod (WSO2 API Manager) for CS0012345. -- Agent Smith"
+
+	t.Run("preview renders without creating", func(t *testing.T) {
+		w := f.do(http.MethodPost, "/cases/case-1/comments/from-template", `{"templateId":"`+tmpl.ID+`","preview":true}`, "id", "case-1")
+		assertStatus(t, w, http.StatusOK)
+		if got := decodeJSON[map[string]string](t, w)["content"]; got != want {
+			t.Errorf("content = %q, want %q", got, want)
+		}
+		if len(f.comments) != 0 {
+			t.Errorf("preview created %v", f.comments)
+		}
+	})
+
+	t.Run("creates the rendered comment", func(t *testing.T) {
+		w := f.do(http.MethodPost, "/cases/case-1/comments/from-template", `{"templateId":"`+tmpl.ID+`","type":"work_note"}`, "id", "case-1")
+		assertStatus(t, w, http.StatusCreated)
+		if len(f.comments) != 1 {
+			t.Fatalf("created %v, want one comment", f.comments)
+		}
+		var got map[string]string
+		if err := json.Unmarshal([]byte(f.comments[0]), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got["content"] != want || got["type"] != "work_note" {
+			t.Errorf("comment = %v", got)
+		}
+	})
+
+	t.Run("rejects a bad request", func(t *testing.T) {
+		assertStatus(t, f.do(http.MethodPost, "/cases/case-1/comments/from-template", `{"templateId":"`+tmpl.ID+`","type":"note"}`, "id", "case-1"), http.StatusBadRequest)
+		assertStatus(t, f.do(http.MethodPost, "/cases/case-1/comments/from-template", `{}`, "id", "case-1"), http.StatusBadRequest)
+		assertStatus(t, f.do(http.MethodPost, "/cases/case-1/comments/from-template", `{"templateId":"missing"}`, "id", "case-1"), http.StatusNotFound)
+	})
+}
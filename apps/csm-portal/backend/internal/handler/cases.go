@@ -20,14 +20,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/scheduling"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/workflow"
 )
 
 var uuidRe = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
@@ -99,7 +103,15 @@ type entityCaseClient interface {
 // CaseHandler handles HTTP requests for case operations, delegating to the
 // entity service for data access.
 type CaseHandler struct {
-	entity entityCaseClient
+	entity    entityCaseClient
+	mentions  mentionProcessor
+	conflicts callConflictChecker
+}
+
+// callConflictChecker finds the calls a booking would double-book; satisfied
+// by *scheduling.Scheduler.
+type callConflictChecker interface {
+	Conflicts(ctx context.Context, caseID, callRequestID string, start time.Time, durationMin int, assignee string) ([]scheduling.Booking, error)
 }
 
 // NewCaseHandler creates a CaseHandler backed by the given entity client.
@@ -107,6 +119,21 @@ func NewCaseHandler(entity entityCaseClient) *CaseHandler {
 	return &CaseHandler{entity: entity}
 }
 
+// WithMentions makes CreateCaseComment resolve and notify the @mentions in
+// new comments. Without it they are left as plain text.
+func (h *CaseHandler) WithMentions(m mentionProcessor) *CaseHandler {
+	h.mentions = m
+	return h
+}
+
+// WithScheduler makes PatchCallRequest refuse a schedule or reschedule that
+// double-books the assignee, as CallSchedulingHandler.ScheduleCallRequest
+// does. Without it such a PATCH is forwarded unchecked.
+func (h *CaseHandler) WithScheduler(c callConflictChecker) *CaseHandler {
+	h.conflicts = c
+	return h
+}
+
 // resolveCurrentUserID returns the caller's platform user id — the id
 // GET /users/me resolves via the entity service — for comparing against a
 // platform record's own user references (e.g. a case's assigned engineer).
@@ -141,6 +168,28 @@ func (h *CaseHandler) resolveCurrentUserID(r *http.Request, user *middleware.Use
 	return me.ID
 }
 
+// resolveWorkflowCaller resolves who the caller is for the case workflow's
+// role and team-family guards, from the same GET /users/me record
+// resolveCurrentUserID uses. Returns nil when the lookup fails, which the
+// guards treat as matching no role and no team.
+func (h *CaseHandler) resolveWorkflowCaller(r *http.Request, user *middleware.UserInfo) *workflow.Caller {
+	raw, err := h.entity.GetUserMe(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity GetUserMe failed while resolving the caller for the case workflow", "userID", user.UserID, "err", err)
+		return nil
+	}
+	var me entityUserMeResponse
+	if err := json.Unmarshal(raw, &me); err != nil {
+		slog.ErrorContext(r.Context(), "entity GetUserMe: parse response failed while resolving the caller for the case workflow", "userID", user.UserID, "err", err)
+		return nil
+	}
+	groupNames := make([]string, 0, len(me.Groups))
+	for _, g := range me.Groups {
+		groupNames = append(groupNames, g.Name)
+	}
+	return workflow.Active().ResolveCaller(me.Roles, groupNames)
+}
+
 // maxRequestBodyBytes caps incoming request bodies at 1 MiB to prevent memory DoS.
 const maxRequestBodyBytes = 1 << 20
 
@@ -261,8 +310,11 @@ func (h *CaseHandler) CreateCaseComment(w http.ResponseWriter, r *http.Request)
 	}
 	_ = json.Unmarshal(body, &reqMeta) // body is already validated JSON
 
+	// Both guards below read the case; its number and subject name it in
+	// mention notifications.
+	var current []byte
 	if reqMeta.Type != "work_note" {
-		current, err := h.entity.GetCase(r.Context(), caseID)
+		current, err = h.entity.GetCase(r.Context(), caseID)
 		if err != nil {
 			slog.ErrorContext(r.Context(), "entity GetCase failed during comment guard", "userID", user.UserID, "caseID", caseID, "err", err)
 			mapUpstreamErrorGeneric(w, err, "Failed to create case comment.")
@@ -305,7 +357,7 @@ func (h *CaseHandler) CreateCaseComment(w http.ResponseWriter, r *http.Request)
 
 	// Work notes are blocked on closed cases (separate from the in-progress guard above).
 	if reqMeta.Type == "work_note" {
-		current, err := h.entity.GetCase(r.Context(), caseID)
+		current, err = h.entity.GetCase(r.Context(), caseID)
 		if err != nil {
 			slog.ErrorContext(r.Context(), "entity GetCase failed during work-note closed guard", "userID", user.UserID, "caseID", caseID, "err", err)
 			mapUpstreamErrorGeneric(w, err, "Failed to create case comment.")
@@ -332,6 +384,7 @@ func (h *CaseHandler) CreateCaseComment(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	result = attachMentions(r.Context(), h.mentions, user, mentionRecord(current, "case", caseID), body, result)
 	writeJSON(w, http.StatusCreated, result)
 }
 
@@ -866,14 +919,14 @@ func (h *CaseHandler) PatchCase(w http.ResponseWriter, r *http.Request) {
 		}
 		var currentCase struct {
 			State string `json:"state"`
+			Type  string `json:"type"`
 		}
 		if err := json.Unmarshal(current, &currentCase); err != nil {
 			slog.ErrorContext(r.Context(), "failed to parse current case state", "userID", user.UserID, "caseID", caseID, "err", err)
 			writeError(w, http.StatusInternalServerError, ErrMsgInternal)
 			return
 		}
-		if patch.State != nil && !isValidStateTransition(currentCase.State, *patch.State) {
-			writeError(w, http.StatusBadRequest, ErrMsgInvalidTransition)
+		if patch.State != nil && !h.checkTransition(w, r, user, currentCase.Type, currentCase.State, *patch.State, current, body) {
 			return
 		}
 		if patch.WorkState != nil && currentCase.State != caseStateWorkInProgress {
@@ -892,6 +945,42 @@ func (h *CaseHandler) PatchCase(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, result)
 }
 
+// checkTransition enforces the active case workflow on a PATCH that moves a
+// case from -> to: the transition must exist for the case's type, the caller
+// must pass its role and team-family guards, and every required field must be
+// non-empty in current with patch applied over it. It writes the error
+// response and returns false when the PATCH must not be forwarded.
+func (h *CaseHandler) checkTransition(w http.ResponseWriter, r *http.Request, user *middleware.UserInfo, caseType, from, to string, current, patch []byte) bool {
+	t, ok := workflow.Active().Transition(caseType, from, to)
+	if !ok {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidTransition)
+		return false
+	}
+	if t.CallerGuarded() {
+		if !t.Permits(h.resolveWorkflowCaller(r, user)) {
+			writeError(w, http.StatusForbidden, ErrMsgTransitionNotPermitted)
+			return false
+		}
+	}
+	if len(t.RequiredFields) > 0 {
+		var currentFields, patchFields map[string]json.RawMessage
+		if err := json.Unmarshal(current, &currentFields); err != nil {
+			slog.ErrorContext(r.Context(), "failed to parse current case for required-field check", "userID", user.UserID, "err", err)
+			writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+			return false
+		}
+		if err := json.Unmarshal(patch, &patchFields); err != nil {
+			writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+			return false
+		}
+		if missing := t.MissingFields(currentFields, patchFields); len(missing) > 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Moving this case to %s requires: %s.", to, strings.Join(missing, ", ")))
+			return false
+		}
+	}
+	return true
+}
+
 // GetCase handles GET /cases/{id}.
 func (h *CaseHandler) GetCase(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
@@ -917,7 +1006,7 @@ func (h *CaseHandler) GetCase(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err = injectNextStates(result)
+	result, err = injectNextStates(result, func() *workflow.Caller { return h.resolveWorkflowCaller(r, user) })
 	if err != nil {
 		slog.ErrorContext(r.Context(), "failed to inject nextStates", "userID", user.UserID, "caseID", caseID, "err", err)
 		writeError(w, http.StatusInternalServerError, "Failed to process case details.")
@@ -1091,6 +1180,11 @@ func (h *CaseHandler) SearchAllCallRequests(w http.ResponseWriter, r *http.Reque
 // selected by the target `state` in the body. The backend has no role-based access
 // control layer yet, so any authenticated user may invoke them today; engineer-only
 // gating is a follow-up and MUST NOT be invented here.
+//
+// A body moving the call to "scheduled" is checked against the assignee's
+// other scheduled calls, like CallSchedulingHandler.ScheduleCallRequest: a
+// double booking is refused with 409 and the conflicting calls unless the
+// body sets "override", which is not forwarded.
 func (h *CaseHandler) PatchCallRequest(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
 	if user == nil {
@@ -1127,6 +1221,11 @@ func (h *CaseHandler) PatchCallRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	body, ok := h.checkCallConflicts(w, r, user, caseID, callRequestID, body)
+	if !ok {
+		return
+	}
+
 	entityBody, err := injectCaseIDField(body, caseID)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
@@ -1143,6 +1242,66 @@ func (h *CaseHandler) PatchCallRequest(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, result)
 }
 
+// patchCallRequestSchedule is the part of a PATCH /call-requests body that
+// books the call.
+type patchCallRequestSchedule struct {
+	State             string `json:"state"`
+	MeetingDate       string `json:"meetingDate"`
+	DurationInMinutes int    `json:"durationInMinutes"`
+	Assignee          string `json:"assignee"`
+	Override          bool   `json:"override"`
+}
+
+// checkCallConflicts refuses a PATCH /call-requests body that schedules the
+// call over another of the assignee's, writing the response and returning
+// false. It returns the body to forward, without "override".
+func (h *CaseHandler) checkCallConflicts(w http.ResponseWriter, r *http.Request, user *middleware.UserInfo, caseID, callRequestID string, body []byte) ([]byte, bool) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(body, &m); err != nil || m == nil {
+		// Left for injectCaseIDField to reject.
+		return body, true
+	}
+	var req patchCallRequestSchedule
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return nil, false
+	}
+	if _, ok := m["override"]; ok {
+		delete(m, "override")
+		stripped, err := json.Marshal(m)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+			return nil, false
+		}
+		body = stripped
+	}
+	if h.conflicts == nil || req.State != "scheduled" || req.Override {
+		return body, true
+	}
+
+	start, ok := scheduling.ParseTime(req.MeetingDate)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "meetingDate must be an RFC3339 timestamp.")
+		return nil, false
+	}
+	conflicts, err := h.conflicts.Conflicts(r.Context(), caseID, callRequestID, start, req.DurationInMinutes, req.Assignee)
+	if err != nil {
+		if !writeSchedulingError(w, err) {
+			slog.ErrorContext(r.Context(), "call request conflict check failed", "userID", user.UserID, "caseID", caseID, "callRequestID", callRequestID, "err", err)
+			mapUpstreamError(w, err, "Failed to update call request.")
+		}
+		return nil, false
+	}
+	if len(conflicts) > 0 {
+		writeJSONValue(w, http.StatusConflict, conflictBody{
+			Message:   "The slot overlaps another call scheduled for the assignee.",
+			Conflicts: conflicts,
+		})
+		return nil, false
+	}
+	return body, true
+}
+
 // CreateCaseGithubIssue handles POST /cases/{id}/github-issues.
 func (h *CaseHandler) CreateCaseGithubIssue(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
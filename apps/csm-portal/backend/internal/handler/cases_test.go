@@ -27,6 +27,8 @@ import (
 	"time"
 
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/apierror"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/workflow"
 )
 
 // upstreamErrorCases is the table used by every PATCH/update handler — the
@@ -2757,3 +2759,153 @@ func TestAggregateCases(t *testing.T) {
 		}
 	})
 }
+
+// withGuardedWorkflow installs a case workflow with one required-field guard
+// (work_in_progress -> closed) and one role-and-team guard (open -> closed)
+// for the duration of the test.
+func withGuardedWorkflow(t *testing.T) {
+	t.Helper()
+	dir, err := directory.New([]directory.Team{{Key: "sre-a", Name: "SRE Team A", Family: directory.FamilySREAbt}}, directory.DefaultRoles)
+	if err != nil {
+		t.Fatalf("directory.New: %v", err)
+	}
+	def, err := workflow.Parse([]byte(`{"caseTypes":{"case":{"transitions":[
+		{"from":"open","to":"work_in_progress"},
+		{"from":"open","to":"closed","roles":["admin"],"teamFamilies":["sre-abt"]},
+		{"from":"work_in_progress","to":"closed","requiredFields":["resolutionNotes"]}
+	]}}}`))
+	if err != nil {
+		t.Fatalf("workflow.Parse: %v", err)
+	}
+	wf, err := workflow.Compile(def, dir)
+	if err != nil {
+		t.Fatalf("workflow.Compile: %v", err)
+	}
+	workflow.SetActive(wf)
+	t.Cleanup(func() { workflow.SetActive(nil) })
+}
+
+func TestCaseWorkflowGuards(t *testing.T) {
+	const testCaseID = "11111111-1111-1111-1111-111111111111"
+	const adminOnSRE = `{"id":"u1","roles":["agent","admin"],"groups":[{"id":"g1","name":"SRE Team A"}]}`
+	const agentOnSRE = `{"id":"u1","roles":["agent"],"groups":[{"id":"g1","name":"SRE Team A"}]}`
+	withGuardedWorkflow(t)
+
+	patch := func(t *testing.T, current, me, body string) (*httptest.ResponseRecorder, bool) {
+		t.Helper()
+		forwarded := false
+		client := &mockEntityCaseClient{
+			getCaseFn: func(_ context.Context, _ string) ([]byte, error) { return []byte(current), nil },
+			getUserMeFn: func(_ context.Context) ([]byte, error) {
+				if me == "" {
+					return nil, errors.New("identity lookup failed")
+				}
+				return []byte(me), nil
+			},
+			patchCaseFn: func(_ context.Context, _ string, _ []byte) ([]byte, error) {
+				forwarded = true
+				return []byte(`{"id":"` + testCaseID + `"}`), nil
+			},
+		}
+		r := withUser(httptest.NewRequest(http.MethodPatch, "/cases/"+testCaseID, strings.NewReader(body)))
+		r.SetPathValue("id", testCaseID)
+		w := httptest.NewRecorder()
+		NewCaseHandler(client).PatchCase(w, r)
+		return w, forwarded
+	}
+
+	t.Run("transition outside the workflow is rejected", func(t *testing.T) {
+		w, forwarded := patch(t, `{"state":"open"}`, adminOnSRE, `{"state":"awaiting_info"}`)
+		assertStatus(t, w, http.StatusBadRequest)
+		assertErrorMessage(t, w, ErrMsgInvalidTransition)
+		if forwarded {
+			t.Error("PATCH must not be forwarded")
+		}
+	})
+
+	t.Run("role-and-team guard", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			me       string
+			wantCode int
+		}{
+			{"caller with the role on a family team", adminOnSRE, http.StatusOK},
+			{"caller without the role", agentOnSRE, http.StatusForbidden},
+			{"caller lookup failure fails closed", "", http.StatusForbidden},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				w, forwarded := patch(t, `{"type":"case","state":"open"}`, tc.me, `{"state":"closed"}`)
+				assertStatus(t, w, tc.wantCode)
+				if tc.wantCode == http.StatusForbidden {
+					assertErrorMessage(t, w, ErrMsgTransitionNotPermitted)
+				}
+				if forwarded != (tc.wantCode == http.StatusOK) {
+					t.Errorf("forwarded = %v, want %v", forwarded, tc.wantCode == http.StatusOK)
+				}
+			})
+		}
+	})
+
+	t.Run("required field guard", func(t *testing.T) {
+		w, forwarded := patch(t, `{"state":"work_in_progress","resolutionNotes":null}`, "", `{"state":"closed"}`)
+		assertStatus(t, w, http.StatusBadRequest)
+		assertErrorMessage(t, w, "Moving this case to closed requires: resolutionNotes.")
+		if forwarded {
+			t.Error("PATCH must not be forwarded")
+		}
+
+		w, forwarded = patch(t, `{"state":"work_in_progress"}`, "", `{"state":"closed","resolutionNotes":"Patched in U12."}`)
+		assertStatus(t, w, http.StatusOK)
+		if !forwarded {
+			t.Error("PATCH carrying the required field must be forwarded")
+		}
+	})
+
+	t.Run("GetCase reports legalNextStates for the caller", func(t *testing.T) {
+		cases := []struct {
+			name      string
+			state     string
+			me        string
+			wantNext  []string
+			wantLegal []string
+		}{
+			{"guarded transition, permitted caller", "open", adminOnSRE, []string{"work_in_progress", "closed"}, []string{"work_in_progress", "closed"}},
+			{"guarded transition, other caller", "open", agentOnSRE, []string{"work_in_progress", "closed"}, []string{"work_in_progress"}},
+			{"required-field guard does not hide the state", "work_in_progress", "", []string{"closed"}, []string{"closed"}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				meCalls := 0
+				client := &mockEntityCaseClient{
+					getCaseFn: func(_ context.Context, _ string) ([]byte, error) {
+						return []byte(`{"id":"` + testCaseID + `","type":"case","state":"` + tc.state + `"}`), nil
+					},
+					getUserMeFn: func(_ context.Context) ([]byte, error) {
+						meCalls++
+						return []byte(tc.me), nil
+					},
+				}
+				r := withUser(httptest.NewRequest(http.MethodGet, "/cases/"+testCaseID, nil))
+				r.SetPathValue("id", testCaseID)
+				w := httptest.NewRecorder()
+				NewCaseHandler(client).GetCase(w, r)
+				assertStatus(t, w, http.StatusOK)
+
+				resp := decodeJSON[struct {
+					NextStates      []string `json:"nextStates"`
+					LegalNextStates []string `json:"legalNextStates"`
+				}](t, w)
+				if strings.Join(resp.NextStates, ",") != strings.Join(tc.wantNext, ",") {
+					t.Errorf("nextStates = %v, want %v", resp.NextStates, tc.wantNext)
+				}
+				if strings.Join(resp.LegalNextStates, ",") != strings.Join(tc.wantLegal, ",") {
+					t.Errorf("legalNextStates = %v, want %v", resp.LegalNextStates, tc.wantLegal)
+				}
+				if tc.me == "" && meCalls != 0 {
+					t.Errorf("GetUserMe calls = %d, want 0 when no transition is caller-guarded", meCalls)
+				}
+			})
+		}
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/mentions"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
 )
 
@@ -50,7 +51,8 @@ type entityChangeRequestClient interface {
 
 // ChangeRequestHandler handles HTTP requests for change-request operations.
 type ChangeRequestHandler struct {
-	entity entityChangeRequestClient
+	entity   entityChangeRequestClient
+	mentions mentionProcessor
 }
 
 // NewChangeRequestHandler creates a ChangeRequestHandler backed by the given entity client.
@@ -58,6 +60,13 @@ func NewChangeRequestHandler(entity entityChangeRequestClient) *ChangeRequestHan
 	return &ChangeRequestHandler{entity: entity}
 }
 
+// WithMentions makes CreateChangeRequestComment resolve and notify the @mentions in
+// new comments. Without it they are left as plain text.
+func (h *ChangeRequestHandler) WithMentions(m mentionProcessor) *ChangeRequestHandler {
+	h.mentions = m
+	return h
+}
+
 // CreateChangeRequest handles POST /change-requests.
 func (h *ChangeRequestHandler) CreateChangeRequest(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
@@ -215,7 +224,8 @@ func (h *ChangeRequestHandler) CreateChangeRequestComment(w http.ResponseWriter,
 		return
 	}
 
-	if _, err := h.entity.GetChangeRequest(r.Context(), id); err != nil {
+	current, err := h.entity.GetChangeRequest(r.Context(), id)
+	if err != nil {
 		slog.ErrorContext(r.Context(), "entity GetChangeRequest failed during comment guard", "userID", user.UserID, "id", id, "err", err)
 		mapUpstreamErrorGeneric(w, err, "Failed to create change request comment.")
 		return
@@ -234,6 +244,7 @@ func (h *ChangeRequestHandler) CreateChangeRequestComment(w http.ResponseWriter,
 		return
 	}
 
+	result = attachMentions(r.Context(), h.mentions, user, mentionRecord(current, mentions.RecordChangeRequest, id), body, result)
 	writeJSON(w, http.StatusCreated, result)
 }
 
@@ -17,11 +17,19 @@
 package handler
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"sort"
 
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/reports"
+)
+
+const (
+	errMsgTrendsUnavailable = "Trend widgets are not available."
+	errMsgTrendFailed       = "Failed to resolve the trend."
 )
 
 // dashboardPieSliceView is one wedge of a Shape "pie" widget — see
@@ -50,6 +58,8 @@ type dashboardWidgetView struct {
 	GridWidth    int                      `json:"gridWidth"`
 	Query        map[string]any           `json:"query"`
 	GroupBy      *dashboard.GroupByConfig `json:"groupBy,omitempty"`
+	Metrics      *dashboard.MetricsConfig `json:"metrics,omitempty"`
+	Trend        *dashboard.TrendConfig   `json:"trend,omitempty"`
 	ListLimit    int                      `json:"listLimit,omitempty"`
 	Slices       []dashboardPieSliceView  `json:"slices,omitempty"`
 	Section      string                   `json:"section,omitempty"`
@@ -139,13 +149,52 @@ type dashboardDetailView struct {
 // resolves GET /users/me for its own purposes and can substitute the id
 // itself, the same way it already does for "__current_team__" (see
 // apps/csm-portal/webapp/src/features/csm-dashboard/utils/teamFilterPlaceholder.ts).
-type DashboardHandler struct{}
+//
+// The one placeholder the frontend cannot resolve is dashboard.OnCallPlaceholder:
+// who is on call is configuration only this service has. GET
+// /dashboards/{dashboardId} resolves it for the caller's own team, or for
+// ?team=<key> when one is named.
+//
+// The other exception is a Shape "line"/"area" widget: a trend is one search
+// per bucket per series, too many for a page load to issue, so GET
+// /dashboards/{dashboardId}/widgets/{widgetId}/trend resolves it here.
+type DashboardHandler struct {
+	oncall onCallUserResolver
+	trends trendResolver
+}
+
+// trendResolver resolves a Shape "line"/"area" widget (see
+// reports.TrendResolver).
+type trendResolver interface {
+	Resolve(ctx context.Context, w dashboard.WidgetTemplate) (reports.Trend, error)
+}
+
+// onCallUserResolver resolves dashboard.OnCallPlaceholder for a team, and
+// the caller's own team when the request names none.
+type onCallUserResolver interface {
+	CurrentOnCallUserID(ctx context.Context, teamKey string) (string, bool)
+	CallerTeamKey(ctx context.Context) (string, bool)
+}
 
 // NewDashboardHandler creates a DashboardHandler.
 func NewDashboardHandler() *DashboardHandler {
 	return &DashboardHandler{}
 }
 
+// WithOnCall makes GetDashboardDetail resolve dashboard.OnCallPlaceholder.
+// Without it the placeholder is left as configured.
+func (h *DashboardHandler) WithOnCall(r onCallUserResolver) *DashboardHandler {
+	h.oncall = r
+	return h
+}
+
+// WithTrends makes GetWidgetTrend resolve trend widgets. Without it the
+// endpoint is a 503.
+func (h *DashboardHandler) WithTrends(r trendResolver) *DashboardHandler {
+	h.trends = r
+	return h
+}
+
 // GetDashboards handles GET /dashboards.
 func (h *DashboardHandler) GetDashboards(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
@@ -186,6 +235,9 @@ func (h *DashboardHandler) GetDashboardDetail(w http.ResponseWriter, r *http.Req
 	}
 
 	widgets := widgetViews(d.Widgets)
+	if h.oncall != nil {
+		h.resolveOnCall(r.Context(), r.URL.Query().Get("team"), widgets)
+	}
 
 	writeJSONValue(w, http.StatusOK, dashboardDetailView{
 		ID:          d.ID,
@@ -198,6 +250,46 @@ func (h *DashboardHandler) GetDashboardDetail(w http.ResponseWriter, r *http.Req
 	})
 }
 
+// GetWidgetTrend handles GET /dashboards/{dashboardId}/widgets/{widgetId}/trend:
+// a Shape "line"/"area" widget's bucketed counts. Any other widget is a 404,
+// as there is no trend to return.
+func (h *DashboardHandler) GetWidgetTrend(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+	if h.trends == nil {
+		writeError(w, http.StatusServiceUnavailable, errMsgTrendsUnavailable)
+		return
+	}
+
+	dashboardID, widgetID := r.PathValue("dashboardId"), r.PathValue("widgetId")
+	d, ok := dashboard.ByID(dashboardID)
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		return
+	}
+	var widget *dashboard.WidgetTemplate
+	for i := range d.Widgets {
+		if d.Widgets[i].ID == widgetID && d.Widgets[i].Trend != nil {
+			widget = &d.Widgets[i]
+		}
+	}
+	if widget == nil {
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		return
+	}
+
+	trend, err := h.trends.Resolve(r.Context(), *widget)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to resolve dashboard trend", "dashboardId", dashboardID, "widgetId", widgetID, "err", err)
+		mapUpstreamErrorGeneric(w, err, errMsgTrendFailed)
+		return
+	}
+	writeJSONValue(w, http.StatusOK, trend)
+}
+
 // widgetViews maps widget templates onto their wire shape. Shared by
 // GET /dashboards/{dashboardId} and GET /dashboards/sections so a section's
 // widgets and a dashboard's widgets are never two different shapes on the
@@ -225,6 +317,8 @@ func widgetViews(templates []dashboard.WidgetTemplate) []dashboardWidgetView {
 			GridWidth:    tpl.GridWidth,
 			Query:        tpl.Query,
 			GroupBy:      tpl.GroupBy,
+			Metrics:      tpl.Metrics,
+			Trend:        tpl.Trend,
 			ListLimit:    tpl.ListLimit,
 			Slices:       slices,
 			Section:      tpl.Section,
@@ -235,6 +329,95 @@ func widgetViews(templates []dashboard.WidgetTemplate) []dashboardWidgetView {
 	return views
 }
 
+// resolveOnCall replaces dashboard.OnCallPlaceholder in every widget's and
+// slice's query with the platform user id of whoever is on call for team,
+// or for the caller's own team when team is empty; the caller's team is only
+// looked up when some query carries the placeholder. The views share their
+// queries with the registry, so a changed query is replaced by a copy rather
+// than edited. When there is no team, nobody is on call, or the id cannot be
+// resolved, the placeholder is left for the search to reject: a tile
+// erroring is visibly wrong, where a silently widened filter is not.
+func (h *DashboardHandler) resolveOnCall(ctx context.Context, team string, widgets []dashboardWidgetView) {
+	carries := false
+	for _, w := range widgets {
+		if containsOnCallPlaceholder(w.Query) {
+			carries = true
+		}
+		for _, s := range w.Slices {
+			if containsOnCallPlaceholder(s.Query) {
+				carries = true
+			}
+		}
+	}
+	if !carries {
+		return
+	}
+	if team == "" {
+		var ok bool
+		if team, ok = h.oncall.CallerTeamKey(ctx); !ok {
+			return
+		}
+	}
+	id, ok := h.oncall.CurrentOnCallUserID(ctx, team)
+	if !ok {
+		return
+	}
+	for i := range widgets {
+		if containsOnCallPlaceholder(widgets[i].Query) {
+			widgets[i].Query = replaceOnCallPlaceholder(widgets[i].Query, id).(map[string]any)
+		}
+		for j := range widgets[i].Slices {
+			if containsOnCallPlaceholder(widgets[i].Slices[j].Query) {
+				widgets[i].Slices[j].Query = replaceOnCallPlaceholder(widgets[i].Slices[j].Query, id).(map[string]any)
+			}
+		}
+	}
+}
+
+func containsOnCallPlaceholder(v any) bool {
+	switch x := v.(type) {
+	case string:
+		return x == dashboard.OnCallPlaceholder
+	case []any:
+		for _, e := range x {
+			if containsOnCallPlaceholder(e) {
+				return true
+			}
+		}
+	case map[string]any:
+		for _, e := range x {
+			if containsOnCallPlaceholder(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// replaceOnCallPlaceholder returns a copy of v with every
+// dashboard.OnCallPlaceholder string replaced by id.
+func replaceOnCallPlaceholder(v any, id string) any {
+	switch x := v.(type) {
+	case string:
+		if x == dashboard.OnCallPlaceholder {
+			return id
+		}
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = replaceOnCallPlaceholder(e, id)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[k] = replaceOnCallPlaceholder(e, id)
+		}
+		return out
+	}
+	return v
+}
+
 // GetFilterPresets handles GET /dashboards/filter-presets.
 //
 // Lists the shared filter presets a dashboard definition may reference by
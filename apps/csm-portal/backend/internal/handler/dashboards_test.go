@@ -17,6 +17,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -26,7 +27,9 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/apierror"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/reports"
 )
 
 // testDashboardsConfigJSON is a small, entirely dummy 2-dashboard fixture —
@@ -685,3 +688,110 @@ func TestGetDashboardDetail(t *testing.T) {
 // to the frontend — see DashboardHandler's doc comment in dashboards.go and
 // the "my-open-cases"/"Mine" assertions above in TestGetDashboardDetail,
 // which now assert the placeholder reaches the response unresolved instead.
+
+// fixedOnCall resolves every team to the same user id, and the caller to
+// the team "abt-1".
+type fixedOnCall string
+
+func (f fixedOnCall) CurrentOnCallUserID(context.Context, string) (string, bool) {
+	return string(f), f != ""
+}
+
+func (fixedOnCall) CallerTeamKey(context.Context) (string, bool) { return "abt-1", true }
+
+// teamlessOnCall has somebody on call for every team, but no caller team.
+type teamlessOnCall struct{ fixedOnCall }
+
+func (teamlessOnCall) CallerTeamKey(context.Context) (string, bool) { return "", false }
+
+func TestDashboardResolveOnCall(t *testing.T) {
+	query := map[string]any{"filters": []any{map[string]any{"field": "assignedUserId", "op": "in", "values": []any{"__current_oncall__"}}}}
+	widgets := []dashboardWidgetView{
+		{WidgetID: "oncall", Query: query},
+		{WidgetID: "plain", Query: map[string]any{"filters": []any{}}},
+	}
+
+	NewDashboardHandler().WithOnCall(fixedOnCall("u-1")).resolveOnCall(context.Background(), "abt-1", widgets)
+	got := widgets[0].Query["filters"].([]any)[0].(map[string]any)["values"].([]any)[0]
+	if got != "u-1" {
+		t.Errorf("resolved value = %v, want u-1", got)
+	}
+	// The registry's own query is untouched.
+	if orig := query["filters"].([]any)[0].(map[string]any)["values"].([]any)[0]; orig != "__current_oncall__" {
+		t.Errorf("registry query was edited in place: %v", orig)
+	}
+
+	widgets[0].Query = query
+	NewDashboardHandler().WithOnCall(fixedOnCall("")).resolveOnCall(context.Background(), "abt-1", widgets)
+	if !containsOnCallPlaceholder(widgets[0].Query) {
+		t.Error("placeholder removed with nobody on call")
+	}
+
+	// With no team named, the caller's own team is used.
+	NewDashboardHandler().WithOnCall(fixedOnCall("u-2")).resolveOnCall(context.Background(), "", widgets)
+	if got := widgets[0].Query["filters"].([]any)[0].(map[string]any)["values"].([]any)[0]; got != "u-2" {
+		t.Errorf("resolved value for the caller's team = %v, want u-2", got)
+	}
+
+	// A caller in no team, naming none, keeps the placeholder.
+	widgets[0].Query = query
+	NewDashboardHandler().WithOnCall(teamlessOnCall{"u-1"}).resolveOnCall(context.Background(), "", widgets)
+	if !containsOnCallPlaceholder(widgets[0].Query) {
+		t.Error("placeholder removed with no team to resolve it for")
+	}
+}
+
+type fixedTrend struct {
+	trend reports.Trend
+	err   error
+}
+
+func (f fixedTrend) Resolve(context.Context, dashboard.WidgetTemplate) (reports.Trend, error) {
+	return f.trend, f.err
+}
+
+func TestGetWidgetTrend(t *testing.T) {
+	previous := dashboard.Active()
+	dashboard.SetActive(dashboard.NewStaticRegistry([]dashboard.Dashboard{{
+		ID: "d", DisplayName: "D",
+		Widgets: []dashboard.WidgetTemplate{
+			{ID: "count", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapeCount},
+			{ID: "trend", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapeLine,
+				Trend: &dashboard.TrendConfig{Bucket: dashboard.BucketWeek, From: "__daysAgo:30__"}},
+		},
+	}}))
+	t.Cleanup(func() { dashboard.SetActive(previous) })
+
+	trend := reports.Trend{Bucket: dashboard.BucketWeek, Periods: []reports.TrendPeriod{{Start: "2026-05-11", End: "2026-05-14"}},
+		Series: []reports.TrendSeries{{Label: "Created", Counts: []float64{3}}}}
+	cases := []struct {
+		name     string
+		h        *DashboardHandler
+		widgetID string
+		want     int
+	}{
+		{"resolved", NewDashboardHandler().WithTrends(fixedTrend{trend: trend}), "trend", http.StatusOK},
+		{"not a trend widget", NewDashboardHandler().WithTrends(fixedTrend{trend: trend}), "count", http.StatusNotFound},
+		{"unknown widget", NewDashboardHandler().WithTrends(fixedTrend{trend: trend}), "bogus", http.StatusNotFound},
+		{"upstream failure", NewDashboardHandler().WithTrends(fixedTrend{err: &apierror.Error{StatusCode: http.StatusBadGateway}}), "trend", http.StatusServiceUnavailable},
+		{"no resolver", NewDashboardHandler(), "trend", http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := withUser(withDashboardID(httptest.NewRequest(http.MethodGet, "/dashboards/d/widgets/"+tc.widgetID+"/trend", nil), "d"))
+			r.SetPathValue("widgetId", tc.widgetID)
+			w := httptest.NewRecorder()
+			tc.h.GetWidgetTrend(w, r)
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, tc.want, w.Body.String())
+			}
+			if tc.want != http.StatusOK {
+				return
+			}
+			var got reports.Trend
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || !reflect.DeepEqual(got, trend) {
+				t.Errorf("body = %+v (%v), want %+v", got, err, trend)
+			}
+		})
+	}
+}
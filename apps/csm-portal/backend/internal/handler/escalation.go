@@ -0,0 +1,149 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/escalation"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+// maxSnoozeReasonRunes bounds the reason kept in the escalation audit trail.
+const maxSnoozeReasonRunes = 500
+
+// caseEscalator abstracts the escalation engine used by EscalationHandler.
+type caseEscalator interface {
+	Snooze(caseID string, d time.Duration, by, reason string) (escalation.CaseState, error)
+	Case(caseID string) (escalation.CaseState, bool)
+	Entries(f escalation.Filter) []escalation.Entry
+}
+
+// EscalationHandler serves the escalation of unacknowledged cases by their
+// policies (see escalation.Engine).
+type EscalationHandler struct {
+	engine caseEscalator
+}
+
+// NewEscalationHandler creates an EscalationHandler.
+func NewEscalationHandler(engine caseEscalator) *EscalationHandler {
+	return &EscalationHandler{engine: engine}
+}
+
+// caseEscalationView is the GET /cases/{id}/escalation response. State is
+// null for a case under no policy; Entries is its audit trail, newest first,
+// and outlives the state.
+type caseEscalationView struct {
+	State   *escalation.CaseState `json:"state"`
+	Entries []escalation.Entry    `json:"entries"`
+}
+
+// snoozeEscalationRequest is the POST /cases/{id}/escalation/snooze body.
+type snoozeEscalationRequest struct {
+	Minutes int    `json:"minutes"`
+	Reason  string `json:"reason"`
+}
+
+// GetCaseEscalation handles GET /cases/{id}/escalation.
+// Returns where the case is in its escalation and its audit trail.
+func (h *EscalationHandler) GetCaseEscalation(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	caseID := r.PathValue("id")
+	if caseID == "" {
+		writeError(w, http.StatusBadRequest, "Case ID cannot be empty!")
+		return
+	}
+	view := caseEscalationView{Entries: h.engine.Entries(escalation.Filter{CaseID: caseID})}
+	if st, ok := h.engine.Case(caseID); ok {
+		view.State = &st
+	}
+	writeJSONValue(w, http.StatusOK, view)
+}
+
+// SnoozeCaseEscalation handles POST /cases/{id}/escalation/snooze.
+// Pauses the case's escalation for minutes (1-1440): no step fires until the
+// snooze runs out, and the remaining steps then fall due that much later.
+// Snoozing again replaces when the snooze ends. Returns the case's state.
+func (h *EscalationHandler) SnoozeCaseEscalation(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	caseID := r.PathValue("id")
+	if caseID == "" {
+		writeError(w, http.StatusBadRequest, "Case ID cannot be empty!")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+	var req snoozeEscalationRequest
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+	d := time.Duration(req.Minutes) * time.Minute
+	if req.Minutes < 1 || d > escalation.MaxSnooze {
+		writeError(w, http.StatusBadRequest, "minutes must be an integer between 1 and 1440")
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len([]rune(reason)) > maxSnoozeReasonRunes {
+		writeError(w, http.StatusBadRequest, "reason must be at most 500 characters")
+		return
+	}
+
+	st, err := h.engine.Snooze(caseID, d, user.Email, reason)
+	switch {
+	case err == nil:
+		writeJSONValue(w, http.StatusOK, st)
+	case errors.Is(err, escalation.ErrNotConfigured):
+		writeError(w, http.StatusNotFound, "Case escalation is not configured.")
+	case errors.Is(err, escalation.ErrNotEscalating):
+		writeError(w, http.StatusConflict, "The case is not being escalated.")
+	case errors.Is(err, escalation.ErrAcknowledged):
+		writeError(w, http.StatusConflict, "The case is already acknowledged.")
+	default:
+		slog.ErrorContext(r.Context(), "case escalation snooze failed", "userID", user.UserID, "caseID", caseID, "err", err)
+		writeError(w, http.StatusInternalServerError, "Failed to snooze the case escalation.")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/escalation"
+)
+
+// mockCaseEscalator records the snooze it was given and returns err.
+type mockCaseEscalator struct {
+	caseID, by, reason string
+	d                  time.Duration
+	state              *escalation.CaseState
+	err                error
+}
+
+func (m *mockCaseEscalator) Snooze(caseID string, d time.Duration, by, reason string) (escalation.CaseState, error) {
+	m.caseID, m.d, m.by, m.reason = caseID, d, by, reason
+	return escalation.CaseState{CaseID: caseID, SnoozedBy: by}, m.err
+}
+
+func (m *mockCaseEscalator) Case(string) (escalation.CaseState, bool) {
+	if m.state == nil {
+		return escalation.CaseState{}, false
+	}
+	return *m.state, true
+}
+
+func (m *mockCaseEscalator) Entries(f escalation.Filter) []escalation.Entry {
+	return []escalation.Entry{{CaseID: f.CaseID, Action: escalation.ActionStarted}}
+}
+
+func snoozeEscalation(h *EscalationHandler, body string) *httptest.ResponseRecorder {
+	r := withUser(httptest.NewRequest(http.MethodPost, "/cases/case-1/escalation/snooze", strings.NewReader(body)))
+	r.SetPathValue("id", "case-1")
+	w := httptest.NewRecorder()
+	h.SnoozeCaseEscalation(w, r)
+	return w
+}
+
+func TestSnoozeCaseEscalation(t *testing.T) {
+	t.Run("snoozes as the caller", func(t *testing.T) {
+		m := &mockCaseEscalator{}
+		w := snoozeEscalation(NewEscalationHandler(m), `{"minutes":30,"reason":"  On a bridge call  "}`)
+		assertStatus(t, w, http.StatusOK)
+		if m.caseID != "case-1" || m.d != 30*time.Minute || m.by != testUser.Email || m.reason != "On a bridge call" {
+			t.Errorf("Snooze(%q, %s, %q, %q)", m.caseID, m.d, m.by, m.reason)
+		}
+		if got := decodeJSON[escalation.CaseState](t, w); got.SnoozedBy != testUser.Email {
+			t.Errorf("state = %+v", got)
+		}
+	})
+
+	for name, body := range map[string]string{
+		"no minutes":    `{}`,
+		"too long":      `{"minutes":1441}`,
+		"negative":      `{"minutes":-5}`,
+		"unknown field": `{"minutes":5,"until":"tomorrow"}`,
+		"long reason":   `{"minutes":5,"reason":"` + strings.Repeat("x", 501) + `"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := &mockCaseEscalator{}
+			assertStatus(t, snoozeEscalation(NewEscalationHandler(m), body), http.StatusBadRequest)
+			if m.caseID != "" {
+				t.Error("an invalid request was snoozed")
+			}
+		})
+	}
+
+	errs := []struct {
+		err  error
+		code int
+		msg  string
+	}{
+		{escalation.ErrNotConfigured, http.StatusNotFound, "Case escalation is not configured."},
+		{escalation.ErrNotEscalating, http.StatusConflict, "The case is not being escalated."},
+		{escalation.ErrAcknowledged, http.StatusConflict, "The case is already acknowledged."},
+	}
+	for _, tc := range errs {
+		t.Run(tc.msg, func(t *testing.T) {
+			w := snoozeEscalation(NewEscalationHandler(&mockCaseEscalator{err: tc.err}), `{"minutes":5}`)
+			assertStatus(t, w, tc.code)
+			assertErrorMessage(t, w, tc.msg)
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/cases/case-1/escalation/snooze", strings.NewReader(`{"minutes":5}`))
+		r.SetPathValue("id", "case-1")
+		w := httptest.NewRecorder()
+		NewEscalationHandler(&mockCaseEscalator{}).SnoozeCaseEscalation(w, r)
+		assertStatus(t, w, http.StatusUnauthorized)
+	})
+}
+
+func TestGetCaseEscalation(t *testing.T) {
+	get := func(m *mockCaseEscalator) caseEscalationView {
+		r := withUser(httptest.NewRequest(http.MethodGet, "/cases/case-1/escalation", nil))
+		r.SetPathValue("id", "case-1")
+		w := httptest.NewRecorder()
+		NewEscalationHandler(m).GetCaseEscalation(w, r)
+		assertStatus(t, w, http.StatusOK)
+		return decodeJSON[caseEscalationView](t, w)
+	}
+
+	got := get(&mockCaseEscalator{state: &escalation.CaseState{CaseID: "case-1", Policy: "critical", Fired: 1}})
+	if got.State == nil || got.State.Fired != 1 || len(got.Entries) != 1 || got.Entries[0].CaseID != "case-1" {
+		t.Errorf("view = %+v", got)
+	}
+	if got := get(&mockCaseEscalator{}); got.State != nil {
+		t.Errorf("a case under no policy has state %+v", got.State)
+	}
+}
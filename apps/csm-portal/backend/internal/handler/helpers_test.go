@@ -486,6 +486,7 @@ func (m *mockEntityProductClient) SearchProductVersions(ctx context.Context, pro
 type mockEntityIncidentClient struct {
 	searchIncidentsFn          func(ctx context.Context, body []byte) ([]byte, error)
 	aggregateIncidentsFn       func(ctx context.Context, body []byte) ([]byte, error)
+	incidentMetricsFn          func(ctx context.Context, body []byte) ([]byte, error)
 	createIncidentFn           func(ctx context.Context, body []byte) ([]byte, error)
 	getIncidentFn              func(ctx context.Context, id string) ([]byte, error)
 	patchIncidentFn            func(ctx context.Context, id string, body []byte) ([]byte, error)
@@ -508,6 +509,13 @@ func (m *mockEntityIncidentClient) AggregateIncidents(ctx context.Context, body
 	return []byte(`{"groups":[],"othersCount":0,"totalRecords":0}`), nil
 }
 
+func (m *mockEntityIncidentClient) IncidentMetrics(ctx context.Context, body []byte) ([]byte, error) {
+	if m.incidentMetricsFn != nil {
+		return m.incidentMetricsFn(ctx, body)
+	}
+	return []byte(`{"total":0,"analyzed":0,"truncated":false,"series":[]}`), nil
+}
+
 func (m *mockEntityIncidentClient) CreateIncident(ctx context.Context, body []byte) ([]byte, error) {
 	if m.createIncidentFn != nil {
 		return m.createIncidentFn(ctx, body)
@@ -732,6 +740,26 @@ func (m *mockEntityGroupClient) SearchGroups(ctx context.Context, body []byte) (
 	return []byte(`{"groups":[],"total":0,"limit":20,"offset":0}`), nil
 }
 
+// ----- mock entity calendar client -----
+
+type mockEntityCalendarClient struct {
+	calendarID        string
+	body              []byte
+	addBusinessTimeFn func(ctx context.Context, calendarID string, body []byte) ([]byte, error)
+}
+
+func (m *mockEntityCalendarClient) ListCalendars(context.Context) ([]byte, error) {
+	return []byte(`{"calendars":[]}`), nil
+}
+
+func (m *mockEntityCalendarClient) AddBusinessTime(ctx context.Context, calendarID string, body []byte) ([]byte, error) {
+	m.calendarID, m.body = calendarID, body
+	if m.addBusinessTimeFn != nil {
+		return m.addBusinessTimeFn(ctx, calendarID, body)
+	}
+	return []byte(`{"calendarId":"` + calendarID + `","due":"2026-12-29T11:30:00Z"}`), nil
+}
+
 // ----- mock entity configuration item client -----
 
 type mockEntityConfigurationItemClient struct {
@@ -24,6 +24,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/mentions"
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
 )
 
@@ -31,6 +32,7 @@ import (
 type entityIncidentClient interface {
 	SearchIncidents(ctx context.Context, body []byte) ([]byte, error)
 	AggregateIncidents(ctx context.Context, body []byte) ([]byte, error)
+	IncidentMetrics(ctx context.Context, body []byte) ([]byte, error)
 	CreateIncident(ctx context.Context, body []byte) ([]byte, error)
 	GetIncident(ctx context.Context, id string) ([]byte, error)
 	PatchIncident(ctx context.Context, id string, body []byte) ([]byte, error)
@@ -318,7 +320,8 @@ func validateSearchIncidentsBody(body []byte) bool {
 // IncidentHandler handles HTTP requests for incident operations, delegating to the
 // entity service for data access.
 type IncidentHandler struct {
-	entity entityIncidentClient
+	entity   entityIncidentClient
+	mentions mentionProcessor
 }
 
 // NewIncidentHandler creates an IncidentHandler backed by the given entity client.
@@ -326,6 +329,13 @@ func NewIncidentHandler(entity entityIncidentClient) *IncidentHandler {
 	return &IncidentHandler{entity: entity}
 }
 
+// WithMentions makes CreateIncidentComment resolve and notify the @mentions in
+// new comments. Without it they are left as plain text.
+func (h *IncidentHandler) WithMentions(m mentionProcessor) *IncidentHandler {
+	h.mentions = m
+	return h
+}
+
 // SearchIncidents handles POST /incidents/search.
 func (h *IncidentHandler) SearchIncidents(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
@@ -407,6 +417,46 @@ func (h *IncidentHandler) AggregateIncidents(w http.ResponseWriter, r *http.Requ
 	writeJSON(w, http.StatusOK, result)
 }
 
+// IncidentMetrics handles POST /incidents/metrics.
+// Mean time to acknowledge, mean time to resolve and reopen rate over a
+// createdOn range, bucketed weekly or monthly and optionally broken down by
+// priority, category, assignmentGroup or businessService. The range, groupBy
+// and interval are validated upstream by the entity service; this layer only
+// forwards the request and passes the response through as-is.
+func (h *IncidentHandler) IncidentMetrics(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+
+	if !json.Valid(body) {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+
+	result, err := h.entity.IncidentMetrics(r.Context(), body)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity IncidentMetrics failed", "userID", user.UserID, "err", err)
+		mapUpstreamErrorGeneric(w, err, "Failed to compute incident metrics.")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
 // CreateIncident handles POST /incidents.
 func (h *IncidentHandler) CreateIncident(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
@@ -550,7 +600,8 @@ func (h *IncidentHandler) CreateIncidentComment(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	if _, err := h.entity.GetIncident(r.Context(), id); err != nil {
+	current, err := h.entity.GetIncident(r.Context(), id)
+	if err != nil {
 		slog.ErrorContext(r.Context(), "entity GetIncident failed during comment guard", "userID", user.UserID, "incidentID", id, "err", err)
 		mapUpstreamErrorGeneric(w, err, "Failed to create incident comment.")
 		return
@@ -569,6 +620,7 @@ func (h *IncidentHandler) CreateIncidentComment(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	result = attachMentions(r.Context(), h.mentions, user, mentionRecord(current, mentions.RecordIncident, id), body, result)
 	writeJSON(w, http.StatusCreated, result)
 }
 
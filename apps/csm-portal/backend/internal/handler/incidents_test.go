@@ -691,3 +691,70 @@ func TestAggregateIncidents(t *testing.T) {
 		}
 	})
 }
+
+func TestIncidentMetrics(t *testing.T) {
+	t.Run("requires authenticated user", func(t *testing.T) {
+		h := NewIncidentHandler(&mockEntityIncidentClient{})
+		r := httptest.NewRequest(http.MethodPost, "/incidents/metrics", strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		h.IncidentMetrics(w, r)
+		assertStatus(t, w, http.StatusUnauthorized)
+		assertErrorMessage(t, w, ErrMsgUnauthorized)
+		assertContentType(t, w, "application/json")
+	})
+
+	t.Run("rejects invalid JSON body", func(t *testing.T) {
+		h := NewIncidentHandler(&mockEntityIncidentClient{})
+		r := withUser(httptest.NewRequest(http.MethodPost, "/incidents/metrics", strings.NewReader(`not-json`)))
+		w := httptest.NewRecorder()
+		h.IncidentMetrics(w, r)
+		assertStatus(t, w, http.StatusBadRequest)
+		assertErrorMessage(t, w, ErrMsgBadRequest)
+		assertContentType(t, w, "application/json")
+	})
+
+	t.Run("forwards body to upstream and returns 200 with response", func(t *testing.T) {
+		const reqPayload = `{"filters":{"filters":[{"field":"createdOn","op":"gte","values":["2026-03-01"]},{"field":"createdOn","op":"lte","values":["2026-03-31"]}]},"groupBy":"priority","interval":"week"}`
+		var capturedBody []byte
+		client := &mockEntityIncidentClient{
+			incidentMetricsFn: func(_ context.Context, body []byte) ([]byte, error) {
+				capturedBody = body
+				return []byte(`{"total":3,"analyzed":3,"truncated":false,"overall":{"count":3},"series":[]}`), nil
+			},
+		}
+		h := NewIncidentHandler(client)
+		r := withUser(httptest.NewRequest(http.MethodPost, "/incidents/metrics", strings.NewReader(reqPayload)))
+		w := httptest.NewRecorder()
+		h.IncidentMetrics(w, r)
+
+		assertStatus(t, w, http.StatusOK)
+		assertContentType(t, w, "application/json")
+		if string(capturedBody) != reqPayload {
+			t.Errorf("upstream received body %q, want %q", capturedBody, reqPayload)
+		}
+		resp := decodeJSON[map[string]any](t, w)
+		if resp["total"] != float64(3) {
+			t.Errorf("total = %v, want 3", resp["total"])
+		}
+	})
+
+	t.Run("upstream errors are mapped correctly", func(t *testing.T) {
+		for _, tc := range upstreamErrorsGeneric("Failed to compute incident metrics.") {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				client := &mockEntityIncidentClient{
+					incidentMetricsFn: func(_ context.Context, _ []byte) ([]byte, error) {
+						return nil, tc.err
+					},
+				}
+				h := NewIncidentHandler(client)
+				r := withUser(httptest.NewRequest(http.MethodPost, "/incidents/metrics", strings.NewReader(`{}`)))
+				w := httptest.NewRecorder()
+				h.IncidentMetrics(w, r)
+				assertStatus(t, w, tc.wantCode)
+				assertErrorMessage(t, w, tc.wantMsg)
+				assertContentType(t, w, "application/json")
+			})
+		}
+	})
+}
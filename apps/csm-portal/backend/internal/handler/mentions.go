@@ -0,0 +1,90 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/mentions"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+// mentionProcessor abstracts mentions.Service for the comment handlers,
+// allowing them to be tested independently of user resolution and email.
+type mentionProcessor interface {
+	Process(ctx context.Context, rec mentions.Record, authorEmail, content string, workNote bool) []mentions.Mention
+}
+
+// mentionRecord reads the number, subject and product of the record a
+// comment is on from its GET response. typ is used when the response has no "type" of its
+// own, as incidents and change requests do not.
+func mentionRecord(detail []byte, typ, id string) mentions.Record {
+	var d struct {
+		Type    string  `json:"type"`
+		Number  string  `json:"number"`
+		Subject *string `json:"subject"`
+		Product *struct {
+			Name string `json:"name"`
+		} `json:"product"`
+	}
+	_ = json.Unmarshal(detail, &d) // best-effort: the mention still links by id
+	rec := mentions.Record{Type: typ, ID: id, Number: d.Number}
+	if d.Type != "" {
+		rec.Type = d.Type
+	}
+	if d.Subject != nil {
+		rec.Subject = *d.Subject
+	}
+	if d.Product != nil {
+		rec.Product = d.Product.Name
+	}
+	return rec
+}
+
+// attachMentions resolves and notifies the mentions in a comment the caller
+// has just created on rec, and returns the entity service's response with
+// them added as "mentions". reqBody is the create request, whose content is
+// parsed and whose type says whether it is a work note. With no processor, or a response that is not a JSON object, result
+// is returned unchanged.
+func attachMentions(ctx context.Context, m mentionProcessor, user *middleware.UserInfo, rec mentions.Record, reqBody, result []byte) []byte {
+	if m == nil {
+		return result
+	}
+	var created map[string]json.RawMessage
+	if err := json.Unmarshal(result, &created); err != nil {
+		slog.WarnContext(ctx, "created comment is not a JSON object; mentions not processed", "userID", user.UserID, "err", err)
+		return result
+	}
+	var req struct {
+		Content string `json:"content"`
+		Type    string `json:"type"`
+	}
+	_ = json.Unmarshal(reqBody, &req) // reqBody is already validated JSON
+
+	raw, err := json.Marshal(m.Process(ctx, rec, user.Email, req.Content, req.Type == "work_note"))
+	if err != nil {
+		return result
+	}
+	created["mentions"] = raw
+	out, err := json.Marshal(created)
+	if err != nil {
+		return result
+	}
+	return out
+}
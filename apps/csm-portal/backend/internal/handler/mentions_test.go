@@ -0,0 +1,161 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/mentions"
+)
+
+// mockMentionProcessor records the comment it was asked to process and
+// returns mentions.
+type mockMentionProcessor struct {
+	rec         mentions.Record
+	authorEmail string
+	content     string
+	workNote    bool
+	mentions    []mentions.Mention
+}
+
+func (m *mockMentionProcessor) Process(_ context.Context, rec mentions.Record, authorEmail, content string, workNote bool) []mentions.Mention {
+	m.rec, m.authorEmail, m.content, m.workNote = rec, authorEmail, content, workNote
+	return m.mentions
+}
+
+const createdCommentJSON = `{"message":"Comment created.","comment":{"id":"11111111-1111-1111-1111-111111111111","createdBy":"agent@example.com"}}`
+
+// decodeMentions returns the mentions on a create-comment response, checking
+// the entity service's own fields survived.
+func decodeMentions(t *testing.T, w *httptest.ResponseRecorder) []mentions.Mention {
+	t.Helper()
+	var got struct {
+		Message  string             `json:"message"`
+		Mentions []mentions.Mention `json:"mentions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.Message != "Comment created." {
+		t.Errorf("message = %q, want the entity service's response kept", got.Message)
+	}
+	return got.Mentions
+}
+
+func TestCreateComment_Mentions(t *testing.T) {
+	jane := mentions.Mention{Handle: "jane", User: &mentions.User{ID: "u1", UserName: "jane", Name: "Jane Doe", Email: "jane@example.com"}}
+
+	t.Run("case comment", func(t *testing.T) {
+		m := &mockMentionProcessor{mentions: []mentions.Mention{jane}}
+		client := &mockEntityCaseClient{
+			getCaseFn: func(_ context.Context, _ string) ([]byte, error) {
+				return []byte(`{"state":"work_in_progress","type":"service_request","number":"CS001","subject":"Login fails","product":{"name":"API Manager"}}`), nil
+			},
+			createCaseCommentFn: func(_ context.Context, _ string, _ []byte) ([]byte, error) {
+				return []byte(createdCommentJSON), nil
+			},
+		}
+		h := NewCaseHandler(client).WithMentions(m)
+		r := withUser(httptest.NewRequest(http.MethodPost, "/cases/case-1/comments", strings.NewReader(`{"type":"work_note","content":"@jane please look"}`)))
+		r.SetPathValue("id", "case-1")
+		w := httptest.NewRecorder()
+		h.CreateCaseComment(w, r)
+
+		assertStatus(t, w, http.StatusCreated)
+		want := mentions.Record{Type: "service_request", ID: "case-1", Number: "CS001", Subject: "Login fails", Product: "API Manager"}
+		if m.rec != want || m.authorEmail != testUser.Email || m.content != "@jane please look" || !m.workNote {
+			t.Errorf("processed %+v by %q: %q (work note %t)", m.rec, m.authorEmail, m.content, m.workNote)
+		}
+		if got := decodeMentions(t, w); len(got) != 1 || got[0].User == nil || got[0].User.Email != "jane@example.com" {
+			t.Errorf("mentions = %+v", got)
+		}
+	})
+
+	t.Run("incident comment", func(t *testing.T) {
+		m := &mockMentionProcessor{mentions: []mentions.Mention{{Handle: "nobody"}}}
+		client := &mockEntityIncidentClient{
+			getIncidentFn: func(_ context.Context, id string) ([]byte, error) {
+				return []byte(`{"id":"` + id + `","number":"INC001","subject":null}`), nil
+			},
+			createCommentFn: func(_ context.Context, _ []byte) ([]byte, error) {
+				return []byte(createdCommentJSON), nil
+			},
+		}
+		h := NewIncidentHandler(client).WithMentions(m)
+		r := withUser(httptest.NewRequest(http.MethodPost, "/incidents/"+testCRID+"/comments", strings.NewReader(`{"type":"comment","content":"@nobody"}`)))
+		r.SetPathValue("id", testCRID)
+		w := httptest.NewRecorder()
+		h.CreateIncidentComment(w, r)
+
+		assertStatus(t, w, http.StatusCreated)
+		if want := (mentions.Record{Type: mentions.RecordIncident, ID: testCRID, Number: "INC001"}); m.rec != want || m.workNote {
+			t.Errorf("record = %+v (work note %t), want %+v", m.rec, m.workNote, want)
+		}
+		if got := decodeMentions(t, w); len(got) != 1 || got[0].Handle != "nobody" || got[0].User != nil {
+			t.Errorf("mentions = %+v, want nobody unresolved", got)
+		}
+	})
+
+	t.Run("change request comment", func(t *testing.T) {
+		m := &mockMentionProcessor{mentions: []mentions.Mention{}}
+		client := &mockEntityChangeRequestClient{
+			getChangeRequestFn: func(_ context.Context, _ string) ([]byte, error) {
+				return []byte(`{"id":"` + testCRID + `","number":"CHG001","subject":"Upgrade"}`), nil
+			},
+			createCommentFn: func(_ context.Context, _ []byte) ([]byte, error) {
+				return []byte(createdCommentJSON), nil
+			},
+		}
+		h := NewChangeRequestHandler(client).WithMentions(m)
+		r := withUser(httptest.NewRequest(http.MethodPost, "/change-requests/"+testCRID+"/comments", strings.NewReader(`{"type":"comment","content":"no mentions"}`)))
+		r.SetPathValue("id", testCRID)
+		w := httptest.NewRecorder()
+		h.CreateChangeRequestComment(w, r)
+
+		assertStatus(t, w, http.StatusCreated)
+		if m.rec.Type != mentions.RecordChangeRequest || m.rec.Number != "CHG001" {
+			t.Errorf("record = %+v", m.rec)
+		}
+		if !strings.Contains(w.Body.String(), `"mentions":[]`) {
+			t.Errorf("body = %s, want an empty mentions list", w.Body.String())
+		}
+	})
+
+	t.Run("without mentions the response is passed through", func(t *testing.T) {
+		client := &mockEntityIncidentClient{
+			getIncidentFn: func(_ context.Context, _ string) ([]byte, error) { return []byte(`{}`), nil },
+			createCommentFn: func(_ context.Context, _ []byte) ([]byte, error) {
+				return []byte(createdCommentJSON), nil
+			},
+		}
+		h := NewIncidentHandler(client)
+		r := withUser(httptest.NewRequest(http.MethodPost, "/incidents/"+testCRID+"/comments", strings.NewReader(`{"type":"comment","content":"@jane"}`)))
+		r.SetPathValue("id", testCRID)
+		w := httptest.NewRecorder()
+		h.CreateIncidentComment(w, r)
+
+		assertStatus(t, w, http.StatusCreated)
+		if w.Body.String() != createdCommentJSON {
+			t.Errorf("body = %s, want the entity response verbatim", w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/casenotify"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+// notificationPreferenceStore abstracts the preference store used by
+// NotificationPreferencesHandler, allowing the handler to be tested
+// independently of the file-backed store.
+type notificationPreferenceStore interface {
+	Get(email string) (casenotify.Preferences, bool)
+	Put(email string, p casenotify.Preferences) (casenotify.Preferences, error)
+}
+
+// NotificationPreferencesHandler handles HTTP requests for the caller's own
+// case notification preferences.
+type NotificationPreferencesHandler struct {
+	store notificationPreferenceStore
+}
+
+// NewNotificationPreferencesHandler creates a NotificationPreferencesHandler
+// backed by the given store.
+func NewNotificationPreferencesHandler(store notificationPreferenceStore) *NotificationPreferencesHandler {
+	return &NotificationPreferencesHandler{store: store}
+}
+
+// GetMyPreferences handles GET /users/me/notification-preferences.
+// A caller who has never saved any gets the defaults.
+func (h *NotificationPreferencesHandler) GetMyPreferences(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+	prefs, _ := h.store.Get(user.Email)
+	writeJSONValue(w, http.StatusOK, prefs)
+}
+
+// PutMyPreferences handles PUT /users/me/notification-preferences.
+// The body replaces the caller's preferences wholesale. Preferences are keyed
+// by the email on the caller's token, the address notifications are sent to.
+func (h *NotificationPreferencesHandler) PutMyPreferences(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+
+	var prefs casenotify.Preferences
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&prefs); err != nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+	if err := prefs.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid notification preferences: "+err.Error()+".")
+		return
+	}
+
+	saved, err := h.store.Put(user.Email, prefs)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "saving notification preferences failed", "userID", user.UserID, "err", err)
+		writeError(w, http.StatusInternalServerError, "Failed to save notification preferences.")
+		return
+	}
+	writeJSONValue(w, http.StatusOK, saved)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/casenotify"
+)
+
+// failingPreferenceStore is a notificationPreferenceStore whose saves fail.
+type failingPreferenceStore struct{}
+
+func (failingPreferenceStore) Get(string) (casenotify.Preferences, bool) {
+	return casenotify.Default(), false
+}
+
+func (failingPreferenceStore) Put(string, casenotify.Preferences) (casenotify.Preferences, error) {
+	return casenotify.Preferences{}, errors.New("disk full")
+}
+
+func newPreferencesHandler(t *testing.T) *NotificationPreferencesHandler {
+	t.Helper()
+	store, err := casenotify.OpenStore("")
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	return NewNotificationPreferencesHandler(store)
+}
+
+func getPreferences(h *NotificationPreferencesHandler, authenticated bool) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/users/me/notification-preferences", nil)
+	if authenticated {
+		r = withUser(r)
+	}
+	w := httptest.NewRecorder()
+	h.GetMyPreferences(w, r)
+	return w
+}
+
+func putPreferences(h *NotificationPreferencesHandler, body string) *httptest.ResponseRecorder {
+	r := withUser(httptest.NewRequest(http.MethodPut, "/users/me/notification-preferences", strings.NewReader(body)))
+	w := httptest.NewRecorder()
+	h.PutMyPreferences(w, r)
+	return w
+}
+
+func TestNotificationPreferences_RequiresAuth(t *testing.T) {
+	assertStatus(t, getPreferences(newPreferencesHandler(t), false), http.StatusUnauthorized)
+}
+
+func TestNotificationPreferences_DefaultsThenSaved(t *testing.T) {
+	h := newPreferencesHandler(t)
+
+	w := getPreferences(h, true)
+	assertStatus(t, w, http.StatusOK)
+	got := decodeJSON[casenotify.Preferences](t, w)
+	if got.DigestFrequency != casenotify.DigestNone || len(got.Events) != 4 {
+		t.Errorf("defaults = %+v, want every event, no digest", got)
+	}
+
+	w = putPreferences(h, `{"channels":["email"],"events":["comment"],"timeZone":"Asia/Colombo",
+		"quietHours":{"start":"22:00","end":"07:00"},"digestFrequency":"daily","digestTime":"09:30"}`)
+	assertStatus(t, w, http.StatusOK)
+
+	got = decodeJSON[casenotify.Preferences](t, getPreferences(h, true))
+	if got.DigestFrequency != casenotify.DigestDaily || got.DigestTime != "09:30" || got.QuietHours == nil || len(got.Events) != 1 {
+		t.Errorf("saved = %+v", got)
+	}
+
+	// Turning everything off is a setting, and comes back as [] not null.
+	w = putPreferences(h, `{"channels":[],"digestFrequency":"none"}`)
+	assertStatus(t, w, http.StatusOK)
+	if body := getPreferences(h, true).Body.String(); !strings.Contains(body, `"channels":[]`) || !strings.Contains(body, `"events":[]`) {
+		t.Errorf("body = %s, want empty channels and events", body)
+	}
+}
+
+func TestNotificationPreferences_RejectsInvalid(t *testing.T) {
+	cases := []struct {
+		name, body, wantMsg string
+	}{
+		{"unknown key", `{"channels":["email"],"digestFrequency":"none","digest":"daily"}`, ErrMsgBadRequest},
+		{"unknown channel", `{"channels":["sms"],"digestFrequency":"none"}`, `Invalid notification preferences: unknown channel "sms".`},
+		{"no frequency", `{"channels":["email"]}`, `Invalid notification preferences: digestFrequency must be one of "none", "hourly" or "daily".`},
+		{"time without daily", `{"digestFrequency":"hourly","digestTime":"08:00"}`, "Invalid notification preferences: digestTime only applies to a daily digest."},
+		{"bad quiet hours", `{"digestFrequency":"none","quietHours":{"start":"22:00","end":"7am"}}`, "Invalid notification preferences: quietHours start and end must be 24-hour HH:MM times."},
+		{"unknown zone", `{"digestFrequency":"none","timeZone":"Mars/Olympus"}`, `Invalid notification preferences: unknown timeZone "Mars/Olympus".`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := putPreferences(newPreferencesHandler(t), tc.body)
+			assertStatus(t, w, http.StatusBadRequest)
+			assertErrorMessage(t, w, tc.wantMsg)
+		})
+	}
+}
+
+func TestNotificationPreferences_SaveFailure(t *testing.T) {
+	w := putPreferences(NewNotificationPreferencesHandler(failingPreferenceStore{}), `{"channels":["email"],"digestFrequency":"none"}`)
+	assertStatus(t, w, http.StatusInternalServerError)
+	assertErrorMessage(t, w, "Failed to save notification preferences.")
+}
@@ -65,7 +65,8 @@ type googleChatAlertRequest struct {
 }
 
 // PostGoogleChatAlert handles POST /notifications/google-chat/alerts.
-// It is called manually today (not yet wired into case/incident creation).
+// It posts an ad-hoc alert; alerts for new and escalated cases and incidents
+// are posted automatically by internal/chatalerts.
 func (h *NotificationHandler) PostGoogleChatAlert(w http.ResponseWriter, r *http.Request) {
 	user := middleware.UserInfoFromContext(r.Context())
 	if user == nil {
@@ -0,0 +1,264 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"crypto/sha1" // #nosec G505 -- a stable calendar UID, not a security boundary
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+// The on-call calendar covers the past week, so a shift just finished still
+// shows, and the next 90 days.
+const (
+	onCallCalendarPast   = 7 * 24 * time.Hour
+	onCallCalendarFuture = 90 * 24 * time.Hour
+)
+
+// onCallUserSearcher abstracts the entity user lookups OnCallHandler uses: the
+// search that resolves an on-call engineer's platform user id, and
+// GET /users/me for the caller's own team.
+type onCallUserSearcher interface {
+	SearchUsers(ctx context.Context, body []byte) ([]byte, error)
+	GetUserMe(ctx context.Context) ([]byte, error)
+}
+
+// OnCallHandler serves the on-call rotations configured alongside the team
+// registry (see directory.OnCall).
+type OnCallHandler struct {
+	dir    *directory.Directory
+	oncall *directory.OnCall
+	users  onCallUserSearcher
+	now    func() time.Time
+}
+
+// NewOnCallHandler creates an OnCallHandler. oncall may be nil when no
+// rotations are configured: every team then has none.
+func NewOnCallHandler(dir *directory.Directory, oncall *directory.OnCall, users onCallUserSearcher) *OnCallHandler {
+	return &OnCallHandler{dir: dir, oncall: oncall, users: users, now: time.Now}
+}
+
+// onCallShiftView is a shift plus the engineer's platform user id, which is
+// what a case search's assignedUserId filter takes. UserID is omitted when
+// the lookup fails.
+type onCallShiftView struct {
+	directory.Shift
+	UserID string `json:"userId,omitempty"`
+}
+
+// teamOnCallView is the GET /teams/{key}/on-call response. OnCall is null
+// when nobody is on call at At.
+type teamOnCallView struct {
+	TeamKey string           `json:"teamKey"`
+	At      time.Time        `json:"at"`
+	OnCall  *onCallShiftView `json:"onCall"`
+}
+
+// GetTeamOnCall handles GET /teams/{key}/on-call.
+// Returns who is on call for the team at ?at= (RFC 3339), default now.
+func (h *OnCallHandler) GetTeamOnCall(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	key := r.PathValue("key")
+	if _, ok := h.dir.TeamByKey(key); !ok {
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		return
+	}
+	if !h.oncall.HasRotation(key) {
+		writeError(w, http.StatusNotFound, "The team has no on-call rotation.")
+		return
+	}
+	at := h.now()
+	if v := r.URL.Query().Get("at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "at must be an RFC 3339 time")
+			return
+		}
+		at = t
+	}
+
+	view := teamOnCallView{TeamKey: key, At: at.UTC()}
+	if s, ok := h.oncall.At(key, at); ok {
+		view.OnCall = &onCallShiftView{Shift: s, UserID: h.userID(r.Context(), s.Email)}
+	}
+	writeJSONValue(w, http.StatusOK, view)
+}
+
+// GetOnCallCalendar handles GET /on-call/calendar.
+// Returns an iCalendar file of ?email='s on-call shifts across every team,
+// default the caller's own, from a week ago to 90 days ahead. It needs the
+// caller's bearer token like every other route, so it is a download to
+// import rather than a URL a calendar app can subscribe to.
+func (h *OnCallHandler) GetOnCallCalendar(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	email := strings.TrimSpace(r.URL.Query().Get("email"))
+	if email == "" {
+		email = user.Email
+	}
+	if email == "" {
+		writeError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	now := h.now()
+	shifts := h.oncall.ShiftsFor(email, now.Add(-onCallCalendarPast), now.Add(onCallCalendarFuture))
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="on-call.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(h.renderCalendar(email, shifts, now)))
+}
+
+// CurrentOnCallUserID returns the platform user id of whoever is on call for
+// teamKey now. ok is false when nobody is or the id cannot be resolved.
+func (h *OnCallHandler) CurrentOnCallUserID(ctx context.Context, teamKey string) (string, bool) {
+	s, ok := h.oncall.At(teamKey, h.now())
+	if !ok {
+		return "", false
+	}
+	id := h.userID(ctx, s.Email)
+	return id, id != ""
+}
+
+// CallerTeamKey returns the registry key of the caller's own team, from the
+// groups GET /users/me reports. ok is false when the lookup fails or the
+// caller is in no registry team.
+func (h *OnCallHandler) CallerTeamKey(ctx context.Context) (string, bool) {
+	raw, err := h.users.GetUserMe(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "entity GetUserMe failed while resolving the caller's on-call team", "err", err)
+		return "", false
+	}
+	var me entityUserMeResponse
+	if err := json.Unmarshal(raw, &me); err != nil {
+		slog.WarnContext(ctx, "entity GetUserMe response unreadable while resolving the caller's on-call team", "err", err)
+		return "", false
+	}
+	for _, g := range me.Groups {
+		if team, ok := h.dir.TeamByGroupName(g.Name); ok {
+			return team.Key, true
+		}
+	}
+	return "", false
+}
+
+// userID resolves email to a platform user id, or "" when the search fails
+// or finds nobody.
+func (h *OnCallHandler) userID(ctx context.Context, email string) string {
+	body, err := json.Marshal(map[string]any{
+		"filters":    map[string]any{"emails": []string{email}},
+		"pagination": map[string]int{"offset": 0, "limit": 1},
+	})
+	if err != nil {
+		return ""
+	}
+	raw, err := h.users.SearchUsers(ctx, body)
+	if err != nil {
+		slog.WarnContext(ctx, "entity SearchUsers failed while resolving the on-call engineer", "email", email, "err", err)
+		return ""
+	}
+	var resp struct {
+		Users []struct {
+			ID    string `json:"id"`
+			Email string `json:"email"`
+		} `json:"users"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		slog.WarnContext(ctx, "entity SearchUsers response unreadable while resolving the on-call engineer", "email", email, "err", err)
+		return ""
+	}
+	for _, u := range resp.Users {
+		if strings.EqualFold(u.Email, email) {
+			return u.ID
+		}
+	}
+	return ""
+}
+
+// renderCalendar renders shifts as an RFC 5545 calendar.
+func (h *OnCallHandler) renderCalendar(email string, shifts []directory.Shift, now time.Time) string {
+	const stamp = "20060102T150405Z"
+	var b strings.Builder
+	line := func(s string) {
+		// Fold at 75 octets, as RFC 5545 requires; a continuation line starts
+		// with a space. Only cut between UTF-8 sequences.
+		for len(s) > 75 {
+			cut := 75
+			for cut > 0 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			b.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		b.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//WSO2//CSM Portal on-call//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + icsText("On-call: "+email))
+	for _, s := range shifts {
+		team := s.TeamKey
+		if t, ok := h.dir.TeamByKey(s.TeamKey); ok {
+			team = t.Name
+		}
+		summary := "On call: " + team
+		description := "Layer: " + s.Layer
+		if s.Override {
+			description = "Override"
+			if s.Reason != "" {
+				description += ": " + s.Reason
+			}
+		}
+		sum := sha1.Sum([]byte(s.TeamKey + "|" + strings.ToLower(s.Email) + "|" + s.Start.UTC().Format(time.RFC3339))) // #nosec G401 -- see import
+		line("BEGIN:VEVENT")
+		line("UID:" + hex.EncodeToString(sum[:]) + "@csm-portal")
+		line("DTSTAMP:" + now.UTC().Format(stamp))
+		line("DTSTART:" + s.Start.UTC().Format(stamp))
+		line("DTEND:" + s.End.UTC().Format(stamp))
+		line("SUMMARY:" + icsText(summary))
+		line("DESCRIPTION:" + icsText(description))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icsText escapes an iCalendar TEXT value.
+func icsText(s string) string { return icsTextEscaper.Replace(s) }
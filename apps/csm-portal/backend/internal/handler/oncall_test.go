@@ -0,0 +1,176 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+)
+
+// mockUserSearcher answers every user search with raw, or err, and
+// GET /users/me with me.
+type mockUserSearcher struct {
+	raw  string
+	err  error
+	body string
+	me   string
+}
+
+func (m *mockUserSearcher) SearchUsers(_ context.Context, body []byte) ([]byte, error) {
+	m.body = string(body)
+	return []byte(m.raw), m.err
+}
+
+func (m *mockUserSearcher) GetUserMe(context.Context) ([]byte, error) {
+	return []byte(m.me), m.err
+}
+
+const testOnCallConfig = `{"rotations":[{"teamKey":"abt-1","timezone":"Asia/Colombo","layers":[
+  {"name":"primary","type":"weekly","start":"2026-01-05","handoff":"09:00","members":["jane@example.com","raj@example.com"]}
+],"overrides":[{"email":"sam@example.com","start":"2026-01-07T00:00:00Z","end":"2026-01-08T00:00:00Z","reason":"Cover, for Jane"}]}]}`
+
+func testOnCallHandler(t *testing.T, users *mockUserSearcher) *OnCallHandler {
+	t.Helper()
+	dir := testDirectory(t)
+	oncall, err := directory.ParseOnCall([]byte(testOnCallConfig), dir)
+	if err != nil {
+		t.Fatalf("ParseOnCall: %v", err)
+	}
+	h := NewOnCallHandler(dir, oncall, users)
+	h.now = func() time.Time { return time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC) }
+	return h
+}
+
+func getTeamOnCall(h *OnCallHandler, key, query string) *httptest.ResponseRecorder {
+	r := withUser(httptest.NewRequest(http.MethodGet, "/teams/"+key+"/on-call"+query, nil))
+	r.SetPathValue("key", key)
+	w := httptest.NewRecorder()
+	h.GetTeamOnCall(w, r)
+	return w
+}
+
+func TestGetTeamOnCall(t *testing.T) {
+	users := &mockUserSearcher{raw: `{"users":[{"id":"u-jane","email":"Jane@example.com"}]}`}
+	h := testOnCallHandler(t, users)
+
+	w := getTeamOnCall(h, "abt-1", "")
+	assertStatus(t, w, http.StatusOK)
+	got := decodeJSON[teamOnCallView](t, w)
+	if got.OnCall == nil || got.OnCall.Email != "jane@example.com" || got.OnCall.UserID != "u-jane" || got.OnCall.Layer != "primary" {
+		t.Errorf("on call = %+v", got.OnCall)
+	}
+	if !strings.Contains(users.body, `"emails":["jane@example.com"]`) {
+		t.Errorf("user search body = %s", users.body)
+	}
+
+	got = decodeJSON[teamOnCallView](t, getTeamOnCall(h, "abt-1", "?at=2026-01-07T10:00:00Z"))
+	if got.OnCall == nil || got.OnCall.Email != "sam@example.com" || !got.OnCall.Override || got.OnCall.UserID != "" {
+		t.Errorf("override = %+v", got.OnCall)
+	}
+
+	got = decodeJSON[teamOnCallView](t, getTeamOnCall(h, "abt-1", "?at=2025-12-01T00:00:00Z"))
+	if got.OnCall != nil {
+		t.Errorf("before the rotation starts: on call = %+v", got.OnCall)
+	}
+
+	assertStatus(t, getTeamOnCall(h, "abt-1", "?at=tomorrow"), http.StatusBadRequest)
+	assertStatus(t, getTeamOnCall(h, "abt-2", ""), http.StatusNotFound)
+	assertStatus(t, getTeamOnCall(h, "nope", ""), http.StatusNotFound)
+
+	// The lookup failing still answers who; only the id is missing.
+	users.err = errors.New("entity down")
+	got = decodeJSON[teamOnCallView](t, getTeamOnCall(h, "abt-1", ""))
+	if got.OnCall == nil || got.OnCall.UserID != "" {
+		t.Errorf("with the lookup failing: on call = %+v", got.OnCall)
+	}
+	if _, ok := h.CurrentOnCallUserID(context.Background(), "abt-1"); ok {
+		t.Error("CurrentOnCallUserID resolved an id with the lookup failing")
+	}
+
+	w = httptest.NewRecorder()
+	h.GetTeamOnCall(w, httptest.NewRequest(http.MethodGet, "/teams/abt-1/on-call", nil))
+	assertStatus(t, w, http.StatusUnauthorized)
+}
+
+func TestOnCallHandler_CallerTeamKey(t *testing.T) {
+	users := &mockUserSearcher{me: `{"id":"u-1","groups":[{"id":"g1","name":"Everyone"},{"id":"g2","name":"ABT One"}]}`}
+	h := testOnCallHandler(t, users)
+	if key, ok := h.CallerTeamKey(context.Background()); !ok || key != "abt-1" {
+		t.Errorf("CallerTeamKey = %q, %v, want abt-1", key, ok)
+	}
+
+	users.me = `{"id":"u-1","groups":[{"id":"g1","name":"Everyone"}]}`
+	if key, ok := h.CallerTeamKey(context.Background()); ok {
+		t.Errorf("CallerTeamKey with no registry team = %q", key)
+	}
+
+	users.err = errors.New("entity down")
+	if _, ok := h.CallerTeamKey(context.Background()); ok {
+		t.Error("CallerTeamKey resolved a team with the lookup failing")
+	}
+}
+
+func TestGetOnCallCalendar(t *testing.T) {
+	h := testOnCallHandler(t, &mockUserSearcher{})
+
+	w := httptest.NewRecorder()
+	h.GetOnCallCalendar(w, withUser(httptest.NewRequest(http.MethodGet, "/on-call/calendar?email=sam@example.com", nil)))
+	assertStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20260107T000000Z\r\n",
+		"DTEND:20260108T000000Z\r\n",
+		"SUMMARY:On call: ABT One\r\n",
+		`DESCRIPTION:Override: Cover\, for Jane` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("calendar is missing %q:\n%s", want, body)
+		}
+	}
+	if n := strings.Count(body, "BEGIN:VEVENT"); n != 1 {
+		t.Errorf("%d events, want 1", n)
+	}
+
+	// Without ?email= it is the caller's own calendar; the test user has no
+	// shifts.
+	w = httptest.NewRecorder()
+	h.GetOnCallCalendar(w, withUser(httptest.NewRequest(http.MethodGet, "/on-call/calendar", nil)))
+	assertStatus(t, w, http.StatusOK)
+	if strings.Contains(w.Body.String(), "BEGIN:VEVENT") {
+		t.Errorf("caller's calendar has events:\n%s", w.Body.String())
+	}
+}
+
+func TestOnCallHandler_NoRotations(t *testing.T) {
+	h := NewOnCallHandler(testDirectory(t), nil, &mockUserSearcher{})
+	assertStatus(t, getTeamOnCall(h, "abt-1", ""), http.StatusNotFound)
+	w := httptest.NewRecorder()
+	h.GetOnCallCalendar(w, withUser(httptest.NewRequest(http.MethodGet, "/on-call/calendar", nil)))
+	assertStatus(t, w, http.StatusOK)
+}
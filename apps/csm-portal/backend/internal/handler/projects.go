@@ -22,6 +22,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
 )
@@ -39,11 +40,13 @@ type entityProjectClient interface {
 // entity service for data access.
 type ProjectHandler struct {
 	entity entityProjectClient
+	// now is the clock the renewal view buckets against; overridden in tests.
+	now func() time.Time
 }
 
 // NewProjectHandler creates a ProjectHandler backed by the given entity client.
 func NewProjectHandler(entity entityProjectClient) *ProjectHandler {
-	return &ProjectHandler{entity: entity}
+	return &ProjectHandler{entity: entity, now: time.Now}
 }
 
 // GetProject handles GET /projects/{id}.
@@ -0,0 +1,404 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+)
+
+// renewalWindows are the Account Closure Process (ACP) notice thresholds in
+// days-to-end-date, widest first. They must stay in step with the ACP
+// service's closure.NoticeWindow values: a renewal manager reading this view
+// is trying to get ahead of exactly those notices, so a bucket boundary that
+// drifts from ACP's would show a project as "not yet noticed" on the day the
+// notice actually goes out. Window 0 is the terminal bucket: the end date has
+// passed (or is today) and ACP suspends.
+var renewalWindows = []int{90, 60, 30, 15, 7, 0}
+
+// renewalOverdueLookbackDays bounds how far past its end date a project is
+// still listed in the window-0 bucket. Without a lower bound every project that
+// ever lapsed would sit in that bucket forever.
+const renewalOverdueLookbackDays = 30
+
+// renewalDefaultLimit and renewalMaxLimit bound the page size. The cap is lower
+// than the plain project search's because each project on the page costs one
+// extra upstream contacts lookup.
+const (
+	renewalDefaultLimit = 20
+	renewalMaxLimit     = 50
+)
+
+// renewalContactsLimit is the page size of the per-project contacts lookup. A
+// project with more contacts than this and no business contact among the first
+// page is reported as having none, which is accepted: real projects have a
+// handful of contacts.
+const renewalContactsLimit = 100
+
+// renewalBusinessContactRole is the project-contact role ACP resolves a
+// project's customer-facing notice recipient from. Mirrors the ACP service's
+// recipients.businessContactRole; change both together.
+const renewalBusinessContactRole = "business_contact"
+
+// renewalSubscriptionEndDateKey is the suspensionProcessState key the ACP
+// service records its subscription-end-date notices under.
+const renewalSubscriptionEndDateKey = "based_on_subscription_end_date"
+
+// renewalEventTypeToWindow maps the event_type values ACP writes under
+// renewalSubscriptionEndDateKey to the notice window each one records. Any
+// other value (notably "open") means no notice has been sent.
+var renewalEventTypeToWindow = map[string]int{
+	"90_days_notice": 90,
+	"60_days_notice": 60,
+	"30_days_notice": 30,
+	"15_days_notice": 15,
+	"7_days_notice":  7,
+	"suspend":        0,
+}
+
+// renewalSearchRequest is the body of POST /projects/renewals/search.
+type renewalSearchRequest struct {
+	Filters struct {
+		// Window restricts the search to a single bucket. Nil means every bucket.
+		Window        *int   `json:"window"`
+		AccountID     string `json:"accountId"`
+		ClosureStatus string `json:"closureStatus"`
+	} `json:"filters"`
+	Pagination struct {
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+	} `json:"pagination"`
+}
+
+// renewalUpstreamProject is the subset of the entity service's project search
+// result this view reads. Fields outside the subset are not forwarded.
+type renewalUpstreamProject struct {
+	ID                         string          `json:"id"`
+	Name                       string          `json:"name"`
+	Key                        string          `json:"key"`
+	SubscriptionType           string          `json:"subscriptionType"`
+	EndDate                    *time.Time      `json:"endDate"`
+	Account                    *entityRef      `json:"account"`
+	ClosureStatus              *string         `json:"closureStatus"`
+	EndDateClosureState        *string         `json:"endDateClosureState"`
+	InvoiceDueDateClosureState *string         `json:"invoiceDueDateClosureState"`
+	SuspensionProcessState     json.RawMessage `json:"suspensionProcessState"`
+}
+
+// entityRef is the entity service's {id, name} reference shape.
+type entityRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// renewalProject is one project in the renewal pipeline view.
+type renewalProject struct {
+	ID                         string     `json:"id"`
+	Name                       string     `json:"name"`
+	Key                        string     `json:"key"`
+	SubscriptionType           string     `json:"subscriptionType"`
+	Account                    *entityRef `json:"account"`
+	EndDate                    time.Time  `json:"endDate"`
+	DaysRemaining              int        `json:"daysRemaining"`
+	Window                     int        `json:"window"`
+	ClosureStatus              *string    `json:"closureStatus"`
+	EndDateClosureState        *string    `json:"endDateClosureState"`
+	InvoiceDueDateClosureState *string    `json:"invoiceDueDateClosureState"`
+	// LastNoticeWindow is the window of the last ACP notice recorded for the
+	// project, or nil when none has been sent (or the state is unreadable).
+	LastNoticeWindow *int `json:"lastNoticeWindow"`
+	// NoticePending is true when the project has reached a window whose ACP
+	// notice has not gone out yet: the one thing a renewal manager can still
+	// get ahead of.
+	NoticePending bool `json:"noticePending"`
+	// HasBusinessContact is nil when the contacts lookup failed; the row is
+	// still returned rather than failing the whole page over it.
+	HasBusinessContact *bool `json:"hasBusinessContact"`
+}
+
+// renewalBucket groups the page's projects that fall into one window.
+type renewalBucket struct {
+	Window      int              `json:"window"`
+	EndDateFrom string           `json:"endDateFrom"`
+	EndDateTo   string           `json:"endDateTo"`
+	Projects    []renewalProject `json:"projects"`
+}
+
+// renewalSearchResponse is the response of POST /projects/renewals/search.
+type renewalSearchResponse struct {
+	AsOf    string          `json:"asOf"`
+	Buckets []renewalBucket `json:"buckets"`
+	Total   int             `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+	HasMore bool            `json:"hasMore"`
+}
+
+// SearchRenewals handles POST /projects/renewals/search: the renewal and
+// closure pipeline view over projects.
+//
+// Projects whose end date falls within the widest ACP window (or lapsed within
+// renewalOverdueLookbackDays) are fetched in end-date order, one page at a
+// time, and each is bucketed by days-to-end-date using ACP's own windows. For
+// each one the last notice ACP recorded is decoded from suspensionProcessState
+// and its contacts are checked for a business contact, so a renewal manager
+// can act before the automated notices go out. Buckets are always returned in
+// widest-first order, including empty ones, so the view's layout does not
+// shift from page to page.
+func (h *ProjectHandler) SearchRenewals(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+
+	var req renewalSearchRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+			return
+		}
+	}
+	if req.Filters.Window != nil && !slices.Contains(renewalWindows, *req.Filters.Window) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("filters.window must be one of %v.", renewalWindows))
+		return
+	}
+	if req.Filters.AccountID != "" && !uuidRe.MatchString(req.Filters.AccountID) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+	limit := req.Pagination.Limit
+	if limit == 0 {
+		limit = renewalDefaultLimit
+	}
+	if limit < 0 || limit > renewalMaxLimit || req.Pagination.Offset < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("pagination.limit must be between 1 and %d and pagination.offset must not be negative.", renewalMaxLimit))
+		return
+	}
+
+	today := h.now().UTC().Truncate(24 * time.Hour)
+	from, to := renewalSearchRange(today, req.Filters.Window)
+
+	upstreamBody, err := json.Marshal(map[string]any{
+		"endDateFrom":   from.Format(time.DateOnly),
+		"endDateTo":     to.Format(time.DateOnly),
+		"accountId":     req.Filters.AccountID,
+		"closureStatus": req.Filters.ClosureStatus,
+		"sortBy":        "endDate",
+		"sortOrder":     "asc",
+		"pagination":    map[string]int{"limit": limit, "offset": req.Pagination.Offset},
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+
+	result, err := h.entity.SearchProjects(r.Context(), upstreamBody)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity SearchProjects failed", "userID", user.UserID, "err", err)
+		mapUpstreamErrorGeneric(w, err, "Failed to search project renewals.")
+		return
+	}
+
+	var page struct {
+		Projects []renewalUpstreamProject `json:"projects"`
+		Total    int                      `json:"total"`
+		Limit    int                      `json:"limit"`
+		Offset   int                      `json:"offset"`
+		HasMore  bool                     `json:"hasMore"`
+	}
+	if err := json.Unmarshal(result, &page); err != nil {
+		slog.ErrorContext(r.Context(), "decode entity SearchProjects response failed", "userID", user.UserID, "err", err)
+		writeError(w, http.StatusInternalServerError, "Failed to search project renewals.")
+		return
+	}
+
+	buckets := make([]renewalBucket, 0, len(renewalWindows))
+	index := make(map[int]int, len(renewalWindows))
+	for _, window := range renewalWindows {
+		if req.Filters.Window != nil && *req.Filters.Window != window {
+			continue
+		}
+		bFrom, bTo := renewalSearchRange(today, &window)
+		index[window] = len(buckets)
+		buckets = append(buckets, renewalBucket{
+			Window:      window,
+			EndDateFrom: bFrom.Format(time.DateOnly),
+			EndDateTo:   bTo.Format(time.DateOnly),
+			Projects:    []renewalProject{},
+		})
+	}
+
+	for _, p := range page.Projects {
+		if p.EndDate == nil {
+			// The entity service's date filter already excludes these; a row
+			// without an end date cannot be bucketed either way.
+			continue
+		}
+		days := renewalDaysRemaining(today, *p.EndDate)
+		window := renewalWindowFor(days)
+		i, ok := index[window]
+		if !ok {
+			continue
+		}
+		row := renewalProject{
+			ID:                         p.ID,
+			Name:                       p.Name,
+			Key:                        p.Key,
+			SubscriptionType:           p.SubscriptionType,
+			Account:                    p.Account,
+			EndDate:                    *p.EndDate,
+			DaysRemaining:              days,
+			Window:                     window,
+			ClosureStatus:              p.ClosureStatus,
+			EndDateClosureState:        p.EndDateClosureState,
+			InvoiceDueDateClosureState: p.InvoiceDueDateClosureState,
+		}
+		last, err := renewalLastNoticeWindow(p.SuspensionProcessState)
+		if err != nil {
+			// A malformed blob is ACP's problem to surface, not this view's;
+			// report the project as never noticed rather than dropping it.
+			slog.WarnContext(r.Context(), "unreadable suspensionProcessState", "projectID", p.ID, "err", err)
+		}
+		row.LastNoticeWindow = last
+		row.NoticePending = last == nil || *last > window
+		row.HasBusinessContact = h.hasBusinessContact(r, p.ID)
+		buckets[i].Projects = append(buckets[i].Projects, row)
+	}
+
+	writeJSONValue(w, http.StatusOK, renewalSearchResponse{
+		AsOf:    today.Format(time.DateOnly),
+		Buckets: buckets,
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		HasMore: page.HasMore,
+	})
+}
+
+// hasBusinessContact reports whether the project has at least one contact with
+// the business-contact role. Best-effort: nil when the lookup fails.
+func (h *ProjectHandler) hasBusinessContact(r *http.Request, projectID string) *bool {
+	body, err := json.Marshal(map[string]any{
+		"pagination": map[string]int{"limit": renewalContactsLimit, "offset": 0},
+	})
+	if err != nil {
+		return nil
+	}
+	result, err := h.entity.SearchProjectContacts(r.Context(), projectID, body)
+	if err != nil {
+		slog.WarnContext(r.Context(), "entity SearchProjectContacts failed; business contact unknown", "projectID", projectID, "err", summarizeErr(err))
+		return nil
+	}
+	var page struct {
+		Contacts []struct {
+			Roles []string `json:"roles"`
+		} `json:"contacts"`
+	}
+	if err := json.Unmarshal(result, &page); err != nil {
+		slog.WarnContext(r.Context(), "decode entity SearchProjectContacts response failed; business contact unknown", "projectID", projectID, "err", err)
+		return nil
+	}
+	found := false
+	for _, c := range page.Contacts {
+		if slices.Contains(c.Roles, renewalBusinessContactRole) {
+			found = true
+			break
+		}
+	}
+	return &found
+}
+
+// renewalSearchRange returns the inclusive end-date range for one window, or
+// for every window when window is nil. A window covers the days-to-end-date
+// after the next narrower window up to and including its own threshold; window
+// 0 covers today back to renewalOverdueLookbackDays ago.
+func renewalSearchRange(today time.Time, window *int) (time.Time, time.Time) {
+	if window == nil {
+		return today.AddDate(0, 0, -renewalOverdueLookbackDays), today.AddDate(0, 0, renewalWindows[0])
+	}
+	if *window == 0 {
+		return today.AddDate(0, 0, -renewalOverdueLookbackDays), today
+	}
+	i := slices.Index(renewalWindows, *window)
+	narrower := renewalWindows[i+1]
+	return today.AddDate(0, 0, narrower+1), today.AddDate(0, 0, *window)
+}
+
+// renewalDaysRemaining is the number of calendar days from today to endDate,
+// matching ACP's rounding: a project ending any time tomorrow has one day left.
+func renewalDaysRemaining(today, endDate time.Time) int {
+	end := endDate.UTC().Truncate(24 * time.Hour)
+	return int(end.Sub(today).Hours() / 24)
+}
+
+// renewalWindowFor returns the narrowest window that days has reached.
+func renewalWindowFor(days int) int {
+	window := renewalWindows[0]
+	for _, w := range renewalWindows {
+		if days <= w {
+			window = w
+		}
+	}
+	return window
+}
+
+// renewalLastNoticeWindow decodes the last ACP notice window from a raw
+// suspensionProcessState blob. Nil, without error, when the blob is empty, has
+// no subscription-end-date section, or records no notice.
+func renewalLastNoticeWindow(raw json.RawMessage) (*int, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var blob map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &blob); err != nil {
+		return nil, fmt.Errorf("parse suspensionProcessState: %w", err)
+	}
+	section, ok := blob[renewalSubscriptionEndDateKey]
+	if !ok {
+		return nil, nil
+	}
+	var state struct {
+		EventType string `json:"event_type"`
+	}
+	if err := json.Unmarshal(section, &state); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", renewalSubscriptionEndDateKey, err)
+	}
+	window, ok := renewalEventTypeToWindow[state.EventType]
+	if !ok {
+		return nil, nil
+	}
+	return &window, nil
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// renewalTestNow is mid-morning so the tests also prove a project's days
+// remaining is counted in calendar days, not from the current hour.
+var renewalTestNow = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+func newRenewalTestHandler(client *mockEntityProjectClient) *ProjectHandler {
+	h := NewProjectHandler(client)
+	h.now = func() time.Time { return renewalTestNow }
+	return h
+}
+
+func TestSearchRenewals(t *testing.T) {
+	t.Run("requires authenticated user", func(t *testing.T) {
+		h := newRenewalTestHandler(&mockEntityProjectClient{})
+		r := httptest.NewRequest(http.MethodPost, "/projects/renewals/search", strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		h.SearchRenewals(w, r)
+		assertStatus(t, w, http.StatusUnauthorized)
+		assertErrorMessage(t, w, ErrMsgUnauthorized)
+	})
+
+	t.Run("rejects invalid request bodies", func(t *testing.T) {
+		for _, tc := range []struct {
+			name, body string
+		}{
+			{"malformed JSON", `not-json`},
+			{"unknown window", `{"filters":{"window":45}}`},
+			{"non-UUID account", `{"filters":{"accountId":"acc-1"}}`},
+			{"limit over the cap", `{"pagination":{"limit":51}}`},
+			{"negative offset", `{"pagination":{"offset":-1}}`},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				called := false
+				h := newRenewalTestHandler(&mockEntityProjectClient{
+					searchProjectsFn: func(_ context.Context, _ []byte) ([]byte, error) {
+						called = true
+						return []byte(`{}`), nil
+					},
+				})
+				r := withUser(httptest.NewRequest(http.MethodPost, "/projects/renewals/search", strings.NewReader(tc.body)))
+				w := httptest.NewRecorder()
+				h.SearchRenewals(w, r)
+				assertStatus(t, w, http.StatusBadRequest)
+				if called {
+					t.Error("upstream was called for an invalid request")
+				}
+			})
+		}
+	})
+
+	t.Run("searches the full window range sorted by end date", func(t *testing.T) {
+		var captured map[string]any
+		h := newRenewalTestHandler(&mockEntityProjectClient{
+			searchProjectsFn: func(_ context.Context, body []byte) ([]byte, error) {
+				if err := json.Unmarshal(body, &captured); err != nil {
+					t.Fatalf("upstream body is not JSON: %v", err)
+				}
+				return []byte(`{"projects":[],"total":0,"limit":20,"offset":0,"hasMore":false}`), nil
+			},
+		})
+		r := withUser(httptest.NewRequest(http.MethodPost, "/projects/renewals/search", nil))
+		w := httptest.NewRecorder()
+		h.SearchRenewals(w, r)
+
+		assertStatus(t, w, http.StatusOK)
+		want := map[string]any{
+			"endDateFrom": "2026-01-30",
+			"endDateTo":   "2026-05-30",
+			"sortBy":      "endDate",
+			"sortOrder":   "asc",
+		}
+		for k, v := range want {
+			if captured[k] != v {
+				t.Errorf("upstream %s = %v, want %v", k, captured[k], v)
+			}
+		}
+		resp := decodeJSON[renewalSearchResponse](t, w)
+		if len(resp.Buckets) != len(renewalWindows) {
+			t.Fatalf("got %d buckets, want %d (empty buckets included)", len(resp.Buckets), len(renewalWindows))
+		}
+		for i, b := range resp.Buckets {
+			if b.Window != renewalWindows[i] {
+				t.Errorf("bucket %d window = %d, want %d", i, b.Window, renewalWindows[i])
+			}
+		}
+	})
+
+	t.Run("a window filter narrows the range to that bucket", func(t *testing.T) {
+		var captured map[string]any
+		h := newRenewalTestHandler(&mockEntityProjectClient{
+			searchProjectsFn: func(_ context.Context, body []byte) ([]byte, error) {
+				_ = json.Unmarshal(body, &captured)
+				return []byte(`{"projects":[]}`), nil
+			},
+		})
+		r := withUser(httptest.NewRequest(http.MethodPost, "/projects/renewals/search", strings.NewReader(`{"filters":{"window":30}}`)))
+		w := httptest.NewRecorder()
+		h.SearchRenewals(w, r)
+
+		assertStatus(t, w, http.StatusOK)
+		if captured["endDateFrom"] != "2026-03-17" || captured["endDateTo"] != "2026-03-31" {
+			t.Errorf("upstream range = %v..%v, want 2026-03-17..2026-03-31", captured["endDateFrom"], captured["endDateTo"])
+		}
+		resp := decodeJSON[renewalSearchResponse](t, w)
+		if len(resp.Buckets) != 1 || resp.Buckets[0].Window != 30 {
+			t.Errorf("buckets = %+v, want only window 30", resp.Buckets)
+		}
+	})
+
+	t.Run("buckets projects and decodes the last notice and business contact", func(t *testing.T) {
+		const page = `{"projects":[
+			{"id":"p-overdue","name":"Overdue","endDate":"2026-02-27T00:00:00Z",
+			 "suspensionProcessState":{"based_on_subscription_end_date":{"event_type":"suspend"}}},
+			{"id":"p-week","name":"Week","endDate":"2026-03-06T00:00:00Z",
+			 "suspensionProcessState":{"based_on_subscription_end_date":{"event_type":"15_days_notice"}}},
+			{"id":"p-far","name":"Far","endDate":"2026-05-20T00:00:00Z",
+			 "suspensionProcessState":{"based_on_subscription_end_date":{"event_type":"open"}}},
+			{"id":"p-nodate","name":"No date","endDate":null}
+		],"total":4,"limit":20,"offset":0,"hasMore":false}`
+		h := newRenewalTestHandler(&mockEntityProjectClient{
+			searchProjectsFn: func(_ context.Context, _ []byte) ([]byte, error) {
+				return []byte(page), nil
+			},
+			searchProjectContactsFn: func(_ context.Context, projectID string, _ []byte) ([]byte, error) {
+				switch projectID {
+				case "p-week":
+					return []byte(`{"contacts":[{"roles":["technical_contact","business_contact"]}]}`), nil
+				case "p-far":
+					return nil, errors.New("upstream connection refused")
+				}
+				return []byte(`{"contacts":[{"roles":["technical_contact"]}]}`), nil
+			},
+		})
+		r := withUser(httptest.NewRequest(http.MethodPost, "/projects/renewals/search", strings.NewReader(`{}`)))
+		w := httptest.NewRecorder()
+		h.SearchRenewals(w, r)
+
+		assertStatus(t, w, http.StatusOK)
+		resp := decodeJSON[renewalSearchResponse](t, w)
+		got := map[string]renewalProject{}
+		windows := map[string]int{}
+		for _, b := range resp.Buckets {
+			for _, p := range b.Projects {
+				got[p.ID] = p
+				windows[p.ID] = b.Window
+			}
+		}
+		if _, ok := got["p-nodate"]; ok {
+			t.Error("a project with no end date was bucketed")
+		}
+
+		overdue := got["p-overdue"]
+		if windows["p-overdue"] != 0 || overdue.DaysRemaining != -2 {
+			t.Errorf("p-overdue in window %d with %d days, want window 0 with -2", windows["p-overdue"], overdue.DaysRemaining)
+		}
+		if overdue.NoticePending || overdue.LastNoticeWindow == nil || *overdue.LastNoticeWindow != 0 {
+			t.Errorf("p-overdue last notice %v pending %v, want 0 and not pending", overdue.LastNoticeWindow, overdue.NoticePending)
+		}
+		if overdue.HasBusinessContact == nil || *overdue.HasBusinessContact {
+			t.Errorf("p-overdue hasBusinessContact = %v, want false", overdue.HasBusinessContact)
+		}
+
+		week := got["p-week"]
+		if windows["p-week"] != 7 || week.DaysRemaining != 5 {
+			t.Errorf("p-week in window %d with %d days, want window 7 with 5", windows["p-week"], week.DaysRemaining)
+		}
+		if !week.NoticePending {
+			t.Error("p-week: 15-day notice sent but 7-day window reached; want noticePending")
+		}
+		if week.HasBusinessContact == nil || !*week.HasBusinessContact {
+			t.Errorf("p-week hasBusinessContact = %v, want true", week.HasBusinessContact)
+		}
+
+		far := got["p-far"]
+		if windows["p-far"] != 90 || far.LastNoticeWindow != nil || !far.NoticePending {
+			t.Errorf("p-far window %d last %v pending %v, want 90, nil, true", windows["p-far"], far.LastNoticeWindow, far.NoticePending)
+		}
+		if far.HasBusinessContact != nil {
+			t.Errorf("p-far hasBusinessContact = %v, want nil after a failed lookup", *far.HasBusinessContact)
+		}
+	})
+
+	t.Run("upstream errors are mapped correctly", func(t *testing.T) {
+		for _, tc := range upstreamErrorsGeneric("Failed to search project renewals.") {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				h := newRenewalTestHandler(&mockEntityProjectClient{
+					searchProjectsFn: func(_ context.Context, _ []byte) ([]byte, error) {
+						return nil, tc.err
+					},
+				})
+				r := withUser(httptest.NewRequest(http.MethodPost, "/projects/renewals/search", strings.NewReader(`{}`)))
+				w := httptest.NewRecorder()
+				h.SearchRenewals(w, r)
+				assertStatus(t, w, tc.wantCode)
+				assertErrorMessage(t, w, tc.wantMsg)
+			})
+		}
+	})
+}
+
+func TestRenewalWindowFor(t *testing.T) {
+	for days, want := range map[int]int{-5: 0, 0: 0, 1: 7, 7: 7, 8: 15, 15: 15, 16: 30, 31: 60, 60: 60, 61: 90, 90: 90} {
+		if got := renewalWindowFor(days); got != want {
+			t.Errorf("renewalWindowFor(%d) = %d, want %d", days, got, want)
+		}
+	}
+}
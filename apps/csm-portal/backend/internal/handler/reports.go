@@ -0,0 +1,81 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/middleware"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/reports"
+)
+
+const (
+	errMsgNoReportSchedule = "This dashboard has no report schedule."
+	errMsgReportsDisabled  = "Dashboard reports are not available: the email channel is not configured."
+)
+
+// dashboardReportSender abstracts the report scheduler used by ReportHandler,
+// allowing the handler to be tested independently of the real scheduler.
+type dashboardReportSender interface {
+	SendNow(ctx context.Context, dashboardID string) error
+}
+
+// ReportHandler handles HTTP requests for emailed dashboard snapshot reports.
+type ReportHandler struct {
+	reports dashboardReportSender
+}
+
+// NewReportHandler creates a ReportHandler backed by the given scheduler.
+func NewReportHandler(sender dashboardReportSender) *ReportHandler {
+	return &ReportHandler{reports: sender}
+}
+
+// SendDashboardReport handles POST /dashboards/{dashboardId}/report.
+//
+// Sends the dashboard's report now, to the recipients its schedule already
+// names -- deliberately not to caller-supplied addresses, so the endpoint
+// cannot be used to mail portal data anywhere else. The run itself happens in
+// the background (see reports.Scheduler.SendNow), hence 202.
+func (h *ReportHandler) SendDashboardReport(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserInfoFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+
+	dashboardID := r.PathValue("dashboardId")
+	if err := h.reports.SendNow(r.Context(), dashboardID); err != nil {
+		switch {
+		case errors.Is(err, reports.ErrUnknownDashboard):
+			writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		case errors.Is(err, reports.ErrNoSchedule):
+			writeError(w, http.StatusConflict, errMsgNoReportSchedule)
+		case errors.Is(err, reports.ErrEmailUnavailable):
+			writeError(w, http.StatusServiceUnavailable, errMsgReportsDisabled)
+		default:
+			slog.ErrorContext(r.Context(), "dashboard report SendNow failed", "userID", user.UserID, "dashboardId", dashboardID, "err", err)
+			writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		}
+		return
+	}
+
+	slog.InfoContext(r.Context(), "dashboard report requested", "userID", user.UserID, "dashboardId", dashboardID)
+	writeJSONValue(w, http.StatusAccepted, map[string]string{"message": "report queued"})
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/reports"
+)
+
+// mockDashboardReportSender is a test double for dashboardReportSender.
+type mockDashboardReportSender struct {
+	err            error
+	gotDashboardID string
+	called         bool
+}
+
+func (m *mockDashboardReportSender) SendNow(ctx context.Context, dashboardID string) error {
+	m.called = true
+	m.gotDashboardID = dashboardID
+	return m.err
+}
+
+func sendReportRequest(h *ReportHandler, authenticated bool) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/dashboards/cs-overview/report", nil)
+	r.SetPathValue("dashboardId", "cs-overview")
+	if authenticated {
+		r = withUser(r)
+	}
+	w := httptest.NewRecorder()
+	h.SendDashboardReport(w, r)
+	return w
+}
+
+func TestSendDashboardReport_RequiresAuth(t *testing.T) {
+	mock := &mockDashboardReportSender{}
+	w := sendReportRequest(NewReportHandler(mock), false)
+
+	assertStatus(t, w, http.StatusUnauthorized)
+	if mock.called {
+		t.Error("scheduler should not have been called without a user")
+	}
+}
+
+func TestSendDashboardReport_Queued(t *testing.T) {
+	mock := &mockDashboardReportSender{}
+	w := sendReportRequest(NewReportHandler(mock), true)
+
+	assertStatus(t, w, http.StatusAccepted)
+	if mock.gotDashboardID != "cs-overview" {
+		t.Errorf("dashboardID = %q, want cs-overview", mock.gotDashboardID)
+	}
+}
+
+func TestSendDashboardReport_Errors(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{"unknown dashboard", reports.ErrUnknownDashboard, http.StatusNotFound, ErrMsgNotFound},
+		{"no schedule", reports.ErrNoSchedule, http.StatusConflict, errMsgNoReportSchedule},
+		{"email not configured", reports.ErrEmailUnavailable, http.StatusServiceUnavailable, errMsgReportsDisabled},
+		{"anything else", errors.New("boom"), http.StatusInternalServerError, ErrMsgInternal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := sendReportRequest(NewReportHandler(&mockDashboardReportSender{err: tc.err}), true)
+
+			assertStatus(t, w, tc.wantStatus)
+			assertErrorMessage(t, w, tc.wantMsg)
+		})
+	}
+}
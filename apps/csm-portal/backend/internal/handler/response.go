@@ -34,6 +34,7 @@ const (
 	ErrMsgTooLarge               = "Request body too large."
 	ErrMsgInternal               = "An internal server error occurred. Please try again later."
 	ErrMsgInvalidTransition      = "Invalid state transition."
+	ErrMsgTransitionNotPermitted = "You are not permitted to move this case to the requested state."
 	ErrMsgWorkStateNotAllowed    = "Work state can only be updated when the case is in progress."
 	ErrMsgCommentNotAllowed      = "Comments can only be added when the case is in progress and the work state is ongoing."
 	ErrMsgCommentNotOwnCase      = "Only the assigned engineer can add a public comment on this case."
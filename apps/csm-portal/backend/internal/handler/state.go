@@ -19,6 +19,8 @@ package handler
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/workflow"
 )
 
 // relatedCaseWindow mirrors the upstream data source's own eligibility rule:
@@ -30,58 +32,20 @@ const relatedCaseWindow = 60 * 24 * time.Hour
 // service_request, security_report_analysis, or engagement. Related-case
 // creation is only offered for this type; the other types have their own
 // create flows that don't accept a relatedCaseId today.
-const caseTypeCase = "case"
+const caseTypeCase = workflow.CaseTypeCase
 
+// Case states, aliased from the workflow package so the handlers keep their
+// own short names for the states they special-case.
 const (
-	caseStateOpen             = "open"
-	caseStateWorkInProgress   = "work_in_progress"
-	caseStateWaitingOnWSO2    = "waiting_on_wso2"
-	caseStateAwaitingInfo     = "awaiting_info"
-	caseStateSolutionProposed = "solution_proposed"
-	caseStateClosed           = "closed"
-	caseStateReopened         = "reopened"
+	caseStateOpen             = workflow.StateOpen
+	caseStateWorkInProgress   = workflow.StateWorkInProgress
+	caseStateWaitingOnWSO2    = workflow.StateWaitingOnWSO2
+	caseStateAwaitingInfo     = workflow.StateAwaitingInfo
+	caseStateSolutionProposed = workflow.StateSolutionProposed
+	caseStateClosed           = workflow.StateClosed
+	caseStateReopened         = workflow.StateReopened
 )
 
-// nextStates returns the valid next states reachable from the given case state.
-// Role-gated transitions (team-lead override, auto-close) are excluded until
-// role enforcement is implemented. Closed is terminal — the upstream data
-// source rejects any outbound transition from a closed case, so no next
-// states are advertised for it here. The one exception is surfaced by the
-// caller (injectNextStates): `caseStateReopened` is reused as a signal that
-// this closed case is still within its related-case window, since the
-// frontend has no real reopen to offer, only "create a related case".
-func nextStates(state string) []string {
-	switch state {
-	case caseStateOpen:
-		return []string{caseStateWorkInProgress}
-	case caseStateWorkInProgress:
-		return []string{caseStateWaitingOnWSO2, caseStateAwaitingInfo, caseStateSolutionProposed, caseStateClosed}
-	case caseStateWaitingOnWSO2:
-		return []string{caseStateWorkInProgress}
-	case caseStateAwaitingInfo:
-		return []string{caseStateWaitingOnWSO2}
-	case caseStateSolutionProposed:
-		return []string{caseStateClosed, caseStateWaitingOnWSO2}
-	case caseStateClosed:
-		return []string{}
-	case caseStateReopened:
-		return []string{caseStateWorkInProgress}
-	default: // unknown values are terminal
-		return []string{}
-	}
-}
-
-// isValidStateTransition reports whether transitioning from the current state to
-// the requested state is permitted by the case state machine.
-func isValidStateTransition(from, to string) bool {
-	for _, s := range nextStates(from) {
-		if s == to {
-			return true
-		}
-	}
-	return false
-}
-
 // closedOnLayouts are the timestamp shapes the "closedOn" field has been seen
 // in, in order of preference. The entity service normally emits RFC 3339, but
 // some upstream (ServiceNow) values pass through as bare "YYYY-MM-DD HH:MM:SS"
@@ -137,14 +101,22 @@ func canCreateRelatedCase(caseType, state, closedOn, updatedOn string) bool {
 	return time.Since(t) <= relatedCaseWindow
 }
 
-// injectNextStates parses raw case JSON returned by the entity service,
-// derives the valid next states from "state", and returns the JSON with a
-// "nextStates" key appended. For a closed case still within its related-case
-// window (see canCreateRelatedCase), "reopened" is included as the sole
-// entry — there is no separate eligibility field; the frontend renders that
-// entry as "Create related case" rather than an actual reopen, since a real
-// reopen is never valid.
-func injectNextStates(data []byte) ([]byte, error) {
+// injectNextStates parses raw case JSON returned by the entity service and
+// returns it with two keys appended, both derived from "state" and "type"
+// through the active workflow:
+//
+//   - "nextStates": every state the case's lifecycle allows next, whoever
+//     asks. For a closed case still within its related-case window (see
+//     canCreateRelatedCase), "reopened" is the sole entry instead -- there is
+//     no separate eligibility field; the frontend renders that entry as
+//     "Create related case" rather than an actual reopen, since a real reopen
+//     is never valid.
+//   - "legalNextStates": the subset of those transitions caller may make
+//     under their role and team-family guards. It never carries the
+//     "reopened" signal. caller is only invoked when some transition out of
+//     the current state is guarded; a nil result leaves every guarded
+//     transition out.
+func injectNextStates(data []byte, caller func() *workflow.Caller) ([]byte, error) {
 	var m map[string]json.RawMessage
 	if err := json.Unmarshal(data, &m); err != nil {
 		return nil, err
@@ -158,7 +130,8 @@ func injectNextStates(data []byte) ([]byte, error) {
 	var caseType string
 	if raw, ok := m["type"]; ok {
 		// Best-effort: a null/absent type just leaves it empty, which
-		// canCreateRelatedCase treats as ineligible.
+		// canCreateRelatedCase treats as ineligible and the workflow treats
+		// as a standard case.
 		_ = json.Unmarshal(raw, &caseType)
 	}
 	var closedOn string
@@ -171,7 +144,9 @@ func injectNextStates(data []byte) ([]byte, error) {
 	if raw, ok := m["updatedOn"]; ok {
 		_ = json.Unmarshal(raw, &updatedOn)
 	}
-	ns := nextStates(state)
+
+	wf := workflow.Active()
+	ns := wf.NextStates(caseType, state)
 	if canCreateRelatedCase(caseType, state, closedOn, updatedOn) {
 		ns = []string{caseStateReopened}
 	}
@@ -180,5 +155,15 @@ func injectNextStates(data []byte) ([]byte, error) {
 		return nil, err
 	}
 	m["nextStates"] = nsJSON
+
+	var c *workflow.Caller
+	if wf.NeedsCaller(caseType, state) {
+		c = caller()
+	}
+	legalJSON, err := json.Marshal(wf.LegalNextStates(caseType, state, c))
+	if err != nil {
+		return nil, err
+	}
+	m["legalNextStates"] = legalJSON
 	return json.Marshal(m)
 }
@@ -0,0 +1,309 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package mentions
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/casenotify"
+)
+
+// chatTimeout bounds the Google Chat post about a comment's mentions.
+const chatTimeout = 10 * time.Second
+
+// maxChatTextRunes caps the comment text quoted in a Google Chat post.
+const maxChatTextRunes = 500
+
+// userTypeInternal is the userType of WSO2 staff in user search results. Only
+// they are sent the text of a work note.
+const userTypeInternal = "internal"
+
+// resolveTimeout bounds each user search a comment's mentions are resolved
+// with. It is short because the comment's response waits on it.
+const resolveTimeout = 5 * time.Second
+
+// Record types a mention can be made on, besides the case types.
+const (
+	RecordIncident      = "incident"
+	RecordChangeRequest = "change_request"
+)
+
+// portalPaths is where the portal webapp shows each record type. A case type
+// not listed is shown on the case page.
+var portalPaths = map[string]string{
+	"case":                     "/cases/",
+	"service_request":          "/operations/service-requests/",
+	"engagement":               "/engagements/",
+	"security_report_analysis": "/security-center/security-reports/",
+	"announcement":             "/announcements/",
+	RecordIncident:             "/operations/incidents/",
+	RecordChangeRequest:        "/operations/change-requests/",
+}
+
+// userSearcher abstracts the entity service's POST /users/search.
+type userSearcher interface {
+	SearchUsers(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// Notifier delivers a mention to the users it names (see
+// casenotify.Dispatcher).
+type Notifier interface {
+	Dispatch(ctx context.Context, e casenotify.Event)
+}
+
+// ChatNotifier posts a card into a thread of a Google Chat space (see
+// notifications.GoogleChatClient).
+type ChatNotifier interface {
+	HasSpace(space string) bool
+	SendThreadedAlert(ctx context.Context, space, threadKey, title, details, portalURL string) error
+}
+
+// Record is the case, incident or change request a comment was made on.
+type Record struct {
+	// Type is the case's type ("case", "service_request", ...), or
+	// RecordIncident or RecordChangeRequest.
+	Type    string
+	ID      string
+	Number  string
+	Subject string
+	// Product is the case's product name, which is also the name of its
+	// Google Chat space. Incidents and change requests have none.
+	Product string
+}
+
+// User is the platform user a mention resolved to.
+type User struct {
+	ID       string `json:"id"`
+	UserName string `json:"userName"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+
+	internal bool
+}
+
+// Mention is one handle mentioned in a comment, as returned with the comment.
+type Mention struct {
+	// Handle is the mention as written, without the "@".
+	Handle string `json:"handle"`
+	// User is nil when the handle matches no user, or could not be looked up.
+	User *User `json:"user"`
+}
+
+// Service resolves the mentions in new comments and notifies the users
+// mentioned.
+type Service struct {
+	users         userSearcher
+	notify        Notifier
+	chat          ChatNotifier
+	portalBaseURL string
+}
+
+// NewService creates a Service. A nil notify resolves mentions without
+// notifying anyone.
+func NewService(users userSearcher, notify Notifier, portalBaseURL string) *Service {
+	return &Service{users: users, notify: notify, portalBaseURL: strings.TrimRight(portalBaseURL, "/")}
+}
+
+// WithChat also posts each comment's mentions into the Google Chat space of
+// the case's product, in the case's thread -- the one chatalerts posts the
+// case's alerts into. A record with no product, or whose product has no
+// space, gets no post.
+func (s *Service) WithChat(chat ChatNotifier) *Service {
+	s.chat = chat
+	return s
+}
+
+// Process resolves the mentions in a comment the author has just added to
+// rec, notifies each user mentioned other than the author, and returns the
+// mentions for the comment's response. The text of a work note is emailed to
+// internal users only: anyone else mentioned in one is not notified, and its
+// Google Chat post leaves the text out. Failing to resolve or notify is
+// logged and never fails the comment: the comment has already been saved.
+func (s *Service) Process(ctx context.Context, rec Record, authorEmail, content string, workNote bool) []Mention {
+	handles := Parse(content)
+	if len(handles) == 0 {
+		return []Mention{}
+	}
+
+	// The author is looked up with the mentioned emails, for the name their
+	// notification is signed with.
+	var emails, userNames []string
+	if authorEmail != "" {
+		emails = append(emails, strings.ToLower(authorEmail))
+	}
+	for _, h := range handles {
+		if h.Email {
+			// Stored addresses are lowercase; "@Jane@Example.com" is still Jane.
+			emails = append(emails, strings.ToLower(h.Value))
+		} else {
+			userNames = append(userNames, h.Value)
+		}
+	}
+	// The entity service ANDs its filters, so emails and usernames are two
+	// searches.
+	byEmail := s.resolve(ctx, "emails", emails)
+	byUserName := s.resolve(ctx, "userNames", userNames)
+
+	out := make([]Mention, 0, len(handles))
+	var recipients, names []string
+	for _, h := range handles {
+		m := Mention{Handle: h.Value}
+		if h.Email {
+			m.User = byEmail[strings.ToLower(h.Value)]
+		} else {
+			m.User = byUserName[strings.ToLower(h.Value)]
+		}
+		if m.User != nil && !strings.EqualFold(m.User.Email, authorEmail) {
+			names = append(names, cmp.Or(m.User.Name, m.User.UserName, m.User.Email))
+		}
+		if m.User != nil && m.User.Email != "" && (!workNote || m.User.internal) {
+			recipients = append(recipients, m.User.Email)
+		}
+		out = append(out, m)
+	}
+
+	authorName := authorEmail
+	if u := byEmail[strings.ToLower(authorEmail)]; u != nil && u.Name != "" {
+		authorName = u.Name
+	}
+	if s.chat != nil && len(names) > 0 && rec.Product != "" && s.chat.HasSpace(rec.Product) && s.link(rec) != "" {
+		details := "Work note: open it in the CSM portal to read it."
+		if !workNote {
+			details = truncate(plainText(content), maxChatTextRunes)
+		}
+		title := fmt.Sprintf("%s mentioned %s on %s", authorName, strings.Join(names, ", "), cmp.Or(rec.Number, rec.ID))
+		// Like email, the response does not wait on the post.
+		go s.postChat(context.WithoutCancel(ctx), rec, title, details)
+	}
+	if s.notify != nil && len(recipients) > 0 {
+		e := casenotify.Event{
+			Type:       casenotify.EventMention,
+			CaseID:     rec.ID,
+			CaseNumber: rec.Number,
+			Subject:    rec.Subject,
+			Link:       s.link(rec),
+			Actor:      authorName,
+			ActorEmail: authorEmail,
+			Summary:    plainText(content),
+			At:         time.Now(),
+			Recipients: recipients,
+		}
+		// Sending email is slow and the response does not wait on it.
+		go s.notify.Dispatch(context.WithoutCancel(ctx), e)
+	}
+	return out
+}
+
+// postChat posts a mention card into rec's thread in its product's space.
+func (s *Service) postChat(ctx context.Context, rec Record, title, details string) {
+	ctx, cancel := context.WithTimeout(ctx, chatTimeout)
+	defer cancel()
+	if err := s.chat.SendThreadedAlert(ctx, rec.Product, rec.Type+"-"+rec.ID, title, details, s.link(rec)); err != nil {
+		slog.WarnContext(ctx, "mentions: google chat post failed", "recordType", rec.Type, "recordId", rec.ID, "err", err)
+	}
+}
+
+// resolve looks up the users whose field matches one of values, keyed by the
+// lowercased value. A failed search is logged and resolves nobody.
+func (s *Service) resolve(ctx context.Context, field string, values []string) map[string]*User {
+	found := make(map[string]*User)
+	if len(values) == 0 {
+		return found
+	}
+	body, err := json.Marshal(map[string]any{
+		"filters":    map[string]any{field: values},
+		"pagination": map[string]int{"offset": 0, "limit": len(values)},
+	})
+	if err != nil {
+		return found
+	}
+	searchCtx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	raw, err := s.users.SearchUsers(searchCtx, body)
+	if err != nil {
+		slog.WarnContext(ctx, "mentions: user search failed; mentions left unresolved", "filter", field, "err", err)
+		return found
+	}
+	var resp struct {
+		Users []struct {
+			ID        string `json:"id"`
+			UserName  string `json:"userName"`
+			Email     string `json:"email"`
+			Name      string `json:"name"`
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+			UserType  string `json:"userType"`
+		} `json:"users"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		slog.WarnContext(ctx, "mentions: user search response unreadable; mentions left unresolved", "filter", field, "err", err)
+		return found
+	}
+	for _, u := range resp.Users {
+		name := u.Name
+		if name == "" {
+			name = strings.TrimSpace(u.FirstName + " " + u.LastName)
+		}
+		user := &User{ID: u.ID, UserName: u.UserName, Name: name, Email: u.Email, internal: u.UserType == userTypeInternal}
+		key := u.UserName
+		if field == "emails" {
+			key = u.Email
+		}
+		found[strings.ToLower(key)] = user
+	}
+	return found
+}
+
+func (s *Service) link(rec Record) string {
+	if s.portalBaseURL == "" {
+		return ""
+	}
+	path, ok := portalPaths[rec.Type]
+	if !ok {
+		path = portalPaths["case"]
+	}
+	return s.portalBaseURL + path + url.PathEscape(rec.ID)
+}
+
+var (
+	tagRe   = regexp.MustCompile(`<[^>]*>`)
+	spaceRe = regexp.MustCompile(`\s+`)
+)
+
+// plainText is comment content, which the rich-text editor writes as HTML,
+// as the plain text quoted in a notification.
+func plainText(content string) string {
+	return strings.TrimSpace(spaceRe.ReplaceAllString(html.UnescapeString(tagRe.ReplaceAllString(content, " ")), " "))
+}
+
+// truncate cuts s to at most n runes, marking a cut with an ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
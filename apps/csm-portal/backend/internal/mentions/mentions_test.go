@@ -0,0 +1,209 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package mentions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/casenotify"
+)
+
+// fakeUsers answers user searches from a fixed directory, matching the
+// entity service's emails and userNames filters.
+type fakeUsers struct {
+	users []map[string]string
+	err   error
+}
+
+func (f *fakeUsers) SearchUsers(_ context.Context, body []byte) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var req struct {
+		Filters struct {
+			Emails    []string `json:"emails"`
+			UserNames []string `json:"userNames"`
+		} `json:"filters"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	var out []map[string]string
+	for _, u := range f.users {
+		if slices.Contains(req.Filters.Emails, u["email"]) || slices.Contains(req.Filters.UserNames, u["userName"]) {
+			out = append(out, u)
+		}
+	}
+	return json.Marshal(map[string]any{"users": out})
+}
+
+type chatPost struct{ space, threadKey, title, details, portalURL string }
+
+type fakeChat struct {
+	posts chan chatPost
+}
+
+func (f *fakeChat) HasSpace(space string) bool { return space == "API Manager" }
+
+func (f *fakeChat) SendThreadedAlert(_ context.Context, space, threadKey, title, details, portalURL string) error {
+	f.posts <- chatPost{space, threadKey, title, details, portalURL}
+	return nil
+}
+
+type fakeNotifier struct {
+	events chan casenotify.Event
+}
+
+func (f *fakeNotifier) Dispatch(_ context.Context, e casenotify.Event) { f.events <- e }
+
+func TestService_Process(t *testing.T) {
+	users := &fakeUsers{users: []map[string]string{
+		{"id": "u1", "userName": "jane", "email": "jane@example.com", "firstName": "Jane", "lastName": "Doe"},
+		{"id": "u2", "userName": "john.smith", "email": "john@example.com", "name": "John Smith"},
+		{"id": "u3", "userName": "agent", "email": "agent@example.com", "name": "Alex Agent"},
+	}}
+	notify := &fakeNotifier{events: make(chan casenotify.Event, 1)}
+	s := NewService(users, notify, "https://portal.example.com/")
+
+	rec := Record{Type: RecordIncident, ID: "inc-1", Number: "INC001", Subject: "Outage"}
+	got := s.Process(context.Background(), rec, "agent@example.com",
+		"<p>@jane and @John@Example.com, see @nobody &amp; @agent</p>", false)
+
+	want := []Mention{
+		{Handle: "jane", User: &User{ID: "u1", UserName: "jane", Name: "Jane Doe", Email: "jane@example.com"}},
+		{Handle: "John@Example.com", User: &User{ID: "u2", UserName: "john.smith", Name: "John Smith", Email: "john@example.com"}},
+		{Handle: "nobody"},
+		{Handle: "agent", User: &User{ID: "u3", UserName: "agent", Name: "Alex Agent", Email: "agent@example.com"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Process = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Handle != want[i].Handle || (got[i].User == nil) != (want[i].User == nil) ||
+			(got[i].User != nil && *got[i].User != *want[i].User) {
+			t.Errorf("mention %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	select {
+	case e := <-notify.events:
+		if e.Type != casenotify.EventMention || e.Link != "https://portal.example.com/operations/incidents/inc-1" ||
+			e.Actor != "Alex Agent" || e.ActorEmail != "agent@example.com" || e.CaseNumber != "INC001" {
+			t.Errorf("event = %+v", e)
+		}
+		// The author is a recipient here; the dispatcher drops them.
+		if !slices.Equal(e.Recipients, []string{"jane@example.com", "john@example.com", "agent@example.com"}) {
+			t.Errorf("recipients = %v", e.Recipients)
+		}
+		if e.Summary != "@jane and @John@Example.com, see @nobody & @agent" {
+			t.Errorf("summary = %q", e.Summary)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no mention was dispatched")
+	}
+}
+
+func TestService_ProcessWithoutMentionsOrUsers(t *testing.T) {
+	notify := &fakeNotifier{events: make(chan casenotify.Event, 1)}
+
+	s := NewService(&fakeUsers{err: errors.New("entity service down")}, notify, "")
+	if got := s.Process(context.Background(), Record{ID: "c1"}, "agent@example.com", "no mentions", false); got == nil || len(got) != 0 {
+		t.Errorf("Process without mentions = %#v, want an empty list", got)
+	}
+	got := s.Process(context.Background(), Record{ID: "c1"}, "agent@example.com", "@jane", false)
+	if len(got) != 1 || got[0].User != nil {
+		t.Errorf("Process with search down = %+v, want jane unresolved", got)
+	}
+	select {
+	case e := <-notify.events:
+		t.Errorf("dispatched %+v with nobody resolved", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestService_ProcessWorkNote(t *testing.T) {
+	users := &fakeUsers{users: []map[string]string{
+		{"id": "u1", "userName": "jane", "email": "jane@wso2.com", "name": "Jane Doe", "userType": "internal"},
+		{"id": "u2", "userName": "carol", "email": "carol@acme.example", "name": "Carol Customer", "userType": "customer"},
+		{"id": "u3", "userName": "agent", "email": "agent@wso2.com", "name": "Alex Agent", "userType": "internal"},
+	}}
+	notify := &fakeNotifier{events: make(chan casenotify.Event, 1)}
+	chat := &fakeChat{posts: make(chan chatPost, 1)}
+	s := NewService(users, notify, "https://portal.example.com").WithChat(chat)
+
+	rec := Record{Type: "case", ID: "c1", Number: "CS001", Subject: "Login fails", Product: "API Manager"}
+	s.Process(context.Background(), rec, "agent@wso2.com", "@jane @carol the customer's key leaked", true)
+
+	// Only internal users are emailed a work note.
+	select {
+	case e := <-notify.events:
+		if !slices.Equal(e.Recipients, []string{"jane@wso2.com"}) {
+			t.Errorf("recipients = %v, want only the internal user", e.Recipients)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no mention was dispatched")
+	}
+	// The chat post names everyone mentioned, in the case's thread, without
+	// the work note's text.
+	select {
+	case p := <-chat.posts:
+		want := chatPost{"API Manager", "case-c1", "Alex Agent mentioned Jane Doe, Carol Customer on CS001",
+			"Work note: open it in the CSM portal to read it.", "https://portal.example.com/cases/c1"}
+		if p != want {
+			t.Errorf("chat post = %+v, want %+v", p, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("nothing was posted to chat")
+	}
+}
+
+func TestService_ProcessChat(t *testing.T) {
+	users := &fakeUsers{users: []map[string]string{
+		{"id": "u1", "userName": "jane", "email": "jane@wso2.com", "name": "Jane Doe", "userType": "internal"},
+	}}
+	chat := &fakeChat{posts: make(chan chatPost, 1)}
+	s := NewService(users, nil, "https://portal.example.com").WithChat(chat)
+
+	// A customer-visible comment is quoted.
+	s.Process(context.Background(), Record{Type: "case", ID: "c1", Product: "API Manager"}, "agent@wso2.com", "<p>@jane can you check?</p>", false)
+	select {
+	case p := <-chat.posts:
+		if p.title != "agent@wso2.com mentioned Jane Doe on c1" || p.details != "@jane can you check?" {
+			t.Errorf("chat post = %+v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("nothing was posted to chat")
+	}
+
+	// No space for the product, or no product at all: no post.
+	for _, rec := range []Record{
+		{Type: "case", ID: "c2", Product: "Identity Server"},
+		{Type: RecordIncident, ID: "i1"},
+	} {
+		s.Process(context.Background(), rec, "agent@wso2.com", "@jane", false)
+	}
+	select {
+	case p := <-chat.posts:
+		t.Errorf("posted %+v to a record with no space", p)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package mentions finds "@user" mentions in comments, resolves them to
+// platform users, and notifies the users mentioned.
+package mentions
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxMentions caps how many distinct handles one comment can mention. Past it
+// the rest are ignored: a comment pasting a mailing list is not a way to
+// email everyone on it.
+const maxMentions = 20
+
+// codeSpanRe matches the spans a mention is ignored inside: markdown fenced
+// blocks and inline code, and the HTML the rich-text editor emits for code
+// (<pre> and <code>, with any attributes). An unclosed fence runs to the end.
+var codeSpanRe = regexp.MustCompile("(?is)```.*?(?:```|$)|`[^`\n]*`|<pre\\b[^>]*>.*?</pre\\s*>|<code\\b[^>]*>.*?</code\\s*>")
+
+// mentionRe matches "@" and a handle -- an email address or a username -- at
+// the start of the text or after a character that cannot be part of a word or
+// address, so "jane@example.com" written without a leading "@" is not a
+// mention.
+var mentionRe = regexp.MustCompile(`(?:^|[^\w.@/&])@([A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}|[A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// Handle is one mentioned user as written, without the "@".
+type Handle struct {
+	Value string
+	// Email reports whether Value is an email address rather than a username.
+	Email bool
+}
+
+// Parse returns the distinct handles mentioned in content, in the order they
+// first appear, ignoring any inside code. Handles compare case-insensitively,
+// and a username's trailing punctuation ("@jane.doe.") is not part of it.
+func Parse(content string) []Handle {
+	text := codeSpanRe.ReplaceAllString(content, " ")
+	var handles []Handle
+	seen := make(map[string]bool)
+	for _, m := range mentionRe.FindAllStringSubmatch(text, -1) {
+		value := m[1]
+		email := strings.Contains(value, "@")
+		if !email {
+			value = strings.TrimRight(value, "._-")
+		}
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		handles = append(handles, Handle{Value: value, Email: email})
+		if len(handles) == maxMentions {
+			break
+		}
+	}
+	return handles
+}
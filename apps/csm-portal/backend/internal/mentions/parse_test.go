@@ -0,0 +1,59 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package mentions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name, content string
+		want          []Handle
+	}{
+		{"none", "no mentions here", nil},
+		{"username", "thanks @jane.doe, looks good", []Handle{{Value: "jane.doe"}}},
+		{"email", "cc @jane@example.com please", []Handle{{Value: "jane@example.com", Email: true}}},
+		{"start of text and html", "<p>@john</p><p>and @jane.</p>", []Handle{{Value: "john"}, {Value: "jane"}}},
+		{"bare email is not a mention", "mail jane@example.com", nil},
+		{"url is not a mention", "see https://example.com/@jane and &#64;x", nil},
+		{"duplicates case-insensitively", "@Jane then @jane and @JANE", []Handle{{Value: "Jane"}}},
+		{"markdown code", "`@inline` and\n```\n@fenced\n```\nbut @real", []Handle{{Value: "real"}}},
+		{"unclosed fence", "@before ```\n@inside", []Handle{{Value: "before"}}},
+		{"html code", `<pre class="x">@pre</pre><code>@code</code> @out`, []Handle{{Value: "out"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Parse(tc.content); !slices.Equal(got, tc.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tc.content, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParse_CapsMentions(t *testing.T) {
+	var b strings.Builder
+	for i := range maxMentions + 5 {
+		fmt.Fprintf(&b, "@user%d ", i)
+	}
+	if got := Parse(b.String()); len(got) != maxMentions {
+		t.Errorf("Parse returned %d handles, want %d", len(got), maxMentions)
+	}
+}
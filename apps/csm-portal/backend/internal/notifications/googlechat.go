@@ -109,8 +109,19 @@ func redactURLError(err error) error {
 // single card message: https://developers.google.com/chat/api/guides/message-formats/cards
 type chatCardMessage struct {
 	CardsV2 []chatCardWrapper `json:"cardsV2"`
+	// Thread, when set, posts the message as a reply in the thread with that
+	// key (see threadReplyOption).
+	Thread *chatThread `json:"thread,omitempty"`
 }
 
+type chatThread struct {
+	ThreadKey string `json:"threadKey"`
+}
+
+// threadReplyOption makes a threaded post start the thread when no message
+// with its key exists yet, instead of failing.
+const threadReplyOption = "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD"
+
 type chatCardWrapper struct {
 	CardID string   `json:"cardId"`
 	Card   chatCard `json:"card"`
@@ -162,6 +173,38 @@ type chatOpenLink struct {
 // incident/case, with a button linking back to the case in the CSM portal,
 // to the Google Chat space configured for the given product.
 func (c *GoogleChatClient) SendIncidentAlert(ctx context.Context, product, title, shortDescription, portalURL string) error {
+	return c.sendLinkCard(ctx, product, "incident-alert", "", title, "Short Description", shortDescription, portalURL)
+}
+
+// SendDashboardAlert posts a card message announcing that a dashboard
+// widget's threshold was crossed or recovered, with a button linking back to
+// the dashboard in the CSM portal, to the Google Chat space configured for
+// the given product.
+func (c *GoogleChatClient) SendDashboardAlert(ctx context.Context, product, title, details, portalURL string) error {
+	return c.sendLinkCard(ctx, product, "dashboard-alert", "", title, "Details", details, portalURL)
+}
+
+// HasSpace reports whether a usable webhook is configured for space (a
+// GoogleChatSpace.Product value), so callers that route by space name can
+// reject an unknown one at startup rather than at the first send.
+func (c *GoogleChatClient) HasSpace(space string) bool {
+	return c.webhookURLsByProduct[normalizeProduct(space)] != ""
+}
+
+// SendThreadedAlert posts an incident-alert card to space as a reply in the
+// thread keyed threadKey, starting that thread if it does not exist yet, so
+// every alert about one record lands in one thread.
+func (c *GoogleChatClient) SendThreadedAlert(ctx context.Context, space, threadKey, title, details, portalURL string) error {
+	if threadKey == "" {
+		return fmt.Errorf("notifications: thread key is required")
+	}
+	return c.sendLinkCard(ctx, space, "incident-alert", threadKey, title, "Details", details, portalURL)
+}
+
+// sendLinkCard posts a single-section card with an "Open in CSM Portal"
+// button to the space configured for product, threaded under threadKey
+// unless it is empty.
+func (c *GoogleChatClient) sendLinkCard(ctx context.Context, product, cardID, threadKey, title, sectionHeader, text, portalURL string) error {
 	if title == "" {
 		return fmt.Errorf("notifications: title is required")
 	}
@@ -173,14 +216,14 @@ func (c *GoogleChatClient) SendIncidentAlert(ctx context.Context, product, title
 	msg := chatCardMessage{
 		CardsV2: []chatCardWrapper{
 			{
-				CardID: "incident-alert",
+				CardID: cardID,
 				Card: chatCard{
 					Header: chatCardHeader{Title: title},
 					Sections: []chatCardSection{
 						{
-							Header: "Short Description",
+							Header: sectionHeader,
 							Widgets: []chatCardWidget{
-								{TextParagraph: &chatTextParagraph{Text: shortDescription}},
+								{TextParagraph: &chatTextParagraph{Text: text}},
 							},
 						},
 						{
@@ -203,6 +246,18 @@ func (c *GoogleChatClient) SendIncidentAlert(ctx context.Context, product, title
 		},
 	}
 
+	if threadKey != "" {
+		msg.Thread = &chatThread{ThreadKey: threadKey}
+		u, err := url.Parse(webhookURL)
+		if err != nil {
+			return fmt.Errorf("notifications: parse google chat webhook url: %w", redactURLError(err))
+		}
+		q := u.Query()
+		q.Set("messageReplyOption", threadReplyOption)
+		u.RawQuery = q.Encode()
+		webhookURL = u.String()
+	}
+
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("notifications: encode google chat message: %w", err)
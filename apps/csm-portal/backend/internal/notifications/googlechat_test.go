@@ -195,3 +195,68 @@ func TestSendIncidentAlert_ConstructsWithZeroValueConfig(t *testing.T) {
 		t.Fatal("NewGoogleChatClient returned nil for zero-value GoogleChatConfig")
 	}
 }
+
+func TestSendDashboardAlert_SendsExpectedCard(t *testing.T) {
+	var capturedBody chatCardMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&capturedBody); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := NewGoogleChatClient(GoogleChatConfig{Spaces: []GoogleChatSpace{{Product: "support-ops", WebhookURL: srv.URL}}})
+
+	err := c.SendDashboardAlert(context.Background(), "support-ops", "[CRITICAL] Unacknowledged S0 cases", "Now 3 (critical above 0).", "https://portal.example.com/dashboard/cs-overview")
+	if err != nil {
+		t.Fatalf("SendDashboardAlert returned error: %v", err)
+	}
+
+	wrapper := capturedBody.CardsV2[0]
+	if wrapper.CardID != "dashboard-alert" {
+		t.Errorf("CardID = %q, want %q", wrapper.CardID, "dashboard-alert")
+	}
+	if got := wrapper.Card.Sections[0].Widgets[0].TextParagraph.Text; got != "Now 3 (critical above 0)." {
+		t.Errorf("details text = %q", got)
+	}
+	if got := wrapper.Card.Sections[1].Widgets[0].ButtonList.Buttons[0].OnClick.OpenLink.URL; got != "https://portal.example.com/dashboard/cs-overview" {
+		t.Errorf("button URL = %q", got)
+	}
+}
+
+func TestSendThreadedAlert_ThreadsByKey(t *testing.T) {
+	var capturedBody chatCardMessage
+	var capturedQuery map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedQuery = r.URL.Query()
+		if err := json.NewDecoder(r.Body).Decode(&capturedBody); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := NewGoogleChatClient(GoogleChatConfig{Spaces: []GoogleChatSpace{{Product: "support-ops", WebhookURL: srv.URL + "/messages?key=K&token=T"}}})
+	if !c.HasSpace(" Support-Ops ") || c.HasSpace("other") {
+		t.Error("HasSpace should match configured spaces only, case-insensitively")
+	}
+
+	if err := c.SendThreadedAlert(context.Background(), "support-ops", "", "title", "details", "https://example.com"); err == nil {
+		t.Fatal("expected error for an empty thread key, got nil")
+	}
+	err := c.SendThreadedAlert(context.Background(), "support-ops", "case-123", "title", "details", "https://example.com")
+	if err != nil {
+		t.Fatalf("SendThreadedAlert returned error: %v", err)
+	}
+	if capturedBody.Thread == nil || capturedBody.Thread.ThreadKey != "case-123" {
+		t.Errorf("thread = %+v, want threadKey case-123", capturedBody.Thread)
+	}
+	if got := capturedQuery["messageReplyOption"]; len(got) != 1 || got[0] != threadReplyOption {
+		t.Errorf("messageReplyOption = %v, want %s", got, threadReplyOption)
+	}
+	// The webhook's own credentials must survive the added parameter.
+	if capturedQuery["key"][0] != "K" || capturedQuery["token"][0] != "T" {
+		t.Errorf("webhook credentials lost: %v", capturedQuery)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reports
+
+// mergeQueries merges a pie/bar slice's query under its widget's base query,
+// a port of the frontend's mergeWidgetFilters (see
+// apps/csm-portal/webapp/src/features/csm-dashboard/utils/widgetFilterMerge.ts)
+// so a report's slice counts match the dashboard's: top-level keys are
+// slice-wins, the "filters" arrays merge by field (slice-wins), and two
+// "anyOf" branch sets are ANDed by distributing every base branch over every
+// slice branch.
+func mergeQueries(base, slice map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(slice))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range slice {
+		merged[k] = v
+	}
+
+	baseFilters, okBase := filterArray(base["filters"])
+	sliceFilters, okSlice := filterArray(slice["filters"])
+	if okBase && okSlice {
+		merged["filters"] = mergeFilterArrays(baseFilters, sliceFilters)
+	}
+
+	baseBranches, okBase := branchArray(base["anyOf"])
+	sliceBranches, okSlice := branchArray(slice["anyOf"])
+	if okBase && okSlice {
+		var out []any
+		for _, b := range baseBranches {
+			for _, s := range sliceBranches {
+				branch := make(map[string]any, len(b)+len(s))
+				for k, v := range b {
+					branch[k] = v
+				}
+				for k, v := range s {
+					branch[k] = v
+				}
+				bf, _ := filterArray(b["filters"])
+				sf, _ := filterArray(s["filters"])
+				branch["filters"] = mergeFilterArrays(bf, sf)
+				out = append(out, branch)
+			}
+		}
+		merged["anyOf"] = out
+	}
+	return merged
+}
+
+// filterArray reports whether v is a (possibly empty) array of
+// {"field": ...} filter objects.
+func filterArray(v any) ([]map[string]any, bool) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	out := make([]map[string]any, 0, len(arr))
+	for _, e := range arr {
+		f, ok := e.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if _, ok := f["field"].(string); !ok {
+			return nil, false
+		}
+		out = append(out, f)
+	}
+	return out, true
+}
+
+// branchArray reports whether v is a non-empty array of {"filters": [...]}
+// anyOf branches.
+func branchArray(v any) ([]map[string]any, bool) {
+	arr, ok := v.([]any)
+	if !ok || len(arr) == 0 {
+		return nil, false
+	}
+	out := make([]map[string]any, 0, len(arr))
+	for _, e := range arr {
+		b, ok := e.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if _, ok := filterArray(b["filters"]); !ok {
+			return nil, false
+		}
+		out = append(out, b)
+	}
+	return out, true
+}
+
+func mergeFilterArrays(base, slice []map[string]any) []any {
+	sliceFields := make(map[string]bool, len(slice))
+	for _, f := range slice {
+		sliceFields[f["field"].(string)] = true
+	}
+	out := make([]any, 0, len(base)+len(slice))
+	for _, f := range base {
+		if !sliceFields[f["field"].(string)] {
+			out = append(out, f)
+		}
+	}
+	for _, f := range slice {
+		out = append(out, f)
+	}
+	return out
+}
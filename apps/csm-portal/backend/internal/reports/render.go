@@ -0,0 +1,233 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// emptyCell is what an empty table cell renders as in HTML, matching the
+// frontend's own em-dash convention for a missing column value.
+const emptyCell = "—"
+
+// reportTemplate is the email body. Inline styles only: most mail clients
+// drop <style> blocks.
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html><body style="font-family:Arial,Helvetica,sans-serif;color:#222;">
+<h2 style="margin-bottom:4px;">{{.Title}}</h2>
+<p style="color:#666;margin-top:0;">Generated {{.GeneratedAt}}{{if .DashboardURL}} &middot; <a href="{{.DashboardURL}}">Open in CSM Portal</a>{{end}}</p>
+{{range .Sections}}
+{{if .Name}}<h3 style="border-bottom:1px solid #ddd;padding-bottom:4px;">{{.Name}}</h3>{{end}}
+{{if .Tiles}}<table cellpadding="8" style="border-collapse:separate;border-spacing:8px;"><tr>
+{{range .Tiles}}<td style="border:1px solid #ddd;border-radius:4px;min-width:120px;vertical-align:top;">
+<div style="color:#666;font-size:12px;">{{.Name}}</div>
+{{if .Problem}}<div style="color:#b00;font-size:12px;">{{.Problem}}</div>{{else}}<div style="font-size:24px;font-weight:bold;">{{.Value}}</div>{{end}}
+</td>{{end}}
+</tr></table>{{end}}
+{{range .Tables}}
+<h4 style="margin-bottom:4px;">{{.Name}}</h4>
+{{if .Description}}<p style="color:#666;margin-top:0;font-size:12px;">{{.Description}}</p>{{end}}
+{{if .Problem}}<p style="color:#b00;font-size:12px;">{{.Problem}}</p>
+{{else}}<table cellpadding="4" style="border-collapse:collapse;font-size:13px;">
+<tr>{{range .Header}}<th style="border-bottom:2px solid #ccc;text-align:left;">{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td style="border-bottom:1px solid #eee;">{{.}}</td>{{end}}</tr>
+{{else}}<tr><td colspan="{{len .Header}}" style="color:#666;">No matching records.</td></tr>{{end}}
+</table>
+{{if .Footer}}<p style="color:#666;font-size:12px;">{{.Footer}}</p>{{end}}
+{{end}}
+{{end}}
+{{end}}
+</body></html>`))
+
+type reportView struct {
+	Title        string
+	GeneratedAt  string
+	DashboardURL string
+	Sections     []sectionView
+}
+
+type sectionView struct {
+	Name   string
+	Tiles  []tileView
+	Tables []tableView
+}
+
+type tileView struct {
+	Name    string
+	Value   string
+	Problem string
+}
+
+type tableView struct {
+	Name        string
+	Description string
+	Header      []string
+	Rows        [][]string
+	Footer      string
+	Problem     string
+}
+
+// problem is the one-line explanation shown in place of a widget's data, or
+// "" if it resolved.
+func (r widgetResult) problem() string {
+	switch {
+	case r.note != "":
+		return r.note
+	case r.err != nil:
+		return "Could not be resolved for this report."
+	}
+	return ""
+}
+
+// renderHTML renders the report body: count widgets as tiles, every other
+// widget as a table, grouped by Section in the order each section first
+// appears -- the same grouping the dashboard itself uses.
+func renderHTML(d dashboard.Dashboard, results []widgetResult, generatedAt time.Time, dashboardURL string) (string, error) {
+	view := reportView{
+		Title:        d.DisplayName,
+		GeneratedAt:  generatedAt.Format("Mon, 2 Jan 2006 15:04 MST"),
+		DashboardURL: dashboardURL,
+	}
+	index := map[string]int{}
+	for _, r := range results {
+		i, ok := index[r.widget.Section]
+		if !ok {
+			i = len(view.Sections)
+			index[r.widget.Section] = i
+			view.Sections = append(view.Sections, sectionView{Name: r.widget.Section})
+		}
+		s := &view.Sections[i]
+		if r.widget.Shape == dashboard.ShapeCount {
+			s.Tiles = append(s.Tiles, tileView{Name: r.widget.DisplayName, Value: formatNumber(r.count), Problem: r.problem()})
+			continue
+		}
+		t := tableView{Name: r.widget.DisplayName, Description: r.widget.Description, Problem: r.problem()}
+		if t.Problem == "" {
+			t.Header = r.header
+			for _, row := range r.rows {
+				cells := make([]string, len(row))
+				for i, cell := range row {
+					cells[i] = formatCell(cell, r.formats[i])
+				}
+				t.Rows = append(t.Rows, cells)
+			}
+			if r.widget.Shape == dashboard.ShapeList && int(r.count) > len(r.rows) {
+				t.Footer = fmt.Sprintf("Showing %d of %s.", len(r.rows), formatNumber(r.count))
+			}
+		}
+		s.Tables = append(s.Tables, t)
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, view); err != nil {
+		return "", fmt.Errorf("render report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// backendDateLayouts are the timestamp shapes entity-service responses carry.
+var backendDateLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// formatCell applies a Column.Format to a raw cell, as the frontend's
+// formatColumnValue does: "date" renders a parseable date as "Jan 2, 2006"
+// and anything else empty; every other format is plain text.
+func formatCell(raw, format string) string {
+	if raw == "" {
+		return emptyCell
+	}
+	if format != "date" {
+		return raw
+	}
+	for _, layout := range backendDateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.Format("Jan 2, 2006")
+		}
+	}
+	return emptyCell
+}
+
+// renderAttachments builds the CSV attachments: one per resolved table
+// widget, plus one "counts.csv" of every resolved count tile. Cells are the
+// raw values, so a date column stays machine-readable.
+func renderAttachments(results []widgetResult) ([]notifications.EmailAttachment, error) {
+	var attachments []notifications.EmailAttachment
+	var counts [][]string
+	for _, r := range results {
+		if r.problem() != "" {
+			continue
+		}
+		if r.widget.Shape == dashboard.ShapeCount {
+			counts = append(counts, []string{r.widget.DisplayName, formatNumber(r.count)})
+			continue
+		}
+		data, err := encodeCSV(r.header, r.rows)
+		if err != nil {
+			return nil, fmt.Errorf("widget %q: %w", r.widget.ID, err)
+		}
+		attachments = append(attachments, csvAttachment(r.widget.ID+".csv", data))
+	}
+	if len(counts) > 0 {
+		data, err := encodeCSV([]string{"Widget", "Count"}, counts)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append([]notifications.EmailAttachment{csvAttachment("counts.csv", data)}, attachments...)
+	}
+	return attachments, nil
+}
+
+func encodeCSV(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("encode csv: %w", err)
+	}
+	for _, row := range rows {
+		// A leading =, +, - or @ makes spreadsheet apps evaluate the cell as
+		// a formula; record text comes from customers, so neutralise it.
+		safe := make([]string, len(row))
+		for i, cell := range row {
+			if cell != "" && strings.ContainsRune("=+-@", rune(cell[0])) {
+				if _, err := strconv.ParseFloat(cell, 64); err != nil {
+					cell = "'" + cell
+				}
+			}
+			safe[i] = cell
+		}
+		if err := w.Write(safe); err != nil {
+			return nil, fmt.Errorf("encode csv: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("encode csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func csvAttachment(name string, data []byte) notifications.EmailAttachment {
+	return notifications.EmailAttachment{ContentName: name, ContentType: "text/csv", Attachment: data}
+}
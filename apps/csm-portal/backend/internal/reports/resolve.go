@@ -0,0 +1,258 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/widgetquery"
+)
+
+// defaultListLimit matches the frontend's own DEFAULT_LIST_LIMIT, so a list
+// widget without a listLimit shows the same rows in the report as on screen.
+const defaultListLimit = 4
+
+// entityClient is the subset of entity.CustomerEntityClient a report resolves
+// widgets with.
+type entityClient = widgetquery.Aggregator
+
+// widgetResult is one widget as it appears in a report. Exactly one of note
+// and err explains a widget with no data; otherwise a count widget carries
+// count and every other shape carries a table.
+type widgetResult struct {
+	widget dashboard.WidgetTemplate
+	count  float64
+	// header, formats and rows are the widget's table: a pie/bar widget's
+	// label/count pairs or a list widget's configured Columns. Cells hold the
+	// raw value (what the CSV attachment gets); formats is each column's
+	// Column.Format, applied only when rendering HTML.
+	header  []string
+	formats []string
+	rows    [][]string
+	note    string
+	err     error
+}
+
+// resolveWidget resolves one widget the way the frontend does, with the
+// service's own credentials. A failure is recorded on the result rather than
+// returned: one broken widget must not cost the recipients the whole report.
+func resolveWidget(ctx context.Context, c entityClient, w dashboard.WidgetTemplate) widgetResult {
+	res := widgetResult{widget: w}
+	if p := userScopedPlaceholder(w); p != "" {
+		res.note = fmt.Sprintf("Depends on the viewer (%s), so it cannot be included in a scheduled report.", p)
+		return res
+	}
+	switch w.Shape {
+	case dashboard.ShapeMetrics, dashboard.ShapeLine, dashboard.ShapeArea:
+		res.note = "Charts are not included in emailed reports; open the dashboard to view it."
+		return res
+	case dashboard.ShapeList:
+		if len(w.Columns) == 0 {
+			res.note = "This list has no configured columns, so it has nothing to tabulate; open the dashboard to view it."
+			return res
+		}
+	}
+
+	r, err := widgetquery.For(c, w.ResourceType)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	switch w.Shape {
+	case dashboard.ShapeCount:
+		res.count, res.err = count(ctx, r, w.Query)
+	case dashboard.ShapeList:
+		res.err = resolveList(ctx, r, w, &res)
+	case dashboard.ShapePie, dashboard.ShapeBar:
+		res.header, res.formats = []string{"Label", "Count"}, []string{"", ""}
+		if w.GroupBy != nil {
+			res.err = resolveGroupBy(ctx, r, w, &res)
+		} else {
+			res.err = resolveSlices(ctx, r, w, &res)
+		}
+	default:
+		res.err = fmt.Errorf("shape %q is not supported in reports", w.Shape)
+	}
+	return res
+}
+
+// userScopedPlaceholder returns the first "__current_user__"/
+// "__current_team__"/"__current_oncall__" placeholder anywhere in w's
+// criteria, or "".
+func userScopedPlaceholder(w dashboard.WidgetTemplate) string {
+	if p := dashboard.FindUserScopedPlaceholder(w.Query); p != "" {
+		return p
+	}
+	for _, s := range w.Slices {
+		if p := dashboard.FindUserScopedPlaceholder(s.Query); p != "" {
+			return p
+		}
+	}
+	return ""
+}
+
+func post(ctx context.Context, call widgetquery.Func, payload map[string]any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+	return widgetquery.Call(ctx, call, payload)
+}
+
+func count(ctx context.Context, r widgetquery.Resource, query map[string]any) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+	return widgetquery.Count(ctx, r, query)
+}
+
+func resolveList(ctx context.Context, r widgetquery.Resource, w dashboard.WidgetTemplate, res *widgetResult) error {
+	limit := w.ListLimit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	payload := widgetquery.SearchPayload(w.Query, limit)
+	if w.SortBy != nil {
+		payload["sortBy"] = w.SortBy
+	}
+	raw, err := post(ctx, r.Search, payload)
+	if err != nil {
+		return err
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("decode search response: %w", err)
+	}
+	if t, err := widgetquery.Total(raw); err == nil {
+		res.count = t
+	}
+	for _, col := range w.Columns {
+		res.header = append(res.header, col.Label)
+		res.formats = append(res.formats, col.Format)
+	}
+	items, _ := resp[r.ItemsKey].([]any)
+	for _, item := range items {
+		obj, _ := item.(map[string]any)
+		row := make([]string, len(w.Columns))
+		for i, col := range w.Columns {
+			row[i] = scalarString(resolvePath(obj, col.Path))
+		}
+		res.rows = append(res.rows, row)
+	}
+	return nil
+}
+
+func resolveSlices(ctx context.Context, r widgetquery.Resource, w dashboard.WidgetTemplate, res *widgetResult) error {
+	for _, s := range w.Slices {
+		n, err := count(ctx, r, mergeQueries(w.Query, s.Query))
+		if err != nil {
+			return fmt.Errorf("slice %q: %w", s.Label, err)
+		}
+		res.count += n
+		res.rows = append(res.rows, []string{s.Label, formatNumber(n)})
+	}
+	return nil
+}
+
+func resolveGroupBy(ctx context.Context, r widgetquery.Resource, w dashboard.WidgetTemplate, res *widgetResult) error {
+	if r.Aggregate == nil {
+		return fmt.Errorf("resourceType %q has no group-by endpoint", w.ResourceType)
+	}
+	query := w.Query
+	if query == nil {
+		query = map[string]any{}
+	}
+	payload := map[string]any{"filters": query, "groupBy": w.GroupBy.Field}
+	if w.GroupBy.MaxGroups > 0 {
+		payload["maxGroups"] = w.GroupBy.MaxGroups
+	}
+	raw, err := post(ctx, r.Aggregate, payload)
+	if err != nil {
+		return err
+	}
+	var parsed struct {
+		Groups []struct {
+			Key   string  `json:"key"`
+			Label string  `json:"label"`
+			Count float64 `json:"count"`
+		} `json:"groups"`
+		OthersCount float64 `json:"othersCount"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return fmt.Errorf("decode group-by response: %w", err)
+	}
+	for _, g := range parsed.Groups {
+		label := g.Label
+		if label == "" {
+			label = g.Key
+		}
+		res.count += g.Count
+		res.rows = append(res.rows, []string{label, formatNumber(g.Count)})
+	}
+	if parsed.OthersCount > 0 {
+		label := w.GroupBy.OthersLabel
+		if label == "" {
+			label = "Others"
+		}
+		res.count += parsed.OthersCount
+		res.rows = append(res.rows, []string{label, formatNumber(parsed.OthersCount)})
+	}
+	return nil
+}
+
+// resolvePath walks a dot-separated Column.Path into a decoded search item,
+// exactly as the frontend's resolveColumnPath does: nil for anything that
+// does not resolve.
+func resolvePath(item map[string]any, path string) any {
+	var cur any = item
+	start := 0
+	for i := 0; i <= len(path); i++ {
+		if i < len(path) && path[i] != '.' {
+			continue
+		}
+		seg := path[start:i]
+		start = i + 1
+		if seg == "" {
+			continue
+		}
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = obj[seg]
+	}
+	return cur
+}
+
+// scalarString renders a resolved column value; like the frontend's
+// formatColumnValue, anything that is not a scalar renders empty.
+func scalarString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return formatNumber(x)
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	return ""
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
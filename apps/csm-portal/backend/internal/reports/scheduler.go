@@ -0,0 +1,259 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package reports mails dashboards out as snapshot reports. A dashboard opts
+// in with a "schedule" block (see dashboard.ReportSchedule); the Scheduler
+// resolves every widget at each fire time with the portal's own entity
+// credentials, renders the result as an HTML email with CSV attachments, and
+// sends it through the email notification channel.
+//
+// Schedules are evaluated in-process, so every replica running the scheduler
+// sends its own copy; run it on one.
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// searchTimeout bounds each entity call a report makes.
+const searchTimeout = 30 * time.Second
+
+// tickInterval is how often the Scheduler checks for due schedules: cron's
+// own resolution.
+const tickInterval = time.Minute
+
+var (
+	// ErrUnknownDashboard is returned by SendNow for an id no dashboard has.
+	ErrUnknownDashboard = errors.New("reports: unknown dashboard")
+	// ErrNoSchedule is returned by SendNow for a dashboard with no
+	// "schedule" block, and so no recipients.
+	ErrNoSchedule = errors.New("reports: dashboard has no report schedule")
+	// ErrEmailUnavailable is returned by SendNow when the email channel is
+	// not configured.
+	ErrEmailUnavailable = errors.New("reports: email channel is not configured")
+)
+
+// EmailNotifier is the email channel (see notifications.EmailClient).
+type EmailNotifier interface {
+	SendEmail(ctx context.Context, to, cc, bcc, replyTo []string, subject, htmlBody string, attachments []notifications.EmailAttachment) error
+}
+
+// Config holds the Scheduler's settings.
+type Config struct {
+	// PortalBaseURL is the CSM portal webapp's base URL, used for the
+	// report's "Open in CSM Portal" link. Optional; the link is omitted
+	// without it.
+	PortalBaseURL string
+	// OnCall returns the email of whoever is on call for a team at a time,
+	// for "__current_oncall__:<teamKey>" recipients. Nil resolves none.
+	OnCall func(teamKey string, at time.Time) (string, bool)
+}
+
+// trigger is what started a report run.
+type trigger string
+
+const (
+	triggerSchedule trigger = "schedule"
+	triggerManual   trigger = "manual"
+)
+
+// runRecord is the record of one report run, logged when it finishes.
+type runRecord struct {
+	DashboardID  string
+	Trigger      trigger
+	StartedAt    time.Time
+	FinishedAt   time.Time
+	Recipients   int
+	Widgets      int
+	Skipped      int // widgets left out with a note, e.g. user-scoped ones
+	WidgetErrors int // widgets that failed to resolve
+	Attachments  int
+	Err          error // nil when the email was sent
+}
+
+// Scheduler sends scheduled dashboard reports, and sends one on demand.
+type Scheduler struct {
+	entity     entityClient
+	email      EmailNotifier
+	dashboards func() []dashboard.Dashboard
+	cfg        Config
+	now        func() time.Time
+
+	// mu serialises runs, so a manual send racing a scheduled one for the
+	// same dashboard cannot interleave two sets of entity calls.
+	mu sync.Mutex
+}
+
+// NewScheduler creates a Scheduler. dashboards is re-read on every tick so a
+// hot-reloaded definition is picked up. email may be nil when the channel is
+// not configured; Run then never sends and SendNow reports
+// ErrEmailUnavailable.
+func NewScheduler(entity entityClient, email EmailNotifier, dashboards func() []dashboard.Dashboard, cfg Config) *Scheduler {
+	return &Scheduler{
+		entity:     entity,
+		email:      email,
+		dashboards: dashboards,
+		cfg:        Config{PortalBaseURL: strings.TrimRight(cfg.PortalBaseURL, "/"), OnCall: cfg.OnCall},
+		now:        time.Now,
+	}
+}
+
+// Run checks for due schedules once a minute until ctx is cancelled. Fire
+// times that passed while the process was down are not caught up on: a
+// Monday-morning report sent on Tuesday afternoon is noise.
+func (s *Scheduler) Run(ctx context.Context) {
+	if s.email == nil {
+		return
+	}
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	last := s.now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now := s.now()
+		s.runDue(ctx, last, now)
+		last = now
+	}
+}
+
+// runDue sends every dashboard whose schedule fires in (from, to].
+func (s *Scheduler) runDue(ctx context.Context, from, to time.Time) {
+	for _, d := range s.dashboards() {
+		if d.Schedule == nil {
+			continue
+		}
+		next := d.Schedule.Next(from)
+		if next.IsZero() || next.After(to) {
+			continue
+		}
+		s.send(ctx, d, triggerSchedule)
+	}
+}
+
+// SendNow queues dashboardID's report for its scheduled recipients
+// immediately, outside its schedule. Only the checks that make the request
+// itself wrong are synchronous; the run happens in the background, since
+// resolving a large dashboard can outlast the caller's HTTP write timeout,
+// and its outcome is logged like a scheduled run's.
+func (s *Scheduler) SendNow(ctx context.Context, dashboardID string) error {
+	if s.email == nil {
+		return ErrEmailUnavailable
+	}
+	for _, d := range s.dashboards() {
+		if d.ID != dashboardID {
+			continue
+		}
+		if d.Schedule == nil {
+			return ErrNoSchedule
+		}
+		// WithoutCancel keeps ctx's values (the correlation id, for the run's
+		// log lines) but not its deadline: the request ends before the run.
+		go s.send(context.WithoutCancel(ctx), d, triggerManual)
+		return nil
+	}
+	return ErrUnknownDashboard
+}
+
+// send resolves, renders and mails one report, and logs the run.
+func (s *Scheduler) send(ctx context.Context, d dashboard.Dashboard, trigger trigger) runRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := runRecord{DashboardID: d.ID, Trigger: trigger, StartedAt: s.now(), Recipients: len(d.Schedule.Recipients)}
+	rec.Err = s.deliver(ctx, d, &rec)
+	rec.FinishedAt = s.now()
+
+	attrs := []any{
+		"dashboardId", rec.DashboardID, "trigger", string(rec.Trigger),
+		"recipients", rec.Recipients, "widgets", rec.Widgets, "skipped", rec.Skipped,
+		"widgetErrors", rec.WidgetErrors, "attachments", rec.Attachments,
+		"durationMs", rec.FinishedAt.Sub(rec.StartedAt).Milliseconds(),
+	}
+	if rec.Err != nil {
+		slog.ErrorContext(ctx, "dashboard report failed", append(attrs, "err", rec.Err)...)
+	} else {
+		slog.InfoContext(ctx, "dashboard report sent", attrs...)
+	}
+	return rec
+}
+
+func (s *Scheduler) deliver(ctx context.Context, d dashboard.Dashboard, rec *runRecord) error {
+	loc, err := d.Schedule.Location()
+	if err != nil {
+		return fmt.Errorf("schedule timezone: %w", err)
+	}
+
+	results := make([]widgetResult, 0, len(d.Widgets))
+	for _, w := range d.Widgets {
+		r := resolveWidget(ctx, s.entity, w)
+		switch {
+		case r.note != "":
+			rec.Skipped++
+		case r.err != nil:
+			rec.WidgetErrors++
+			slog.WarnContext(ctx, "dashboard report: widget failed to resolve",
+				"dashboardId", d.ID, "widgetId", w.ID, "resourceType", w.ResourceType, "err", r.err)
+		}
+		results = append(results, r)
+	}
+	rec.Widgets = len(results)
+
+	var dashboardURL string
+	if s.cfg.PortalBaseURL != "" {
+		dashboardURL = s.cfg.PortalBaseURL + "/dashboard/" + url.PathEscape(d.ID)
+	}
+	body, err := renderHTML(d, results, rec.StartedAt.In(loc), dashboardURL)
+	if err != nil {
+		return err
+	}
+	attachments, err := renderAttachments(results)
+	if err != nil {
+		return err
+	}
+	rec.Attachments = len(attachments)
+
+	subject := d.Schedule.Subject
+	if subject == "" {
+		subject = d.DisplayName + " report"
+	}
+	subject += " — " + rec.StartedAt.In(loc).Format("2 Jan 2006")
+	var onCall func(string) (string, bool)
+	if s.cfg.OnCall != nil {
+		onCall = func(teamKey string) (string, bool) { return s.cfg.OnCall(teamKey, rec.StartedAt) }
+	}
+	recipients, unresolved := dashboard.ExpandRecipients(d.Schedule.Recipients, onCall)
+	if len(unresolved) > 0 {
+		slog.WarnContext(ctx, "dashboard report: nobody is on call for a recipient team", "dashboardId", d.ID, "teams", unresolved)
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("no recipients: nobody is on call for %v", unresolved)
+	}
+	return s.email.SendEmail(ctx, recipients, nil, nil, nil, subject, body, attachments)
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/notifications"
+)
+
+// fakeEntity answers case searches by the severity filter they carry, and
+// fails every other resource.
+type fakeEntity struct {
+	entityClient // nil: any method not overridden below panics if called
+}
+
+func (fakeEntity) SearchCases(_ context.Context, body []byte) ([]byte, error) {
+	var req struct {
+		Filters struct {
+			Filters []struct {
+				Field  string   `json:"field"`
+				Values []string `json:"values"`
+			} `json:"filters"`
+		} `json:"filters"`
+		Pagination struct{ Limit int } `json:"pagination"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Pagination.Limit > 1 {
+		return []byte(`{"cases":[
+			{"number":"CS0001","createdOn":"2026-03-02 09:00:00","project":{"key":"ACME"}},
+			{"number":"=HYPERLINK(\"x\")","createdOn":"","project":null}
+		],"total":7}`), nil
+	}
+	for _, f := range req.Filters.Filters {
+		if f.Field == "severity" && len(f.Values) == 1 && f.Values[0] == "critical" {
+			return []byte(`{"total":2}`), nil
+		}
+	}
+	return []byte(`{"total":5}`), nil
+}
+
+func (fakeEntity) SearchIncidents(context.Context, []byte) ([]byte, error) {
+	return nil, errors.New("entity unavailable")
+}
+
+type sentEmail struct {
+	to          []string
+	subject     string
+	body        string
+	attachments []notifications.EmailAttachment
+}
+
+type fakeEmail struct{ sent []sentEmail }
+
+func (f *fakeEmail) SendEmail(_ context.Context, to, _, _, _ []string, subject, body string, attachments []notifications.EmailAttachment) error {
+	f.sent = append(f.sent, sentEmail{to, subject, body, attachments})
+	return nil
+}
+
+func reportDashboard() dashboard.Dashboard {
+	return dashboard.Dashboard{
+		ID: "cs-weekly", DisplayName: "CS Weekly",
+		Schedule: &dashboard.ReportSchedule{Cron: "0 8 * * mon", Timezone: "Asia/Colombo", Recipients: []string{"leads@example.com"}},
+		Widgets: []dashboard.WidgetTemplate{
+			{ID: "open", DisplayName: "Open Cases", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapeCount, Section: "Overview"},
+			{ID: "mine", DisplayName: "My Cases", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapeCount, Section: "Overview",
+				Query: map[string]any{"filters": []any{map[string]any{"field": "assignedUserId", "op": "in", "values": []any{"__current_user__"}}}}},
+			{ID: "incidents", DisplayName: "Open Incidents", ResourceType: dashboard.ResourceIncident, Shape: dashboard.ShapeCount, Section: "Overview"},
+			{ID: "by-severity", DisplayName: "By Severity", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapePie,
+				Query: map[string]any{"filters": []any{map[string]any{"field": "severity", "op": "in", "values": []any{"low"}}}},
+				Slices: []dashboard.PieSlice{
+					{Label: "Critical", Query: map[string]any{"filters": []any{map[string]any{"field": "severity", "op": "in", "values": []any{"critical"}}}}},
+					{Label: "Other", Query: map[string]any{}},
+				}},
+			{ID: "recent", DisplayName: "Recent <Cases>", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapeList, ListLimit: 2,
+				Columns: []dashboard.Column{{Path: "number", Label: "Number"}, {Path: "project.key", Label: "Project"}, {Path: "createdOn", Label: "Created", Format: "date"}}},
+			{ID: "trend", DisplayName: "Trend", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapeLine},
+		},
+	}
+}
+
+func newTestScheduler(email *fakeEmail) *Scheduler {
+	s := NewScheduler(fakeEntity{}, email, func() []dashboard.Dashboard { return []dashboard.Dashboard{reportDashboard()} },
+		Config{PortalBaseURL: "https://portal.example.com/"})
+	s.now = func() time.Time { return time.Date(2026, 3, 9, 2, 30, 0, 0, time.UTC) } // Monday 08:00 in Colombo
+	return s
+}
+
+func TestScheduler_RunDueFiresOnlyInsideTheWindow(t *testing.T) {
+	email := &fakeEmail{}
+	s := newTestScheduler(email)
+
+	s.runDue(context.Background(), time.Date(2026, 3, 9, 2, 28, 0, 0, time.UTC), time.Date(2026, 3, 9, 2, 29, 0, 0, time.UTC))
+	if len(email.sent) != 0 {
+		t.Fatalf("sent %d reports before the fire time", len(email.sent))
+	}
+	s.runDue(context.Background(), time.Date(2026, 3, 9, 2, 29, 0, 0, time.UTC), time.Date(2026, 3, 9, 2, 30, 0, 0, time.UTC))
+	if len(email.sent) != 1 {
+		t.Fatalf("sent %d reports at the fire time, want 1", len(email.sent))
+	}
+	s.runDue(context.Background(), time.Date(2026, 3, 9, 2, 30, 0, 0, time.UTC), time.Date(2026, 3, 9, 2, 31, 0, 0, time.UTC))
+	if len(email.sent) != 1 {
+		t.Errorf("sent %d reports, want the fire time to count once", len(email.sent))
+	}
+
+	got := email.sent[0]
+	if !reflect.DeepEqual(got.to, []string{"leads@example.com"}) || got.subject != "CS Weekly report — 9 Mar 2026" {
+		t.Errorf("sent to %v with subject %q", got.to, got.subject)
+	}
+}
+
+func TestScheduler_ReportContent(t *testing.T) {
+	email := &fakeEmail{}
+	rec := newTestScheduler(email).send(context.Background(), reportDashboard(), triggerManual)
+	if rec.Err != nil {
+		t.Fatalf("send: %v", rec.Err)
+	}
+	if rec.Widgets != 6 || rec.Skipped != 2 || rec.WidgetErrors != 1 || rec.Attachments != 3 {
+		t.Errorf("run = %+v, want 6 widgets, 2 skipped, 1 error, 3 attachments", rec)
+	}
+
+	body := email.sent[0].body
+	for _, want := range []string{
+		"https://portal.example.com/dashboard/cs-weekly",
+		"Recent &lt;Cases&gt;", // escaped, not injected
+		"Depends on the viewer (__current_user__)",
+		"Could not be resolved",
+		"Charts are not included",
+		"Mar 2, 2026", // date column formatted
+		"Showing 2 of 7.",
+		"Mon, 9 Mar 2026 08:00", // in the schedule's own timezone
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("report body is missing %q", want)
+		}
+	}
+
+	files := map[string]string{}
+	for _, a := range email.sent[0].attachments {
+		files[a.ContentName] = string(a.Attachment)
+	}
+	if files["counts.csv"] != "Widget,Count\nOpen Cases,5\n" {
+		t.Errorf("counts.csv = %q", files["counts.csv"])
+	}
+	// The slice's severity filter replaces the base's rather than ANDing with it.
+	if files["by-severity.csv"] != "Label,Count\nCritical,2\nOther,5\n" {
+		t.Errorf("by-severity.csv = %q", files["by-severity.csv"])
+	}
+	if files["recent.csv"] != "Number,Project,Created\nCS0001,ACME,2026-03-02 09:00:00\n\"'=HYPERLINK(\"\"x\"\")\",,\n" {
+		t.Errorf("recent.csv = %q", files["recent.csv"])
+	}
+}
+
+func TestScheduler_SendNow(t *testing.T) {
+	s := newTestScheduler(&fakeEmail{})
+	if err := s.SendNow(context.Background(), "nope"); !errors.Is(err, ErrUnknownDashboard) {
+		t.Errorf("unknown dashboard: err = %v", err)
+	}
+
+	s.dashboards = func() []dashboard.Dashboard { return []dashboard.Dashboard{{ID: "plain", DisplayName: "Plain"}} }
+	if err := s.SendNow(context.Background(), "plain"); !errors.Is(err, ErrNoSchedule) {
+		t.Errorf("unscheduled dashboard: err = %v", err)
+	}
+
+	s.email = nil
+	if err := s.SendNow(context.Background(), "plain"); !errors.Is(err, ErrEmailUnavailable) {
+		t.Errorf("no email channel: err = %v", err)
+	}
+}
+
+func TestScheduler_OnCallRecipients(t *testing.T) {
+	email := &fakeEmail{}
+	s := newTestScheduler(email)
+	d := reportDashboard()
+	d.Schedule.Recipients = []string{"__current_oncall__:abt-1"}
+	s.cfg.OnCall = func(teamKey string, at time.Time) (string, bool) {
+		if teamKey != "abt-1" || !at.Equal(s.now()) {
+			return "", false
+		}
+		return "oncall@example.com", true
+	}
+	if rec := s.send(context.Background(), d, triggerSchedule); rec.Err != nil {
+		t.Fatalf("send: %v", rec.Err)
+	}
+	if len(email.sent) != 1 || !reflect.DeepEqual(email.sent[0].to, []string{"oncall@example.com"}) {
+		t.Fatalf("sent = %+v, want one email to the on-call engineer", email.sent)
+	}
+
+	s.cfg.OnCall = nil
+	if rec := s.send(context.Background(), d, triggerSchedule); rec.Err == nil {
+		t.Error("a report with nobody to send to was sent")
+	}
+}
+
+func TestMergeQueries_DistributesAnyOf(t *testing.T) {
+	f := func(field, value string) any {
+		return map[string]any{"field": field, "op": "in", "values": []any{value}}
+	}
+	base := map[string]any{"anyOf": []any{
+		map[string]any{"filters": []any{f("state", "open")}},
+		map[string]any{"filters": []any{f("state", "new")}},
+	}}
+	slice := map[string]any{"anyOf": []any{map[string]any{"filters": []any{f("severity", "high")}}}}
+
+	got := mergeQueries(base, slice)["anyOf"].([]any)
+	if len(got) != 2 {
+		t.Fatalf("anyOf has %d branches, want 2", len(got))
+	}
+	for _, b := range got {
+		if filters := b.(map[string]any)["filters"].([]any); len(filters) != 2 {
+			t.Errorf("branch filters = %v, want the base state AND the slice severity", filters)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reports
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/widgetquery"
+)
+
+// trendConcurrency bounds the searches one trend has in flight.
+const trendConcurrency = 8
+
+// Trend is a resolved Shape "line"/"area" widget: one count per period per
+// series, Counts[i] belonging to Periods[i].
+type Trend struct {
+	Bucket  dashboard.TrendBucket `json:"bucket"`
+	Periods []TrendPeriod         `json:"periods"`
+	Series  []TrendSeries         `json:"series"`
+}
+
+// TrendPeriod is one bucket of a Trend, both ends inclusive (YYYY-MM-DD).
+type TrendPeriod struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// TrendSeries is one line of a Trend.
+type TrendSeries struct {
+	Label  string    `json:"label"`
+	Color  string    `json:"color,omitempty"`
+	Counts []float64 `json:"counts"`
+}
+
+// TrendResolver resolves trend widgets with the service's own credentials.
+type TrendResolver struct {
+	entity widgetquery.Searcher
+	now    func() time.Time
+}
+
+// NewTrendResolver creates a TrendResolver searching entity.
+func NewTrendResolver(entity widgetquery.Searcher) *TrendResolver {
+	return &TrendResolver{entity: entity, now: time.Now}
+}
+
+// Resolve counts w's series per period: one search per point, as
+// dashboard.TrendConfig describes. Any failed search fails the whole trend;
+// a chart with a silently missing point reads as a dip.
+func (t *TrendResolver) Resolve(ctx context.Context, w dashboard.WidgetTemplate) (Trend, error) {
+	if w.Trend == nil {
+		return Trend{}, fmt.Errorf("widget %q has no trend", w.ID)
+	}
+	r, err := widgetquery.For(t.entity, w.ResourceType)
+	if err != nil {
+		return Trend{}, err
+	}
+	periods, err := dashboard.ResolveTrendRange(*w.Trend, t.now().UTC())
+	if err != nil {
+		return Trend{}, err
+	}
+
+	out := Trend{Bucket: w.Trend.Bucket, Periods: make([]TrendPeriod, len(periods)), Series: make([]TrendSeries, len(w.Trend.Series))}
+	for i, p := range periods {
+		out.Periods[i] = TrendPeriod{Start: p.Start.Format(time.DateOnly), End: p.End.Format(time.DateOnly)}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, trendConcurrency)
+		errOnce  sync.Once
+		firstErr error
+	)
+	for si, s := range w.Trend.Series {
+		out.Series[si] = TrendSeries{Label: s.Label, Color: s.Color, Counts: make([]float64, len(periods))}
+		base := mergeQueries(w.Query, s.Query)
+		for pi, p := range out.Periods {
+			query := mergeQueries(base, nil)
+			filters, _ := query["filters"].([]any)
+			query["filters"] = append(append([]any(nil), filters...),
+				map[string]any{"field": s.DateField, "op": "gte", "values": []any{p.Start}},
+				map[string]any{"field": s.DateField, "op": "lte", "values": []any{p.End}},
+			)
+			wg.Add(1)
+			go func(counts []float64, pi int, label string) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-sem }()
+				n, err := count(ctx, r, query)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("series %q: %w", label, err)
+						cancel()
+					})
+					return
+				}
+				counts[pi] = n
+			}(out.Series[si].Counts, pi, s.Label)
+		}
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return Trend{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return Trend{}, err
+	}
+	return out, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+)
+
+// trendEntity counts the cases in created (by createdOn) and closed (by
+// closedOn) that fall inside a search's gte/lte pair, and requires the
+// widget's own severity filter on every search.
+type trendEntity struct {
+	entityClient
+	created, closed []string
+}
+
+func (f trendEntity) SearchCases(_ context.Context, body []byte) ([]byte, error) {
+	var req struct {
+		Filters struct {
+			Filters []struct {
+				Field  string   `json:"field"`
+				Op     string   `json:"op"`
+				Values []string `json:"values"`
+			} `json:"filters"`
+		} `json:"filters"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	var field, gte, lte string
+	severity := false
+	for _, c := range req.Filters.Filters {
+		switch c.Op {
+		case "gte":
+			field, gte = c.Field, c.Values[0]
+		case "lte":
+			lte = c.Values[0]
+		}
+		severity = severity || c.Field == "severity"
+	}
+	dates := f.created
+	if field == "closedOn" {
+		dates = f.closed
+	}
+	n := 0
+	for _, d := range dates {
+		if severity && d >= gte && d <= lte {
+			n++
+		}
+	}
+	return json.Marshal(map[string]int{"total": n})
+}
+
+func TestTrendResolver_Resolve(t *testing.T) {
+	entity := trendEntity{
+		created: []string{"2026-05-04", "2026-05-05", "2026-05-12"},
+		closed:  []string{"2026-05-11"},
+	}
+	r := NewTrendResolver(entity)
+	r.now = func() time.Time { return time.Date(2026, 5, 14, 10, 0, 0, 0, time.UTC) }
+
+	w := dashboard.WidgetTemplate{
+		ID: "created-vs-closed", ResourceType: dashboard.ResourceCase, Shape: dashboard.ShapeLine,
+		Query: map[string]any{"filters": []any{map[string]any{"field": "severity", "op": "in", "values": []any{"critical"}}}},
+		Trend: &dashboard.TrendConfig{Bucket: dashboard.BucketWeek, From: "__daysAgo:10__", Series: []dashboard.TrendSeries{
+			{Label: "Created", DateField: "createdOn"},
+			{Label: "Closed", DateField: "closedOn", Color: "success"},
+		}},
+	}
+	got, err := r.Resolve(context.Background(), w)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := Trend{
+		Bucket:  dashboard.BucketWeek,
+		Periods: []TrendPeriod{{"2026-05-04", "2026-05-10"}, {"2026-05-11", "2026-05-14"}},
+		Series: []TrendSeries{
+			{Label: "Created", Counts: []float64{2, 1}},
+			{Label: "Closed", Color: "success", Counts: []float64{0, 1}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve = %+v, want %+v", got, want)
+	}
+}
+
+func TestTrendResolver_FailedSearchFailsTheTrend(t *testing.T) {
+	r := NewTrendResolver(fakeEntity{})
+	w := dashboard.WidgetTemplate{
+		ID: "incidents", ResourceType: dashboard.ResourceIncident, Shape: dashboard.ShapeLine,
+		Trend: &dashboard.TrendConfig{Bucket: dashboard.BucketMonth, From: "__startOfMonth:-2__",
+			Series: []dashboard.TrendSeries{{Label: "Created", DateField: "createdOn"}}},
+	}
+	if _, err := r.Resolve(context.Background(), w); err == nil {
+		t.Error("Resolve succeeded with a failing search, want an error")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package scheduling
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calendarList is the entity service's GET /calendars response.
+type calendarList struct {
+	Calendars []calendarWire `json:"calendars"`
+}
+
+type calendarWire struct {
+	ID           string   `json:"id"`
+	Timezone     string   `json:"timezone"`
+	WorkingDays  []string `json:"workingDays"`
+	WorkingHours struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	} `json:"workingHours"`
+	Holidays []struct {
+		Date string `json:"date"`
+	} `json:"holidays"`
+	Default bool `json:"default"`
+}
+
+// weekdays maps the entity service's working day names to time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// workingHours is one business calendar's working week, in its own zone.
+type workingHours struct {
+	id  string
+	loc *time.Location
+	// open and close are minutes after local midnight.
+	open, close int
+	days        [7]bool
+	holidays    map[string]bool
+}
+
+// pickCalendar decodes a GET /calendars response and returns calendar id, or
+// the default calendar when id is empty.
+func pickCalendar(raw []byte, id string) (*workingHours, error) {
+	var list calendarList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("decode calendars: %w", err)
+	}
+	for _, c := range list.Calendars {
+		if (id == "" && c.Default) || (id != "" && c.ID == id) {
+			return c.workingHours()
+		}
+	}
+	if id == "" && len(list.Calendars) == 1 {
+		return list.Calendars[0].workingHours()
+	}
+	return nil, ErrUnknownCalendar
+}
+
+func (c calendarWire) workingHours() (*workingHours, error) {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("calendar %q: %w", c.ID, err)
+	}
+	h := &workingHours{id: c.ID, loc: loc, holidays: make(map[string]bool, len(c.Holidays))}
+	if h.open, err = parseClock(c.WorkingHours.Start); err != nil {
+		return nil, fmt.Errorf("calendar %q: %w", c.ID, err)
+	}
+	if h.close, err = parseClock(c.WorkingHours.End); err != nil {
+		return nil, fmt.Errorf("calendar %q: %w", c.ID, err)
+	}
+	for _, d := range c.WorkingDays {
+		if wd, ok := weekdays[strings.ToLower(d)]; ok {
+			h.days[wd] = true
+		}
+	}
+	for _, hol := range c.Holidays {
+		h.holidays[hol.Date] = true
+	}
+	return h, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("bad working hours %q", s)
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || h > 24 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("bad working hours %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// contains reports whether [start, start+d) lies within one working day's
+// hours. Opening and closing are on the wall clock, so they hold across a
+// daylight saving change.
+func (h *workingHours) contains(start time.Time, d time.Duration) bool {
+	local := start.In(h.loc)
+	if !h.days[local.Weekday()] || h.holidays[local.Format(time.DateOnly)] {
+		return false
+	}
+	y, m, day := local.Date()
+	open := time.Date(y, m, day, 0, h.open, 0, 0, h.loc)
+	closing := time.Date(y, m, day, 0, h.close, 0, 0, h.loc)
+	return !start.Before(open) && !start.Add(d).After(closing)
+}
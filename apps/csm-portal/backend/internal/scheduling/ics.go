@@ -0,0 +1,119 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package scheduling
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// icsTime is the iCalendar UTC date-time form (RFC 5545 §3.3.5).
+const icsTime = "20060102T150405Z"
+
+// buildInvite renders a scheduled call request as an RFC 5545 VCALENDAR
+// with one VEVENT. The UID is stable per call request and SEQUENCE grows
+// with stamp, so a calendar client replaces the event when the call is
+// rescheduled rather than adding a second one. An assignee or attendee that
+// is an email address becomes the ORGANIZER or an ATTENDEE.
+func buildInvite(cr callRequest, stamp time.Time) (string, error) {
+	if cr.ScheduleTime == nil {
+		return "", ErrNotScheduled
+	}
+	start, ok := ParseTime(*cr.ScheduleTime)
+	if !ok {
+		return "", fmt.Errorf("scheduling: unparseable schedule time %q", *cr.ScheduleTime)
+	}
+	end := start.Add(time.Duration(max(cr.DurationMin, 0)) * time.Minute)
+
+	summary := "Call " + cr.Number
+	if cr.Case.Number != nil && *cr.Case.Number != "" {
+		summary += " for case " + *cr.Case.Number
+	}
+	if cr.Case.Name != "" {
+		summary += ": " + cr.Case.Name
+	}
+
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldLine(s)) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//WSO2//CSM Portal//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:REQUEST")
+	line("BEGIN:VEVENT")
+	line("UID:" + cr.ID + "@csm-portal")
+	line(fmt.Sprintf("SEQUENCE:%d", stamp.Unix()/60))
+	line("DTSTAMP:" + stamp.UTC().Format(icsTime))
+	line("DTSTART:" + start.UTC().Format(icsTime))
+	line("DTEND:" + end.UTC().Format(icsTime))
+	line("SUMMARY:" + escapeText(summary))
+	if cr.Reason != nil && *cr.Reason != "" {
+		line("DESCRIPTION:" + escapeText(*cr.Reason))
+	}
+	if cr.MeetingLink != nil && *cr.MeetingLink != "" {
+		line("LOCATION:" + escapeText(*cr.MeetingLink))
+		line("URL:" + *cr.MeetingLink)
+	}
+	if cr.Assignee != nil {
+		if addr, err := mail.ParseAddress(*cr.Assignee); err == nil {
+			line("ORGANIZER" + commonName(addr) + ":mailto:" + addr.Address)
+		}
+	}
+	if cr.Attendees != nil {
+		for _, a := range strings.FieldsFunc(*cr.Attendees, func(r rune) bool { return r == ',' || r == ';' || r == '\n' }) {
+			if addr, err := mail.ParseAddress(strings.TrimSpace(a)); err == nil {
+				line("ATTENDEE" + commonName(addr) + ";ROLE=REQ-PARTICIPANT;RSVP=TRUE:mailto:" + addr.Address)
+			}
+		}
+	}
+	line("STATUS:CONFIRMED")
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return b.String(), nil
+}
+
+func commonName(addr *mail.Address) string {
+	if addr.Name == "" {
+		return ""
+	}
+	return `;CN="` + strings.NewReplacer(`"`, "", "\r", "", "\n", "").Replace(addr.Name) + `"`
+}
+
+// escapeText escapes an iCalendar TEXT value (RFC 5545 §3.3.11).
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// foldLine terminates a content line with CRLF, folding it so no physical
+// line exceeds 75 octets (RFC 5545 §3.1) without splitting a UTF-8 sequence.
+func foldLine(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvite(t *testing.T) {
+	s, f := testScheduler()
+	if _, err := s.Invite(context.Background(), "c1", "cr1"); !errors.Is(err, ErrNotScheduled) {
+		t.Fatalf("unscheduled: err = %v", err)
+	}
+
+	f.caseCalls = strings.Replace(caseCalls, `"state":{"id":"pending_on_wso2"}`,
+		`"state":{"id":"scheduled"},"scheduleTime":"2026-10-20 08:00:00","meetingLink":"https://meet.example.com/abc"`, 1)
+	got, err := s.Invite(context.Background(), "c1", "cr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:cr1@csm-portal\r\n",
+		"DTSTAMP:20261019T060000Z\r\n",
+		"DTSTART:20261020T080000Z\r\n",
+		"DTEND:20261020T090000Z\r\n",
+		"SUMMARY:Call CR1 for case CS1: Gateway down\r\n",
+		`DESCRIPTION:Walk through the logs\; bring traces` + "\r\n",
+		"URL:https://meet.example.com/abc\r\n",
+		"ORGANIZER:mailto:alice@example.com\r\n",
+		`ATTENDEE;CN="Dan";ROLE=REQ-PARTICIPANT;RSVP=TRUE:mailto:dan@example.com` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("invite lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "ATTENDEE") != 1 {
+		t.Errorf("an attendee that is not an address was invited:\n%s", got)
+	}
+}
+
+func TestInvite_SequenceGrows(t *testing.T) {
+	at := "2026-10-20 08:00:00"
+	cr := callRequest{ID: "cr1", Number: "CR1", DurationMin: 30, ScheduleTime: &at}
+	first, _ := buildInvite(cr, time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC))
+	second, _ := buildInvite(cr, time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC))
+	seq := func(s string) string {
+		_, rest, _ := strings.Cut(s, "SEQUENCE:")
+		v, _, _ := strings.Cut(rest, "\r\n")
+		return v
+	}
+	if seq(first) >= seq(second) {
+		t.Errorf("sequence %s then %s", seq(first), seq(second))
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	got := foldLine("SUMMARY:" + strings.Repeat("é", 60))
+	for _, l := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line of %d octets: %q", len(l), l)
+		}
+	}
+	if unfolded := strings.ReplaceAll(got, "\r\n ", ""); unfolded != "SUMMARY:"+strings.Repeat("é", 60)+"\r\n" {
+		t.Errorf("unfolded = %q", unfolded)
+	}
+}
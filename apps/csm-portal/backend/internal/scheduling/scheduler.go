@@ -0,0 +1,677 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package scheduling finds times for a customer call request and books them.
+// A slot must fall within the assignee's working hours -- an entity-service
+// business calendar -- and must not overlap a call already scheduled for the
+// same assignee, read live from the cross-case call request search. The
+// customer's preferred times are tried first; when too few of them fit, free
+// slots are offered from the earliest preferred time onwards, within the
+// customer's own calendar's hours when one is given.
+//
+// Schedule refuses a slot that double-books the assignee unless told to
+// override, and returns an iCalendar invite for the booked call.
+package scheduling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// callTimeout bounds each entity call the scheduler makes.
+const callTimeout = 30 * time.Second
+
+const (
+	// pageLimit is the call request searches' own maximum page size.
+	pageLimit = 50
+	// maxPages caps how many pages of call requests are read, so a huge
+	// backlog of scheduled calls cannot stall a suggestion.
+	maxPages = 20
+)
+
+const (
+	// DefaultLimit is how many slots Suggest returns unless asked otherwise.
+	DefaultLimit = 5
+	// MaxLimit is the most slots Suggest returns.
+	MaxLimit = 20
+	// MaxDurationMin is the longest call that can be scheduled.
+	MaxDurationMin = 8 * 60
+	// slotStep is the spacing of the alternative slots Suggest offers.
+	slotStep = 30 * time.Minute
+	// horizon is how far past its first candidate Suggest looks for
+	// alternative slots.
+	horizon = 14 * 24 * time.Hour
+)
+
+// Reasons a preferred time was not offered as a slot.
+const (
+	ReasonUnparseable  = "unparseable"
+	ReasonPast         = "past"
+	ReasonOutsideHours = "outside_working_hours"
+	ReasonConflict     = "conflict"
+)
+
+var (
+	// ErrCallRequestNotFound is returned for a call request not on the case.
+	ErrCallRequestNotFound = errors.New("scheduling: call request not found")
+	// ErrUnknownCalendar is returned for a calendar ID the entity service
+	// does not have.
+	ErrUnknownCalendar = errors.New("scheduling: unknown calendar")
+	// ErrInvalidTimezone is returned for a customer timezone that is not an
+	// IANA zone name.
+	ErrInvalidTimezone = errors.New("scheduling: invalid customer timezone")
+	// ErrNoAssignee is returned when neither the request nor the call request
+	// names an assignee.
+	ErrNoAssignee = errors.New("scheduling: no assignee")
+	// ErrUnknownAssignee is returned for an assignee that is neither a
+	// platform user id nor the email of a user the entity service has, so
+	// their scheduled calls cannot be found.
+	ErrUnknownAssignee = errors.New("scheduling: unknown assignee")
+	// ErrInvalidDuration is returned for a duration outside 1-MaxDurationMin
+	// minutes.
+	ErrInvalidDuration = errors.New("scheduling: invalid duration")
+	// ErrInPast is returned for a meeting time that has already passed.
+	ErrInPast = errors.New("scheduling: meeting time is in the past")
+	// ErrNotScheduled is returned for an invite to a call that is not
+	// scheduled.
+	ErrNotScheduled = errors.New("scheduling: call request is not scheduled")
+)
+
+// ConflictError is returned by Schedule when the slot double-books the
+// assignee and the request does not override.
+type ConflictError struct {
+	Conflicts []Booking
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("scheduling: slot conflicts with %d scheduled call(s)", len(e.Conflicts))
+}
+
+// entityClient abstracts the entity service calls the scheduler makes.
+type entityClient interface {
+	SearchCallRequests(ctx context.Context, body []byte) ([]byte, error)
+	SearchAllCallRequests(ctx context.Context, body []byte) ([]byte, error)
+	PatchCallRequest(ctx context.Context, callRequestID string, body []byte) ([]byte, error)
+	ListCalendars(ctx context.Context) ([]byte, error)
+	SearchUsers(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// callRequest is the part of a call request the scheduler reads.
+type callRequest struct {
+	ID     string `json:"id"`
+	Number string `json:"number"`
+	Case   struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Number *string `json:"number"`
+	} `json:"case"`
+	Reason         *string  `json:"reason"`
+	PreferredTimes []string `json:"preferredTimes"`
+	DurationMin    int      `json:"durationMin"`
+	ScheduleTime   *string  `json:"scheduleTime"`
+	MeetingLink    *string  `json:"meetingLink"`
+	State          struct {
+		ID string `json:"id"`
+	} `json:"state"`
+	Assignee  *string `json:"assignee"`
+	Attendees *string `json:"attendees"`
+}
+
+type callRequestPage struct {
+	CallRequests []callRequest `json:"callRequests"`
+	Total        int           `json:"total"`
+}
+
+// Booking is a call already scheduled for the assignee.
+type Booking struct {
+	CallRequestID string    `json:"callRequestId"`
+	Number        string    `json:"number"`
+	CaseNumber    string    `json:"caseNumber,omitempty"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+}
+
+// LocalTimes is a slot on one party's clock.
+type LocalTimes struct {
+	Timezone string `json:"timezone"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+}
+
+// Slot is a conflict-free time for the call.
+type Slot struct {
+	// Preferred reports the customer asked for this time.
+	Preferred bool      `json:"preferred"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	// Assignee and Customer are Start and End on each party's clock.
+	Assignee LocalTimes `json:"assignee"`
+	Customer LocalTimes `json:"customer"`
+}
+
+// Rejection is a preferred time that was not offered, and why.
+type Rejection struct {
+	PreferredTime string    `json:"preferredTime"`
+	Reason        string    `json:"reason"`
+	Conflicts     []Booking `json:"conflicts,omitempty"`
+}
+
+// SuggestRequest asks for slots for one call request.
+type SuggestRequest struct {
+	CaseID        string
+	CallRequestID string
+	// Assignee overrides the call request's own assignee.
+	Assignee string
+	// CalendarID is the business calendar of the assignee's working hours;
+	// empty uses the entity service's default calendar.
+	CalendarID string
+	// CustomerCalendarID, when set, confines alternative slots to the
+	// customer's working hours and gives the customer's timezone.
+	CustomerCalendarID string
+	// CustomerTimezone is the customer's IANA zone when they have no
+	// calendar; empty is UTC.
+	CustomerTimezone string
+	// DurationMin overrides the call request's requested duration.
+	DurationMin int
+	Limit       int
+}
+
+// Suggestion is the slots found for a call request.
+type Suggestion struct {
+	CallRequestID    string      `json:"callRequestId"`
+	Number           string      `json:"number"`
+	Assignee         string      `json:"assignee"`
+	DurationMin      int         `json:"durationMin"`
+	AssigneeTimezone string      `json:"assigneeTimezone"`
+	CustomerTimezone string      `json:"customerTimezone"`
+	Slots            []Slot      `json:"slots"`
+	Rejected         []Rejection `json:"rejected"`
+}
+
+// ScheduleRequest books a call request at a time.
+type ScheduleRequest struct {
+	CaseID        string
+	CallRequestID string
+	Start         time.Time
+	DurationMin   int
+	Assignee      string
+	// Override books the slot even when it double-books the assignee.
+	Override bool
+}
+
+// ScheduleResult is a booked call.
+type ScheduleResult struct {
+	// CallRequest is the entity service's PATCH response.
+	CallRequest json.RawMessage `json:"callRequest"`
+	// Conflicts are the calls the booking overlaps; only set when it was
+	// overridden.
+	Conflicts []Booking `json:"conflicts,omitempty"`
+	Invite    string    `json:"invite"`
+}
+
+// Scheduler suggests and books call request slots.
+type Scheduler struct {
+	entity entityClient
+	now    func() time.Time
+}
+
+// NewScheduler creates a Scheduler.
+func NewScheduler(entity entityClient) *Scheduler {
+	return &Scheduler{entity: entity, now: time.Now}
+}
+
+// Suggest returns up to req.Limit conflict-free slots for a call request:
+// the customer's preferred times that fit, then alternatives. Preferred times
+// that do not fit are returned with the reason.
+func (s *Scheduler) Suggest(ctx context.Context, req SuggestRequest) (Suggestion, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	cr, err := s.load(ctx, req.CaseID, req.CallRequestID)
+	if err != nil {
+		return Suggestion{}, err
+	}
+	assignee := strings.TrimSpace(req.Assignee)
+	if assignee == "" && cr.Assignee != nil {
+		assignee = strings.TrimSpace(*cr.Assignee)
+	}
+	if assignee == "" {
+		return Suggestion{}, ErrNoAssignee
+	}
+	durationMin := req.DurationMin
+	if durationMin == 0 {
+		durationMin = cr.DurationMin
+	}
+	if durationMin <= 0 || durationMin > MaxDurationMin {
+		return Suggestion{}, ErrInvalidDuration
+	}
+	d := time.Duration(durationMin) * time.Minute
+
+	raw, err := s.call(ctx, func(ctx context.Context) ([]byte, error) { return s.entity.ListCalendars(ctx) })
+	if err != nil {
+		return Suggestion{}, err
+	}
+	hours, err := pickCalendar(raw, req.CalendarID)
+	if err != nil {
+		return Suggestion{}, err
+	}
+	var customerHours *workingHours
+	customerLoc := time.UTC
+	switch {
+	case req.CustomerCalendarID != "":
+		if customerHours, err = pickCalendar(raw, req.CustomerCalendarID); err != nil {
+			return Suggestion{}, err
+		}
+		customerLoc = customerHours.loc
+	case req.CustomerTimezone != "":
+		if customerLoc, err = loadZone(req.CustomerTimezone); err != nil {
+			return Suggestion{}, err
+		}
+	}
+
+	// Alternatives are looked for from the earliest future preferred time
+	// (else now) for horizon, so busy covers that and every later preferred
+	// time.
+	now := s.now()
+	from, last := now, now
+	first := true
+	for _, p := range cr.PreferredTimes {
+		if t, ok := ParseTime(p); ok && t.After(now) {
+			if first || t.Before(from) {
+				from, first = t, false
+			}
+			if t.After(last) {
+				last = t
+			}
+		}
+	}
+	until := from.Add(horizon)
+	if last.After(until) {
+		until = last
+	}
+	busy, err := s.bookings(ctx, assignee, cr.ID, now, until.Add(d))
+	if err != nil {
+		return Suggestion{}, err
+	}
+
+	out := Suggestion{
+		CallRequestID:    cr.ID,
+		Number:           cr.Number,
+		Assignee:         assignee,
+		DurationMin:      durationMin,
+		AssigneeTimezone: hours.loc.String(),
+		CustomerTimezone: customerLoc.String(),
+		Slots:            []Slot{},
+		Rejected:         []Rejection{},
+	}
+	slot := func(start time.Time, preferred bool) Slot {
+		end := start.Add(d)
+		return Slot{
+			Preferred: preferred,
+			Start:     start.UTC(),
+			End:       end.UTC(),
+			Assignee:  localTimes(start, end, hours.loc),
+			Customer:  localTimes(start, end, customerLoc),
+		}
+	}
+	taken := func(t time.Time) bool {
+		return slices.ContainsFunc(out.Slots, func(sl Slot) bool { return sl.Start.Equal(t) })
+	}
+
+	for _, p := range cr.PreferredTimes {
+		t, ok := ParseTime(p)
+		switch {
+		case !ok:
+			out.Rejected = append(out.Rejected, Rejection{PreferredTime: p, Reason: ReasonUnparseable})
+			continue
+		case !t.After(now):
+			out.Rejected = append(out.Rejected, Rejection{PreferredTime: p, Reason: ReasonPast})
+			continue
+		}
+		if !hours.contains(t, d) {
+			out.Rejected = append(out.Rejected, Rejection{PreferredTime: p, Reason: ReasonOutsideHours})
+			continue
+		}
+		if c := overlapping(busy, t, d); len(c) > 0 {
+			out.Rejected = append(out.Rejected, Rejection{PreferredTime: p, Reason: ReasonConflict, Conflicts: c})
+			continue
+		}
+		if len(out.Slots) < limit && !taken(t) {
+			out.Slots = append(out.Slots, slot(t, true))
+		}
+	}
+
+	end := from.Add(horizon)
+	for t := alignUp(from, hours.loc); len(out.Slots) < limit && t.Before(end); t = t.Add(slotStep) {
+		if !hours.contains(t, d) || (customerHours != nil && !customerHours.contains(t, d)) {
+			continue
+		}
+		if taken(t) || len(overlapping(busy, t, d)) > 0 {
+			continue
+		}
+		out.Slots = append(out.Slots, slot(t, false))
+	}
+	return out, nil
+}
+
+// Schedule books a call request at req.Start for req.Assignee and returns the
+// updated call request with an invite. A slot that overlaps another of the
+// assignee's scheduled calls is refused with a *ConflictError unless
+// req.Override.
+func (s *Scheduler) Schedule(ctx context.Context, req ScheduleRequest) (ScheduleResult, error) {
+	assignee := strings.TrimSpace(req.Assignee)
+	if assignee == "" {
+		return ScheduleResult{}, ErrNoAssignee
+	}
+	if req.DurationMin <= 0 || req.DurationMin > MaxDurationMin {
+		return ScheduleResult{}, ErrInvalidDuration
+	}
+	now := s.now()
+	if !req.Start.After(now) {
+		return ScheduleResult{}, ErrInPast
+	}
+	d := time.Duration(req.DurationMin) * time.Minute
+
+	cr, err := s.load(ctx, req.CaseID, req.CallRequestID)
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+	busy, err := s.bookings(ctx, assignee, cr.ID, req.Start, req.Start.Add(d))
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+	conflicts := overlapping(busy, req.Start, d)
+	if len(conflicts) > 0 && !req.Override {
+		return ScheduleResult{}, &ConflictError{Conflicts: conflicts}
+	}
+
+	meetingDate := req.Start.UTC().Format(time.RFC3339)
+	body, err := json.Marshal(map[string]any{
+		"caseId":            req.CaseID,
+		"state":             "scheduled",
+		"meetingDate":       meetingDate,
+		"durationInMinutes": req.DurationMin,
+		"assignee":          assignee,
+	})
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+	result, err := s.call(ctx, func(ctx context.Context) ([]byte, error) {
+		return s.entity.PatchCallRequest(ctx, cr.ID, body)
+	})
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+
+	cr.ScheduleTime, cr.DurationMin, cr.Assignee = &meetingDate, req.DurationMin, &assignee
+	cr.State.ID = "scheduled"
+	invite, err := buildInvite(cr, now)
+	if err != nil {
+		return ScheduleResult{}, err
+	}
+	return ScheduleResult{CallRequest: result, Conflicts: conflicts, Invite: invite}, nil
+}
+
+// Conflicts returns the assignee's other scheduled calls that booking call
+// request callRequestID at start for durationMin minutes would overlap, for
+// a booking made outside Schedule. An empty assignee or a zero duration is
+// the call request's own.
+func (s *Scheduler) Conflicts(ctx context.Context, caseID, callRequestID string, start time.Time, durationMin int, assignee string) ([]Booking, error) {
+	cr, err := s.load(ctx, caseID, callRequestID)
+	if err != nil {
+		return nil, err
+	}
+	assignee = strings.TrimSpace(assignee)
+	if assignee == "" && cr.Assignee != nil {
+		assignee = strings.TrimSpace(*cr.Assignee)
+	}
+	if assignee == "" {
+		return nil, ErrNoAssignee
+	}
+	if durationMin == 0 {
+		durationMin = cr.DurationMin
+	}
+	if durationMin <= 0 || durationMin > MaxDurationMin {
+		return nil, ErrInvalidDuration
+	}
+	d := time.Duration(durationMin) * time.Minute
+	busy, err := s.bookings(ctx, assignee, cr.ID, start, start.Add(d))
+	if err != nil {
+		return nil, err
+	}
+	return overlapping(busy, start, d), nil
+}
+
+// Invite returns the iCalendar invite for a scheduled call request.
+func (s *Scheduler) Invite(ctx context.Context, caseID, callRequestID string) (string, error) {
+	cr, err := s.load(ctx, caseID, callRequestID)
+	if err != nil {
+		return "", err
+	}
+	if cr.State.ID != "scheduled" {
+		return "", ErrNotScheduled
+	}
+	return buildInvite(cr, s.now())
+}
+
+// load finds a call request among its case's call requests.
+func (s *Scheduler) load(ctx context.Context, caseID, callRequestID string) (callRequest, error) {
+	for page := 0; page < maxPages; page++ {
+		body, err := json.Marshal(map[string]any{
+			"caseId":     caseID,
+			"pagination": map[string]int{"limit": pageLimit, "offset": page * pageLimit},
+		})
+		if err != nil {
+			return callRequest{}, err
+		}
+		raw, err := s.call(ctx, func(ctx context.Context) ([]byte, error) { return s.entity.SearchCallRequests(ctx, body) })
+		if err != nil {
+			return callRequest{}, err
+		}
+		var p callRequestPage
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return callRequest{}, fmt.Errorf("decode call requests: %w", err)
+		}
+		for _, cr := range p.CallRequests {
+			if strings.EqualFold(cr.ID, callRequestID) {
+				return cr, nil
+			}
+		}
+		if len(p.CallRequests) < pageLimit || (page+1)*pageLimit >= p.Total {
+			break
+		}
+	}
+	return callRequest{}, ErrCallRequestNotFound
+}
+
+// bookings returns the assignee's scheduled calls that overlap
+// [since, until), other than call request exclude. The search is filtered to
+// the assignee and read in ascending scheduleTime order, stopping at until,
+// so the calls that matter are never crowded out by later ones.
+func (s *Scheduler) bookings(ctx context.Context, assignee, exclude string, since, until time.Time) ([]Booking, error) {
+	userID, err := s.assigneeID(ctx, assignee)
+	if err != nil {
+		return nil, err
+	}
+	var out []Booking
+	for page := 0; page < maxPages; page++ {
+		body, err := json.Marshal(map[string]any{
+			"filters":    map[string][]string{"states": {"scheduled"}, "assignedUserIds": {userID}},
+			"sortBy":     map[string]string{"field": "scheduleTime", "order": "asc"},
+			"pagination": map[string]int{"limit": pageLimit, "offset": page * pageLimit},
+		})
+		if err != nil {
+			return nil, err
+		}
+		raw, err := s.call(ctx, func(ctx context.Context) ([]byte, error) { return s.entity.SearchAllCallRequests(ctx, body) })
+		if err != nil {
+			return nil, err
+		}
+		var p callRequestPage
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, fmt.Errorf("decode call requests: %w", err)
+		}
+		later := false
+		for _, cr := range p.CallRequests {
+			if cr.ScheduleTime == nil {
+				continue
+			}
+			start, ok := ParseTime(*cr.ScheduleTime)
+			if !ok {
+				continue
+			}
+			if !start.Before(until) {
+				// Sorted oldest first, so every later call starts after until too.
+				later = true
+				break
+			}
+			end := start.Add(time.Duration(max(cr.DurationMin, 0)) * time.Minute)
+			if !end.After(since) || strings.EqualFold(cr.ID, exclude) {
+				continue
+			}
+			b := Booking{CallRequestID: cr.ID, Number: cr.Number, Start: start.UTC(), End: end.UTC()}
+			if cr.Case.Number != nil {
+				b.CaseNumber = *cr.Case.Number
+			}
+			out = append(out, b)
+		}
+		if later || len(p.CallRequests) < pageLimit || (page+1)*pageLimit >= p.Total {
+			break
+		}
+	}
+	return out, nil
+}
+
+// uuidRe matches a platform user id.
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// assigneeID resolves an assignee -- a platform user id, or a user's email --
+// to the user id the call request search filters on.
+func (s *Scheduler) assigneeID(ctx context.Context, assignee string) (string, error) {
+	if uuidRe.MatchString(assignee) {
+		return strings.ToLower(assignee), nil
+	}
+	if !strings.Contains(assignee, "@") {
+		return "", ErrUnknownAssignee
+	}
+	body, err := json.Marshal(map[string]any{
+		"filters":    map[string]any{"emails": []string{assignee}},
+		"pagination": map[string]int{"offset": 0, "limit": 1},
+	})
+	if err != nil {
+		return "", err
+	}
+	raw, err := s.call(ctx, func(ctx context.Context) ([]byte, error) { return s.entity.SearchUsers(ctx, body) })
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Users []struct {
+			ID    string `json:"id"`
+			Email string `json:"email"`
+		} `json:"users"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", fmt.Errorf("decode users: %w", err)
+	}
+	for _, u := range resp.Users {
+		if strings.EqualFold(u.Email, assignee) {
+			return u.ID, nil
+		}
+	}
+	return "", ErrUnknownAssignee
+}
+
+// overlapping returns the bookings that overlap [start, start+d).
+func overlapping(busy []Booking, start time.Time, d time.Duration) []Booking {
+	end := start.Add(d)
+	var out []Booking
+	for _, b := range busy {
+		if start.Before(b.End) && b.Start.Before(end) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func (s *Scheduler) call(ctx context.Context, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	return fn(callCtx)
+}
+
+// timeLayouts are the shapes call request times arrive in. The backing data
+// source returns zoneless wall-clock strings that are UTC, as the webapp's
+// normalizeBackendTimestamp (src/utils/dateTime.ts) also assumes.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"01/02/2006 15:04:05",
+}
+
+// ParseTime parses a call request time, treating a zoneless value as UTC.
+func ParseTime(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// loadZone loads a customer's IANA zone. "Local" is refused: it would be the
+// server's zone, not the customer's.
+func loadZone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// alignUp rounds t up to the next slotStep boundary on loc's clock, so
+// alternatives start on the hour or half hour wherever the assignee is.
+func alignUp(t time.Time, loc *time.Location) time.Time {
+	_, offset := t.In(loc).Zone()
+	shift := time.Duration(offset) * time.Second
+	aligned := t.Add(shift).Truncate(slotStep).Add(-shift)
+	if aligned.Before(t) {
+		aligned = aligned.Add(slotStep)
+	}
+	return aligned
+}
+
+func localTimes(start, end time.Time, loc *time.Location) LocalTimes {
+	return LocalTimes{
+		Timezone: loc.String(),
+		Start:    start.In(loc).Format(time.RFC3339),
+		End:      end.In(loc).Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package scheduling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeEntity serves one case's call requests, each user's scheduled calls
+// across all cases and the business calendars, and records every PATCH and
+// scheduled-call search.
+type fakeEntity struct {
+	caseCalls string
+	scheduled map[string]string // user id -> search response
+	patched   map[string]string // call request id -> PATCH body
+	searches  []string
+}
+
+func (f *fakeEntity) SearchCallRequests(_ context.Context, _ []byte) ([]byte, error) {
+	return []byte(f.caseCalls), nil
+}
+
+func (f *fakeEntity) SearchAllCallRequests(_ context.Context, body []byte) ([]byte, error) {
+	f.searches = append(f.searches, string(body))
+	var req struct {
+		Filters struct {
+			States          []string `json:"states"`
+			AssignedUserIDs []string `json:"assignedUserIds"`
+		} `json:"filters"`
+		SortBy struct {
+			Field string `json:"field"`
+			Order string `json:"order"`
+		} `json:"sortBy"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || len(req.Filters.States) != 1 || req.Filters.States[0] != "scheduled" ||
+		len(req.Filters.AssignedUserIDs) != 1 || req.SortBy.Field != "scheduleTime" || req.SortBy.Order != "asc" {
+		return nil, errors.New("unexpected search " + string(body))
+	}
+	if page, ok := f.scheduled[req.Filters.AssignedUserIDs[0]]; ok {
+		return []byte(page), nil
+	}
+	return []byte(`{"callRequests":[],"total":0}`), nil
+}
+
+func (f *fakeEntity) SearchUsers(_ context.Context, body []byte) ([]byte, error) {
+	switch {
+	case strings.Contains(string(body), `"emails":["alice@example.com"]`):
+		return []byte(`{"users":[{"id":"` + aliceID + `","email":"Alice@example.com"}]}`), nil
+	case strings.Contains(string(body), `"emails":["bob@example.com"]`):
+		return []byte(`{"users":[{"id":"` + bobID + `","email":"bob@example.com"}]}`), nil
+	}
+	return []byte(`{"users":[]}`), nil
+}
+
+func (f *fakeEntity) PatchCallRequest(_ context.Context, id string, body []byte) ([]byte, error) {
+	f.patched[id] = string(body)
+	return []byte(`{"message":"ok"}`), nil
+}
+
+func (f *fakeEntity) ListCalendars(context.Context) ([]byte, error) {
+	return []byte(`{"calendars":[
+		{"id":"lk","timezone":"Asia/Colombo","workingDays":["mon","tue","wed","thu","fri"],
+		 "workingHours":{"start":"08:30","end":"17:30"},"holidays":[{"date":"2026-10-21","name":"Poya"}],"default":true},
+		{"id":"uk","timezone":"Europe/London","workingDays":["mon","tue","wed","thu","fri"],
+		 "workingHours":{"start":"09:00","end":"17:30"},"holidays":[]}]}`), nil
+}
+
+// CR1 asks for an hour with alice at five times on Monday 2026-10-19 and
+// Tuesday; alice already has CR2 from 04:30 to 05:30 UTC on Tuesday, and bob
+// has CR3 at 08:00, which is no concern of alice's.
+const caseCalls = `{"callRequests":[{"id":"cr1","number":"CR1","case":{"id":"c1","name":"Gateway down","number":"CS1"},
+	"reason":"Walk through the logs; bring traces","durationMin":60,"state":{"id":"pending_on_wso2"},
+	"assignee":"alice@example.com","attendees":"Dan <dan@example.com>, not an address",
+	"preferredTimes":["2026-10-19 04:00:00","2026-10-19 13:00:00","2026-10-20 05:00:00","2026-10-20 08:00:00","garbage"]}],"total":1}`
+
+const (
+	aliceID = "a1111111-1111-1111-1111-111111111111"
+	bobID   = "b2222222-2222-2222-2222-222222222222"
+)
+
+// scheduled is each user's scheduled calls, oldest first: alice's CR4 is
+// long over.
+var scheduled = map[string]string{
+	aliceID: `{"callRequests":[
+	{"id":"cr4","number":"CR4","case":{"id":"c4","name":"z"},"durationMin":60,"scheduleTime":"2026-10-01 04:30:00","assignee":"alice@example.com"},
+	{"id":"cr2","number":"CR2","case":{"id":"c2","name":"y","number":"CS2"},"durationMin":60,"scheduleTime":"2026-10-20 04:30:00","assignee":"Alice@Example.com"}],"total":2}`,
+	bobID: `{"callRequests":[
+	{"id":"cr3","number":"CR3","case":{"id":"c3","name":"x"},"durationMin":30,"scheduleTime":"2026-10-20 08:00:00","assignee":"bob@example.com"}],"total":1}`,
+}
+
+func testScheduler() (*Scheduler, *fakeEntity) {
+	f := &fakeEntity{caseCalls: caseCalls, scheduled: maps.Clone(scheduled), patched: map[string]string{}}
+	s := NewScheduler(f)
+	s.now = func() time.Time { return time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC) }
+	return s, f
+}
+
+func starts(slots []Slot) string {
+	var out []string
+	for _, s := range slots {
+		v := s.Start.Format("01-02 15:04")
+		if s.Preferred {
+			v += "*"
+		}
+		out = append(out, v)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestSuggest(t *testing.T) {
+	s, _ := testScheduler()
+	got, err := s.Suggest(context.Background(), SuggestRequest{CaseID: "c1", CallRequestID: "cr1", Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The one preferred time that fits comes first; alternatives then start
+	// when Colombo opens on Tuesday (03:00 UTC) and stop short of CR2.
+	if want := "10-20 08:00*,10-20 03:00,10-20 03:30"; starts(got.Slots) != want {
+		t.Errorf("slots = %s, want %s", starts(got.Slots), want)
+	}
+	if got.Assignee != "alice@example.com" || got.DurationMin != 60 || got.AssigneeTimezone != "Asia/Colombo" || got.CustomerTimezone != "UTC" {
+		t.Errorf("suggestion = %+v", got)
+	}
+	if a := got.Slots[0].Assignee; a.Start != "2026-10-20T13:30:00+05:30" || a.End != "2026-10-20T14:30:00+05:30" {
+		t.Errorf("assignee times = %+v", a)
+	}
+
+	reasons := map[string]string{}
+	for _, r := range got.Rejected {
+		reasons[r.PreferredTime] = r.Reason
+		if r.Reason == ReasonConflict && (len(r.Conflicts) != 1 || r.Conflicts[0].Number != "CR2" || r.Conflicts[0].CaseNumber != "CS2") {
+			t.Errorf("conflicts = %+v", r.Conflicts)
+		}
+	}
+	want := map[string]string{
+		"2026-10-19 04:00:00": ReasonPast,
+		"2026-10-19 13:00:00": ReasonOutsideHours,
+		"2026-10-20 05:00:00": ReasonConflict,
+		"garbage":             ReasonUnparseable,
+	}
+	if len(reasons) != len(want) {
+		t.Errorf("rejected = %+v", got.Rejected)
+	}
+	for k, v := range want {
+		if reasons[k] != v {
+			t.Errorf("%s rejected as %q, want %q", k, reasons[k], v)
+		}
+	}
+}
+
+func TestSuggest_CustomerCalendar(t *testing.T) {
+	s, _ := testScheduler()
+	got, err := s.Suggest(context.Background(), SuggestRequest{CaseID: "c1", CallRequestID: "cr1", CustomerCalendarID: "uk", Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// London opens at 08:00 UTC during summer time; 08:00 itself is the
+	// preferred slot already offered.
+	if want := "10-20 08:00*,10-20 08:30,10-20 09:00"; starts(got.Slots) != want {
+		t.Errorf("slots = %s, want %s", starts(got.Slots), want)
+	}
+	if c := got.Slots[1].Customer; c.Timezone != "Europe/London" || c.Start != "2026-10-20T09:30:00+01:00" {
+		t.Errorf("customer times = %+v", c)
+	}
+}
+
+func TestSuggest_SkipsHolidays(t *testing.T) {
+	s, f := testScheduler()
+	f.caseCalls = strings.Replace(caseCalls, `"2026-10-20 08:00:00"`, `"2026-10-21 08:00:00"`, 1)
+	got, err := s.Suggest(context.Background(), SuggestRequest{CaseID: "c1", CallRequestID: "cr1", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Slots[0].Preferred {
+		t.Errorf("a preferred time on a holiday was offered: %+v", got.Slots[0])
+	}
+}
+
+func TestSuggest_Errors(t *testing.T) {
+	s, f := testScheduler()
+	cases := []struct {
+		name string
+		req  SuggestRequest
+		want error
+	}{
+		{"unknown call request", SuggestRequest{CaseID: "c1", CallRequestID: "nope"}, ErrCallRequestNotFound},
+		{"unknown calendar", SuggestRequest{CaseID: "c1", CallRequestID: "cr1", CalendarID: "mars"}, ErrUnknownCalendar},
+		{"bad timezone", SuggestRequest{CaseID: "c1", CallRequestID: "cr1", CustomerTimezone: "Mars/Olympus"}, ErrInvalidTimezone},
+		{"local timezone", SuggestRequest{CaseID: "c1", CallRequestID: "cr1", CustomerTimezone: "Local"}, ErrInvalidTimezone},
+		{"too long", SuggestRequest{CaseID: "c1", CallRequestID: "cr1", DurationMin: MaxDurationMin + 1}, ErrInvalidDuration},
+	}
+	for _, tc := range cases {
+		if _, err := s.Suggest(context.Background(), tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	f.caseCalls = strings.Replace(caseCalls, `"assignee":"alice@example.com",`, "", 1)
+	if _, err := s.Suggest(context.Background(), SuggestRequest{CaseID: "c1", CallRequestID: "cr1"}); !errors.Is(err, ErrNoAssignee) {
+		t.Errorf("no assignee: err = %v", err)
+	}
+}
+
+func TestSchedule_RefusesDoubleBooking(t *testing.T) {
+	s, f := testScheduler()
+	req := ScheduleRequest{
+		CaseID: "c1", CallRequestID: "cr1", Assignee: "alice@example.com", DurationMin: 60,
+		Start: time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC),
+	}
+	_, err := s.Schedule(context.Background(), req)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Conflicts) != 1 || conflict.Conflicts[0].CallRequestID != "cr2" {
+		t.Fatalf("err = %v, want a conflict with cr2", err)
+	}
+	if len(f.patched) != 0 {
+		t.Errorf("a refused booking was patched: %v", f.patched)
+	}
+
+	req.Override = true
+	got, err := s.Schedule(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Conflicts) != 1 || string(got.CallRequest) != `{"message":"ok"}` {
+		t.Errorf("result = %+v", got)
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(f.patched["cr1"]), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["state"] != "scheduled" || body["meetingDate"] != "2026-10-20T05:00:00Z" || body["durationInMinutes"] != float64(60) ||
+		body["assignee"] != "alice@example.com" || body["caseId"] != "c1" {
+		t.Errorf("PATCH body = %v", body)
+	}
+	if !strings.Contains(got.Invite, "DTSTART:20261020T050000Z\r\n") || !strings.Contains(got.Invite, "DTEND:20261020T060000Z\r\n") {
+		t.Errorf("invite = %q", got.Invite)
+	}
+}
+
+func TestSchedule_BackToBackIsNotAConflict(t *testing.T) {
+	s, f := testScheduler()
+	_, err := s.Schedule(context.Background(), ScheduleRequest{
+		CaseID: "c1", CallRequestID: "cr1", Assignee: "alice@example.com", DurationMin: 30,
+		Start: time.Date(2026, 10, 20, 5, 30, 0, 0, time.UTC),
+	})
+	if err != nil || f.patched["cr1"] == "" {
+		t.Fatalf("err = %v, patched = %v", err, f.patched)
+	}
+}
+
+func TestSchedule_Errors(t *testing.T) {
+	s, _ := testScheduler()
+	future := time.Date(2026, 10, 22, 5, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		req  ScheduleRequest
+		want error
+	}{
+		{"past", ScheduleRequest{CaseID: "c1", CallRequestID: "cr1", Assignee: "a", DurationMin: 30, Start: time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC)}, ErrInPast},
+		{"no assignee", ScheduleRequest{CaseID: "c1", CallRequestID: "cr1", Assignee: " ", DurationMin: 30, Start: future}, ErrNoAssignee},
+		{"no duration", ScheduleRequest{CaseID: "c1", CallRequestID: "cr1", Assignee: "a", Start: future}, ErrInvalidDuration},
+		{"unknown call request", ScheduleRequest{CaseID: "c1", CallRequestID: "nope", Assignee: "a", DurationMin: 30, Start: future}, ErrCallRequestNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := s.Schedule(context.Background(), tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestSchedule_UnknownAssignee(t *testing.T) {
+	s, f := testScheduler()
+	for _, assignee := range []string{"carol@example.com", "Alice"} {
+		_, err := s.Schedule(context.Background(), ScheduleRequest{
+			CaseID: "c1", CallRequestID: "cr1", Assignee: assignee, DurationMin: 30,
+			Start: time.Date(2026, 10, 22, 5, 0, 0, 0, time.UTC),
+		})
+		if !errors.Is(err, ErrUnknownAssignee) {
+			t.Errorf("%s: err = %v, want ErrUnknownAssignee", assignee, err)
+		}
+	}
+	if len(f.patched) != 0 {
+		t.Errorf("an unchecked booking was patched: %v", f.patched)
+	}
+
+	// A platform user id is used as it is.
+	if _, err := s.Schedule(context.Background(), ScheduleRequest{
+		CaseID: "c1", CallRequestID: "cr1", Assignee: strings.ToUpper(aliceID), DurationMin: 60,
+		Start: time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC),
+	}); !errors.As(err, new(*ConflictError)) {
+		t.Errorf("by user id: err = %v, want a conflict with cr2", err)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	s, _ := testScheduler()
+	// The duration and assignee default to the call request's own: an hour
+	// with alice.
+	got, err := s.Conflicts(context.Background(), "c1", "cr1", time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC), 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].CallRequestID != "cr2" {
+		t.Errorf("Conflicts = %+v, want cr2", got)
+	}
+
+	got, err = s.Conflicts(context.Background(), "c1", "cr1", time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC), 30, "bob@example.com")
+	if err != nil || len(got) != 1 || got[0].CallRequestID != "cr3" {
+		t.Errorf("Conflicts for bob = %+v, %v, want cr3", got, err)
+	}
+}
+
+// A busy assignee's later calls end the read at the window rather than
+// crowding out the ones in it.
+func TestBookings_StopsAfterTheWindow(t *testing.T) {
+	s, f := testScheduler()
+	var calls []string
+	for i := range pageLimit {
+		calls = append(calls, fmt.Sprintf(`{"id":"n%d","number":"N%d","case":{"id":"c"},"durationMin":30,"scheduleTime":"2026-11-%02d 04:00:00"}`, i, i, i%28+1))
+	}
+	f.scheduled[aliceID] = `{"callRequests":[` + strings.Join(calls, ",") + `],"total":1000}`
+	busy, err := s.bookings(context.Background(), "alice@example.com", "cr1",
+		time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(busy) != 1 || busy[0].CallRequestID != "n0" {
+		t.Errorf("bookings = %+v, want n0", busy)
+	}
+	if len(f.searches) != 1 {
+		t.Errorf("%d searches, want 1", len(f.searches))
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+	for _, s := range []string{"2026-10-20 08:00:00", "2026-10-20T08:00:00", "2026-10-20T13:30:00+05:30", "10/20/2026 08:00:00"} {
+		if got, ok := ParseTime(s); !ok || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %s, %v", s, got, ok)
+		}
+	}
+	if _, ok := ParseTime("tomorrow"); ok {
+		t.Error("ParseTime accepted tomorrow")
+	}
+}
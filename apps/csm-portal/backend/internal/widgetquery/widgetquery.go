@@ -0,0 +1,167 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package widgetquery resolves a dashboard widget's query against the entity
+// service with the portal backend's own credentials, the way the frontend
+// does with the signed-in user's: each dashboard.ResourceType maps to the
+// same search (and group-by) endpoint, and a count is the search's total at
+// limit 1. The threshold alert evaluator, the emailed reports and the trend
+// endpoint all resolve through it.
+package widgetquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+)
+
+// Searcher is the subset of entity.CustomerEntityClient a widget's search
+// resolves against -- one per dashboard.ResourceType's /search endpoint.
+type Searcher interface {
+	SearchCases(ctx context.Context, body []byte) ([]byte, error)
+	SearchIncidents(ctx context.Context, body []byte) ([]byte, error)
+	SearchChangeRequests(ctx context.Context, body []byte) ([]byte, error)
+	SearchProblems(ctx context.Context, body []byte) ([]byte, error)
+	SearchIncidentTasks(ctx context.Context, body []byte) ([]byte, error)
+	SearchAccounts(ctx context.Context, body []byte) ([]byte, error)
+	SearchProjects(ctx context.Context, body []byte) ([]byte, error)
+	SearchUsers(ctx context.Context, body []byte) ([]byte, error)
+	SearchTimeCards(ctx context.Context, body []byte) ([]byte, error)
+	SearchProductVulnerabilities(ctx context.Context, body []byte) ([]byte, error)
+	SearchAllCallRequests(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// Aggregator is a Searcher that also has the group-by endpoints a
+// "groupBy" pie/bar widget resolves against.
+type Aggregator interface {
+	Searcher
+	AggregateCases(ctx context.Context, body []byte) ([]byte, error)
+	AggregateIncidents(ctx context.Context, body []byte) ([]byte, error)
+	AggregateChangeRequests(ctx context.Context, body []byte) ([]byte, error)
+	AggregateProblems(ctx context.Context, body []byte) ([]byte, error)
+	AggregateIncidentTasks(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// Func is one entity call: a JSON request body in, a JSON response out.
+type Func func(context.Context, []byte) ([]byte, error)
+
+// Resource is how one widget ResourceType is fetched: the same endpoint and
+// response items key the frontend's WIDGET_RESOURCE_CONFIG maps it to.
+// Aggregate is nil for a resourceType with no group-by endpoint, or when the
+// client is not an Aggregator.
+type Resource struct {
+	Search    Func
+	Aggregate Func
+	ItemsKey  string
+}
+
+// For returns rt's Resource on c.
+func For(c Searcher, rt dashboard.ResourceType) (Resource, error) {
+	agg, canAggregate := c.(Aggregator)
+	var r Resource
+	switch rt {
+	case dashboard.ResourceCase, dashboard.ResourceServiceRequest, dashboard.ResourceSecurityReportAnalysis,
+		dashboard.ResourceAnnouncement, dashboard.ResourceEngagement:
+		r = Resource{Search: c.SearchCases, ItemsKey: "cases"}
+		if canAggregate {
+			r.Aggregate = agg.AggregateCases
+		}
+	case dashboard.ResourceIncident:
+		r = Resource{Search: c.SearchIncidents, ItemsKey: "incidents"}
+		if canAggregate {
+			r.Aggregate = agg.AggregateIncidents
+		}
+	case dashboard.ResourceChangeRequest:
+		r = Resource{Search: c.SearchChangeRequests, ItemsKey: "changeRequests"}
+		if canAggregate {
+			r.Aggregate = agg.AggregateChangeRequests
+		}
+	case dashboard.ResourceProblem:
+		r = Resource{Search: c.SearchProblems, ItemsKey: "problems"}
+		if canAggregate {
+			r.Aggregate = agg.AggregateProblems
+		}
+	case dashboard.ResourceIncidentTask:
+		r = Resource{Search: c.SearchIncidentTasks, ItemsKey: "incidentTasks"}
+		if canAggregate {
+			r.Aggregate = agg.AggregateIncidentTasks
+		}
+	case dashboard.ResourceAccount:
+		r = Resource{Search: c.SearchAccounts, ItemsKey: "accounts"}
+	case dashboard.ResourceProject:
+		r = Resource{Search: c.SearchProjects, ItemsKey: "projects"}
+	case dashboard.ResourceUser:
+		r = Resource{Search: c.SearchUsers, ItemsKey: "users"}
+	case dashboard.ResourceTimeCard:
+		r = Resource{Search: c.SearchTimeCards, ItemsKey: "timeCards"}
+	case dashboard.ResourceProductVulnerability:
+		r = Resource{Search: c.SearchProductVulnerabilities, ItemsKey: "productVulnerabilities"}
+	case dashboard.ResourceCallRequest:
+		r = Resource{Search: c.SearchAllCallRequests, ItemsKey: "callRequests"}
+	default:
+		return Resource{}, fmt.Errorf("resourceType %q has no search to resolve against", rt)
+	}
+	return r, nil
+}
+
+// SearchPayload is a search request body: query as the filters, first page
+// of limit.
+func SearchPayload(query map[string]any, limit int) map[string]any {
+	if query == nil {
+		query = map[string]any{}
+	}
+	return map[string]any{"filters": query, "pagination": map[string]int{"offset": 0, "limit": limit}}
+}
+
+// Call encodes payload and makes the call.
+func Call(ctx context.Context, call Func, payload map[string]any) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	return call(ctx, body)
+}
+
+// Total reads a search response's total, accepting either of the two names
+// the entity service's responses use for it.
+func Total(raw []byte) (float64, error) {
+	var resp struct {
+		Total        *float64 `json:"total"`
+		TotalRecords *float64 `json:"totalRecords"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return 0, fmt.Errorf("decode search response: %w", err)
+	}
+	switch {
+	case resp.Total != nil:
+		return *resp.Total, nil
+	case resp.TotalRecords != nil:
+		return *resp.TotalRecords, nil
+	}
+	return 0, fmt.Errorf("search response carries no total")
+}
+
+// Count resolves query the way a count widget is: query as the search
+// filters, limit 1, reading total off the response.
+func Count(ctx context.Context, r Resource, query map[string]any) (float64, error) {
+	raw, err := Call(ctx, r.Search, SearchPayload(query, 1))
+	if err != nil {
+		return 0, err
+	}
+	return Total(raw)
+}
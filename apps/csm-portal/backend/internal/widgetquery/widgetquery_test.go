@@ -0,0 +1,88 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package widgetquery
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/dashboard"
+)
+
+// searcher answers case searches with a fixed body and records the request.
+type searcher struct {
+	Searcher // nil: any method not overridden below panics if called
+	resp     string
+	body     []byte
+}
+
+func (s *searcher) SearchCases(_ context.Context, body []byte) ([]byte, error) {
+	s.body = body
+	return []byte(s.resp), nil
+}
+
+type aggregator struct {
+	*searcher
+	Aggregator
+}
+
+func (a aggregator) SearchCases(ctx context.Context, body []byte) ([]byte, error) {
+	return a.searcher.SearchCases(ctx, body)
+}
+
+func TestTotal(t *testing.T) {
+	for raw, want := range map[string]float64{`{"total":7}`: 7, `{"totalRecords":3,"items":[]}`: 3} {
+		if got, err := Total([]byte(raw)); err != nil || got != want {
+			t.Errorf("Total(%s) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	if _, err := Total([]byte(`{"cases":[]}`)); err == nil {
+		t.Error("Total without a total succeeded")
+	}
+}
+
+func TestCount(t *testing.T) {
+	s := &searcher{resp: `{"total":4}`}
+	r, err := For(s, dashboard.ResourceServiceRequest)
+	if err != nil {
+		t.Fatalf("For: %v", err)
+	}
+	if r.ItemsKey != "cases" || r.Aggregate != nil {
+		t.Errorf("resource = %+v, want cases with no group-by on a plain Searcher", r)
+	}
+	got, err := Count(context.Background(), r, map[string]any{"filters": []any{}})
+	if err != nil || got != 4 {
+		t.Fatalf("Count = %v, %v; want 4", got, err)
+	}
+	var req struct {
+		Pagination struct{ Limit int } `json:"pagination"`
+	}
+	if err := json.Unmarshal(s.body, &req); err != nil || req.Pagination.Limit != 1 {
+		t.Errorf("request = %s, want limit 1", s.body)
+	}
+}
+
+func TestFor(t *testing.T) {
+	r, err := For(aggregator{searcher: &searcher{}}, dashboard.ResourceCase)
+	if err != nil || r.Aggregate == nil {
+		t.Errorf("For(aggregator, case) = %+v, %v; want a group-by endpoint", r, err)
+	}
+	if _, err := For(&searcher{}, "widget"); err == nil {
+		t.Error("For(unknown resourceType) succeeded")
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package workflow holds the case lifecycle as a declarative definition: for
+// each case type, which state may move to which, and what a transition
+// demands of the case and of the caller making it.
+//
+// The lifecycle used to be a hardcoded switch in the handler package, which
+// left out every role-gated transition (team-lead override, auto-close)
+// because there was nowhere to say who may make them. A Transition's guards
+// say exactly that. The definition is deployment configuration, resolved and
+// validated once at startup like the team registry: a typo in it fails the
+// deploy instead of silently opening or closing a transition.
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+)
+
+// Case types a definition may configure a lifecycle for. CaseTypeCase is the
+// one every definition must carry: it is also the lifecycle of any case whose
+// type has none of its own, or no type at all.
+const (
+	CaseTypeCase                   = "case"
+	CaseTypeServiceRequest         = "service_request"
+	CaseTypeSecurityReportAnalysis = "security_report_analysis"
+	CaseTypeEngagement             = "engagement"
+	CaseTypeAnnouncement           = "announcement"
+)
+
+var validCaseTypes = map[string]bool{
+	CaseTypeCase:                   true,
+	CaseTypeServiceRequest:         true,
+	CaseTypeSecurityReportAnalysis: true,
+	CaseTypeEngagement:             true,
+	CaseTypeAnnouncement:           true,
+}
+
+// Case states, in the upstream data source's vocabulary.
+const (
+	StateOpen             = "open"
+	StateWorkInProgress   = "work_in_progress"
+	StateWaitingOnWSO2    = "waiting_on_wso2"
+	StateAwaitingInfo     = "awaiting_info"
+	StateSolutionProposed = "solution_proposed"
+	StateClosed           = "closed"
+	StateReopened         = "reopened"
+)
+
+// validStates is closed for the same reason directory's family set is: a
+// transition to "work_inprogress" would not error anywhere -- upstream would
+// just reject every PATCH that takes it, long after the deploy.
+var validStates = map[string]bool{
+	StateOpen:             true,
+	StateWorkInProgress:   true,
+	StateWaitingOnWSO2:    true,
+	StateAwaitingInfo:     true,
+	StateSolutionProposed: true,
+	StateClosed:           true,
+	StateReopened:         true,
+}
+
+// caseFields are the top-level keys of a case as the entity service returns
+// it, which is what RequiredFields names. Closed for the same reason as
+// validStates: a required "closeNotes" would never be present, and would
+// block the transition for everyone.
+var caseFields = map[string]bool{
+	"subject": true, "description": true, "severity": true, "issueType": true,
+	"workState": true, "type": true, "engagementType": true,
+	"project": true, "deployment": true, "deployedProduct": true,
+	"catalog": true, "catalogItem": true, "variables": true,
+	"assignedTeam": true, "assignedEngineer": true, "acknowledgedBy": true,
+	"parentCase": true, "relatedCase": true, "account": true,
+	"linkedServiceRequests": true, "linkedChangeRequests": true,
+	"resolutionCode": true, "cause": true, "resolutionNotes": true,
+	"watchList": true, "bestCaseFixEta": true, "mostLikelyFixEta": true,
+	"worstCaseFixEta": true, "tags": true,
+}
+
+// patchFieldNames maps the case fields PATCH /cases/{id} takes under
+// another name to that name.
+var patchFieldNames = map[string]string{"resolutionNotes": "closeNotes"}
+
+// Definition is the configuration form of a workflow: one Lifecycle per case
+// type, keyed by the type.
+type Definition struct {
+	CaseTypes map[string]Lifecycle `json:"caseTypes"`
+}
+
+// Lifecycle is one case type's state machine. Transitions are listed in the
+// order next states are offered to the frontend.
+type Lifecycle struct {
+	Transitions []Transition `json:"transitions"`
+}
+
+// Transition permits a case in From to be moved to To. Its guards are ANDed:
+// a caller must satisfy Roles AND TeamFamilies, and the case must carry every
+// RequiredFields entry. Within Roles or TeamFamilies any one match suffices.
+// An empty guard does not restrict.
+type Transition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// RequiredFields are case fields (top-level keys of the case as the
+	// entity service returns it, e.g. "resolutionNotes") that must be
+	// non-empty once the PATCH is applied -- set by the PATCH itself (under
+	// its PATCH name, e.g. "closeNotes") or already set on the case.
+	RequiredFields []string `json:"requiredFields,omitempty"`
+	// Roles are platform roles (see directory.DefaultRoles), matched against
+	// the caller's own roles from GET /users/me.
+	Roles []string `json:"roles,omitempty"`
+	// TeamFamilies are team families (see directory.Family), matched against
+	// the families of the registry teams the caller belongs to.
+	TeamFamilies []directory.Family `json:"teamFamilies,omitempty"`
+}
+
+// CallerGuarded reports whether the transition depends on who the caller is,
+// and so whether the caller has to be resolved to check it.
+func (t Transition) CallerGuarded() bool {
+	return len(t.Roles) > 0 || len(t.TeamFamilies) > 0
+}
+
+// Caller is who is making a transition, as far as the guards care.
+type Caller struct {
+	Roles    []string
+	Families []directory.Family
+}
+
+// Permits reports whether c satisfies the transition's role and team-family
+// guards. A nil c (the caller could not be resolved) only passes a
+// transition with neither guard, so an identity lookup failure fails closed.
+func (t Transition) Permits(c *Caller) bool {
+	if !t.CallerGuarded() {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+	if len(t.Roles) > 0 && !slices.ContainsFunc(t.Roles, func(r string) bool { return slices.Contains(c.Roles, r) }) {
+		return false
+	}
+	if len(t.TeamFamilies) > 0 && !slices.ContainsFunc(t.TeamFamilies, func(f directory.Family) bool { return slices.Contains(c.Families, f) }) {
+		return false
+	}
+	return true
+}
+
+// MissingFields returns the RequiredFields that are absent or empty in the
+// case as it would be after patch is applied over current. Both are the
+// top-level objects of their JSON documents; either may be nil. A field is
+// empty when it is null, "", [] or {}.
+func (t Transition) MissingFields(current, patch map[string]json.RawMessage) []string {
+	var missing []string
+	for _, f := range t.RequiredFields {
+		raw, ok := patch[f]
+		if !ok && patchFieldNames[f] != "" {
+			raw, ok = patch[patchFieldNames[f]]
+		}
+		if !ok {
+			raw = current[f]
+		}
+		if isEmptyJSON(raw) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+func isEmptyJSON(raw json.RawMessage) bool {
+	v := bytes.TrimSpace(raw)
+	switch string(v) {
+	case "", "null", `""`, "[]", "{}":
+		return true
+	}
+	var s string
+	if json.Unmarshal(v, &s) == nil {
+		return strings.TrimSpace(s) == ""
+	}
+	return false
+}
+
+// Workflow is a validated Definition, indexed for lookup. It is read-only
+// after Compile, so it needs no locking.
+type Workflow struct {
+	// byType maps case type -> from state -> transitions out of it, in
+	// definition order.
+	byType map[string]map[string][]Transition
+	dir    *directory.Directory
+}
+
+// Default is the lifecycle the portal enforced before it was configurable,
+// applied to every case type: no transition is guarded, and closed is
+// terminal -- the upstream data source rejects any outbound transition from
+// a closed case.
+var Default = Definition{CaseTypes: map[string]Lifecycle{
+	CaseTypeCase: {Transitions: []Transition{
+		{From: StateOpen, To: StateWorkInProgress},
+		{From: StateWorkInProgress, To: StateWaitingOnWSO2},
+		{From: StateWorkInProgress, To: StateAwaitingInfo},
+		{From: StateWorkInProgress, To: StateSolutionProposed},
+		{From: StateWorkInProgress, To: StateClosed},
+		{From: StateWaitingOnWSO2, To: StateWorkInProgress},
+		{From: StateAwaitingInfo, To: StateWaitingOnWSO2},
+		{From: StateSolutionProposed, To: StateClosed},
+		{From: StateSolutionProposed, To: StateWaitingOnWSO2},
+		{From: StateReopened, To: StateWorkInProgress},
+	}},
+}}
+
+// Parse decodes a Definition from its JSON configuration form. Unknown keys
+// are an error: a misspelt "requiredField" would otherwise drop the guard
+// without a word.
+func Parse(raw []byte) (Definition, error) {
+	var d Definition
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&d); err != nil {
+		return Definition{}, fmt.Errorf("case workflow: %w", err)
+	}
+	return d, nil
+}
+
+// Compile validates d and indexes it. dir resolves the caller's team
+// families and is the allow-list role guards are checked against; it may be
+// nil only for a definition with no role or team-family guard.
+//
+// Every error names the case type and transition at fault. Callers are
+// expected to treat any error as fatal at startup.
+func Compile(d Definition, dir *directory.Directory) (*Workflow, error) {
+	if _, ok := d.CaseTypes[CaseTypeCase]; !ok {
+		return nil, fmt.Errorf("case workflow: no lifecycle for case type %q, which every other type falls back to", CaseTypeCase)
+	}
+	w := &Workflow{byType: make(map[string]map[string][]Transition, len(d.CaseTypes)), dir: dir}
+	for caseType, lc := range d.CaseTypes {
+		if !validCaseTypes[caseType] {
+			return nil, fmt.Errorf("case workflow: unknown case type %q", caseType)
+		}
+		if len(lc.Transitions) == 0 {
+			return nil, fmt.Errorf("case workflow: case type %q has no transitions", caseType)
+		}
+		byFrom := make(map[string][]Transition)
+		for i, t := range lc.Transitions {
+			where := fmt.Sprintf("case workflow: %s transition %d (%s -> %s)", caseType, i, t.From, t.To)
+			compiled, err := compileTransition(t, dir)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", where, err)
+			}
+			if slices.ContainsFunc(byFrom[t.From], func(o Transition) bool { return o.To == t.To }) {
+				return nil, fmt.Errorf("%s: is defined more than once", where)
+			}
+			byFrom[t.From] = append(byFrom[t.From], compiled)
+		}
+		w.byType[caseType] = byFrom
+	}
+	return w, nil
+}
+
+func compileTransition(t Transition, dir *directory.Directory) (Transition, error) {
+	if !validStates[t.From] {
+		return Transition{}, fmt.Errorf("unknown state %q", t.From)
+	}
+	if !validStates[t.To] {
+		return Transition{}, fmt.Errorf("unknown state %q", t.To)
+	}
+	if t.From == t.To {
+		return Transition{}, fmt.Errorf("a transition must change the state")
+	}
+	if t.From == StateClosed {
+		return Transition{}, fmt.Errorf("%q is terminal upstream; no transition may leave it", StateClosed)
+	}
+	for _, f := range t.RequiredFields {
+		if strings.TrimSpace(f) == "" {
+			return Transition{}, fmt.Errorf("requiredFields has an empty entry")
+		}
+		if !caseFields[f] {
+			return Transition{}, fmt.Errorf("requiredFields entry %q is not a case field as GET /cases/{id} returns it", f)
+		}
+	}
+	if t.CallerGuarded() && dir == nil {
+		return Transition{}, fmt.Errorf("role and team-family guards need the directory")
+	}
+	for _, r := range t.Roles {
+		if !dir.IsValidRole(r) {
+			return Transition{}, fmt.Errorf("role %q is not in the role allow-list", r)
+		}
+	}
+	families := make([]directory.Family, 0, len(t.TeamFamilies))
+	for _, f := range t.TeamFamilies {
+		fam, err := directory.ParseFamily(string(f))
+		if err != nil {
+			return Transition{}, err
+		}
+		if fam == "" {
+			return Transition{}, fmt.Errorf("teamFamilies has an empty entry")
+		}
+		families = append(families, fam)
+	}
+	t.TeamFamilies = families
+	return t, nil
+}
+
+// lifecycle returns caseType's transitions by from state, falling back to the
+// "case" lifecycle for a type the definition does not configure.
+func (w *Workflow) lifecycle(caseType string) map[string][]Transition {
+	if lc, ok := w.byType[caseType]; ok {
+		return lc
+	}
+	return w.byType[CaseTypeCase]
+}
+
+// NextStates returns every state a case of caseType in state can move to,
+// regardless of guards. An unknown state has none.
+func (w *Workflow) NextStates(caseType, state string) []string {
+	out := []string{}
+	for _, t := range w.lifecycle(caseType)[state] {
+		out = append(out, t.To)
+	}
+	return out
+}
+
+// Transition returns the transition from -> to for caseType, if one is
+// defined.
+func (w *Workflow) Transition(caseType, from, to string) (Transition, bool) {
+	for _, t := range w.lifecycle(caseType)[from] {
+		if t.To == to {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// NeedsCaller reports whether any transition out of state is role- or
+// team-guarded, so a caller only has to resolve who they are when it
+// matters.
+func (w *Workflow) NeedsCaller(caseType, state string) bool {
+	return slices.ContainsFunc(w.lifecycle(caseType)[state], Transition.CallerGuarded)
+}
+
+// LegalNextStates returns the subset of NextStates c is permitted to make.
+// Required-field guards are not applied: they depend on the PATCH, not on the
+// caller, and are enforced when it is made.
+func (w *Workflow) LegalNextStates(caseType, state string, c *Caller) []string {
+	out := []string{}
+	for _, t := range w.lifecycle(caseType)[state] {
+		if t.Permits(c) {
+			out = append(out, t.To)
+		}
+	}
+	return out
+}
+
+// ResolveCaller builds a Caller from the caller's platform roles and the
+// names of the groups they belong to; only groups that are registry teams
+// with a family contribute one.
+func (w *Workflow) ResolveCaller(roles, groupNames []string) *Caller {
+	c := &Caller{Roles: roles}
+	if w.dir == nil {
+		return c
+	}
+	for _, name := range groupNames {
+		if team, ok := w.dir.TeamByGroupName(name); ok && team.Family != "" {
+			c.Families = append(c.Families, team.Family)
+		}
+	}
+	return c
+}
+
+// defaultWorkflow is Default, compiled. Default has no caller guards, so it
+// compiles without a directory.
+var defaultWorkflow = func() *Workflow {
+	w, err := Compile(Default, nil)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}()
+
+var (
+	activeMu sync.RWMutex
+	active   *Workflow
+)
+
+// SetActive installs the workflow the handlers enforce. It is called once
+// during startup by cmd/server/main.go (and by tests); nil restores Default.
+func SetActive(w *Workflow) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active = w
+}
+
+// Active returns the installed workflow, or Default when none is installed.
+func Active() *Workflow {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	if active == nil {
+		return defaultWorkflow
+	}
+	return active
+}
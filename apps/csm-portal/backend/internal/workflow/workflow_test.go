@@ -0,0 +1,212 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/apps/csm-portal/backend/internal/directory"
+)
+
+func testDirectory(t *testing.T) *directory.Directory {
+	t.Helper()
+	dir, err := directory.New([]directory.Team{
+		{Key: "sre-a", Name: "SRE Team A", Family: directory.FamilySREAbt},
+		{Key: "plain", Name: "Plain Team"},
+	}, directory.DefaultRoles)
+	if err != nil {
+		t.Fatalf("directory.New: %v", err)
+	}
+	return dir
+}
+
+func mustCompile(t *testing.T, raw string, dir *directory.Directory) *Workflow {
+	t.Helper()
+	d, err := Parse([]byte(raw))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	w, err := Compile(d, dir)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	return w
+}
+
+func TestDefaultMatchesLegacyLifecycle(t *testing.T) {
+	w := Active()
+	cases := map[string][]string{
+		StateOpen:             {StateWorkInProgress},
+		StateWorkInProgress:   {StateWaitingOnWSO2, StateAwaitingInfo, StateSolutionProposed, StateClosed},
+		StateWaitingOnWSO2:    {StateWorkInProgress},
+		StateAwaitingInfo:     {StateWaitingOnWSO2},
+		StateSolutionProposed: {StateClosed, StateWaitingOnWSO2},
+		StateClosed:           {},
+		StateReopened:         {StateWorkInProgress},
+		"bogus":               {},
+	}
+	for state, want := range cases {
+		// Every type falls back to the "case" lifecycle, as does no type.
+		for _, caseType := range []string{CaseTypeCase, CaseTypeEngagement, ""} {
+			if got := w.NextStates(caseType, state); !slices.Equal(got, want) {
+				t.Errorf("NextStates(%q, %q) = %v, want %v", caseType, state, got, want)
+			}
+		}
+	}
+}
+
+func TestCompileRejects(t *testing.T) {
+	dir := testDirectory(t)
+	cases := []struct {
+		name, raw, want string
+	}{
+		{"no case lifecycle", `{"caseTypes":{"engagement":{"transitions":[{"from":"open","to":"closed"}]}}}`, `no lifecycle for case type "case"`},
+		{"unknown case type", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed"}]},"incident":{"transitions":[{"from":"open","to":"closed"}]}}}`, `unknown case type "incident"`},
+		{"empty lifecycle", `{"caseTypes":{"case":{"transitions":[]}}}`, "has no transitions"},
+		{"unknown state", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"work_inprogress"}]}}}`, `unknown state "work_inprogress"`},
+		{"self transition", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"open"}]}}}`, "must change the state"},
+		{"leaves closed", `{"caseTypes":{"case":{"transitions":[{"from":"closed","to":"reopened"}]}}}`, "terminal"},
+		{"duplicate", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed"},{"from":"open","to":"closed","roles":["admin"]}]}}}`, "more than once"},
+		{"unknown role", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed","roles":["team_lead"]}]}}}`, `role "team_lead"`},
+		{"unknown family", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed","teamFamilies":["sre_abt"]}]}}}`, `unknown family "sre_abt"`},
+		{"empty required field", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed","requiredFields":[" "]}]}}}`, "empty entry"},
+		{"unknown required field", `{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed","requiredFields":["closeNotes"]}]}}}`, `"closeNotes" is not a case field`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := Parse([]byte(tc.raw))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			_, err = Compile(d, dir)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("Compile error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := Parse([]byte(`{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed","requiredField":["cause"]}]}}}`))
+		if err == nil || !strings.Contains(err.Error(), "requiredField") {
+			t.Fatalf("Parse error = %v, want an unknown-field error", err)
+		}
+	})
+
+	t.Run("caller guard without a directory", func(t *testing.T) {
+		d, _ := Parse([]byte(`{"caseTypes":{"case":{"transitions":[{"from":"open","to":"closed","roles":["admin"]}]}}}`))
+		if _, err := Compile(d, nil); err == nil {
+			t.Fatal("Compile with a role guard and no directory: want an error")
+		}
+	})
+}
+
+func TestGuards(t *testing.T) {
+	w := mustCompile(t, `{"caseTypes":{
+		"case":{"transitions":[
+			{"from":"work_in_progress","to":"closed","requiredFields":["resolutionNotes","cause"]},
+			{"from":"work_in_progress","to":"solution_proposed"},
+			{"from":"open","to":"closed","roles":["admin"],"teamFamilies":["SRE-ABT"]}
+		]},
+		"engagement":{"transitions":[{"from":"open","to":"closed"}]}
+	}}`, testDirectory(t))
+
+	t.Run("type-specific lifecycle", func(t *testing.T) {
+		if got := w.NextStates(CaseTypeEngagement, StateWorkInProgress); len(got) != 0 {
+			t.Errorf("engagement NextStates(work_in_progress) = %v, want none", got)
+		}
+		if got := w.NextStates(CaseTypeServiceRequest, StateWorkInProgress); !slices.Equal(got, []string{StateClosed, StateSolutionProposed}) {
+			t.Errorf("service_request falls back to case: got %v", got)
+		}
+	})
+
+	t.Run("role and team family are both required", func(t *testing.T) {
+		tr, ok := w.Transition(CaseTypeCase, StateOpen, StateClosed)
+		if !ok {
+			t.Fatal("transition open -> closed not found")
+		}
+		cases := []struct {
+			name   string
+			caller *Caller
+			want   bool
+		}{
+			{"unresolved caller", nil, false},
+			{"role only", w.ResolveCaller([]string{"admin"}, []string{"Plain Team"}), false},
+			{"team only", w.ResolveCaller([]string{"agent"}, []string{"SRE Team A"}), false},
+			{"both", w.ResolveCaller([]string{"agent", "admin"}, []string{"Other", "SRE Team A"}), true},
+		}
+		for _, tc := range cases {
+			if got := tr.Permits(tc.caller); got != tc.want {
+				t.Errorf("%s: Permits = %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("legal next states apply caller guards only", func(t *testing.T) {
+		if !w.NeedsCaller(CaseTypeCase, StateOpen) || w.NeedsCaller(CaseTypeCase, StateWorkInProgress) {
+			t.Error("NeedsCaller should be true only where a transition is caller-guarded")
+		}
+		if got := w.LegalNextStates(CaseTypeCase, StateOpen, nil); len(got) != 0 {
+			t.Errorf("LegalNextStates(open, nil) = %v, want none", got)
+		}
+		if got := w.LegalNextStates(CaseTypeCase, StateWorkInProgress, nil); !slices.Equal(got, []string{StateClosed, StateSolutionProposed}) {
+			t.Errorf("LegalNextStates(work_in_progress, nil) = %v", got)
+		}
+	})
+
+	t.Run("required fields read the patch over the current case", func(t *testing.T) {
+		tr, _ := w.Transition(CaseTypeCase, StateWorkInProgress, StateClosed)
+		obj := func(s string) map[string]json.RawMessage {
+			var m map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(s), &m); err != nil {
+				t.Fatal(err)
+			}
+			return m
+		}
+		cases := []struct {
+			name, current, patch string
+			want                 []string
+		}{
+			{"neither set", `{"state":"work_in_progress"}`, `{"state":"closed"}`, []string{"resolutionNotes", "cause"}},
+			{"set on the case", `{"resolutionNotes":"done","cause":{"id":"c1"}}`, `{"state":"closed"}`, nil},
+			{"set by the patch", `{"resolutionNotes":null}`, `{"state":"closed","resolutionNotes":"done","cause":"config"}`, nil},
+			{"blanked by the patch", `{"resolutionNotes":"done","cause":"config"}`, `{"state":"closed","resolutionNotes":"  "}`, []string{"resolutionNotes"}},
+			{"empty object", `{"resolutionNotes":"done","cause":{}}`, `{"state":"closed"}`, []string{"cause"}},
+			// PATCH takes resolutionNotes as closeNotes.
+			{"set by the patch under its PATCH name", `{"resolutionNotes":null,"cause":"config"}`, `{"state":"closed","closeNotes":"done"}`, nil},
+			{"blanked by the patch under its PATCH name", `{"resolutionNotes":"done","cause":"config"}`, `{"state":"closed","closeNotes":""}`, []string{"resolutionNotes"}},
+		}
+		for _, tc := range cases {
+			if got := tr.MissingFields(obj(tc.current), obj(tc.patch)); !slices.Equal(got, tc.want) {
+				t.Errorf("%s: MissingFields = %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	})
+}
+
+// The committed case-workflow.example.json is what .env.example points
+// CASE_WORKFLOW_FILE at, so it must compile against the default role list.
+func TestExampleDefinitionCompiles(t *testing.T) {
+	raw, err := os.ReadFile("../../case-workflow.example.json")
+	if err != nil {
+		t.Fatalf("read example: %v", err)
+	}
+	mustCompile(t, string(raw), testDirectory(t))
+}
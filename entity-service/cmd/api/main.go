@@ -27,9 +27,11 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/calendar"
 	"github.com/wso2-open-operations/cs-tools/entity-service/internal/config"
 	"github.com/wso2-open-operations/cs-tools/entity-service/internal/db"
 	"github.com/wso2-open-operations/cs-tools/entity-service/internal/server"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/service"
 )
 
 func main() {
@@ -42,6 +44,12 @@ func main() {
 		log.Fatalf("invalid configuration: %v", err)
 	}
 
+	calendars, err := calendar.Load(cfg.BusinessCalendarsFile)
+	if err != nil {
+		log.Fatalf("load business calendars: %v", err)
+	}
+	service.SetBusinessCalendars(calendars)
+
 	pool, err := db.NewPoolIfNeeded(cfg)
 	if err != nil {
 		log.Fatalf("connect to database: %v", err)
@@ -0,0 +1,361 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package calendar models regional business calendars -- working days,
+// working hours and holidays in a time zone -- and the business-time
+// arithmetic built on them: counting business days back from a date and
+// adding business time to an instant.
+package calendar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultID is the ID of the built-in calendar used when no calendars file
+// is configured.
+const DefaultID = "default"
+
+// dateLayout is the layout of holiday dates and of the civil dates the
+// calendar arithmetic works on.
+const dateLayout = "2006-01-02"
+
+// weekdayNames maps the config file's day names to time.Weekday.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+var (
+	idPattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	clockPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):([0-5][0-9])$`)
+)
+
+// Holiday is a non-working date in a holiday list.
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// Calendar is one region's business calendar. Business time runs from Start
+// to End on every working day that is not a holiday, in Location.
+type Calendar struct {
+	ID       string
+	Name     string
+	Location *time.Location
+	// Start and End are the working hours, as offsets from local midnight.
+	Start, End time.Duration
+
+	workdays [7]bool
+	holidays map[string]string
+}
+
+// IsBusinessDay reports whether the calendar day t falls on, in t's own
+// location, is a working day and not a holiday. Convert an instant with
+// t.In(c.Location) first to ask about the calendar's own day.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if !c.workdays[t.Weekday()] {
+		return false
+	}
+	_, holiday := c.holidays[t.Format(dateLayout)]
+	return !holiday
+}
+
+// Holiday returns the name of the holiday on the calendar day t falls on, in
+// t's own location.
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	name, ok := c.holidays[t.Format(dateLayout)]
+	return name, ok
+}
+
+// Workdays returns the working days, Sunday first.
+func (c *Calendar) Workdays() []time.Weekday {
+	var out []time.Weekday
+	for d, ok := range c.workdays {
+		if ok {
+			out = append(out, time.Weekday(d))
+		}
+	}
+	return out
+}
+
+// Holidays returns the calendar's holidays in date order.
+func (c *Calendar) Holidays() []Holiday {
+	out := make([]Holiday, 0, len(c.holidays))
+	for date, name := range c.holidays {
+		out = append(out, Holiday{Date: date, Name: name})
+	}
+	slices.SortFunc(out, func(a, b Holiday) int { return strings.Compare(a.Date, b.Date) })
+	return out
+}
+
+// BusinessDaysBefore returns the calendar day n business days before the day
+// t falls on, in t's own location, at midnight. n=0 returns t's own day
+// whether or not it is a business day; a weekend day counted back from
+// Monday is skipped, so one business day before a Monday is the Friday.
+func (c *Calendar) BusinessDaysBefore(t time.Time, n int) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for n > 0 {
+		day = day.AddDate(0, 0, -1)
+		if c.IsBusinessDay(day) {
+			n--
+		}
+	}
+	return day
+}
+
+// AddBusinessTime returns the instant days business days and then d of
+// working hours after start, in the calendar's location. A start outside
+// working hours counts from the next opening, so adding 1h to a Friday
+// evening ends an hour into Monday. Adding whole days keeps the time of day.
+// With days and d both zero, it returns the first business instant at or
+// after start.
+func (c *Calendar) AddBusinessTime(start time.Time, days int, d time.Duration) time.Time {
+	t := c.nextBusinessInstant(start.In(c.Location))
+	for ; days > 0; days-- {
+		next := c.nextBusinessDay(t)
+		t = time.Date(next.Year(), next.Month(), next.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), c.Location)
+	}
+	for {
+		left := c.closing(t).Sub(t)
+		if d <= left {
+			return t.Add(d)
+		}
+		d -= left
+		t = c.opening(c.nextBusinessDay(t))
+	}
+}
+
+// nextBusinessInstant returns t if it falls within working hours on a
+// business day, else the next opening.
+func (c *Calendar) nextBusinessInstant(t time.Time) time.Time {
+	if c.IsBusinessDay(t) {
+		if open := c.opening(t); t.Before(open) {
+			return open
+		}
+		if !t.After(c.closing(t)) {
+			return t
+		}
+	}
+	return c.opening(c.nextBusinessDay(t))
+}
+
+// nextBusinessDay returns midnight of the first business day after t's day.
+func (c *Calendar) nextBusinessDay(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.Location)
+	for {
+		day = day.AddDate(0, 0, 1)
+		if c.IsBusinessDay(day) {
+			return day
+		}
+	}
+}
+
+// opening and closing return the start and end of working hours on t's day.
+// They are built from the wall clock, not added to midnight, so a daylight
+// saving change keeps the hours where the config put them.
+func (c *Calendar) opening(t time.Time) time.Time { return c.clock(t, c.Start) }
+func (c *Calendar) closing(t time.Time) time.Time { return c.clock(t, c.End) }
+
+func (c *Calendar) clock(t time.Time, offset time.Duration) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, int(offset/time.Minute), 0, 0, c.Location)
+}
+
+// Set is the configured calendars, keyed by ID, with one of them the
+// default used wherever no calendar is named.
+type Set struct {
+	calendars map[string]*Calendar
+	def       *Calendar
+}
+
+// Builtin returns the set used when no calendars file is configured: a
+// single calendar, DefaultID, working 09:00-17:00 Monday to Friday in UTC
+// with no holidays.
+func Builtin() *Set {
+	c := &Calendar{ID: DefaultID, Name: "Default", Location: time.UTC, Start: 9 * time.Hour, End: 17 * time.Hour}
+	for d := time.Monday; d <= time.Friday; d++ {
+		c.workdays[d] = true
+	}
+	return &Set{calendars: map[string]*Calendar{DefaultID: c}, def: c}
+}
+
+// Get returns the calendar with the given ID.
+func (s *Set) Get(id string) (*Calendar, bool) {
+	c, ok := s.calendars[id]
+	return c, ok
+}
+
+// Default returns the default calendar.
+func (s *Set) Default() *Calendar { return s.def }
+
+// All returns every calendar, ordered by ID.
+func (s *Set) All() []*Calendar {
+	out := make([]*Calendar, 0, len(s.calendars))
+	for _, c := range s.calendars {
+		out = append(out, c)
+	}
+	slices.SortFunc(out, func(a, b *Calendar) int { return strings.Compare(a.ID, b.ID) })
+	return out
+}
+
+// fileConfig is the calendars file. Holiday lists are named separately from
+// the calendars so that regions sharing public holidays share one list.
+type fileConfig struct {
+	DefaultCalendar string               `json:"defaultCalendar"`
+	HolidayLists    map[string][]Holiday `json:"holidayLists"`
+	Calendars       []calendarConfig     `json:"calendars"`
+}
+
+type calendarConfig struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Timezone     string   `json:"timezone"`
+	WorkingDays  []string `json:"workingDays"`
+	WorkingHours struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	} `json:"workingHours"`
+	HolidayLists []string `json:"holidayLists"`
+}
+
+// Load reads the calendars file at path. An empty path returns Builtin().
+func Load(path string) (*Set, error) {
+	if path == "" {
+		return Builtin(), nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("calendars: %w", err)
+	}
+	return Parse(raw)
+}
+
+// Parse parses and validates a calendars file.
+func Parse(raw []byte) (*Set, error) {
+	var cfg fileConfig
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("calendars: %w", err)
+	}
+	if len(cfg.Calendars) == 0 {
+		return nil, fmt.Errorf("calendars: no calendars defined")
+	}
+
+	for list, holidays := range cfg.HolidayLists {
+		for _, h := range holidays {
+			if _, err := time.Parse(dateLayout, h.Date); err != nil {
+				return nil, fmt.Errorf("calendars: holiday list %q: date %q is not YYYY-MM-DD", list, h.Date)
+			}
+		}
+	}
+
+	s := &Set{calendars: make(map[string]*Calendar, len(cfg.Calendars))}
+	for _, cc := range cfg.Calendars {
+		c, err := buildCalendar(cc, cfg.HolidayLists)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := s.calendars[c.ID]; dup {
+			return nil, fmt.Errorf("calendars: duplicate calendar %q", c.ID)
+		}
+		s.calendars[c.ID] = c
+	}
+
+	switch {
+	case cfg.DefaultCalendar != "":
+		c, ok := s.calendars[cfg.DefaultCalendar]
+		if !ok {
+			return nil, fmt.Errorf("calendars: defaultCalendar %q is not a defined calendar", cfg.DefaultCalendar)
+		}
+		s.def = c
+	case len(s.calendars) == 1:
+		s.def = s.calendars[cfg.Calendars[0].ID]
+	default:
+		return nil, fmt.Errorf("calendars: defaultCalendar is required when more than one calendar is defined")
+	}
+	return s, nil
+}
+
+func buildCalendar(cc calendarConfig, lists map[string][]Holiday) (*Calendar, error) {
+	if !idPattern.MatchString(cc.ID) {
+		return nil, fmt.Errorf("calendars: calendar id %q must be lowercase letters, digits and hyphens", cc.ID)
+	}
+	c := &Calendar{ID: cc.ID, Name: cc.Name, holidays: map[string]string{}}
+	if c.Name == "" {
+		c.Name = c.ID
+	}
+
+	// Reject "Local": the host's zone is not a region anyone can name.
+	if cc.Timezone == "" || cc.Timezone == "Local" {
+		return nil, fmt.Errorf("calendars: calendar %q: timezone is required", cc.ID)
+	}
+	loc, err := time.LoadLocation(cc.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("calendars: calendar %q: unknown timezone %q", cc.ID, cc.Timezone)
+	}
+	c.Location = loc
+
+	if len(cc.WorkingDays) == 0 {
+		return nil, fmt.Errorf("calendars: calendar %q: workingDays is required", cc.ID)
+	}
+	for _, name := range cc.WorkingDays {
+		d, ok := weekdayNames[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("calendars: calendar %q: unknown working day %q", cc.ID, name)
+		}
+		c.workdays[d] = true
+	}
+
+	if c.Start, err = parseClock(cc.WorkingHours.Start); err != nil {
+		return nil, fmt.Errorf("calendars: calendar %q: workingHours.start: %w", cc.ID, err)
+	}
+	if c.End, err = parseClock(cc.WorkingHours.End); err != nil {
+		return nil, fmt.Errorf("calendars: calendar %q: workingHours.end: %w", cc.ID, err)
+	}
+	if c.End <= c.Start {
+		return nil, fmt.Errorf("calendars: calendar %q: workingHours must end after they start", cc.ID)
+	}
+
+	for _, list := range cc.HolidayLists {
+		holidays, ok := lists[list]
+		if !ok {
+			return nil, fmt.Errorf("calendars: calendar %q: unknown holiday list %q", cc.ID, list)
+		}
+		for _, h := range holidays {
+			c.holidays[h.Date] = h.Name
+		}
+	}
+	return c, nil
+}
+
+// parseClock parses an "HH:MM" time of day into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	h, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	return time.Duration(h)*time.Hour + time.Duration(min)*time.Minute, nil
+}
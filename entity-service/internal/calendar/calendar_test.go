@@ -0,0 +1,187 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package calendar
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadExample(t *testing.T) *Set {
+	t.Helper()
+	raw, err := os.ReadFile("../../calendars.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse(example): %v", err)
+	}
+	return s
+}
+
+func TestParse_Example(t *testing.T) {
+	s := loadExample(t)
+	if s.Default().ID != "lk" {
+		t.Errorf("default = %q, want lk", s.Default().ID)
+	}
+	lk, ok := s.Get("lk")
+	if !ok {
+		t.Fatal("lk calendar missing")
+	}
+	if lk.Location.String() != "Asia/Colombo" || lk.Start != 8*time.Hour+30*time.Minute || lk.End != 17*time.Hour+30*time.Minute {
+		t.Errorf("lk = %s %s-%s", lk.Location, lk.Start, lk.End)
+	}
+	if name, ok := lk.Holiday(time.Date(2026, 12, 25, 0, 0, 0, 0, lk.Location)); !ok || name != "Christmas Day" {
+		t.Errorf("Holiday(2026-12-25) = %q, %v", name, ok)
+	}
+	var ids []string
+	for _, c := range s.All() {
+		ids = append(ids, c.ID)
+	}
+	if got := strings.Join(ids, ","); got != "lk,uk,us-east" {
+		t.Errorf("All = %s", got)
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	hours := `"workingHours":{"start":"09:00","end":"17:00"}`
+	cases := map[string]string{
+		"no calendars":      `{"calendars":[]}`,
+		"bad id":            `{"calendars":[{"id":"LK","timezone":"UTC","workingDays":["mon"],` + hours + `}]}`,
+		"duplicate id":      `{"defaultCalendar":"a","calendars":[{"id":"a","timezone":"UTC","workingDays":["mon"],` + hours + `},{"id":"a","timezone":"UTC","workingDays":["mon"],` + hours + `}]}`,
+		"no timezone":       `{"calendars":[{"id":"a","workingDays":["mon"],` + hours + `}]}`,
+		"local timezone":    `{"calendars":[{"id":"a","timezone":"Local","workingDays":["mon"],` + hours + `}]}`,
+		"unknown timezone":  `{"calendars":[{"id":"a","timezone":"Mars/Olympus","workingDays":["mon"],` + hours + `}]}`,
+		"no working days":   `{"calendars":[{"id":"a","timezone":"UTC","workingDays":[],` + hours + `}]}`,
+		"unknown day":       `{"calendars":[{"id":"a","timezone":"UTC","workingDays":["funday"],` + hours + `}]}`,
+		"bad clock":         `{"calendars":[{"id":"a","timezone":"UTC","workingDays":["mon"],"workingHours":{"start":"9am","end":"17:00"}}]}`,
+		"hours backwards":   `{"calendars":[{"id":"a","timezone":"UTC","workingDays":["mon"],"workingHours":{"start":"17:00","end":"09:00"}}]}`,
+		"unknown list":      `{"calendars":[{"id":"a","timezone":"UTC","workingDays":["mon"],` + hours + `,"holidayLists":["nope"]}]}`,
+		"bad holiday date":  `{"holidayLists":{"x":[{"date":"25/12/2026","name":"Xmas"}]},"calendars":[{"id":"a","timezone":"UTC","workingDays":["mon"],` + hours + `}]}`,
+		"ambiguous default": `{"calendars":[{"id":"a","timezone":"UTC","workingDays":["mon"],` + hours + `},{"id":"b","timezone":"UTC","workingDays":["mon"],` + hours + `}]}`,
+		"undefined default": `{"defaultCalendar":"z","calendars":[{"id":"a","timezone":"UTC","workingDays":["mon"],` + hours + `}]}`,
+		"unknown field":     `{"calendars":[{"id":"a","tz":"UTC","workingDays":["mon"],` + hours + `}]}`,
+		"not a JSON object": `[]`,
+	}
+	for name, raw := range cases {
+		if _, err := Parse([]byte(raw)); err == nil {
+			t.Errorf("%s: Parse accepted %s", name, raw)
+		} else if !strings.HasPrefix(err.Error(), "calendars:") {
+			t.Errorf("%s: error %q does not say where it came from", name, err)
+		}
+	}
+}
+
+func TestBusinessDaysBefore(t *testing.T) {
+	lk, _ := loadExample(t).Get("lk")
+	day := func(s string) time.Time {
+		d, err := time.ParseInLocation(dateLayout, s, lk.Location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	cases := []struct {
+		from string
+		n    int
+		want string
+	}{
+		{"2026-12-29", 0, "2026-12-29"},
+		{"2026-12-27", 0, "2026-12-27"}, // a Sunday is still today
+		{"2026-12-29", 1, "2026-12-28"},
+		{"2026-12-28", 1, "2026-12-24"}, // skips the weekend and Christmas Day
+		{"2026-12-28", 3, "2026-12-22"},
+	}
+	for _, tc := range cases {
+		if got := lk.BusinessDaysBefore(day(tc.from), tc.n).Format(dateLayout); got != tc.want {
+			t.Errorf("BusinessDaysBefore(%s, %d) = %s, want %s", tc.from, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestAddBusinessTime(t *testing.T) {
+	s := loadExample(t)
+	lk, _ := s.Get("lk")
+	at := func(c *Calendar, s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, c.Location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	cases := []struct {
+		name  string
+		start string
+		days  int
+		d     time.Duration
+		want  string
+	}{
+		{"within the day", "2026-12-22 10:00", 0, 2 * time.Hour, "2026-12-22 12:00"},
+		{"spills into the next day", "2026-12-22 16:30", 0, 2 * time.Hour, "2026-12-23 09:30"},
+		{"ends exactly at closing", "2026-12-22 16:30", 0, time.Hour, "2026-12-22 17:30"},
+		{"before opening", "2026-12-22 06:00", 0, time.Hour, "2026-12-22 09:30"},
+		{"friday evening", "2026-12-18 19:00", 0, time.Hour, "2026-12-21 09:30"},
+		{"over a holiday", "2026-12-24 17:00", 0, time.Hour, "2026-12-28 09:00"},
+		{"whole days keep the time", "2026-12-23 11:15", 2, 0, "2026-12-28 11:15"},
+		{"days then hours", "2026-12-23 16:00", 1, 2 * time.Hour, "2026-12-28 09:00"},
+		{"zero snaps forward", "2026-12-26 12:00", 0, 0, "2026-12-28 08:30"},
+	}
+	for _, tc := range cases {
+		got := lk.AddBusinessTime(at(lk, tc.start), tc.days, tc.d)
+		if want := at(lk, tc.want); !got.Equal(want) {
+			t.Errorf("%s: AddBusinessTime(%s, %d, %s) = %s, want %s", tc.name, tc.start, tc.days, tc.d, got.Format("2006-01-02 15:04 Mon"), tc.want)
+		}
+		if got.Location() != lk.Location {
+			t.Errorf("%s: result in %s, want the calendar's zone", tc.name, got.Location())
+		}
+	}
+
+	// A start in another zone is converted first: 09:00 UTC is 14:30 in Colombo.
+	got := lk.AddBusinessTime(time.Date(2026, 12, 22, 9, 0, 0, 0, time.UTC), 0, 4*time.Hour)
+	if want := at(lk, "2026-12-23 09:30"); !got.Equal(want) {
+		t.Errorf("UTC start: got %s, want %s", got, want)
+	}
+
+	// Working hours stay on the wall clock across a daylight saving change:
+	// US clocks go back on 2026-11-01, and Monday still opens at 09:00 local.
+	us, _ := s.Get("us-east")
+	got = us.AddBusinessTime(at(us, "2026-10-30 16:00"), 0, 2*time.Hour)
+	if want := at(us, "2026-11-02 10:00"); !got.Equal(want) {
+		t.Errorf("across DST: got %s, want %s", got, want)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	s := Builtin()
+	c := s.Default()
+	if c.ID != DefaultID || c.Location != time.UTC || len(s.All()) != 1 {
+		t.Fatalf("Builtin = %+v", c)
+	}
+	if got := c.AddBusinessTime(time.Date(2026, 10, 16, 16, 0, 0, 0, time.UTC), 0, 2*time.Hour); !got.Equal(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Friday 16:00 + 2h = %s", got)
+	}
+}
+
+func TestLoad_EmptyPathIsBuiltin(t *testing.T) {
+	s, err := Load("")
+	if err != nil || s.Default().ID != DefaultID {
+		t.Fatalf("Load(\"\") = %v, %v", s, err)
+	}
+}
@@ -53,6 +53,9 @@ type Config struct {
 	ServiceNowIntegrationServiceClientID     string
 	ServiceNowIntegrationServiceClientSecret string
 	ServiceNowIntegrationServiceScopes       string
+	// BusinessCalendarsFile is the path of the business calendars JSON file
+	// (see calendar.Load). Optional; unset means the built-in UTC calendar.
+	BusinessCalendarsFile string
 }
 
 // Load reads configuration from environment variables and returns a populated
@@ -73,6 +76,7 @@ func Load() *Config {
 		ServiceNowIntegrationServiceClientID:     os.Getenv("SERVICENOW_INTEGRATION_SERVICE_CLIENT_ID"),
 		ServiceNowIntegrationServiceClientSecret: os.Getenv("SERVICENOW_INTEGRATION_SERVICE_CLIENT_SECRET"),
 		ServiceNowIntegrationServiceScopes:       os.Getenv("SERVICENOW_INTEGRATION_SERVICE_SCOPES"),
+		BusinessCalendarsFile:                    os.Getenv("BUSINESS_CALENDARS_FILE"),
 	}
 }
 
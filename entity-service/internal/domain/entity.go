@@ -529,6 +529,9 @@ type SearchProjectsRequest struct {
 	// converted to the backing data source's internal id before dispatch
 	// (ServiceNow data source only).
 	AccountID string `json:"accountId"`
+	// UpdatedFrom filters to projects last updated at or after this instant,
+	// so a poller can ask only for what changed since its previous poll.
+	UpdatedFrom *time.Time `json:"updatedFrom"`
 }
 
 // ProjectView is the unified search result shape returned for all data sources.
@@ -547,6 +550,9 @@ type ProjectView struct {
 	// for this project (e.g. ServiceNow leaves it blank).
 	EndDate   *time.Time `json:"endDate"`
 	CreatedOn time.Time  `json:"createdOn"`
+	// UpdatedOn is when the project record last changed, closure fields
+	// included. Nil when the backing data source leaves it blank.
+	UpdatedOn *time.Time `json:"updatedOn"`
 	// Account is nil when the project has no linked account (ServiceNow data source only).
 	Account *EntityRef `json:"account"`
 	ProjectClosureFields
@@ -1959,9 +1965,21 @@ type CreateCommentResponse struct {
 // SearchCaseCommentsRequest is the input for listing comments on a case.
 // CaseID is populated from the URL path parameter and is not part of the JSON body.
 type SearchCaseCommentsRequest struct {
-	CaseID     string          `json:"-"`
-	Filters    *CommentFilters `json:"filters"`
-	Pagination Pagination      `json:"pagination"`
+	CaseID  string          `json:"-"`
+	Filters *CommentFilters `json:"filters"`
+	// SortBy orders the comments; the only field is createdOn. Omitted, they
+	// come newest first from the database and in ServiceNow's own order.
+	SortBy     *CommentSort `json:"sortBy"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+// CommentSortFieldCreatedOn is the one column case comments can be sorted on.
+const CommentSortFieldCreatedOn = "createdOn"
+
+// CommentSort specifies the sort field and direction for case comments.
+type CommentSort struct {
+	Field string        `json:"field"`
+	Order CaseSortOrder `json:"order"`
 }
 
 // CommentFilters holds optional filter criteria for searching case comments.
@@ -3577,6 +3595,86 @@ type SearchIncidentsResponse struct {
 	Limit     int                  `json:"limit"`
 }
 
+// IncidentMetricsInterval is the bucket size of an incident metrics series.
+type IncidentMetricsInterval string
+
+const (
+	IncidentMetricsIntervalWeek  IncidentMetricsInterval = "week"
+	IncidentMetricsIntervalMonth IncidentMetricsInterval = "month"
+)
+
+// IncidentMetricsRequest is the input for POST /incidents/metrics. The date
+// range is the Filters "createdOn" gte/lte pair, and both bounds are required:
+// an open-ended range would make every call walk the whole incident table.
+// Every other filter narrows the incident set exactly as it does for search.
+type IncidentMetricsRequest struct {
+	Filters SearchIncidentsFilters `json:"filters"`
+	// GroupBy breaks the metrics down by one dimension: "priority",
+	// "category", "assignmentGroup" or "businessService" (optional; omitted
+	// means overall metrics only).
+	GroupBy string `json:"groupBy,omitempty"`
+	// Interval is the series bucket size (optional; defaults to "week").
+	// Weeks start on Monday; both kinds of bucket are UTC.
+	Interval IncidentMetricsInterval `json:"interval,omitempty"`
+}
+
+// IncidentMetrics is one set of time-based incident metrics. The means are
+// nil when no incident in the set reached the milestone they measure, so
+// "no data" is never reported as zero.
+type IncidentMetrics struct {
+	// Count is the number of incidents opened in the set.
+	Count int `json:"count"`
+	// Acknowledged is how many of them left the NEW state (or were assigned
+	// to an engineer) at least once.
+	Acknowledged int `json:"acknowledged"`
+	// Resolved is how many of them carry a resolution time.
+	Resolved int `json:"resolved"`
+	// Reopened is how many of them moved out of RESOLVED or CLOSED back into
+	// an open state at least once.
+	Reopened int `json:"reopened"`
+	// MeanTimeToAcknowledgeSeconds is the mean of opened -> acknowledged.
+	MeanTimeToAcknowledgeSeconds *float64 `json:"meanTimeToAcknowledgeSeconds"`
+	// MeanTimeToResolveSeconds is the mean of opened -> resolved.
+	MeanTimeToResolveSeconds *float64 `json:"meanTimeToResolveSeconds"`
+	// ReopenRate is Reopened / Resolved.
+	ReopenRate *float64 `json:"reopenRate"`
+}
+
+// IncidentMetricsPoint is one bucket of an incident metrics series, keyed by
+// the UTC start of the week or month the incidents were opened in.
+type IncidentMetricsPoint struct {
+	PeriodStart time.Time `json:"periodStart"`
+	IncidentMetrics
+}
+
+// IncidentMetricsGroup is the metrics for one value of the requested GroupBy
+// dimension. Key is the enum value (priority, category) or the platform UUID
+// (assignmentGroup, businessService); Name is its display name. Incidents
+// with no value for the dimension are grouped under an empty Key.
+type IncidentMetricsGroup struct {
+	Key     string                 `json:"key"`
+	Name    string                 `json:"name"`
+	Metrics IncidentMetrics        `json:"metrics"`
+	Series  []IncidentMetricsPoint `json:"series"`
+}
+
+// IncidentMetricsResponse is the result of POST /incidents/metrics.
+// Analyzed is how many incidents the metrics were computed from; it is less
+// than Total, and Truncated is true, when the range matched more incidents
+// than a single metrics call reads.
+type IncidentMetricsResponse struct {
+	From      time.Time               `json:"from"`
+	To        time.Time               `json:"to"`
+	Interval  IncidentMetricsInterval `json:"interval"`
+	GroupBy   string                  `json:"groupBy,omitempty"`
+	Total     int                     `json:"total"`
+	Analyzed  int                     `json:"analyzed"`
+	Truncated bool                    `json:"truncated"`
+	Overall   IncidentMetrics         `json:"overall"`
+	Series    []IncidentMetricsPoint  `json:"series"`
+	Groups    []IncidentMetricsGroup  `json:"groups,omitempty"`
+}
+
 // IncidentState represents the workflow state of an incident.
 type IncidentState string
 
@@ -4084,3 +4182,55 @@ type SearchConversationsResponse struct {
 	Offset        int                      `json:"offset"`
 	Limit         int                      `json:"limit"`
 }
+
+// BusinessHours is a calendar's daily working hours, as local "HH:MM".
+type BusinessHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// BusinessHoliday is a non-working date on a business calendar.
+type BusinessHoliday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// BusinessCalendar is a regional business calendar: the working days and
+// hours in a time zone, less its holidays.
+type BusinessCalendar struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Timezone     string            `json:"timezone"`
+	WorkingDays  []string          `json:"workingDays"`
+	WorkingHours BusinessHours     `json:"workingHours"`
+	Holidays     []BusinessHoliday `json:"holidays"`
+	// Default marks the calendar business-day placeholders use when they
+	// name none.
+	Default bool `json:"default"`
+}
+
+// ListBusinessCalendarsResponse is the result of GET /calendars.
+type ListBusinessCalendarsResponse struct {
+	Calendars []BusinessCalendar `json:"calendars"`
+}
+
+// AddBusinessTimeRequest is the input for POST /calendars/{id}/add-business-time.
+// Days are added first, keeping the time of day, then Hours and Minutes of
+// working time.
+type AddBusinessTimeRequest struct {
+	CalendarID string `json:"-"`
+	// Start is an RFC3339 timestamp (optional); defaults to now.
+	Start   *string `json:"start,omitempty"`
+	Days    int     `json:"days"`
+	Hours   int     `json:"hours"`
+	Minutes int     `json:"minutes"`
+}
+
+// AddBusinessTimeResponse is the result of POST /calendars/{id}/add-business-time.
+// Both timestamps are RFC3339 in the calendar's time zone.
+type AddBusinessTimeResponse struct {
+	CalendarID string `json:"calendarId"`
+	Timezone   string `json:"timezone"`
+	Start      string `json:"start"`
+	Due        string `json:"due"`
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/domain"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/service"
+)
+
+// CalendarHandler handles HTTP requests for the business calendars resource.
+type CalendarHandler struct {
+	svc service.CalendarService
+}
+
+// NewCalendarHandler constructs a CalendarHandler with the given service.
+func NewCalendarHandler(svc service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{svc: svc}
+}
+
+// ListCalendars handles GET /calendars.
+func (h *CalendarHandler) ListCalendars(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.svc.ListCalendars(r.Context())
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// AddBusinessTime handles POST /calendars/{id}/add-business-time.
+func (h *CalendarHandler) AddBusinessTime(w http.ResponseWriter, r *http.Request) {
+	var req domain.AddBusinessTimeRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	req.CalendarID = r.PathValue("id")
+	resp, err := h.svc.AddBusinessTime(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -127,3 +127,19 @@ func (h *IncidentHandler) AggregateIncidents(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(resp)
 }
+
+// IncidentMetrics handles POST /incidents/metrics.
+func (h *IncidentHandler) IncidentMetrics(w http.ResponseWriter, r *http.Request) {
+	var req domain.IncidentMetricsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	resp, err := h.svc.IncidentMetrics(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -235,6 +235,11 @@ func (r *caseRepo) SearchCaseComments(ctx context.Context, req domain.SearchCase
 		typeFilter = fmt.Sprintf(" AND cc.type = $%d::comment_type_enum", len(args))
 	}
 
+	order := "DESC"
+	if req.SortBy != nil && req.SortBy.Order == domain.CaseSortOrderAsc {
+		order = "ASC"
+	}
+
 	countQuery := `SELECT COUNT(*) FROM case_comments cc WHERE cc.case_id = $1` + typeFilter
 	dataQuery := fmt.Sprintf(`
 		SELECT cc.id, cc.case_id, cc.type, cc.content,
@@ -244,8 +249,8 @@ func (r *caseRepo) SearchCaseComments(ctx context.Context, req domain.SearchCase
 		FROM case_comments cc
 		JOIN users u ON u.id = cc.created_by
 		WHERE cc.case_id = $1%s
-		ORDER BY cc.created_at DESC, cc.id
-		LIMIT $%d OFFSET $%d`, typeFilter, len(args)+1, len(args)+2)
+		ORDER BY cc.created_at %s, cc.id
+		LIMIT $%d OFFSET $%d`, typeFilter, order, len(args)+1, len(args)+2)
 
 	dataArgs := append(args, req.Pagination.Limit, req.Pagination.Offset)
 
@@ -69,6 +69,12 @@ func (r *projectRepo) SearchProjects(ctx context.Context, req domain.SearchProje
 		argIdx++
 	}
 
+	if req.UpdatedFrom != nil {
+		where += fmt.Sprintf(" AND updated_at >= $%d", argIdx)
+		filterArgs = append(filterArgs, *req.UpdatedFrom)
+		argIdx++
+	}
+
 	countQuery := "SELECT COUNT(*) FROM projects " + where
 
 	dataQuery := fmt.Sprintf(
@@ -225,6 +225,10 @@ func NewRouter(db *pgxpool.Pool, cfg *config.Config) http.Handler {
 	}
 	taskHandler := handler.NewTaskHandler(activeTaskSvc)
 
+	// Business calendars are configuration, not backing data, so both data
+	// sources serve them.
+	calendarHandler := handler.NewCalendarHandler(service.NewCalendarService())
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /health", handler.HealthCheck)
@@ -369,6 +373,7 @@ func NewRouter(db *pgxpool.Pool, cfg *config.Config) http.Handler {
 		mux.HandleFunc("POST /incidents", incidentHandler.CreateIncident)
 		mux.HandleFunc("POST /incidents/search", incidentHandler.SearchIncidents)
 		mux.HandleFunc("POST /incidents/aggregate", incidentHandler.AggregateIncidents)
+		mux.HandleFunc("POST /incidents/metrics", incidentHandler.IncidentMetrics)
 		mux.HandleFunc("POST /incidents/{id}/activities/search", incidentHandler.SearchIncidentActivities)
 	}
 
@@ -389,6 +394,9 @@ func NewRouter(db *pgxpool.Pool, cfg *config.Config) http.Handler {
 		mux.HandleFunc("POST /conversations/search", conversationHandler.SearchConversations)
 	}
 
+	mux.HandleFunc("GET /calendars", calendarHandler.ListCalendars)
+	mux.HandleFunc("POST /calendars/{id}/add-business-time", calendarHandler.AddBusinessTime)
+
 	return middleware.CorrelationID(
 		middleware.Recovery(
 			middleware.Logger(
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/apierror"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/calendar"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/domain"
+)
+
+// maxBusinessDays bounds how far AddBusinessTime reaches, in business days or
+// the equivalent hours and minutes, so a request cannot walk the calendar
+// for ever.
+const maxBusinessDays = 365
+
+// businessCalendars is the calendar set that business-day placeholders (see
+// resolveRelativeDate) and CalendarService resolve against. It is the
+// built-in UTC calendar until SetBusinessCalendars replaces it.
+var businessCalendars = calendar.Builtin()
+
+// SetBusinessCalendars replaces the calendars business-day placeholders and
+// CalendarService use. Call it once at startup, before the server serves.
+func SetBusinessCalendars(s *calendar.Set) {
+	businessCalendars = s
+}
+
+// calendarService implements CalendarService over businessCalendars.
+type calendarService struct {
+	now func() time.Time
+}
+
+// NewCalendarService returns a CalendarService over the calendars set by
+// SetBusinessCalendars.
+func NewCalendarService() CalendarService {
+	return &calendarService{now: time.Now}
+}
+
+// ListCalendars returns every configured calendar, ordered by ID.
+func (s *calendarService) ListCalendars(_ context.Context) (domain.ListBusinessCalendarsResponse, error) {
+	def := businessCalendars.Default()
+	all := businessCalendars.All()
+	resp := domain.ListBusinessCalendarsResponse{Calendars: make([]domain.BusinessCalendar, 0, len(all))}
+	for _, c := range all {
+		resp.Calendars = append(resp.Calendars, toBusinessCalendar(c, c == def))
+	}
+	return resp, nil
+}
+
+// AddBusinessTime returns when req's business time after its start runs out
+// on the named calendar.
+func (s *calendarService) AddBusinessTime(_ context.Context, req domain.AddBusinessTimeRequest) (domain.AddBusinessTimeResponse, error) {
+	c, ok := businessCalendars.Get(req.CalendarID)
+	if !ok {
+		return domain.AddBusinessTimeResponse{}, &apierror.NotFoundError{Msg: fmt.Sprintf("calendar %q not found", req.CalendarID)}
+	}
+	if req.Days < 0 || req.Hours < 0 || req.Minutes < 0 {
+		return domain.AddBusinessTimeResponse{}, &apierror.ValidationError{Msg: "days, hours and minutes must not be negative"}
+	}
+	workday := c.End - c.Start
+	d := time.Duration(req.Hours)*time.Hour + time.Duration(req.Minutes)*time.Minute
+	if req.Days > maxBusinessDays || req.Hours > maxBusinessDays*24 || req.Minutes > maxBusinessDays*24*60 ||
+		time.Duration(req.Days)*workday+d > maxBusinessDays*workday {
+		return domain.AddBusinessTimeResponse{}, &apierror.ValidationError{Msg: fmt.Sprintf("business time must not exceed %d business days", maxBusinessDays)}
+	}
+
+	start := s.now()
+	if req.Start != nil {
+		t, err := time.Parse(time.RFC3339, *req.Start)
+		if err != nil {
+			return domain.AddBusinessTimeResponse{}, &apierror.ValidationError{Msg: fmt.Sprintf("start %q must be an RFC3339 timestamp", *req.Start)}
+		}
+		start = t
+	}
+
+	return domain.AddBusinessTimeResponse{
+		CalendarID: c.ID,
+		Timezone:   c.Location.String(),
+		Start:      start.In(c.Location).Format(time.RFC3339),
+		Due:        c.AddBusinessTime(start, req.Days, d).Format(time.RFC3339),
+	}, nil
+}
+
+// toBusinessCalendar converts a calendar to its wire form.
+func toBusinessCalendar(c *calendar.Calendar, isDefault bool) domain.BusinessCalendar {
+	out := domain.BusinessCalendar{
+		ID:       c.ID,
+		Name:     c.Name,
+		Timezone: c.Location.String(),
+		WorkingHours: domain.BusinessHours{
+			Start: formatClock(c.Start),
+			End:   formatClock(c.End),
+		},
+		Holidays: []domain.BusinessHoliday{},
+		Default:  isDefault,
+	}
+	for _, d := range c.Workdays() {
+		out.WorkingDays = append(out.WorkingDays, strings.ToLower(d.String()[:3]))
+	}
+	for _, h := range c.Holidays() {
+		out.Holidays = append(out.Holidays, domain.BusinessHoliday{Date: h.Date, Name: h.Name})
+	}
+	return out
+}
+
+// formatClock renders an offset from midnight as "HH:MM".
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied. See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/apierror"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/calendar"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/domain"
+)
+
+// useExampleCalendars installs calendars.example.json (default "lk") for the
+// duration of the test.
+func useExampleCalendars(t *testing.T) {
+	t.Helper()
+	raw, err := os.ReadFile("../../calendars.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := calendar.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := businessCalendars
+	SetBusinessCalendars(s)
+	t.Cleanup(func() { SetBusinessCalendars(prev) })
+}
+
+// holidayNow is a Tuesday, 03:00 UTC: already the 29th in Colombo and London
+// but still the 28th in New York. The 25th is a holiday everywhere, and the
+// 28th is also one in the UK.
+var holidayNow = time.Date(2026, time.December, 29, 3, 0, 0, 0, time.UTC)
+
+func TestResolveRelativeDateIn_Calendars(t *testing.T) {
+	useExampleCalendars(t)
+	cases := []struct {
+		value, want, zone string
+	}{
+		{"__today__", "2026-12-29", "UTC"},
+		{"__today@us-east__", "2026-12-28", "America/New_York"},
+		{"__today@Asia/Colombo__", "2026-12-29", "Asia/Colombo"},
+		{"__daysAgo:1@America/New_York__", "2026-12-27", "America/New_York"},
+		{"__startOfMonth:0@lk__", "2026-12-01", "Asia/Colombo"},
+		{"__businessDaysAgo:0__", "2026-12-29", "Asia/Colombo"},
+		{"__businessDaysAgo:1__", "2026-12-28", "Asia/Colombo"},
+		{"__businessDaysAgo:1@uk__", "2026-12-24", "Europe/London"},
+		// A zone keeps the default (lk) calendar's days: from New York's
+		// Monday the 28th, back over the weekend and Christmas Day.
+		{"__businessDaysAgo:1@America/New_York__", "2026-12-24", "America/New_York"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, loc, matched, err := resolveRelativeDateIn(tc.value, holidayNow)
+			if err != nil || !matched {
+				t.Fatalf("resolveRelativeDateIn(%q) = %v, %v", tc.value, matched, err)
+			}
+			if got != tc.want || loc.String() != tc.zone {
+				t.Errorf("resolveRelativeDateIn(%q) = %s in %s, want %s in %s", tc.value, got, loc, tc.want, tc.zone)
+			}
+		})
+	}
+}
+
+func TestResolveRelativeDateIn_Rejections(t *testing.T) {
+	useExampleCalendars(t)
+	for _, value := range []string{
+		"__today@Mars/Olympus__",   // neither a calendar nor a zone
+		"__today@Local__",          // the host's zone is not a region
+		"__businessDaysAgo__",      // missing required offset
+		"__businessDaysAgo:-1__",   // negative offset
+		"__businessDaysAgo:x@lk__", // non-integer offset
+	} {
+		t.Run(value, func(t *testing.T) {
+			_, _, _, err := resolveRelativeDateIn(value, holidayNow)
+			var ve *apierror.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("resolveRelativeDateIn(%q) error = %v, want a ValidationError", value, err)
+			}
+		})
+	}
+	if _, _, matched, err := resolveRelativeDateIn("__bogus@lk__", holidayNow); matched || err != nil {
+		t.Errorf("an unknown name with a suffix = %v, %v; want it left to the literal parse", matched, err)
+	}
+}
+
+func TestParseFilterDate_ZonedPlaceholder(t *testing.T) {
+	useExampleCalendars(t)
+	gte, err := parseFilterDate("createdOn", "gte", "__today@Asia/Colombo__", holidayNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, time.December, 28, 18, 30, 0, 0, time.UTC); !gte.Equal(want) {
+		t.Errorf("gte = %s, want Colombo midnight %s", gte.UTC(), want)
+	}
+	lte, err := parseFilterDate("createdOn", "lte", "__businessDaysAgo:1@uk__", holidayNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, time.December, 25, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond); !lte.Equal(want) {
+		t.Errorf("lte = %s, want the end of the 24th in London %s", lte.UTC(), want)
+	}
+	// A literal date stays UTC midnight.
+	lit, err := parseFilterDate("createdOn", "gte", "2026-12-28", holidayNow)
+	if err != nil || !lit.Equal(time.Date(2026, time.December, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("literal = %v, %v", lit, err)
+	}
+}
+
+func TestCalendarService_AddBusinessTime(t *testing.T) {
+	useExampleCalendars(t)
+	svc := &calendarService{now: func() time.Time { return holidayNow }}
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	resp, err := svc.AddBusinessTime(ctx, domain.AddBusinessTimeRequest{CalendarID: "uk", Start: str("2026-12-24T16:00:00Z"), Hours: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1.5h on Christmas Eve, then the 25th and 28th are holidays.
+	if resp.Due != "2026-12-29T11:30:00Z" || resp.Timezone != "Europe/London" || resp.CalendarID != "uk" {
+		t.Errorf("resp = %+v", resp)
+	}
+
+	resp, err = svc.AddBusinessTime(ctx, domain.AddBusinessTimeRequest{CalendarID: "lk", Days: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Start != "2026-12-29T08:30:00+05:30" || resp.Due != "2026-12-30T08:30:00+05:30" {
+		t.Errorf("defaulted start: resp = %+v", resp)
+	}
+
+	var nfe *apierror.NotFoundError
+	if _, err := svc.AddBusinessTime(ctx, domain.AddBusinessTimeRequest{CalendarID: "mars", Hours: 1}); !errors.As(err, &nfe) {
+		t.Errorf("unknown calendar: err = %v, want a NotFoundError", err)
+	}
+	for name, req := range map[string]domain.AddBusinessTimeRequest{
+		"negative":  {CalendarID: "lk", Hours: -1},
+		"too far":   {CalendarID: "lk", Days: 300, Hours: 9 * 70},
+		"bad start": {CalendarID: "lk", Start: str("tomorrow"), Hours: 1},
+	} {
+		var ve *apierror.ValidationError
+		if _, err := svc.AddBusinessTime(ctx, req); !errors.As(err, &ve) {
+			t.Errorf("%s: err = %v, want a ValidationError", name, err)
+		}
+	}
+}
+
+func TestCalendarService_ListCalendars(t *testing.T) {
+	resp, err := NewCalendarService().ListCalendars(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Calendars) != 1 {
+		t.Fatalf("builtin calendars = %+v", resp.Calendars)
+	}
+	c := resp.Calendars[0]
+	if c.ID != calendar.DefaultID || !c.Default || c.Timezone != "UTC" || c.WorkingHours.Start != "09:00" || len(c.WorkingDays) != 5 || c.WorkingDays[0] != "mon" {
+		t.Errorf("builtin = %+v", c)
+	}
+}
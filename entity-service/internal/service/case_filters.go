@@ -73,96 +73,133 @@ func badCaseFilterCombo(f domain.CaseFieldFilter) error {
 // relativeDatePlaceholderPattern matches the shape of every relative-date
 // placeholder this package recognizes: __<name>__ (no argument, e.g. __today__)
 // or __<name>:<argument>__ (e.g. __daysAgo:30__, __startOfMonth:-1__,
-// __daysAgo:abc__). The argument capture is deliberately permissive (any
-// run of non-underscore characters, not just digits) so a non-integer
-// argument like "abc" still matches this shape -- letting
-// parseRelativeDateArg reject it with a targeted "non-integer offset" error
-// -- rather than silently falling through as "not a placeholder at all"
-// just because it happened to fail a stricter \d+ match. A string that
-// doesn't match this shape at all is not one of ours -- it falls through to
-// the literal RFC3339/YYYY-MM-DD parse in parseCaseFilterDate, which
-// rejects it with its own generic message. A string that DOES match this
-// shape but names an unrecognized function gets the same fallthrough.
-var relativeDatePlaceholderPattern = regexp.MustCompile(`^__([a-zA-Z]+)(?::([^_]*))?__$`)
+// __daysAgo:abc__), either optionally followed by @<calendar or time zone>
+// (e.g. __today@Asia/Colombo__, __businessDaysAgo:5@lk__). The argument
+// capture is deliberately permissive (any run of non-underscore characters,
+// not just digits) so a non-integer argument like "abc" still matches this
+// shape -- letting parseRelativeDateArg reject it with a targeted
+// "non-integer offset" error -- rather than silently falling through as "not
+// a placeholder at all" just because it happened to fail a stricter \d+
+// match. A string that doesn't match this shape at all is not one of ours --
+// it falls through to the literal RFC3339/YYYY-MM-DD parse in
+// parseFilterDate, which rejects it with its own generic message. A string
+// that DOES match this shape but names an unrecognized function gets the
+// same fallthrough.
+var relativeDatePlaceholderPattern = regexp.MustCompile(`^__([a-zA-Z]+)(?::([^_@]*))?(?:@([A-Za-z0-9_/+-]+))?__$`)
+
+// relativeDateNames is the set of placeholder names resolveRelativeDateIn
+// recognizes.
+var relativeDateNames = map[string]bool{
+	"today": true, "daysAgo": true, "businessDaysAgo": true,
+	"startOfMonth": true, "endOfMonth": true, "startOfQuarter": true, "endOfQuarter": true,
+}
 
 // resolveRelativeDate resolves a filter value like "__daysAgo:2__" or
-// "__startOfMonth:-1__" to a concrete "YYYY-MM-DD" string computed against
-// now (always UTC, matching the rest of this pipeline's date handling --
-// see formatSNDateTimeUTC). Returns matched=false, err=nil for a value that
-// isn't one of these placeholders at all (a literal date, or garbage that
-// parseCaseFilterDate's own validation will reject).
+// "__startOfMonth:-1__" to a concrete "YYYY-MM-DD" string; see
+// resolveRelativeDateIn, which also says which time zone that date is in.
+func resolveRelativeDate(value string, now time.Time) (resolved string, matched bool, err error) {
+	resolved, _, matched, err = resolveRelativeDateIn(value, now)
+	return resolved, matched, err
+}
+
+// resolveRelativeDateIn resolves a relative-date placeholder to a concrete
+// "YYYY-MM-DD" string computed against now, and the time zone whose day it
+// is. Returns matched=false, err=nil for a value that isn't one of these
+// placeholders at all (a literal date, or garbage that parseFilterDate's own
+// validation will reject).
 //
 // Supported placeholders (N is a signed integer offset, 0 = current):
 //   - __today__                 today's date
 //   - __daysAgo:N__             today minus N days (N >= 0)
+//   - __businessDaysAgo:N__     N business days before today (N >= 0) on a
+//     business calendar (see SetBusinessCalendars); weekends and the
+//     calendar's holidays are skipped
 //   - __startOfMonth:N__        1st of the month N months from now
 //   - __endOfMonth:N__          last day of the month N months from now
 //   - __startOfQuarter:N__      1st of the quarter N quarters from now
 //   - __endOfQuarter:N__        last day of the quarter N quarters from now
 //
-// Each resolves to a plain date (no time component); the caller (parseCaseFilterDate)
-// applies the existing date-only handling -- including the lte
-// inclusive-of-whole-day bump -- identically to a literal "YYYY-MM-DD" value.
-func resolveRelativeDate(value string, now time.Time) (resolved string, matched bool, err error) {
+// The calendar-day placeholders count in UTC, matching the rest of this
+// pipeline's date handling (see formatSNDateTimeUTC); __businessDaysAgo__
+// counts on the default calendar, in its time zone. An @suffix names the
+// calendar or IANA time zone to count in instead: a calendar ID selects that
+// calendar and its zone, and a zone keeps the default calendar's working
+// days and holidays but counts today's date in that zone.
+//
+// Each resolves to a plain date (no time component); the caller
+// (parseFilterDate) applies the existing date-only handling -- including the
+// lte inclusive-of-whole-day bump -- identically to a literal "YYYY-MM-DD"
+// value, taking the date's midnight in the returned zone.
+func resolveRelativeDateIn(value string, now time.Time) (resolved string, loc *time.Location, matched bool, err error) {
 	m := relativeDatePlaceholderPattern.FindStringSubmatch(value)
 	if m == nil {
-		return "", false, nil
+		return "", nil, false, nil
+	}
+	name, argStr, suffix := m[1], m[2], m[3]
+	if !relativeDateNames[name] {
+		// Shape matches (__name__ or __name:N__) but the name isn't one of
+		// ours -- not a relative-date placeholder at all (e.g. some other
+		// package's __current_x__ token used by mistake on a date field).
+		// Let parseFilterDate's literal-date parse reject it.
+		return "", nil, false, nil
 	}
-	name, argStr := m[1], m[2]
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
+	cal := businessCalendars.Default()
+	loc = time.UTC
+	if name == "businessDaysAgo" {
+		loc = cal.Location
+	}
+	if suffix != "" {
+		if c, ok := businessCalendars.Get(suffix); ok {
+			cal, loc = c, c.Location
+		} else if z, zerr := time.LoadLocation(suffix); zerr == nil && suffix != "Local" {
+			loc = z
+		} else {
+			return "", nil, false, &apierror.ValidationError{Msg: fmt.Sprintf("filters: %q names an unknown calendar or time zone %q", value, suffix)}
+		}
+	}
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	var day time.Time
 	switch name {
 	case "today":
 		if argStr != "" {
-			return "", false, &apierror.ValidationError{Msg: fmt.Sprintf("filters: %q does not take an argument", value)}
+			return "", nil, false, &apierror.ValidationError{Msg: fmt.Sprintf("filters: %q does not take an argument", value)}
 		}
-		return today.Format("2006-01-02"), true, nil
+		day = today
 
-	case "daysAgo":
+	case "daysAgo", "businessDaysAgo":
 		n, err := parseRelativeDateArg(value, argStr)
 		if err != nil {
-			return "", false, err
+			return "", nil, false, err
 		}
 		if n < 0 {
-			return "", false, &apierror.ValidationError{Msg: fmt.Sprintf("filters: %q must have a non-negative offset", value)}
-		}
-		return today.AddDate(0, 0, -n).Format("2006-01-02"), true, nil
-
-	case "startOfMonth":
-		n, err := parseRelativeDateArg(value, argStr)
-		if err != nil {
-			return "", false, err
+			return "", nil, false, &apierror.ValidationError{Msg: fmt.Sprintf("filters: %q must have a non-negative offset", value)}
 		}
-		return startOfRelativeMonth(now, n).Format("2006-01-02"), true, nil
-
-	case "endOfMonth":
-		n, err := parseRelativeDateArg(value, argStr)
-		if err != nil {
-			return "", false, err
+		if name == "daysAgo" {
+			day = today.AddDate(0, 0, -n)
+		} else {
+			day = cal.BusinessDaysBefore(today, n)
 		}
-		return startOfRelativeMonth(now, n+1).AddDate(0, 0, -1).Format("2006-01-02"), true, nil
 
-	case "startOfQuarter":
+	case "startOfMonth", "endOfMonth", "startOfQuarter", "endOfQuarter":
 		n, err := parseRelativeDateArg(value, argStr)
 		if err != nil {
-			return "", false, err
+			return "", nil, false, err
 		}
-		return startOfRelativeQuarter(now, n).Format("2006-01-02"), true, nil
-
-	case "endOfQuarter":
-		n, err := parseRelativeDateArg(value, argStr)
-		if err != nil {
-			return "", false, err
+		switch name {
+		case "startOfMonth":
+			day = startOfRelativeMonth(now, n)
+		case "endOfMonth":
+			day = startOfRelativeMonth(now, n+1).AddDate(0, 0, -1)
+		case "startOfQuarter":
+			day = startOfRelativeQuarter(now, n)
+		default:
+			day = startOfRelativeQuarter(now, n+1).AddDate(0, 0, -1)
 		}
-		return startOfRelativeQuarter(now, n+1).AddDate(0, 0, -1).Format("2006-01-02"), true, nil
-
-	default:
-		// Shape matches (__name__ or __name:N__) but the name isn't one of
-		// ours -- not a relative-date placeholder at all (e.g. some other
-		// package's __current_x__ token used by mistake on a date field).
-		// Let parseCaseFilterDate's literal-date parse reject it.
-		return "", false, nil
 	}
+	return day.Format("2006-01-02"), loc, true, nil
 }
 
 // parseRelativeDateArg parses a relativeDatePlaceholderPattern match's :N
@@ -180,45 +217,55 @@ func parseRelativeDateArg(value, argStr string) (int, error) {
 }
 
 // startOfRelativeMonth returns the 1st of the month n months from now's
-// month (UTC, midnight), e.g. n=0 this month, n=-1 last month, n=1 next
-// month. Relies on time.AddDate's month normalization for year rollover.
+// month (midnight in now's location), e.g. n=0 this month, n=-1 last month,
+// n=1 next month. Relies on time.AddDate's month normalization for year
+// rollover.
 func startOfRelativeMonth(now time.Time, n int) time.Time {
-	base := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	base := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	return base.AddDate(0, n, 0)
 }
 
 // startOfRelativeQuarter returns the 1st of the quarter n quarters from now's
-// quarter (UTC, midnight), e.g. n=0 this quarter, n=-1 last quarter.
+// quarter (midnight in now's location), e.g. n=0 this quarter, n=-1 last
+// quarter.
 func startOfRelativeQuarter(now time.Time, n int) time.Time {
 	quarterStartMonth := ((int(now.Month())-1)/3)*3 + 1
-	base := time.Date(now.Year(), time.Month(quarterStartMonth), 1, 0, 0, 0, 0, time.UTC)
+	base := time.Date(now.Year(), time.Month(quarterStartMonth), 1, 0, 0, 0, 0, now.Location())
 	return base.AddDate(0, n*3, 0)
 }
 
 // parseCaseFilterDate parses a single filter value into a date/time. The
-// value may be one of the relative-date placeholders resolveRelativeDate
+// value may be one of the relative-date placeholders resolveRelativeDateIn
 // recognizes, a full RFC3339 timestamp, or a plain YYYY-MM-DD date
 // (interpreted as UTC midnight) -- callers may reasonably send any of these
 // for a date-range bound. now is the reference instant relative-date
 // placeholders resolve against (production passes time.Now().UTC(); tests
 // pass a fixed instant).
 func parseCaseFilterDate(f domain.CaseFieldFilter, value string, now time.Time) (*time.Time, error) {
-	if resolved, matched, err := resolveRelativeDate(value, now); err != nil {
+	return parseFilterDate(f.Field, f.Op, value, now)
+}
+
+// parseFilterDate is the date parsing shared by every entity's filters. A
+// placeholder resolves to a date in its own time zone; a literal
+// YYYY-MM-DD is UTC.
+func parseFilterDate(field, op, value string, now time.Time) (*time.Time, error) {
+	loc := time.UTC
+	if resolved, zone, matched, err := resolveRelativeDateIn(value, now); err != nil {
 		return nil, err
 	} else if matched {
-		value = resolved
+		value, loc = resolved, zone
 	}
 	if t, err := time.Parse(time.RFC3339, value); err == nil {
 		return &t, nil
 	}
-	if t, err := time.Parse("2006-01-02", value); err == nil {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
 		// A date-only lte bound means "on or before that whole day".
-		if f.Op == "lte" {
+		if op == "lte" {
 			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
 		}
 		return &t, nil
 	}
-	return nil, &apierror.ValidationError{Msg: fmt.Sprintf("filters: field %q op %q value %q must be an RFC3339 timestamp, YYYY-MM-DD date, or a recognized relative-date placeholder", f.Field, f.Op, value)}
+	return nil, &apierror.ValidationError{Msg: fmt.Sprintf("filters: field %q op %q value %q must be an RFC3339 timestamp, YYYY-MM-DD date, or a recognized relative-date placeholder", field, op, value)}
 }
 
 // ParseCaseFieldFilters translates the case-search wire contract's generic
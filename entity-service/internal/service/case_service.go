@@ -255,6 +255,22 @@ var validCommentType = map[domain.CommentType]bool{
 	domain.CommentTypeActivity: true,
 }
 
+// validateCommentSort rejects a comment sort on anything but createdOn, or
+// in a direction other than asc or desc.
+func validateCommentSort(sort *domain.CommentSort) error {
+	if sort == nil {
+		return nil
+	}
+	if sort.Field != domain.CommentSortFieldCreatedOn {
+		return &apierror.ValidationError{Msg: "sortBy.field must be " + domain.CommentSortFieldCreatedOn}
+	}
+	switch sort.Order {
+	case "", domain.CaseSortOrderAsc, domain.CaseSortOrderDesc:
+		return nil
+	}
+	return &apierror.ValidationError{Msg: "sortBy.order must be asc or desc"}
+}
+
 // CreateCaseComment implements CaseService.
 func (s *caseService) CreateCaseComment(ctx context.Context, req domain.CreateCaseCommentRequest) (domain.CreateCaseCommentResponse, error) {
 	if err := validateUUIDs("caseId", []string{req.CaseID}); err != nil {
@@ -304,6 +320,9 @@ func (s *caseService) SearchCaseComments(ctx context.Context, req domain.SearchC
 	if req.Filters != nil && req.Filters.Type != nil && !validCommentType[*req.Filters.Type] {
 		return domain.SearchCaseCommentsResponse{}, &apierror.ValidationError{Msg: "filters.type contains invalid value: " + string(*req.Filters.Type)}
 	}
+	if err := validateCommentSort(req.SortBy); err != nil {
+		return domain.SearchCaseCommentsResponse{}, err
+	}
 	comments, total, err := s.repo.SearchCaseComments(ctx, req)
 	if err != nil {
 		return domain.SearchCaseCommentsResponse{}, err
@@ -294,3 +294,27 @@ func TestCaseService_UpdateCase_RejectsTypeTransferFields(t *testing.T) {
 		})
 	}
 }
+
+// TestCaseService_SearchCaseComments_RejectsUnsupportedSort proves a comment
+// sort on anything but createdOn, or in an unknown direction, is a 400 before
+// the repository is reached.
+func TestCaseService_SearchCaseComments_RejectsUnsupportedSort(t *testing.T) {
+	svc := NewCaseService(&stubCaseRepo{}, stubUserRepo{})
+
+	for _, sort := range []domain.CommentSort{
+		{Field: "updatedOn", Order: domain.CaseSortOrderDesc},
+		{Field: domain.CommentSortFieldCreatedOn, Order: "newest"},
+	} {
+		t.Run(sort.Field+" "+string(sort.Order), func(t *testing.T) {
+			_, err := svc.SearchCaseComments(context.Background(), domain.SearchCaseCommentsRequest{
+				CaseID:     "00000000-0000-0000-0000-000000000001",
+				SortBy:     &sort,
+				Pagination: domain.Pagination{Limit: 10},
+			})
+			var ve *apierror.ValidationError
+			if !asValidationError(err, &ve) {
+				t.Fatalf("expected *apierror.ValidationError, got %T: %v", err, err)
+			}
+		})
+	}
+}
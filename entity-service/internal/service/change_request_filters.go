@@ -61,22 +61,7 @@ func badChangeRequestFilterCombo(f domain.ChangeRequestFieldFilter) error {
 // mirroring parseCaseFilterDate in case_filters.go. now is the reference
 // instant relative placeholders resolve against.
 func parseChangeRequestFilterDate(f domain.ChangeRequestFieldFilter, value string, now time.Time) (*time.Time, error) {
-	if resolved, matched, err := resolveRelativeDate(value, now); err != nil {
-		return nil, err
-	} else if matched {
-		value = resolved
-	}
-	if t, err := time.Parse(time.RFC3339, value); err == nil {
-		return &t, nil
-	}
-	if t, err := time.Parse("2006-01-02", value); err == nil {
-		// A date-only lte bound means "on or before that whole day".
-		if f.Op == "lte" {
-			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
-		}
-		return &t, nil
-	}
-	return nil, &apierror.ValidationError{Msg: fmt.Sprintf("filters: field %q op %q value %q must be an RFC3339 timestamp, YYYY-MM-DD date, or a recognized relative-date placeholder", f.Field, f.Op, value)}
+	return parseFilterDate(f.Field, f.Op, value, now)
 }
 
 // parsedChangeRequestFilters is the internal, named-field representation that
@@ -45,22 +45,7 @@ var incidentFilterOpSet = map[string]bool{
 // retyped for domain.IncidentFieldFilter -- same RFC3339/date-only/
 // relative-placeholder parsing, same error message shape.
 func parseIncidentFilterDate(f domain.IncidentFieldFilter, value string, now time.Time) (*time.Time, error) {
-	if resolved, matched, err := resolveRelativeDate(value, now); err != nil {
-		return nil, err
-	} else if matched {
-		value = resolved
-	}
-	if t, err := time.Parse(time.RFC3339, value); err == nil {
-		return &t, nil
-	}
-	if t, err := time.Parse("2006-01-02", value); err == nil {
-		// A date-only lte bound means "on or before that whole day".
-		if f.Op == "lte" {
-			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
-		}
-		return &t, nil
-	}
-	return nil, &apierror.ValidationError{Msg: fmt.Sprintf("filters: field %q op %q value %q must be an RFC3339 timestamp, YYYY-MM-DD date, or a recognized relative-date placeholder", f.Field, f.Op, value)}
+	return parseFilterDate(f.Field, f.Op, value, now)
 }
 
 // requireIncidentFilterValues rejects a filter entry whose op needs a
@@ -341,6 +341,17 @@ type GroupService interface {
 	SearchGroups(ctx context.Context, req domain.SearchGroupsRequest) (domain.SearchGroupsResponse, error)
 }
 
+// CalendarService defines the operations available on business calendars.
+// Calendars are configuration (see SetBusinessCalendars), so both data sources
+// serve them.
+type CalendarService interface {
+	// ListCalendars returns every configured calendar, ordered by ID.
+	ListCalendars(ctx context.Context) (domain.ListBusinessCalendarsResponse, error)
+	// AddBusinessTime returns when the requested business time after start
+	// runs out on the calendar; a promised response date, for example.
+	AddBusinessTime(ctx context.Context, req domain.AddBusinessTimeRequest) (domain.AddBusinessTimeResponse, error)
+}
+
 // ServiceOfferingService defines the operations available on the service offerings entity.
 // All methods require the ServiceNow data source; there is no Postgres fallback.
 type ServiceOfferingService interface {
@@ -425,6 +436,12 @@ type IncidentService interface {
 	// returned for invalid input.
 	AggregateIncidents(ctx context.Context, req domain.AggregateIncidentsRequest) (domain.AggregateResponse, error)
 
+	// IncidentMetrics computes mean time to acknowledge, mean time to resolve
+	// and reopen rate over the incidents opened in req's createdOn range,
+	// overall and per req.GroupBy value, with a weekly or monthly series. A
+	// ValidationError is returned for invalid input.
+	IncidentMetrics(ctx context.Context, req domain.IncidentMetricsRequest) (domain.IncidentMetricsResponse, error)
+
 	// CreateIncident creates a new incident in ServiceNow.
 	// callerId, category, serviceId, impact, urgency, and subject are required.
 	CreateIncident(ctx context.Context, req domain.CreateIncidentRequest) (domain.CreateIncidentResponse, error)
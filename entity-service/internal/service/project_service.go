@@ -49,7 +49,7 @@ func (s *projectService) SearchProjects(ctx context.Context, req domain.SearchPr
 
 	views := make([]domain.ProjectView, len(projects))
 	for i, p := range projects {
-		endDate := p.EndDate
+		endDate, updatedOn := p.EndDate, p.UpdatedOn
 		views[i] = domain.ProjectView{
 			ID:               p.ID,
 			Name:             p.Name,
@@ -57,6 +57,7 @@ func (s *projectService) SearchProjects(ctx context.Context, req domain.SearchPr
 			SubscriptionType: p.SubscriptionType,
 			EndDate:          &endDate,
 			CreatedOn:        p.CreatedOn,
+			UpdatedOn:        &updatedOn,
 		}
 	}
 
@@ -1111,6 +1111,7 @@ type snSearchCommentsPayload struct {
 	ReferenceID   string              `json:"referenceId"`
 	ReferenceType string              `json:"referenceType"`
 	Filters       *snCommentFilters   `json:"filters,omitempty"`
+	SortBy        *snCaseSort         `json:"sortBy,omitempty"`
 	Pagination    snProjectPagination `json:"pagination"`
 }
 
@@ -1170,6 +1171,16 @@ func (s *snCaseService) SearchCaseComments(ctx context.Context, req domain.Searc
 		}
 		payload.Filters = &snCommentFilters{Type: snType}
 	}
+	if err := validateCommentSort(req.SortBy); err != nil {
+		return domain.SearchCaseCommentsResponse{}, err
+	}
+	if req.SortBy != nil {
+		order := string(req.SortBy.Order)
+		if order == "" {
+			order = "desc"
+		}
+		payload.SortBy = &snCaseSort{Field: req.SortBy.Field, Order: order}
+	}
 
 	raw, err := s.client.Post(ctx, "/comments/search", token, payload)
 	if err != nil {
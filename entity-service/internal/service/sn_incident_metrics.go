@@ -0,0 +1,444 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/apierror"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/domain"
+)
+
+// incidentMetricsMaxIncidents caps how many incidents one metrics call reads.
+// ServiceNow has no server-side aggregation for time-to-milestone, so every
+// incident costs a detail read and an activity read; past this cap the
+// response reports Truncated rather than letting a wide range run for minutes.
+const incidentMetricsMaxIncidents = 500
+
+// incidentMetricsMaxActivityPages caps the activity pages read per incident.
+// Acknowledgement happens early in an incident's life and a reopen is rare
+// enough that a feed this long is already an outlier.
+const incidentMetricsMaxActivityPages = 4
+
+// incidentMetricsMaxRangeDays caps the createdOn range of one metrics call. A
+// year of weekly periods is already 53 points per series, each repeated per
+// group; a wider range would grow the response without bound.
+const incidentMetricsMaxRangeDays = 366
+
+// incidentMetricsConcurrency bounds the per-incident reads in flight at once,
+// so one metrics call cannot monopolize the integration service.
+const incidentMetricsConcurrency = 8
+
+// validIncidentMetricsGroupBy is the allow-list for
+// IncidentMetricsRequest.GroupBy, matching openapi.yaml's enum exactly.
+var validIncidentMetricsGroupBy = map[string]bool{
+	"priority":        true,
+	"category":        true,
+	"assignmentGroup": true,
+	"businessService": true,
+}
+
+// incidentSample is what one incident contributes to the metrics.
+type incidentSample struct {
+	opened       time.Time
+	acknowledged *time.Duration
+	resolved     *time.Duration
+	reopened     bool
+	groupKey     string
+	groupName    string
+}
+
+// incidentMetricsAccumulator sums samples into one domain.IncidentMetrics.
+type incidentMetricsAccumulator struct {
+	count, acknowledged, resolved, reopened int
+	ackSum, resolveSum                      time.Duration
+}
+
+func (a *incidentMetricsAccumulator) add(s incidentSample) {
+	a.count++
+	if s.acknowledged != nil {
+		a.acknowledged++
+		a.ackSum += *s.acknowledged
+	}
+	if s.resolved != nil {
+		a.resolved++
+		a.resolveSum += *s.resolved
+	}
+	if s.reopened {
+		a.reopened++
+	}
+}
+
+func (a *incidentMetricsAccumulator) metrics() domain.IncidentMetrics {
+	m := domain.IncidentMetrics{
+		Count:        a.count,
+		Acknowledged: a.acknowledged,
+		Resolved:     a.resolved,
+		Reopened:     a.reopened,
+	}
+	if a.acknowledged > 0 {
+		v := a.ackSum.Seconds() / float64(a.acknowledged)
+		m.MeanTimeToAcknowledgeSeconds = &v
+	}
+	if a.resolved > 0 {
+		v := a.resolveSum.Seconds() / float64(a.resolved)
+		m.MeanTimeToResolveSeconds = &v
+		rate := float64(a.reopened) / float64(a.resolved)
+		m.ReopenRate = &rate
+	}
+	return m
+}
+
+// IncidentMetrics implements IncidentService. It reads the incidents opened in
+// the requested range through SearchIncidents (so every search filter applies
+// unchanged), then each one's detail and activity feed, and derives:
+//
+//   - acknowledged: the first field change moving state out of NEW, or the
+//     first assignment to an engineer, whichever came first.
+//   - resolved: the incident's own resolution time.
+//   - reopened: any field change moving state out of RESOLVED or CLOSED into
+//     a state other than CLOSED or CANCELLED.
+//
+// An incident whose detail or activities cannot be read fails the whole call:
+// silently dropping it would skew every mean without saying so.
+func (s *snIncidentService) IncidentMetrics(ctx context.Context, req domain.IncidentMetricsRequest) (domain.IncidentMetricsResponse, error) {
+	if req.GroupBy != "" && !validIncidentMetricsGroupBy[req.GroupBy] {
+		return domain.IncidentMetricsResponse{}, &apierror.ValidationError{Msg: "groupBy contains invalid value: " + req.GroupBy}
+	}
+	switch req.Interval {
+	case "":
+		req.Interval = domain.IncidentMetricsIntervalWeek
+	case domain.IncidentMetricsIntervalWeek, domain.IncidentMetricsIntervalMonth:
+	default:
+		return domain.IncidentMetricsResponse{}, &apierror.ValidationError{Msg: "interval contains invalid value: " + string(req.Interval)}
+	}
+	parsed, err := ParseIncidentFieldFilters(req.Filters.Filters, time.Now().UTC())
+	if err != nil {
+		return domain.IncidentMetricsResponse{}, err
+	}
+	if parsed.StartCreatedDate == nil || parsed.EndCreatedDate == nil {
+		return domain.IncidentMetricsResponse{}, &apierror.ValidationError{Msg: "filters: createdOn gte and lte are both required"}
+	}
+	from, to := parsed.StartCreatedDate.UTC(), parsed.EndCreatedDate.UTC()
+	if to.Before(from) {
+		return domain.IncidentMetricsResponse{}, &apierror.ValidationError{Msg: "filters: createdOn lte must not be before gte"}
+	}
+	if to.Sub(from) > incidentMetricsMaxRangeDays*24*time.Hour {
+		return domain.IncidentMetricsResponse{}, &apierror.ValidationError{Msg: fmt.Sprintf("filters: createdOn range must not exceed %d days", incidentMetricsMaxRangeDays)}
+	}
+
+	var incidents []domain.SearchIncidentView
+	total := 0
+	for len(incidents) < incidentMetricsMaxIncidents {
+		page, err := s.SearchIncidents(ctx, domain.SearchIncidentsRequest{
+			Filters:    req.Filters,
+			SortBy:     domain.IncidentSort{Field: domain.IncidentSortFieldCreatedOn, Order: domain.IncidentSortOrderAsc},
+			Pagination: domain.Pagination{Limit: maxLimit, Offset: len(incidents)},
+		})
+		if err != nil {
+			return domain.IncidentMetricsResponse{}, err
+		}
+		total = page.Total
+		incidents = append(incidents, page.Incidents...)
+		if len(page.Incidents) < maxLimit || len(incidents) >= total {
+			break
+		}
+	}
+	if len(incidents) > incidentMetricsMaxIncidents {
+		incidents = incidents[:incidentMetricsMaxIncidents]
+	}
+
+	// Every ID is checked before the first read starts, so a bad search result
+	// never leaves reads running behind an early return.
+	ids := make([]string, len(incidents))
+	for i, inc := range incidents {
+		if inc.ID == nil {
+			return domain.IncidentMetricsResponse{}, fmt.Errorf("sn incident metrics: search returned an incident with no id")
+		}
+		ids[i] = *inc.ID
+	}
+
+	samples := make([]incidentSample, len(ids))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(incidentMetricsConcurrency)
+	for i, id := range ids {
+		eg.Go(func() error {
+			sample, err := s.incidentMetricsSample(egCtx, id, req.GroupBy)
+			if err != nil {
+				return err
+			}
+			samples[i] = sample
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return domain.IncidentMetricsResponse{}, err
+	}
+
+	return buildIncidentMetricsResponse(req, from, to, total, samples), nil
+}
+
+// incidentMetricsSample reads one incident's detail and activity feed and
+// reduces them to an incidentSample.
+func (s *snIncidentService) incidentMetricsSample(ctx context.Context, id, groupBy string) (incidentSample, error) {
+	view, err := s.GetIncidentByID(ctx, id)
+	if err != nil {
+		return incidentSample{}, fmt.Errorf("sn incident metrics: get incident %s: %w", id, err)
+	}
+
+	openedRaw := view.CreatedOn
+	if view.OpenedOn != nil && *view.OpenedOn != "" {
+		openedRaw = *view.OpenedOn
+	}
+	opened, err := parseSNDateTime(ctx, "IncidentMetrics", "openedOn", openedRaw)
+	if err != nil {
+		return incidentSample{}, fmt.Errorf("sn incident metrics: parse openedOn of incident %s: %w", id, err)
+	}
+	sample := incidentSample{opened: opened}
+	sample.groupKey, sample.groupName = incidentMetricsGroup(view, groupBy)
+
+	if view.ResolvedOn != nil && *view.ResolvedOn != "" {
+		resolvedOn, err := parseSNDateTime(ctx, "IncidentMetrics", "resolvedOn", *view.ResolvedOn)
+		if err != nil {
+			return incidentSample{}, fmt.Errorf("sn incident metrics: parse resolvedOn of incident %s: %w", id, err)
+		}
+		d := clampNonNegative(resolvedOn.Sub(opened))
+		sample.resolved = &d
+	}
+
+	var activity []domain.CaseActivity
+	includeFieldChanges := true
+	for page := 0; page < incidentMetricsMaxActivityPages; page++ {
+		resp, err := s.SearchIncidentActivities(ctx, domain.SearchIncidentActivitiesRequest{
+			IncidentID:          id,
+			Pagination:          domain.Pagination{Limit: maxLimit, Offset: len(activity)},
+			IncludeFieldChanges: &includeFieldChanges,
+		})
+		if err != nil {
+			return incidentSample{}, fmt.Errorf("sn incident metrics: search activities of incident %s: %w", id, err)
+		}
+		activity = append(activity, resp.Activity...)
+		if !resp.HasMore || len(resp.Activity) == 0 {
+			break
+		}
+		if page == incidentMetricsMaxActivityPages-1 {
+			slog.WarnContext(ctx, "incident metrics: activity feed truncated", "incidentID", id, "read", len(activity), "total", resp.Total)
+		}
+	}
+	sort.SliceStable(activity, func(i, j int) bool { return activity[i].CreatedOn.Before(activity[j].CreatedOn) })
+
+	for _, a := range activity {
+		if a.Type != domain.ActivityTypeFieldChange {
+			continue
+		}
+		for _, ch := range a.Changes {
+			switch ch.Field {
+			case "state", "incident_state":
+				prev, next := normalizeIncidentStateValue(ch.PreviousValue), normalizeIncidentStateValue(ch.NewValue)
+				if sample.acknowledged == nil && prev == domain.IncidentStateNew && next != domain.IncidentStateNew {
+					d := clampNonNegative(a.CreatedOn.Sub(opened))
+					sample.acknowledged = &d
+				}
+				if (prev == domain.IncidentStateResolved || prev == domain.IncidentStateClosed) &&
+					next != "" && next != domain.IncidentStateResolved &&
+					next != domain.IncidentStateClosed && next != domain.IncidentStateCancelled {
+					sample.reopened = true
+				}
+			case "assigned_to":
+				if sample.acknowledged == nil && ch.NewValue != "" {
+					d := clampNonNegative(a.CreatedOn.Sub(opened))
+					sample.acknowledged = &d
+				}
+			}
+		}
+	}
+	return sample, nil
+}
+
+// normalizeIncidentStateValue maps a state value as it appears in an
+// activity field change -- a ServiceNow display label ("In Progress"), a raw
+// numeric key ("2"), or the domain enum itself -- to a domain.IncidentState.
+// Unrecognized values map to "".
+func normalizeIncidentStateValue(v string) domain.IncidentState {
+	v = strings.TrimSpace(v)
+	var key int
+	if _, err := fmt.Sscanf(v, "%d", &key); err == nil {
+		return domain.IncidentState(snIncidentStateLabelMap[key])
+	}
+	state := domain.IncidentState(strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(v)))
+	if state == "CANCELED" {
+		state = domain.IncidentStateCancelled
+	}
+	if !validIncidentState[state] {
+		return ""
+	}
+	return state
+}
+
+// incidentMetricsGroup returns the GroupBy key and display name of an incident.
+func incidentMetricsGroup(view domain.IncidentView, groupBy string) (string, string) {
+	switch groupBy {
+	case "priority":
+		if view.Priority != nil {
+			return *view.Priority, *view.Priority
+		}
+	case "category":
+		if view.Category != nil {
+			return *view.Category, *view.Category
+		}
+	case "assignmentGroup":
+		if view.AssignmentGroup != nil {
+			return view.AssignmentGroup.ID, view.AssignmentGroup.Name
+		}
+	case "businessService":
+		if view.Service != nil {
+			return view.Service.ID, view.Service.Name
+		}
+	}
+	return "", ""
+}
+
+// buildIncidentMetricsResponse folds the samples into the overall metrics,
+// the series, and the per-group breakdown. Every period between from and to
+// is present in each series, empty or not, so a chart never has to fill gaps.
+func buildIncidentMetricsResponse(req domain.IncidentMetricsRequest, from, to time.Time, total int, samples []incidentSample) domain.IncidentMetricsResponse {
+	periods := incidentMetricsPeriods(from, to, req.Interval)
+
+	var overall incidentMetricsAccumulator
+	overallSeries := make([]incidentMetricsAccumulator, len(periods))
+	type groupAcc struct {
+		name    string
+		overall incidentMetricsAccumulator
+		series  []incidentMetricsAccumulator
+	}
+	groups := map[string]*groupAcc{}
+
+	for _, s := range samples {
+		overall.add(s)
+		p := incidentMetricsPeriodIndex(periods, s.opened, req.Interval)
+		if p >= 0 {
+			overallSeries[p].add(s)
+		}
+		if req.GroupBy == "" {
+			continue
+		}
+		g, ok := groups[s.groupKey]
+		if !ok {
+			g = &groupAcc{name: s.groupName, series: make([]incidentMetricsAccumulator, len(periods))}
+			groups[s.groupKey] = g
+		}
+		g.overall.add(s)
+		if p >= 0 {
+			g.series[p].add(s)
+		}
+	}
+
+	series := func(accs []incidentMetricsAccumulator) []domain.IncidentMetricsPoint {
+		points := make([]domain.IncidentMetricsPoint, len(periods))
+		for i := range periods {
+			points[i] = domain.IncidentMetricsPoint{PeriodStart: periods[i], IncidentMetrics: accs[i].metrics()}
+		}
+		return points
+	}
+
+	resp := domain.IncidentMetricsResponse{
+		From:      from,
+		To:        to,
+		Interval:  req.Interval,
+		GroupBy:   req.GroupBy,
+		Total:     total,
+		Analyzed:  len(samples),
+		Truncated: len(samples) < total,
+		Overall:   overall.metrics(),
+		Series:    series(overallSeries),
+	}
+	if req.GroupBy != "" {
+		resp.Groups = make([]domain.IncidentMetricsGroup, 0, len(groups))
+		for key, g := range groups {
+			resp.Groups = append(resp.Groups, domain.IncidentMetricsGroup{
+				Key:     key,
+				Name:    g.name,
+				Metrics: g.overall.metrics(),
+				Series:  series(g.series),
+			})
+		}
+		// Largest group first, then by key, so the order is stable.
+		sort.Slice(resp.Groups, func(i, j int) bool {
+			if resp.Groups[i].Metrics.Count != resp.Groups[j].Metrics.Count {
+				return resp.Groups[i].Metrics.Count > resp.Groups[j].Metrics.Count
+			}
+			return resp.Groups[i].Key < resp.Groups[j].Key
+		})
+	}
+	return resp
+}
+
+// incidentMetricsPeriodStart returns the UTC start of the week (Monday) or
+// month containing t.
+func incidentMetricsPeriodStart(t time.Time, interval domain.IncidentMetricsInterval) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == domain.IncidentMetricsIntervalMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// incidentMetricsPeriods returns the start of every period from the one
+// containing from through the one containing to.
+func incidentMetricsPeriods(from, to time.Time, interval domain.IncidentMetricsInterval) []time.Time {
+	var periods []time.Time
+	for p := incidentMetricsPeriodStart(from, interval); !p.After(to); {
+		periods = append(periods, p)
+		if interval == domain.IncidentMetricsIntervalMonth {
+			p = p.AddDate(0, 1, 0)
+		} else {
+			p = p.AddDate(0, 0, 7)
+		}
+	}
+	return periods
+}
+
+// incidentMetricsPeriodIndex returns the index of the period containing t, or
+// -1 when t falls outside every period.
+func incidentMetricsPeriodIndex(periods []time.Time, t time.Time, interval domain.IncidentMetricsInterval) int {
+	start := incidentMetricsPeriodStart(t, interval)
+	for i, p := range periods {
+		if p.Equal(start) {
+			return i
+		}
+	}
+	return -1
+}
+
+// clampNonNegative guards the means against clock skew between the incident's
+// opened time and the activity feed: a milestone recorded "before" the
+// incident opened counts as immediate, not as negative time.
+func clampNonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package service
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/apierror"
+	"github.com/wso2-open-operations/cs-tools/entity-service/internal/domain"
+)
+
+const (
+	metricsIncA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	metricsIncB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+// metricsRange is the createdOn pair every valid metrics request carries.
+var metricsRange = []domain.IncidentFieldFilter{
+	{Field: "createdOn", Op: "gte", Values: []string{"2026-03-02"}},
+	{Field: "createdOn", Op: "lte", Values: []string{"2026-03-15"}},
+}
+
+// newMetricsTestService serves two incidents opened in consecutive weeks:
+// A is CRITICAL, acknowledged after 30 minutes (New -> In Progress), resolved
+// after 4 hours and later reopened; B is LOW, acknowledged by assignment after
+// 10 minutes and never resolved.
+func newMetricsTestService(t *testing.T) IncidentService {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/incidents/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"incidents":[{"id":"` + metricsIncA + `"},{"id":"` + metricsIncB + `"}],"totalRecords":2,"offset":0,"limit":50}`))
+	})
+	mux.HandleFunc("/incidents/"+metricsIncA, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + metricsIncA + `","openedOn":"2026-03-03 08:00:00","priority":{"id":1,"label":"1 - Critical"},` +
+			`"resolved":"2026-03-03 12:00:00","createdOn":"2026-03-03 08:00:00"}`))
+	})
+	mux.HandleFunc("/incidents/"+metricsIncB, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + metricsIncB + `","openedOn":"2026-03-10 09:00:00","priority":{"id":4,"label":"4 - Low"},` +
+			`"createdOn":"2026-03-10 09:00:00"}`))
+	})
+	mux.HandleFunc("/incidents/"+metricsIncA+"/activities/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Newest first, as the feed is served: the service must not depend on order.
+		_, _ = w.Write([]byte(`{"activity":[
+			{"id":"3","type":"field_change","createdOn":"2026-03-04 09:00:00","changes":[{"field":"state","previousValue":"Resolved","newValue":"In Progress"}]},
+			{"id":"2","type":"field_change","createdOn":"2026-03-03 12:00:00","changes":[{"field":"state","previousValue":"In Progress","newValue":"Resolved"}]},
+			{"id":"1","type":"field_change","createdOn":"2026-03-03 08:30:00","changes":[{"field":"state","previousValue":"New","newValue":"In Progress"}]}
+		],"totalRecords":3,"offset":0,"limit":50}`))
+	})
+	mux.HandleFunc("/incidents/"+metricsIncB+"/activities/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"activity":[
+			{"id":"1","type":"field_change","createdOn":"2026-03-10 09:10:00","changes":[{"field":"assigned_to","previousValue":"","newValue":"Jane Doe"}]}
+		],"totalRecords":1,"offset":0,"limit":50}`))
+	})
+	return NewServiceNowIncidentService(newTestSNClient(t, mux))
+}
+
+func TestSNIncidentService_IncidentMetrics_ComputesMeansAndReopenRate(t *testing.T) {
+	svc := newMetricsTestService(t)
+	resp, err := svc.IncidentMetrics(contextWithUserIDToken("token"), domain.IncidentMetricsRequest{
+		Filters: domain.SearchIncidentsFilters{Filters: metricsRange},
+		GroupBy: "priority",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := resp.Overall
+	if o.Count != 2 || o.Acknowledged != 2 || o.Resolved != 1 || o.Reopened != 1 {
+		t.Fatalf("overall counts = %+v, want count 2, acknowledged 2, resolved 1, reopened 1", o)
+	}
+	if o.MeanTimeToAcknowledgeSeconds == nil || *o.MeanTimeToAcknowledgeSeconds != 20*60 {
+		t.Errorf("MTTA = %v, want 1200s (mean of 30m and 10m)", o.MeanTimeToAcknowledgeSeconds)
+	}
+	if o.MeanTimeToResolveSeconds == nil || *o.MeanTimeToResolveSeconds != 4*3600 {
+		t.Errorf("MTTR = %v, want 14400s", o.MeanTimeToResolveSeconds)
+	}
+	if o.ReopenRate == nil || *o.ReopenRate != 1 {
+		t.Errorf("reopen rate = %v, want 1", o.ReopenRate)
+	}
+
+	if len(resp.Series) != 2 {
+		t.Fatalf("series has %d points, want 2 weeks", len(resp.Series))
+	}
+	if !resp.Series[0].PeriodStart.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) || resp.Series[0].Count != 1 || resp.Series[1].Count != 1 {
+		t.Errorf("series = %+v, want one incident in each of the weeks of 2026-03-02 and 2026-03-09", resp.Series)
+	}
+
+	if len(resp.Groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(resp.Groups))
+	}
+	byKey := map[string]domain.IncidentMetricsGroup{}
+	for _, g := range resp.Groups {
+		byKey[g.Key] = g
+	}
+	if low := byKey["LOW"]; low.Metrics.Count != 1 || low.Metrics.MeanTimeToResolveSeconds != nil || low.Metrics.ReopenRate != nil {
+		t.Errorf("LOW group = %+v, want one unresolved incident with nil MTTR and reopen rate", low.Metrics)
+	}
+	if resp.Truncated || resp.Analyzed != 2 || resp.Total != 2 {
+		t.Errorf("analyzed %d of %d (truncated %v), want 2 of 2", resp.Analyzed, resp.Total, resp.Truncated)
+	}
+}
+
+func TestSNIncidentService_IncidentMetrics_Validation(t *testing.T) {
+	svc := NewServiceNowIncidentService(newTestSNClient(t, http.NewServeMux()))
+	for _, tc := range []struct {
+		name string
+		req  domain.IncidentMetricsRequest
+	}{
+		{"missing range", domain.IncidentMetricsRequest{}},
+		{"open-ended range", domain.IncidentMetricsRequest{Filters: domain.SearchIncidentsFilters{Filters: metricsRange[:1]}}},
+		{"unknown groupBy", domain.IncidentMetricsRequest{Filters: domain.SearchIncidentsFilters{Filters: metricsRange}, GroupBy: "state"}},
+		{"unknown interval", domain.IncidentMetricsRequest{Filters: domain.SearchIncidentsFilters{Filters: metricsRange}, Interval: "day"}},
+		{"reversed range", domain.IncidentMetricsRequest{Filters: domain.SearchIncidentsFilters{Filters: []domain.IncidentFieldFilter{
+			{Field: "createdOn", Op: "gte", Values: []string{"2026-03-15"}},
+			{Field: "createdOn", Op: "lte", Values: []string{"2026-03-02"}},
+		}}}},
+		{"range over a year", domain.IncidentMetricsRequest{Filters: domain.SearchIncidentsFilters{Filters: []domain.IncidentFieldFilter{
+			{Field: "createdOn", Op: "gte", Values: []string{"2024-01-01"}},
+			{Field: "createdOn", Op: "lte", Values: []string{"2026-03-02"}},
+		}}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.IncidentMetrics(contextWithUserIDToken("token"), tc.req)
+			var ve *apierror.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("err = %v, want a ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestIncidentMetricsPeriods_Monthly(t *testing.T) {
+	got := incidentMetricsPeriods(
+		time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		domain.IncidentMetricsIntervalMonth)
+	if len(got) != 3 || got[0].Month() != time.January || got[2].Month() != time.March || got[0].Day() != 1 {
+		t.Errorf("periods = %v, want the first of January, February and March", got)
+	}
+}
+
+func TestNormalizeIncidentStateValue(t *testing.T) {
+	for in, want := range map[string]domain.IncidentState{
+		"New": domain.IncidentStateNew, "In Progress": domain.IncidentStateInProgress,
+		"6": domain.IncidentStateResolved, "Canceled": domain.IncidentStateCancelled,
+		"ON_HOLD": domain.IncidentStateOnHold, "Awaiting Caller": "",
+	} {
+		if got := normalizeIncidentStateValue(in); got != want {
+			t.Errorf("normalizeIncidentStateValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestSNIncidentService_IncidentMetrics_MissingIDReadsNothing verifies a
+// search result without an id fails the call before any detail read starts.
+func TestSNIncidentService_IncidentMetrics_MissingIDReadsNothing(t *testing.T) {
+	var detailReads atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/incidents/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"incidents":[{"id":"` + metricsIncA + `"},{}],"totalRecords":2,"offset":0,"limit":50}`))
+	})
+	mux.HandleFunc("/incidents/", func(w http.ResponseWriter, r *http.Request) {
+		detailReads.Add(1)
+		http.NotFound(w, r)
+	})
+	svc := NewServiceNowIncidentService(newTestSNClient(t, mux))
+
+	_, err := svc.IncidentMetrics(contextWithUserIDToken("token"), domain.IncidentMetricsRequest{
+		Filters: domain.SearchIncidentsFilters{Filters: metricsRange},
+	})
+	if err == nil {
+		t.Fatal("err = nil, want the missing id reported")
+	}
+	if n := detailReads.Load(); n != 0 {
+		t.Errorf("%d detail reads started, want 0", n)
+	}
+}
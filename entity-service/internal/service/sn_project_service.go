@@ -60,6 +60,7 @@ type snProject struct {
 	StartDate *string                 `json:"startDate"`
 	EndDate   string                  `json:"endDate"`
 	CreatedOn string                  `json:"createdOn"`
+	UpdatedOn string                  `json:"updatedOn"`
 	Account   snProjectSummaryAccount `json:"account"`
 	snProjectClosureFields
 }
@@ -89,6 +90,7 @@ type snProjectFilters struct {
 	SortBy        string `json:"sortBy,omitempty"`
 	SortOrder     string `json:"sortOrder,omitempty"`
 	AccountID     string `json:"accountId,omitempty"`
+	UpdatedFrom   string `json:"updatedFrom,omitempty"`
 }
 
 type snProjectPagination struct {
@@ -184,6 +186,7 @@ func (s *snProjectService) SearchProjects(ctx context.Context, req domain.Search
 			SortBy:        req.SortBy,
 			SortOrder:     req.SortOrder,
 			AccountID:     accountSysid,
+			UpdatedFrom:   formatSNDateTimeUTC(req.UpdatedFrom),
 		},
 		Pagination: snProjectPagination{Limit: req.Pagination.Limit, Offset: req.Pagination.Offset},
 	}
@@ -223,6 +226,14 @@ func (s *snProjectService) SearchProjects(ctx context.Context, req domain.Search
 			}
 			endDate = &parsed
 		}
+		var updatedOn *time.Time
+		if p.UpdatedOn != "" {
+			parsed, err := parseSNDateTime(ctx, "SearchProjects", "updatedOn", p.UpdatedOn)
+			if err != nil {
+				return domain.SearchProjectsResponse{}, fmt.Errorf("sn projects: parse updatedOn %q: %w", p.UpdatedOn, err)
+			}
+			updatedOn = &parsed
+		}
 		var account *domain.EntityRef
 		if p.Account.ID != "" {
 			account = &domain.EntityRef{ID: sysidToUUID(p.Account.ID), Name: p.Account.Name}
@@ -235,6 +246,7 @@ func (s *snProjectService) SearchProjects(ctx context.Context, req domain.Search
 			StartDate:        startDate,
 			EndDate:          endDate,
 			CreatedOn:        createdOn,
+			UpdatedOn:        updatedOn,
 			Account:          account,
 			ProjectClosureFields: domain.ProjectClosureFields{
 				ClosureState:                    p.ClosureState,
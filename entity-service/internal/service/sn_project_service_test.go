@@ -152,6 +152,57 @@ func TestSNProjectService_SearchProjects_MapsStartDate(t *testing.T) {
 	}
 }
 
+// TestSNProjectService_SearchProjects_UpdatedOn verifies that updatedFrom is
+// forwarded in the integration service's UTC filter format, and that each
+// result's updatedOn is mapped, blank to nil.
+func TestSNProjectService_SearchProjects_UpdatedOn(t *testing.T) {
+	var filters map[string]any
+	client := newTestSNClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Filters map[string]any `json:"filters"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		filters = body.Filters
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"projects": []map[string]any{
+				{
+					"id": "11111111111111111111111111111111", "name": "Updated", "key": "UP",
+					"type":    map[string]any{"name": "Subscription"},
+					"endDate": "", "createdOn": "2024-01-01 00:00:00", "updatedOn": "2026-10-19 08:30:00",
+					"account": map[string]any{"id": "", "name": ""},
+				},
+				{
+					"id": "22222222222222222222222222222222", "name": "Blank", "key": "BL",
+					"type":    map[string]any{"name": "Subscription"},
+					"endDate": "", "createdOn": "2024-01-01 00:00:00", "updatedOn": "",
+					"account": map[string]any{"id": "", "name": ""},
+				},
+			},
+			"totalRecords": 2, "offset": 0, "limit": 10,
+		})
+	}))
+
+	from := time.Date(2026, 10, 19, 14, 0, 0, 0, time.FixedZone("IST", 5*3600+1800))
+	svc := NewServiceNowProjectService(client, nil)
+	resp, err := svc.SearchProjects(contextWithUserIDToken("token"), domain.SearchProjectsRequest{
+		Pagination:  domain.Pagination{Limit: 10},
+		UpdatedFrom: &from,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filters["updatedFrom"] != "2026-10-19T08:30:00Z" {
+		t.Fatalf("updatedFrom filter = %v, want 2026-10-19T08:30:00Z", filters["updatedFrom"])
+	}
+	if got := resp.Projects[0].UpdatedOn; got == nil || !got.Equal(time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected UpdatedOn: %v", got)
+	}
+	if got := resp.Projects[1].UpdatedOn; got != nil {
+		t.Fatalf("expected nil UpdatedOn for a blank updatedOn, got %v", *got)
+	}
+}
+
 // The contact id is optional upstream: absent on an instance that predates the field, and
 // null for a row with no linked contact record. Neither case may produce a bogus id — the
 // caller uses emptiness to decide whether the row is clickable.
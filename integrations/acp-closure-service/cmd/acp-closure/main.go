@@ -16,27 +16,55 @@
 
 // Command acp-closure runs one full ACP evaluation pass and exits — the
 // Choreo Scheduled/Manual Task's cron owns the schedule, not this process.
+//
+// With --forecast-days N it instead simulates the next N daily runs,
+// starting today, and prints which project gets which notice or suspension
+// on which date. A forecast writes and sends nothing, whatever DRY_RUN says.
+//
+// With --serve it instead stays up as a service: runs are started through
+// an HTTP API (POST /runs) and, optionally, a daily in-process schedule,
+// never more than one at a time.
+//
+// With --render-samples it prints every notice template rendered against a
+// fixture project and exits, for reviewing a wording change before it ships.
+//
+// With --list-approvals, --approve ID or --reject ID it lists or decides
+// suspensions awaiting approval in APPROVALS_FILE and exits; a decision
+// takes effect on the next run.
 package main
 
 import (
 	"bufio"
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
+	"math"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/entity"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/report"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/runlock"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/runs"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/server"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/sweep"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/templates"
 )
 
-// projectUpdater is declared locally so main can hold either the real
+// projectUpdater is declared locally so sweepOnce can hold either the real
 // *entity.Client or *sweep.DryRunProjectUpdater in one variable — Go
 // interface satisfaction is structural, so neither concrete type needs to
 // reference this declaration.
@@ -45,14 +73,59 @@ type projectUpdater interface {
 }
 
 func main() {
+	forecastDays := flag.Int("forecast-days", 0, "simulate the next N daily runs and print the notice calendar instead of running")
+	serve := flag.Bool("serve", false, "run as a long-running service with the run API and an optional daily schedule")
+	renderSamples := flag.Bool("render-samples", false, "print every notice template rendered against a fixture project and exit")
+	listApprovals := flag.Bool("list-approvals", false, "print every suspension approval in APPROVALS_FILE as JSON and exit")
+	approve := flag.String("approve", "", "approve the pending suspension of this project ID and exit")
+	reject := flag.String("reject", "", "reject the pending suspension of this project ID (needs --reason) and exit")
+	decidedBy := flag.String("by", "", "who is deciding, for --approve/--reject")
+	reason := flag.String("reason", "", "why, for --approve/--reject; required to reject")
+	flag.Parse()
+
 	loadDotEnv(".env")
 
+	approvalStore, approvalTiers, err := approvalConfig()
+	if err != nil {
+		slog.Error("invalid approval configuration", "err", err)
+		os.Exit(1)
+	}
+	if *listApprovals || *approve != "" || *reject != "" {
+		os.Exit(runApprovals(approvalStore, *listApprovals, *approve, *reject, *decidedBy, *reason))
+	}
+
+	noticeTemplates, err := loadNoticeTemplates(os.Getenv("NOTICE_TEMPLATES_DIR"))
+	if err != nil {
+		slog.Error("invalid notice templates", "dir", os.Getenv("NOTICE_TEMPLATES_DIR"), "err", err)
+		os.Exit(1)
+	}
+	if *renderSamples {
+		if err := noticeTemplates.WriteSamples(os.Stdout); err != nil {
+			slog.Error("failed to render samples", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+	sweep.UseNoticeTemplates(noticeTemplates)
+
 	dryRun := envBool("DRY_RUN", true)
 	testProjectID := os.Getenv("TEST_PROJECT_ID")
 	excludedProjectIDs := parseExcludedProjectIDs(os.Getenv("EXCLUDED_PROJECT_IDS"))
+	concurrency, rateLimit, rateBurst, err := sweepLimits()
+	if err != nil {
+		slog.Error("invalid sweep limits", "err", err)
+		os.Exit(1)
+	}
 	runID := newRunID()
 
-	slog.Info("acp-closure-service starting", "runID", runID, "dryRun", dryRun, "testProjectID", testProjectID, "excludedProjectIDs", sortedKeys(excludedProjectIDs))
+	cfg := sweepConfig{
+		excludedProjectIDs: excludedProjectIDs,
+		concurrency:        concurrency,
+		approvals:          approvalStore,
+		approvalTiers:      approvalTiers,
+	}
+
+	slog.Info("acp-closure-service starting", "runID", runID, "dryRun", dryRun, "testProjectID", testProjectID, "excludedProjectIDs", sortedKeys(excludedProjectIDs), "approvalTiers", approvalTiers)
 
 	entityClient := entity.NewClient(entity.Config{
 		BaseURL:      mustEnv("CSM_INTEGRATION_BASE_URL"),
@@ -60,48 +133,312 @@ func main() {
 		ClientID:     mustEnv("CSM_INTEGRATION_CLIENT_ID"),
 		ClientSecret: mustEnv("CSM_INTEGRATION_CLIENT_SECRET"),
 		Scopes:       strings.Fields(mustEnv("CSM_INTEGRATION_SCOPES")),
+		RateLimit:    rateLimit,
+		RateBurst:    rateBurst,
 	})
 
-	var updater projectUpdater = entityClient
-	if dryRun {
-		updater = &sweep.DryRunProjectUpdater{}
+	if *forecastDays != 0 {
+		os.Exit(runForecast(entity.WithCorrelationID(context.Background(), runID), entityClient, *forecastDays, testProjectID, excludedProjectIDs))
 	}
 
-	notifier := &notify.LoggingNotifier{Logger: slog.Default()}
+	var runLock *runlock.File
+	if path := os.Getenv("RUN_LOCK_FILE"); path != "" {
+		runLock = runlock.New(path)
+	}
 
-	ctx := entity.WithCorrelationID(context.Background(), runID)
+	if *serve {
+		os.Exit(runService(entityClient, cfg, dryRun, testProjectID, runLock))
+	}
 
-	result, err := sweep.Run(ctx, entityClient, updater, notifier, time.Now(), testProjectID, excludedProjectIDs)
-	if err != nil {
-		slog.Error("acp-closure-service sweep failed", "runID", runID, "err", err)
+	if runLock != nil {
+		// Held until the process exits; the kernel drops it then.
+		if _, err := runLock.TryLock(); err != nil {
+			slog.Error("acp-closure-service sweep not started", "runID", runID, "lockFile", runLock.Path(), "err", err)
+			os.Exit(1)
+		}
+	}
+	r, reportErr := sweepOnce(context.Background(), entityClient, cfg, runID, runs.Request{ProjectID: testProjectID, DryRun: dryRun}, nil)
+	if r.Error != "" {
+		slog.Error("acp-closure-service sweep failed", "runID", runID, "err", r.Error)
 		os.Exit(1)
 	}
 
 	slog.Info("acp-closure-service finished",
 		"runID", runID,
 		"dryRun", dryRun,
-		"projectsEvaluated", result.ProjectsEvaluated,
-		"projectsExcluded", result.ProjectsExcluded,
-		"failureCount", len(result.Failures),
+		"projectsEvaluated", r.ProjectsEvaluated,
+		"projectsExcluded", r.ProjectsExcluded,
+		"failureCount", r.FailureCount,
 	)
-	for _, f := range result.Failures {
-		slog.Error("project failed", "runID", runID, "projectID", f.ProjectID, "err", f.Err)
+	for _, p := range r.Projects {
+		if p.Error != "" {
+			slog.Error("project failed", "runID", runID, "projectID", p.ProjectID, "err", p.Error)
+		}
+	}
+
+	os.Exit(exitCode(r.FailureCount, reportErr))
+}
+
+// loadNoticeTemplates loads and validates the notice templates in dir, or
+// the embedded defaults when dir is "". Done before anything else so a
+// broken template fails the process at startup rather than on the first
+// project that reaches the affected window.
+func loadNoticeTemplates(dir string) (*templates.Set, error) {
+	if dir == "" {
+		return templates.Default()
+	}
+	return templates.Load(os.DirFS(dir))
+}
+
+// sweepConfig is the process-wide configuration every sweep runs with,
+// one-shot or service mode.
+type sweepConfig struct {
+	excludedProjectIDs map[string]bool
+	concurrency        int
+	// approvals is APPROVALS_FILE's store, nil when unset; approvalTiers
+	// are SUSPENSION_APPROVAL_TIERS, and the approval gate is on only
+	// when there are any.
+	approvals     *approvals.Store
+	approvalTiers []string
+}
+
+// sweepOnce runs one sweep as runID and writes its report to REPORT_DIR,
+// returning the report and writeReport's error. Dry-run is injected here,
+// as the choice of projectUpdater and a read-only approval gate, for both
+// the one-shot CLI and every service-mode run, so the two cannot drift
+// apart.
+func sweepOnce(ctx context.Context, client *entity.Client, cfg sweepConfig, runID string, req runs.Request, progress sweep.Progress) (report.Report, error) {
+	var updater projectUpdater = client
+	if req.DryRun {
+		updater = &sweep.DryRunProjectUpdater{}
+	}
+	opts := sweep.Options{Concurrency: cfg.concurrency, Progress: progress}
+	if len(cfg.approvalTiers) > 0 {
+		opts.Gate = approvals.NewGate(cfg.approvals, cfg.approvalTiers, req.DryRun)
+	}
+
+	notifier := &notify.LoggingNotifier{Logger: slog.Default()}
+
+	ctx = entity.WithCorrelationID(ctx, runID)
+
+	startedAt := time.Now()
+	result, err := sweep.RunWithOptions(ctx, client, updater, notifier, startedAt, req.ProjectID, cfg.excludedProjectIDs, opts)
+	r := report.Build(runID, req.DryRun, req.ProjectID, startedAt, time.Now(), result, err)
+	return r, writeReport(os.Getenv("REPORT_DIR"), r)
+}
+
+// runService runs the service mode until SIGINT/SIGTERM: the run API on
+// PORT and, when RUN_SCHEDULE_UTC is set, a daily run at that time, both
+// going through one runs.Manager so two sweeps never overlap. With
+// RUN_LOCK_FILE set they also hold that lock, so sweeps in other replicas
+// or the one-shot CronJob don't overlap them either. The scheduled
+// run covers what the one-shot CLI would (TEST_PROJECT_ID, DRY_RUN). On
+// shutdown a run in progress is given until the shutdown deadline to
+// finish; the exit code is non-zero if it had to be abandoned. With
+// APPROVALS_FILE set, the API also lists and decides approvals.
+func runService(client *entity.Client, cfg sweepConfig, dryRun bool, testProjectID string, runLock *runlock.File) int {
+	manager := runs.NewManager(func(ctx context.Context, runID string, req runs.Request, progress sweep.Progress) report.Report {
+		r, _ := sweepOnce(ctx, client, cfg, runID, req, progress)
+		return r
+	}, newRunID, runLock)
+	if runLock == nil {
+		slog.Warn("RUN_LOCK_FILE is not set; runs are kept from overlapping within this process only, so run a single replica and not the one-shot CronJob alongside it")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if v := os.Getenv("RUN_SCHEDULE_UTC"); v != "" {
+		at, err := parseTimeOfDay(v)
+		if err != nil {
+			slog.Error("invalid RUN_SCHEDULE_UTC, want HH:MM", "value", v, "err", err)
+			return 1
+		}
+		go manager.ScheduleDaily(ctx, at, runs.Request{ProjectID: testProjectID, DryRun: dryRun})
+	}
+
+	addr := ":" + envOrDefault("PORT", "8080")
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		slog.Error("failed to bind", "addr", addr, "err", err)
+		return 1
+	}
+	slog.Info("acp-closure-service listening", "addr", addr)
+
+	srv := &http.Server{
+		Handler:           server.NewHandler(manager, dryRun, testProjectID, cfg.approvals).Routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			slog.Error("server exited", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("graceful shutdown failed", "err", err)
+		return 1
+	}
+	if !manager.Wait(shutdownCtx) {
+		slog.Error("shutting down with a run still in progress")
+		return 1
 	}
+	slog.Info("acp-closure-service stopped")
+	return 0
+}
 
-	os.Exit(exitCode(len(result.Failures)))
+// parseTimeOfDay parses RUN_SCHEDULE_UTC's HH:MM into an offset from
+// midnight.
+func parseTimeOfDay(v string) (time.Duration, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
 }
 
 // exitCode reports the process exit status for a completed sweep. A
 // scheduled Choreo task relies on the exit code as its alerting signal, so
 // any project failure — not just a fatal sweep-level error — must be
-// reported as non-zero; a fully green run is the only case that exits 0.
-func exitCode(failureCount int) int {
-	if failureCount > 0 {
+// reported as non-zero, as must a run report that could not be written:
+// auditors rely on there being one per run. A fully green run is the only
+// case that exits 0.
+func exitCode(failureCount int, reportErr error) int {
+	if failureCount > 0 || reportErr != nil {
 		return 1
 	}
 	return 0
 }
 
+// runForecast runs sweep.Forecast over days days from now and prints its
+// calendar to stdout, returning the exit code: non-zero on a fatal error or
+// any project failure, as for a real run.
+func runForecast(ctx context.Context, reader *entity.Client, days int, testProjectID string, excludedProjectIDs map[string]bool) int {
+	forecast, err := sweep.Forecast(ctx, reader, time.Now(), days, testProjectID, excludedProjectIDs)
+	if err != nil {
+		slog.Error("acp-closure-service forecast failed", "err", err)
+		return 1
+	}
+	if err := report.WriteForecast(os.Stdout, forecast); err != nil {
+		slog.Error("forecast could not be printed", "err", err)
+		return 1
+	}
+	return exitCode(len(forecast.Failures), nil)
+}
+
+// runApprovals lists every approval in store as JSON on stdout, or
+// approves or rejects one project's pending approval, returning the exit
+// code.
+func runApprovals(store *approvals.Store, list bool, approveID, rejectID, by, reason string) int {
+	if store == nil {
+		slog.Error("APPROVALS_FILE is not set")
+		return 1
+	}
+	if list {
+		all, err := store.List()
+		if err != nil {
+			slog.Error("failed to list approvals", "err", err)
+			return 1
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			slog.Error("failed to print approvals", "err", err)
+			return 1
+		}
+		return 0
+	}
+	if approveID != "" && rejectID != "" {
+		slog.Error("--approve and --reject are mutually exclusive")
+		return 1
+	}
+
+	projectID := approveID
+	if projectID == "" {
+		projectID = rejectID
+	}
+	decided, err := store.Decide(projectID, approveID != "", by, reason)
+	if err != nil {
+		slog.Error("failed to decide approval", "projectID", projectID, "err", err)
+		return 1
+	}
+	slog.Info("approval decided", "projectID", projectID, "state", decided.State, "decidedBy", decided.DecidedBy)
+	return 0
+}
+
+// approvalConfig reads SUSPENSION_APPROVAL_TIERS, the comma-separated
+// account tiers whose suspensions need approval, and APPROVALS_FILE, where
+// approvals are kept. The gate is off without tiers; with them, a missing
+// APPROVALS_FILE is an error rather than a gate that holds nothing — or
+// everything — without a record of it.
+func approvalConfig() (*approvals.Store, []string, error) {
+	var tiers []string
+	for raw := range strings.SplitSeq(os.Getenv("SUSPENSION_APPROVAL_TIERS"), ",") {
+		if tier := strings.ToLower(strings.TrimSpace(raw)); tier != "" {
+			tiers = append(tiers, tier)
+		}
+	}
+	path := os.Getenv("APPROVALS_FILE")
+	if len(tiers) > 0 && path == "" {
+		return nil, nil, errors.New("APPROVALS_FILE must be set when SUSPENSION_APPROVAL_TIERS is")
+	}
+	if path == "" {
+		return nil, tiers, nil
+	}
+	return approvals.NewStore(path), tiers, nil
+}
+
+// writeReport writes a run report to dir (REPORT_DIR), or does nothing when
+// dir is empty. A failure is logged and returned, never fatal by itself:
+// the sweep has already run, and its own outcome is still worth logging.
+func writeReport(dir string, r report.Report) error {
+	if dir == "" {
+		return nil
+	}
+	paths, err := report.Write(dir, r)
+	if err != nil {
+		slog.Error("run report could not be written", "runID", r.RunID, "dir", dir, "err", err)
+		return err
+	}
+	slog.Info("run report written", "runID", r.RunID, "paths", paths)
+	return nil
+}
+
+// sweepLimits reads SWEEP_CONCURRENCY (projects processed at once, default
+// 4), ENTITY_RATE_LIMIT (requests per second to csm-integration-service,
+// default 10, 0 for none) and ENTITY_RATE_BURST (default 5). Unlike
+// DRY_RUN, a malformed or out-of-range value is an error rather than a
+// fallback to the default: these only tune throughput, and a typo should
+// stop the run, not quietly run it at some other rate.
+func sweepLimits() (concurrency int, rateLimit float64, rateBurst int, err error) {
+	if concurrency, err = strconv.Atoi(envOrDefault("SWEEP_CONCURRENCY", "4")); err != nil || concurrency < 1 {
+		return 0, 0, 0, fmt.Errorf("SWEEP_CONCURRENCY must be a whole number of at least 1, got %q", os.Getenv("SWEEP_CONCURRENCY"))
+	}
+	if rateLimit, err = strconv.ParseFloat(envOrDefault("ENTITY_RATE_LIMIT", "10"), 64); err != nil || rateLimit < 0 || math.IsInf(rateLimit, 0) || math.IsNaN(rateLimit) {
+		return 0, 0, 0, fmt.Errorf("ENTITY_RATE_LIMIT must be a non-negative number of requests per second, got %q", os.Getenv("ENTITY_RATE_LIMIT"))
+	}
+	if rateBurst, err = strconv.Atoi(envOrDefault("ENTITY_RATE_BURST", "5")); err != nil || rateBurst < 1 {
+		return 0, 0, 0, fmt.Errorf("ENTITY_RATE_BURST must be a whole number of at least 1, got %q", os.Getenv("ENTITY_RATE_BURST"))
+	}
+	return concurrency, rateLimit, rateBurst, nil
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
 func mustEnv(key string) string {
 	v := os.Getenv(key)
 	if v == "" {
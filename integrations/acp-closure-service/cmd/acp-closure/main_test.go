@@ -17,8 +17,10 @@
 package main
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestParseExcludedProjectIDs covers EXCLUDED_PROJECT_IDS parsing: a
@@ -61,23 +63,100 @@ func TestParseExcludedProjectIDs(t *testing.T) {
 }
 
 // TestExitCode verifies the process reports failure to its caller (a
-// scheduled Choreo task) whenever any project failed during the sweep, and
-// success only when none did.
+// scheduled Choreo task) whenever any project failed during the sweep or the
+// run report could not be written, and success only when neither happened.
 func TestExitCode(t *testing.T) {
 	tests := []struct {
 		name         string
 		failureCount int
+		reportErr    error
 		wantExitCode int
 	}{
 		{name: "no failures", failureCount: 0, wantExitCode: 0},
 		{name: "one failure", failureCount: 1, wantExitCode: 1},
 		{name: "many failures", failureCount: 7, wantExitCode: 1},
+		{name: "report not written", failureCount: 0, reportErr: errors.New("disk full"), wantExitCode: 1},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := exitCode(tt.failureCount)
+			got := exitCode(tt.failureCount, tt.reportErr)
 			if got != tt.wantExitCode {
-				t.Errorf("exitCode(%d) = %d, want %d", tt.failureCount, got, tt.wantExitCode)
+				t.Errorf("exitCode(%d, %v) = %d, want %d", tt.failureCount, tt.reportErr, got, tt.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	if got, err := parseTimeOfDay("02:30"); err != nil || got != 2*time.Hour+30*time.Minute {
+		t.Errorf("parseTimeOfDay(02:30) = %v, %v", got, err)
+	}
+	for _, bad := range []string{"", "2:30pm", "24:00", "02:30:00"} {
+		if _, err := parseTimeOfDay(bad); err == nil {
+			t.Errorf("parseTimeOfDay(%q) error = nil, want an error", bad)
+		}
+	}
+}
+
+// TestSweepLimits covers the defaults and that a malformed or out-of-range
+// value is an error, not a silent fallback.
+func TestSweepLimits(t *testing.T) {
+	tests := []struct {
+		name                       string
+		concurrency, limit, burst  string
+		wantConcurrency, wantBurst int
+		wantLimit                  float64
+		wantErr                    bool
+	}{
+		{name: "defaults", wantConcurrency: 4, wantLimit: 10, wantBurst: 5},
+		{name: "set", concurrency: "8", limit: "2.5", burst: "1", wantConcurrency: 8, wantLimit: 2.5, wantBurst: 1},
+		{name: "no rate limit", limit: "0", wantConcurrency: 4, wantLimit: 0, wantBurst: 5},
+		{name: "zero concurrency", concurrency: "0", wantErr: true},
+		{name: "malformed concurrency", concurrency: "four", wantErr: true},
+		{name: "negative limit", limit: "-1", wantErr: true},
+		{name: "infinite limit", limit: "Inf", wantErr: true},
+		{name: "zero burst", burst: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SWEEP_CONCURRENCY", tt.concurrency)
+			t.Setenv("ENTITY_RATE_LIMIT", tt.limit)
+			t.Setenv("ENTITY_RATE_BURST", tt.burst)
+			concurrency, limit, burst, err := sweepLimits()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sweepLimits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (concurrency != tt.wantConcurrency || limit != tt.wantLimit || burst != tt.wantBurst) {
+				t.Errorf("sweepLimits() = %d, %v, %d; want %d, %v, %d", concurrency, limit, burst, tt.wantConcurrency, tt.wantLimit, tt.wantBurst)
+			}
+		})
+	}
+}
+
+// TestApprovalConfig covers tier parsing and that turning the gate on
+// without APPROVALS_FILE is an error.
+func TestApprovalConfig(t *testing.T) {
+	tests := []struct {
+		name, tiers, file string
+		wantTiers         []string
+		wantStore         bool
+		wantErr           bool
+	}{
+		{name: "off"},
+		{name: "file only", file: "approvals.json", wantStore: true},
+		{name: "on", tiers: " Enterprise, ,basic", file: "approvals.json", wantTiers: []string{"enterprise", "basic"}, wantStore: true},
+		{name: "tiers without file", tiers: "enterprise", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SUSPENSION_APPROVAL_TIERS", tt.tiers)
+			t.Setenv("APPROVALS_FILE", tt.file)
+			store, tiers, err := approvalConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("approvalConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tiers, tt.wantTiers) || (store != nil) != tt.wantStore {
+				t.Errorf("approvalConfig() = %v, %v; want store %v, tiers %v", store, tiers, tt.wantStore, tt.wantTiers)
 			}
 		})
 	}
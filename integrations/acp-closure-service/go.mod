@@ -2,4 +2,7 @@ module github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service
 
 go 1.26.5
 
-require golang.org/x/oauth2 v0.27.0
+require (
+	golang.org/x/oauth2 v0.27.0
+	golang.org/x/time v0.9.0
+)
@@ -0,0 +1,318 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package approvals is the human approval gate in front of day-0
+// suspensions. When SUSPENSION_APPROVAL_TIERS names an account tier, a due
+// suspension of a project in that tier is not executed: it becomes a pending
+// Approval, persisted in APPROVALS_FILE, until someone approves or rejects
+// it (through the service mode's API or the CLI). Only an approved one is
+// executed, on the next run after the decision; a rejected one is skipped,
+// with its reason on record, for as long as the project's end date stays
+// the one it was rejected for.
+//
+// Every Approval is tied to the end date it was requested for. A renewal —
+// or any other end-date change — makes the record stale: the next due
+// suspension starts a fresh pending Approval, whatever the old one said.
+//
+// The file is re-read before and rewritten (atomically, via rename) after
+// every change rather than cached, so the CLI can decide an approval while
+// a service-mode process is running against the same file. Two processes
+// writing at the same instant can still lose one change; deciding
+// approvals is a human-paced action, and that window is accepted.
+package approvals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State is where an Approval is.
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateApproved State = "approved"
+	StateRejected State = "rejected"
+)
+
+var (
+	// ErrNotFound is returned by Decide for a project with no Approval.
+	ErrNotFound = errors.New("approvals: no approval for this project")
+	// ErrNotPending is returned by Decide for an Approval already decided.
+	ErrNotPending = errors.New("approvals: approval is not pending")
+	// ErrDecidedByRequired is returned by Decide without a decider.
+	ErrDecidedByRequired = errors.New("approvals: decidedBy is required")
+	// ErrReasonRequired is returned by Decide for a rejection without a
+	// reason.
+	ErrReasonRequired = errors.New("approvals: a rejection needs a reason")
+)
+
+// Approval is one project's suspension awaiting, or given, a decision.
+type Approval struct {
+	ProjectID   string `json:"projectId"`
+	ProjectName string `json:"projectName"`
+	ProjectKey  string `json:"projectKey"`
+	AccountName string `json:"accountName,omitempty"`
+	Tier        string `json:"tier"`
+	// EndDate is the end date the suspension was requested for; the
+	// Approval only holds while the project's end date still equals it.
+	EndDate     time.Time  `json:"endDate"`
+	State       State      `json:"state"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	DecidedAt   *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy   string     `json:"decidedBy,omitempty"`
+	// Reason is required for a rejection, optional for an approval.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Request is a project whose day-0 suspension is due, as the gate is asked
+// about it.
+type Request struct {
+	ProjectID   string
+	ProjectName string
+	ProjectKey  string
+	AccountName string
+	// Tier is the account's tier ("basic", "enterprise"); "" when the
+	// project has no linked account or upstream sent none.
+	Tier    string
+	EndDate time.Time
+}
+
+// file is the APPROVALS_FILE layout.
+type file struct {
+	Approvals []Approval `json:"approvals"`
+}
+
+// Store persists Approvals, one per project, in a JSON file.
+type Store struct {
+	path string
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+// NewStore returns a Store backed by the file at path, which need not
+// exist yet.
+func NewStore(path string) *Store {
+	return &Store{path: path, now: time.Now}
+}
+
+// List returns every Approval, oldest request first.
+func (s *Store) List() ([]Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return sorted(m), nil
+}
+
+// Decide approves or rejects projectID's pending Approval, recording by
+// and reason, and returns the decided Approval.
+func (s *Store) Decide(projectID string, approve bool, by, reason string) (Approval, error) {
+	by, reason = strings.TrimSpace(by), strings.TrimSpace(reason)
+	if by == "" {
+		return Approval{}, ErrDecidedByRequired
+	}
+	if !approve && reason == "" {
+		return Approval{}, ErrReasonRequired
+	}
+
+	var decided Approval
+	err := s.update(func(m map[string]Approval) (bool, error) {
+		a, ok := m[projectID]
+		if !ok {
+			return false, ErrNotFound
+		}
+		if a.State != StatePending {
+			return false, ErrNotPending
+		}
+		now := s.now().UTC()
+		a.State, a.DecidedAt, a.DecidedBy, a.Reason = StateRejected, &now, by, reason
+		if approve {
+			a.State = StateApproved
+		}
+		m[projectID] = a
+		decided = a
+		return true, nil
+	})
+	return decided, err
+}
+
+// update loads the file, applies fn, and writes it back if fn reports a
+// change.
+func (s *Store) update(fn func(map[string]Approval) (bool, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.load()
+	if err != nil {
+		return err
+	}
+	changed, err := fn(m)
+	if err != nil || !changed {
+		return err
+	}
+	return s.save(m)
+}
+
+func (s *Store) load() (map[string]Approval, error) {
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Approval{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("approvals: read %s: %w", s.path, err)
+	}
+	var f file
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("approvals: parse %s: %w", s.path, err)
+	}
+	m := make(map[string]Approval, len(f.Approvals))
+	for _, a := range f.Approvals {
+		m[a.ProjectID] = a
+	}
+	return m, nil
+}
+
+// save writes m to a temporary file beside the real one and renames it
+// into place, so a reader never sees a half-written file.
+func (s *Store) save(m map[string]Approval) error {
+	data, err := json.MarshalIndent(file{Approvals: sorted(m)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("approvals: marshal: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("approvals: write %s: %w", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("approvals: write %s: %w", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("approvals: write %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("approvals: write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func sorted(m map[string]Approval) []Approval {
+	out := make([]Approval, 0, len(m))
+	for _, a := range m {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].RequestedAt.Equal(out[j].RequestedAt) {
+			return out[i].RequestedAt.Before(out[j].RequestedAt)
+		}
+		return out[i].ProjectID < out[j].ProjectID
+	})
+	return out
+}
+
+// Gate decides, for one run, whether a due suspension may be executed.
+type Gate struct {
+	store *Store
+	tiers map[string]bool
+	// readOnly is set for a dry run: the gate answers as a live run's
+	// would, but never records a new pending Approval or clears an
+	// executed one.
+	readOnly bool
+}
+
+// NewGate returns a Gate requiring approval for projects whose account is
+// in one of tiers (case-insensitive), backed by store. A read-only Gate
+// writes nothing.
+func NewGate(store *Store, tiers []string, readOnly bool) *Gate {
+	g := &Gate{store: store, tiers: map[string]bool{}, readOnly: readOnly}
+	for _, t := range tiers {
+		g.tiers[strings.ToLower(t)] = true
+	}
+	return g
+}
+
+// needsApproval reports whether a project in tier needs approval. The
+// sweep reads tier from GET /projects/{id}'s account, since search results
+// don't carry it. An unknown tier ("": no linked account, or an account
+// with none set) does: a gate that can't tell how large an account is fails
+// toward a human looking at it.
+func (g *Gate) needsApproval(tier string) bool {
+	return tier == "" || g.tiers[strings.ToLower(tier)]
+}
+
+// Check returns the Approval governing req's suspension, or nil when none
+// is needed and the suspension may go ahead. The suspension may go ahead
+// with an Approval only once its State is StateApproved. A project with no
+// Approval for its current end date gets a new pending one.
+func (g *Gate) Check(ctx context.Context, req Request) (*Approval, error) {
+	if !g.needsApproval(req.Tier) {
+		return nil, nil
+	}
+
+	var current Approval
+	err := g.store.update(func(m map[string]Approval) (bool, error) {
+		if a, ok := m[req.ProjectID]; ok && a.EndDate.Equal(req.EndDate) {
+			current = a
+			return false, nil
+		}
+		current = Approval{
+			ProjectID:   req.ProjectID,
+			ProjectName: req.ProjectName,
+			ProjectKey:  req.ProjectKey,
+			AccountName: req.AccountName,
+			Tier:        req.Tier,
+			EndDate:     req.EndDate.UTC(),
+			State:       StatePending,
+			RequestedAt: g.store.now().UTC(),
+		}
+		if g.readOnly {
+			return false, nil
+		}
+		m[req.ProjectID] = current
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &current, nil
+}
+
+// Executed clears projectID's Approval once its suspension has been
+// written, so a later suspension of the same project — after a manual
+// reopen, say — asks again rather than reusing a spent approval.
+func (g *Gate) Executed(ctx context.Context, projectID string) error {
+	if g.readOnly {
+		return nil
+	}
+	return g.store.update(func(m map[string]Approval) (bool, error) {
+		if _, ok := m[projectID]; !ok {
+			return false, nil
+		}
+		delete(m, projectID)
+		return true, nil
+	})
+}
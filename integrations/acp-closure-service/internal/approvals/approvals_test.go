@@ -0,0 +1,162 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package approvals
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s := NewStore(filepath.Join(t.TempDir(), "approvals.json"))
+	s.now = func() time.Time { return time.Date(2026, 7, 28, 9, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func request(id, tier string, endDate time.Time) Request {
+	return Request{ProjectID: id, ProjectName: "Project " + id, Tier: tier, EndDate: endDate}
+}
+
+// TestGate_Check covers which tiers are gated: a listed tier (any case) and
+// an unknown one need approval; any other tier does not.
+func TestGate_Check(t *testing.T) {
+	endDate := time.Date(2026, 7, 25, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		tier string
+		want bool
+	}{
+		{"enterprise", true},
+		{"Enterprise", true},
+		{"", true},
+		{"basic", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tier, func(t *testing.T) {
+			g := NewGate(newTestStore(t), []string{"ENTERPRISE"}, false)
+			a, err := g.Check(context.Background(), request("p1", tt.tier, endDate))
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if (a != nil) != tt.want {
+				t.Fatalf("Check() = %+v, want approval needed %v", a, tt.want)
+			}
+			if a != nil && a.State != StatePending {
+				t.Errorf("State = %q, want %q", a.State, StatePending)
+			}
+		})
+	}
+}
+
+// TestGate_DecisionLifecycle verifies a decision sticks for the end date
+// it was made for, a new end date asks again, and Executed clears it.
+func TestGate_DecisionLifecycle(t *testing.T) {
+	s := newTestStore(t)
+	g := NewGate(s, []string{"enterprise"}, false)
+	ctx := context.Background()
+	endDate := time.Date(2026, 7, 25, 0, 0, 0, 0, time.UTC)
+
+	if _, err := g.Check(ctx, request("p1", "enterprise", endDate)); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if _, err := s.Decide("p1", false, "finance@example.com", "in negotiation"); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+
+	a, err := g.Check(ctx, request("p1", "enterprise", endDate))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if a.State != StateRejected || a.Reason != "in negotiation" || a.DecidedBy != "finance@example.com" {
+		t.Errorf("same end date: %+v, want the rejection", a)
+	}
+
+	a, err = g.Check(ctx, request("p1", "enterprise", endDate.AddDate(0, 1, 0)))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if a.State != StatePending {
+		t.Errorf("new end date: State = %q, want %q", a.State, StatePending)
+	}
+
+	if _, err := s.Decide("p1", true, "finance@example.com", ""); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	if err := g.Executed(ctx, "p1"); err != nil {
+		t.Fatalf("Executed() error = %v", err)
+	}
+	if list, _ := s.List(); len(list) != 0 {
+		t.Errorf("List() after Executed = %+v, want empty", list)
+	}
+}
+
+// TestGate_ReadOnlyWritesNothing verifies a dry run's gate answers pending
+// without creating the file.
+func TestGate_ReadOnlyWritesNothing(t *testing.T) {
+	s := newTestStore(t)
+	g := NewGate(s, []string{"enterprise"}, true)
+
+	a, err := g.Check(context.Background(), request("p1", "enterprise", time.Now()))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if a == nil || a.State != StatePending {
+		t.Fatalf("Check() = %+v, want pending", a)
+	}
+	if _, err := os.Stat(s.path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("approvals file stat error = %v, want not exist", err)
+	}
+}
+
+func TestStore_DecideErrors(t *testing.T) {
+	s := newTestStore(t)
+	g := NewGate(s, []string{"enterprise"}, false)
+	if _, err := g.Check(context.Background(), request("p1", "enterprise", time.Now())); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		projectID string
+		approve   bool
+		by        string
+		reason    string
+		want      error
+	}{
+		{"unknown project", "p2", true, "finance", "", ErrNotFound},
+		{"no decider", "p1", true, " ", "", ErrDecidedByRequired},
+		{"rejection without reason", "p1", false, "finance", "", ErrReasonRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Decide(tt.projectID, tt.approve, tt.by, tt.reason); !errors.Is(err, tt.want) {
+				t.Errorf("Decide() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.Decide("p1", true, "finance", ""); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	if _, err := s.Decide("p1", false, "finance", "changed mind"); !errors.Is(err, ErrNotPending) {
+		t.Errorf("second Decide() error = %v, want %v", err, ErrNotPending)
+	}
+}
@@ -98,6 +98,18 @@ func Decide(now, endDate time.Time, lastNoticeWindow *NoticeWindow) Decision {
 	}
 }
 
+// Renewed reports whether endDate has moved out past lastNoticeWindow — more
+// days remain now than the window that was recorded covers. Days remaining
+// only ever shrink while the end date stays put, so this is only true once
+// the subscription was renewed (or its end date otherwise pushed out) after
+// that notice fired, and the recorded window, NoticeWindow0 included, is
+// stale. Decide itself would treat a stale window as already covering every
+// window the project has yet to reach again, so callers check this first.
+// False when no window has been recorded.
+func Renewed(now, endDate time.Time, lastNoticeWindow *NoticeWindow) bool {
+	return lastNoticeWindow != nil && daysBetween(now, endDate) > int(*lastNoticeWindow)
+}
+
 // dueWindow finds the narrowest (latest) notice window that daysRemaining
 // has reached but that lastNoticeWindow hasn't already covered.
 func dueWindow(daysRemaining int, lastNoticeWindow *NoticeWindow) (NoticeWindow, bool) {
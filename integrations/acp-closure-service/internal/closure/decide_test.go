@@ -189,3 +189,31 @@ func TestDecide_Day0ReportsNotifyAndSuspendIndependently(t *testing.T) {
 		})
 	}
 }
+
+// TestRenewed covers the stale-window check: only a recorded window the
+// end date has since moved out past is stale, and nothing recorded never is.
+func TestRenewed(t *testing.T) {
+	now := time.Date(2026, 7, 24, 0, 0, 0, 0, time.UTC)
+	w := func(v NoticeWindow) *NoticeWindow { return &v }
+
+	tests := []struct {
+		name    string
+		endDate time.Time
+		last    *NoticeWindow
+		want    bool
+	}{
+		{"nothing recorded", now.AddDate(1, 0, 0), nil, false},
+		{"still inside the recorded window", now.AddDate(0, 0, 25), w(NoticeWindow30), false},
+		{"exactly at the recorded window", now.AddDate(0, 0, 30), w(NoticeWindow30), false},
+		{"moved out past the recorded window", now.AddDate(0, 0, 31), w(NoticeWindow30), true},
+		{"suspended and still past the end date", now.AddDate(0, 0, -3), w(NoticeWindow0), false},
+		{"suspended, then renewed for a year", now.AddDate(1, 0, 0), w(NoticeWindow0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Renewed(now, tt.endDate, tt.last); got != tt.want {
+				t.Errorf("Renewed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
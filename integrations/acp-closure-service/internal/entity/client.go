@@ -33,6 +33,7 @@ import (
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/apierror"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/time/rate"
 )
 
 // RequiredScopes is the exact scope set csm-integration-service's token
@@ -80,6 +81,13 @@ type Config struct {
 	ClientSecret string
 	// Scopes should be RequiredScopes — see its doc comment.
 	Scopes []string
+	// RateLimit caps outbound requests per second with a token bucket of
+	// RateBurst tokens, shared by every caller of the Client — with a
+	// concurrent sweep, that is every worker at once. Zero means no limit.
+	// Token-endpoint requests are not counted: they go to a different
+	// host, and are rare.
+	RateLimit float64
+	RateBurst int
 }
 
 // Client is an HTTP client authenticated to csm-integration-service via the
@@ -92,6 +100,8 @@ type Config struct {
 type Client struct {
 	http    *http.Client
 	baseURL string
+	// limiter is nil when Config.RateLimit is zero.
+	limiter *rate.Limiter
 }
 
 // NewClient constructs a Client that authenticates against
@@ -118,15 +128,26 @@ func NewClient(cfg Config) *Client {
 		return http.ErrUseLastResponse
 	}
 
-	return &Client{
+	c := &Client{
 		http:    httpClient,
 		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
 	}
+	if cfg.RateLimit > 0 {
+		c.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), max(cfg.RateBurst, 1))
+	}
+	return c
 }
 
 // do executes an authenticated HTTP request against csm-integration-service
 // and returns the raw JSON response body. The caller owns the returned slice.
+// It first waits for the rate limiter, if any, giving up if ctx ends first.
 func (c *Client) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("entity: %s %s: rate limit wait: %w", method, path, err)
+		}
+	}
+
 	var reqBody io.Reader
 	if len(body) > 0 {
 		reqBody = bytes.NewReader(body)
@@ -289,3 +289,46 @@ func TestNewClientRequestsConfiguredScopes(t *testing.T) {
 		t.Errorf("token request scope = %q, want %q", gotScope, wantScope)
 	}
 }
+
+// TestDoWaitsForRateLimiter verifies requests past the burst wait for the
+// token bucket, and that a request whose context ends while waiting fails
+// without reaching upstream.
+func TestDoWaitsForRateLimiter(t *testing.T) {
+	var hits int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	tokenSrv := tokenServer(t)
+	client := NewClient(Config{
+		BaseURL:      upstream.URL,
+		TokenURL:     tokenSrv.URL,
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		Scopes:       RequiredScopes,
+		RateLimit:    20,
+		RateBurst:    1,
+	})
+
+	start := time.Now()
+	for range 3 {
+		if _, err := client.GetAccount(context.Background(), "a1"); err != nil {
+			t.Fatalf("GetAccount() error = %v", err)
+		}
+	}
+	// One request from the burst, two more at 20/s: at least ~100ms.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests took %v, want the limiter to space them out", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetAccount(ctx, "a1"); err == nil {
+		t.Error("GetAccount() with a cancelled context error = nil, want an error")
+	}
+	if hits != 3 {
+		t.Errorf("upstream hits = %d, want 3", hits)
+	}
+}
@@ -58,8 +58,8 @@ type Notice struct {
 	EndDate     time.Time
 	Window      closure.NoticeWindow
 	// Subject is the notice's title line — one of five templates depending
-	// on notice type and window (see sweep.go's internalNoticeSubject/
-	// customerNoticeSubject for the exact wording): the internal day-count
+	// on notice type and window (see package templates' notices/ for the
+	// exact wording): the internal day-count
 	// reminder (90/60/30/15/7, [ACP]-prefixed — every internal window, not
 	// just 90/60/30), the internal day-0 suspension notice (also
 	// [ACP]-prefixed, distinct wording since there's no "days remaining"
@@ -70,7 +70,8 @@ type Notice struct {
 	Subject string
 	// Body is the notice's full email body — populated for every notice
 	// type today (day-count reminder, day-0 suspension, customer notice,
-	// no-business-contact urgent notice all have their own template).
+	// no-business-contact urgent notice all have their own template, and
+	// may have a locale variant chosen by the account's region).
 	Body       string
 	Recipients Recipients
 	// ResolvedVia records which tier of the three-tier customer-contact
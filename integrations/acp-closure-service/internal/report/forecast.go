@@ -0,0 +1,76 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/sweep"
+)
+
+// WriteForecast writes f to w as a plain-text calendar: one heading per
+// date that has anything on it, then each project's notices (subject and
+// recipients) and suspension for that date.
+func WriteForecast(w io.Writer, f sweep.ForecastResult) error {
+	bw := bufio.NewWriter(w)
+	last := f.From.AddDate(0, 0, f.Days-1)
+	fmt.Fprintf(bw, "ACP forecast %s to %s (%d days): %d projects, %d excluded, %d failed, %d events\n",
+		f.From.Format(time.DateOnly), last.Format(time.DateOnly), f.Days,
+		f.ProjectsForecast, f.ProjectsExcluded, len(f.Failures), len(f.Events))
+
+	date := ""
+	for _, e := range f.Events {
+		if d := e.Date.Format(time.DateOnly); d != date {
+			date = d
+			fmt.Fprintf(bw, "\n%s\n", date)
+		}
+		fmt.Fprintf(bw, "  %s (%s) %s: %s\n", e.ProjectName, e.ProjectKey, e.ProjectID, forecastAction(e))
+		for _, n := range e.Notices {
+			fmt.Fprintf(bw, "    notice: %s <%s>\n", n.Subject, strings.Join(addresses(n.Recipients), ", "))
+		}
+	}
+
+	if len(f.Failures) > 0 {
+		fmt.Fprintf(bw, "\nFailed\n")
+		for _, pf := range f.Failures {
+			fmt.Fprintf(bw, "  %s: %v\n", pf.ProjectID, pf.Err)
+		}
+	}
+	return bw.Flush()
+}
+
+func forecastAction(e sweep.ForecastEvent) string {
+	if e.Reinstates {
+		return "reinstatement after renewal"
+	}
+	var parts []string
+	if len(e.Notices) > 0 {
+		if e.Window.IsTerminal() {
+			parts = append(parts, "day-0 notice")
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-day notice", int(e.Window)))
+		}
+	}
+	if e.Suspends {
+		parts = append(parts, "suspension")
+	}
+	return fmt.Sprintf("%s, %d days remaining", strings.Join(parts, " and "), e.DaysRemaining)
+}
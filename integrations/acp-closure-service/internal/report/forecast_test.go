@@ -0,0 +1,67 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package report
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/recipients"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/sweep"
+)
+
+func TestWriteForecast(t *testing.T) {
+	from := time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC)
+	internal := notify.Notice{Subject: "[ACP] Project Suspension Notice of Acme", Recipients: notify.Recipients{
+		AccountOwner: recipients.Contact{Email: "am@wso2.example"},
+	}}
+	f := sweep.ForecastResult{
+		From: from, Days: 30, ProjectsForecast: 2,
+		Events: []sweep.ForecastEvent{
+			{Date: from, ProjectID: "p1", ProjectName: "Acme", ProjectKey: "ACME", DaysRemaining: 20, Window: closure.NoticeWindow30, Notices: []notify.Notice{internal}},
+			{Date: from, ProjectID: "p2", ProjectName: "Beta", ProjectKey: "BETA", DaysRemaining: 0, Window: closure.NoticeWindow0, Suspends: true},
+			{Date: from.AddDate(0, 0, 20), ProjectID: "p1", ProjectName: "Acme", ProjectKey: "ACME", DaysRemaining: 0, Window: closure.NoticeWindow0, Notices: []notify.Notice{internal}, Suspends: true},
+		},
+		Failures: []sweep.ProjectFailure{{ProjectID: "p3", Err: errors.New("get account: boom")}},
+	}
+
+	var b strings.Builder
+	if err := WriteForecast(&b, f); err != nil {
+		t.Fatal(err)
+	}
+	want := `ACP forecast 2026-10-19 to 2026-11-17 (30 days): 2 projects, 0 excluded, 1 failed, 3 events
+
+2026-10-19
+  Acme (ACME) p1: 30-day notice, 20 days remaining
+    notice: [ACP] Project Suspension Notice of Acme <am@wso2.example>
+  Beta (BETA) p2: suspension, 0 days remaining
+
+2026-11-08
+  Acme (ACME) p1: day-0 notice and suspension, 0 days remaining
+    notice: [ACP] Project Suspension Notice of Acme <am@wso2.example>
+
+Failed
+  p3: get account: boom
+`
+	if b.String() != want {
+		t.Errorf("calendar =\n%s\nwant\n%s", b.String(), want)
+	}
+}
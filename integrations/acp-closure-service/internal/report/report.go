@@ -0,0 +1,342 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package report turns one sweep's Result into a run report: a row per
+// project saying what was decided, which notices were built and to whom,
+// and whether suspension fired — so "why was project X suspended on the
+// 3rd" is answered by opening that day's report rather than grepping logs.
+// Each run writes the same report three ways, all named by its runID: JSON
+// for tooling, CSV for spreadsheets, and HTML for reading. WriteForecast
+// renders a sweep.Forecast the same way a person would read it: as a
+// calendar.
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/sweep"
+)
+
+// Report is one run's report.
+type Report struct {
+	RunID         string    `json:"runId"`
+	StartedAt     time.Time `json:"startedAt"`
+	FinishedAt    time.Time `json:"finishedAt"`
+	DryRun        bool      `json:"dryRun"`
+	TestProjectID string    `json:"testProjectId,omitempty"`
+	// Error is the fatal sweep error that cut the run short, if any; the
+	// rows then cover only the projects reached before it.
+	Error             string `json:"error,omitempty"`
+	ProjectsEvaluated int    `json:"projectsEvaluated"`
+	ProjectsExcluded  int    `json:"projectsExcluded"`
+	FailureCount      int    `json:"failureCount"`
+	Projects          []Row  `json:"projects"`
+}
+
+// Row is one project's line in a report.
+type Row struct {
+	ProjectID   string `json:"projectId"`
+	ProjectName string `json:"projectName,omitempty"`
+	ProjectKey  string `json:"projectKey,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+	Excluded    bool   `json:"excluded,omitempty"`
+	// DaysRemaining and Decision are nil for a project never decided on.
+	DaysRemaining *int      `json:"daysRemaining"`
+	Decision      *Decision `json:"decision"`
+	// LastWindowBefore and LastWindowAfter are the last notice window
+	// recorded before and after this run, in days; nil for none.
+	LastWindowBefore *int     `json:"lastWindowBefore"`
+	LastWindowAfter  *int     `json:"lastWindowAfter"`
+	Notices          []Notice `json:"notices"`
+	// ResolvedVia is the customer-contact resolution tier, or "" when no
+	// notice attempted it.
+	ResolvedVia string `json:"resolvedVia,omitempty"`
+	Suspended   bool   `json:"suspended"`
+	// Reset reports the end date had moved out past the recorded window (a
+	// renewal) and the record was reset to "open"; Reinstated, that a
+	// suspension was reverted with it.
+	Reset      bool `json:"reset"`
+	Reinstated bool `json:"reinstated"`
+	// Approval is the state of the approval the day-0 suspension needed
+	// ("pending", "approved", "rejected"); empty when it needed none.
+	Approval       string `json:"approval,omitempty"`
+	ApprovalReason string `json:"approvalReason,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Decision is closure.Decision as reported.
+type Decision struct {
+	Fires bool `json:"fires"`
+	// Window is the due notice window in days; nil when nothing fires.
+	Window        *int `json:"window"`
+	ShouldNotify  bool `json:"shouldNotify"`
+	ShouldSuspend bool `json:"shouldSuspend"`
+}
+
+// Notice is one notice built for a project.
+type Notice struct {
+	Subject string `json:"subject"`
+	// To is every non-empty recipient address, customer first.
+	To          []string `json:"to"`
+	ResolvedVia string   `json:"resolvedVia,omitempty"`
+	// Error is why Send failed; empty when it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// Build assembles the report for a run that started at startedAt and
+// finished at finishedAt with result and runErr, Run's return values.
+func Build(runID string, dryRun bool, testProjectID string, startedAt, finishedAt time.Time, result sweep.Result, runErr error) Report {
+	r := Report{
+		RunID:             runID,
+		StartedAt:         startedAt.UTC(),
+		FinishedAt:        finishedAt.UTC(),
+		DryRun:            dryRun,
+		TestProjectID:     testProjectID,
+		ProjectsEvaluated: result.ProjectsEvaluated,
+		ProjectsExcluded:  result.ProjectsExcluded,
+		FailureCount:      len(result.Failures),
+		Projects:          make([]Row, 0, len(result.Projects)),
+	}
+	if runErr != nil {
+		r.Error = runErr.Error()
+	}
+	for _, o := range result.Projects {
+		r.Projects = append(r.Projects, row(o))
+	}
+	return r
+}
+
+func row(o sweep.ProjectOutcome) Row {
+	r := Row{
+		ProjectID:        o.ProjectID,
+		ProjectName:      o.ProjectName,
+		ProjectKey:       o.ProjectKey,
+		Excluded:         o.Excluded,
+		LastWindowBefore: windowDays(o.LastWindowBefore),
+		LastWindowAfter:  windowDays(o.LastWindowAfter),
+		Notices:          make([]Notice, 0, len(o.Notices)),
+		ResolvedVia:      string(o.ResolvedVia()),
+		Suspended:        o.Suspended,
+		Reset:            o.Reset,
+		Reinstated:       o.Reinstated,
+		Approval:         string(o.Approval),
+		ApprovalReason:   o.ApprovalReason,
+	}
+	if o.EndDate != nil {
+		r.EndDate = o.EndDate.Format(time.DateOnly)
+	}
+	if d := o.Decision; d != nil {
+		days := d.DaysRemaining
+		r.DaysRemaining = &days
+		r.Decision = &Decision{Fires: d.Fires, ShouldNotify: d.ShouldNotify, ShouldSuspend: d.ShouldSuspend}
+		if d.Fires {
+			r.Decision.Window = windowDays(&d.Window)
+		}
+	}
+	for _, n := range o.Notices {
+		out := Notice{Subject: n.Notice.Subject, To: addresses(n.Notice.Recipients), ResolvedVia: string(n.Notice.ResolvedVia)}
+		if n.Err != nil {
+			out.Error = n.Err.Error()
+		}
+		r.Notices = append(r.Notices, out)
+	}
+	if o.Err != nil {
+		r.Error = o.Err.Error()
+	}
+	return r
+}
+
+func windowDays(w *closure.NoticeWindow) *int {
+	if w == nil {
+		return nil
+	}
+	days := int(*w)
+	return &days
+}
+
+func addresses(r notify.Recipients) []string {
+	var out []string
+	if r.Customer != nil && r.Customer.Email != "" {
+		out = append(out, r.Customer.Email)
+	}
+	for _, c := range []string{r.AccountOwner.Email, r.RenewalManager.Email, r.TechnicalOwner.Email} {
+		if c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Write writes r to dir as acp-run-<runID>.json, .csv and .html, creating
+// dir if needed, and returns the paths written. The files name people and
+// their addresses, so they are readable by their owner only.
+func Write(dir string, r Report) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("report: create %s: %w", dir, err)
+	}
+	renderers := []struct {
+		ext    string
+		render func(Report) ([]byte, error)
+	}{
+		{"json", renderJSON},
+		{"csv", RenderCSV},
+		{"html", RenderHTML},
+	}
+	var paths []string
+	for _, rd := range renderers {
+		data, err := rd.render(r)
+		if err != nil {
+			return paths, fmt.Errorf("report: render %s: %w", rd.ext, err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("acp-run-%s.%s", r.RunID, rd.ext))
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return paths, fmt.Errorf("report: write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func renderJSON(r Report) ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// csvHeader is RenderCSV's header row. Notices are flattened into one cell,
+// one "subject <to, ...>" per line.
+var csvHeader = []string{
+	"projectId", "projectName", "projectKey", "endDate", "excluded", "daysRemaining",
+	"fires", "window", "shouldNotify", "shouldSuspend", "lastWindowBefore", "lastWindowAfter",
+	"notices", "resolvedVia", "suspended", "reset", "reinstated",
+	"approval", "approvalReason", "error",
+}
+
+// RenderCSV renders r's rows as CSV, one project per row.
+func RenderCSV(r Report) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, p := range r.Projects {
+		rec := []string{
+			p.ProjectID, p.ProjectName, p.ProjectKey, p.EndDate, strconv.FormatBool(p.Excluded), intCell(p.DaysRemaining),
+			"", "", "", "", intCell(p.LastWindowBefore), intCell(p.LastWindowAfter),
+			noticesCell(p.Notices), p.ResolvedVia, strconv.FormatBool(p.Suspended),
+			strconv.FormatBool(p.Reset), strconv.FormatBool(p.Reinstated), p.Approval, p.ApprovalReason, p.Error,
+		}
+		if d := p.Decision; d != nil {
+			rec[6], rec[7], rec[8], rec[9] = strconv.FormatBool(d.Fires), intCell(d.Window), strconv.FormatBool(d.ShouldNotify), strconv.FormatBool(d.ShouldSuspend)
+		}
+		if err := w.Write(rec); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func intCell(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func noticesCell(notices []Notice) string {
+	lines := make([]string, 0, len(notices))
+	for _, n := range notices {
+		line := fmt.Sprintf("%s <%s>", n.Subject, strings.Join(n.To, ", "))
+		if n.Error != "" {
+			line += " FAILED: " + n.Error
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"days": intCell,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>ACP run {{.RunID}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+.failed { color: #b00020; }
+ul { margin: 0; padding-left: 1.2em; }
+</style>
+</head>
+<body>
+<h1>ACP run {{.RunID}}</h1>
+<p>
+Started {{.StartedAt.Format "2006-01-02 15:04:05 MST"}}, finished {{.FinishedAt.Format "2006-01-02 15:04:05 MST"}}.
+{{if .DryRun}}Dry run: nothing was written or sent.{{else}}Live run.{{end}}
+{{with .TestProjectID}}Scoped to project {{.}}.{{end}}
+</p>
+<p>{{.ProjectsEvaluated}} evaluated, {{.ProjectsExcluded}} excluded, {{.FailureCount}} failed.</p>
+{{with .Error}}<p class="failed">The run stopped early: {{.}}</p>{{end}}
+<table>
+<thead>
+<tr><th>Project</th><th>End date</th><th>Days remaining</th><th>Decision</th><th>Last window before</th><th>Last window after</th><th>Notices</th><th>Resolved via</th><th>Suspended</th><th>Reinstated</th><th>Approval</th><th>Error</th></tr>
+</thead>
+<tbody>
+{{range .Projects}}<tr>
+<td>{{if .ProjectName}}{{.ProjectName}} ({{.ProjectKey}})<br>{{end}}<small>{{.ProjectID}}</small></td>
+<td>{{.EndDate}}</td>
+<td>{{days .DaysRemaining}}</td>
+<td>{{if .Excluded}}excluded{{else if .Reset}}renewed: notice state reset{{else if not .Decision}}not evaluated{{else if not .Decision.Fires}}nothing due{{else}}{{days .Decision.Window}}-day window{{if .Decision.ShouldNotify}}, notify{{end}}{{if .Decision.ShouldSuspend}}, suspend{{end}}{{end}}</td>
+<td>{{days .LastWindowBefore}}</td>
+<td>{{days .LastWindowAfter}}</td>
+<td>{{if .Notices}}<ul>{{range .Notices}}<li{{if .Error}} class="failed"{{end}}>{{.Subject}}<br><small>{{range $i, $to := .To}}{{if $i}}, {{end}}{{$to}}{{end}}</small>{{with .Error}}<br>{{.}}{{end}}</li>{{end}}</ul>{{end}}</td>
+<td>{{.ResolvedVia}}</td>
+<td>{{if .Suspended}}yes{{end}}</td>
+<td>{{if .Reinstated}}yes{{end}}</td>
+<td>{{.Approval}}{{with .ApprovalReason}}: {{.}}{{end}}</td>
+<td class="failed">{{.Error}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+// RenderHTML renders r as a standalone HTML page.
+func RenderHTML(r Report) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlReport.Execute(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
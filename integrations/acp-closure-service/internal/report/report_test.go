@@ -0,0 +1,204 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/recipients"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/sweep"
+)
+
+// testResult is a sweep that suspended one project, excluded one, failed
+// to notify a third, and reinstated a fourth after its renewal.
+func testResult() sweep.Result {
+	endDate := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+	renewedEndDate := endDate.AddDate(1, 0, 0)
+	w7, w0 := closure.NoticeWindow7, closure.NoticeWindow0
+	sendErr := errors.New("smtp down")
+	return sweep.Result{
+		ProjectsEvaluated: 3,
+		ProjectsExcluded:  1,
+		Failures:          []sweep.ProjectFailure{{ProjectID: "p3", Err: sendErr}},
+		Projects: []sweep.ProjectOutcome{
+			{
+				ProjectID: "p1", ProjectName: "Acme <Subscription>", ProjectKey: "ACME", EndDate: &endDate,
+				Decision:         &closure.Decision{DaysRemaining: -1, Window: closure.NoticeWindow0, Fires: true, ShouldNotify: true, ShouldSuspend: true},
+				LastWindowBefore: &w7, LastWindowAfter: &w0,
+				Notices: []sweep.NoticeOutcome{
+					{Notice: notify.Notice{Subject: "[ACP] Project Suspension Notice of Acme", Recipients: notify.Recipients{
+						AccountOwner: recipients.Contact{Email: "am@wso2.example"},
+					}}},
+					{Notice: notify.Notice{Subject: "Project Suspension Notice - Acme", ResolvedVia: recipients.ResolvedViaPrimaryContact, Recipients: notify.Recipients{
+						AccountOwner: recipients.Contact{Email: "am@wso2.example"},
+						Customer:     &recipients.Contact{Email: "pat@customer.example"},
+					}}},
+				},
+				Suspended:      true,
+				Approval:       approvals.StateApproved,
+				ApprovalReason: "confirmed with finance",
+			},
+			{ProjectID: "p2", Excluded: true},
+			{
+				ProjectID: "p3", EndDate: &endDate,
+				Decision: &closure.Decision{DaysRemaining: 80, Window: closure.NoticeWindow90, Fires: true, ShouldNotify: true},
+				Notices:  []sweep.NoticeOutcome{{Notice: notify.Notice{Subject: "[ACP] 90 Days Reminder"}, Err: sendErr}},
+				Err:      sendErr,
+			},
+			{
+				ProjectID: "p4", EndDate: &renewedEndDate,
+				LastWindowBefore: &w0,
+				Notices:          []sweep.NoticeOutcome{{Notice: notify.Notice{Subject: "Project Reinstatement Notice - Acme"}}},
+				Reset:            true,
+				Reinstated:       true,
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	started := time.Date(2026, 7, 28, 1, 0, 0, 0, time.FixedZone("IST", 5*3600+1800))
+	r := Build("run-1", true, "", started, started.Add(time.Minute), testResult(), nil)
+
+	if r.RunID != "run-1" || !r.DryRun || r.FailureCount != 1 || r.ProjectsExcluded != 1 || len(r.Projects) != 4 {
+		t.Fatalf("report = %+v", r)
+	}
+	if r.StartedAt.Location() != time.UTC {
+		t.Errorf("StartedAt = %s, want UTC", r.StartedAt)
+	}
+
+	p1 := r.Projects[0]
+	if p1.EndDate != "2026-07-27" || *p1.DaysRemaining != -1 || *p1.Decision.Window != 0 || !p1.Decision.ShouldSuspend {
+		t.Errorf("p1 = %+v", p1)
+	}
+	if *p1.LastWindowBefore != 7 || *p1.LastWindowAfter != 0 || !p1.Suspended {
+		t.Errorf("p1 windows/suspended = %v %v %v", *p1.LastWindowBefore, *p1.LastWindowAfter, p1.Suspended)
+	}
+	if p1.Approval != "approved" || p1.ApprovalReason != "confirmed with finance" {
+		t.Errorf("p1 approval = %q %q", p1.Approval, p1.ApprovalReason)
+	}
+	if p1.ResolvedVia != "primary_contact" || len(p1.Notices) != 2 {
+		t.Errorf("p1 notices = %+v, resolvedVia %q", p1.Notices, p1.ResolvedVia)
+	}
+	if got := strings.Join(p1.Notices[1].To, ","); got != "pat@customer.example,am@wso2.example" {
+		t.Errorf("customer notice To = %s", got)
+	}
+
+	if p2 := r.Projects[1]; !p2.Excluded || p2.Decision != nil || p2.DaysRemaining != nil {
+		t.Errorf("p2 = %+v", p2)
+	}
+	if p3 := r.Projects[2]; p3.Error != "smtp down" || p3.Notices[0].Error != "smtp down" || p3.LastWindowAfter != nil {
+		t.Errorf("p3 = %+v", p3)
+	}
+	if p4 := r.Projects[3]; !p4.Reset || !p4.Reinstated || p4.Decision != nil || *p4.LastWindowBefore != 0 || p4.LastWindowAfter != nil {
+		t.Errorf("p4 = %+v", p4)
+	}
+
+	if r := Build("run-2", false, "p1", started, started, sweep.Result{}, errors.New("search failed")); r.Error != "search failed" || r.Projects == nil {
+		t.Errorf("fatal run report = %+v", r)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	r := Build("run-1", true, "", time.Now(), time.Now(), testResult(), nil)
+
+	paths, err := Write(dir, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("paths = %v", paths)
+	}
+	for _, ext := range []string{"json", "csv", "html"} {
+		info, err := os.Stat(filepath.Join(dir, "acp-run-run-1."+ext))
+		if err != nil {
+			t.Fatalf("%s report: %v", ext, err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("%s report mode = %v, want 0600", ext, info.Mode().Perm())
+		}
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "acp-run-run-1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Report
+	if err := json.Unmarshal(raw, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.RunID != "run-1" || len(back.Projects) != 4 || back.Projects[0].ProjectKey != "ACME" {
+		t.Errorf("JSON round trip = %+v", back)
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	data, err := RenderCSV(Build("run-1", true, "", time.Now(), time.Now(), testResult(), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || len(rows[0]) != len(csvHeader) {
+		t.Fatalf("rows = %v", rows)
+	}
+	p1 := rows[1]
+	if p1[0] != "p1" || p1[5] != "-1" || p1[6] != "true" || p1[7] != "0" || p1[10] != "7" || p1[11] != "0" || p1[14] != "true" || p1[17] != "approved" {
+		t.Errorf("p1 row = %v", p1)
+	}
+	if !strings.Contains(p1[12], "Project Suspension Notice - Acme <pat@customer.example, am@wso2.example>") {
+		t.Errorf("p1 notices cell = %q", p1[12])
+	}
+	if p2 := rows[2]; p2[4] != "true" || p2[6] != "" {
+		t.Errorf("p2 row = %v", p2)
+	}
+	if p3 := rows[3]; !strings.Contains(p3[12], "FAILED: smtp down") || p3[19] != "smtp down" {
+		t.Errorf("p3 row = %v", p3)
+	}
+	if p4 := rows[4]; p4[14] != "false" || p4[15] != "true" || p4[16] != "true" {
+		t.Errorf("p4 row = %v", p4)
+	}
+}
+
+func TestRenderHTML_Escapes(t *testing.T) {
+	data, err := RenderHTML(Build("run-1", true, "", time.Now(), time.Now(), testResult(), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	if strings.Contains(html, "Acme <Subscription>") || !strings.Contains(html, "Acme &lt;Subscription&gt;") {
+		t.Error("project name was not escaped")
+	}
+	for _, want := range []string{"ACP run run-1", "Dry run", "-1", "0-day window, notify, suspend", "excluded", "smtp down", "renewed: notice state reset", "approved: confirmed with finance"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML lacks %q", want)
+		}
+	}
+}
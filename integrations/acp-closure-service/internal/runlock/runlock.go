@@ -0,0 +1,58 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+// Package runlock keeps sweeps in different processes from overlapping: the
+// service's runs, another replica's, and the one-shot CLI's all take the same
+// lock file before sweeping. It is an advisory flock, so the kernel drops it
+// when its holder exits, crashed or not, and a stale lock never needs
+// clearing by hand. The file must be on a volume every sweeping process
+// mounts.
+package runlock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrHeld is returned by TryLock while another holder has the lock.
+var ErrHeld = errors.New("runlock: another sweep holds the lock")
+
+// File is a lock on the file at a path.
+type File struct {
+	path string
+}
+
+// New returns a lock on the file at path, which is created if missing.
+func New(path string) *File {
+	return &File{path: path}
+}
+
+// Path is the lock file's path.
+func (f *File) Path() string { return f.path }
+
+// TryLock takes the lock without waiting, returning the func that releases
+// it, or ErrHeld if another holder, in this process or another, has it.
+func (f *File) TryLock() (release func(), err error) {
+	fd, err := os.OpenFile(f.path, os.O_RDWR|os.O_CREATE, 0o644) // #nosec G304 -- path is operator configuration (RUN_LOCK_FILE)
+	if err != nil {
+		return nil, fmt.Errorf("runlock: %w", err)
+	}
+	if err := lock(fd); err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return func() { fd.Close() }, nil
+}
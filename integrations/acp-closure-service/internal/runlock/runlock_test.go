@@ -0,0 +1,50 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+package runlock
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestFile_ExcludesOtherHolders verifies a second holder is refused while
+// the first has the lock, and gets it once released.
+func TestFile_ExcludesOtherHolders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acp-run.lock")
+	release, err := New(path).TryLock()
+	if err != nil {
+		t.Fatalf("TryLock() error = %v", err)
+	}
+
+	if _, err := New(path).TryLock(); !errors.Is(err, ErrHeld) {
+		t.Fatalf("second TryLock() error = %v, want ErrHeld", err)
+	}
+
+	release()
+	again, err := New(path).TryLock()
+	if err != nil {
+		t.Fatalf("TryLock() after release error = %v", err)
+	}
+	again()
+}
+
+func TestFile_UnwritableDirectory(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing", "acp-run.lock")).TryLock()
+	if err == nil || errors.Is(err, ErrHeld) {
+		t.Errorf("TryLock() error = %v, want an open error", err)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package runs backs the service mode: it starts sweeps on request or on a
+// daily schedule, never more than one at a time, and keeps their progress
+// and reports for the status API. What a sweep actually does is injected as
+// a SweepFunc — this package knows nothing about entity-service, dry-run, or
+// notifiers, the same way package sweep knows nothing about DRY_RUN.
+//
+// Overlap is prevented in-process by Manager itself and across processes —
+// another replica, or the one-shot CronJob — by the optional runlock.File
+// every sweeping process shares.
+//
+// History is held in memory only and is lost on restart; REPORT_DIR is where
+// reports outlive the process.
+package runs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/report"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/runlock"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/sweep"
+)
+
+// historySize is how many runs Manager remembers; the oldest is dropped
+// first.
+const historySize = 50
+
+// ErrRunInProgress is returned by Start while another run is still going.
+var ErrRunInProgress = errors.New("runs: a run is already in progress")
+
+// ErrRunElsewhere is returned by Start while another process holds the run
+// lock; that run's details are not known here.
+var ErrRunElsewhere = errors.New("runs: a run is already in progress in another process")
+
+// Trigger is what started a run.
+type Trigger string
+
+const (
+	TriggerAPI      Trigger = "api"
+	TriggerSchedule Trigger = "schedule"
+)
+
+// State is where a run is. A run that finished with any project failure is
+// StateFailed, matching the one-shot CLI's non-zero exit code for it.
+type State string
+
+const (
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+// Request is what a run covers: one project when ProjectID is set, every
+// open project otherwise.
+type Request struct {
+	ProjectID string
+	DryRun    bool
+}
+
+// SweepFunc performs one sweep for req, identified by runID, calling
+// progress after each project, and returns its report.
+type SweepFunc func(ctx context.Context, runID string, req Request, progress sweep.Progress) report.Report
+
+// Progress counts the projects a running sweep has reached so far.
+type Progress struct {
+	ProjectsEvaluated int `json:"projectsEvaluated"`
+	ProjectsExcluded  int `json:"projectsExcluded"`
+	Failures          int `json:"failures"`
+}
+
+// Run is a snapshot of one run.
+type Run struct {
+	ID         string     `json:"runId"`
+	Trigger    Trigger    `json:"trigger"`
+	ProjectID  string     `json:"projectId,omitempty"`
+	DryRun     bool       `json:"dryRun"`
+	State      State      `json:"state"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Progress   Progress   `json:"progress"`
+	// Report is the finished run's report; nil while it is running, and in
+	// List's summaries.
+	Report *report.Report `json:"report,omitempty"`
+}
+
+// Manager starts runs and remembers them.
+type Manager struct {
+	sweep SweepFunc
+	newID func() string
+	now   func() time.Time
+	lock  *runlock.File
+
+	mu      sync.Mutex
+	active  *Run
+	history []*Run // oldest first
+	done    chan struct{}
+}
+
+// NewManager creates a Manager that runs sweeps with fn, naming each run
+// with newID. Each run holds lock while it sweeps, when lock is non-nil;
+// with a nil lock, only runs in this process are kept from overlapping.
+func NewManager(fn SweepFunc, newID func() string, lock *runlock.File) *Manager {
+	return &Manager{sweep: fn, newID: newID, now: time.Now, lock: lock}
+}
+
+// Start starts a run for req in the background and returns its snapshot,
+// or ErrRunInProgress, with the active run's snapshot, if one is already
+// going here, or ErrRunElsewhere if another process holds the run lock.
+// The run is detached from ctx's cancellation: an HTTP request ending must
+// not abandon a sweep halfway through a project.
+func (m *Manager) Start(ctx context.Context, trigger Trigger, req Request) (Run, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active != nil {
+		return *m.active, ErrRunInProgress
+	}
+	release := func() {}
+	if m.lock != nil {
+		var err error
+		if release, err = m.lock.TryLock(); err != nil {
+			if errors.Is(err, runlock.ErrHeld) {
+				return Run{}, ErrRunElsewhere
+			}
+			return Run{}, err
+		}
+	}
+
+	run := &Run{
+		ID:        m.newID(),
+		Trigger:   trigger,
+		ProjectID: req.ProjectID,
+		DryRun:    req.DryRun,
+		State:     StateRunning,
+		StartedAt: m.now().UTC(),
+	}
+	m.active = run
+	m.history = append(m.history, run)
+	if len(m.history) > historySize {
+		m.history = m.history[len(m.history)-historySize:]
+	}
+	m.done = make(chan struct{})
+
+	go m.execute(context.WithoutCancel(ctx), run, req, m.done, release)
+	return *run, nil
+}
+
+func (m *Manager) execute(ctx context.Context, run *Run, req Request, done chan struct{}, release func()) {
+	defer close(done)
+	defer release()
+	slog.InfoContext(ctx, "run started", "runID", run.ID, "trigger", run.Trigger, "projectID", req.ProjectID, "dryRun", req.DryRun)
+
+	r := m.sweep(ctx, run.ID, req, func(o sweep.ProjectOutcome) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if o.Excluded {
+			run.Progress.ProjectsExcluded++
+		} else {
+			run.Progress.ProjectsEvaluated++
+		}
+		if o.Err != nil {
+			run.Progress.Failures++
+		}
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	finished := m.now().UTC()
+	run.FinishedAt = &finished
+	run.Report = &r
+	run.Progress = Progress{ProjectsEvaluated: r.ProjectsEvaluated, ProjectsExcluded: r.ProjectsExcluded, Failures: r.FailureCount}
+	run.State = StateSucceeded
+	if r.Error != "" || r.FailureCount > 0 {
+		run.State = StateFailed
+	}
+	m.active = nil
+	slog.InfoContext(ctx, "run finished", "runID", run.ID, "state", run.State, "failureCount", r.FailureCount, "err", r.Error)
+}
+
+// Get returns the run with id, if Manager still remembers it.
+func (m *Manager) Get(id string) (Run, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, run := range m.history {
+		if run.ID == id {
+			return *run, true
+		}
+	}
+	return Run{}, false
+}
+
+// List returns every remembered run, newest first, without reports.
+func (m *Manager) List() []Run {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Run, 0, len(m.history))
+	for i := len(m.history) - 1; i >= 0; i-- {
+		run := *m.history[i]
+		run.Report = nil
+		out = append(out, run)
+	}
+	return out
+}
+
+// Wait blocks until the active run, if any, finishes or ctx is done, and
+// reports whether nothing is left running. Used at shutdown so a sweep is
+// not cut off mid-project.
+func (m *Manager) Wait(ctx context.Context) bool {
+	m.mu.Lock()
+	active, done := m.active, m.done
+	m.mu.Unlock()
+	if active == nil {
+		return true
+	}
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ScheduleDaily starts a run for req every day at the time of day at (an
+// offset from midnight UTC) until ctx is done. A day on which a run is
+// already going, here or in another process, when the time comes is
+// skipped, not queued — the next
+// day's run picks up whatever it missed, the same as a late cron run.
+func (m *Manager) ScheduleDaily(ctx context.Context, at time.Duration, req Request) {
+	for {
+		next := nextDaily(m.now(), at)
+		slog.InfoContext(ctx, "next scheduled run", "at", next)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if run, err := m.Start(ctx, TriggerSchedule, req); errors.Is(err, ErrRunInProgress) {
+			slog.WarnContext(ctx, "scheduled run skipped, a run is already in progress", "activeRunID", run.ID)
+		} else if err != nil {
+			slog.WarnContext(ctx, "scheduled run skipped", "err", err)
+		}
+	}
+}
+
+// nextDaily returns the first time strictly after now that is at past
+// midnight UTC.
+func nextDaily(now time.Time, at time.Duration) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(at)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
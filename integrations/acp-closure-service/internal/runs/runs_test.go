@@ -0,0 +1,218 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package runs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/report"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/runlock"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/sweep"
+)
+
+// blockingSweep reports one evaluated project, then waits for release
+// before returning a report with failures failures.
+type blockingSweep struct {
+	progressed chan struct{}
+	release    chan struct{}
+	failures   int
+	got        []Request
+}
+
+func newBlockingSweep(failures int) *blockingSweep {
+	return &blockingSweep{progressed: make(chan struct{}, 1), release: make(chan struct{}), failures: failures}
+}
+
+func (b *blockingSweep) run(ctx context.Context, runID string, req Request, progress sweep.Progress) report.Report {
+	b.got = append(b.got, req)
+	progress(sweep.ProjectOutcome{ProjectID: "p1"})
+	select {
+	case b.progressed <- struct{}{}:
+	default:
+	}
+	<-b.release
+	return report.Report{RunID: runID, ProjectsEvaluated: 1, FailureCount: b.failures}
+}
+
+func sequentialIDs() func() string {
+	n := 0
+	return func() string {
+		n++
+		return fmt.Sprintf("run-%d", n)
+	}
+}
+
+func TestManager_OneRunAtATime(t *testing.T) {
+	sw := newBlockingSweep(0)
+	m := NewManager(sw.run, sequentialIDs(), nil)
+
+	first, err := m.Start(context.Background(), TriggerAPI, Request{ProjectID: "p1", DryRun: true})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if first.ID != "run-1" || first.State != StateRunning || first.Trigger != TriggerAPI || !first.DryRun {
+		t.Errorf("started run = %+v", first)
+	}
+	<-sw.progressed
+
+	active, err := m.Start(context.Background(), TriggerSchedule, Request{})
+	if !errors.Is(err, ErrRunInProgress) || active.ID != "run-1" {
+		t.Fatalf("second Start() = %+v, %v; want run-1, ErrRunInProgress", active, err)
+	}
+
+	running, ok := m.Get("run-1")
+	if !ok || running.State != StateRunning || running.Progress.ProjectsEvaluated != 1 || running.Report != nil {
+		t.Errorf("running run = %+v", running)
+	}
+
+	close(sw.release)
+	if !m.Wait(context.Background()) {
+		t.Fatal("Wait() = false, want true")
+	}
+	done, _ := m.Get("run-1")
+	if done.State != StateSucceeded || done.FinishedAt == nil || done.Report == nil || done.Report.RunID != "run-1" {
+		t.Errorf("finished run = %+v", done)
+	}
+
+	sw.release = make(chan struct{})
+	close(sw.release)
+	if _, err := m.Start(context.Background(), TriggerSchedule, Request{}); err != nil {
+		t.Fatalf("Start() after the first run finished: %v", err)
+	}
+	m.Wait(context.Background())
+
+	list := m.List()
+	if len(list) != 2 || list[0].ID != "run-2" || list[1].ID != "run-1" || list[1].Report != nil {
+		t.Errorf("List() = %+v, want run-2 then run-1 without reports", list)
+	}
+	if len(sw.got) != 2 || sw.got[0].ProjectID != "p1" {
+		t.Errorf("sweeps = %+v", sw.got)
+	}
+}
+
+// TestManager_RunLockExcludesOtherProcesses verifies Start refuses while
+// another holder, such as the one-shot CLI, has the run lock, and that a
+// run holds the lock until it finishes.
+func TestManager_RunLockExcludesOtherProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acp-run.lock")
+	sw := newBlockingSweep(0)
+	m := NewManager(sw.run, sequentialIDs(), runlock.New(path))
+
+	other, err := runlock.New(path).TryLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Start(context.Background(), TriggerAPI, Request{}); !errors.Is(err, ErrRunElsewhere) {
+		t.Fatalf("Start() while locked elsewhere error = %v, want ErrRunElsewhere", err)
+	}
+	if len(m.List()) != 0 {
+		t.Errorf("List() = %+v, want no runs", m.List())
+	}
+	other()
+
+	if _, err := m.Start(context.Background(), TriggerAPI, Request{}); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	<-sw.progressed
+	if _, err := runlock.New(path).TryLock(); !errors.Is(err, runlock.ErrHeld) {
+		t.Errorf("TryLock() during a run error = %v, want ErrHeld", err)
+	}
+	close(sw.release)
+	m.Wait(context.Background())
+	release, err := runlock.New(path).TryLock()
+	if err != nil {
+		t.Fatalf("TryLock() after the run error = %v", err)
+	}
+	release()
+}
+
+func TestManager_FailuresFailTheRun(t *testing.T) {
+	sw := newBlockingSweep(1)
+	close(sw.release)
+	m := NewManager(sw.run, sequentialIDs(), nil)
+	if _, err := m.Start(context.Background(), TriggerAPI, Request{}); err != nil {
+		t.Fatal(err)
+	}
+	m.Wait(context.Background())
+	if run, _ := m.Get("run-1"); run.State != StateFailed || run.Progress.Failures != 1 {
+		t.Errorf("run = %+v, want failed with 1 failure", run)
+	}
+}
+
+func TestManager_RunOutlivesStartContext(t *testing.T) {
+	sw := newBlockingSweep(0)
+	m := NewManager(sw.run, sequentialIDs(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := m.Start(ctx, TriggerAPI, Request{}); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-sw.progressed
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer waitCancel()
+	if m.Wait(waitCtx) {
+		t.Error("Wait() = true while the run is still going")
+	}
+	close(sw.release)
+	if !m.Wait(context.Background()) {
+		t.Error("Wait() = false after the run finished")
+	}
+}
+
+func TestManager_HistoryIsBounded(t *testing.T) {
+	sw := newBlockingSweep(0)
+	close(sw.release)
+	m := NewManager(sw.run, sequentialIDs(), nil)
+	for range historySize + 5 {
+		if _, err := m.Start(context.Background(), TriggerAPI, Request{}); err != nil {
+			t.Fatal(err)
+		}
+		m.Wait(context.Background())
+	}
+	list := m.List()
+	if len(list) != historySize || list[0].ID != fmt.Sprintf("run-%d", historySize+5) {
+		t.Errorf("List() = %d runs, newest %s", len(list), list[0].ID)
+	}
+	if _, ok := m.Get("run-1"); ok {
+		t.Error("Get(run-1) found a run that should have been dropped")
+	}
+}
+
+func TestNextDaily(t *testing.T) {
+	at := 2*time.Hour + 30*time.Minute
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC), time.Date(2026, 10, 20, 2, 30, 0, 0, time.UTC)},
+		{time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 2, 30, 0, 0, time.UTC)},
+		// 07:00 in Colombo is 01:30 UTC, still before the 02:30 UTC run.
+		{time.Date(2026, 10, 19, 7, 0, 0, 0, time.FixedZone("IST", 5*3600+1800)), time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := nextDaily(tt.now, at); !got.Equal(tt.want) {
+			t.Errorf("nextDaily(%s) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package server is the service mode's HTTP API: trigger a run, see one
+// run's progress and report, list recent runs, and — with the approval
+// gate on — list and decide suspensions awaiting approval. Like
+// csm-integration-service, there is no auth layer here — the Choreo API
+// Manager gateway in front of the service is the trust boundary. What this
+// package does guard is live writes: a request can always ask for a dry
+// run, but a live run only when the service itself was started with
+// DRY_RUN=false.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/runs"
+)
+
+// uuidRe validates a project ID before it is forwarded upstream.
+var uuidRe = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// jwtAssertionHeader carries the JWT the Choreo gateway forwards for the
+// signed-in user.
+const jwtAssertionHeader = "x-jwt-assertion"
+
+// maxRequestBodyBytes caps incoming request bodies; a run request is a few
+// dozen bytes.
+const maxRequestBodyBytes = 1 << 10
+
+// Error message constants matching csm-integration-service's vocabulary.
+const (
+	ErrMsgBadRequest   = "Invalid request payload."
+	ErrMsgTooLarge     = "Request body too large."
+	ErrMsgNotFound     = "The requested resource was not found!"
+	ErrMsgInvalidUUID  = "Invalid UUID format."
+	ErrMsgInternal     = "An internal server error occurred. Please try again later."
+	ErrMsgUnauthorized = "You are not authorized to perform this action. Please try again."
+	errMsgLiveDisabled = "Live runs are disabled: the service is running with DRY_RUN=true."
+	errMsgRunActive    = "A run is already in progress."
+	errMsgRunElsewhere = "A run is already in progress in another instance of the service or the one-shot job."
+	errMsgDecision     = `decision must be "approve" or "reject".`
+	errMsgReason       = "A rejection needs a reason."
+	errMsgDecided      = "The approval has already been decided."
+)
+
+// runManager abstracts the *runs.Manager used by Handler.
+type runManager interface {
+	Start(ctx context.Context, trigger runs.Trigger, req runs.Request) (runs.Run, error)
+	Get(id string) (runs.Run, bool)
+	List() []runs.Run
+}
+
+// Handler serves the run API.
+type Handler struct {
+	runs runManager
+	// dryRun is the service's DRY_RUN: the default for a request that does
+	// not say, and, when true, a bar on any request asking for a live run.
+	dryRun bool
+	// testProjectID is the service's TEST_PROJECT_ID: the default scope for
+	// a request that does not name a project.
+	testProjectID string
+	// approvals is the approval gate's store, nil when the gate is off —
+	// the approval routes are then not served at all.
+	approvals *approvals.Store
+}
+
+// NewHandler creates a Handler starting runs with m and, if store is
+// non-nil, deciding approvals in store.
+func NewHandler(m runManager, dryRun bool, testProjectID string, store *approvals.Store) *Handler {
+	return &Handler{runs: m, dryRun: dryRun, testProjectID: testProjectID, approvals: store}
+}
+
+// Routes returns the API's routes.
+func (h *Handler) Routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /runs", h.StartRun)
+	mux.HandleFunc("GET /runs", h.ListRuns)
+	mux.HandleFunc("GET /runs/{runID}", h.GetRun)
+	if h.approvals != nil {
+		mux.HandleFunc("GET /approvals", h.ListApprovals)
+		mux.HandleFunc("POST /approvals/{projectID}", h.DecideApproval)
+	}
+	return mux
+}
+
+// startRunRequest is the POST /runs body. Every field is optional: an empty
+// body starts the same run the one-shot CLI would.
+type startRunRequest struct {
+	ProjectID string `json:"projectId"`
+	DryRun    *bool  `json:"dryRun"`
+}
+
+// runConflictBody is the 409 payload naming the run already in progress.
+type runConflictBody struct {
+	Message string `json:"message"`
+	RunID   string `json:"runId"`
+}
+
+// StartRun handles POST /runs. Starts a sweep — every open project, or the
+// one named by projectId — and returns 202 with the new run, whose progress
+// GET /runs/{runID} then follows. 409 while another run is going.
+func (h *Handler) StartRun(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var req startRunRequest
+	if len(bytes.TrimSpace(body)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+			return
+		}
+	}
+	if req.ProjectID != "" && !uuidRe.MatchString(req.ProjectID) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+
+	run := runs.Request{ProjectID: req.ProjectID, DryRun: h.dryRun}
+	if run.ProjectID == "" {
+		run.ProjectID = h.testProjectID
+	}
+	if req.DryRun != nil {
+		if !*req.DryRun && h.dryRun {
+			writeError(w, http.StatusForbidden, errMsgLiveDisabled)
+			return
+		}
+		run.DryRun = *req.DryRun
+	}
+
+	started, err := h.runs.Start(r.Context(), runs.TriggerAPI, run)
+	if errors.Is(err, runs.ErrRunInProgress) {
+		writeJSON(w, http.StatusConflict, runConflictBody{Message: errMsgRunActive, RunID: started.ID})
+		return
+	}
+	if errors.Is(err, runs.ErrRunElsewhere) {
+		writeError(w, http.StatusConflict, errMsgRunElsewhere)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "start run failed", "err", err)
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+	w.Header().Set("Location", "/runs/"+started.ID)
+	writeJSON(w, http.StatusAccepted, started)
+}
+
+// GetRun handles GET /runs/{runID}: the run's state and progress, and its
+// report once finished.
+func (h *Handler) GetRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := h.runs.Get(r.PathValue("runID"))
+	if !ok {
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// runList is the GET /runs payload.
+type runList struct {
+	Runs []runs.Run `json:"runs"`
+}
+
+// ListRuns handles GET /runs: the runs since the service started, newest
+// first, without their reports.
+func (h *Handler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, runList{Runs: h.runs.List()})
+}
+
+// approvalList is the GET /approvals payload.
+type approvalList struct {
+	Approvals []approvals.Approval `json:"approvals"`
+}
+
+// ListApprovals handles GET /approvals: every recorded approval, pending
+// and decided, oldest request first.
+func (h *Handler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	list, err := h.approvals.List()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "list approvals failed", "err", err)
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+	writeJSON(w, http.StatusOK, approvalList{Approvals: list})
+}
+
+// decideApprovalRequest is the POST /approvals/{projectID} body. Who
+// decided is not part of it: that is the caller's gateway identity (see
+// decider).
+type decideApprovalRequest struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// DecideApproval handles POST /approvals/{projectID}: approves or rejects
+// the project's pending approval, recorded as decided by the caller, and
+// returns it decided. The decision takes effect on the next run. 401
+// without a gateway identity, 404 without an approval, 409 once decided.
+func (h *Handler) DecideApproval(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("projectID")
+	if !uuidRe.MatchString(projectID) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+	decidedBy, err := decider(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "decide approval: no caller identity", "projectID", projectID, "err", err)
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return
+	}
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	var req decideApprovalRequest
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+	if req.Decision != "approve" && req.Decision != "reject" {
+		writeError(w, http.StatusBadRequest, errMsgDecision)
+		return
+	}
+
+	decided, err := h.approvals.Decide(projectID, req.Decision == "approve", decidedBy, req.Reason)
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, decided)
+	case errors.Is(err, approvals.ErrReasonRequired):
+		writeError(w, http.StatusBadRequest, errMsgReason)
+	case errors.Is(err, approvals.ErrNotFound):
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+	case errors.Is(err, approvals.ErrNotPending):
+		writeError(w, http.StatusConflict, errMsgDecided)
+	default:
+		slog.ErrorContext(r.Context(), "decide approval failed", "projectID", projectID, "err", err)
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+	}
+}
+
+// decider returns the email claim of r's x-jwt-assertion: the signed-in
+// user the gateway forwarded the request for, so an approval records who
+// actually decided it rather than whatever a request body claims. The
+// assertion's signature is not verified here — the gateway verified the
+// caller and issued it, and remains the trust boundary (see the package
+// doc).
+func decider(r *http.Request) (string, error) {
+	token := r.Header.Get(jwtAssertionHeader)
+	if token == "" {
+		return "", errors.New("no x-jwt-assertion")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed x-jwt-assertion")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed x-jwt-assertion payload")
+	}
+	var claims struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", errors.New("malformed x-jwt-assertion claims")
+	}
+	if strings.TrimSpace(claims.Email) == "" {
+		return "", errors.New("x-jwt-assertion has no email claim")
+	}
+	return claims.Email, nil
+}
+
+// readBody reads r's body, capped at maxRequestBodyBytes, writing the error
+// response itself and reporting false if that fails.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return nil, false
+	}
+	return body, true
+}
+
+// errorBody is the JSON error payload format.
+type errorBody struct {
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, errorBody{Message: message})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/runs"
+)
+
+const testProjectID = "e3e87599-1bc7-6650-182c-0dc5604bcb68"
+
+type mockRunManager struct {
+	started []runs.Request
+	err     error
+	runs    map[string]runs.Run
+}
+
+func (m *mockRunManager) Start(ctx context.Context, trigger runs.Trigger, req runs.Request) (runs.Run, error) {
+	if m.err != nil {
+		return runs.Run{ID: "active"}, m.err
+	}
+	m.started = append(m.started, req)
+	return runs.Run{ID: "run-1", Trigger: trigger, ProjectID: req.ProjectID, DryRun: req.DryRun, State: runs.StateRunning}, nil
+}
+
+func (m *mockRunManager) Get(id string) (runs.Run, bool) {
+	run, ok := m.runs[id]
+	return run, ok
+}
+
+func (m *mockRunManager) List() []runs.Run {
+	return []runs.Run{m.runs["run-1"]}
+}
+
+func serve(h *Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.Routes().ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+// testAssertion returns an unsigned x-jwt-assertion carrying claims.
+func testAssertion(claims string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"none"}`)) + "." + enc([]byte(claims)) + ".sig"
+}
+
+func TestStartRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		serviceDryRun bool
+		defaultScope  string
+		body          string
+		wantStatus    int
+		want          runs.Request
+	}{
+		{"empty body uses the service defaults", true, "", "", http.StatusAccepted, runs.Request{DryRun: true}},
+		{"empty body keeps TEST_PROJECT_ID scope", false, testProjectID, "{}", http.StatusAccepted, runs.Request{ProjectID: testProjectID}},
+		{"single project", true, "", `{"projectId":"` + testProjectID + `"}`, http.StatusAccepted, runs.Request{ProjectID: testProjectID, DryRun: true}},
+		{"dry run on a live service", false, "", `{"dryRun":true}`, http.StatusAccepted, runs.Request{DryRun: true}},
+		{"live run on a live service", false, "", `{"dryRun":false}`, http.StatusAccepted, runs.Request{}},
+		{"live run on a dry-run service", true, "", `{"dryRun":false}`, http.StatusForbidden, runs.Request{}},
+		{"bad project ID", true, "", `{"projectId":"../x"}`, http.StatusBadRequest, runs.Request{}},
+		{"unknown field", true, "", `{"project":"x"}`, http.StatusBadRequest, runs.Request{}},
+		{"too large", true, "", `{"projectId":"` + strings.Repeat("a", maxRequestBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, runs.Request{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockRunManager{}
+			w := serve(NewHandler(m, tt.serviceDryRun, tt.defaultScope, nil), http.MethodPost, "/runs", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusAccepted {
+				if len(m.started) != 0 {
+					t.Errorf("a run was started: %+v", m.started)
+				}
+				return
+			}
+			if len(m.started) != 1 || m.started[0] != tt.want {
+				t.Errorf("started %+v, want %+v", m.started, tt.want)
+			}
+			if loc := w.Header().Get("Location"); loc != "/runs/run-1" {
+				t.Errorf("Location = %q", loc)
+			}
+		})
+	}
+}
+
+func TestStartRun_ConflictNamesActiveRun(t *testing.T) {
+	w := serve(NewHandler(&mockRunManager{err: runs.ErrRunInProgress}, true, "", nil), http.MethodPost, "/runs", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+	var body runConflictBody
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil || body.RunID != "active" {
+		t.Errorf("body = %+v, %v", body, err)
+	}
+}
+
+func TestStartRun_ConflictWithAnotherProcess(t *testing.T) {
+	w := serve(NewHandler(&mockRunManager{err: runs.ErrRunElsewhere}, true, "", nil), http.MethodPost, "/runs", "")
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), errMsgRunElsewhere) {
+		t.Errorf("status = %d, body = %s; want 409 naming another process", w.Code, w.Body)
+	}
+}
+
+func TestStartRun_LockFailureIsInternal(t *testing.T) {
+	w := serve(NewHandler(&mockRunManager{err: errors.New("runlock: permission denied")}, true, "", nil), http.MethodPost, "/runs", "")
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+}
+
+func TestGetAndListRuns(t *testing.T) {
+	m := &mockRunManager{runs: map[string]runs.Run{"run-1": {ID: "run-1", State: runs.StateSucceeded}}}
+	h := NewHandler(m, true, "", nil)
+
+	w := serve(h, http.MethodGet, "/runs/run-1", "")
+	var run runs.Run
+	if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&run) != nil || run.State != runs.StateSucceeded {
+		t.Errorf("GET /runs/run-1 = %d %+v", w.Code, run)
+	}
+
+	if w := serve(h, http.MethodGet, "/runs/nope", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET /runs/nope = %d, want 404", w.Code)
+	}
+
+	w = serve(h, http.MethodGet, "/runs", "")
+	var list runList
+	if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&list) != nil || len(list.Runs) != 1 {
+		t.Errorf("GET /runs = %d %+v", w.Code, list)
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	store := approvals.NewStore(filepath.Join(t.TempDir(), "approvals.json"))
+	gate := approvals.NewGate(store, []string{"enterprise"}, false)
+	if _, err := gate.Check(context.Background(), approvals.Request{ProjectID: testProjectID, Tier: "enterprise", EndDate: time.Now()}); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	h := NewHandler(&mockRunManager{}, true, "", store)
+
+	w := serve(h, http.MethodGet, "/approvals", "")
+	var list approvalList
+	if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&list) != nil || len(list.Approvals) != 1 {
+		t.Fatalf("GET /approvals = %d %+v", w.Code, list)
+	}
+
+	finance := testAssertion(`{"email":"finance@example.com"}`)
+	tests := []struct {
+		name, path, assertion, body string
+		want                        int
+	}{
+		{"bad uuid", "/approvals/nope", finance, `{"decision":"approve"}`, http.StatusBadRequest},
+		{"no assertion", "/approvals/" + testProjectID, "", `{"decision":"approve"}`, http.StatusUnauthorized},
+		{"no email claim", "/approvals/" + testProjectID, testAssertion(`{"sub":"finance"}`), `{"decision":"approve"}`, http.StatusUnauthorized},
+		{"decidedBy in the body", "/approvals/" + testProjectID, finance, `{"decision":"approve","decidedBy":"someone-else"}`, http.StatusBadRequest},
+		{"bad decision", "/approvals/" + testProjectID, finance, `{"decision":"maybe"}`, http.StatusBadRequest},
+		{"reject without reason", "/approvals/" + testProjectID, finance, `{"decision":"reject"}`, http.StatusBadRequest},
+		{"unknown project", "/approvals/1b6a5d3e-4c1f-4e2a-9a7b-1c2d3e4f5a6b", finance, `{"decision":"approve"}`, http.StatusNotFound},
+		{"reject", "/approvals/" + testProjectID, finance, `{"decision":"reject","reason":"in negotiation"}`, http.StatusOK},
+		{"already decided", "/approvals/" + testProjectID, finance, `{"decision":"approve"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.assertion != "" {
+				r.Header.Set("x-jwt-assertion", tt.assertion)
+			}
+			w := httptest.NewRecorder()
+			h.Routes().ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("POST %s = %d, want %d (%s)", tt.path, w.Code, tt.want, w.Body)
+			}
+		})
+	}
+	if list, _ := store.List(); len(list) != 1 || list[0].DecidedBy != "finance@example.com" {
+		t.Errorf("approvals after the decision = %+v, want decided by the caller", list)
+	}
+
+	if w := serve(NewHandler(&mockRunManager{}, true, "", nil), http.MethodGet, "/approvals", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET /approvals with the gate off = %d, want 404", w.Code)
+	}
+}
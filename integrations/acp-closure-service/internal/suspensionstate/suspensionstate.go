@@ -35,6 +35,9 @@ import (
 
 const subscriptionEndDateKey = "based_on_subscription_end_date"
 
+// eventTypeOpen is the event_type of a project no notice has fired for.
+const eventTypeOpen = "open"
+
 // eventTypeToWindow maps the legacy event_type vocabulary observed in
 // based_on_subscription_end_date to closure.NoticeWindow.
 var eventTypeToWindow = map[string]closure.NoticeWindow{
@@ -99,13 +102,6 @@ func LastNoticeWindow(raw json.RawMessage) (*closure.NoticeWindow, error) {
 // unmarshaled into a typed structure and re-serialized, only carried through
 // as raw JSON, so nothing about their formatting or content can drift.
 func WithSubscriptionEndDateState(raw json.RawMessage, window closure.NoticeWindow, actions map[string]string) (json.RawMessage, error) {
-	blob := map[string]json.RawMessage{}
-	if len(raw) > 0 {
-		if err := json.Unmarshal(raw, &blob); err != nil {
-			return nil, fmt.Errorf("suspensionstate: parse blob: %w", err)
-		}
-	}
-
 	eventType, ok := windowToEventType[window]
 	if !ok {
 		return nil, fmt.Errorf("suspensionstate: no event_type mapping for window %d", window)
@@ -115,6 +111,26 @@ func WithSubscriptionEndDateState(raw json.RawMessage, window closure.NoticeWind
 	for action, result := range actions {
 		section[action] = result
 	}
+	return withSubscriptionEndDateSection(raw, section)
+}
+
+// WithSubscriptionEndDateOpen returns a copy of raw with the
+// based_on_subscription_end_date key reset to event_type "open" — the state
+// of a project no notice has fired for, so LastNoticeWindow reads nil again
+// — dropping the previous window's action results with it. Every other key
+// is preserved byte-for-byte, as in WithSubscriptionEndDateState. Used when
+// a renewal has made the recorded window stale.
+func WithSubscriptionEndDateOpen(raw json.RawMessage) (json.RawMessage, error) {
+	return withSubscriptionEndDateSection(raw, map[string]string{"event_type": eventTypeOpen})
+}
+
+func withSubscriptionEndDateSection(raw json.RawMessage, section map[string]string) (json.RawMessage, error) {
+	blob := map[string]json.RawMessage{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &blob); err != nil {
+			return nil, fmt.Errorf("suspensionstate: parse blob: %w", err)
+		}
+	}
 
 	sectionRaw, err := json.Marshal(section)
 	if err != nil {
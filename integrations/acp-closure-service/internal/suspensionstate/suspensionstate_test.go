@@ -206,6 +206,45 @@ func TestWithSubscriptionEndDateState_RejectsUnmappedWindow(t *testing.T) {
 	}
 }
 
+// TestWithSubscriptionEndDateOpen verifies a reset drops the recorded
+// window and its action results, reads back as no prior notice, and leaves
+// the Phase 2 sections untouched.
+func TestWithSubscriptionEndDateOpen(t *testing.T) {
+	input := json.RawMessage(`{
+		"based_on_subscription_end_date": {
+			"event_type": "suspend",
+			"actionSendEmailNotification": "SUCCESSFUL"
+		},
+		"based_on_due_invoices": {
+			"event_type": "7_days_notice",
+			"actionSendEmailNotification": "SUCCESSFUL"
+		}
+	}`)
+
+	got, err := WithSubscriptionEndDateOpen(input)
+	if err != nil {
+		t.Fatalf("WithSubscriptionEndDateOpen() error = %v, want nil", err)
+	}
+
+	if window, err := LastNoticeWindow(got); err != nil || window != nil {
+		t.Errorf("LastNoticeWindow(reset) = %v, %v; want nil, nil", window, err)
+	}
+
+	var gotBlob, wantBlob map[string]json.RawMessage
+	if err := json.Unmarshal(got, &gotBlob); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal(input, &wantBlob); err != nil {
+		t.Fatalf("test input is not valid JSON: %v", err)
+	}
+	if want := `{"event_type":"open"}`; string(gotBlob[subscriptionEndDateKey]) != want {
+		t.Errorf("%s = %s, want %s", subscriptionEndDateKey, gotBlob[subscriptionEndDateKey], want)
+	}
+	if !bytes.Equal(normalizeJSON(t, gotBlob["based_on_due_invoices"]), normalizeJSON(t, wantBlob["based_on_due_invoices"])) {
+		t.Errorf("based_on_due_invoices changed: %s", gotBlob["based_on_due_invoices"])
+	}
+}
+
 // normalizeJSON re-marshals a JSON value through Go's canonical encoding so
 // two semantically-identical values that differ only in whitespace compare
 // equal. The values under test here are never re-serialized by
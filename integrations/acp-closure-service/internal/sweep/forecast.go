@@ -0,0 +1,191 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sweep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+)
+
+// MaxForecastDays bounds Forecast's range. The widest notice window is 90
+// days, so a year covers every notice a project can still get plus its
+// suspension, with room to spare.
+const MaxForecastDays = 366
+
+// ForecastResult is what Forecast predicts a daily run would do over its
+// range, one event per project per day on which anything happens.
+type ForecastResult struct {
+	// From is the first simulated run; the others follow it at the same
+	// time of day.
+	From             time.Time
+	Days             int
+	ProjectsForecast int
+	ProjectsExcluded int
+	Events           []ForecastEvent
+	Failures         []ProjectFailure
+}
+
+// ForecastEvent is one simulated run's action on one project: the notices
+// it would send, the suspension it would write, or both — or, for a
+// suspended project already renewed at the start of the range, its
+// reinstatement, with DaysRemaining and Window left zero.
+type ForecastEvent struct {
+	Date          time.Time
+	ProjectID     string
+	ProjectName   string
+	ProjectKey    string
+	DaysRemaining int
+	Window        closure.NoticeWindow
+	Notices       []notify.Notice
+	Suspends      bool
+	Reinstates    bool
+}
+
+// Forecast replays the daily run over days days starting at now — the same
+// projects Run would cover, the same processProject — without writing or
+// sending anything, so "what goes out over the next 30 days?" can be
+// answered before the job is enabled in a new environment.
+//
+// Each project is simulated on its own copy: writes go to a
+// simulatedUpdater that applies them to that copy instead of
+// entity-service, so each day's simulated run sees the lastNoticeWindow
+// (and endDateClosureState) the previous day left behind, exactly as the
+// real run would see them re-fetched. Notices are built in full — contacts
+// are read, so each one shows its real recipients — and then dropped.
+// Project data is read once, at now; a contact or end-date change later in
+// the range is not foreseen. The approval gate is not consulted: every
+// suspension is forecast as if approved, since when (or whether) a pending
+// one is decided can't be foreseen either.
+//
+// Like Run, the broad forecast makes a second pass over "Suspended"
+// projects and simulates only those already renewed at now — the
+// reinstatements the first run would make. A suspended project renewed
+// later in the range is not foreseen, since project data is read once.
+//
+// Failures follow Run's rules: a fetch failure for the project list is
+// fatal, and one project's failure (a contact lookup, say) ends that
+// project's simulation and is recorded without stopping the rest.
+func Forecast(ctx context.Context, reader sweepReader, now time.Time, days int, projectID string, excludedProjectIDs map[string]bool) (ForecastResult, error) {
+	result := ForecastResult{From: now, Days: days}
+	if days < 1 || days > MaxForecastDays {
+		return result, fmt.Errorf("sweep: forecast days must be between 1 and %d, got %d", MaxForecastDays, days)
+	}
+
+	visit := func(proj project) {
+		result.ProjectsForecast++
+		events, err := forecastProject(ctx, reader, now, days, proj)
+		result.Events = append(result.Events, events...)
+		if err != nil {
+			slog.ErrorContext(ctx, "project forecast failed", "projectID", proj.ID, "err", err)
+			result.Failures = append(result.Failures, ProjectFailure{ProjectID: proj.ID, Err: err})
+		}
+	}
+
+	err := eachProject(ctx, reader, projectID, "Open", excludedProjectIDs, visit,
+		func(string) { result.ProjectsExcluded++ },
+	)
+	if err == nil && projectID == "" {
+		err = eachProject(ctx, reader, "", "Suspended", excludedProjectIDs,
+			func(proj project) {
+				if renewed(now, proj) {
+					visit(proj)
+				}
+			},
+			func(string) {},
+		)
+	}
+
+	sort.SliceStable(result.Events, func(i, j int) bool {
+		return result.Events[i].Date.Before(result.Events[j].Date)
+	})
+	return result, err
+}
+
+// forecastProject runs processProject for proj once per simulated day,
+// returning the events up to the first failure.
+func forecastProject(ctx context.Context, reader entityReader, now time.Time, days int, proj project) ([]ForecastEvent, error) {
+	updater := &simulatedUpdater{proj: &proj}
+	var events []ForecastEvent
+	for day := range days {
+		at := now.AddDate(0, 0, day)
+		out, err := processProject(ctx, reader, updater, discardNotifier{}, nil, at, proj)
+		if err != nil {
+			return events, fmt.Errorf("%s: %w", at.Format(time.DateOnly), err)
+		}
+		if len(out.Notices) == 0 && !out.Suspended && !out.Reinstated {
+			continue
+		}
+		event := ForecastEvent{
+			Date:        at,
+			ProjectID:   proj.ID,
+			ProjectName: proj.Name,
+			ProjectKey:  proj.ProjectKey,
+			Suspends:    out.Suspended,
+			Reinstates:  out.Reinstated,
+		}
+		if out.Decision != nil {
+			event.DaysRemaining, event.Window = out.Decision.DaysRemaining, out.Decision.Window
+		}
+		for _, n := range out.Notices {
+			event.Notices = append(event.Notices, n.Notice)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// simulatedUpdater satisfies projectUpdater by applying the two writes
+// processProject makes — the suspensionProcessState record and the
+// endDateClosureState suspension — to an in-memory project rather than to
+// entity-service. Unlike DryRunProjectUpdater, the write is kept: that is
+// what carries a forecast's state from one simulated day to the next.
+type simulatedUpdater struct {
+	proj *project
+}
+
+// UpdateProject applies body to the simulated project.
+func (u *simulatedUpdater) UpdateProject(ctx context.Context, id string, body []byte) ([]byte, error) {
+	var patch struct {
+		SuspensionProcessState json.RawMessage `json:"suspensionProcessState"`
+		EndDateClosureState    *string         `json:"endDateClosureState"`
+	}
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return nil, fmt.Errorf("simulate update: %w", err)
+	}
+	if patch.SuspensionProcessState != nil {
+		u.proj.SuspensionProcessState = patch.SuspensionProcessState
+	}
+	if patch.EndDateClosureState != nil {
+		u.proj.EndDateClosureState = patch.EndDateClosureState
+	}
+	return []byte(`{}`), nil
+}
+
+// discardNotifier satisfies notifier by dropping every notice; a forecast
+// reads the notices off processProject's outcome instead.
+type discardNotifier struct{}
+
+func (discardNotifier) Send(ctx context.Context, n notify.Notice) error { return nil }
+
+func (discardNotifier) Delivers() bool { return false }
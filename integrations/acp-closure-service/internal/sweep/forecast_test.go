@@ -0,0 +1,140 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sweep
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+)
+
+// TestForecast_CarriesStateForward covers a project 20 days out over a
+// 30-day forecast: each window fires once, on the day it is reached, and
+// suspension fires once on day 0 — the simulated lastNoticeWindow and
+// endDateClosureState carry over from one day to the next.
+func TestForecast_CarriesStateForward(t *testing.T) {
+	now := time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC)
+	reader := &mockEntityReader{
+		searchProjectsFn: func(ctx context.Context, body []byte) ([]byte, error) {
+			return []byte(`{
+				"projects": [
+					{"id": "p1", "name": "Acme", "key": "ACME", "endDate": "2026-11-08T00:00:00Z", "endDateClosureState": "Open"},
+					{"id": "p2", "endDate": "2027-06-01T00:00:00Z"},
+					{"id": "p3", "endDate": "2026-11-01T00:00:00Z"}
+				],
+				"total": 3, "limit": 50, "offset": 0, "hasMore": false
+			}`), nil
+		},
+		searchProjectContactsFn: func(ctx context.Context, projectID string, body []byte) ([]byte, error) {
+			return []byte(`{"contacts":[{"name":"Bob","email":"bob@customer.example","roles":["business_contact"]}]}`), nil
+		},
+	}
+
+	got, err := Forecast(context.Background(), reader, now, 30, "", map[string]bool{"p3": true})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v, want nil", err)
+	}
+	if got.ProjectsForecast != 2 || got.ProjectsExcluded != 1 || len(got.Failures) != 0 {
+		t.Errorf("counts = %d forecast, %d excluded, %d failed", got.ProjectsForecast, got.ProjectsExcluded, len(got.Failures))
+	}
+
+	want := []struct {
+		day      int
+		window   closure.NoticeWindow
+		notices  int
+		suspends bool
+	}{
+		{0, closure.NoticeWindow30, 1, false},
+		{5, closure.NoticeWindow15, 2, false},
+		{13, closure.NoticeWindow7, 2, false},
+		{20, closure.NoticeWindow0, 2, true},
+	}
+	if len(got.Events) != len(want) {
+		t.Fatalf("events = %+v, want %d", got.Events, len(want))
+	}
+	for i, w := range want {
+		e := got.Events[i]
+		if !e.Date.Equal(now.AddDate(0, 0, w.day)) || e.ProjectID != "p1" || e.Window != w.window ||
+			len(e.Notices) != w.notices || e.Suspends != w.suspends {
+			t.Errorf("event %d = %+v, want day %d window %d", i, e, w.day, w.window)
+		}
+	}
+	if c := got.Events[1].Notices[1].Recipients.Customer; c == nil || c.Email != "bob@customer.example" {
+		t.Errorf("15-day customer notice recipient = %+v", c)
+	}
+}
+
+// TestForecast_IncludesRenewedSuspendedProjects verifies the forecast makes
+// Run's second pass: a suspended project already renewed is forecast to be
+// reinstated on the first day, and one still lapsed is passed over and not
+// counted.
+func TestForecast_IncludesRenewedSuspendedProjects(t *testing.T) {
+	now := time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC)
+	state := `{"based_on_subscription_end_date":{"event_type":"suspend"}}`
+	reader := &mockEntityReader{
+		searchSuspendedFn: func(ctx context.Context, body []byte) ([]byte, error) {
+			return []byte(`{
+				"projects": [
+					{"id": "renewed", "endDate": "2027-10-19T00:00:00Z", "endDateClosureState": "Suspended", "suspensionProcessState": ` + state + `},
+					{"id": "lapsed", "endDate": "2026-10-09T00:00:00Z", "endDateClosureState": "Suspended", "suspensionProcessState": ` + state + `}
+				],
+				"total": 2, "limit": 50, "offset": 0, "hasMore": false
+			}`), nil
+		},
+	}
+
+	got, err := Forecast(context.Background(), reader, now, 30, "", nil)
+	if err != nil {
+		t.Fatalf("Forecast() error = %v, want nil", err)
+	}
+	if got.ProjectsForecast != 1 || len(got.Failures) != 0 {
+		t.Errorf("counts = %d forecast, %d failed; want only the renewed project", got.ProjectsForecast, len(got.Failures))
+	}
+	if len(got.Events) != 1 || got.Events[0].ProjectID != "renewed" || !got.Events[0].Reinstates || !got.Events[0].Date.Equal(now) {
+		t.Errorf("events = %+v, want the renewed project reinstated on day 0", got.Events)
+	}
+}
+
+func TestForecast_ProjectFailureDoesNotBlockTheRest(t *testing.T) {
+	reader := &mockEntityReader{
+		getProjectFn: func(ctx context.Context, id string) ([]byte, error) {
+			return []byte(`{"id": "p1", "account": {"id": "a1"}, "endDate": "2026-10-25T00:00:00Z"}`), nil
+		},
+		getAccountFn: func(ctx context.Context, id string) ([]byte, error) {
+			return nil, errors.New("boom")
+		},
+	}
+
+	got, err := Forecast(context.Background(), reader, time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC), 10, "p1", nil)
+	if err != nil {
+		t.Fatalf("Forecast() error = %v, want nil", err)
+	}
+	if len(got.Failures) != 1 || got.Failures[0].ProjectID != "p1" || len(got.Events) != 0 {
+		t.Errorf("forecast = %+v, want one failure and no events", got)
+	}
+}
+
+func TestForecast_RejectsOutOfRangeDays(t *testing.T) {
+	for _, days := range []int{-1, 0, MaxForecastDays + 1} {
+		if _, err := Forecast(context.Background(), &mockEntityReader{}, time.Now(), days, "", nil); err == nil {
+			t.Errorf("Forecast(days=%d) error = nil, want an error", days)
+		}
+	}
+}
@@ -18,7 +18,9 @@ package sweep
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
 )
 
@@ -26,10 +28,15 @@ type mockEntityReader struct {
 	searchAccountContactsFn func(ctx context.Context, accountID string, body []byte) ([]byte, error)
 	searchProjectContactsFn func(ctx context.Context, projectID string, body []byte) ([]byte, error)
 	searchProjectsFn        func(ctx context.Context, body []byte) ([]byte, error)
-	searchProjectsCalls     [][]byte
-	getProjectFn            func(ctx context.Context, id string) ([]byte, error)
-	getAccountFn            func(ctx context.Context, id string) ([]byte, error)
-	getAccountCalls         []string
+	// searchSuspendedFn answers Run's second, closureStatus "Suspended"
+	// pass, so searchProjectsFn only ever sees the "Open" pass. Both
+	// passes are recorded in searchProjectsCalls.
+	searchSuspendedFn   func(ctx context.Context, body []byte) ([]byte, error)
+	searchProjectsCalls [][]byte
+	getProjectFn        func(ctx context.Context, id string) ([]byte, error)
+	getProjectCalls     []string
+	getAccountFn        func(ctx context.Context, id string) ([]byte, error)
+	getAccountCalls     []string
 }
 
 func (m *mockEntityReader) GetAccount(ctx context.Context, id string) ([]byte, error) {
@@ -41,6 +48,7 @@ func (m *mockEntityReader) GetAccount(ctx context.Context, id string) ([]byte, e
 }
 
 func (m *mockEntityReader) GetProject(ctx context.Context, id string) ([]byte, error) {
+	m.getProjectCalls = append(m.getProjectCalls, id)
 	if m.getProjectFn != nil {
 		return m.getProjectFn(ctx, id)
 	}
@@ -49,6 +57,13 @@ func (m *mockEntityReader) GetProject(ctx context.Context, id string) ([]byte, e
 
 func (m *mockEntityReader) SearchProjects(ctx context.Context, body []byte) ([]byte, error) {
 	m.searchProjectsCalls = append(m.searchProjectsCalls, body)
+	var req searchProjectsRequest
+	if err := json.Unmarshal(body, &req); err == nil && req.ClosureStatus == "Suspended" {
+		if m.searchSuspendedFn != nil {
+			return m.searchSuspendedFn(ctx, body)
+		}
+		return []byte(`{"projects":[],"total":0,"limit":50,"offset":0,"hasMore":false}`), nil
+	}
 	if m.searchProjectsFn != nil {
 		return m.searchProjectsFn(ctx, body)
 	}
@@ -69,6 +84,19 @@ func (m *mockEntityReader) SearchProjectContacts(ctx context.Context, projectID
 	return []byte(`{"contacts":[]}`), nil
 }
 
+// recordingGate holds back every suspension it is asked about, recording
+// each request.
+type recordingGate struct {
+	requests []approvals.Request
+}
+
+func (g *recordingGate) Check(ctx context.Context, req approvals.Request) (*approvals.Approval, error) {
+	g.requests = append(g.requests, req)
+	return &approvals.Approval{State: approvals.StatePending}, nil
+}
+
+func (g *recordingGate) Executed(ctx context.Context, projectID string) error { return nil }
+
 type updateCall struct {
 	id   string
 	body []byte
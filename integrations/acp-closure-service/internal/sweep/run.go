@@ -21,7 +21,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/suspensionstate"
 )
 
 // pageSize is the page size used for /projects/search. entity-service's own
@@ -58,65 +62,231 @@ const pageSize = 50
 // bugs — a project excluded here produces zero log signal about whatever
 // might be wrong with it, which is the opposite of what you want for an
 // actual bug. nil is equivalent to an empty set (nothing excluded).
+//
+// The broad sweep makes a second pass, over "Suspended" projects, for
+// renewals: a project this component suspended is no longer "Open", so the
+// first pass never sees it again, and its reinstatement (see reinstate)
+// depends on this pass finding it. Only a project whose end date has moved
+// out past its recorded window is evaluated, and counted, in this pass — the
+// rest, suspended for any reason and still due to stay so, are passed over
+// without a trace, excluded ones included. The TEST_PROJECT_ID-scoped path
+// needs no second pass: GetProject fetches the project whatever its status.
 func Run(ctx context.Context, reader sweepReader, updater projectUpdater, ntf notifier, now time.Time, projectID string, excludedProjectIDs map[string]bool) (Result, error) {
-	var result Result
+	return RunWithOptions(ctx, reader, updater, ntf, now, projectID, excludedProjectIDs, Options{})
+}
+
+// Progress is told each project's outcome as soon as Run has it, evaluated
+// or excluded, in the order Result.Projects will list them.
+type Progress func(ProjectOutcome)
+
+// Options are RunWithOptions's optional settings; the zero value is Run.
+type Options struct {
+	// Concurrency is how many projects are processed at once. Below 2, one
+	// at a time, in the calling goroutine.
+	Concurrency int
+	// Progress, if non-nil, is called after each project. It backs the
+	// service mode's run status, which has to show a sweep's progress
+	// before the sweep returns.
+	Progress Progress
+	// Gate, if non-nil, holds due day-0 suspensions for approval (see
+	// processProject).
+	Gate suspensionGate
+}
+
+// RunWithOptions is Run with opts.
+//
+// With a Concurrency above 1, up to that many processProject calls run at
+// once, on their own goroutines, while pagination itself stays sequential:
+// the next page is fetched only once a worker is free for its first
+// project, so at most one page's worth of projects is ever in flight. What
+// concurrency must not change is the result: Result.Projects, Failures and
+// the Progress calls all keep the order projects were fetched in, however
+// the workers finish, so a report reads the same at any concurrency. Each
+// project is still processed by exactly one worker — notify before suspend
+// within a project is untouched. The entity client's rate limit, not this
+// pool, is what bounds the load on csm-integration-service.
+//
+// updater, ntf and reader are called from several goroutines at once when
+// Concurrency is above 1; *entity.Client and the dry-run implementations
+// are safe for that.
+func RunWithOptions(ctx context.Context, reader sweepReader, updater projectUpdater, ntf notifier, now time.Time, projectID string, excludedProjectIDs map[string]bool, opts Options) (Result, error) {
+	c := &collector{progress: opts.Progress}
+	pool := newWorkerPool(opts.Concurrency)
+	visit := func(proj project) {
+		slot := c.reserve()
+		pool.do(func() { c.fill(slot, evaluate(ctx, reader, updater, ntf, opts.Gate, now, proj)) })
+	}
+	exclude := func(id string) {
+		c.fill(c.reserve(), skipExcluded(ctx, id))
+	}
+
+	err := eachProject(ctx, reader, projectID, "Open", excludedProjectIDs, visit, exclude)
+	if err == nil && projectID == "" {
+		err = eachProject(ctx, reader, "", "Suspended", excludedProjectIDs,
+			func(proj project) {
+				if renewed(now, proj) {
+					visit(proj)
+				}
+			},
+			func(string) {},
+		)
+	}
+	pool.wait()
+	return c.result, err
+}
+
+// workerPool runs at most size funcs at once. do blocks while all are busy.
+// A size below 2 runs each func inline, in do itself.
+type workerPool struct {
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+func newWorkerPool(size int) *workerPool {
+	if size < 2 {
+		return &workerPool{}
+	}
+	return &workerPool{sem: make(chan struct{}, size)}
+}
+
+func (p *workerPool) do(fn func()) {
+	if p.sem == nil {
+		fn()
+		return
+	}
+	p.sem <- struct{}{}
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+		fn()
+	}()
+}
+
+func (p *workerPool) wait() {
+	p.wg.Wait()
+}
+
+// collector assembles Result from outcomes that may finish out of order:
+// each project reserves a slot in fetch order, and an outcome is counted
+// into result, and reported to progress, only once every slot before it
+// has been — so both see exactly the sequential order.
+type collector struct {
+	progress Progress
 
+	mu      sync.Mutex
+	pending []*ProjectOutcome
+	next    int // first slot not yet counted
+	result  Result
+}
+
+// reserve returns the next slot, in fetch order.
+func (c *collector) reserve() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, nil)
+	return len(c.pending) - 1
+}
+
+// fill records slot's outcome and counts every outcome it unblocks.
+func (c *collector) fill(slot int, out ProjectOutcome) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending[slot] = &out
+	for c.next < len(c.pending) && c.pending[c.next] != nil {
+		o := *c.pending[c.next]
+		c.pending[c.next] = nil
+		c.next++
+
+		if o.Excluded {
+			c.result.ProjectsExcluded++
+		} else {
+			c.result.ProjectsEvaluated++
+		}
+		if o.Err != nil {
+			c.result.Failures = append(c.result.Failures, ProjectFailure{ProjectID: o.ProjectID, Err: o.Err})
+		}
+		c.result.Projects = append(c.result.Projects, o)
+		if c.progress != nil {
+			c.progress(o)
+		}
+	}
+}
+
+// renewed reports whether proj's end date has moved out past its recorded
+// notice window — the Suspended pass's filter. An unparseable
+// suspensionProcessState reports false: a suspended project that is not
+// this component's to reinstate must not turn into a daily failure.
+func renewed(now time.Time, proj project) bool {
+	if proj.EndDate == nil {
+		return false
+	}
+	lastWindow, err := suspensionstate.LastNoticeWindow(proj.SuspensionProcessState)
+	if err != nil {
+		return false
+	}
+	return closure.Renewed(now, *proj.EndDate, lastWindow)
+}
+
+// eachProject fetches the projects a run covers — the one projectID, or
+// every project with closureStatus page by page — and calls visit for each,
+// or exclude for each one in excludedProjectIDs instead. It holds all of Run's
+// fetching and pagination rules (see Run's doc comment), so Forecast walks
+// exactly the projects a real run would. Its error is always fatal.
+func eachProject(ctx context.Context, reader sweepReader, projectID, closureStatus string, excludedProjectIDs map[string]bool, visit func(project), exclude func(id string)) error {
 	if projectID != "" {
-		if skipExcluded(ctx, projectID, excludedProjectIDs, &result) {
-			return result, nil
+		if excludedProjectIDs[projectID] {
+			exclude(projectID)
+			return nil
 		}
 
 		raw, err := reader.GetProject(ctx, projectID)
 		if err != nil {
-			return result, fmt.Errorf("sweep: get project %s: %w", projectID, err)
+			return fmt.Errorf("sweep: get project %s: %w", projectID, err)
 		}
 
 		var proj project
 		if err := json.Unmarshal(raw, &proj); err != nil {
-			return result, fmt.Errorf("sweep: parse project %s: %w", projectID, err)
-		}
-
-		result.ProjectsEvaluated++
-		if err := processProject(ctx, reader, updater, ntf, now, proj); err != nil {
-			slog.ErrorContext(ctx, "processProject failed", "projectID", proj.ID, "err", err)
-			result.Failures = append(result.Failures, ProjectFailure{ProjectID: proj.ID, Err: err})
+			return fmt.Errorf("sweep: parse project %s: %w", projectID, err)
 		}
+		proj.detailed = true
 
-		return result, nil
+		visit(proj)
+		return nil
 	}
 
-	offset := 0
+	offset, seen := 0, 0
 	for {
 		reqBody, err := json.Marshal(searchProjectsRequest{
 			Pagination:    pagination{Limit: pageSize, Offset: offset},
-			ClosureStatus: "Open",
+			ClosureStatus: closureStatus,
 			SortBy:        "endDate",
 			SortOrder:     "asc",
 		})
 		if err != nil {
-			return result, fmt.Errorf("sweep: build search request: %w", err)
+			return fmt.Errorf("sweep: build search request: %w", err)
 		}
 
 		raw, err := reader.SearchProjects(ctx, reqBody)
 		if err != nil {
-			return result, fmt.Errorf("sweep: search projects at offset %d: %w", offset, err)
+			return fmt.Errorf("sweep: search %s projects at offset %d: %w", closureStatus, offset, err)
 		}
 
 		var page searchProjectsResponse
 		if err := json.Unmarshal(raw, &page); err != nil {
-			return result, fmt.Errorf("sweep: parse search response at offset %d: %w", offset, err)
+			return fmt.Errorf("sweep: parse search response at offset %d: %w", offset, err)
 		}
 
 		for _, proj := range page.Projects {
-			if skipExcluded(ctx, proj.ID, excludedProjectIDs, &result) {
+			seen++
+			if excludedProjectIDs[proj.ID] {
+				exclude(proj.ID)
 				continue
 			}
 
-			result.ProjectsEvaluated++
-			if err := processProject(ctx, reader, updater, ntf, now, proj); err != nil {
-				slog.ErrorContext(ctx, "processProject failed", "projectID", proj.ID, "err", err)
-				result.Failures = append(result.Failures, ProjectFailure{ProjectID: proj.ID, Err: err})
-			}
+			visit(proj)
 		}
 
 		if len(page.Projects) == 0 {
@@ -127,27 +297,33 @@ func Run(ctx context.Context, reader sweepReader, updater projectUpdater, ntf no
 			break
 		}
 
-		if page.Total > 0 && result.ProjectsEvaluated+result.ProjectsExcluded >= page.Total {
+		if page.Total > 0 && seen >= page.Total {
 			slog.WarnContext(ctx, "sweep: pagination hit the Total bound while hasMore was still true",
-				"total", page.Total, "projectsEvaluated", result.ProjectsEvaluated, "projectsExcluded", result.ProjectsExcluded)
+				"total", page.Total, "projectsSeen", seen)
 			break
 		}
 
 		offset += pageSize
 	}
 
-	return result, nil
+	return nil
 }
 
-// skipExcluded reports whether id is in excludedProjectIDs, logging and
-// counting the skip in result if so. Shared by both of Run's paths (the
-// TEST_PROJECT_ID-scoped early check and the broad-sweep loop) so the
-// log line and counter update have exactly one definition.
-func skipExcluded(ctx context.Context, id string, excludedProjectIDs map[string]bool, result *Result) bool {
-	if !excludedProjectIDs[id] {
-		return false
-	}
+// skipExcluded logs an excluded project and returns its outcome, for both
+// of eachProject's paths (the TEST_PROJECT_ID-scoped early check and the
+// broad-sweep loop).
+func skipExcluded(ctx context.Context, id string) ProjectOutcome {
 	slog.InfoContext(ctx, "project excluded from evaluation", "projectID", id)
-	result.ProjectsExcluded++
-	return true
+	return ProjectOutcome{ProjectID: id, Excluded: true}
+}
+
+// evaluate runs processProject for proj and returns its outcome, logging a
+// failure, and recording it in the outcome, without stopping the sweep.
+func evaluate(ctx context.Context, reader entityReader, updater projectUpdater, ntf notifier, gate suspensionGate, now time.Time, proj project) ProjectOutcome {
+	outcome, err := processProject(ctx, reader, updater, ntf, gate, now, proj)
+	if err != nil {
+		slog.ErrorContext(ctx, "processProject failed", "projectID", proj.ID, "err", err)
+		outcome.Err = err
+	}
+	return outcome
 }
@@ -20,6 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -49,8 +52,9 @@ func TestRun_SinglePageEvaluatesAllProjects(t *testing.T) {
 	if len(result.Failures) != 0 {
 		t.Errorf("Failures = %d, want 0", len(result.Failures))
 	}
-	if len(reader.searchProjectsCalls) != 1 {
-		t.Errorf("SearchProjects calls = %d, want 1", len(reader.searchProjectsCalls))
+	// One page of Open projects, then the (empty) Suspended pass.
+	if len(reader.searchProjectsCalls) != 2 {
+		t.Errorf("SearchProjects calls = %d, want 2", len(reader.searchProjectsCalls))
 	}
 }
 
@@ -329,6 +333,9 @@ func TestRun_SkipsExcludedProjectsInBroadSweep(t *testing.T) {
 	if len(result.Failures) != 0 {
 		t.Errorf("Failures = %d, want 0", len(result.Failures))
 	}
+	if len(result.Projects) != 3 || result.Projects[1].ProjectID != "p2" || !result.Projects[1].Excluded || result.Projects[0].Excluded {
+		t.Errorf("Projects = %+v, want p1, p2 (excluded), p3 in order", result.Projects)
+	}
 }
 
 // TestRun_ScopedToProjectIDSkipsExcludedProjectWithoutFetching verifies the
@@ -360,3 +367,196 @@ func TestRun_ScopedToProjectIDSkipsExcludedProjectWithoutFetching(t *testing.T)
 		t.Errorf("ProjectsExcluded = %d, want 1", result.ProjectsExcluded)
 	}
 }
+
+// TestRunWithOptions_ReportsEachProjectInOrder verifies the service mode's
+// progress hook sees every project, excluded ones included, as Run reaches
+// it and in Result.Projects order.
+func TestRunWithOptions_ReportsEachProjectInOrder(t *testing.T) {
+	reader := &mockEntityReader{
+		searchProjectsFn: func(ctx context.Context, body []byte) ([]byte, error) {
+			return []byte(`{
+				"projects": [
+					{"id": "p1", "endDate": null},
+					{"id": "p2", "endDate": null},
+					{"id": "p3", "endDate": null}
+				],
+				"total": 3, "limit": 50, "offset": 0, "hasMore": false
+			}`), nil
+		},
+	}
+
+	var seen []string
+	result, err := RunWithOptions(context.Background(), reader, &mockProjectUpdater{}, &mockNotifier{}, time.Now(), "", map[string]bool{"p2": true},
+		Options{Progress: func(o ProjectOutcome) { seen = append(seen, o.ProjectID) }})
+	if err != nil {
+		t.Fatalf("RunWithOptions() error = %v, want nil", err)
+	}
+	if len(seen) != 3 || seen[0] != "p1" || seen[1] != "p2" || seen[2] != "p3" {
+		t.Errorf("progress saw %v, want [p1 p2 p3]", seen)
+	}
+	if len(result.Projects) != len(seen) {
+		t.Errorf("Projects = %d, progress calls = %d", len(result.Projects), len(seen))
+	}
+}
+
+// slowReader is a goroutine-safe sweepReader for the concurrency tests:
+// two pages of projects p0..p11, one account each, where GetAccount for a
+// later project returns sooner — so workers finish in roughly reverse
+// order — and GetAccount for a3 fails. It tracks the most GetAccount calls
+// ever in flight at once.
+type slowReader struct {
+	endDate string
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (r *slowReader) SearchProjects(ctx context.Context, body []byte) ([]byte, error) {
+	var req searchProjectsRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if req.ClosureStatus != "Open" {
+		return []byte(`{"projects":[],"hasMore":false}`), nil
+	}
+	first := req.Pagination.Offset / pageSize * 6
+	var projects []string
+	for i := first; i < first+6; i++ {
+		projects = append(projects, fmt.Sprintf(`{"id":"p%d","account":{"id":"a%d"},"endDate":%q}`, i, i, r.endDate))
+	}
+	return []byte(fmt.Sprintf(`{"projects":[%s],"total":12,"hasMore":%t}`, strings.Join(projects, ","), first == 0)), nil
+}
+
+func (r *slowReader) GetProject(ctx context.Context, id string) ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+func (r *slowReader) GetAccount(ctx context.Context, id string) ([]byte, error) {
+	r.mu.Lock()
+	r.inFlight++
+	r.maxInFlight = max(r.maxInFlight, r.inFlight)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.inFlight--
+		r.mu.Unlock()
+	}()
+
+	var n int
+	fmt.Sscanf(id, "a%d", &n)
+	time.Sleep(time.Duration(12-n) * 2 * time.Millisecond)
+	if id == "a3" {
+		return nil, errors.New("boom")
+	}
+	return []byte(`{}`), nil
+}
+
+func (r *slowReader) SearchAccountContacts(ctx context.Context, accountID string, body []byte) ([]byte, error) {
+	return []byte(`{"contacts":[]}`), nil
+}
+
+func (r *slowReader) SearchProjectContacts(ctx context.Context, projectID string, body []byte) ([]byte, error) {
+	return []byte(`{"contacts":[]}`), nil
+}
+
+// lockedUpdater is a goroutine-safe projectUpdater recording who it wrote.
+type lockedUpdater struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+func (u *lockedUpdater) UpdateProject(ctx context.Context, id string, body []byte) ([]byte, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.ids[id] = true
+	return []byte(`{}`), nil
+}
+
+// TestRunWithOptions_ConcurrentResultMatchesSequential verifies a
+// concurrent sweep's Result — projects, failures, counts and progress
+// order — is exactly the sequential one, across both pages, while really
+// running projects at once and never more than Concurrency of them.
+func TestRunWithOptions_ConcurrentResultMatchesSequential(t *testing.T) {
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(0, 0, 89).Format(time.RFC3339) // fires the internal-only 90-day window
+
+	run := func(concurrency int) (Result, []string, *slowReader, *lockedUpdater) {
+		reader := &slowReader{endDate: endDate}
+		updater := &lockedUpdater{ids: map[string]bool{}}
+		var seen []string
+		result, err := RunWithOptions(context.Background(), reader, updater, discardNotifier{}, now, "", nil, Options{
+			Concurrency: concurrency,
+			Progress:    func(o ProjectOutcome) { seen = append(seen, o.ProjectID) },
+		})
+		if err != nil {
+			t.Fatalf("RunWithOptions(concurrency %d) error = %v", concurrency, err)
+		}
+		return result, seen, reader, updater
+	}
+
+	want, wantSeen, _, _ := run(1)
+	got, gotSeen, reader, updater := run(4)
+
+	if got.ProjectsEvaluated != 12 || got.ProjectsEvaluated != want.ProjectsEvaluated {
+		t.Errorf("ProjectsEvaluated = %d, want 12", got.ProjectsEvaluated)
+	}
+	if len(got.Failures) != 1 || got.Failures[0].ProjectID != "p3" {
+		t.Errorf("Failures = %+v, want p3 only", got.Failures)
+	}
+	if strings.Join(gotSeen, ",") != strings.Join(wantSeen, ",") {
+		t.Errorf("progress order = %v, want %v", gotSeen, wantSeen)
+	}
+	for i, o := range got.Projects {
+		if o.ProjectID != want.Projects[i].ProjectID || (o.Err == nil) != (want.Projects[i].Err == nil) {
+			t.Errorf("Projects[%d] = %s (err %v), want %s", i, o.ProjectID, o.Err, want.Projects[i].ProjectID)
+		}
+	}
+	if reader.maxInFlight < 2 || reader.maxInFlight > 4 {
+		t.Errorf("max GetAccount calls in flight = %d, want 2..4", reader.maxInFlight)
+	}
+	if len(updater.ids) != 11 || updater.ids["p3"] {
+		t.Errorf("updated %d projects (p3: %v), want the 11 that did not fail", len(updater.ids), updater.ids["p3"])
+	}
+}
+
+// TestRun_SuspendedPassEvaluatesOnlyRenewedProjects verifies the broad
+// sweep's second pass over Suspended projects: a renewed one is reinstated
+// and counted, one still past its end date is passed over without a trace,
+// and an excluded one is skipped even though it was renewed.
+func TestRun_SuspendedPassEvaluatesOnlyRenewedProjects(t *testing.T) {
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	renewed := now.AddDate(1, 0, 0).Format(time.RFC3339)
+	lapsed := now.AddDate(0, 0, -10).Format(time.RFC3339)
+	state := `{"based_on_subscription_end_date":{"event_type":"suspend"}}`
+	reader := &mockEntityReader{
+		searchSuspendedFn: func(ctx context.Context, body []byte) ([]byte, error) {
+			return []byte(`{
+				"projects": [
+					{"id": "renewed", "endDate": "` + renewed + `", "endDateClosureState": "Suspended", "suspensionProcessState": ` + state + `},
+					{"id": "lapsed", "endDate": "` + lapsed + `", "endDateClosureState": "Suspended", "suspensionProcessState": ` + state + `},
+					{"id": "excluded", "endDate": "` + renewed + `", "endDateClosureState": "Suspended", "suspensionProcessState": ` + state + `}
+				],
+				"total": 3, "limit": 50, "offset": 0, "hasMore": false
+			}`), nil
+		},
+	}
+	updater := &mockProjectUpdater{}
+	ntf := &mockNotifier{}
+
+	result, err := Run(context.Background(), reader, updater, ntf, now, "", map[string]bool{"excluded": true})
+	if err != nil {
+		t.Fatalf("Run() error = %v, want nil", err)
+	}
+	if result.ProjectsEvaluated != 1 || result.ProjectsExcluded != 0 || len(result.Projects) != 1 {
+		t.Fatalf("result = %+v, want only the renewed project", result)
+	}
+	if out := result.Projects[0]; out.ProjectID != "renewed" || !out.Reinstated || !out.Reset {
+		t.Errorf("outcome = %+v, want renewed reinstated", out)
+	}
+	for _, c := range updater.calls {
+		if c.id != "renewed" {
+			t.Errorf("UpdateProject(%s), want writes for the renewed project only", c.id)
+		}
+	}
+}
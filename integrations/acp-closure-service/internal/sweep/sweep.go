@@ -27,223 +27,258 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/recipients"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/suspensionstate"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/templates"
 )
 
 // processProject evaluates and, if anything is due, acts on a single
-// project. Notify happens before suspend is ever attempted, and an error
-// from notify returns immediately — this ordering, not a separate flag, is
-// what guarantees suspend never proceeds after a failed notify (the day-0
-// "email first, stop on failure" contract).
-func processProject(ctx context.Context, reader entityReader, updater projectUpdater, ntf notifier, now time.Time, proj project) error {
+// project, returning what it decided and did — also on error, as far as it
+// got. A project whose end date has moved out past its recorded window is
+// reinstated instead of decided on (see reinstate). Notify happens before suspend is ever attempted, and an error from
+// notify returns immediately — this ordering, not a separate flag, is what
+// guarantees suspend never proceeds after a failed notify (the day-0 "email
+// first, stop on failure" contract).
+//
+// With a gate (nil for none), a due day-0 suspension of a project still
+// Open is first checked against it, and unless approved, the whole day-0
+// action — notice included, so the customer isn't told of a suspension that
+// may never happen — is held back until a run after the decision.
+func processProject(ctx context.Context, reader entityReader, updater projectUpdater, ntf notifier, gate suspensionGate, now time.Time, proj project) (ProjectOutcome, error) {
+	out := ProjectOutcome{ProjectID: proj.ID, ProjectName: proj.Name, ProjectKey: proj.ProjectKey, EndDate: proj.EndDate}
 	if proj.EndDate == nil {
-		return nil
+		return out, nil
 	}
 
 	lastWindow, err := suspensionstate.LastNoticeWindow(proj.SuspensionProcessState)
 	if err != nil {
-		return fmt.Errorf("sweep: parse suspensionProcessState for project %s: %w", proj.ID, err)
+		return out, fmt.Errorf("sweep: parse suspensionProcessState for project %s: %w", proj.ID, err)
+	}
+	out.LastWindowBefore, out.LastWindowAfter = lastWindow, lastWindow
+
+	renewal := closure.Renewed(now, *proj.EndDate, lastWindow)
+	var decision closure.Decision
+	if !renewal {
+		decision = closure.Decide(now, *proj.EndDate, lastWindow)
+		out.Decision = &decision
+		if !decision.Fires {
+			return out, nil
+		}
+	}
+
+	proj, err = withAccountDetails(ctx, reader, proj)
+	if err != nil {
+		return out, fmt.Errorf("sweep: look up account for project %s: %w", proj.ID, err)
 	}
 
-	decision := closure.Decide(now, *proj.EndDate, lastWindow)
-	if !decision.Fires {
-		return nil
+	if renewal {
+		if err := reinstate(ctx, reader, updater, ntf, proj, &out); err != nil {
+			return out, fmt.Errorf("sweep: reinstate project %s: %w", proj.ID, err)
+		}
+		return out, nil
+	}
+
+	if decision.ShouldSuspend && gate != nil && suspendable(proj) {
+		approval, err := gate.Check(ctx, suspensionRequest(proj))
+		if err != nil {
+			return out, fmt.Errorf("sweep: check suspension approval for project %s: %w", proj.ID, err)
+		}
+		if approval != nil {
+			out.Approval, out.ApprovalReason = approval.State, approval.Reason
+			if approval.State != approvals.StateApproved {
+				return out, nil
+			}
+		}
 	}
 
 	if decision.ShouldNotify {
-		if err := notifyForWindow(ctx, reader, ntf, proj, decision.Window); err != nil {
-			return fmt.Errorf("sweep: notify project %s: %w", proj.ID, err)
+		notices, err := notifyForWindow(ctx, reader, ntf, proj, decision.Window)
+		out.Notices = notices
+		if err != nil {
+			return out, fmt.Errorf("sweep: notify project %s: %w", proj.ID, err)
 		}
 		if err := recordNoticeSent(ctx, updater, proj, decision.Window, ntf.Delivers()); err != nil {
-			return fmt.Errorf("sweep: record notice for project %s: %w", proj.ID, err)
+			return out, fmt.Errorf("sweep: record notice for project %s: %w", proj.ID, err)
 		}
+		window := decision.Window
+		out.LastWindowAfter = &window
 	}
 
 	if decision.ShouldSuspend {
-		if err := suspend(ctx, updater, proj); err != nil {
-			return fmt.Errorf("sweep: suspend project %s: %w", proj.ID, err)
+		suspended, err := suspend(ctx, updater, proj)
+		if err != nil {
+			return out, fmt.Errorf("sweep: suspend project %s: %w", proj.ID, err)
+		}
+		out.Suspended = suspended
+		if suspended && out.Approval == approvals.StateApproved {
+			if err := gate.Executed(ctx, proj.ID); err != nil {
+				return out, fmt.Errorf("sweep: clear executed approval for project %s: %w", proj.ID, err)
+			}
 		}
 	}
 
-	return nil
+	return out, nil
 }
 
-// needsCustomerAudience reports whether window's confirmed audience matrix
-// includes the customer, not just the Account Manager. 90/60/30 are
-// internal-only; 15/7/0 are both.
-func needsCustomerAudience(window closure.NoticeWindow) bool {
-	switch window {
-	case closure.NoticeWindow15, closure.NoticeWindow7, closure.NoticeWindow0:
-		return true
-	default:
-		return false
+// withAccountDetails returns proj with its account's region and tier filled
+// in from GetProject. A SearchProjects item's account carries only id and
+// name, so without this every searched project's notices would render in
+// the default locale and its suspension would reach the gate with no tier.
+// It is called only once something is due, so a quiet project costs no
+// extra read; a project already read via GetProject, or with no account,
+// is returned as is.
+func withAccountDetails(ctx context.Context, reader entityReader, proj project) (project, error) {
+	if proj.detailed || proj.Account == nil {
+		return proj, nil
+	}
+	raw, err := reader.GetProject(ctx, proj.ID)
+	if err != nil {
+		return proj, err
+	}
+	var full project
+	if err := json.Unmarshal(raw, &full); err != nil {
+		return proj, fmt.Errorf("parse project: %w", err)
 	}
+	if full.Account != nil {
+		account := *proj.Account
+		account.Region, account.Tier = full.Account.Region, full.Account.Tier
+		proj.Account = &account
+	}
+	proj.detailed = true
+	return proj, nil
 }
 
-// projectOfAccount builds the "{ProjectName} of {AccountName}" clause
-// shared by both internal subject variants, omitting the " of {AccountName}"
-// half entirely when there's no linked account — a project with
-// proj.Account == nil previously produced a dangling "...of " with a
-// trailing space and nothing after it in a real outbound email subject (PR
-// #1440 review, Sajith Ekanayake).
-func projectOfAccount(projectName, accountName string) string {
-	if accountName == "" {
-		return projectName
-	}
-	return fmt.Sprintf("%s of %s", projectName, accountName)
+// suspensionRequest is what a suspensionGate is asked about proj.
+func suspensionRequest(proj project) approvals.Request {
+	return approvals.Request{
+		ProjectID:   proj.ID,
+		ProjectName: proj.Name,
+		ProjectKey:  proj.ProjectKey,
+		AccountName: accountName(proj),
+		Tier:        proj.tier(),
+		EndDate:     *proj.EndDate,
+	}
 }
 
-// internalNoticeSubject builds the always-sent internal (Account Owner/
-// Renewal Manager/Technical Owner) notice's title line. Every internal copy
-// is [ACP]-prefixed regardless of window — that prefix marks "this is the
-// internal-audience copy," not "this is a 90/60/30 window" (confirmed
-// against real examples from Chamara spanning 90 through 0; an earlier
-// version of this function had the rule backwards). Day-0 gets distinct
-// "suspension notice" wording instead of "N Days Reminder" — there's no
-// "days remaining" left to report once a project is actually suspended.
-func internalNoticeSubject(window closure.NoticeWindow, projectName, accountName string) string {
-	target := projectOfAccount(projectName, accountName)
-	if window.IsTerminal() {
-		return fmt.Sprintf("[ACP] Project Suspension Notice of %s", target)
-	}
-	return fmt.Sprintf("[ACP] %d Days Reminder of Project for %s", int(window), target)
-}
+// reinstate handles a renewal: the end date has moved out past the window
+// recorded for proj, so that record is stale — left alone, Decide would keep
+// reading it as every later window already covered, and a suspended project
+// would stay suspended for good. For a project this component suspended
+// (endDateClosureState "Suspended"), it sends the reinstatement notice and
+// reverts endDateClosureState to Open; for every renewed project it then
+// resets based_on_subscription_end_date to "open". Nothing else is decided
+// this run: the next run starts the notice cascade over against the new end
+// date, exactly as for a project that was never notified.
+//
+// The order mirrors day 0's "email first, stop on failure", and the reset
+// goes last because the stale record is what makes a retry happen at all: a
+// failed notice changes nothing, so the next run tries again in full; a
+// failed revert leaves the record stale too, so the next run resends the
+// notice and retries it (a duplicate notice, never a project left
+// suspended); and a failed reset after a successful revert is retried next
+// run without the notice, since endDateClosureState is Open by then.
+//
+// An endDateClosureState past Suspended ("Closed", set outside this
+// component) is not reverted — a closed project is not this component's to
+// reopen — but its stale record is still reset.
+func reinstate(ctx context.Context, reader entityReader, updater projectUpdater, ntf notifier, proj project, out *ProjectOutcome) error {
+	if proj.EndDateClosureState != nil && *proj.EndDateClosureState == "Suspended" {
+		notices, err := notifyReinstatement(ctx, reader, ntf, proj)
+		out.Notices = notices
+		if err != nil {
+			return err
+		}
+		if err := setEndDateClosureState(ctx, updater, proj, "Open"); err != nil {
+			return fmt.Errorf("revert endDateClosureState: %w", err)
+		}
+		out.Reinstated = true
+	}
 
-// customerNoticeSubject builds the customer-facing notice's title line
-// (15/7/0 only) — never [ACP]-prefixed, never names the account, and uses
-// future tense ("Upcoming") before day-0 versus past tense once actually
-// suspended.
-func customerNoticeSubject(window closure.NoticeWindow, projectName string) string {
-	if window.IsTerminal() {
-		return fmt.Sprintf("Project Suspension Notice - %s", projectName)
+	newState, err := suspensionstate.WithSubscriptionEndDateOpen(proj.SuspensionProcessState)
+	if err != nil {
+		return fmt.Errorf("build suspensionProcessState: %w", err)
+	}
+	if err := updateSuspensionProcessState(ctx, updater, proj, newState); err != nil {
+		return fmt.Errorf("reset suspensionProcessState: %w", err)
 	}
-	return fmt.Sprintf("Upcoming Project Suspension Notice - %s", projectName)
+	out.Reset = true
+	out.LastWindowAfter = nil
+	return nil
 }
 
-// internalReminderBodyTemplate is the day-count (90/60/30/15/7) internal
-// notice body, confirmed verbatim from real examples Chamara shared. The
-// greeting always names the Account Manager (%[1]s), regardless of which of
-// the three internal recipients is actually reading their copy — not
-// personalized per recipient. Dates are formatted 2006-01-02.
-const internalReminderBodyTemplate = `Dear %[1]s
-
-The following project has a non renewed contract. Please find the details below.
-
-Project Name: %[2]s
-
-Project Key: %[3]s
-
-Account Owner: %[1]s
-
-Start Date: %[4]s
-
-End Date: %[5]s
-
-Since projects needs contract renewal, kindly take the remedial actions to avoid any disruptions of subscription support. We appreciate your understanding and your prompt attention to this matter.
-
-Best Regards,
-WSO2 Team`
-
-// internalSuspensionBodyTemplate is the day-0 internal notice body — past
-// tense ("has been suspended"), asking for reinstatement rather than
-// renewal.
-const internalSuspensionBodyTemplate = `Dear %[1]s
-
-The following project has been suspended due to non renewed contract. Kindly request that you take the appropriate action to reinitiate the suspended support account.
-
-Project Name: %[2]s
-
-Project Key: %[3]s
-
-Account Owner: %[1]s
-
-Start Date: %[4]s
-
-End Date: %[5]s
-
-Since the project is suspended, kindly take the remedial actions to reinstate the subscription support. We appreciate your prompt attention to this matter.
+// notifyReinstatement sends the reinstatement notice: one notice to the
+// Account Owner, Renewal Manager and Technical Owner, with the customer
+// contact added when the three-tier fallback resolves one. Unlike a 15/7/0
+// notice there is no no-business-contact variant — the news is good, and
+// the internal recipients get it either way.
+func notifyReinstatement(ctx context.Context, reader entityReader, ntf notifier, proj project) ([]NoticeOutcome, error) {
+	contacts, err := resolveAccountContacts(ctx, reader, proj.accountID())
+	if err != nil {
+		return nil, fmt.Errorf("resolve account contacts: %w", err)
+	}
+	projectContacts, accountContactsList, err := fetchContacts(ctx, reader, proj)
+	if err != nil {
+		return nil, err
+	}
+	resolution := recipients.ResolveCustomerContact(projectContacts, accountContactsList)
 
-Best Regards,
-WSO2 Team`
+	n := baseNotice(proj, closure.NoticeWindow0)
+	n.Recipients = notify.Recipients{
+		AccountOwner:   contacts.AccountOwner,
+		RenewalManager: contacts.RenewalManager,
+		TechnicalOwner: contacts.TechnicalOwner,
+		Customer:       resolution.CustomerContact,
+	}
+	n.ResolvedVia = resolution.ResolvedVia
+	if err := renderNotice(templates.KindReinstatement, &n, proj); err != nil {
+		return nil, err
+	}
 
-func internalNoticeBody(window closure.NoticeWindow, proj project, accountOwnerName string) string {
-	template := internalReminderBodyTemplate
-	if window.IsTerminal() {
-		template = internalSuspensionBodyTemplate
+	err = ntf.Send(ctx, n)
+	sent := []NoticeOutcome{{Notice: n, Err: err}}
+	if err != nil {
+		return sent, fmt.Errorf("send reinstatement notice: %w", err)
 	}
-	return fmt.Sprintf(template, accountOwnerName, proj.Name, proj.ProjectKey, formatDate(proj.StartDate), formatDate(proj.EndDate))
+	return sent, nil
 }
 
-// customerUpcomingSuspensionBodyTemplate is the 15/7-day customer-facing
-// body — future tense, no greeting (customer-facing notices never open with
-// "Dear X", confirmed explicitly). Dates are formatted 01/02/2006 (US
-// style), distinct from the internal bodies' 2006-01-02 — matches Chamara's
-// real examples exactly.
-const customerUpcomingSuspensionBodyTemplate = `We regret to inform you that the project %[1]s will be suspended on %[2]s due to non-renewal of the contracts upon the end of the previous subscription period.
-
-Please ensure to take necessary actions on or before %[2]s to avoid service disruption. If you have any questions or need assistance, please contact your Account Manager or the WSO2 Customer Success Team.
-
-Best Regards,
-WSO2 Team`
-
-// customerAlreadySuspendedBodyTemplate is the day-0 customer-facing body —
-// past tense, once the project has actually been suspended.
-const customerAlreadySuspendedBodyTemplate = `We trust this message finds you well. This project %[1]s was suspended %[2]s due to non-renewal of the contracts upon the end of the previous subscription period.
-
-To avoid future suspensions, please ensure that all necessary actions are completed in a timely manner. If you have any questions or need assistance, please contact your Account Manager or the WSO2 Customer Success Team.
-
-Best Regards,
-WSO2 Team`
-
-func customerNoticeBody(window closure.NoticeWindow, proj project) string {
-	template := customerUpcomingSuspensionBodyTemplate
-	if window.IsTerminal() {
-		template = customerAlreadySuspendedBodyTemplate
+// needsCustomerAudience reports whether window's confirmed audience matrix
+// includes the customer, not just the Account Manager. 90/60/30 are
+// internal-only; 15/7/0 are both.
+func needsCustomerAudience(window closure.NoticeWindow) bool {
+	switch window {
+	case closure.NoticeWindow15, closure.NoticeWindow7, closure.NoticeWindow0:
+		return true
+	default:
+		return false
 	}
-	return fmt.Sprintf(template, proj.Name, formatDateUS(proj.EndDate))
 }
 
-// noBusinessContactBodyTemplate is the fixed body for the urgent
-// no-business-contact notice, confirmed verbatim from a real existing
-// notice Chamara shared. Dates are formatted 2006-01-02; Account Owner is
-// the resolved Account Manager's *name*, not email.
-const noBusinessContactBodyTemplate = `Internal - Customer Project without Business Contacts
+// noticeTemplates renders every notice's subject and body. It is the
+// embedded default set unless UseNoticeTemplates replaced it at startup —
+// package state rather than another Run parameter because, like the
+// wording it holds, it is fixed for the life of the process.
+var noticeTemplates = templates.MustDefault()
 
-Urgent reminder regarding the project %[1]s.
-
-Please note that no Business Contacts was found for the project %[1]s. Immediate action is required to address this issue, as without a business contact, the customers will not receive essential notifications regarding their project suspension status. Additionally, this will lead to failures in further automated actions related to project suspension.
-
-Please refer this document to Update Business Contact
-
-Project Name: %[1]s
-Project Key: %[2]s
-Account Owner: %[3]s
-Start Date: %[4]s
-End Date: %[5]s`
-
-func noBusinessContactBody(proj project, accountOwnerName string) string {
-	return fmt.Sprintf(noBusinessContactBodyTemplate,
-		proj.Name, proj.ProjectKey, accountOwnerName, formatDate(proj.StartDate), formatDate(proj.EndDate))
-}
-
-func formatDate(t *time.Time) string {
-	if t == nil {
-		return ""
-	}
-	return t.Format("2006-01-02")
+// UseNoticeTemplates makes every later sweep render notices with s. Call it
+// once at startup, before any sweep runs.
+func UseNoticeTemplates(s *templates.Set) {
+	noticeTemplates = s
 }
 
-// formatDateUS formats a date 01/02/2006 (US style), for the customer-facing
-// prose bodies specifically — distinct from formatDate's 2006-01-02, used
-// everywhere else (internal bodies' "Start Date:"/"End Date:" fields).
-func formatDateUS(t *time.Time) string {
-	if t == nil {
-		return ""
+// renderNotice fills n's Subject and Body from the kind template for its
+// window, in the locale of proj's account region. n's other fields,
+// Recipients included, must already be set: the templates read them.
+func renderNotice(kind templates.Kind, n *notify.Notice, proj project) error {
+	subject, body, err := noticeTemplates.Render(kind, proj.region(), templates.Data{Notice: *n, AccountName: accountName(proj)})
+	if err != nil {
+		return fmt.Errorf("render %s notice: %w", kind, err)
 	}
-	return t.Format("01/02/2006")
+	n.Subject, n.Body = subject, body
+	return nil
 }
 
 func timeValue(t *time.Time) time.Time {
@@ -283,10 +318,20 @@ func accountName(proj project) string {
 // one — that's the tradeoff for keeping "skip contact-fetch entirely for
 // internal-only windows" (needsCustomerAudience) and "never send before
 // contacts resolve" both true at once; both sites wrap the error identically.
-func notifyForWindow(ctx context.Context, reader entityReader, ntf notifier, proj project, window closure.NoticeWindow) error {
+//
+// It returns every notice it attempted, with each Send's error, for the
+// run report.
+func notifyForWindow(ctx context.Context, reader entityReader, ntf notifier, proj project, window closure.NoticeWindow) ([]NoticeOutcome, error) {
+	var sent []NoticeOutcome
+	send := func(n notify.Notice) error {
+		err := ntf.Send(ctx, n)
+		sent = append(sent, NoticeOutcome{Notice: n, Err: err})
+		return err
+	}
+
 	contacts, err := resolveAccountContacts(ctx, reader, proj.accountID())
 	if err != nil {
-		return fmt.Errorf("resolve account contacts: %w", err)
+		return nil, fmt.Errorf("resolve account contacts: %w", err)
 	}
 
 	internalRecipients := notify.Recipients{
@@ -296,48 +341,52 @@ func notifyForWindow(ctx context.Context, reader entityReader, ntf notifier, pro
 	}
 
 	internalNotice := baseNotice(proj, window)
-	internalNotice.Subject = internalNoticeSubject(window, proj.Name, accountName(proj))
-	internalNotice.Body = internalNoticeBody(window, proj, contacts.AccountOwner.Name)
 	internalNotice.Recipients = internalRecipients
+	if err := renderNotice(templates.KindInternal, &internalNotice, proj); err != nil {
+		return nil, err
+	}
 
 	if !needsCustomerAudience(window) {
-		if err := ntf.Send(ctx, internalNotice); err != nil {
-			return fmt.Errorf("send internal notice: %w", err)
+		if err := send(internalNotice); err != nil {
+			return sent, fmt.Errorf("send internal notice: %w", err)
 		}
-		return nil
+		return sent, nil
 	}
 
 	projectContacts, accountContactsList, err := fetchContacts(ctx, reader, proj)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	resolution := recipients.ResolveCustomerContact(projectContacts, accountContactsList)
 
-	if err := ntf.Send(ctx, internalNotice); err != nil {
-		return fmt.Errorf("send internal notice: %w", err)
+	if err := send(internalNotice); err != nil {
+		return sent, fmt.Errorf("send internal notice: %w", err)
 	}
 
 	if !resolution.NeedsAMNudge {
 		customerNotice := baseNotice(proj, window)
-		customerNotice.Subject = customerNoticeSubject(window, proj.Name)
-		customerNotice.Body = customerNoticeBody(window, proj)
 		customerNotice.Recipients = internalRecipients
 		customerNotice.Recipients.Customer = resolution.CustomerContact
 		customerNotice.ResolvedVia = resolution.ResolvedVia
-		return ntf.Send(ctx, customerNotice)
+		if err := renderNotice(templates.KindCustomer, &customerNotice, proj); err != nil {
+			return sent, err
+		}
+		return sent, send(customerNotice)
 	}
 
 	nudgeNotice := baseNotice(proj, window)
-	nudgeNotice.Subject = fmt.Sprintf("[Urgent] [ACP] No Business Contacts Specified for Project %s", proj.Name)
-	nudgeNotice.Body = noBusinessContactBody(proj, contacts.AccountOwner.Name)
 	nudgeNotice.Recipients = internalRecipients
 	nudgeNotice.ResolvedVia = resolution.ResolvedVia
-	return ntf.Send(ctx, nudgeNotice)
+	if err := renderNotice(templates.KindNoBusinessContact, &nudgeNotice, proj); err != nil {
+		return sent, err
+	}
+	return sent, send(nudgeNotice)
 }
 
 // baseNotice builds the project-identity fields shared by every Notice sent
-// for a project/window — Subject, Body, Recipients, and ResolvedVia are left
-// at their zero value for the caller to fill in per notice type.
+// for a project/window — Recipients and ResolvedVia are left at their zero
+// value for the caller to fill in per notice type, and Subject and Body for
+// renderNotice after that.
 func baseNotice(proj project, window closure.NoticeWindow) notify.Notice {
 	return notify.Notice{
 		ProjectID:   proj.ID,
@@ -449,7 +498,12 @@ func recordNoticeSent(ctx context.Context, updater projectUpdater, proj project,
 	if err != nil {
 		return fmt.Errorf("build suspensionProcessState: %w", err)
 	}
+	return updateSuspensionProcessState(ctx, updater, proj, newState)
+}
 
+// updateSuspensionProcessState writes newState as proj's whole
+// suspensionProcessState.
+func updateSuspensionProcessState(ctx context.Context, updater projectUpdater, proj project, newState json.RawMessage) error {
 	body, err := json.Marshal(map[string]json.RawMessage{"suspensionProcessState": newState})
 	if err != nil {
 		return fmt.Errorf("marshal update request: %w", err)
@@ -459,13 +513,13 @@ func recordNoticeSent(ctx context.Context, updater projectUpdater, proj project,
 	return err
 }
 
-// suspend writes endDateClosureState=Suspended, unless this dimension has
-// already moved past its initial "Open" state (checked against the
-// already-fetched endDateClosureState — no extra round-trip), mirroring
-// legacy's checkForOpenProject guard. Guarding on "not Open" rather than "==
-// Suspended" matters because endDateClosureState can progress further to
-// "Closed" via a process outside this component (confirmed via a real
-// suspended project, Postman, project
+// suspend writes endDateClosureState=Suspended and reports that it did,
+// unless this dimension has already moved past its initial "Open" state
+// (checked against the already-fetched endDateClosureState — no extra
+// round-trip), mirroring legacy's checkForOpenProject guard. Guarding on
+// "not Open" rather than "== Suspended" matters because endDateClosureState
+// can progress further to "Closed" via a process outside this component
+// (confirmed via a real suspended project, Postman, project
 // acac149b-eba1-4714-fcf5-f5dabad0cdb1) — an equality check against
 // "Suspended" alone would miss that real case and re-suspend indefinitely.
 //
@@ -478,12 +532,27 @@ func recordNoticeSent(ctx context.Context, updater projectUpdater, proj project,
 // since notifyForWindow is never called on that path either. Confirmed
 // acceptable — do not add logging back here without re-confirming that
 // decision has changed.
-func suspend(ctx context.Context, updater projectUpdater, proj project) error {
-	if proj.EndDateClosureState != nil && *proj.EndDateClosureState != "Open" {
-		return nil
+func suspend(ctx context.Context, updater projectUpdater, proj project) (bool, error) {
+	if !suspendable(proj) {
+		return false, nil
+	}
+
+	if err := setEndDateClosureState(ctx, updater, proj, "Suspended"); err != nil {
+		return false, err
 	}
+	return true, nil
+}
+
+// suspendable reports whether proj's endDateClosureState is still at (or,
+// unset, treated as) its initial "Open" — suspend's guard, see there.
+func suspendable(proj project) bool {
+	return proj.EndDateClosureState == nil || *proj.EndDateClosureState == "Open"
+}
 
-	body, err := json.Marshal(map[string]string{"endDateClosureState": "Suspended"})
+// setEndDateClosureState writes proj's endDateClosureState: "Suspended" from
+// suspend, "Open" back from reinstate.
+func setEndDateClosureState(ctx context.Context, updater projectUpdater, proj project, state string) error {
+	body, err := json.Marshal(map[string]string{"endDateClosureState": state})
 	if err != nil {
 		return fmt.Errorf("marshal update request: %w", err)
 	}
@@ -20,10 +20,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/recipients"
@@ -36,7 +38,7 @@ func TestProcessProject_NoEndDateIsNoOp(t *testing.T) {
 
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: nil}
 
-	err := processProject(context.Background(), reader, updater, ntf, time.Now(), proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, time.Now(), proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -71,7 +73,7 @@ func TestProcessProject_InternalOnlyWindowSkipsCustomerContactLookup(t *testing.
 	endDate := now.AddDate(0, 0, 89) // fires the 90-day window
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -115,7 +117,7 @@ func TestProcessProject_RecordsIgnoredWhenNotifierDoesNotDeliver(t *testing.T) {
 	endDate := now.AddDate(0, 0, 89) // fires the 90-day window
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -157,7 +159,7 @@ func TestProcessProject_CustomerAudienceWindowNotifiesBusinessContact(t *testing
 	endDate := now.AddDate(0, 0, 6) // fires the 7-day window
 	proj := project{ID: "p1", Name: "Acme - Subscription", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -173,7 +175,8 @@ func TestProcessProject_CustomerAudienceWindowNotifiesBusinessContact(t *testing
 	}
 	// The fixture project has an account with no Name set, so the subject
 	// correctly omits the " of {AccountName}" clause entirely (regression
-	// coverage for the dangling "of " bug lives in TestInternalNoticeSubject).
+	// coverage for the dangling "of " bug lives in TestInternalNoticeSubject in
+	// internal/templates/templates_test.go).
 	const wantInternalSubject = "[ACP] 7 Days Reminder of Project for Acme - Subscription"
 	if internal.Subject != wantInternalSubject {
 		t.Errorf("internal Subject = %q, want %q", internal.Subject, wantInternalSubject)
@@ -232,7 +235,7 @@ func TestProcessProject_CustomerAudienceWindowSendsNoBusinessContactNoticeWhenNo
 		EndDate:    &endDate,
 	}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -302,7 +305,7 @@ func TestProcessProject_CustomerAudienceWindowSkipsAccountContactLookupWhenNoAcc
 	endDate := now.AddDate(0, 0, 6) // fires the 7-day window
 	proj := project{ID: "p1", Account: nil, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -336,7 +339,7 @@ func TestProcessProject_CustomerAudienceWindowFetchContactsFailureSendsNothing(t
 	endDate := now.AddDate(0, 0, 6) // fires the 7-day window (customer-audience)
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err == nil {
 		t.Fatal("processProject() error = nil, want non-nil")
 	}
@@ -365,7 +368,7 @@ func TestProcessProject_NotifyFailureBlocksStateWrite(t *testing.T) {
 	endDate := now.AddDate(0, 0, 89) // fires the 90-day window (internal-only)
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err == nil {
 		t.Fatal("processProject() error = nil, want non-nil")
 	}
@@ -391,7 +394,7 @@ func TestProcessProject_Day0SuccessfulNotifyThenSuspend(t *testing.T) {
 	open := "Open"
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate, ClosureState: &open}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -457,7 +460,7 @@ func TestProcessProject_Day0RetrySkipsNotifyWhenAlreadyRecorded(t *testing.T) {
 		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"suspend"}}`),
 	}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -502,7 +505,7 @@ func TestProcessProject_SuspendGuardSkipsAlreadySuspendedProject(t *testing.T) {
 		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"suspend"}}`),
 	}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -535,7 +538,7 @@ func TestProcessProject_SuspendGuardSkipsWhenEndDateClosureStateIsClosed(t *test
 		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"suspend"}}`),
 	}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -564,7 +567,7 @@ func TestProcessProject_SuspendProceedsWhenEndDateClosureStateIsExplicitlyOpen(t
 	open := "Open"
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate, EndDateClosureState: &open}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -582,7 +585,7 @@ func TestProcessProject_NothingDueIsNoOp(t *testing.T) {
 	endDate := now.AddDate(0, 0, 200) // far beyond the 90-day window
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -592,6 +595,49 @@ func TestProcessProject_NothingDueIsNoOp(t *testing.T) {
 	if len(ntf.sent) != 0 {
 		t.Errorf("ntf.sent = %d, want 0", len(ntf.sent))
 	}
+	if len(reader.getProjectCalls) != 0 {
+		t.Errorf("GetProject calls = %v, want none for a project with nothing due", reader.getProjectCalls)
+	}
+}
+
+// TestProcessProject_LooksUpAccountRegionAndTier uses the real response
+// shapes: a SearchProjects item's account is only {id, name}, and the
+// region and tier come from GetProject's ProjectAccountRef. A due project is
+// looked up once, and both reach the notice and the gate.
+func TestProcessProject_LooksUpAccountRegionAndTier(t *testing.T) {
+	var page searchProjectsResponse
+	if err := json.Unmarshal([]byte(`{"projects":[{"id":"p1","name":"Acme","key":"ACME","endDate":"2026-07-25T00:00:00Z","account":{"id":"a1","name":"Acme Corp"}}],"hasMore":false}`), &page); err != nil {
+		t.Fatal(err)
+	}
+	proj := page.Projects[0]
+	if proj.region() != "" || proj.tier() != "" {
+		t.Fatalf("search item region, tier = %q, %q; want both empty", proj.region(), proj.tier())
+	}
+
+	reader := &mockEntityReader{
+		getProjectFn: func(ctx context.Context, id string) ([]byte, error) {
+			return []byte(`{"id":"p1","account":{"id":"a1","name":"Acme Corp","activationDate":"2024-01-01","tier":"enterprise","region":"Japan"}}`), nil
+		},
+	}
+	gate := &recordingGate{}
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	if _, err := processProject(context.Background(), reader, &mockProjectUpdater{}, &mockNotifier{}, gate, now, proj); err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if len(reader.getProjectCalls) != 1 || reader.getProjectCalls[0] != "p1" {
+		t.Errorf("GetProject calls = %v, want [p1]", reader.getProjectCalls)
+	}
+	if len(gate.requests) != 1 || gate.requests[0].Tier != "enterprise" || gate.requests[0].AccountName != "Acme Corp" {
+		t.Errorf("gate requests = %+v, want one for Acme Corp with tier enterprise", gate.requests)
+	}
+
+	detailed, err := withAccountDetails(context.Background(), reader, proj)
+	if err != nil || detailed.region() != "Japan" {
+		t.Errorf("withAccountDetails() region = %q, %v; want Japan", detailed.region(), err)
+	}
+	if again, _ := withAccountDetails(context.Background(), reader, detailed); again.region() != "Japan" || len(reader.getProjectCalls) != 2 {
+		t.Errorf("withAccountDetails() on a looked-up project: region %q, GetProject calls %v; want no new call", again.region(), reader.getProjectCalls)
+	}
 }
 
 // TestProcessProject_ReminderUsesRealAccountContacts is a regression test
@@ -639,7 +685,7 @@ func TestProcessProject_ReminderUsesRealAccountContacts(t *testing.T) {
 		EndDate: &endDate,
 	}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -680,7 +726,7 @@ func TestProcessProject_ReminderHasEmptyAccountOwnerEmailWhenNoAccountManager(t
 	endDate := now.AddDate(0, 0, 89)
 	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1"}, EndDate: &endDate}
 
-	err := processProject(context.Background(), reader, updater, ntf, now, proj)
+	_, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
 	if err != nil {
 		t.Fatalf("processProject() error = %v, want nil", err)
 	}
@@ -692,210 +738,343 @@ func TestProcessProject_ReminderHasEmptyAccountOwnerEmailWhenNoAccountManager(t
 	}
 }
 
-// TestInternalNoticeSubject covers the always-[ACP]-prefixed internal
-// subject template directly, confirmed against real examples from Chamara:
-// every window (90 through 0) gets the prefix — it marks "internal
-// audience," not "90/60/30 window" specifically (an earlier version of this
-// logic had that backwards). Day-0 uses distinct "Project Suspension
-// Notice" wording; every other window uses "N Days Reminder".
-func TestInternalNoticeSubject(t *testing.T) {
-	tests := []struct {
-		name        string
-		window      closure.NoticeWindow
-		projectName string
-		accountName string
-		want        string
-	}{
-		{
-			name:        "90-day window",
-			window:      90,
-			projectName: "TICKETNETWORK - Subscription",
-			accountName: "TicketNetwork",
-			want:        "[ACP] 90 Days Reminder of Project for TICKETNETWORK - Subscription of TicketNetwork",
-		},
-		{
-			name:        "60-day window",
-			window:      60,
-			projectName: "P",
-			accountName: "A",
-			want:        "[ACP] 60 Days Reminder of Project for P of A",
-		},
-		{
-			name:        "30-day window",
-			window:      30,
-			projectName: "P",
-			accountName: "A",
-			want:        "[ACP] 30 Days Reminder of Project for P of A",
-		},
-		{
-			name:        "15-day window is still [ACP]-prefixed",
-			window:      15,
-			projectName: "SSC ICT - Subscription",
-			accountName: "SSC-ICT",
-			want:        "[ACP] 15 Days Reminder of Project for SSC ICT - Subscription of SSC-ICT",
-		},
-		{
-			name:        "7-day window is still [ACP]-prefixed",
-			window:      7,
-			projectName: "APIM & Integration - Subscription",
-			accountName: "Department of Science & Technology (DOST)",
-			want:        "[ACP] 7 Days Reminder of Project for APIM & Integration - Subscription of Department of Science & Technology (DOST)",
-		},
-		{
-			name:        "day-0 uses suspension wording, not days-remaining",
-			window:      0,
-			projectName: "Kotak Insurance - Subscription",
-			accountName: "Kotak Life Insurance company Ltd",
-			want:        "[ACP] Project Suspension Notice of Kotak Insurance - Subscription of Kotak Life Insurance company Ltd",
-		},
-		{
-			// Regression test, PR #1440 review (Sajith Ekanayake): a
-			// project with no linked account previously produced a
-			// dangling "...of " with a trailing space and nothing after
-			// it, in a real outbound email subject.
-			name:        "no linked account: omits the dangling \" of \" clause entirely",
-			window:      90,
-			projectName: "Solo Project - Subscription",
-			accountName: "",
-			want:        "[ACP] 90 Days Reminder of Project for Solo Project - Subscription",
-		},
-		{
-			name:        "no linked account, day-0: omits the dangling \" of \" clause entirely",
-			window:      0,
-			projectName: "Solo Project - Subscription",
-			accountName: "",
-			want:        "[ACP] Project Suspension Notice of Solo Project - Subscription",
+// TestProcessProject_ReportsOutcome verifies the outcome a run report is
+// built from: the decision, the last window before and after, every notice
+// built with its resolution tier, and that suspension fired.
+func TestProcessProject_ReportsOutcome(t *testing.T) {
+	reader := &mockEntityReader{
+		searchAccountContactsFn: func(ctx context.Context, accountID string, body []byte) ([]byte, error) {
+			return []byte(`{"contacts":[{"name":"Pat","email":"pat@customer.example","isPrimary":true}]}`), nil
 		},
 	}
+	updater := &mockProjectUpdater{}
+	ntf := &mockNotifier{}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := internalNoticeSubject(tt.window, tt.projectName, tt.accountName); got != tt.want {
-				t.Errorf("internalNoticeSubject(%v, %q, %q) = %q, want %q", tt.window, tt.projectName, tt.accountName, got, tt.want)
-			}
-		})
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(0, 0, -1)
+	open := "Open"
+	proj := project{
+		ID:                     "p1",
+		Name:                   "Acme - Subscription",
+		ProjectKey:             "ACME",
+		Account:                &projectAccountRef{ID: "a1"},
+		EndDate:                &endDate,
+		EndDateClosureState:    &open,
+		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"7_days_notice"}}`),
+	}
+
+	got, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if got.ProjectID != "p1" || got.ProjectKey != "ACME" || got.EndDate != &endDate {
+		t.Errorf("outcome identity = %+v", got)
+	}
+	if got.Decision == nil || !got.Decision.ShouldSuspend || got.Decision.Window != closure.NoticeWindow0 {
+		t.Errorf("Decision = %+v, want a day-0 suspension", got.Decision)
+	}
+	if got.LastWindowBefore == nil || *got.LastWindowBefore != closure.NoticeWindow7 {
+		t.Errorf("LastWindowBefore = %v, want 7", got.LastWindowBefore)
+	}
+	if got.LastWindowAfter == nil || *got.LastWindowAfter != closure.NoticeWindow0 {
+		t.Errorf("LastWindowAfter = %v, want 0", got.LastWindowAfter)
+	}
+	if len(got.Notices) != 2 || got.Notices[0].Err != nil || got.Notices[1].Err != nil {
+		t.Fatalf("Notices = %+v, want the internal and customer notices, both sent", got.Notices)
+	}
+	if got.ResolvedVia() != recipients.ResolvedViaPrimaryContact {
+		t.Errorf("ResolvedVia() = %q, want %q", got.ResolvedVia(), recipients.ResolvedViaPrimaryContact)
+	}
+	if !got.Suspended {
+		t.Error("Suspended = false, want true")
 	}
 }
 
-// TestCustomerNoticeSubject covers the customer-facing subject template,
-// confirmed against real examples: never [ACP]-prefixed, never names the
-// account, future ("Upcoming") tense before day-0 and past tense at day-0.
-func TestCustomerNoticeSubject(t *testing.T) {
-	tests := []struct {
-		name        string
-		window      closure.NoticeWindow
-		projectName string
-		want        string
-	}{
-		{
-			name:        "15-day window",
-			window:      15,
-			projectName: "SSC ICT - Subscription",
-			want:        "Upcoming Project Suspension Notice - SSC ICT - Subscription",
-		},
-		{
-			name:        "7-day window",
-			window:      7,
-			projectName: "SSC ICT - Subscription",
-			want:        "Upcoming Project Suspension Notice - SSC ICT - Subscription",
-		},
-		{
-			name:        "day-0 uses past tense, no \"Upcoming\"",
-			window:      0,
-			projectName: "Kotak Insurance - Subscription",
-			want:        "Project Suspension Notice - Kotak Insurance - Subscription",
+// TestProcessProject_OutcomeKeepsFailedNotice verifies a notice whose Send
+// failed is still reported, with its error, and that the last window does
+// not move when nothing was recorded.
+func TestProcessProject_OutcomeKeepsFailedNotice(t *testing.T) {
+	sendErr := errors.New("smtp down")
+	ntf := &mockNotifier{sendFn: func(ctx context.Context, n notify.Notice) error { return sendErr }}
+
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(0, 0, 89)
+	proj := project{ID: "p1", EndDate: &endDate}
+
+	got, err := processProject(context.Background(), &mockEntityReader{}, &mockProjectUpdater{}, ntf, nil, now, proj)
+	if !errors.Is(err, sendErr) {
+		t.Fatalf("processProject() error = %v, want %v", err, sendErr)
+	}
+	if len(got.Notices) != 1 || !errors.Is(got.Notices[0].Err, sendErr) {
+		t.Errorf("Notices = %+v, want the one failed internal notice", got.Notices)
+	}
+	if got.LastWindowAfter != nil {
+		t.Errorf("LastWindowAfter = %v, want nil", *got.LastWindowAfter)
+	}
+	if got.Suspended {
+		t.Error("Suspended = true, want false")
+	}
+}
+
+// TestProcessProject_RenewalReinstatesSuspendedProject covers the full
+// reinstatement: a project this component suspended whose end date has
+// since moved out a year gets the reinstatement notice (customer included),
+// then endDateClosureState back to Open, then its state reset to "open" —
+// in that order, with the Phase 2 sections preserved and nothing decided.
+func TestProcessProject_RenewalReinstatesSuspendedProject(t *testing.T) {
+	reader := &mockEntityReader{
+		searchProjectContactsFn: func(ctx context.Context, projectID string, body []byte) ([]byte, error) {
+			return []byte(`{"contacts":[{"name":"Bob","email":"bob@customer.example","roles":["business_contact"]}]}`), nil
 		},
 	}
+	updater := &mockProjectUpdater{}
+	ntf := &mockNotifier{}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := customerNoticeSubject(tt.window, tt.projectName); got != tt.want {
-				t.Errorf("customerNoticeSubject(%v, %q) = %q, want %q", tt.window, tt.projectName, got, tt.want)
-			}
-		})
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(1, 0, 0)
+	suspended := "Suspended"
+	proj := project{
+		ID:                  "p1",
+		Name:                "Acme - Subscription",
+		Account:             &projectAccountRef{ID: "a1"},
+		EndDate:             &endDate,
+		EndDateClosureState: &suspended,
+		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"suspend","actionSendEmailNotification":"SUCCESSFUL"},` +
+			`"based_on_due_invoices":{"event_type":"open"}}`),
+	}
+
+	out, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if !out.Reset || !out.Reinstated || out.Decision != nil || out.LastWindowAfter != nil || out.Suspended {
+		t.Errorf("outcome = %+v, want reset and reinstated, nothing decided", out)
+	}
+
+	if len(ntf.sent) != 1 {
+		t.Fatalf("ntf.sent = %d, want 1 reinstatement notice", len(ntf.sent))
+	}
+	n := ntf.sent[0]
+	if n.Subject != "Project Reinstatement Notice - Acme - Subscription" {
+		t.Errorf("Subject = %q", n.Subject)
+	}
+	if n.Recipients.Customer == nil || n.Recipients.Customer.Email != "bob@customer.example" {
+		t.Errorf("Customer = %+v, want the business contact", n.Recipients.Customer)
+	}
+
+	if len(updater.calls) != 2 {
+		t.Fatalf("updater.calls = %d, want 2 (revert, then reset)", len(updater.calls))
+	}
+	if got := string(updater.calls[0].body); got != `{"endDateClosureState":"Open"}` {
+		t.Errorf("first update = %s, want the endDateClosureState revert", got)
+	}
+	var resetBody struct {
+		SuspensionProcessState map[string]json.RawMessage `json:"suspensionProcessState"`
+	}
+	if err := json.Unmarshal(updater.calls[1].body, &resetBody); err != nil {
+		t.Fatalf("parse second update body: %v", err)
+	}
+	if got := string(resetBody.SuspensionProcessState["based_on_subscription_end_date"]); got != `{"event_type":"open"}` {
+		t.Errorf("based_on_subscription_end_date = %s, want reset to open", got)
+	}
+	if got := string(resetBody.SuspensionProcessState["based_on_due_invoices"]); got != `{"event_type":"open"}` {
+		t.Errorf("based_on_due_invoices = %s, want preserved", got)
 	}
 }
 
-// TestInternalNoticeBody covers the internal body templates directly,
-// confirmed verbatim against real examples: the greeting always names the
-// Account Manager (not whichever recipient happens to read their own copy),
-// and day-0 uses distinct "already suspended" wording asking for
-// reinstatement rather than renewal.
-func TestInternalNoticeBody(t *testing.T) {
-	startDate := time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC)
+// TestProcessProject_RenewalBeforeSuspensionOnlyResetsState covers a
+// renewal caught mid-cascade: the 30-day notice was recorded, the project
+// was never suspended, so the only write is the reset — no notice, no
+// endDateClosureState change.
+func TestProcessProject_RenewalBeforeSuspensionOnlyResetsState(t *testing.T) {
+	reader := &mockEntityReader{}
+	updater := &mockProjectUpdater{}
+	ntf := &mockNotifier{}
+
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(0, 0, 200)
+	open := "Open"
 	proj := project{
-		Name:       "TICKETNETWORK - Subscription",
-		ProjectKey: "TICKETNETWORKPROD",
-		StartDate:  &startDate,
-		EndDate:    &endDate,
+		ID:                     "p1",
+		EndDate:                &endDate,
+		EndDateClosureState:    &open,
+		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"30_days_notice"}}`),
 	}
 
-	t.Run("day-count window", func(t *testing.T) {
-		got := internalNoticeBody(90, proj, "Lochana De Alwis")
-		wantContains := []string{
-			"Dear Lochana De Alwis",
-			"non renewed contract",
-			"Project Name: TICKETNETWORK - Subscription",
-			"Project Key: TICKETNETWORKPROD",
-			"Account Owner: Lochana De Alwis",
-			"Start Date: 2024-11-10",
-			"End Date: 2026-11-09",
-			"Best Regards,\nWSO2 Team",
-		}
-		for _, want := range wantContains {
-			if !strings.Contains(got, want) {
-				t.Errorf("body missing %q, got:\n%s", want, got)
-			}
-		}
-	})
-
-	t.Run("day-0 window uses suspension wording", func(t *testing.T) {
-		got := internalNoticeBody(0, proj, "Rajat Mehta")
-		wantContains := []string{
-			"Dear Rajat Mehta",
-			"has been suspended",
-			"reinitiate the suspended support account",
-			"Account Owner: Rajat Mehta",
-		}
-		for _, want := range wantContains {
-			if !strings.Contains(got, want) {
-				t.Errorf("body missing %q, got:\n%s", want, got)
-			}
-		}
-		if strings.Contains(got, "non renewed contract. Please find") {
-			t.Errorf("day-0 body contains day-count wording, got:\n%s", got)
-		}
-	})
+	out, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if !out.Reset || out.Reinstated {
+		t.Errorf("outcome = %+v, want reset only", out)
+	}
+	if len(ntf.sent) != 0 || len(reader.getAccountCalls) != 0 {
+		t.Errorf("sent %d notices, %d account lookups; want none", len(ntf.sent), len(reader.getAccountCalls))
+	}
+	if len(updater.calls) != 1 || !strings.Contains(string(updater.calls[0].body), `"event_type":"open"`) {
+		t.Errorf("updater.calls = %+v, want the reset only", updater.calls)
+	}
 }
 
-// TestCustomerNoticeBody covers the customer body templates directly:
-// future tense with US-formatted (01/02/2006) dates before day-0, past
-// tense at day-0, and no greeting in either case.
-func TestCustomerNoticeBody(t *testing.T) {
-	endDate := time.Date(2026, 8, 24, 0, 0, 0, 0, time.UTC)
-	proj := project{Name: "SSC ICT - Subscription", EndDate: &endDate}
-
-	t.Run("day-count window uses future tense and US date format", func(t *testing.T) {
-		got := customerNoticeBody(15, proj)
-		if strings.Contains(got, "Dear ") {
-			t.Errorf("body has a greeting, want none:\n%s", got)
-		}
-		wantContains := []string{
-			"will be suspended on 08/24/2026",
-			"on or before 08/24/2026",
-		}
-		for _, want := range wantContains {
-			if !strings.Contains(got, want) {
-				t.Errorf("body missing %q, got:\n%s", want, got)
-			}
-		}
-	})
+// TestProcessProject_ReinstatementNoticeFailureChangesNothing verifies a
+// failed reinstatement notice leaves the project exactly as it was —
+// still suspended, state still stale — so the next run retries it in full.
+func TestProcessProject_ReinstatementNoticeFailureChangesNothing(t *testing.T) {
+	reader := &mockEntityReader{}
+	updater := &mockProjectUpdater{}
+	ntf := &mockNotifier{sendFn: func(ctx context.Context, n notify.Notice) error { return errors.New("smtp down") }}
 
-	t.Run("day-0 window uses past tense", func(t *testing.T) {
-		got := customerNoticeBody(0, proj)
-		if !strings.Contains(got, "was suspended 08/24/2026") {
-			t.Errorf("body missing past-tense suspension wording, got:\n%s", got)
-		}
-	})
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(1, 0, 0)
+	suspended := "Suspended"
+	proj := project{
+		ID:                     "p1",
+		EndDate:                &endDate,
+		EndDateClosureState:    &suspended,
+		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"suspend"}}`),
+	}
+
+	out, err := processProject(context.Background(), reader, updater, ntf, nil, now, proj)
+	if err == nil {
+		t.Fatal("processProject() error = nil, want the send failure")
+	}
+	if len(updater.calls) != 0 {
+		t.Errorf("updater.calls = %d, want 0", len(updater.calls))
+	}
+	if out.Reset || out.Reinstated || len(out.Notices) != 1 || out.Notices[0].Err == nil {
+		t.Errorf("outcome = %+v, want the failed notice and nothing else", out)
+	}
+}
+
+// TestProcessProject_RenewalDoesNotReopenClosedProject verifies a project
+// moved past Suspended to "Closed" outside this component stays closed on
+// renewal: its stale state is reset, but no notice goes out and
+// endDateClosureState is left alone.
+func TestProcessProject_RenewalDoesNotReopenClosedProject(t *testing.T) {
+	updater := &mockProjectUpdater{}
+	ntf := &mockNotifier{}
+
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(1, 0, 0)
+	closed := "Closed"
+	proj := project{
+		ID:                     "p1",
+		EndDate:                &endDate,
+		EndDateClosureState:    &closed,
+		SuspensionProcessState: []byte(`{"based_on_subscription_end_date":{"event_type":"suspend"}}`),
+	}
+
+	out, err := processProject(context.Background(), &mockEntityReader{}, updater, ntf, nil, now, proj)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if !out.Reset || out.Reinstated || len(ntf.sent) != 0 {
+		t.Errorf("outcome = %+v, sent %d; want reset only", out, len(ntf.sent))
+	}
+	if len(updater.calls) != 1 || strings.Contains(string(updater.calls[0].body), "endDateClosureState") {
+		t.Errorf("updater.calls = %+v, want the reset only", updater.calls)
+	}
+}
+
+// TestProcessProject_ApprovalGateHoldsDay0UntilApproved walks an
+// enterprise project's day-0 suspension through the gate: the first run
+// holds it back entirely — no notice, no write — as a pending approval; a
+// run after approval sends the notice, suspends, and clears the approval.
+func TestProcessProject_ApprovalGateHoldsDay0UntilApproved(t *testing.T) {
+	store := approvals.NewStore(filepath.Join(t.TempDir(), "approvals.json"))
+	gate := approvals.NewGate(store, []string{"enterprise"}, false)
+	updater := &mockProjectUpdater{}
+	ntf := &mockNotifier{}
+
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(0, 0, -3)
+	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1", Tier: "enterprise"}, EndDate: &endDate}
+
+	out, err := processProject(context.Background(), &mockEntityReader{}, updater, ntf, gate, now, proj)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if out.Approval != approvals.StatePending || out.Suspended || len(ntf.sent) != 0 || len(updater.calls) != 0 {
+		t.Fatalf("held run: outcome = %+v, sent %d, updates %d; want pending and nothing done", out, len(ntf.sent), len(updater.calls))
+	}
+
+	if _, err := store.Decide("p1", true, "finance@example.com", ""); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	out, err = processProject(context.Background(), &mockEntityReader{}, updater, ntf, gate, now.AddDate(0, 0, 1), proj)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if out.Approval != approvals.StateApproved || !out.Suspended || len(ntf.sent) == 0 {
+		t.Errorf("approved run: outcome = %+v, sent %d; want notice sent and suspended", out, len(ntf.sent))
+	}
+	if list, _ := store.List(); len(list) != 0 {
+		t.Errorf("approvals after execution = %+v, want none", list)
+	}
+}
+
+// TestProcessProject_ApprovalGateSkipsRejectedAndUngatedTiers verifies a
+// rejected suspension stays held with its reason, and that a tier outside
+// SUSPENSION_APPROVAL_TIERS is suspended without asking.
+func TestProcessProject_ApprovalGateSkipsRejectedAndUngatedTiers(t *testing.T) {
+	store := approvals.NewStore(filepath.Join(t.TempDir(), "approvals.json"))
+	gate := approvals.NewGate(store, []string{"enterprise"}, false)
+
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(0, 0, -3)
+	large := project{ID: "p1", Account: &projectAccountRef{ID: "a1", Tier: "enterprise"}, EndDate: &endDate}
+	small := project{ID: "p2", Account: &projectAccountRef{ID: "a2", Tier: "basic"}, EndDate: &endDate}
+
+	if _, err := processProject(context.Background(), &mockEntityReader{}, &mockProjectUpdater{}, &mockNotifier{}, gate, now, large); err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if _, err := store.Decide("p1", false, "finance@example.com", "renewal in negotiation"); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+
+	updater := &mockProjectUpdater{}
+	out, err := processProject(context.Background(), &mockEntityReader{}, updater, &mockNotifier{}, gate, now, large)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if out.Approval != approvals.StateRejected || out.ApprovalReason != "renewal in negotiation" || len(updater.calls) != 0 {
+		t.Errorf("rejected: outcome = %+v, updates %d; want rejected with reason and nothing done", out, len(updater.calls))
+	}
+
+	out, err = processProject(context.Background(), &mockEntityReader{}, &mockProjectUpdater{}, &mockNotifier{}, gate, now, small)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if out.Approval != "" || !out.Suspended {
+		t.Errorf("basic tier: outcome = %+v, want suspended without approval", out)
+	}
+}
+
+// TestProcessProject_ApprovalGateUsesLookedUpTier verifies a searched
+// project — whose account carries no tier — reaches the gate with the tier
+// GetProject reports: a basic-tier account is suspended without asking,
+// not held as if its tier were unknown.
+func TestProcessProject_ApprovalGateUsesLookedUpTier(t *testing.T) {
+	store := approvals.NewStore(filepath.Join(t.TempDir(), "approvals.json"))
+	gate := approvals.NewGate(store, []string{"enterprise"}, false)
+
+	now := time.Date(2026, 7, 28, 0, 0, 0, 0, time.UTC)
+	endDate := now.AddDate(0, 0, -3)
+	proj := project{ID: "p1", Account: &projectAccountRef{ID: "a1", Name: "Acme Corp"}, EndDate: &endDate}
+	reader := &mockEntityReader{
+		getProjectFn: func(ctx context.Context, id string) ([]byte, error) {
+			return []byte(`{"id":"p1","account":{"id":"a1","name":"Acme Corp","tier":"basic","region":null}}`), nil
+		},
+	}
+
+	out, err := processProject(context.Background(), reader, &mockProjectUpdater{}, &mockNotifier{}, gate, now, proj)
+	if err != nil {
+		t.Fatalf("processProject() error = %v, want nil", err)
+	}
+	if out.Approval != "" || !out.Suspended {
+		t.Errorf("outcome = %+v, want suspended without approval", out)
+	}
+	if list, _ := store.List(); len(list) != 0 {
+		t.Errorf("approvals = %+v, want none", list)
+	}
 }
@@ -21,7 +21,10 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/approvals"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
 	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/recipients"
 )
 
 // project is the subset of csm-integration-service's Project shape this
@@ -65,15 +68,26 @@ type project struct {
 	// value as already handled, not just an exact "Suspended" match.
 	EndDateClosureState    *string         `json:"endDateClosureState"`
 	SuspensionProcessState json.RawMessage `json:"suspensionProcessState"`
+	// detailed is set on a project read via GetProject, whose Account
+	// already carries region and tier; see withAccountDetails.
+	detailed bool
 }
 
-// projectAccountRef is the nested account reference on both GetProject's and
-// SearchProjects's response shapes. id and name are used (name for the
-// notice subject line); the upstream shape carries more (activationDate,
-// tier, region, ...) that this component doesn't need.
+// projectAccountRef is the nested account reference on GetProject's and
+// SearchProjects's response shapes. The two differ: SearchProjects's items
+// carry only {id, name} (entity-service's ProjectView.Account is a plain
+// EntityRef), while GetProject's carries entity-service's full
+// ProjectAccountRef, region and tier included. id, name (for the notice
+// subject line), region (to pick a notice locale) and tier ("basic" or
+// "enterprise", to decide whether a suspension needs approval) are used, so
+// a searched project has its region and tier looked up before anything is
+// done to it (see withAccountDetails); the upstream shape carries more
+// (activationDate, ...) that this component doesn't need.
 type projectAccountRef struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Region string `json:"region"`
+	Tier   string `json:"tier"`
 }
 
 // accountID returns the project's account ID, or "" if the project
@@ -85,6 +99,26 @@ func (p project) accountID() string {
 	return p.Account.ID
 }
 
+// region returns the project's account region, or "" if the project
+// genuinely has no linked account or it hasn't been looked up yet (see
+// withAccountDetails).
+func (p project) region() string {
+	if p.Account == nil {
+		return ""
+	}
+	return p.Account.Region
+}
+
+// tier returns the project's account tier, or "" if the project genuinely
+// has no linked account or it hasn't been looked up yet (see
+// withAccountDetails).
+func (p project) tier() string {
+	if p.Account == nil {
+		return ""
+	}
+	return p.Account.Tier
+}
+
 // searchProjectsResponse mirrors csm-integration-service's ProjectSearchResponse.
 type searchProjectsResponse struct {
 	Projects []project `json:"projects"`
@@ -136,22 +170,24 @@ type accountDTO struct {
 }
 
 // entityReader is the minimal read surface processProject needs. Satisfied
-// directly by *entity.Client — reads are never dry-run-gated.
+// directly by *entity.Client — reads are never dry-run-gated. GetProject
+// fills in a searched project's account region and tier (see
+// withAccountDetails).
 type entityReader interface {
 	SearchAccountContacts(ctx context.Context, accountID string, body []byte) ([]byte, error)
 	SearchProjectContacts(ctx context.Context, projectID string, body []byte) ([]byte, error)
 	GetAccount(ctx context.Context, id string) ([]byte, error)
+	GetProject(ctx context.Context, id string) ([]byte, error)
 }
 
-// sweepReader is everything Run needs: entityReader plus SearchProjects and
-// GetProject, the two extra read methods the outer loop uses that
-// processProject doesn't. GetProject backs the TEST_PROJECT_ID scoped-run
-// path — fetching one project directly instead of paginating the broad
-// search. Satisfied directly by *entity.Client.
+// sweepReader is everything Run needs: entityReader plus SearchProjects, the
+// extra read method the outer loop uses that processProject doesn't. The
+// outer loop also uses GetProject for the TEST_PROJECT_ID scoped-run path —
+// fetching one project directly instead of paginating the broad search.
+// Satisfied directly by *entity.Client.
 type sweepReader interface {
 	entityReader
 	SearchProjects(ctx context.Context, body []byte) ([]byte, error)
-	GetProject(ctx context.Context, id string) ([]byte, error)
 }
 
 // pagination mirrors entity-service's Pagination shape.
@@ -182,6 +218,72 @@ type Result struct {
 	ProjectsEvaluated int
 	ProjectsExcluded  int
 	Failures          []ProjectFailure
+	// Projects holds one outcome per project Run reached, evaluated or
+	// excluded, in the order it reached them — what a run report is built
+	// from.
+	Projects []ProjectOutcome
+}
+
+// ProjectOutcome records what one project's evaluation decided and did.
+// Fields past ProjectID are left at their zero value for an excluded
+// project, and past EndDate for a project with no end date (never
+// evaluated).
+type ProjectOutcome struct {
+	ProjectID   string
+	ProjectName string
+	ProjectKey  string
+	EndDate     *time.Time
+	Excluded    bool
+	// Decision is nil when the project was never decided on: excluded, no
+	// end date, an unparseable suspensionProcessState, or a renewal this
+	// run reset the recorded state for instead.
+	Decision *closure.Decision
+	// LastWindowBefore is the last notice window recorded before this run;
+	// LastWindowAfter is the same after it, which differs only when this
+	// run recorded a new notice or reset a stale one (nil).
+	LastWindowBefore *closure.NoticeWindow
+	LastWindowAfter  *closure.NoticeWindow
+	// Notices are the notices built for this project, in send order, each
+	// with the error its Send returned. A notice that was never attempted
+	// because an earlier one failed is not listed.
+	Notices []NoticeOutcome
+	// Suspended reports this run wrote endDateClosureState=Suspended. False
+	// when suspension was due but the project had already moved past Open.
+	Suspended bool
+	// Reset reports this run found the end date moved out past the recorded
+	// window — a renewal — and reset based_on_subscription_end_date to
+	// "open", so the notice cascade starts over from the new end date.
+	Reset bool
+	// Reinstated reports this run, on that renewal, reverted
+	// endDateClosureState from Suspended to Open and sent the reinstatement
+	// notice (listed in Notices).
+	Reinstated bool
+	// Approval is the state of the approval this project's day-0
+	// suspension needed ("" when it needed none): pending or rejected
+	// means the whole day-0 action — notice and suspension — was held
+	// back this run; approved means it went ahead. ApprovalReason is the
+	// decider's reason, if any.
+	Approval       approvals.State
+	ApprovalReason string
+	Err            error
+}
+
+// ResolvedVia returns the customer-contact resolution tier of the
+// project's notices, or "" when none attempted the fallback (an
+// internal-only window, or no notice at all).
+func (o ProjectOutcome) ResolvedVia() recipients.ResolvedVia {
+	for _, n := range o.Notices {
+		if n.Notice.ResolvedVia != "" {
+			return n.Notice.ResolvedVia
+		}
+	}
+	return ""
+}
+
+// NoticeOutcome is one notice built for a project and how its Send went.
+type NoticeOutcome struct {
+	Notice notify.Notice
+	Err    error
 }
 
 // ProjectFailure records a single project's processProject failure.
@@ -198,6 +300,17 @@ type projectUpdater interface {
 	UpdateProject(ctx context.Context, id string, body []byte) ([]byte, error)
 }
 
+// suspensionGate decides whether a due day-0 suspension may be executed.
+// Satisfied by *approvals.Gate; nil when SUSPENSION_APPROVAL_TIERS is
+// unset and every suspension goes ahead as soon as it is due.
+type suspensionGate interface {
+	// Check returns the Approval governing req's suspension, nil when none
+	// is needed.
+	Check(ctx context.Context, req approvals.Request) (*approvals.Approval, error)
+	// Executed is told once an approved suspension has been written.
+	Executed(ctx context.Context, projectID string) error
+}
+
 // notifier is the minimal send surface processProject needs. Satisfied by
 // *notify.LoggingNotifier today; a real implementation will satisfy the same
 // interface once one exists.
@@ -0,0 +1,349 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package templates holds the wording of every ACP notice as text/template
+// files, so a wording change from the renewals team is a template edit, not
+// a Go change. The defaults in notices/ are embedded in the binary and
+// carry the wording confirmed against Chamara's real examples;
+// NOTICE_TEMPLATES_DIR can point at a directory laid out the same way to
+// replace them without a rebuild.
+//
+// There is one file per notice kind and window, named <kind>_<window>.tmpl
+// — internal_90.tmpl ... internal_0.tmpl, customer_15/7/0.tmpl,
+// no_business_contact_15/7/0.tmpl and reinstatement_0.tmpl — each defining a "subject" and a "body"
+// template, executed against Data: a notify.Notice plus the account name.
+// Two funcs format dates: date (2006-01-02) and usdate (01/02/2006, the
+// customer-facing bodies' style).
+//
+// A locale variant, <kind>_<window>.<locale>.tmpl, is used instead of the
+// base file for a project whose account's region maps to that locale in
+// the set's optional locales.json ({"<region>": "<locale>", ...}). A
+// kind/window with no variant for the locale falls back to the base file.
+//
+// Load validates the whole set up front — every kind/window present, every
+// file name recognized, every template executing cleanly against a fixture
+// notice — so a typo in a field name fails at startup, not on a real
+// project's day-0 notice.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/recipients"
+)
+
+//go:embed notices/*.tmpl
+var embedded embed.FS
+
+// Kind is a notice's audience and purpose.
+type Kind string
+
+const (
+	// KindInternal is the always-sent notice to the Account Owner, Renewal
+	// Manager and Technical Owner.
+	KindInternal Kind = "internal"
+	// KindCustomer is the customer-facing notice for the 15/7/0 windows.
+	KindCustomer Kind = "customer"
+	// KindNoBusinessContact is the urgent internal notice sent instead of
+	// KindCustomer when no customer contact resolves.
+	KindNoBusinessContact Kind = "no_business_contact"
+	// KindReinstatement is the notice sent when a suspended project's
+	// renewal reverts its suspension. It has only the day-0 template: it
+	// reverses the day-0 suspension.
+	KindReinstatement Kind = "reinstatement"
+)
+
+// windows lists the notice windows each kind has a template for.
+var windows = map[Kind][]closure.NoticeWindow{
+	KindInternal:          {closure.NoticeWindow90, closure.NoticeWindow60, closure.NoticeWindow30, closure.NoticeWindow15, closure.NoticeWindow7, closure.NoticeWindow0},
+	KindCustomer:          {closure.NoticeWindow15, closure.NoticeWindow7, closure.NoticeWindow0},
+	KindNoBusinessContact: {closure.NoticeWindow15, closure.NoticeWindow7, closure.NoticeWindow0},
+	KindReinstatement:     {closure.NoticeWindow0},
+}
+
+// Data is what every template is executed against. Subject and Body are
+// still empty while it is.
+type Data struct {
+	notify.Notice
+	// AccountName is "" when the project has no linked account.
+	AccountName string
+}
+
+// fileRe matches a template file name: kind, window, optional locale.
+var fileRe = regexp.MustCompile(`^([a-z_]+)_(\d+)(?:\.([A-Za-z0-9-]+))?\.tmpl$`)
+
+const localesFile = "locales.json"
+
+type key struct {
+	kind   Kind
+	window closure.NoticeWindow
+	locale string
+}
+
+func (k key) String() string {
+	name := fmt.Sprintf("%s_%d", k.kind, k.window)
+	if k.locale != "" {
+		name += "." + k.locale
+	}
+	return name
+}
+
+// Set is a loaded, validated set of notice templates.
+type Set struct {
+	templates map[key]*template.Template
+	// locales maps an account region to a locale.
+	locales map[string]string
+}
+
+var funcs = template.FuncMap{
+	"date":   func(t time.Time) string { return formatDate(t, "2006-01-02") },
+	"usdate": func(t time.Time) string { return formatDate(t, "01/02/2006") },
+}
+
+func formatDate(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// Default returns the embedded default set.
+func Default() (*Set, error) {
+	sub, err := fs.Sub(embedded, "notices")
+	if err != nil {
+		return nil, err
+	}
+	return Load(sub)
+}
+
+// MustDefault is Default, panicking on error: the embedded set is covered
+// by this package's tests, so an error here is a build defect.
+func MustDefault() *Set {
+	s, err := Default()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Load reads and validates the set of templates at the root of fsys,
+// returning every problem found, not just the first.
+func Load(fsys fs.FS) (*Set, error) {
+	s := &Set{templates: map[key]*template.Template{}, locales: map[string]string{}}
+	var errs []error
+
+	if raw, err := fs.ReadFile(fsys, localesFile); err == nil {
+		if err := json.Unmarshal(raw, &s.locales); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", localesFile, err))
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		errs = append(errs, fmt.Errorf("%s: %w", localesFile, err))
+	}
+	knownLocales := map[string]bool{}
+	for _, locale := range s.locales {
+		knownLocales[locale] = true
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || name == localesFile {
+			continue
+		}
+		k, err := parseName(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if k.locale != "" && !knownLocales[k.locale] {
+			errs = append(errs, fmt.Errorf("%s: locale %q is not mapped from any region in %s", name, k.locale, localesFile))
+			continue
+		}
+		raw, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		t, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(raw))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := validate(t, k); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		s.templates[k] = t
+	}
+
+	for _, kind := range kinds() {
+		for _, w := range windows[kind] {
+			if _, ok := s.templates[key{kind: kind, window: w}]; !ok {
+				errs = append(errs, fmt.Errorf("%s.tmpl: missing", key{kind: kind, window: w}))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("templates: %w", errors.Join(errs...))
+	}
+	return s, nil
+}
+
+func kinds() []Kind {
+	return []Kind{KindInternal, KindCustomer, KindNoBusinessContact, KindReinstatement}
+}
+
+// parseName maps a file name to the kind/window/locale it holds, rejecting
+// any name that is not one of the expected templates.
+func parseName(name string) (key, error) {
+	m := fileRe.FindStringSubmatch(name)
+	if m == nil {
+		return key{}, fmt.Errorf("%s: not a <kind>_<window>[.<locale>].tmpl file name", name)
+	}
+	kind := Kind(m[1])
+	days, _ := strconv.Atoi(m[2])
+	for _, w := range windows[kind] {
+		if int(w) == days {
+			return key{kind: kind, window: w, locale: m[3]}, nil
+		}
+	}
+	return key{}, fmt.Errorf("%s: no %s notice is sent for a %d-day window", name, kind, days)
+}
+
+// validate checks t defines a single-line subject and a body, and that both
+// execute against the fixture notice for k.
+func validate(t *template.Template, k key) error {
+	for _, name := range []string{"subject", "body"} {
+		if t.Lookup(name) == nil {
+			return fmt.Errorf("no %q template defined", name)
+		}
+	}
+	_, _, err := execute(t, sample(k.kind, k.window))
+	return err
+}
+
+func execute(t *template.Template, d Data) (subject, body string, err error) {
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "subject", d); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(buf.String())
+	if strings.ContainsAny(subject, "\r\n") {
+		return "", "", errors.New("subject renders to more than one line")
+	}
+	buf.Reset()
+	if err := t.ExecuteTemplate(&buf, "body", d); err != nil {
+		return "", "", err
+	}
+	return subject, buf.String(), nil
+}
+
+// Render renders the subject and body of a kind notice for d, using the
+// locale variant for region when there is one.
+func (s *Set) Render(kind Kind, region string, d Data) (subject, body string, err error) {
+	k := key{kind: kind, window: d.Window}
+	if locale := s.locales[region]; locale != "" {
+		if _, ok := s.templates[key{kind: kind, window: d.Window, locale: locale}]; ok {
+			k.locale = locale
+		}
+	}
+	t, ok := s.templates[k]
+	if !ok {
+		return "", "", fmt.Errorf("templates: no %s template", k)
+	}
+	subject, body, err = execute(t, d)
+	if err != nil {
+		return "", "", fmt.Errorf("templates: render %s: %w", k, err)
+	}
+	return subject, body, nil
+}
+
+// WriteSamples renders every template in the set against the fixture
+// project and writes them to w, in kind, window, locale order — what
+// --render-samples prints for reviewing a wording change.
+func (s *Set) WriteSamples(w io.Writer) error {
+	keys := make([]key, 0, len(s.templates))
+	for k := range s.templates {
+		keys = append(keys, k)
+	}
+	order := map[Kind]int{KindInternal: 0, KindCustomer: 1, KindNoBusinessContact: 2, KindReinstatement: 3}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.kind != b.kind {
+			return order[a.kind] < order[b.kind]
+		}
+		if a.window != b.window {
+			return a.window > b.window
+		}
+		return a.locale < b.locale
+	})
+
+	for _, k := range keys {
+		subject, body, err := execute(s.templates[k], sample(k.kind, k.window))
+		if err != nil {
+			return fmt.Errorf("templates: render %s: %w", k, err)
+		}
+		if _, err := fmt.Fprintf(w, "==== %s.tmpl\nSubject: %s\n\n%s\n\n", k, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sample is the fixture notice every template is validated and sampled
+// against: every field set, with the recipients kind actually sends to.
+func sample(kind Kind, window closure.NoticeWindow) Data {
+	end := time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC)
+	n := notify.Notice{
+		ProjectID:   "00000000-0000-4000-8000-000000000000",
+		ProjectName: "Acme - Subscription",
+		ProjectKey:  "ACMEPROD",
+		StartDate:   end.AddDate(-1, 0, 1),
+		EndDate:     end,
+		Window:      window,
+		Recipients: notify.Recipients{
+			AccountOwner:   recipients.Contact{Name: "Alex Owner", Email: "alex.owner@wso2.example"},
+			RenewalManager: recipients.Contact{Name: "Riley Renewals", Email: "riley.renewals@wso2.example"},
+			TechnicalOwner: recipients.Contact{Name: "Taylor Tech", Email: "taylor.tech@wso2.example"},
+		},
+	}
+	switch kind {
+	case KindCustomer, KindReinstatement:
+		n.Recipients.Customer = &recipients.Contact{Name: "Pat Customer", Email: "pat@customer.example"}
+		n.ResolvedVia = recipients.ResolvedViaBusinessContact
+	case KindNoBusinessContact:
+		n.ResolvedVia = recipients.ResolvedViaNone
+	}
+	return Data{Notice: n, AccountName: "Acme Corporation"}
+}
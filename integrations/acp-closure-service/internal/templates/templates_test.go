@@ -0,0 +1,372 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package templates
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/closure"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/notify"
+	"github.com/wso2-open-operations/cs-tools/integrations/acp-closure-service/internal/recipients"
+)
+
+// render renders a kind notice for d with the default set.
+func render(t *testing.T, kind Kind, d Data) (subject, body string) {
+	t.Helper()
+	subject, body, err := MustDefault().Render(kind, "", d)
+	if err != nil {
+		t.Fatalf("Render(%s, %d) error = %v", kind, d.Window, err)
+	}
+	return subject, body
+}
+
+// TestInternalNoticeSubject covers the always-[ACP]-prefixed internal
+// subject template, confirmed against real examples from Chamara:
+// every window (90 through 0) gets the prefix — it marks "internal
+// audience," not "90/60/30 window" specifically (an earlier version of this
+// logic had that backwards). Day-0 uses distinct "Project Suspension
+// Notice" wording; every other window uses "N Days Reminder".
+func TestInternalNoticeSubject(t *testing.T) {
+	tests := []struct {
+		name        string
+		window      closure.NoticeWindow
+		projectName string
+		accountName string
+		want        string
+	}{
+		{
+			name:        "90-day window",
+			window:      90,
+			projectName: "TICKETNETWORK - Subscription",
+			accountName: "TicketNetwork",
+			want:        "[ACP] 90 Days Reminder of Project for TICKETNETWORK - Subscription of TicketNetwork",
+		},
+		{
+			name:        "60-day window",
+			window:      60,
+			projectName: "P",
+			accountName: "A",
+			want:        "[ACP] 60 Days Reminder of Project for P of A",
+		},
+		{
+			name:        "30-day window",
+			window:      30,
+			projectName: "P",
+			accountName: "A",
+			want:        "[ACP] 30 Days Reminder of Project for P of A",
+		},
+		{
+			name:        "15-day window is still [ACP]-prefixed",
+			window:      15,
+			projectName: "SSC ICT - Subscription",
+			accountName: "SSC-ICT",
+			want:        "[ACP] 15 Days Reminder of Project for SSC ICT - Subscription of SSC-ICT",
+		},
+		{
+			name:        "7-day window is still [ACP]-prefixed",
+			window:      7,
+			projectName: "APIM & Integration - Subscription",
+			accountName: "Department of Science & Technology (DOST)",
+			want:        "[ACP] 7 Days Reminder of Project for APIM & Integration - Subscription of Department of Science & Technology (DOST)",
+		},
+		{
+			name:        "day-0 uses suspension wording, not days-remaining",
+			window:      0,
+			projectName: "Kotak Insurance - Subscription",
+			accountName: "Kotak Life Insurance company Ltd",
+			want:        "[ACP] Project Suspension Notice of Kotak Insurance - Subscription of Kotak Life Insurance company Ltd",
+		},
+		{
+			// Regression test, PR #1440 review (Sajith Ekanayake): a
+			// project with no linked account previously produced a
+			// dangling "...of " with a trailing space and nothing after
+			// it, in a real outbound email subject.
+			name:        "no linked account: omits the dangling \" of \" clause entirely",
+			window:      90,
+			projectName: "Solo Project - Subscription",
+			accountName: "",
+			want:        "[ACP] 90 Days Reminder of Project for Solo Project - Subscription",
+		},
+		{
+			name:        "no linked account, day-0: omits the dangling \" of \" clause entirely",
+			window:      0,
+			projectName: "Solo Project - Subscription",
+			accountName: "",
+			want:        "[ACP] Project Suspension Notice of Solo Project - Subscription",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Data{Notice: notify.Notice{ProjectName: tt.projectName, Window: tt.window}, AccountName: tt.accountName}
+			if got, _ := render(t, KindInternal, d); got != tt.want {
+				t.Errorf("internal subject(%v, %q, %q) = %q, want %q", tt.window, tt.projectName, tt.accountName, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCustomerNoticeSubject covers the customer-facing subject template,
+// confirmed against real examples: never [ACP]-prefixed, never names the
+// account, future ("Upcoming") tense before day-0 and past tense at day-0.
+func TestCustomerNoticeSubject(t *testing.T) {
+	tests := []struct {
+		name        string
+		window      closure.NoticeWindow
+		projectName string
+		want        string
+	}{
+		{
+			name:        "15-day window",
+			window:      15,
+			projectName: "SSC ICT - Subscription",
+			want:        "Upcoming Project Suspension Notice - SSC ICT - Subscription",
+		},
+		{
+			name:        "7-day window",
+			window:      7,
+			projectName: "SSC ICT - Subscription",
+			want:        "Upcoming Project Suspension Notice - SSC ICT - Subscription",
+		},
+		{
+			name:        "day-0 uses past tense, no \"Upcoming\"",
+			window:      0,
+			projectName: "Kotak Insurance - Subscription",
+			want:        "Project Suspension Notice - Kotak Insurance - Subscription",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Data{Notice: notify.Notice{ProjectName: tt.projectName, Window: tt.window}}
+			if got, _ := render(t, KindCustomer, d); got != tt.want {
+				t.Errorf("customer subject(%v, %q) = %q, want %q", tt.window, tt.projectName, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestInternalNoticeBody covers the internal body templates,
+// confirmed verbatim against real examples: the greeting always names the
+// Account Manager (not whichever recipient happens to read their own copy),
+// and day-0 uses distinct "already suspended" wording asking for
+// reinstatement rather than renewal.
+func TestInternalNoticeBody(t *testing.T) {
+	startDate := time.Date(2024, 11, 10, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC)
+	notice := func(window closure.NoticeWindow, accountOwnerName string) Data {
+		return Data{Notice: notify.Notice{
+			ProjectName: "TICKETNETWORK - Subscription",
+			ProjectKey:  "TICKETNETWORKPROD",
+			StartDate:   startDate,
+			EndDate:     endDate,
+			Window:      window,
+			Recipients:  notify.Recipients{AccountOwner: recipients.Contact{Name: accountOwnerName}},
+		}}
+	}
+
+	t.Run("day-count window", func(t *testing.T) {
+		_, got := render(t, KindInternal, notice(90, "Lochana De Alwis"))
+		wantContains := []string{
+			"Dear Lochana De Alwis",
+			"non renewed contract",
+			"Project Name: TICKETNETWORK - Subscription",
+			"Project Key: TICKETNETWORKPROD",
+			"Account Owner: Lochana De Alwis",
+			"Start Date: 2024-11-10",
+			"End Date: 2026-11-09",
+			"Best Regards,\nWSO2 Team",
+		}
+		for _, want := range wantContains {
+			if !strings.Contains(got, want) {
+				t.Errorf("body missing %q, got:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("day-0 window uses suspension wording", func(t *testing.T) {
+		_, got := render(t, KindInternal, notice(0, "Rajat Mehta"))
+		wantContains := []string{
+			"Dear Rajat Mehta",
+			"has been suspended",
+			"reinitiate the suspended support account",
+			"Account Owner: Rajat Mehta",
+		}
+		for _, want := range wantContains {
+			if !strings.Contains(got, want) {
+				t.Errorf("body missing %q, got:\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "non renewed contract. Please find") {
+			t.Errorf("day-0 body contains day-count wording, got:\n%s", got)
+		}
+	})
+}
+
+// TestCustomerNoticeBody covers the customer body templates:
+// future tense with US-formatted (01/02/2006) dates before day-0, past
+// tense at day-0, and no greeting in either case.
+func TestCustomerNoticeBody(t *testing.T) {
+	endDate := time.Date(2026, 8, 24, 0, 0, 0, 0, time.UTC)
+	notice := func(window closure.NoticeWindow) Data {
+		return Data{Notice: notify.Notice{ProjectName: "SSC ICT - Subscription", EndDate: endDate, Window: window}}
+	}
+
+	t.Run("day-count window uses future tense and US date format", func(t *testing.T) {
+		_, got := render(t, KindCustomer, notice(15))
+		if strings.Contains(got, "Dear ") {
+			t.Errorf("body has a greeting, want none:\n%s", got)
+		}
+		wantContains := []string{
+			"will be suspended on 08/24/2026",
+			"on or before 08/24/2026",
+		}
+		for _, want := range wantContains {
+			if !strings.Contains(got, want) {
+				t.Errorf("body missing %q, got:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("day-0 window uses past tense", func(t *testing.T) {
+		_, got := render(t, KindCustomer, notice(0))
+		if !strings.Contains(got, "was suspended 08/24/2026") {
+			t.Errorf("body missing past-tense suspension wording, got:\n%s", got)
+		}
+	})
+}
+
+// TestNoBusinessContactNotice covers the urgent notice's fixed subject and
+// its body's Account Owner line, which names the Account Manager.
+func TestNoBusinessContactNotice(t *testing.T) {
+	d := Data{Notice: notify.Notice{
+		ProjectName: "HFC Subscription - Subscription",
+		ProjectKey:  "HFCSUB",
+		Window:      closure.NoticeWindow7,
+		Recipients:  notify.Recipients{AccountOwner: recipients.Contact{Name: "Dana AM"}},
+	}}
+	subject, body := render(t, KindNoBusinessContact, d)
+	if want := "[Urgent] [ACP] No Business Contacts Specified for Project HFC Subscription - Subscription"; subject != want {
+		t.Errorf("subject = %q, want %q", subject, want)
+	}
+	for _, want := range []string{"Project Key: HFCSUB", "Account Owner: Dana AM", "Start Date: \n"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q, got:\n%s", want, body)
+		}
+	}
+}
+
+// validSet returns a minimal valid template set, every required kind and
+// window present, for Load's validation tests to break one piece of.
+func validSet() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for kind, ws := range windows {
+		for _, w := range ws {
+			fsys[key{kind: kind, window: w}.String()+".tmpl"] = &fstest.MapFile{
+				Data: []byte(`{{define "subject"}}{{.ProjectName}} {{.Window}}{{end}}{{define "body"}}Hi {{.Recipients.AccountOwner.Name}}{{end}}`),
+			}
+		}
+	}
+	return fsys
+}
+
+func TestLoad_Validates(t *testing.T) {
+	if _, err := Load(validSet()); err != nil {
+		t.Fatalf("Load(valid set) error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(fstest.MapFS)
+		wantErr string
+	}{
+		{"missing window", func(f fstest.MapFS) { delete(f, "customer_7.tmpl") }, "customer_7.tmpl: missing"},
+		{"unknown field", func(f fstest.MapFS) {
+			f["internal_90.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}{{.ProjectTitle}}{{end}}{{define "body"}}{{end}}`)}
+		}, "ProjectTitle"},
+		{"no body", func(f fstest.MapFS) {
+			f["internal_90.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}x{{end}}`)}
+		}, `no "body" template`},
+		{"multi-line subject", func(f fstest.MapFS) {
+			f["internal_90.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}a{{"\n"}}b{{end}}{{define "body"}}{{end}}`)}
+		}, "more than one line"},
+		{"unsent window", func(f fstest.MapFS) { f["customer_90.tmpl"] = f["customer_7.tmpl"] }, "no customer notice is sent for a 90-day window"},
+		{"unrecognized name", func(f fstest.MapFS) { f["customer-7.tmpl"] = f["customer_7.tmpl"] }, "not a <kind>_<window>"},
+		{"unmapped locale", func(f fstest.MapFS) { f["customer_7.ja.tmpl"] = f["customer_7.tmpl"] }, `locale "ja" is not mapped`},
+		{"parse error", func(f fstest.MapFS) { f["internal_0.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}`)} }, "internal_0.tmpl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := validSet()
+			tt.edit(fsys)
+			_, err := Load(fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRender_LocaleVariant(t *testing.T) {
+	fsys := validSet()
+	fsys["locales.json"] = &fstest.MapFile{Data: []byte(`{"Japan": "ja"}`)}
+	fsys["customer_7.ja.tmpl"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}プロジェクト {{.ProjectName}}{{end}}{{define "body"}}{{usdate .EndDate}}{{end}}`)}
+	s, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := Data{Notice: notify.Notice{ProjectName: "Acme", Window: closure.NoticeWindow7}}
+
+	if subject, _, _ := s.Render(KindCustomer, "Japan", d); subject != "プロジェクト Acme" {
+		t.Errorf("Japan subject = %q, want the ja variant", subject)
+	}
+	if subject, _, _ := s.Render(KindCustomer, "EMEA", d); subject != "Acme 7" {
+		t.Errorf("EMEA subject = %q, want the base template", subject)
+	}
+	d.Window = closure.NoticeWindow0
+	if subject, _, _ := s.Render(KindCustomer, "Japan", d); subject != "Acme 0" {
+		t.Errorf("Japan day-0 subject = %q, want the base template (no ja variant)", subject)
+	}
+}
+
+func TestWriteSamples(t *testing.T) {
+	var b strings.Builder
+	if err := MustDefault().WriteSamples(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if n := strings.Count(out, "==== "); n != 13 {
+		t.Errorf("samples = %d, want 13", n)
+	}
+	for _, want := range []string{
+		"==== internal_90.tmpl\nSubject: [ACP] 90 Days Reminder of Project for Acme - Subscription of Acme Corporation\n",
+		"==== customer_0.tmpl\nSubject: Project Suspension Notice - Acme - Subscription\n",
+		"Dear Alex Owner",
+		"will be suspended on 11/09/2026",
+		"==== reinstatement_0.tmpl\nSubject: Project Reinstatement Notice - Acme - Subscription\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("samples missing %q", want)
+		}
+	}
+	if strings.Index(out, "internal_90") > strings.Index(out, "internal_0") || strings.Index(out, "internal_0") > strings.Index(out, "customer_15") {
+		t.Error("samples are not in kind, then widest-window-first order")
+	}
+}
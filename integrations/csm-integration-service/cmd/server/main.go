@@ -25,15 +25,22 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/access"
 	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/entity"
 	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/handler"
 	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/middleware"
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/usage"
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/webhook"
 )
 
+// webhookDeliveryInterval is how often due webhook deliveries are sent.
+const webhookDeliveryInterval = 5 * time.Second
+
 func main() {
 	loadDotEnv(".env")
 	middleware.ConfigureLogger()
@@ -51,17 +58,56 @@ func main() {
 	accountHandler := handler.NewAccountHandler(entityClient)
 	projectHandler := handler.NewProjectHandler(entityClient)
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	identity := access.Identity{
+		Claim:  envOrDefault("ACCESS_CLIENT_CLAIM", "azp"),
+		Header: os.Getenv("ACCESS_CLIENT_HEADER"),
+	}
+
+	usageStore := openUsageStore(ctx)
+	usageHandler := handler.NewUsageHandler(usageStore)
+	webhookStore := openWebhookStore()
+	webhookHandler := handler.NewWebhookHandler(webhookStore, identity.ClientID)
+
+	routes := []struct {
+		pattern string
+		handler http.HandlerFunc
+	}{
+		{"GET /accounts/{id}", accountHandler.GetAccount},
+		{"POST /accounts/search", accountHandler.SearchAccounts},
+		{"POST /accounts/{id}/contacts/search", accountHandler.SearchAccountContacts},
+		{"GET /projects/{id}", projectHandler.GetProject},
+		{"POST /projects/search", projectHandler.SearchProjects},
+		{"POST /projects/{id}/contacts/search", projectHandler.SearchProjectContacts},
+		{"PATCH /projects/{id}", projectHandler.UpdateProject},
+		{"GET /admin/usage", usageHandler.GetUsage},
+		{"POST /webhooks", webhookHandler.CreateWebhook},
+		{"GET /webhooks", webhookHandler.ListWebhooks},
+		{"DELETE /webhooks/{id}", webhookHandler.DeleteWebhook},
+		{"GET /webhooks/{id}/deliveries", webhookHandler.ListDeliveries},
+		{"POST /webhooks/{id}/deliveries/{deliveryID}/retry", webhookHandler.RetryDelivery},
+	}
+	patterns := make([]string, len(routes))
+	for i, rt := range routes {
+		patterns[i] = rt.pattern
+	}
+
+	policy := loadAccessPolicy(ctx, patterns)
+	startWebhooks(ctx, entityClient, webhookStore, policy)
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
-	mux.HandleFunc("GET /accounts/{id}", accountHandler.GetAccount)
-	mux.HandleFunc("POST /accounts/search", accountHandler.SearchAccounts)
-	mux.HandleFunc("POST /accounts/{id}/contacts/search", accountHandler.SearchAccountContacts)
-	mux.HandleFunc("GET /projects/{id}", projectHandler.GetProject)
-	mux.HandleFunc("POST /projects/search", projectHandler.SearchProjects)
-	mux.HandleFunc("POST /projects/{id}/contacts/search", projectHandler.SearchProjectContacts)
-	mux.HandleFunc("PATCH /projects/{id}", projectHandler.UpdateProject)
+	for _, rt := range routes {
+		var h http.Handler = rt.handler
+		if policy != nil {
+			h = policy.Authorize(rt.pattern, identity, h)
+		}
+		mux.Handle(rt.pattern, usageStore.Meter(rt.pattern, identity.ClientID, h))
+	}
 
 	addr := ":" + envOrDefault("PORT", "8080")
 
@@ -72,9 +118,10 @@ func main() {
 	}
 	slog.Info("Integration Service started", "addr", addr)
 
-	// No Auth layer in this middleware chain — inbound requests are trusted at the
+	// No authentication in this middleware chain — callers are authenticated at the
 	// Choreo API Manager gateway (subscription + M2M app auth), not validated again
-	// in this service. See internal/handler's doc comments for the same note.
+	// in this service. Authorization is per route, from ACCESS_POLICY_FILE (see
+	// loadAccessPolicy), since only a route's own pattern says which route it is.
 	srv := &http.Server{
 		Handler: middleware.SecurityHeaders(
 			middleware.CorrelationID(
@@ -87,9 +134,6 @@ func main() {
 		IdleTimeout:       60 * time.Second,
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
-
 	go func() {
 		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			slog.Error("server exited", "err", err)
@@ -106,9 +150,127 @@ func main() {
 		slog.Error("graceful shutdown failed", "err", err)
 		os.Exit(1)
 	}
+	if err := usageStore.Flush(); err != nil {
+		slog.Error("final usage flush failed", "err", err)
+	}
+	if err := webhookStore.Save(); err != nil {
+		slog.Error("final webhook store save failed", "err", err)
+	}
 	slog.Info("Integration Service stopped")
 }
 
+// loadAccessPolicy loads ACCESS_POLICY_FILE, exiting on an invalid one, and
+// keeps it current until ctx is done: reloaded on SIGHUP, and whenever the
+// file changes (checked every ACCESS_POLICY_RELOAD_INTERVAL, default 30s).
+// Returns nil when ACCESS_POLICY_FILE is unset, leaving every route open to
+// every caller the gateway lets through, as before per-client policies.
+func loadAccessPolicy(ctx context.Context, patterns []string) *access.Store {
+	path := os.Getenv("ACCESS_POLICY_FILE")
+	if path == "" {
+		slog.Warn("ACCESS_POLICY_FILE is not set: every route is open to every gateway-authenticated client")
+		return nil
+	}
+	interval, err := time.ParseDuration(envOrDefault("ACCESS_POLICY_RELOAD_INTERVAL", "30s"))
+	if err != nil || interval <= 0 {
+		slog.Error("invalid ACCESS_POLICY_RELOAD_INTERVAL, want a positive duration", "value", os.Getenv("ACCESS_POLICY_RELOAD_INTERVAL"))
+		os.Exit(1)
+	}
+	policy, err := access.Load(path, patterns)
+	if err != nil {
+		slog.Error("invalid access policy", "err", err)
+		os.Exit(1)
+	}
+
+	go policy.Watch(ctx, interval)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				if err := policy.Reload(); err != nil {
+					slog.Error("access policy reload failed, keeping the previous policy", "err", err)
+					continue
+				}
+				slog.Info("access policy reloaded", "path", path)
+			}
+		}
+	}()
+	return policy
+}
+
+// openUsageStore opens the usage counters kept in USAGE_FILE, exiting on an
+// unreadable one, and flushes them every USAGE_FLUSH_INTERVAL (default 1m)
+// until ctx is done, keeping USAGE_RETENTION_DAYS (default 90) days. With
+// USAGE_FILE unset, usage is still metered and reported but lost on restart.
+func openUsageStore(ctx context.Context) *usage.Store {
+	path := os.Getenv("USAGE_FILE")
+	if path == "" {
+		slog.Warn("USAGE_FILE is not set: usage counters are kept in memory only")
+	}
+	retention, err := strconv.Atoi(envOrDefault("USAGE_RETENTION_DAYS", "90"))
+	if err != nil || retention < 1 {
+		slog.Error("invalid USAGE_RETENTION_DAYS, want a positive number of days", "value", os.Getenv("USAGE_RETENTION_DAYS"))
+		os.Exit(1)
+	}
+	interval, err := time.ParseDuration(envOrDefault("USAGE_FLUSH_INTERVAL", "1m"))
+	if err != nil || interval <= 0 {
+		slog.Error("invalid USAGE_FLUSH_INTERVAL, want a positive duration", "value", os.Getenv("USAGE_FLUSH_INTERVAL"))
+		os.Exit(1)
+	}
+	store, err := usage.Open(path, retention)
+	if err != nil {
+		slog.Error("failed to load usage counters", "err", err)
+		os.Exit(1)
+	}
+	go store.Run(ctx, interval)
+	return store
+}
+
+// openWebhookStore opens the webhook subscriptions, deliveries and change
+// snapshot kept in WEBHOOKS_FILE, exiting on an unreadable one. With
+// WEBHOOKS_FILE unset they are kept in memory only, and a restart loses
+// every subscription.
+func openWebhookStore() *webhook.Store {
+	path := os.Getenv("WEBHOOKS_FILE")
+	if path == "" {
+		slog.Warn("WEBHOOKS_FILE is not set: webhook subscriptions are kept in memory only")
+	}
+	store, err := webhook.Open(path)
+	if err != nil {
+		slog.Error("failed to load webhook store", "err", err)
+		os.Exit(1)
+	}
+	return store
+}
+
+// startWebhooks runs change detection every WEBHOOK_POLL_INTERVAL (default
+// 5m) and delivery until ctx is done, dead-lettering a delivery after
+// WEBHOOK_MAX_ATTEMPTS (default 8) failed attempts. policy scopes and
+// redacts what each subscriber is sent; nil sends everything.
+func startWebhooks(ctx context.Context, entityClient *entity.Client, store *webhook.Store, policy *access.Store) {
+	interval, err := time.ParseDuration(envOrDefault("WEBHOOK_POLL_INTERVAL", "5m"))
+	if err != nil || interval <= 0 {
+		slog.Error("invalid WEBHOOK_POLL_INTERVAL, want a positive duration", "value", os.Getenv("WEBHOOK_POLL_INTERVAL"))
+		os.Exit(1)
+	}
+	maxAttempts, err := strconv.Atoi(envOrDefault("WEBHOOK_MAX_ATTEMPTS", "8"))
+	if err != nil || maxAttempts < 1 {
+		slog.Error("invalid WEBHOOK_MAX_ATTEMPTS, want a positive number", "value", os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+		os.Exit(1)
+	}
+
+	var p webhook.Policy
+	if policy != nil {
+		p = policy
+	}
+	go webhook.NewDetector(entityClient, store, p).Run(ctx, interval)
+	go webhook.NewDispatcher(store, maxAttempts).Run(ctx, webhookDeliveryInterval)
+}
+
 func mustEnv(key string) string {
 	v := os.Getenv(key)
 	if v == "" {
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package access
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// assertion builds an unsigned JWT carrying claims (a JSON object).
+func assertion(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(claims)) + ".sig"
+}
+
+func TestAuthorize(t *testing.T) {
+	s, err := Load(writePolicy(t, testPolicy), testRoutes)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	id := Identity{Claim: "azp", Header: "X-Consumer-ID"}
+
+	tests := []struct {
+		name    string
+		route   string
+		headers map[string]string
+		want    int
+		client  string
+	}{
+		{"allowed via JWT", "GET /projects/{id}", map[string]string{"x-jwt-assertion": assertion(`{"azp":"acp"}`)}, http.StatusOK, "acp"},
+		{"route not allowed", "POST /projects/search", map[string]string{"x-jwt-assertion": assertion(`{"azp":"acp"}`)}, http.StatusForbidden, ""},
+		{"unknown client", "GET /projects/{id}", map[string]string{"x-jwt-assertion": assertion(`{"azp":"stranger"}`)}, http.StatusForbidden, ""},
+		{"JWT without the claim", "GET /projects/{id}", map[string]string{"x-jwt-assertion": assertion(`{"sub":"acp"}`)}, http.StatusUnauthorized, ""},
+		{"malformed JWT", "GET /projects/{id}", map[string]string{"x-jwt-assertion": "not-a-jwt"}, http.StatusUnauthorized, ""},
+		{"allowed via header", "POST /projects/search", map[string]string{"X-Consumer-ID": "reporting"}, http.StatusOK, "reporting"},
+		{"no identity", "GET /projects/{id}", nil, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *Client
+			h := s.Authorize(tt.route, id, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientFromContext(r.Context())
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body: %s", w.Code, tt.want, w.Body)
+			}
+			if tt.client != "" && (got == nil || got.ID != tt.client) {
+				t.Errorf("client in context = %+v, want %q", got, tt.client)
+			}
+		})
+	}
+}
+
+// TestAuthorize_RateLimit verifies a client over its route's limit gets 429
+// with Retry-After, while its unlimited routes and other clients don't.
+func TestAuthorize_RateLimit(t *testing.T) {
+	s, err := Load(writePolicy(t, `{"clients":{
+		"partner": {"routes":["GET /projects/{id}","POST /projects/search"], "limits":{"POST /projects/search":{"rate":0.01,"burst":2}}},
+		"reporting": {"routes":["POST /projects/search"]}
+	}}`), testRoutes)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	id := Identity{Header: "X-Consumer-ID"}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	call := func(client, route string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Consumer-ID", client)
+		w := httptest.NewRecorder()
+		s.Authorize(route, id, ok).ServeHTTP(w, r)
+		return w
+	}
+
+	for i := range 2 {
+		if w := call("partner", "POST /projects/search"); w.Code != http.StatusOK {
+			t.Fatalf("call %d within burst: status = %d, want 200", i+1, w.Code)
+		}
+	}
+	w := call("partner", "POST /projects/search")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("call over burst: status = %d, want 429", w.Code)
+	}
+	if secs, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || secs < 1 {
+		t.Errorf("Retry-After = %q, want a positive number of seconds", w.Header().Get("Retry-After"))
+	}
+
+	if w := call("partner", "GET /projects/{id}"); w.Code != http.StatusOK {
+		t.Errorf("unlimited route: status = %d, want 200", w.Code)
+	}
+	if w := call("reporting", "POST /projects/search"); w.Code != http.StatusOK {
+		t.Errorf("other client: status = %d, want 200", w.Code)
+	}
+}
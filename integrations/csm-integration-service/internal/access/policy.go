@@ -0,0 +1,478 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package access applies a per-client policy to inbound calls: which routes
+// each consumer may call and how often, which account and project fields are
+// redacted from what it gets back, and which projects it may see at all. The gateway is
+// still what authenticates a consumer; this package only decides what an
+// already-authenticated consumer is allowed, keyed on the client ID the
+// gateway forwards.
+package access
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// anyRoute is the limits key for every route a client has no limit of its
+// own for.
+const anyRoute = "*"
+
+// policyFile is the ACCESS_POLICY_FILE layout: one entry per client ID.
+type policyFile struct {
+	Clients map[string]clientPolicy `json:"clients"`
+}
+
+type clientPolicy struct {
+	// Routes are the ServeMux patterns the client may call, e.g.
+	// "GET /projects/{id}".
+	Routes []string `json:"routes"`
+	Redact struct {
+		// Account and Project are dotted field paths (e.g.
+		// "accountManager.email") removed from every account and project
+		// the client is sent.
+		Account []string `json:"account"`
+		Project []string `json:"project"`
+	} `json:"redact"`
+	// Projects limits which projects the client may see; omitted means
+	// every project.
+	Projects *struct {
+		// Tiers are account tiers ("basic", "enterprise"): a project whose
+		// account is in one is visible.
+		Tiers []string `json:"tiers"`
+		// IDs are project IDs visible whatever their tier.
+		IDs []string `json:"ids"`
+	} `json:"projects"`
+	// Limits are token-bucket limits by route, "*" for any route without
+	// its own; a route with neither is unlimited.
+	Limits map[string]limit `json:"limits"`
+}
+
+// limit is one token bucket: Rate requests per second, up to Burst back to
+// back.
+type limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Client is one consumer's compiled policy. A nil *Client — no policy
+// configured — allows everything and redacts nothing, so handlers can call
+// its methods unconditionally.
+type Client struct {
+	ID            string
+	routes        map[string]bool
+	redactAccount [][]string
+	redactProject [][]string
+	// scoped is set when the policy limits projects; tiers and projectIDs
+	// are then what a project must match one of.
+	scoped     bool
+	tiers      map[string]bool
+	projectIDs map[string]bool
+	limits     map[string]limit
+}
+
+// Store holds the policy loaded from a file and reloads it on demand. A
+// reload that fails keeps the previous policy in force.
+type Store struct {
+	path   string
+	routes map[string]bool
+
+	clients atomic.Pointer[map[string]*Client]
+
+	mu      sync.Mutex
+	modTime time.Time
+
+	// limiters holds one token bucket per client and route, kept across
+	// reloads so a policy edit doesn't refill every bucket.
+	limitersMu sync.Mutex
+	limiters   map[string]*rate.Limiter
+}
+
+// Load reads and validates the policy at path. routes are the patterns the
+// server serves; a policy naming any other route is rejected, so a typo
+// fails the load rather than silently denying a client.
+func Load(path string, routes []string) (*Store, error) {
+	s := &Store{path: path, routes: map[string]bool{}, limiters: map[string]*rate.Limiter{}}
+	for _, r := range routes {
+		s.routes[r] = true
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the policy file and, if it is valid, swaps it in.
+func (s *Store) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("access: stat %s: %w", s.path, err)
+	}
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("access: read %s: %w", s.path, err)
+	}
+	clients, err := s.compile(raw)
+	if err != nil {
+		return err
+	}
+	s.clients.Store(&clients)
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// Watch reloads the policy whenever the file's modification time changes,
+// checking every interval until ctx is done. Reload errors are logged and
+// the previous policy stays in force.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(s.path)
+		if err != nil {
+			slog.Error("access policy check failed", "path", s.path, "err", err)
+			continue
+		}
+		s.mu.Lock()
+		changed := !info.ModTime().Equal(s.modTime)
+		s.mu.Unlock()
+		if !changed {
+			continue
+		}
+		if err := s.Reload(); err != nil {
+			slog.Error("access policy reload failed, keeping the previous policy", "path", s.path, "err", err)
+			continue
+		}
+		slog.Info("access policy reloaded", "path", s.path)
+	}
+}
+
+// Client returns the policy for id, or false if the policy does not name
+// it.
+func (s *Store) Client(id string) (*Client, bool) {
+	c, ok := (*s.clients.Load())[id]
+	return c, ok
+}
+
+func (s *Store) compile(raw []byte) (map[string]*Client, error) {
+	var f policyFile
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("access: parse %s: %w", s.path, err)
+	}
+
+	clients := make(map[string]*Client, len(f.Clients))
+	for id, p := range f.Clients {
+		if id == "" {
+			return nil, fmt.Errorf("access: %s: empty client ID", s.path)
+		}
+		c := &Client{ID: id, routes: map[string]bool{}}
+		for _, r := range p.Routes {
+			if !s.routes[r] {
+				return nil, fmt.Errorf("access: %s: client %q: unknown route %q", s.path, id, r)
+			}
+			c.routes[r] = true
+		}
+		var err error
+		if c.redactAccount, err = fieldPaths(p.Redact.Account); err != nil {
+			return nil, fmt.Errorf("access: %s: client %q: %w", s.path, id, err)
+		}
+		if c.redactProject, err = fieldPaths(p.Redact.Project); err != nil {
+			return nil, fmt.Errorf("access: %s: client %q: %w", s.path, id, err)
+		}
+		for r, l := range p.Limits {
+			if r != anyRoute && !s.routes[r] {
+				return nil, fmt.Errorf("access: %s: client %q: limit for unknown route %q", s.path, id, r)
+			}
+			if l.Rate <= 0 || l.Burst < 1 {
+				return nil, fmt.Errorf("access: %s: client %q: limit for %q needs a positive rate and a burst of at least 1", s.path, id, r)
+			}
+		}
+		c.limits = p.Limits
+		if p.Projects != nil {
+			c.scoped = true
+			c.tiers, c.projectIDs = map[string]bool{}, map[string]bool{}
+			for _, t := range p.Projects.Tiers {
+				c.tiers[strings.ToLower(t)] = true
+			}
+			for _, pid := range p.Projects.IDs {
+				c.projectIDs[strings.ToLower(pid)] = true
+			}
+		}
+		clients[id] = c
+	}
+	return clients, nil
+}
+
+func fieldPaths(paths []string) ([][]string, error) {
+	out := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		segs := strings.Split(p, ".")
+		for _, seg := range segs {
+			if seg == "" {
+				return nil, fmt.Errorf("invalid redact path %q", p)
+			}
+		}
+		out = append(out, segs)
+	}
+	return out, nil
+}
+
+// limiter returns the token bucket limiting c's calls to route, or nil if
+// they are unlimited. A bucket whose limit a reload changed is updated in
+// place.
+func (s *Store) limiter(c *Client, route string) *rate.Limiter {
+	l, ok := c.limits[route]
+	if !ok {
+		if l, ok = c.limits[anyRoute]; !ok {
+			return nil
+		}
+		route = anyRoute
+	}
+
+	key := c.ID + " " + route
+	s.limitersMu.Lock()
+	defer s.limitersMu.Unlock()
+	lim, ok := s.limiters[key]
+	if !ok {
+		lim = rate.NewLimiter(rate.Limit(l.Rate), l.Burst)
+		s.limiters[key] = lim
+		return lim
+	}
+	if lim.Limit() != rate.Limit(l.Rate) {
+		lim.SetLimit(rate.Limit(l.Rate))
+	}
+	if lim.Burst() != l.Burst {
+		lim.SetBurst(l.Burst)
+	}
+	return lim
+}
+
+// Allows reports whether the client may call route.
+func (c *Client) Allows(route string) bool {
+	return c == nil || c.routes[route]
+}
+
+// Scoped reports whether the client sees only some projects, so a handler
+// acting on a project by ID has to fetch it to check first.
+func (c *Client) Scoped() bool {
+	return c != nil && c.scoped
+}
+
+// sees reports whether the client may see the project with projectID
+// whose account is in tier.
+func (c *Client) sees(projectID, tier string) bool {
+	if !c.Scoped() {
+		return true
+	}
+	return c.projectIDs[strings.ToLower(projectID)] || c.tiers[strings.ToLower(tier)]
+}
+
+// SeesProject reports whether the client may see the project in raw, a
+// single project payload.
+func (c *Client) SeesProject(raw []byte) (bool, error) {
+	if !c.Scoped() {
+		return true, nil
+	}
+	var p struct {
+		ID      string `json:"id"`
+		Account *struct {
+			Tier string `json:"tier"`
+		} `json:"account"`
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return false, fmt.Errorf("access: parse project: %w", err)
+	}
+	tier := ""
+	if p.Account != nil {
+		tier = p.Account.Tier
+	}
+	return c.sees(p.ID, tier), nil
+}
+
+// RedactAccount removes the client's redacted account fields from raw, a
+// single account payload.
+func (c *Client) RedactAccount(raw []byte) ([]byte, error) {
+	if c == nil || len(c.redactAccount) == 0 {
+		return raw, nil
+	}
+	return rewrite(raw, func(v map[string]any) map[string]any {
+		return redact(v, c.redactAccount)
+	})
+}
+
+// RedactAccounts is RedactAccount for each account of a search response.
+func (c *Client) RedactAccounts(raw []byte) ([]byte, error) {
+	if c == nil || len(c.redactAccount) == 0 {
+		return raw, nil
+	}
+	return rewriteList(raw, "accounts", func(v map[string]any) (map[string]any, error) {
+		return redact(v, c.redactAccount), nil
+	})
+}
+
+// RedactProject removes the client's redacted project fields from raw, a
+// single project payload.
+func (c *Client) RedactProject(raw []byte) ([]byte, error) {
+	if c == nil || len(c.redactProject) == 0 {
+		return raw, nil
+	}
+	return rewrite(raw, func(v map[string]any) map[string]any {
+		return redact(v, c.redactProject)
+	})
+}
+
+// FilterProjects drops the projects the client may not see from raw, a
+// search response page, and redacts the rest, leaving total and hasMore as
+// they were: a scoped client's caller pages through every upstream page and
+// counts what is left itself (see handler.ProjectHandler.SearchProjects).
+// A search item's account carries no tier, so a tier scope is checked
+// against the one tiers looks up.
+func (c *Client) FilterProjects(ctx context.Context, raw []byte, tiers *Tiers) ([]byte, error) {
+	if c == nil || (!c.scoped && len(c.redactProject) == 0) {
+		return raw, nil
+	}
+	return rewriteList(raw, "projects", func(v map[string]any) (map[string]any, error) {
+		visible, err := c.seesSearched(ctx, v, tiers)
+		if err != nil || !visible {
+			return nil, err
+		}
+		return redact(v, c.redactProject), nil
+	})
+}
+
+// SeesSearchedProject reports whether the client may see the project in
+// raw, a single project search item, looking its tier up in tiers.
+func (c *Client) SeesSearchedProject(ctx context.Context, raw []byte, tiers *Tiers) (bool, error) {
+	if !c.Scoped() {
+		return true, nil
+	}
+	v, err := decodeObject(raw)
+	if err != nil {
+		return false, err
+	}
+	return c.seesSearched(ctx, v, tiers)
+}
+
+// seesSearched is SeesSearchedProject for a decoded search item. Only a
+// project outside the client's ids costs a tier lookup, and only when the
+// client is scoped on tiers at all.
+func (c *Client) seesSearched(ctx context.Context, v map[string]any, tiers *Tiers) (bool, error) {
+	id, _ := v["id"].(string)
+	if c.sees(id, "") {
+		return true, nil
+	}
+	account, _ := v["account"].(map[string]any)
+	accountID, _ := account["id"].(string)
+	if len(c.tiers) == 0 || accountID == "" {
+		return false, nil
+	}
+	tier, err := tiers.tier(ctx, id, accountID)
+	if err != nil {
+		return false, err
+	}
+	return c.sees(id, tier), nil
+}
+
+// rewrite decodes raw as one object, applies fn and re-encodes it.
+func rewrite(raw []byte, fn func(map[string]any) map[string]any) ([]byte, error) {
+	v, err := decodeObject(raw)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fn(v))
+}
+
+// rewriteList applies fn to each object in raw's key array, dropping those
+// fn returns nil for, and stopping at fn's first error.
+func rewriteList(raw []byte, key string, fn func(map[string]any) (map[string]any, error)) ([]byte, error) {
+	v, err := decodeObject(raw)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := v[key].([]any)
+	kept := make([]any, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		obj, err := fn(obj)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			kept = append(kept, obj)
+		}
+	}
+	if _, ok := v[key]; ok {
+		v[key] = kept
+	}
+	return json.Marshal(v)
+}
+
+func decodeObject(raw []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v map[string]any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("access: parse response: %w", err)
+	}
+	return v, nil
+}
+
+// redact deletes each path from v. A path crossing an array applies to
+// every element of it.
+func redact(v map[string]any, paths [][]string) map[string]any {
+	for _, p := range paths {
+		deletePath(v, p)
+	}
+	return v
+}
+
+func deletePath(v any, path []string) {
+	switch node := v.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			delete(node, path[0])
+			return
+		}
+		deletePath(node[path[0]], path[1:])
+	case []any:
+		for _, el := range node {
+			deletePath(el, path)
+		}
+	}
+}
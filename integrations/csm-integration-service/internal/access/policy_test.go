@@ -0,0 +1,216 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package access
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRoutes = []string{"GET /accounts/{id}", "GET /projects/{id}", "POST /projects/search", "PATCH /projects/{id}"}
+
+const testPolicy = `{
+  "clients": {
+    "reporting": {
+      "routes": ["GET /accounts/{id}", "GET /projects/{id}", "POST /projects/search"],
+      "redact": {
+        "account": ["accountManager.email", "arrToday"],
+        "project": ["account.tier", "contacts.email"]
+      },
+      "projects": {"tiers": ["Basic"], "ids": ["22222222-2222-2222-2222-222222222222"]}
+    },
+    "acp": {"routes": ["GET /projects/{id}", "PATCH /projects/{id}"]}
+  }
+}`
+
+// writePolicy writes policy to a temp file and returns its path.
+func writePolicy(t *testing.T, policy string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadTestClient(t *testing.T, id string) *Client {
+	t.Helper()
+	s, err := Load(writePolicy(t, testPolicy), testRoutes)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	c, ok := s.Client(id)
+	if !ok {
+		t.Fatalf("Client(%q) not found", id)
+	}
+	return c
+}
+
+func TestLoad_Validates(t *testing.T) {
+	tests := []struct {
+		name, policy, wantErr string
+	}{
+		{"unknown route", `{"clients":{"a":{"routes":["DELETE /projects/{id}"]}}}`, "unknown route"},
+		{"bad redact path", `{"clients":{"a":{"redact":{"project":["account..tier"]}}}}`, "invalid redact path"},
+		{"unknown field", `{"clients":{"a":{"route":["GET /projects/{id}"]}}}`, "unknown field"},
+		{"empty client ID", `{"clients":{"":{}}}`, "empty client ID"},
+		{"limit for unknown route", `{"clients":{"a":{"limits":{"DELETE /projects/{id}":{"rate":1,"burst":1}}}}}`, "limit for unknown route"},
+		{"zero burst", `{"clients":{"a":{"limits":{"*":{"rate":1,"burst":0}}}}}`, "burst of at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writePolicy(t, tt.policy), testRoutes)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestReload_KeepsPreviousPolicyOnError verifies a valid edit takes effect
+// and an invalid one leaves the last good policy in force.
+func TestReload_KeepsPreviousPolicyOnError(t *testing.T) {
+	path := writePolicy(t, testPolicy)
+	s, err := Load(path, testRoutes)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"clients":{"acp":{"routes":["GET /projects/{id}"]}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if c, ok := s.Client("acp"); !ok || c.Allows("PATCH /projects/{id}") {
+		t.Errorf("after reload, acp may still PATCH")
+	}
+	if _, ok := s.Client("reporting"); ok {
+		t.Errorf("after reload, removed client is still known")
+	}
+
+	if err := os.WriteFile(path, []byte(`{not json`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err == nil {
+		t.Fatal("Reload() of invalid policy error = nil")
+	}
+	if c, ok := s.Client("acp"); !ok || !c.Allows("GET /projects/{id}") {
+		t.Errorf("invalid reload replaced the previous policy")
+	}
+}
+
+func TestClient_SeesProject(t *testing.T) {
+	c := loadTestClient(t, "reporting")
+	tests := []struct {
+		name, project string
+		want          bool
+	}{
+		{"allowed tier", `{"id":"11111111-1111-1111-1111-111111111111","account":{"tier":"basic"}}`, true},
+		{"other tier", `{"id":"11111111-1111-1111-1111-111111111111","account":{"tier":"enterprise"}}`, false},
+		{"listed ID", `{"id":"22222222-2222-2222-2222-222222222222","account":{"tier":"enterprise"}}`, true},
+		{"no account", `{"id":"11111111-1111-1111-1111-111111111111"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.SeesProject([]byte(tt.project))
+			if err != nil || got != tt.want {
+				t.Errorf("SeesProject() = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	if ok, _ := loadTestClient(t, "acp").SeesProject([]byte(`{"id":"x","account":{"tier":"enterprise"}}`)); !ok {
+		t.Error("unscoped client does not see every project")
+	}
+	var none *Client
+	if ok, _ := none.SeesProject([]byte(`{}`)); !ok || !none.Allows("PATCH /projects/{id}") {
+		t.Error("nil client is restricted")
+	}
+}
+
+func TestClient_Redact(t *testing.T) {
+	c := loadTestClient(t, "reporting")
+
+	out, err := c.RedactAccount([]byte(`{"id":"a1","arrToday":"1000","accountManager":{"name":"Sam","email":"sam@wso2.example"}}`))
+	if err != nil {
+		t.Fatalf("RedactAccount() error = %v", err)
+	}
+	if s := string(out); strings.Contains(s, "arrToday") || strings.Contains(s, "sam@wso2.example") || !strings.Contains(s, `"name":"Sam"`) {
+		t.Errorf("RedactAccount() = %s", s)
+	}
+
+	out, err = c.RedactProject([]byte(`{"id":"p1","account":{"id":"a1","tier":"basic"},"contacts":[{"name":"Pat","email":"pat@customer.example"}],"count":12345678901234567890}`))
+	if err != nil {
+		t.Fatalf("RedactProject() error = %v", err)
+	}
+	if s := string(out); strings.Contains(s, "tier") || strings.Contains(s, "pat@customer.example") || !strings.Contains(s, "12345678901234567890") {
+		t.Errorf("RedactProject() = %s, want tier and contact emails removed, numbers intact", s)
+	}
+}
+
+// TestClient_FilterProjects verifies search results keep only the visible
+// projects, redacted, and leave the rest of the response alone. Search
+// items carry only the account's {id, name}; the tier comes from the
+// project lookup, once per account.
+func TestClient_FilterProjects(t *testing.T) {
+	c := loadTestClient(t, "reporting")
+	var looked []string
+	tiers := NewTiers(func(_ context.Context, id string) ([]byte, error) {
+		looked = append(looked, id)
+		tier := map[string]string{"p1": "basic", "p2": "enterprise", "p3": "basic"}[id]
+		return []byte(`{"id":"` + id + `","account":{"id":"x","name":"X","tier":"` + tier + `"}}`), nil
+	}, time.Minute)
+	out, err := c.FilterProjects(context.Background(), []byte(`{"projects":[
+		{"id":"p1","account":{"id":"a1","name":"Acme"}},
+		{"id":"p2","account":{"id":"a2","name":"Globex"}},
+		{"id":"p3","account":{"id":"a1","name":"Acme"}},
+		{"id":"22222222-2222-2222-2222-222222222222","account":{"id":"a2","name":"Globex"}}
+	],"total":4,"hasMore":false}`), tiers)
+	if err != nil {
+		t.Fatalf("FilterProjects() error = %v", err)
+	}
+	var resp struct {
+		Projects []map[string]any `json:"projects"`
+		Total    int              `json:"total"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Projects) != 3 || resp.Projects[0]["id"] != "p1" || resp.Projects[1]["id"] != "p3" || resp.Total != 4 {
+		t.Errorf("FilterProjects() = %s", out)
+	}
+	if strings.Join(looked, ",") != "p1,p2" {
+		t.Errorf("tier lookups = %v, want one per account and none for a listed id", looked)
+	}
+
+	tiers.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := c.FilterProjects(context.Background(), []byte(`{"projects":[{"id":"p3","account":{"id":"a1"}}]}`), tiers); err != nil || len(looked) != 3 {
+		t.Errorf("after the TTL: lookups = %v, err %v; want the tier looked up again", looked, err)
+	}
+
+	failing := NewTiers(func(context.Context, string) ([]byte, error) { return nil, errors.New("upstream down") }, time.Minute)
+	if _, err := c.FilterProjects(context.Background(), []byte(`{"projects":[{"id":"p1","account":{"id":"a1"}}]}`), failing); err == nil {
+		t.Error("FilterProjects() with the tier lookup failing = nil error, want it reported")
+	}
+}
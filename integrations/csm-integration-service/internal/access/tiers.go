@@ -0,0 +1,88 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package access
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTierTTL is how long a looked-up account tier is trusted. A tier
+// changes with a contract, not between two searches.
+const DefaultTierTTL = 10 * time.Minute
+
+// Tiers looks up the account tiers a tier-scoped client's search results are
+// filtered on. A project search item's account is only {id, name}; the tier
+// is on GET /projects/{id}'s account. It is an account's, not a project's,
+// so one lookup answers for every project of that account until the TTL
+// passes. Safe for concurrent use.
+type Tiers struct {
+	getProject func(ctx context.Context, id string) ([]byte, error)
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	byAccount map[string]cachedTier
+}
+
+type cachedTier struct {
+	tier    string
+	expires time.Time
+}
+
+// NewTiers returns a Tiers reading projects with getProject and keeping each
+// account's tier for ttl.
+func NewTiers(getProject func(ctx context.Context, id string) ([]byte, error), ttl time.Duration) *Tiers {
+	return &Tiers{getProject: getProject, ttl: ttl, now: time.Now, byAccount: map[string]cachedTier{}}
+}
+
+// tier returns the tier of accountID, reading it off the project with
+// projectID, one of that account's, when it isn't cached.
+func (t *Tiers) tier(ctx context.Context, projectID, accountID string) (string, error) {
+	now := t.now()
+	t.mu.Lock()
+	cached, ok := t.byAccount[accountID]
+	t.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.tier, nil
+	}
+
+	raw, err := t.getProject(ctx, projectID)
+	if err != nil {
+		return "", fmt.Errorf("access: look up tier of project %s: %w", projectID, err)
+	}
+	var p struct {
+		Account *struct {
+			Tier string `json:"tier"`
+		} `json:"account"`
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return "", fmt.Errorf("access: parse project %s: %w", projectID, err)
+	}
+	tier := ""
+	if p.Account != nil {
+		tier = p.Account.Tier
+	}
+
+	t.mu.Lock()
+	t.byAccount[accountID] = cachedTier{tier: tier, expires: now.Add(t.ttl)}
+	t.mu.Unlock()
+	return tier, nil
+}
@@ -22,6 +22,8 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/access"
 )
 
 // entityAccountClient abstracts the entity service account operations used by AccountHandler.
@@ -33,9 +35,10 @@ type entityAccountClient interface {
 
 // AccountHandler handles HTTP requests for account operations, delegating to the
 // entity service for data access. There is no end-user identity to check here —
-// this service has no inbound auth of its own (see the package doc note on
-// response.go); the trust boundary is Choreo's API Manager gateway, which fronts
-// this service for its M2M/third-party consumers.
+// the trust boundary is Choreo's API Manager gateway, which fronts this service
+// for its M2M/third-party consumers. What a consumer may call, and which account
+// fields it is sent, is the access policy's (see internal/access): accounts are
+// redacted per client, but not scoped.
 type AccountHandler struct {
 	entity entityAccountClient
 }
@@ -60,7 +63,7 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	writeFiltered(w, r, result, access.ClientFromContext(r.Context()).RedactAccount)
 }
 
 // SearchAccounts handles POST /accounts/search.
@@ -88,7 +91,7 @@ func (h *AccountHandler) SearchAccounts(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	writeFiltered(w, r, result, access.ClientFromContext(r.Context()).RedactAccounts)
 }
 
 // SearchAccountContacts handles POST /accounts/{id}/contacts/search.
@@ -279,3 +279,34 @@ func TestSearchAccountContacts(t *testing.T) {
 		}
 	})
 }
+
+func TestAccountHandler_AccessPolicyRedacts(t *testing.T) {
+	const policy = `{"clients":{"c1":{"redact":{"account":["accountManager.email"]}}}}`
+	const account = `{"id":"a1","accountManager":{"name":"Sam","email":"sam@wso2.example"}}`
+	h := NewAccountHandler(&mockEntityAccountClient{
+		getAccountFn: func(_ context.Context, _ string) ([]byte, error) {
+			return []byte(account), nil
+		},
+		searchAccountsFn: func(_ context.Context, _ []byte) ([]byte, error) {
+			return []byte(`{"accounts":[` + account + `],"total":1}`), nil
+		},
+	})
+
+	const accountID = "11111111-1111-1111-1111-111111111111"
+	r := withPolicyClient(t, httptest.NewRequest(http.MethodGet, "/accounts/"+accountID, nil), policy)
+	r.SetPathValue("id", accountID)
+	w := httptest.NewRecorder()
+	h.GetAccount(w, r)
+	assertStatus(t, w, http.StatusOK)
+	if body := w.Body.String(); strings.Contains(body, "sam@wso2.example") || !strings.Contains(body, "Sam") {
+		t.Errorf("GetAccount body = %s, want the manager's email redacted", body)
+	}
+
+	r = withPolicyClient(t, httptest.NewRequest(http.MethodPost, "/accounts/search", strings.NewReader(`{}`)), policy)
+	w = httptest.NewRecorder()
+	h.SearchAccounts(w, r)
+	assertStatus(t, w, http.StatusOK)
+	if body := w.Body.String(); strings.Contains(body, "sam@wso2.example") || !strings.Contains(body, `"total":1`) {
+		t.Errorf("SearchAccounts body = %s, want the manager's email redacted", body)
+	}
+}
@@ -22,8 +22,11 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/access"
 	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/apierror"
 )
 
@@ -94,6 +97,28 @@ func upstreamErrors(fallback string) []upstreamErrorCase {
 	}
 }
 
+// ----- access policy -----
+
+// withPolicyClient returns r carrying the access.Client that policy (an
+// ACCESS_POLICY_FILE document with a single client "c1") gives "c1", as
+// access.Authorize would.
+func withPolicyClient(t *testing.T, r *http.Request, policy string) *http.Request {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := access.Load(path, nil)
+	if err != nil {
+		t.Fatalf("access.Load() error = %v", err)
+	}
+	c, ok := store.Client("c1")
+	if !ok {
+		t.Fatal(`policy has no client "c1"`)
+	}
+	return r.WithContext(access.WithClient(r.Context(), c))
+}
+
 // ----- mock entity account client -----
 
 type mockEntityAccountClient struct {
@@ -22,6 +22,8 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/access"
 )
 
 // entityProjectClient abstracts the entity service project operations used by ProjectHandler.
@@ -35,14 +37,19 @@ type entityProjectClient interface {
 // ProjectHandler handles HTTP requests for project operations, delegating to the
 // entity service for data access. See AccountHandler's doc comment: there is no
 // end-user identity checked here — Choreo's API Manager gateway is the trust
-// boundary for this service's M2M/third-party consumers.
+// boundary for this service's M2M/third-party consumers. A consumer whose access
+// policy scopes projects gets 403 for any other project, and only its own in
+// search results.
 type ProjectHandler struct {
 	entity entityProjectClient
+	// tiers resolves the account tiers a tier-scoped client's search
+	// results are filtered on.
+	tiers *access.Tiers
 }
 
 // NewProjectHandler creates a ProjectHandler backed by the given entity client.
 func NewProjectHandler(entity entityProjectClient) *ProjectHandler {
-	return &ProjectHandler{entity: entity}
+	return &ProjectHandler{entity: entity, tiers: access.NewTiers(entity.GetProject, access.DefaultTierTTL)}
 }
 
 // GetProject handles GET /projects/{id}.
@@ -60,7 +67,18 @@ func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	client := access.ClientFromContext(r.Context())
+	visible, err := client.SeesProject(result)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "access policy could not be applied", "projectID", id, "err", summarizeErr(err))
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+	if !visible {
+		writeError(w, http.StatusForbidden, ErrMsgForbidden)
+		return
+	}
+	writeFiltered(w, r, result, client.RedactProject)
 }
 
 // SearchProjects handles POST /projects/search.
@@ -81,6 +99,12 @@ func (h *ProjectHandler) SearchProjects(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	client := access.ClientFromContext(r.Context())
+	if client.Scoped() {
+		h.searchScoped(w, r, client, body)
+		return
+	}
+
 	result, err := h.entity.SearchProjects(r.Context(), body)
 	if err != nil {
 		slog.ErrorContext(r.Context(), "entity SearchProjects failed", "err", summarizeErr(err))
@@ -88,7 +112,95 @@ func (h *ProjectHandler) SearchProjects(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	writeFiltered(w, r, result, func(raw []byte) ([]byte, error) {
+		return client.FilterProjects(r.Context(), raw, h.tiers)
+	})
+}
+
+// scopedSearchPage is the page size searchScoped reads upstream with:
+// entity-service's maximum.
+const scopedSearchPage = 50
+
+// searchPage is the part of an upstream search response searchScoped reads.
+type searchPage struct {
+	Projects []json.RawMessage `json:"projects"`
+	HasMore  bool              `json:"hasMore"`
+}
+
+// scopedSearchResponse is the search response searchScoped builds.
+type scopedSearchResponse struct {
+	Projects []json.RawMessage `json:"projects"`
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+	HasMore  bool              `json:"hasMore"`
+}
+
+// searchScoped answers a scoped client's search. Filtering upstream pages
+// would leave short pages and a total and hasMore counting projects the
+// client can't see, so it pages through every upstream result for body's
+// filters, keeps what the client may see, and returns the page body asked
+// for of that: total and hasMore count only visible projects.
+func (h *ProjectHandler) searchScoped(w http.ResponseWriter, r *http.Request, client *access.Client, body []byte) {
+	var req map[string]any
+	if err := json.Unmarshal(body, &req); err != nil || req == nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+	var page struct {
+		Pagination struct {
+			Limit  int `json:"limit"`
+			Offset int `json:"offset"`
+		} `json:"pagination"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil || page.Pagination.Limit > scopedSearchPage {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+	limit, offset := page.Pagination.Limit, max(page.Pagination.Offset, 0)
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var visible []json.RawMessage
+	for upstream := 0; ; upstream += scopedSearchPage {
+		req["pagination"] = map[string]int{"limit": scopedSearchPage, "offset": upstream}
+		reqBody, err := json.Marshal(req)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+			return
+		}
+		result, err := h.entity.SearchProjects(r.Context(), reqBody)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "entity SearchProjects failed", "offset", upstream, "err", summarizeErr(err))
+			mapUpstreamError(w, err, "Failed to search projects.")
+			return
+		}
+		var all, kept searchPage
+		filtered, err := client.FilterProjects(r.Context(), result, h.tiers)
+		if err == nil {
+			err = json.Unmarshal(result, &all)
+		}
+		if err == nil {
+			err = json.Unmarshal(filtered, &kept)
+		}
+		if err != nil {
+			slog.ErrorContext(r.Context(), "access policy could not be applied", "err", summarizeErr(err))
+			writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+			return
+		}
+		visible = append(visible, kept.Projects...)
+		if !all.HasMore || len(all.Projects) == 0 {
+			break
+		}
+	}
+
+	out := scopedSearchResponse{Projects: []json.RawMessage{}, Total: len(visible), Limit: limit, Offset: offset}
+	if offset < len(visible) {
+		end := min(offset+limit, len(visible))
+		out.Projects, out.HasMore = visible[offset:end], end < len(visible)
+	}
+	writeValue(w, r, http.StatusOK, out)
 }
 
 // UpdateProject handles PATCH /projects/{id}. Targets a ServiceNow-data-source-only
@@ -122,6 +234,10 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.projectVisible(w, r, id) {
+		return
+	}
+
 	result, err := h.entity.UpdateProject(r.Context(), id, body)
 	if err != nil {
 		slog.ErrorContext(r.Context(), "entity UpdateProject failed", "projectID", id, "err", summarizeErr(err))
@@ -129,7 +245,7 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	writeFiltered(w, r, result, access.ClientFromContext(r.Context()).RedactProject)
 }
 
 // SearchProjectContacts handles POST /projects/{id}/contacts/search.
@@ -158,6 +274,10 @@ func (h *ProjectHandler) SearchProjectContacts(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if !h.projectVisible(w, r, id) {
+		return
+	}
+
 	result, err := h.entity.SearchProjectContacts(r.Context(), id, body)
 	if err != nil {
 		slog.ErrorContext(r.Context(), "entity SearchProjectContacts failed", "projectID", id, "err", summarizeErr(err))
@@ -167,3 +287,30 @@ func (h *ProjectHandler) SearchProjectContacts(w http.ResponseWriter, r *http.Re
 
 	writeJSON(w, http.StatusOK, result)
 }
+
+// projectVisible reports whether the caller may act on project id, writing
+// the error response itself if not. Only a caller whose access policy scopes
+// projects costs the extra GetProject this needs.
+func (h *ProjectHandler) projectVisible(w http.ResponseWriter, r *http.Request, id string) bool {
+	client := access.ClientFromContext(r.Context())
+	if !client.Scoped() {
+		return true
+	}
+	project, err := h.entity.GetProject(r.Context(), id)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "entity GetProject failed", "projectID", id, "err", summarizeErr(err))
+		mapUpstreamError(w, err, "Failed to retrieve project.")
+		return false
+	}
+	visible, err := client.SeesProject(project)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "access policy could not be applied", "projectID", id, "err", summarizeErr(err))
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return false
+	}
+	if !visible {
+		writeError(w, http.StatusForbidden, ErrMsgForbidden)
+		return false
+	}
+	return true
+}
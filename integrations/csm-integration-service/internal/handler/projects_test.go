@@ -18,6 +18,8 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -390,3 +392,108 @@ func TestUpdateProject(t *testing.T) {
 		}
 	})
 }
+
+// scopedPolicy limits client c1 to basic-tier projects and redacts the
+// account tier it was scoped on.
+const scopedPolicy = `{"clients":{"c1":{"projects":{"tiers":["basic"]},"redact":{"project":["account.tier"]}}}}`
+
+func TestProjectHandler_AccessPolicy(t *testing.T) {
+	const projectID = "11111111-1111-1111-1111-111111111111"
+	projectOfTier := func(tier string) func(context.Context, string) ([]byte, error) {
+		return func(_ context.Context, id string) ([]byte, error) {
+			return []byte(`{"id":"` + id + `","account":{"id":"a1","tier":"` + tier + `"}}`), nil
+		}
+	}
+
+	t.Run("GetProject forbids a project outside the scope", func(t *testing.T) {
+		h := NewProjectHandler(&mockEntityProjectClient{getProjectFn: projectOfTier("enterprise")})
+		r := withPolicyClient(t, httptest.NewRequest(http.MethodGet, "/projects/"+projectID, nil), scopedPolicy)
+		r.SetPathValue("id", projectID)
+		w := httptest.NewRecorder()
+		h.GetProject(w, r)
+		assertStatus(t, w, http.StatusForbidden)
+		assertErrorMessage(t, w, ErrMsgForbidden)
+	})
+
+	t.Run("GetProject redacts a project inside the scope", func(t *testing.T) {
+		h := NewProjectHandler(&mockEntityProjectClient{getProjectFn: projectOfTier("basic")})
+		r := withPolicyClient(t, httptest.NewRequest(http.MethodGet, "/projects/"+projectID, nil), scopedPolicy)
+		r.SetPathValue("id", projectID)
+		w := httptest.NewRecorder()
+		h.GetProject(w, r)
+		assertStatus(t, w, http.StatusOK)
+		if strings.Contains(w.Body.String(), "tier") {
+			t.Errorf("body = %s, want account.tier redacted", w.Body)
+		}
+	})
+
+	t.Run("UpdateProject checks the scope before writing", func(t *testing.T) {
+		updated := false
+		h := NewProjectHandler(&mockEntityProjectClient{
+			getProjectFn: projectOfTier("enterprise"),
+			updateProjectFn: func(_ context.Context, _ string, _ []byte) ([]byte, error) {
+				updated = true
+				return []byte(`{}`), nil
+			},
+		})
+		r := withPolicyClient(t, httptest.NewRequest(http.MethodPatch, "/projects/"+projectID, strings.NewReader(`{"endDateClosureState":"Suspended"}`)), scopedPolicy)
+		r.SetPathValue("id", projectID)
+		w := httptest.NewRecorder()
+		h.UpdateProject(w, r)
+		assertStatus(t, w, http.StatusForbidden)
+		if updated {
+			t.Error("UpdateProject reached upstream for a project outside the scope")
+		}
+	})
+
+	t.Run("SearchProjects drops projects outside the scope", func(t *testing.T) {
+		// Two upstream pages of search items, whose account is only
+		// {id, name}; the tier is looked up per account. Projects on even
+		// accounts are basic.
+		var upstream []string
+		h := NewProjectHandler(&mockEntityProjectClient{
+			getProjectFn: func(_ context.Context, id string) ([]byte, error) {
+				var n int
+				fmt.Sscanf(id, "p%d", &n)
+				if n%2 == 0 {
+					return projectOfTier("basic")(context.Background(), id)
+				}
+				return projectOfTier("enterprise")(context.Background(), id)
+			},
+			searchProjectsFn: func(_ context.Context, body []byte) ([]byte, error) {
+				upstream = append(upstream, string(body))
+				var req struct {
+					SearchQuery string `json:"searchQuery"`
+					Pagination  struct {
+						Offset int `json:"offset"`
+					} `json:"pagination"`
+				}
+				_ = json.Unmarshal(body, &req)
+				var items []string
+				for i := req.Pagination.Offset; i < min(req.Pagination.Offset+50, 70); i++ {
+					items = append(items, fmt.Sprintf(`{"id":"p%d","account":{"id":"a%d","name":"Account %d"}}`, i, i, i))
+				}
+				return []byte(fmt.Sprintf(`{"projects":[%s],"total":70,"limit":50,"offset":%d,"hasMore":%t}`,
+					strings.Join(items, ","), req.Pagination.Offset, req.Pagination.Offset+50 < 70)), nil
+			},
+		})
+		r := withPolicyClient(t, httptest.NewRequest(http.MethodPost, "/projects/search",
+			strings.NewReader(`{"searchQuery":"acme","pagination":{"limit":10,"offset":30}}`)), scopedPolicy)
+		w := httptest.NewRecorder()
+		h.SearchProjects(w, r)
+		assertStatus(t, w, http.StatusOK)
+		resp := decodeJSON[struct {
+			Projects []map[string]any `json:"projects"`
+			Total    int              `json:"total"`
+			Limit    int              `json:"limit"`
+			Offset   int              `json:"offset"`
+			HasMore  bool             `json:"hasMore"`
+		}](t, w)
+		if len(resp.Projects) != 5 || resp.Projects[0]["id"] != "p60" || resp.Total != 35 || resp.Limit != 10 || resp.Offset != 30 || resp.HasMore {
+			t.Errorf("response = %+v, want p60..p68 of 35 visible, no more", resp)
+		}
+		if len(upstream) != 2 || !strings.Contains(upstream[0], `"searchQuery":"acme"`) {
+			t.Errorf("upstream searches = %v, want both pages with the caller's filters", upstream)
+		}
+	})
+}
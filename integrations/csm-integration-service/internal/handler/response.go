@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"regexp"
 
@@ -63,6 +64,31 @@ func writeJSON(w http.ResponseWriter, statusCode int, data []byte) {
 	_, _ = w.Write(data) // #nosec G705 -- Content-Type: application/json already set; SecurityHeaders middleware adds X-Content-Type-Options: nosniff
 }
 
+// writeValue writes v, one of this service's own response types, as JSON
+// with the given status code.
+func writeValue(w http.ResponseWriter, r *http.Request, statusCode int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "response encoding failed", "err", err)
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+	writeJSON(w, statusCode, data)
+}
+
+// writeFiltered writes a 200 with result as rewritten by filter — the
+// caller's access-policy redaction or filtering of it (see internal/access).
+// A payload the policy can't be applied to is a 500, never sent unfiltered.
+func writeFiltered(w http.ResponseWriter, r *http.Request, result []byte, filter func([]byte) ([]byte, error)) {
+	out, err := filter(result)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "access policy could not be applied", "err", summarizeErr(err))
+		writeError(w, http.StatusInternalServerError, ErrMsgInternal)
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
 // mapUpstreamError translates an upstream service error to an HTTP response.
 func mapUpstreamError(w http.ResponseWriter, err error, fallbackMsg string) {
 	var apiErr *apierror.Error
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/usage"
+)
+
+// defaultUsageDays is how many days, ending today, a usage report without
+// from covers.
+const defaultUsageDays = 7
+
+// usageReporter abstracts the usage store read by UsageHandler.
+type usageReporter interface {
+	Report(from, to, consumer string) []usage.Row
+}
+
+// UsageHandler serves the usage report. Like every other route, who may
+// call it is the access policy's.
+type UsageHandler struct {
+	usage usageReporter
+	now   func() time.Time
+}
+
+// NewUsageHandler creates a UsageHandler reporting from the given store.
+func NewUsageHandler(usage usageReporter) *UsageHandler {
+	return &UsageHandler{usage: usage, now: time.Now}
+}
+
+// usageReport is the GET /admin/usage response.
+type usageReport struct {
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Usage []usage.Row `json:"usage"`
+}
+
+// GetUsage handles GET /admin/usage?from=YYYY-MM-DD&to=YYYY-MM-DD&consumer=…:
+// per consumer, route and UTC day, inclusive of both dates. to defaults to
+// today and from to the six days before it.
+func (h *UsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	to := h.now().UTC()
+	if v := q.Get("to"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(defaultUsageDays - 1))
+	if v := q.Get("from"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+			return
+		}
+		from = t
+	}
+	if from.After(to) {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+
+	report := usageReport{From: from.Format(time.DateOnly), To: to.Format(time.DateOnly)}
+	report.Usage = h.usage.Report(report.From, report.To, q.Get("consumer"))
+	writeValue(w, r, http.StatusOK, report)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/usage"
+)
+
+// mockUsage records the arguments of its last Report call.
+type mockUsage struct {
+	from, to, consumer string
+}
+
+func (m *mockUsage) Report(from, to, consumer string) []usage.Row {
+	m.from, m.to, m.consumer = from, to, consumer
+	return []usage.Row{{Day: from, Consumer: "partner", Route: "POST /projects/search"}}
+}
+
+func TestGetUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantFrom string
+		wantTo   string
+		consumer string
+	}{
+		{"defaults to the last week", "", http.StatusOK, "2026-10-13", "2026-10-19", ""},
+		{"explicit range and consumer", "?from=2026-09-01&to=2026-09-30&consumer=partner", http.StatusOK, "2026-09-01", "2026-09-30", "partner"},
+		{"to alone", "?to=2026-09-30", http.StatusOK, "2026-09-24", "2026-09-30", ""},
+		{"bad date", "?from=yesterday", http.StatusBadRequest, "", "", ""},
+		{"from after to", "?from=2026-10-02&to=2026-10-01", http.StatusBadRequest, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockUsage{}
+			h := NewUsageHandler(m)
+			h.now = func() time.Time { return time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC) }
+
+			w := httptest.NewRecorder()
+			h.GetUsage(w, httptest.NewRequest(http.MethodGet, "/admin/usage"+tt.query, nil))
+
+			assertStatus(t, w, tt.wantCode)
+			if tt.wantCode != http.StatusOK {
+				assertErrorMessage(t, w, ErrMsgBadRequest)
+				return
+			}
+			assertContentType(t, w, "application/json")
+			report := decodeJSON[usageReport](t, w)
+			if report.From != tt.wantFrom || report.To != tt.wantTo || len(report.Usage) != 1 {
+				t.Errorf("report = %+v, want %s..%s with one row", report, tt.wantFrom, tt.wantTo)
+			}
+			if m.from != tt.wantFrom || m.to != tt.wantTo || m.consumer != tt.consumer {
+				t.Errorf("Report(%q, %q, %q), want (%q, %q, %q)", m.from, m.to, m.consumer, tt.wantFrom, tt.wantTo, tt.consumer)
+			}
+		})
+	}
+}
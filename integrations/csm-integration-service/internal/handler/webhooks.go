@@ -0,0 +1,200 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/webhook"
+)
+
+// webhookStore abstracts the subscription store used by WebhookHandler.
+type webhookStore interface {
+	Subscribe(consumer, url string, events []string, secret string) (webhook.Subscription, error)
+	Subscriptions(consumer string) []webhook.Subscription
+	Unsubscribe(consumer, id string) error
+	Deliveries(consumer, id string, state webhook.DeliveryState) ([]webhook.Delivery, error)
+	Retry(consumer, id, deliveryID string) (webhook.Delivery, error)
+}
+
+// WebhookHandler handles a consumer's webhook subscriptions and their
+// delivery logs. Every call acts on the calling consumer's own
+// subscriptions only: another consumer's is a 404, as if it didn't exist.
+type WebhookHandler struct {
+	store      webhookStore
+	consumerID func(*http.Request) (string, error)
+}
+
+// NewWebhookHandler creates a WebhookHandler backed by store. consumerID
+// identifies the caller, the same way the access policy does.
+func NewWebhookHandler(store webhookStore, consumerID func(*http.Request) (string, error)) *WebhookHandler {
+	return &WebhookHandler{store: store, consumerID: consumerID}
+}
+
+// createWebhookRequest is the POST /webhooks body.
+type createWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// CreateWebhook handles POST /webhooks.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	consumer, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrMsgTooLarge)
+			return
+		}
+		writeError(w, http.StatusBadRequest, errMsgReadBody)
+		return
+	}
+	var req createWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+
+	sub, err := h.store.Subscribe(consumer, req.URL, req.Events, req.Secret)
+	if err != nil {
+		h.writeStoreError(w, r, err, "Failed to create webhook.")
+		return
+	}
+	writeValue(w, r, http.StatusCreated, sub)
+}
+
+// ListWebhooks handles GET /webhooks.
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	consumer, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	subs := h.store.Subscriptions(consumer)
+	if subs == nil {
+		subs = []webhook.Subscription{}
+	}
+	writeValue(w, r, http.StatusOK, struct {
+		Webhooks []webhook.Subscription `json:"webhooks"`
+	}{subs})
+}
+
+// DeleteWebhook handles DELETE /webhooks/{id}. Pending deliveries are
+// dropped along with it.
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	consumer, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("id")
+	if !uuidRe.MatchString(id) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+	if err := h.store.Unsubscribe(consumer, id); err != nil {
+		h.writeStoreError(w, r, err, "Failed to delete webhook.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/{id}/deliveries?state=…: the
+// delivery log, newest first. state=dead is the dead-letter list.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	consumer, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("id")
+	if !uuidRe.MatchString(id) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+	state := webhook.DeliveryState(r.URL.Query().Get("state"))
+	switch state {
+	case "", webhook.StatePending, webhook.StateDelivered, webhook.StateDead:
+	default:
+		writeError(w, http.StatusBadRequest, ErrMsgBadRequest)
+		return
+	}
+
+	dels, err := h.store.Deliveries(consumer, id, state)
+	if err != nil {
+		h.writeStoreError(w, r, err, "Failed to list webhook deliveries.")
+		return
+	}
+	writeValue(w, r, http.StatusOK, struct {
+		Deliveries []webhook.Delivery `json:"deliveries"`
+	}{dels})
+}
+
+// RetryDelivery handles POST /webhooks/{id}/deliveries/{deliveryID}/retry:
+// a dead-lettered delivery goes back to pending, with a fresh set of
+// attempts.
+func (h *WebhookHandler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	consumer, ok := h.consumer(w, r)
+	if !ok {
+		return
+	}
+	id, deliveryID := r.PathValue("id"), r.PathValue("deliveryID")
+	if !uuidRe.MatchString(id) || !uuidRe.MatchString(deliveryID) {
+		writeError(w, http.StatusBadRequest, ErrMsgInvalidUUID)
+		return
+	}
+	del, err := h.store.Retry(consumer, id, deliveryID)
+	if err != nil {
+		h.writeStoreError(w, r, err, "Failed to retry webhook delivery.")
+		return
+	}
+	writeValue(w, r, http.StatusOK, del)
+}
+
+// consumer returns the caller's consumer ID, writing a 401 if it has none:
+// a subscription has to belong to someone.
+func (h *WebhookHandler) consumer(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id, err := h.consumerID(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, ErrMsgUnauthorized)
+		return "", false
+	}
+	return id, true
+}
+
+// writeStoreError maps a webhook store error to a response.
+func (h *WebhookHandler) writeStoreError(w http.ResponseWriter, r *http.Request, err error, fallbackMsg string) {
+	var vErr *webhook.ValidationError
+	switch {
+	case errors.As(err, &vErr):
+		writeError(w, http.StatusBadRequest, vErr.Msg)
+	case errors.Is(err, webhook.ErrNotFound):
+		writeError(w, http.StatusNotFound, ErrMsgNotFound)
+	case errors.Is(err, webhook.ErrNotDead):
+		writeError(w, http.StatusConflict, "Only a dead-lettered delivery can be retried.")
+	default:
+		slog.ErrorContext(r.Context(), "webhook store failed", "err", summarizeErr(err))
+		writeError(w, http.StatusInternalServerError, fallbackMsg)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/webhook"
+)
+
+// newTestWebhookHandler returns a WebhookHandler over an in-memory store,
+// identifying the consumer from X-Consumer-ID. Subscriptions use a public IP
+// literal as their host, which the store checks without a DNS lookup.
+func newTestWebhookHandler(t *testing.T) *WebhookHandler {
+	t.Helper()
+	store, err := webhook.Open("")
+	if err != nil {
+		t.Fatalf("webhook.Open() error = %v", err)
+	}
+	return NewWebhookHandler(store, func(r *http.Request) (string, error) {
+		if id := r.Header.Get("X-Consumer-ID"); id != "" {
+			return id, nil
+		}
+		return "", errors.New("no client ID")
+	})
+}
+
+// webhookRequest builds a request from consumer (none if empty).
+func webhookRequest(method, target, consumer, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if consumer != "" {
+		r.Header.Set("X-Consumer-ID", consumer)
+	}
+	return r
+}
+
+func TestCreateWebhook(t *testing.T) {
+	const valid = `{"url":"https://203.0.113.10/hook","events":["project.updated"],"secret":"0123456789abcdef"}`
+	tests := []struct {
+		name     string
+		consumer string
+		body     string
+		wantCode int
+		wantMsg  string
+	}{
+		{"created", "partner", valid, http.StatusCreated, ""},
+		{"no consumer", "", valid, http.StatusUnauthorized, ErrMsgUnauthorized},
+		{"malformed body", "partner", `{`, http.StatusBadRequest, ErrMsgBadRequest},
+		{"http URL", "partner", `{"url":"http://partner.example.com/hook","events":["project.updated"],"secret":"0123456789abcdef"}`,
+			http.StatusBadRequest, "url must be an absolute https URL without credentials"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestWebhookHandler(t)
+			w := httptest.NewRecorder()
+			h.CreateWebhook(w, webhookRequest(http.MethodPost, "/webhooks", tt.consumer, tt.body))
+
+			assertStatus(t, w, tt.wantCode)
+			if tt.wantMsg != "" {
+				assertErrorMessage(t, w, tt.wantMsg)
+				return
+			}
+			if strings.Contains(w.Body.String(), "0123456789abcdef") {
+				t.Errorf("response echoes the secret: %s", w.Body)
+			}
+			sub := decodeJSON[webhook.Subscription](t, w)
+			if sub.ID == "" || sub.Consumer != "partner" {
+				t.Errorf("subscription = %+v, want an ID owned by partner", sub)
+			}
+		})
+	}
+}
+
+// TestWebhookHandler_ScopedToConsumer verifies one consumer's subscription
+// is invisible to another: absent from its list, and 404 on every call.
+func TestWebhookHandler_ScopedToConsumer(t *testing.T) {
+	h := newTestWebhookHandler(t)
+	w := httptest.NewRecorder()
+	h.CreateWebhook(w, webhookRequest(http.MethodPost, "/webhooks", "partner",
+		`{"url":"https://203.0.113.10/hook","events":["account.updated"],"secret":"0123456789abcdef"}`))
+	assertStatus(t, w, http.StatusCreated)
+	id := decodeJSON[webhook.Subscription](t, w).ID
+
+	w = httptest.NewRecorder()
+	h.ListWebhooks(w, webhookRequest(http.MethodGet, "/webhooks", "reporting", ""))
+	assertStatus(t, w, http.StatusOK)
+	if got := decodeJSON[struct{ Webhooks []webhook.Subscription }](t, w); len(got.Webhooks) != 0 {
+		t.Errorf("other consumer's list = %+v, want empty", got.Webhooks)
+	}
+
+	r := webhookRequest(http.MethodGet, "/webhooks/"+id+"/deliveries", "reporting", "")
+	r.SetPathValue("id", id)
+	w = httptest.NewRecorder()
+	h.ListDeliveries(w, r)
+	assertStatus(t, w, http.StatusNotFound)
+
+	r = webhookRequest(http.MethodDelete, "/webhooks/"+id, "reporting", "")
+	r.SetPathValue("id", id)
+	w = httptest.NewRecorder()
+	h.DeleteWebhook(w, r)
+	assertStatus(t, w, http.StatusNotFound)
+
+	r = webhookRequest(http.MethodDelete, "/webhooks/"+id, "partner", "")
+	r.SetPathValue("id", id)
+	w = httptest.NewRecorder()
+	h.DeleteWebhook(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+}
+
+func TestListDeliveries_Validates(t *testing.T) {
+	const id = "11111111-1111-1111-1111-111111111111"
+	tests := []struct {
+		name, id, query string
+		wantCode        int
+	}{
+		{"bad ID", "not-a-uuid", "", http.StatusBadRequest},
+		{"unknown state", id, "?state=lost", http.StatusBadRequest},
+		{"unknown subscription", id, "?state=dead", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := webhookRequest(http.MethodGet, "/webhooks/"+tt.id+"/deliveries"+tt.query, "partner", "")
+			r.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+			newTestWebhookHandler(t).ListDeliveries(w, r)
+			assertStatus(t, w, tt.wantCode)
+		})
+	}
+}
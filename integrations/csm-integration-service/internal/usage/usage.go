@@ -0,0 +1,230 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package usage meters inbound calls: requests, errors and latency per
+// consumer, route and UTC day, kept in memory and flushed to a local JSON
+// file so the counts survive a restart.
+package usage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/filestore"
+)
+
+// UnknownConsumer is the consumer recorded for a request with no client ID.
+const UnknownConsumer = "unknown"
+
+// Counters are one consumer's calls to one route on one day.
+type Counters struct {
+	Requests int64 `json:"requests"`
+	// Errors counts responses with a 4xx or 5xx status, RateLimited among
+	// them.
+	Errors         int64   `json:"errors"`
+	RateLimited    int64   `json:"rateLimited"`
+	LatencyTotalMs float64 `json:"latencyTotalMs"`
+	LatencyMaxMs   float64 `json:"latencyMaxMs"`
+}
+
+// Row is one line of a usage report.
+type Row struct {
+	Day      string `json:"day"`
+	Consumer string `json:"consumer"`
+	Route    string `json:"route"`
+	Counters
+	LatencyAvgMs float64 `json:"latencyAvgMs"`
+}
+
+type key struct {
+	day, consumer, route string
+}
+
+// Store holds the counters. Record only touches memory; Flush (or Run)
+// writes them to path, and Open reads them back.
+type Store struct {
+	path      string
+	retention int
+
+	mu     sync.Mutex
+	counts map[key]*Counters
+
+	now func() time.Time
+}
+
+// Open returns a Store flushing to path, loaded with what a previous run
+// flushed there. Days older than retentionDays are dropped on every flush.
+// An empty path keeps the counters in memory only.
+func Open(path string, retentionDays int) (*Store, error) {
+	if retentionDays < 1 {
+		return nil, fmt.Errorf("usage: retention must be at least 1 day, got %d", retentionDays)
+	}
+	s := &Store{path: path, retention: retentionDays, counts: map[key]*Counters{}, now: time.Now}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is operator configuration (USAGE_FILE)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("usage: %w", err)
+	}
+	var rows []Row
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("usage: %s: %w", path, err)
+	}
+	for _, r := range rows {
+		c := r.Counters
+		s.counts[key{r.Day, r.Consumer, r.Route}] = &c
+	}
+	return s, nil
+}
+
+// Record counts one call by consumer to route that got status after
+// elapsed.
+func (s *Store) Record(consumer, route string, status int, elapsed time.Duration) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+	k := key{s.now().UTC().Format(time.DateOnly), consumer, route}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.counts[k]
+	if !ok {
+		c = &Counters{}
+		s.counts[k] = c
+	}
+	c.Requests++
+	if status >= http.StatusBadRequest {
+		c.Errors++
+	}
+	if status == http.StatusTooManyRequests {
+		c.RateLimited++
+	}
+	c.LatencyTotalMs += ms
+	c.LatencyMaxMs = max(c.LatencyMaxMs, ms)
+}
+
+// Report returns the counters for days from through to (YYYY-MM-DD,
+// inclusive), for consumer only if it is non-empty, ordered by day,
+// consumer and route.
+func (s *Store) Report(from, to, consumer string) []Row {
+	s.mu.Lock()
+	rows := make([]Row, 0, len(s.counts))
+	for k, c := range s.counts {
+		if k.day < from || k.day > to || (consumer != "" && k.consumer != consumer) {
+			continue
+		}
+		rows = append(rows, row(k, c))
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(rows, func(a, b Row) int {
+		return cmp.Or(cmp.Compare(a.Day, b.Day), cmp.Compare(a.Consumer, b.Consumer), cmp.Compare(a.Route, b.Route))
+	})
+	return rows
+}
+
+func row(k key, c *Counters) Row {
+	r := Row{Day: k.day, Consumer: k.consumer, Route: k.route, Counters: *c}
+	if c.Requests > 0 {
+		r.LatencyAvgMs = c.LatencyTotalMs / float64(c.Requests)
+	}
+	return r
+}
+
+// Flush drops days past retention and writes the rest to the store's file.
+func (s *Store) Flush() error {
+	cutoff := s.now().UTC().AddDate(0, 0, -s.retention+1).Format(time.DateOnly)
+
+	s.mu.Lock()
+	rows := make([]Row, 0, len(s.counts))
+	for k, c := range s.counts {
+		if k.day < cutoff {
+			delete(s.counts, k)
+			continue
+		}
+		rows = append(rows, Row{Day: k.day, Consumer: k.consumer, Route: k.route, Counters: *c})
+	}
+	s.mu.Unlock()
+
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("usage: %w", err)
+	}
+	if err := filestore.WriteFile(s.path, data); err != nil {
+		return fmt.Errorf("usage: %w", err)
+	}
+	return nil
+}
+
+// Run flushes every interval until ctx is done. A failed flush is logged and
+// retried next time. Flush once more after the server has drained, so a
+// clean shutdown loses nothing.
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.Flush(); err != nil {
+				slog.Error("usage flush failed, retrying next interval", "err", err)
+			}
+		}
+	}
+}
+
+// Meter returns h recording each call to route against the consumer
+// consumerID names (UnknownConsumer when it returns an error). Wrap it
+// outside any access check, so 401s, 403s and 429s are counted too.
+func (s *Store) Meter(route string, consumerID func(*http.Request) (string, error), h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := s.now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+
+		consumer, err := consumerID(r)
+		if err != nil {
+			consumer = UnknownConsumer
+		}
+		s.Record(consumer, route, sw.status, s.now().Sub(start))
+	})
+}
+
+// statusWriter captures the status code the wrapped handler writes.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
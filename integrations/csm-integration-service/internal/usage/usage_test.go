@@ -0,0 +1,113 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package usage
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T, path string, now time.Time) *Store {
+	t.Helper()
+	s, err := Open(path, 30)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestStore_RecordAndReport(t *testing.T) {
+	s := openTestStore(t, "", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))
+	s.Record("partner", "POST /projects/search", http.StatusOK, 10*time.Millisecond)
+	s.Record("partner", "POST /projects/search", http.StatusTooManyRequests, 30*time.Millisecond)
+	s.Record("partner", "POST /projects/search", http.StatusBadGateway, 50*time.Millisecond)
+	s.Record("reporting", "GET /projects/{id}", http.StatusOK, time.Millisecond)
+
+	rows := s.Report("2026-10-19", "2026-10-19", "partner")
+	if len(rows) != 1 {
+		t.Fatalf("Report() = %+v, want one row", rows)
+	}
+	got := rows[0]
+	if got.Requests != 3 || got.Errors != 2 || got.RateLimited != 1 {
+		t.Errorf("counts = %+v, want 3 requests, 2 errors, 1 rate limited", got.Counters)
+	}
+	if got.LatencyAvgMs != 30 || got.LatencyMaxMs != 50 {
+		t.Errorf("latency avg/max = %v/%v, want 30/50", got.LatencyAvgMs, got.LatencyMaxMs)
+	}
+
+	if rows := s.Report("2026-10-19", "2026-10-19", ""); len(rows) != 2 || rows[0].Consumer != "partner" {
+		t.Errorf("Report() for every consumer = %+v, want partner then reporting", rows)
+	}
+	if rows := s.Report("2026-10-20", "2026-10-21", ""); len(rows) != 0 {
+		t.Errorf("Report() outside the range = %+v, want none", rows)
+	}
+}
+
+// TestStore_FlushPersistsAndPrunes verifies counters survive a reopen and
+// days past retention are dropped.
+func TestStore_FlushPersistsAndPrunes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	old := openTestStore(t, path, now.AddDate(0, 0, -30))
+	old.Record("partner", "GET /projects/{id}", http.StatusOK, time.Millisecond)
+	s := openTestStore(t, path, now)
+	s.counts = old.counts
+	s.Record("partner", "GET /projects/{id}", http.StatusOK, time.Millisecond)
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	reopened := openTestStore(t, path, now)
+	rows := reopened.Report("2000-01-01", "2099-12-31", "")
+	if len(rows) != 1 || rows[0].Day != "2026-10-19" || rows[0].Requests != 1 {
+		t.Errorf("after reopen Report() = %+v, want only today's row", rows)
+	}
+}
+
+// TestStore_Meter verifies the status the wrapped handler writes is counted
+// against the caller, and a caller with no ID against UnknownConsumer.
+func TestStore_Meter(t *testing.T) {
+	s := openTestStore(t, "", time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))
+	consumerID := func(r *http.Request) (string, error) {
+		if id := r.Header.Get("X-Consumer-ID"); id != "" {
+			return id, nil
+		}
+		return "", errors.New("no client ID")
+	}
+	h := s.Meter("POST /projects/search", consumerID, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/projects/search", nil)
+	r.Header.Set("X-Consumer-ID", "partner")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/projects/search", nil))
+
+	rows := s.Report("2026-10-19", "2026-10-19", "")
+	if len(rows) != 2 || rows[0].Consumer != "partner" || rows[1].Consumer != UnknownConsumer {
+		t.Fatalf("Report() = %+v, want partner and %s", rows, UnknownConsumer)
+	}
+	if rows[0].RateLimited != 1 || rows[0].Route != "POST /projects/search" {
+		t.Errorf("partner row = %+v, want one rate-limited call to the route", rows[0])
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// resolveTimeout bounds the lookup of a subscription URL's host.
+const resolveTimeout = 5 * time.Second
+
+// errInternalAddr is a subscriber address this service won't send to.
+var errInternalAddr = errors.New("address is loopback, private or link-local")
+
+// internalAddr reports whether a is an address a subscriber URL must not
+// reach: this host, the private network or link-local services such as
+// cloud metadata.
+func internalAddr(a netip.Addr) bool {
+	a = a.Unmap()
+	return !a.IsValid() || a.IsLoopback() || a.IsPrivate() || a.IsUnspecified() ||
+		a.IsLinkLocalUnicast() || a.IsLinkLocalMulticast() || a.IsInterfaceLocalMulticast() || a.IsMulticast()
+}
+
+// lookupHost resolves host, an IP literal or a name, to its addresses.
+func lookupHost(ctx context.Context, host string) ([]netip.Addr, error) {
+	return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+}
+
+// checkHost resolves host with resolve and fails unless every address it
+// resolves to is a public one.
+func checkHost(ctx context.Context, resolve func(context.Context, string) ([]netip.Addr, error), host string) error {
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	addrs, err := resolve(ctx, host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("resolve %s: no addresses", host)
+	}
+	for _, a := range addrs {
+		if internalAddr(a) {
+			return fmt.Errorf("%s resolves to %s: %w", host, a, errInternalAddr)
+		}
+	}
+	return nil
+}
+
+// dialControl refuses a connection to an internal address. It runs on the
+// address actually dialed, so a subscriber host that resolved to a public
+// address at subscribe time and to an internal one since is still refused.
+func dialControl(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", address, err)
+	}
+	if internalAddr(ap.Addr()) {
+		return fmt.Errorf("dial %s: %w", address, errInternalAddr)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-CSM-Webhook-Event"
+	HeaderDelivery  = "X-CSM-Webhook-Delivery"
+	HeaderSignature = "X-CSM-Webhook-Signature"
+)
+
+const (
+	// deliveryTimeout bounds one attempt, response included.
+	deliveryTimeout = 10 * time.Second
+	// subscriptionBudget bounds the attempts one pass makes at one
+	// subscription's deliveries; what it doesn't reach waits for the next.
+	subscriptionBudget = 30 * time.Second
+	// maxConcurrentSubscriptions bounds the subscriptions a pass delivers to
+	// at once.
+	maxConcurrentSubscriptions = 16
+	// baseBackoff is the wait after the first failed attempt, doubled after
+	// each further one up to maxBackoff.
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+	// maxErrorLen bounds the LastError kept per delivery.
+	maxErrorLen = 200
+)
+
+// Sign returns the HeaderSignature value for body sent at t:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>">". A
+// subscriber recomputes v1 with its secret and rejects a stale t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher sends the store's due deliveries. A delivery that gets no 2xx
+// is retried with exponential backoff, and after maxAttempts attempts is
+// dead-lettered.
+type Dispatcher struct {
+	store       *Store
+	client      *http.Client
+	maxAttempts int
+	budget      time.Duration
+	now         func() time.Time
+}
+
+// NewDispatcher creates a Dispatcher for store's deliveries. Redirects are
+// not followed: a subscriber answering 3xx has failed the attempt. Nor is a
+// proxy used, so every connection is to the subscriber itself and is refused
+// when its address is loopback, private or link-local.
+func NewDispatcher(store *Store, maxAttempts int) *Dispatcher {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: deliveryTimeout, Control: dialControl}).DialContext
+	return &Dispatcher{
+		store: store,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   deliveryTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts: maxAttempts,
+		budget:      subscriptionBudget,
+		now:         time.Now,
+	}
+}
+
+// Run delivers what is due every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "webhook delivery pass failed", "err", err)
+			}
+		}
+	}
+}
+
+// DeliverDue makes one attempt at each due delivery and saves the store.
+// Subscriptions are delivered to concurrently, each one's deliveries in
+// order within its own budget, so a slow subscriber delays only itself.
+func (d *Dispatcher) DeliverDue(ctx context.Context) error {
+	jobs := d.store.due(d.now())
+	if len(jobs) == 0 {
+		return nil
+	}
+	var order []string
+	bySub := map[string][]job{}
+	for _, j := range jobs {
+		if _, ok := bySub[j.SubscriptionID]; !ok {
+			order = append(order, j.SubscriptionID)
+		}
+		bySub[j.SubscriptionID] = append(bySub[j.SubscriptionID], j)
+	}
+
+	sem := make(chan struct{}, maxConcurrentSubscriptions)
+	var wg sync.WaitGroup
+	for _, id := range order {
+		wg.Add(1)
+		go func(jobs []job) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			d.deliverSubscription(ctx, jobs)
+		}(bySub[id])
+	}
+	wg.Wait()
+	return d.store.Save()
+}
+
+// deliverSubscription makes one attempt at each of one subscription's due
+// deliveries, in order, until its budget is spent.
+func (d *Dispatcher) deliverSubscription(ctx context.Context, jobs []job) {
+	ctx, cancel := context.WithTimeout(ctx, d.budget)
+	defer cancel()
+	for _, j := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		d.store.update(d.attempt(ctx, j))
+	}
+}
+
+// attempt sends j once and returns its delivery updated with the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, j job) Delivery {
+	del := j.Delivery
+	status, err := d.send(ctx, j)
+	now := d.now().UTC()
+	del.Attempts++
+	del.LastStatus = status
+	del.UpdatedOn = now
+	if err == nil {
+		del.State, del.LastError, del.NextAttemptOn = StateDelivered, "", time.Time{}
+		return del
+	}
+
+	del.LastError = err.Error()
+	if len(del.LastError) > maxErrorLen {
+		del.LastError = del.LastError[:maxErrorLen]
+	}
+	if del.Attempts >= d.maxAttempts {
+		del.State, del.NextAttemptOn = StateDead, time.Time{}
+		slog.WarnContext(ctx, "webhook delivery dead-lettered",
+			"deliveryID", del.ID, "subscriptionID", del.SubscriptionID, "event", del.Event, "attempts", del.Attempts, "err", del.LastError)
+		return del
+	}
+	del.NextAttemptOn = now.Add(backoff(del.Attempts))
+	return del
+}
+
+// send POSTs j's payload, signed, and returns the response status, with an
+// error unless it was a 2xx.
+func (d *Dispatcher) send(ctx context.Context, j job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.url, bytes.NewReader(j.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "csm-integration-service-webhooks")
+	req.Header.Set(HeaderEvent, j.Event)
+	req.Header.Set(HeaderDelivery, j.ID)
+	req.Header.Set(HeaderSignature, Sign(j.secret, d.now(), j.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		var urlErr interface{ Timeout() bool }
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			return 0, errors.New("request timed out")
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff is the wait after the attempts-th failed attempt.
+func backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queueTestDelivery subscribes "partner" at url and queues one delivery.
+func queueTestDelivery(t *testing.T, s *Store, url string) Subscription {
+	t.Helper()
+	return queueDelivery(t, s, url, "d1")
+}
+
+// queueDelivery subscribes "partner" at url and queues one delivery with id.
+func queueDelivery(t *testing.T, s *Store, url, id string) Subscription {
+	t.Helper()
+	sub, err := s.Subscribe("partner", url, []string{EventProjectUpdated}, testSecret)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	now := s.now()
+	s.record(kindProject, "p-"+id, entry{}, []Delivery{{
+		ID: id, SubscriptionID: sub.ID, Event: EventProjectUpdated, State: StatePending,
+		NextAttemptOn: now, CreatedOn: now, UpdatedOn: now, Payload: []byte(`{"type":"project.updated"}`),
+	}})
+	return sub
+}
+
+// TestDispatcher_DeliversSigned verifies the subscriber receives the
+// payload with event, delivery and a signature it can verify.
+func TestDispatcher_DeliversSigned(t *testing.T) {
+	s := newTestStore(t)
+	var gotSig, gotEvent, gotDelivery string
+	var gotBody []byte
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig, gotEvent, gotDelivery = r.Header.Get(HeaderSignature), r.Header.Get(HeaderEvent), r.Header.Get(HeaderDelivery)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	sub := queueTestDelivery(t, s, srv.URL)
+
+	d := NewDispatcher(s, 3)
+	d.client = srv.Client()
+	d.now = s.now
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+
+	if want := Sign(testSecret, s.now(), gotBody); gotSig != want {
+		t.Errorf("signature = %q, want %q", gotSig, want)
+	}
+	if gotEvent != EventProjectUpdated || gotDelivery != "d1" {
+		t.Errorf("event, delivery headers = %q, %q; want %q, %q", gotEvent, gotDelivery, EventProjectUpdated, "d1")
+	}
+	dels, _ := s.Deliveries("partner", sub.ID, StateDelivered)
+	if len(dels) != 1 || dels[0].Attempts != 1 || dels[0].LastStatus != http.StatusNoContent {
+		t.Errorf("delivered = %+v, want d1 after one attempt", dels)
+	}
+}
+
+// TestDispatcher_RetriesThenDeadLetters verifies a failed attempt waits out
+// its backoff, and the last allowed attempt's failure dead-letters it.
+func TestDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	s := newTestStore(t)
+	calls := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	sub := queueTestDelivery(t, s, srv.URL)
+
+	now := s.now()
+	d := NewDispatcher(s, 2)
+	d.client = srv.Client()
+	d.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := d.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	pending, _ := s.Deliveries("partner", sub.ID, StatePending)
+	if len(pending) != 1 || !pending[0].NextAttemptOn.Equal(now.Add(baseBackoff)) || pending[0].LastStatus != 500 {
+		t.Fatalf("after one failure: %+v, want pending until %v", pending, now.Add(baseBackoff))
+	}
+
+	if err := d.DeliverDue(ctx); err != nil || calls != 1 {
+		t.Fatalf("DeliverDue() before the backoff: err = %v, calls = %d, want no new attempt", err, calls)
+	}
+
+	now = now.Add(baseBackoff)
+	if err := d.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	dead, _ := s.Deliveries("partner", sub.ID, StateDead)
+	if len(dead) != 1 || dead[0].Attempts != 2 || dead[0].LastError == "" {
+		t.Errorf("dead letters = %+v, want d1 after 2 attempts with its last error", dead)
+	}
+}
+
+// TestDispatcher_RefusesInternalAddress verifies the dispatcher's own client
+// won't connect to a subscriber at a loopback address, even one accepted
+// when it subscribed.
+func TestDispatcher_RefusesInternalAddress(t *testing.T) {
+	s := newTestStore(t)
+	calls := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	sub := queueTestDelivery(t, s, srv.URL)
+
+	d := NewDispatcher(s, 3)
+	d.now = s.now
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+
+	if calls != 0 {
+		t.Errorf("subscriber got %d requests, want none", calls)
+	}
+	pending, _ := s.Deliveries("partner", sub.ID, StatePending)
+	if len(pending) != 1 || !strings.Contains(pending[0].LastError, errInternalAddr.Error()) {
+		t.Errorf("pending = %+v, want d1 failed on the internal address", pending)
+	}
+}
+
+// TestDispatcher_DeliversSubscriptionsConcurrently verifies one
+// subscriber's slow response doesn't hold back another's delivery: the slow
+// one answers only once the other has been delivered to.
+func TestDispatcher_DeliversSubscriptionsConcurrently(t *testing.T) {
+	s := newTestStore(t)
+	fastDone := make(chan struct{})
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fast" {
+			close(fastDone)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body) // so the server notices the client giving up
+		select {
+		case <-fastDone:
+			w.WriteHeader(http.StatusNoContent)
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	slow := queueTestDelivery(t, s, srv.URL+"/slow")
+	fast := queueDelivery(t, s, srv.URL+"/fast", "d2")
+
+	d := NewDispatcher(s, 3)
+	d.client = srv.Client()
+	d.now = s.now
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+
+	for _, sub := range []Subscription{slow, fast} {
+		if dels, _ := s.Deliveries("partner", sub.ID, StateDelivered); len(dels) != 1 {
+			t.Errorf("%s delivered = %+v, want its delivery", sub.URL, dels)
+		}
+	}
+}
+
+// TestDispatcher_StopsAtSubscriptionBudget verifies a pass gives up on a
+// subscription once its budget is spent, leaving the deliveries it didn't
+// reach untried for the next pass.
+func TestDispatcher_StopsAtSubscriptionBudget(t *testing.T) {
+	s := newTestStore(t)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body) // so the server notices the client giving up
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+	sub := queueTestDelivery(t, s, srv.URL)
+	now := s.now()
+	s.record(kindProject, "p2", entry{}, []Delivery{{
+		ID: "d2", SubscriptionID: sub.ID, Event: EventProjectUpdated, State: StatePending,
+		NextAttemptOn: now, CreatedOn: now, UpdatedOn: now, Payload: []byte(`{"type":"project.updated"}`),
+	}})
+
+	d := NewDispatcher(s, 3)
+	d.client = srv.Client()
+	d.now = s.now
+	d.budget = 50 * time.Millisecond
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+
+	pending, _ := s.Deliveries("partner", sub.ID, StatePending)
+	attempts := map[string]int{}
+	for _, del := range pending {
+		attempts[del.ID] = del.Attempts
+	}
+	if len(pending) != 2 || attempts["d1"] != 1 || attempts["d2"] != 0 {
+		t.Errorf("attempts = %v, want d1 tried once and d2 left for the next pass", attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/access"
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/filestore"
+)
+
+// pageSize is the page size used for the entity searches: entity-service's
+// live maximum is 50, whatever its code says (see acp-closure-service's
+// sweep).
+const pageSize = 50
+
+// Entity kinds the detector polls.
+const (
+	kindProject = "project"
+	kindAccount = "account"
+)
+
+// readRoutes are the routes a subscriber's access policy must allow for it
+// to be sent an entity of each kind: an event carries what that read
+// would have returned.
+var readRoutes = map[string]string{
+	kindProject: "GET /projects/{id}",
+	kindAccount: "GET /accounts/{id}",
+}
+
+// updatedEvents is the event raised for a new updatedOn of each kind.
+var updatedEvents = map[string]string{
+	kindProject: EventProjectUpdated,
+	kindAccount: EventAccountUpdated,
+}
+
+// entityReader abstracts the entity service reads the detector uses: the
+// searches it polls, and GetProject, for the account tiers a subscriber's
+// project scope is checked against.
+type entityReader interface {
+	SearchProjects(ctx context.Context, body []byte) ([]byte, error)
+	SearchAccounts(ctx context.Context, body []byte) ([]byte, error)
+	GetProject(ctx context.Context, id string) ([]byte, error)
+}
+
+// Policy looks up a consumer's access policy; *access.Store satisfies it.
+type Policy interface {
+	Client(id string) (*access.Client, bool)
+}
+
+// closure is a project's closure-tracking fields (ServiceNow data source
+// only). A change to any of them is a project.closure_changed event.
+type closure struct {
+	ClosureState                    *string `json:"closureState"`
+	EndDateClosureState             *string `json:"endDateClosureState"`
+	InvoiceDueDateClosureState      *string `json:"invoiceDueDateClosureState"`
+	ComplianceViolationClosureState *string `json:"complianceViolationClosureState"`
+}
+
+func (c *closure) equal(o *closure) bool {
+	eq := func(a, b *string) bool { return (a == nil && b == nil) || (a != nil && b != nil && *a == *b) }
+	return eq(c.ClosureState, o.ClosureState) &&
+		eq(c.EndDateClosureState, o.EndDateClosureState) &&
+		eq(c.InvoiceDueDateClosureState, o.InvoiceDueDateClosureState) &&
+		eq(c.ComplianceViolationClosureState, o.ComplianceViolationClosureState)
+}
+
+// payload is the JSON body of a delivery. Data is the entity as the
+// subscriber would read it from entity search — scoped and redacted by its
+// access policy. Previous is set on project.closure_changed.
+type payload struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredOn string          `json:"occurredOn"`
+	Data       json.RawMessage `json:"data"`
+	Previous   *closure        `json:"previous,omitempty"`
+}
+
+// Detector finds project and account changes by polling entity-service's
+// searches and comparing each entity's updatedOn (and, for projects, its
+// closure fields) with what the previous poll saw. The project search
+// filters on updatedOn, so once baselined a poll reads only the projects
+// updated since the latest updatedOn it has seen; the account search
+// can't, so every poll pages through all accounts.
+type Detector struct {
+	entity entityReader
+	store  *Store
+	policy Policy
+	tiers  *access.Tiers
+	now    func() time.Time
+}
+
+// NewDetector creates a Detector reading from entity and queueing
+// deliveries in store. policy is nil when no access policy is configured,
+// in which case every subscriber is sent every entity unredacted.
+func NewDetector(entity entityReader, store *Store, policy Policy) *Detector {
+	return &Detector{
+		entity: entity,
+		store:  store,
+		policy: policy,
+		tiers:  access.NewTiers(entity.GetProject, access.DefaultTierTTL),
+		now:    time.Now,
+	}
+}
+
+// Run polls now and then every interval until ctx is done. A failed poll is
+// logged and the next one picks up where the snapshot left off.
+func (d *Detector) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := d.Poll(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "webhook change detection failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Poll runs one pass over projects and accounts, queueing a delivery per
+// change and interested subscriber, and saves the store. With no
+// subscriptions it polls nothing and drops its snapshot instead, so the
+// first poll after someone subscribes is a fresh baseline rather than
+// every change since the last subscriber left.
+func (d *Detector) Poll(ctx context.Context) error {
+	if !d.store.hasSubscriptions() {
+		d.store.resetSnapshots()
+		return d.store.Save()
+	}
+	err := d.pass(ctx, kindProject)
+	if err == nil {
+		err = d.pass(ctx, kindAccount)
+	}
+	if saveErr := d.store.Save(); err == nil {
+		err = saveErr
+	}
+	return err
+}
+
+// pass pages through kind's search: for projects once baselined, only
+// those updated since the cursor. updatedFrom is inclusive, so the
+// projects at the cursor itself come back again, and compare passes over
+// them as unchanged. Entities are recorded as they are compared, so a pass
+// cut short by an error keeps what it got through; its cursor stays put,
+// and the next pass reads the same projects again.
+func (d *Detector) pass(ctx context.Context, kind string) error {
+	search, listKey := d.entity.SearchProjects, "projects"
+	if kind == kindAccount {
+		search, listKey = d.entity.SearchAccounts, "accounts"
+	}
+	since := ""
+	if kind == kindProject {
+		since = d.store.cursor(kind)
+	}
+
+	seen := map[string]bool{}
+	latest := since
+	for offset := 0; ; offset += pageSize {
+		req := map[string]any{"pagination": map[string]int{"limit": pageSize, "offset": offset}}
+		if since != "" {
+			req["updatedFrom"] = since
+		}
+		body, err := json.Marshal(req)
+		if err != nil {
+			return fmt.Errorf("webhook: build %s search: %w", kind, err)
+		}
+		raw, err := search(ctx, body)
+		if err != nil {
+			return fmt.Errorf("webhook: search %ss at offset %d: %w", kind, offset, err)
+		}
+		var page map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return fmt.Errorf("webhook: parse %s search at offset %d: %w", kind, offset, err)
+		}
+		var items []json.RawMessage
+		var hasMore bool
+		var total int
+		if err := json.Unmarshal(page[listKey], &items); err != nil {
+			return fmt.Errorf("webhook: parse %s search at offset %d: %w", kind, offset, err)
+		}
+		_ = json.Unmarshal(page["hasMore"], &hasMore)
+		_ = json.Unmarshal(page["total"], &total)
+
+		for _, item := range items {
+			id, updatedOn, err := d.compare(ctx, kind, item)
+			if err != nil {
+				return err
+			}
+			seen[id] = true
+			latest = later(latest, updatedOn)
+		}
+		// total bounds the paging in case hasMore never turns false.
+		if len(items) == 0 || !hasMore || (total > 0 && offset+len(items) >= total) {
+			break
+		}
+	}
+	if since != "" {
+		d.store.advance(kind, latest)
+	} else {
+		d.store.completePass(kind, seen, latest)
+	}
+	return nil
+}
+
+// later returns whichever of a and b, RFC 3339 timestamps, is later, or
+// the other one if one of them doesn't parse.
+func later(a, b string) string {
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	switch {
+	case errB != nil:
+		return a
+	case errA != nil || tb.After(ta):
+		return b
+	default:
+		return a
+	}
+}
+
+// compare records item, one entity of kind, queueing deliveries for what
+// changed since it was last seen, and returns its ID and updatedOn.
+func (d *Detector) compare(ctx context.Context, kind string, item json.RawMessage) (string, string, error) {
+	var cur struct {
+		ID        string `json:"id"`
+		UpdatedOn string `json:"updatedOn"`
+		closure
+	}
+	if err := json.Unmarshal(item, &cur); err != nil {
+		return "", "", fmt.Errorf("webhook: parse %s: %w", kind, err)
+	}
+	e := entry{UpdatedOn: cur.UpdatedOn}
+	if kind == kindProject {
+		e.Closure = &cur.closure
+	}
+
+	prev, known, baselined := d.store.seen(kind, cur.ID)
+	var events []payload
+	if baselined {
+		occurredOn := cur.UpdatedOn
+		if occurredOn == "" {
+			occurredOn = d.now().UTC().Format(time.RFC3339)
+		}
+		if !known || prev.UpdatedOn != cur.UpdatedOn {
+			events = append(events, payload{Type: updatedEvents[kind], OccurredOn: occurredOn})
+		}
+		if kind == kindProject && known && prev.Closure != nil && !prev.Closure.equal(e.Closure) {
+			events = append(events, payload{Type: EventProjectClosureChange, OccurredOn: occurredOn, Previous: prev.Closure})
+		}
+	}
+
+	var dels []Delivery
+	for _, ev := range events {
+		ev.ID = filestore.NewID()
+		for _, sub := range d.store.subscribers(ev.Type) {
+			data, ok, err := d.dataFor(ctx, sub, kind, item)
+			if err != nil {
+				return "", "", err
+			}
+			if !ok {
+				continue
+			}
+			ev.Data = data
+			body, err := json.Marshal(ev)
+			if err != nil {
+				return "", "", fmt.Errorf("webhook: build %s payload: %w", ev.Type, err)
+			}
+			now := d.now().UTC()
+			dels = append(dels, Delivery{
+				ID:             filestore.NewID(),
+				SubscriptionID: sub.ID,
+				EventID:        ev.ID,
+				Event:          ev.Type,
+				State:          StatePending,
+				NextAttemptOn:  now,
+				CreatedOn:      now,
+				UpdatedOn:      now,
+				Payload:        body,
+			})
+		}
+	}
+	d.store.record(kind, cur.ID, e, dels)
+	return cur.ID, cur.UpdatedOn, nil
+}
+
+// dataFor returns item as sub's consumer may see it, or false if its access
+// policy doesn't let it read item at all. A project search item carries no
+// account tier, so a tier scope is checked against the looked-up one.
+func (d *Detector) dataFor(ctx context.Context, sub Subscription, kind string, item []byte) ([]byte, bool, error) {
+	var c *access.Client
+	if d.policy != nil {
+		var ok bool
+		if c, ok = d.policy.Client(sub.Consumer); !ok {
+			return nil, false, nil
+		}
+	}
+	if !c.Allows(readRoutes[kind]) {
+		return nil, false, nil
+	}
+	if kind == kindAccount {
+		data, err := c.RedactAccount(item)
+		return data, err == nil, err
+	}
+	sees, err := c.SeesSearchedProject(ctx, item, d.tiers)
+	if err != nil || !sees {
+		return nil, false, err
+	}
+	data, err := c.RedactProject(item)
+	return data, err == nil, err
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/access"
+)
+
+// fakeEntity serves its projects and accounts, as JSON objects, as a single
+// search page, honouring a project search's updatedFrom the way
+// entity-service does. tiers answers GetProject: search items carry only
+// the account's {id, name}, never its tier.
+type fakeEntity struct {
+	projects, accounts []string
+	tiers              map[string]string
+	searches           int
+	projectSearches    []string
+	getProjects        int
+}
+
+func (f *fakeEntity) SearchProjects(ctx context.Context, body []byte) ([]byte, error) {
+	f.searches++
+	f.projectSearches = append(f.projectSearches, string(body))
+	var req struct {
+		UpdatedFrom *time.Time `json:"updatedFrom"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, p := range f.projects {
+		var v struct {
+			UpdatedOn *time.Time `json:"updatedOn"`
+		}
+		_ = json.Unmarshal([]byte(p), &v)
+		if req.UpdatedFrom == nil || (v.UpdatedOn != nil && !v.UpdatedOn.Before(*req.UpdatedFrom)) {
+			out = append(out, p)
+		}
+	}
+	return []byte(`{"projects":[` + strings.Join(out, ",") + `],"total":` + strconv.Itoa(len(out)) + `,"limit":50,"offset":0,"hasMore":false}`), nil
+}
+
+func (f *fakeEntity) SearchAccounts(ctx context.Context, body []byte) ([]byte, error) {
+	f.searches++
+	return []byte(`{"accounts":[` + strings.Join(f.accounts, ",") + `],"hasMore":false}`), nil
+}
+
+func (f *fakeEntity) GetProject(ctx context.Context, id string) ([]byte, error) {
+	f.getProjects++
+	return []byte(`{"id":"` + id + `","account":{"id":"a-` + id + `","name":"Account","tier":"` + f.tiers[id] + `","region":null}}`), nil
+}
+
+// project is a project search item in entity-service's ProjectView shape.
+func project(id, updatedOn, endDateClosureState string) string {
+	return `{"id":"` + id + `","name":"Project ` + id + `","key":"` + strings.ToUpper(id) + `","subscriptionType":"subscription",` +
+		`"startDate":null,"endDate":"2027-01-01T00:00:00Z","createdOn":"2024-01-01T00:00:00Z","updatedOn":"` + updatedOn + `",` +
+		`"account":{"id":"a-` + id + `","name":"Account"},"closureState":"Active","endDateClosureState":"` + endDateClosureState + `",` +
+		`"invoiceDueDateClosureState":null,"complianceViolationClosureState":null,"complianceViolationDate":null,"suspensionProcessState":null}`
+}
+
+// pendingPayloads returns the payloads queued for subscription id, by
+// event type.
+func pendingPayloads(t *testing.T, s *Store, consumer, id string) map[string][]payload {
+	t.Helper()
+	dels, err := s.Deliveries(consumer, id, StatePending)
+	if err != nil {
+		t.Fatalf("Deliveries() error = %v", err)
+	}
+	out := map[string][]payload{}
+	for _, d := range dels {
+		var p payload
+		if err := json.Unmarshal(d.Payload, &p); err != nil {
+			t.Fatalf("payload: %v", err)
+		}
+		out[d.Event] = append(out[d.Event], p)
+	}
+	return out
+}
+
+func TestDetector_PollWithoutSubscriptionsSearchesNothing(t *testing.T) {
+	f := &fakeEntity{}
+	if err := NewDetector(f, newTestStore(t), nil).Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if f.searches != 0 {
+		t.Errorf("searches = %d, want 0", f.searches)
+	}
+}
+
+// TestDetector_Poll verifies the first poll is a silent baseline and the
+// next raises project.updated for a new updatedOn or a new project,
+// project.closure_changed with the previous state, and account.updated.
+func TestDetector_Poll(t *testing.T) {
+	s := newTestStore(t)
+	sub, err := s.Subscribe("partner", "https://partner.example.com/hook",
+		[]string{EventProjectUpdated, EventProjectClosureChange, EventAccountUpdated}, testSecret)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	f := &fakeEntity{
+		projects: []string{project("p1", "2026-10-01T00:00:00Z", "Active"), project("p2", "2026-10-01T00:00:00Z", "Active")},
+		accounts: []string{`{"id":"a1","updatedOn":"2026-10-01T00:00:00Z"}`},
+	}
+	d := NewDetector(f, s, nil)
+	ctx := context.Background()
+
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("baseline Poll() error = %v", err)
+	}
+	if got := pendingPayloads(t, s, "partner", sub.ID); len(got) != 0 {
+		t.Fatalf("after baseline, deliveries = %+v, want none", got)
+	}
+
+	f.projects = []string{
+		project("p1", "2026-10-19T08:00:00Z", "Suspended"),
+		project("p2", "2026-10-01T00:00:00Z", "Active"),
+		project("p3", "2026-10-19T08:00:00Z", "Active"),
+	}
+	f.accounts = []string{`{"id":"a1","updatedOn":"2026-10-19T08:00:00Z"}`}
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	got := pendingPayloads(t, s, "partner", sub.ID)
+	if len(got[EventProjectUpdated]) != 2 {
+		t.Errorf("%s payloads = %+v, want p1 and p3", EventProjectUpdated, got[EventProjectUpdated])
+	}
+	closures := got[EventProjectClosureChange]
+	if len(closures) != 1 || closures[0].Previous == nil || *closures[0].Previous.EndDateClosureState != "Active" {
+		t.Errorf("%s payloads = %+v, want p1's with its previous state", EventProjectClosureChange, closures)
+	}
+	if len(got[EventAccountUpdated]) != 1 {
+		t.Errorf("%s payloads = %+v, want a1's", EventAccountUpdated, got[EventAccountUpdated])
+	}
+
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("unchanged Poll() error = %v", err)
+	}
+	if dels, _ := s.Deliveries("partner", sub.ID, StatePending); len(dels) != 4 {
+		t.Errorf("after an unchanged poll, %d deliveries, want still 4", len(dels))
+	}
+
+	// The baseline read every project; each later poll only those updated
+	// since the latest updatedOn seen.
+	wantFrom := []string{"", `"updatedFrom":"2026-10-01T00:00:00Z"`, `"updatedFrom":"2026-10-19T08:00:00Z"`}
+	if len(f.projectSearches) != len(wantFrom) {
+		t.Fatalf("project searches = %v", f.projectSearches)
+	}
+	for i, want := range wantFrom {
+		if got := f.projectSearches[i]; (want == "" && strings.Contains(got, "updatedFrom")) || !strings.Contains(got, want) {
+			t.Errorf("project search %d = %s, want %s", i, got, want)
+		}
+	}
+	if f.getProjects != 0 {
+		t.Errorf("GetProject calls = %d, want none without a tier scope", f.getProjects)
+	}
+}
+
+// TestDetector_AppliesAccessPolicy verifies a subscriber is sent only the
+// projects its policy lets it see, redacted, and no entity whose read route
+// it isn't granted.
+func TestDetector_AppliesAccessPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	policyJSON := `{"clients":{"partner":{"routes":["GET /projects/{id}"],"redact":{"project":["account.tier"]},"projects":{"tiers":["basic"]}}}}`
+	if err := os.WriteFile(path, []byte(policyJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := access.Load(path, []string{"GET /projects/{id}"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	s := newTestStore(t)
+	sub, err := s.Subscribe("partner", "https://partner.example.com/hook", []string{EventProjectUpdated, EventAccountUpdated}, testSecret)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	f := &fakeEntity{tiers: map[string]string{"p1": "basic", "p2": "enterprise"}}
+	d := NewDetector(f, s, policy)
+	ctx := context.Background()
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("baseline Poll() error = %v", err)
+	}
+
+	f.projects = []string{project("p1", "2026-10-19T08:00:00Z", "Active"), project("p2", "2026-10-19T08:00:00Z", "Active")}
+	f.accounts = []string{`{"id":"a1","updatedOn":"2026-10-19T08:00:00Z"}`}
+	if err := d.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+
+	got := pendingPayloads(t, s, "partner", sub.ID)
+	if len(got[EventAccountUpdated]) != 0 {
+		t.Errorf("account payloads = %+v, want none: GET /accounts/{id} is not allowed", got[EventAccountUpdated])
+	}
+	updates := got[EventProjectUpdated]
+	if len(updates) != 1 {
+		t.Fatalf("project payloads = %+v, want only the basic-tier p1", updates)
+	}
+	if data := string(updates[0].Data); !strings.Contains(data, `"p1"`) || strings.Contains(data, "tier") {
+		t.Errorf("data = %s, want p1 with account.tier redacted", data)
+	}
+	if f.getProjects != 2 {
+		t.Errorf("GetProject calls = %d, want one per account", f.getProjects)
+	}
+}
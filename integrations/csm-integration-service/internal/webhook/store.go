@@ -0,0 +1,498 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package webhook pushes project and account changes to consumers that
+// subscribe to them, instead of their polling /projects/search: a Detector
+// polls entity-service for changes, and a Dispatcher delivers each one,
+// HMAC-signed, to every subscriber's URL with retries.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wso2-open-operations/cs-tools/operations/csm-integration-service/internal/filestore"
+)
+
+// Event types a subscription can ask for.
+const (
+	EventProjectUpdated       = "project.updated"
+	EventProjectClosureChange = "project.closure_changed"
+	EventAccountUpdated       = "account.updated"
+)
+
+var eventTypes = []string{EventProjectUpdated, EventProjectClosureChange, EventAccountUpdated}
+
+const (
+	// minSecretLen is the shortest shared secret accepted for signing.
+	minSecretLen = 16
+	// maxSubscriptions bounds one consumer's subscriptions.
+	maxSubscriptions = 10
+	// maxDelivered and maxDead bound the finished deliveries kept per
+	// subscription for its log; the oldest go first.
+	maxDelivered = 200
+	maxDead      = 500
+)
+
+var (
+	// ErrNotFound is returned for a subscription or delivery the consumer
+	// does not own, or that does not exist.
+	ErrNotFound = errors.New("webhook: not found")
+	// ErrNotDead is returned by Retry for a delivery that has not failed.
+	ErrNotDead = errors.New("webhook: delivery is not dead-lettered")
+)
+
+// ValidationError is a subscription request the store refused; Msg is safe
+// to return to the caller.
+type ValidationError struct {
+	Msg string
+}
+
+func (e *ValidationError) Error() string { return "webhook: " + e.Msg }
+
+// Subscription is one consumer's registration for event types at a URL.
+type Subscription struct {
+	ID        string    `json:"id"`
+	Consumer  string    `json:"consumer"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedOn time.Time `json:"createdOn"`
+	// secret signs every delivery; it is never returned once registered.
+	secret string
+}
+
+// wants reports whether s subscribes to event.
+func (s Subscription) wants(event string) bool {
+	return slices.Contains(s.Events, event)
+}
+
+// DeliveryState is where a delivery is in its lifecycle.
+type DeliveryState string
+
+const (
+	// StatePending is waiting for its first or next attempt.
+	StatePending DeliveryState = "pending"
+	// StateDelivered got a 2xx from the subscriber.
+	StateDelivered DeliveryState = "delivered"
+	// StateDead failed every attempt and sits in the dead-letter list
+	// until retried or its subscription is deleted.
+	StateDead DeliveryState = "dead"
+)
+
+// Delivery is one event sent, or to be sent, to one subscription.
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	EventID        string          `json:"eventId"`
+	Event          string          `json:"event"`
+	State          DeliveryState   `json:"state"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptOn  time.Time       `json:"nextAttemptOn,omitzero"`
+	LastStatus     int             `json:"lastStatus,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedOn      time.Time       `json:"createdOn"`
+	UpdatedOn      time.Time       `json:"updatedOn"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// entry is what the detector last saw of one project or account.
+type entry struct {
+	UpdatedOn string   `json:"updatedOn"`
+	Closure   *closure `json:"closure,omitempty"`
+}
+
+// snapshot is the detector's view of one entity kind. Until Baselined, a
+// pass records what it sees without raising events, so the first poll
+// doesn't report every existing entity as changed. Cursor is the latest
+// updatedOn a pass has seen, which a project pass asks from once
+// baselined.
+type snapshot struct {
+	Baselined bool             `json:"baselined"`
+	Cursor    string           `json:"cursor,omitempty"`
+	Seen      map[string]entry `json:"seen"`
+}
+
+// storedSubscription is a Subscription as persisted, secret included.
+type storedSubscription struct {
+	Subscription
+	Secret string `json:"secret"`
+}
+
+// state is the store's file.
+type state struct {
+	Subscriptions []storedSubscription `json:"subscriptions"`
+	Deliveries    []Delivery           `json:"deliveries"`
+	Projects      snapshot             `json:"projects"`
+	Accounts      snapshot             `json:"accounts"`
+}
+
+// Store holds subscriptions, deliveries and the detector's snapshot in
+// memory, saved to a local JSON file. Calls made for a consumer save before
+// returning; the Detector and Dispatcher save once per pass.
+type Store struct {
+	path string
+
+	mu    sync.Mutex
+	subs  []Subscription
+	dels  []Delivery
+	snaps map[string]*snapshot
+
+	now func() time.Time
+	// resolve looks up a subscription URL's host; tests stub DNS with it.
+	resolve func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// Open returns a Store saving to path, loaded with what was saved there
+// before. An empty path keeps everything in memory only.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		snaps:   map[string]*snapshot{kindProject: {Seen: map[string]entry{}}, kindAccount: {Seen: map[string]entry{}}},
+		now:     time.Now,
+		resolve: lookupHost,
+	}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is operator configuration (WEBHOOKS_FILE)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("webhook: %s: %w", path, err)
+	}
+	for _, ss := range st.Subscriptions {
+		sub := ss.Subscription
+		sub.secret = ss.Secret
+		s.subs = append(s.subs, sub)
+	}
+	s.dels = st.Deliveries
+	for kind, snap := range map[string]snapshot{kindProject: st.Projects, kindAccount: st.Accounts} {
+		if snap.Seen == nil {
+			snap.Seen = map[string]entry{}
+		}
+		s.snaps[kind] = &snap
+	}
+	return s, nil
+}
+
+// Subscribe registers consumer for events at rawURL, signed with secret.
+// The URL must be absolute https, and its host must resolve only to public
+// addresses; events must be known event types.
+func (s *Store) Subscribe(consumer, rawURL string, events []string, secret string) (Subscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return Subscription{}, &ValidationError{Msg: "url must be an absolute https URL without credentials"}
+	}
+	if err := checkHost(context.Background(), s.resolve, u.Hostname()); err != nil {
+		if errors.Is(err, errInternalAddr) {
+			return Subscription{}, &ValidationError{Msg: "url must not resolve to a loopback, private or link-local address"}
+		}
+		return Subscription{}, &ValidationError{Msg: "url host does not resolve"}
+	}
+	if len(events) == 0 {
+		return Subscription{}, &ValidationError{Msg: fmt.Sprintf("events must list at least one of %v", eventTypes)}
+	}
+	var evs []string
+	for _, e := range events {
+		if !slices.Contains(eventTypes, e) {
+			return Subscription{}, &ValidationError{Msg: fmt.Sprintf("unknown event %q, want one of %v", e, eventTypes)}
+		}
+		if !slices.Contains(evs, e) {
+			evs = append(evs, e)
+		}
+	}
+	if len(secret) < minSecretLen {
+		return Subscription{}, &ValidationError{Msg: fmt.Sprintf("secret must be at least %d characters", minSecretLen)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, sub := range s.subs {
+		if sub.Consumer == consumer {
+			n++
+		}
+	}
+	if n >= maxSubscriptions {
+		return Subscription{}, &ValidationError{Msg: fmt.Sprintf("at most %d subscriptions per consumer", maxSubscriptions)}
+	}
+	sub := Subscription{ID: filestore.NewID(), Consumer: consumer, URL: u.String(), Events: evs, CreatedOn: s.now().UTC(), secret: secret}
+	s.subs = append(s.subs, sub)
+	if err := s.saveLocked(); err != nil {
+		return Subscription{}, err
+	}
+	return sub, nil
+}
+
+// Subscriptions returns consumer's subscriptions, oldest first.
+func (s *Store) Subscriptions(consumer string) []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Subscription
+	for _, sub := range s.subs {
+		if sub.Consumer == consumer {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+// Unsubscribe deletes consumer's subscription id and its deliveries,
+// pending ones included.
+func (s *Store) Unsubscribe(consumer, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.subs, func(sub Subscription) bool { return sub.ID == id && sub.Consumer == consumer })
+	if i < 0 {
+		return ErrNotFound
+	}
+	s.subs = slices.Delete(s.subs, i, i+1)
+	s.dels = slices.DeleteFunc(s.dels, func(d Delivery) bool { return d.SubscriptionID == id })
+	return s.saveLocked()
+}
+
+// Deliveries returns the delivery log of consumer's subscription id, newest
+// first, only those in state if it is non-empty.
+func (s *Store) Deliveries(consumer, id string, state DeliveryState) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.ContainsFunc(s.subs, func(sub Subscription) bool { return sub.ID == id && sub.Consumer == consumer }) {
+		return nil, ErrNotFound
+	}
+	out := []Delivery{}
+	for i := len(s.dels) - 1; i >= 0; i-- {
+		if d := s.dels[i]; d.SubscriptionID == id && (state == "" || d.State == state) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// Retry moves a dead-lettered delivery of consumer's subscription id back
+// to pending, with a fresh set of attempts starting now.
+func (s *Store) Retry(consumer, id, deliveryID string) (Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.ContainsFunc(s.subs, func(sub Subscription) bool { return sub.ID == id && sub.Consumer == consumer }) {
+		return Delivery{}, ErrNotFound
+	}
+	i := slices.IndexFunc(s.dels, func(d Delivery) bool { return d.ID == deliveryID && d.SubscriptionID == id })
+	if i < 0 {
+		return Delivery{}, ErrNotFound
+	}
+	d := &s.dels[i]
+	if d.State != StateDead {
+		return Delivery{}, ErrNotDead
+	}
+	now := s.now().UTC()
+	d.State, d.Attempts, d.NextAttemptOn, d.UpdatedOn = StatePending, 0, now, now
+	if err := s.saveLocked(); err != nil {
+		return Delivery{}, err
+	}
+	return *d, nil
+}
+
+// hasSubscriptions reports whether anyone is subscribed at all.
+func (s *Store) hasSubscriptions() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs) > 0
+}
+
+// subscribers returns the subscriptions wanting event.
+func (s *Store) subscribers(event string) []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Subscription
+	for _, sub := range s.subs {
+		if sub.wants(event) {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+// seen returns what the detector last recorded for kind id, and whether
+// kind's snapshot is baselined.
+func (s *Store) seen(kind, id string) (e entry, known, baselined bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := s.snaps[kind]
+	e, known = snap.Seen[id]
+	return e, known, snap.Baselined
+}
+
+// record stores e as the latest for kind id and queues dels.
+func (s *Store) record(kind, id string, e entry, dels []Delivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snaps[kind].Seen[id] = e
+	s.dels = append(s.dels, dels...)
+}
+
+// cursor returns kind's cursor, "" until a baselined pass has set one.
+func (s *Store) cursor(kind string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if snap := s.snaps[kind]; snap.Baselined {
+		return snap.Cursor
+	}
+	return ""
+}
+
+// completePass ends a full pass over kind: entities it didn't see are
+// forgotten, the snapshot is baselined, and its cursor moves to cursor.
+func (s *Store) completePass(kind string, seen map[string]bool, cursor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := s.snaps[kind]
+	for id := range snap.Seen {
+		if !seen[id] {
+			delete(snap.Seen, id)
+		}
+	}
+	snap.Baselined = true
+	snap.Cursor = cursor
+}
+
+// advance ends a pass over only kind's entities updated since its cursor,
+// moving the cursor to cursor. Nothing is forgotten: the pass can't tell
+// an entity that is gone from one that didn't change.
+func (s *Store) advance(kind, cursor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snaps[kind].Cursor = cursor
+}
+
+// resetSnapshots forgets everything the detector has seen, so that polling
+// resumed after a spell with no subscribers starts from a new baseline.
+func (s *Store) resetSnapshots() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, snap := range s.snaps {
+		snap.Baselined = false
+		snap.Cursor = ""
+		clear(snap.Seen)
+	}
+}
+
+// job is a due delivery with what sending it needs.
+type job struct {
+	Delivery
+	url, secret string
+}
+
+// due returns the pending deliveries whose next attempt is at or before
+// now, oldest first.
+func (s *Store) due(now time.Time) []job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subs := map[string]Subscription{}
+	for _, sub := range s.subs {
+		subs[sub.ID] = sub
+	}
+	var out []job
+	for _, d := range s.dels {
+		if d.State != StatePending || d.NextAttemptOn.After(now) {
+			continue
+		}
+		if sub, ok := subs[d.SubscriptionID]; ok {
+			out = append(out, job{Delivery: d, url: sub.URL, secret: sub.secret})
+		}
+	}
+	return out
+}
+
+// update replaces the delivery with d's ID, unless its subscription was
+// deleted meanwhile, and trims the subscription's finished deliveries.
+func (s *Store) update(d Delivery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.dels, func(x Delivery) bool { return x.ID == d.ID })
+	if i < 0 {
+		return
+	}
+	s.dels[i] = d
+	if d.State != StatePending {
+		s.trimLocked(d.SubscriptionID, d.State)
+	}
+}
+
+// trimLocked drops subscription id's oldest deliveries in state beyond
+// that state's bound.
+func (s *Store) trimLocked(id string, state DeliveryState) {
+	limit := maxDelivered
+	if state == StateDead {
+		limit = maxDead
+	}
+	n := 0
+	for _, d := range s.dels {
+		if d.SubscriptionID == id && d.State == state {
+			n++
+		}
+	}
+	s.dels = slices.DeleteFunc(s.dels, func(d Delivery) bool {
+		if n > limit && d.SubscriptionID == id && d.State == state {
+			n--
+			return true
+		}
+		return false
+	})
+}
+
+// Save writes the store to its file.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saveLocked()
+}
+
+// saveLocked writes the store to its file.
+func (s *Store) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	st := state{
+		Subscriptions: make([]storedSubscription, 0, len(s.subs)),
+		Deliveries:    s.dels,
+		Projects:      *s.snaps[kindProject],
+		Accounts:      *s.snaps[kindAccount],
+	}
+	for _, sub := range s.subs {
+		st.Subscriptions = append(st.Subscriptions, storedSubscription{Subscription: sub, Secret: sub.secret})
+	}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	if err := filestore.WriteFile(s.path, data); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
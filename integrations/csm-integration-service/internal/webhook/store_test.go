@@ -0,0 +1,174 @@
+// Copyright (c) 2026 WSO2 LLC. (https://www.wso2.com).
+//
+// WSO2 LLC. licenses this file to you under the Apache License,
+// Version 2.0 (the "License"); you may not use this file except
+// in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef"
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "webhooks.json"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	s.now = func() time.Time { return time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC) }
+	s.resolve = resolveTo("203.0.113.10")
+	return s
+}
+
+// resolveTo stands in for DNS, resolving every host to addrs.
+func resolveTo(addrs ...string) func(context.Context, string) ([]netip.Addr, error) {
+	return func(context.Context, string) ([]netip.Addr, error) {
+		var out []netip.Addr
+		for _, a := range addrs {
+			out = append(out, netip.MustParseAddr(a))
+		}
+		return out, nil
+	}
+}
+
+func TestStore_SubscribeValidates(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+		secret string
+	}{
+		{"http URL", "http://partner.example.com/hook", []string{EventProjectUpdated}, testSecret},
+		{"relative URL", "/hook", []string{EventProjectUpdated}, testSecret},
+		{"URL with credentials", "https://user:pw@partner.example.com/hook", []string{EventProjectUpdated}, testSecret},
+		{"no events", "https://partner.example.com/hook", nil, testSecret},
+		{"unknown event", "https://partner.example.com/hook", []string{"project.deleted"}, testSecret},
+		{"short secret", "https://partner.example.com/hook", []string{EventProjectUpdated}, "short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestStore(t).Subscribe("partner", tt.url, tt.events, tt.secret)
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Errorf("Subscribe() error = %v, want a ValidationError", err)
+			}
+		})
+	}
+}
+
+// TestStore_SubscribeRejectsInternalHosts verifies a URL whose host resolves
+// to any loopback, private or link-local address is refused, as is one that
+// doesn't resolve.
+func TestStore_SubscribeRejectsInternalHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		resolve func(context.Context, string) ([]netip.Addr, error)
+		wantMsg string
+	}{
+		{"public", resolveTo("203.0.113.10", "2001:db8::10"), ""},
+		{"loopback", resolveTo("127.0.0.1"), "url must not resolve to a loopback, private or link-local address"},
+		{"private", resolveTo("203.0.113.10", "10.0.0.5"), "url must not resolve to a loopback, private or link-local address"},
+		{"cloud metadata", resolveTo("169.254.169.254"), "url must not resolve to a loopback, private or link-local address"},
+		{"IPv6 unique local", resolveTo("fd00::1"), "url must not resolve to a loopback, private or link-local address"},
+		{"IPv4-mapped loopback", resolveTo("::ffff:127.0.0.1"), "url must not resolve to a loopback, private or link-local address"},
+		{"unspecified", resolveTo("0.0.0.0"), "url must not resolve to a loopback, private or link-local address"},
+		{"unresolvable", func(context.Context, string) ([]netip.Addr, error) { return nil, errors.New("no such host") },
+			"url host does not resolve"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			s.resolve = tt.resolve
+			_, err := s.Subscribe("partner", "https://partner.example.com/hook", []string{EventProjectUpdated}, testSecret)
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Errorf("Subscribe() error = %v, want none", err)
+				}
+				return
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) || vErr.Msg != tt.wantMsg {
+				t.Errorf("Subscribe() error = %v, want a ValidationError %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+// TestStore_PersistsAndScopesToConsumer verifies subscriptions, secret
+// included, survive a reopen, and a consumer can't see or delete another's.
+func TestStore_PersistsAndScopesToConsumer(t *testing.T) {
+	s := newTestStore(t)
+	sub, err := s.Subscribe("partner", "https://partner.example.com/hook", []string{EventProjectUpdated, EventProjectUpdated}, testSecret)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if len(sub.Events) != 1 {
+		t.Errorf("Events = %v, want duplicates dropped", sub.Events)
+	}
+
+	reopened, err := Open(s.path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	subs := reopened.Subscriptions("partner")
+	if len(subs) != 1 || subs[0].ID != sub.ID || subs[0].secret != testSecret {
+		t.Fatalf("after reopen Subscriptions() = %+v, want the subscription with its secret", subs)
+	}
+
+	if got := reopened.Subscriptions("reporting"); len(got) != 0 {
+		t.Errorf("other consumer's Subscriptions() = %+v, want none", got)
+	}
+	if err := reopened.Unsubscribe("reporting", sub.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("other consumer's Unsubscribe() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := reopened.Deliveries("reporting", sub.ID, ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("other consumer's Deliveries() error = %v, want %v", err, ErrNotFound)
+	}
+	if err := reopened.Unsubscribe("partner", sub.ID); err != nil {
+		t.Errorf("Unsubscribe() error = %v", err)
+	}
+}
+
+func TestStore_Retry(t *testing.T) {
+	s := newTestStore(t)
+	sub, err := s.Subscribe("partner", "https://partner.example.com/hook", []string{EventAccountUpdated}, testSecret)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	s.record(kindAccount, "a1", entry{}, []Delivery{
+		{ID: "d1", SubscriptionID: sub.ID, State: StateDead, Attempts: 8},
+		{ID: "d2", SubscriptionID: sub.ID, State: StateDelivered, Attempts: 1},
+	})
+
+	if _, err := s.Retry("partner", sub.ID, "d2"); !errors.Is(err, ErrNotDead) {
+		t.Errorf("Retry() of a delivered delivery error = %v, want %v", err, ErrNotDead)
+	}
+	d, err := s.Retry("partner", sub.ID, "d1")
+	if err != nil {
+		t.Fatalf("Retry() error = %v", err)
+	}
+	if d.State != StatePending || d.Attempts != 0 {
+		t.Errorf("retried delivery = %+v, want pending with no attempts", d)
+	}
+	if dead, _ := s.Deliveries("partner", sub.ID, StateDead); len(dead) != 0 {
+		t.Errorf("dead letters after Retry() = %+v, want none", dead)
+	}
+}